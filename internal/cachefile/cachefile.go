@@ -0,0 +1,87 @@
+// Package cachefile keeps per-context files in the jk cache directory: the
+// last-run records, job indexes, config snapshots, and deprecation sightings.
+//
+// Each kind of file has its own subdirectory, holding one file per context.
+// Writes replace the file atomically; concurrent writers simply overwrite
+// each other, and the last write wins.
+package cachefile
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DirEnv overrides the cache directory the files are kept under.
+const DirEnv = "JK_CACHE_DIR"
+
+// Dir is the directory holding the files of one kind: kind under DirEnv, or
+// under jk in the user cache directory.
+func Dir(kind string) (string, error) {
+	if dir := strings.TrimSpace(os.Getenv(DirEnv)); dir != "" {
+		return filepath.Join(dir, kind), nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve cache dir: %w", err)
+	}
+	return filepath.Join(dir, "jk", kind), nil
+}
+
+// Path is the file of contextName among the files of kind.
+func Path(kind, contextName string) (string, error) {
+	if contextName == "" {
+		return "", errors.New("context name is required")
+	}
+	dir, err := Dir(kind)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, url.PathEscape(contextName)+".json"), nil
+}
+
+// Read returns the contents of contextName's file of kind, or nil when it
+// does not exist.
+func Read(kind, contextName string) ([]byte, error) {
+	path, err := Path(kind, contextName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write replaces contextName's file of kind with data atomically, creating
+// the directory readable only by the owner.
+func Write(kind, contextName string, data []byte) error {
+	path, err := Path(kind, contextName)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, ".tmp-*.json")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	return os.Rename(tmpFile.Name(), path)
+}
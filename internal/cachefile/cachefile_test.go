@@ -0,0 +1,42 @@
+package cachefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteReplacesFileAtomically(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(DirEnv, dir)
+
+	data, err := Read("last", "team/prod")
+	if err != nil || data != nil {
+		t.Fatalf("Read before Write = %q, %v; want nil, nil", data, err)
+	}
+
+	for _, content := range []string{`{"build":1}`, `{"build":2}`} {
+		if err := Write("last", "team/prod", []byte(content)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	data, err = Read("last", "team/prod")
+	if err != nil || string(data) != `{"build":2}` {
+		t.Fatalf("Read = %q, %v", data, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "last"))
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "team%2Fprod.json" {
+		t.Fatalf("unexpected files %v", entries)
+	}
+}
+
+func TestPathRequiresContext(t *testing.T) {
+	t.Setenv(DirEnv, t.TempDir())
+	if _, err := Path("last", ""); err == nil {
+		t.Fatal("expected an error without a context name")
+	}
+}
@@ -5,15 +5,31 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
+	"github.com/bmatcuk/doublestar/v4"
+	"golang.org/x/net/http/httpguts"
 	"gopkg.in/yaml.v3"
-)
 
-const (
-	currentVersion = 1
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
 )
 
+// migration upgrades a config document by one version in place; doc is the
+// document's top-level mapping.
+type migration func(doc *yaml.Node) error
+
+// migrations[i] upgrades version i+1 to i+2. Files without a version are
+// version 1.
+var migrations []migration
+
+// currentVersion is the version Save writes: one past the last migration.
+func currentVersion() int {
+	return len(migrations) + 1
+}
+
 var (
 	ErrContextNotFound = errors.New("context not found")
 )
@@ -24,8 +40,18 @@ type Config struct {
 	Active      string              `yaml:"active,omitempty"`
 	Contexts    map[string]*Context `yaml:"contexts,omitempty"`
 	Preferences Preferences         `yaml:"preferences,omitempty"`
-	path        string              `yaml:"-"`
-	mu          sync.RWMutex        `yaml:"-"`
+	// ContextRules pick the context for commands on a job path when neither
+	// --context nor JK_CONTEXT is given; see ContextRuleFor.
+	ContextRules []ContextRule `yaml:"context_rules,omitempty"`
+	path         string        `yaml:"-"`
+	warnings     []string      `yaml:"-"`
+	mu           sync.RWMutex  `yaml:"-"`
+}
+
+// ContextRule routes the job paths under Prefix to the named context.
+type ContextRule struct {
+	Prefix  string `yaml:"prefix"`
+	Context string `yaml:"context"`
 }
 
 // Context represents a Jenkins connection configuration.
@@ -36,6 +62,72 @@ type Context struct {
 	Proxy              string `yaml:"proxy,omitempty"`
 	CAFile             string `yaml:"ca_file,omitempty"`
 	AllowInsecureStore bool   `yaml:"allow_insecure_store,omitempty"`
+	AllowHTTP          bool   `yaml:"allow_http,omitempty"`
+	DefaultFolder      string `yaml:"default_folder,omitempty"`
+	RateLimit          string `yaml:"rate_limit,omitempty"`
+	// Defaults maps job path globs to the parameters run start and run rerun
+	// pass unless told otherwise; see DefaultParams.
+	Defaults map[string]map[string]string `yaml:"defaults,omitempty"`
+	// ExtraHeaders are sent with every request, for gateways in front of
+	// Jenkins that want their own token or cookie. A value written as
+	// "secret:<key>" is read from the secret store instead; see
+	// HeaderSecretRef.
+	ExtraHeaders map[string]string `yaml:"extra_headers,omitempty"`
+}
+
+// HeaderSecretPrefix marks an extra header value kept in the secret store.
+const HeaderSecretPrefix = "secret:"
+
+// HeaderSecretRef returns the secret store key of an extra header value
+// written as "secret:<key>".
+func HeaderSecretRef(value string) (string, bool) {
+	key, ok := strings.CutPrefix(value, HeaderSecretPrefix)
+	key = strings.TrimSpace(key)
+	return key, ok && key != ""
+}
+
+// ValidateHeader checks an extra header name and value. Authorization is
+// refused because it carries the Jenkins API token.
+func ValidateHeader(name, value string) error {
+	switch {
+	case !httpguts.ValidHeaderFieldName(name):
+		return fmt.Errorf("invalid header name %q", name)
+	case strings.EqualFold(name, "Authorization"):
+		return errors.New("the Authorization header is set from the context's username and token")
+	case !httpguts.ValidHeaderFieldValue(value) || strings.TrimSpace(value) == "":
+		return fmt.Errorf("invalid value for header %s", name)
+	case strings.HasPrefix(value, HeaderSecretPrefix):
+		if _, ok := HeaderSecretRef(value); !ok {
+			return fmt.Errorf("header %s: %s needs a key", name, HeaderSecretPrefix)
+		}
+	}
+	return nil
+}
+
+// HeaderSecretKeys returns the secret store keys the extra headers
+// reference, sorted.
+func (c *Context) HeaderSecretKeys() []string {
+	var keys []string
+	for _, name := range c.HeaderNames() {
+		if key, ok := HeaderSecretRef(c.ExtraHeaders[name]); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// HeaderNames returns the names of the extra headers, sorted.
+func (c *Context) HeaderNames() []string {
+	if c == nil {
+		return nil
+	}
+	names := make([]string, 0, len(c.ExtraHeaders))
+	for name := range c.ExtraHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // Preferences capture user-level CLI options.
@@ -43,6 +135,16 @@ type Preferences struct {
 	Color          string `yaml:"color,omitempty"`
 	OutputFormat   string `yaml:"output_format,omitempty"`
 	MaxConcurrency int    `yaml:"max_concurrency,omitempty"`
+	// SecretPatterns are regular expressions, matched case-insensitively,
+	// that mark further parameter and variable names as secrets on top of
+	// the built-in keywords.
+	SecretPatterns []string `yaml:"secret_patterns,omitempty"`
+	// NonSecretNames are names never redacted, even when a keyword or
+	// pattern matches them.
+	NonSecretNames []string `yaml:"non_secret_names,omitempty"`
+	// DeprecationHeaders are the response headers that mark an endpoint
+	// deprecated; empty means Deprecation and X-JK-Deprecated.
+	DeprecationHeaders []string `yaml:"deprecation_headers,omitempty"`
 }
 
 // Load retrieves configuration from disk, returning default values when the
@@ -55,7 +157,7 @@ func Load() (*Config, error) {
 	baseDir := filepath.Join(dir, "jk")
 
 	cfg := &Config{
-		Version:  currentVersion,
+		Version:  currentVersion(),
 		Contexts: make(map[string]*Context),
 	}
 
@@ -72,11 +174,16 @@ func Load() (*Config, error) {
 			continue
 		}
 
+		data, err = migrate(path, data)
+		if err != nil {
+			return nil, err
+		}
 		if err := yaml.Unmarshal(data, cfg); err != nil {
 			return nil, fmt.Errorf("decode config: %w", err)
 		}
 
 		cfg.path = path
+		cfg.warnCaseDuplicates()
 		return cfg, nil
 	}
 
@@ -104,14 +211,19 @@ func (c *Config) Save() error {
 	}
 
 	if c.Version == 0 {
-		c.Version = currentVersion
+		c.Version = currentVersion()
 	}
 
 	data, err := yaml.Marshal(c)
 	if err != nil {
 		return fmt.Errorf("encode config: %w", err)
 	}
+	return writeFileAtomic(c.path, data)
+}
 
+// writeFileAtomic replaces path with data, readable only by the owner.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
 	tmpFile, err := os.CreateTemp(dir, ".config-*.yml")
 	if err != nil {
 		return fmt.Errorf("create temp config: %w", err)
@@ -134,13 +246,187 @@ func (c *Config) Save() error {
 		return fmt.Errorf("close temp config: %w", err)
 	}
 
-	if err := os.Rename(tmpFile.Name(), c.path); err != nil {
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
 		return fmt.Errorf("write config: %w", err)
 	}
 
 	return nil
 }
 
+// migrate upgrades config file data written by an older jk. The upgraded
+// file is saved once, next to a .bak copy of the original; when either
+// write fails the upgrade still applies to this process.
+func migrate(path string, data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return data, nil
+	}
+	root := doc.Content[0]
+
+	version := 1
+	versionNode := mappingValue(root, "version")
+	if versionNode != nil {
+		if err := versionNode.Decode(&version); err != nil {
+			return nil, fmt.Errorf("decode config: version: %w", err)
+		}
+		if version < 1 {
+			version = 1
+		}
+	}
+	target := currentVersion()
+	if version >= target {
+		return data, nil
+	}
+
+	for v := version; v < target; v++ {
+		if err := migrations[v-1](root); err != nil {
+			return nil, fmt.Errorf("migrate config from version %d: %w", v, err)
+		}
+	}
+	if versionNode == nil {
+		versionNode = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int"}
+		root.Content = append([]*yaml.Node{{Kind: yaml.ScalarNode, Value: "version"}, versionNode}, root.Content...)
+	}
+	versionNode.Value = strconv.Itoa(target)
+
+	upgraded, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("encode config: %w", err)
+	}
+	if err := os.WriteFile(path+".bak", data, 0o600); err != nil {
+		jklog.L().Debug().Err(err).Msg("back up config before migration failed")
+		return upgraded, nil
+	}
+	if err := writeFileAtomic(path, upgraded); err != nil {
+		jklog.L().Debug().Err(err).Msg("save migrated config failed")
+	}
+	return upgraded, nil
+}
+
+// mappingValue returns the value node of key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// warnCaseDuplicates reports contexts whose names differ only by case,
+// which older versions of jk created, in Warnings. Lookups can no longer
+// tell them apart, but only the user knows which one holds the token,
+// username, and other settings worth keeping, so nothing is removed.
+func (c *Config) warnCaseDuplicates() {
+	groups := make(map[string][]string)
+	for name := range c.Contexts {
+		key := NormalizeContextName(name)
+		groups[key] = append(groups[key], name)
+	}
+	keys := make([]string, 0, len(groups))
+	for key, names := range groups {
+		if len(names) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		names := groups[key]
+		sort.Strings(names)
+		quoted := make([]string, len(names))
+		for i, name := range names {
+			quoted[i] = strconv.Quote(name)
+		}
+		list := strings.Join(quoted, ", ")
+
+		if sameContextURL(c.Contexts, names) {
+			c.warnings = append(c.warnings, fmt.Sprintf("contexts %s differ only by case and point at the same Jenkins URL; context names are case-insensitive, so keep the one that works (`jk context use <name>` then `jk auth status`) and remove the others with `jk context rm <name>`", list))
+			continue
+		}
+		c.warnings = append(c.warnings, fmt.Sprintf("contexts %s differ only by case and point at different Jenkins URLs; context names are case-insensitive, so keep one and remove the others with `jk context rm <name>`, then log in again with a distinct --name", list))
+	}
+}
+
+// sameContextURL reports whether the named contexts point at one URL.
+func sameContextURL(contexts map[string]*Context, names []string) bool {
+	url := ""
+	for i, name := range names {
+		ctx := contexts[name]
+		if ctx == nil {
+			return false
+		}
+		u := strings.TrimSuffix(strings.TrimSpace(ctx.URL), "/")
+		if i > 0 && !strings.EqualFold(u, url) {
+			return false
+		}
+		url = u
+	}
+	return true
+}
+
+// Warnings returns the problems Load found in the config file, for the
+// caller to show.
+func (c *Config) Warnings() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]string(nil), c.warnings...)
+}
+
+// NormalizeContextName returns the canonical form new context names are
+// stored under: trimmed and lowercase.
+func NormalizeContextName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// LookupContext returns the stored name of the context name refers to. An
+// exact match wins; otherwise name matches a context that differs only by
+// case, as long as exactly one does.
+func (c *Config) LookupContext(name string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.Contexts[name]; ok {
+		return name, true
+	}
+	found := ""
+	for stored := range c.Contexts {
+		if strings.EqualFold(stored, name) {
+			if found != "" {
+				return "", false
+			}
+			found = stored
+		}
+	}
+	return found, found != ""
+}
+
+// CaseCollision returns the existing context a new context called name
+// would collide with: one whose name differs from name only by case. It
+// returns "" when there is none.
+func (c *Config) CaseCollision(name string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.Contexts))
+	for stored := range c.Contexts {
+		names = append(names, stored)
+	}
+	sort.Strings(names)
+	for _, stored := range names {
+		if stored != name && strings.EqualFold(stored, name) {
+			return stored
+		}
+	}
+	return ""
+}
+
 // DefaultPath returns the on-disk location for the config file.
 func DefaultPath() (string, error) {
 	dir, err := os.UserConfigDir()
@@ -150,6 +436,23 @@ func DefaultPath() (string, error) {
 	return filepath.Join(dir, "jk", "config.yaml"), nil
 }
 
+// FilePath returns the config file Load reads, config.yaml or config.yml.
+// When neither exists, exists is false and path is where Save writes.
+func FilePath() (path string, exists bool, err error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false, fmt.Errorf("resolve config dir: %w", err)
+	}
+	baseDir := filepath.Join(dir, "jk")
+	for _, name := range []string{"config.yaml", "config.yml"} {
+		candidate := filepath.Join(baseDir, name)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, true, nil
+		}
+	}
+	return filepath.Join(baseDir, "config.yaml"), false, nil
+}
+
 // Path returns the config file path on disk.
 func (c *Config) Path() string {
 	c.mu.RLock()
@@ -227,3 +530,90 @@ func (c *Config) ActiveContext() (*Context, string, error) {
 	}
 	return ctx, c.Active, nil
 }
+
+// DefaultParams returns the default parameters for jobPath and the glob they
+// were configured under. Globs use doublestar syntax; when several match,
+// the longest pattern wins and only its parameters apply.
+func (c *Context) DefaultParams(jobPath string) (string, map[string]string) {
+	if c == nil {
+		return "", nil
+	}
+	best := ""
+	found := false
+	for pattern := range c.Defaults {
+		if ok, err := doublestar.Match(strings.Trim(pattern, "/"), jobPath); err != nil || !ok {
+			continue
+		}
+		if !found || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best = pattern
+			found = true
+		}
+	}
+	if !found {
+		return "", nil
+	}
+	return best, c.Defaults[best]
+}
+
+// NormalizeRulePrefix returns the form context rule prefixes are matched and
+// stored in: a job path without leading or trailing slashes.
+func NormalizeRulePrefix(prefix string) string {
+	return strings.Trim(strings.TrimSpace(prefix), "/")
+}
+
+// ContextRuleFor returns the context rule for jobPath: the rule with the
+// longest prefix that names jobPath itself or a folder above it, so
+// "team-a/" matches "team-a/app" but not "team-ab/app".
+func (c *Config) ContextRuleFor(jobPath string) (ContextRule, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	jobPath = strings.Trim(jobPath, "/")
+	var best ContextRule
+	found := false
+	for _, rule := range c.ContextRules {
+		prefix := NormalizeRulePrefix(rule.Prefix)
+		if prefix == "" || (jobPath != prefix && !strings.HasPrefix(jobPath, prefix+"/")) {
+			continue
+		}
+		if !found || len(prefix) > len(NormalizeRulePrefix(best.Prefix)) {
+			best = rule
+			found = true
+		}
+	}
+	return best, found
+}
+
+// SetContextRule adds a rule routing prefix to context, replacing any rule
+// for the same prefix. Rules are kept sorted by prefix.
+func (c *Config) SetContextRule(prefix, context string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix = NormalizeRulePrefix(prefix)
+	rules := make([]ContextRule, 0, len(c.ContextRules)+1)
+	for _, rule := range c.ContextRules {
+		if NormalizeRulePrefix(rule.Prefix) != prefix {
+			rules = append(rules, rule)
+		}
+	}
+	rules = append(rules, ContextRule{Prefix: prefix, Context: context})
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Prefix < rules[j].Prefix })
+	c.ContextRules = rules
+}
+
+// RemoveContextRule deletes the rule for prefix and reports whether there
+// was one.
+func (c *Config) RemoveContextRule(prefix string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prefix = NormalizeRulePrefix(prefix)
+	for i, rule := range c.ContextRules {
+		if NormalizeRulePrefix(rule.Prefix) == prefix {
+			c.ContextRules = append(c.ContextRules[:i:i], c.ContextRules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
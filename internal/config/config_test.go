@@ -0,0 +1,219 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadMigratesOlderConfig(t *testing.T) {
+	saved := migrations
+	t.Cleanup(func() { migrations = saved })
+	migrations = append(append([]migration(nil), saved...), func(doc *yaml.Node) error {
+		for _, ctx := range mappingValue(mappingValue(doc, "contexts"), "prod").Content {
+			if ctx.Value == "endpoint" {
+				ctx.Value = "url"
+			}
+		}
+		return nil
+	})
+
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	path := filepath.Join(home, "jk", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	original := "active: prod\ncontexts:\n  prod:\n    endpoint: https://ci.example.com\n"
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Version != currentVersion() {
+		t.Fatalf("version = %d, want %d", cfg.Version, currentVersion())
+	}
+	if ctx := cfg.Contexts["prod"]; ctx == nil || ctx.URL != "https://ci.example.com" {
+		t.Fatalf("expected the migrated url, got %+v", ctx)
+	}
+
+	backup, err := os.ReadFile(path + ".bak")
+	if err != nil {
+		t.Fatalf("read backup: %v", err)
+	}
+	if string(backup) != original {
+		t.Fatalf("backup = %q, want the original file", backup)
+	}
+	upgraded, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(upgraded), "version: 2") || strings.Contains(string(upgraded), "endpoint") {
+		t.Fatalf("expected the file to be rewritten, got:\n%s", upgraded)
+	}
+
+	if err := os.Remove(path + ".bak"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err != nil {
+		t.Fatalf("second load: %v", err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Fatalf("expected a current config not to be migrated again, stat err = %v", err)
+	}
+}
+
+func TestLoadLeavesCurrentConfigAlone(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	path := filepath.Join(home, "jk", "config.yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("contexts:\n  prod:\n    url: https://ci.example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Fatalf("expected no backup, stat err = %v", err)
+	}
+	found, exists, err := FilePath()
+	if err != nil || !exists || found != path {
+		t.Fatalf("FilePath() = %q, %v, %v; want %q", found, exists, err, path)
+	}
+}
+
+func TestContextDefaultParamsLongestGlobWins(t *testing.T) {
+	ctx := &Context{Defaults: map[string]map[string]string{
+		"releases/**":            {"ENV": "staging"},
+		"releases/helm/*":        {"ENV": "helm"},
+		"/releases/helm/Deploy/": {"ENV": "deploy"},
+	}}
+
+	tests := []struct {
+		jobPath string
+		pattern string
+		env     string
+	}{
+		{jobPath: "releases/helm/Deploy", pattern: "/releases/helm/Deploy/", env: "deploy"},
+		{jobPath: "releases/helm/Rollback", pattern: "releases/helm/*", env: "helm"},
+		{jobPath: "releases/apps/web/main", pattern: "releases/**", env: "staging"},
+		{jobPath: "tools/lint"},
+	}
+	for _, tt := range tests {
+		pattern, params := ctx.DefaultParams(tt.jobPath)
+		if pattern != tt.pattern || params["ENV"] != tt.env {
+			t.Errorf("DefaultParams(%q) = %q, %v; want %q with ENV=%q", tt.jobPath, pattern, params, tt.pattern, tt.env)
+		}
+	}
+
+	var missing *Context
+	if pattern, params := missing.DefaultParams("releases/helm/Deploy"); pattern != "" || params != nil {
+		t.Fatalf("expected no defaults without a context, got %q %v", pattern, params)
+	}
+}
+
+func TestContextRuleForLongestPrefixWins(t *testing.T) {
+	cfg := &Config{}
+	cfg.SetContextRule("team-a/", "team-a")
+	cfg.SetContextRule("/team-a/infra", "infra")
+	cfg.SetContextRule("team-b", "team-b")
+	cfg.SetContextRule("team-b/", "team-b-new")
+
+	tests := []struct {
+		jobPath string
+		context string
+	}{
+		{jobPath: "team-a/app", context: "team-a"},
+		{jobPath: "team-a/infra/terraform", context: "infra"},
+		{jobPath: "/team-a/infra", context: "infra"},
+		{jobPath: "team-b/web", context: "team-b-new"},
+		{jobPath: "team-ab/app"},
+		{jobPath: "tools/lint"},
+	}
+	for _, tt := range tests {
+		rule, ok := cfg.ContextRuleFor(tt.jobPath)
+		if ok != (tt.context != "") || rule.Context != tt.context {
+			t.Errorf("ContextRuleFor(%q) = %+v, %v; want %q", tt.jobPath, rule, ok, tt.context)
+		}
+	}
+
+	if len(cfg.ContextRules) != 3 || cfg.ContextRules[0].Prefix != "team-a" {
+		t.Fatalf("expected three sorted rules with normalized prefixes, got %+v", cfg.ContextRules)
+	}
+	if !cfg.RemoveContextRule("team-a/infra/") || cfg.RemoveContextRule("team-a/infra") {
+		t.Fatal("expected the infra rule to be removed exactly once")
+	}
+	if rule, _ := cfg.ContextRuleFor("team-a/infra/terraform"); rule.Context != "team-a" {
+		t.Fatalf("expected the team-a rule after removal, got %+v", rule)
+	}
+}
+
+func TestLoadKeepsContextsDifferingByCaseWithSameURL(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	path := filepath.Join(home, "jk", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	data := "active: Prod\ncontexts:\n  prod:\n    url: https://ci.example.com\n    username: alice\n  Prod:\n    url: https://ci.example.com/\n  dev:\n    url: https://dev.example.com\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(cfg.Contexts) != 3 || cfg.Contexts["prod"].Username != "alice" {
+		t.Fatalf("expected every context to be kept, got %v", cfg.Contexts)
+	}
+	if warnings := cfg.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], `contexts "Prod", "prod" differ only by case and point at the same Jenkins URL`) || !strings.Contains(warnings[0], "jk context rm") {
+		t.Fatalf("warnings = %q", warnings)
+	}
+
+	// Loading never rewrites the file.
+	if got, err := os.ReadFile(path); err != nil || string(got) != data {
+		t.Fatalf("config rewritten to %q (%v)", got, err)
+	}
+}
+
+func TestLoadWarnsAboutContextsDifferingByCase(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	path := filepath.Join(home, "jk", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	data := "contexts:\n  prod:\n    url: https://ci.example.com\n  PROD:\n    url: https://other.example.com\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(cfg.Contexts) != 2 {
+		t.Fatalf("expected both contexts to be kept, got %v", cfg.Contexts)
+	}
+	warnings := cfg.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `contexts "PROD", "prod" differ only by case`) || !strings.Contains(warnings[0], "jk context rm") {
+		t.Fatalf("warnings = %q", warnings)
+	}
+	if _, ok := cfg.LookupContext("Prod"); ok {
+		t.Fatal("expected an ambiguous name not to match")
+	}
+	if name, ok := cfg.LookupContext("PROD"); !ok || name != "PROD" {
+		t.Fatalf("expected the exact name to match, got %q %v", name, ok)
+	}
+}
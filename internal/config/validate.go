@@ -0,0 +1,218 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"gopkg.in/yaml.v3"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+)
+
+// Finding is one problem in a config file.
+type Finding struct {
+	// Line is 1-based; 0 when the YAML library cannot place the problem.
+	Line int `json:"line,omitempty"`
+	// Key is the dotted path of the offending setting, e.g.
+	// "contexts.prod.ca_file", for problems found after decoding.
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidateOptions supplies checks implemented outside this package.
+type ValidateOptions struct {
+	// RateLimit parses a context's rate_limit value.
+	RateLimit func(spec string) error
+}
+
+var (
+	yamlLinePrefix = regexp.MustCompile(`^(?:yaml: )?line (\d+): `)
+	unknownField   = regexp.MustCompile(`^field (\S+) not found in type config\.(\w+)$`)
+)
+
+// sectionNames names the config structs in unknown-key messages.
+var sectionNames = map[string]string{
+	"Config":      "at the top level",
+	"Context":     "in a context",
+	"Preferences": "in preferences",
+	"ContextRule": "in a context rule",
+}
+
+// Validate checks config file data strictly: unknown keys and type
+// mismatches, then values that decode but cannot work (an unparsable URL, a
+// missing ca_file, an active context that does not exist). Findings are
+// ordered by line.
+func Validate(data []byte, opts ValidateOptions) []Finding {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []Finding{yamlFinding(err.Error())}
+	}
+
+	var findings []Finding
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return []Finding{yamlFinding(err.Error())}
+		}
+		for _, msg := range typeErr.Errors {
+			findings = append(findings, yamlFinding(msg))
+		}
+	}
+
+	var root *yaml.Node
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+	findings = append(findings, checkValues(&cfg, root, opts)...)
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Line < findings[j].Line })
+	return findings
+}
+
+// yamlFinding turns a YAML library message ("line 7: field urll not found
+// in type config.Context") into a finding.
+func yamlFinding(msg string) Finding {
+	var finding Finding
+	if m := yamlLinePrefix.FindStringSubmatch(msg); m != nil {
+		finding.Line, _ = strconv.Atoi(m[1])
+		msg = msg[len(m[0]):]
+	}
+	msg = strings.TrimPrefix(msg, "yaml: ")
+	if m := unknownField.FindStringSubmatch(msg); m != nil {
+		msg = fmt.Sprintf("unknown key %q %s", m[1], sectionNames[m[2]])
+	} else if strings.HasPrefix(msg, "cannot unmarshal") {
+		msg = "type mismatch: " + msg
+	}
+	finding.Message = msg
+	return finding
+}
+
+func checkValues(cfg *Config, root *yaml.Node, opts ValidateOptions) []Finding {
+	var findings []Finding
+	report := func(message string, path ...string) {
+		findings = append(findings, Finding{Line: keyLine(root, path...), Key: strings.Join(path, "."), Message: message})
+	}
+
+	if cfg.Version > currentVersion() {
+		report(fmt.Sprintf("version %d is newer than this jk supports (%d)", cfg.Version, currentVersion()), "version")
+	}
+	if cfg.Active != "" {
+		if _, ok := cfg.Contexts[cfg.Active]; !ok {
+			report(fmt.Sprintf("active context %q is not defined under contexts", cfg.Active), "active")
+		}
+	}
+
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ctx := cfg.Contexts[name]
+		if ctx == nil {
+			report(fmt.Sprintf("context %q is empty", name), "contexts", name)
+			continue
+		}
+		switch {
+		case strings.TrimSpace(ctx.URL) == "":
+			report(fmt.Sprintf("context %q has no url", name), "contexts", name)
+		case !isHTTPURL(ctx.URL):
+			report(fmt.Sprintf("context %q: url %q is not an absolute http(s) URL", name, ctx.URL), "contexts", name, "url")
+		}
+		if ctx.Proxy != "" && !isHTTPURL(ctx.Proxy) && !strings.HasPrefix(ctx.Proxy, "socks5://") {
+			report(fmt.Sprintf("context %q: proxy %q is not an http(s) or socks5 URL", name, ctx.Proxy), "contexts", name, "proxy")
+		}
+		if ctx.CAFile != "" {
+			if info, err := os.Stat(ctx.CAFile); err != nil {
+				report(fmt.Sprintf("context %q: ca_file %q does not exist", name, ctx.CAFile), "contexts", name, "ca_file")
+			} else if info.IsDir() {
+				report(fmt.Sprintf("context %q: ca_file %q is a directory", name, ctx.CAFile), "contexts", name, "ca_file")
+			}
+		}
+		for pattern := range ctx.Defaults {
+			if !doublestar.ValidatePattern(strings.Trim(pattern, "/")) {
+				report(fmt.Sprintf("context %q: defaults pattern %q is not a valid glob", name, pattern), "contexts", name, "defaults", pattern)
+			}
+		}
+		for _, header := range ctx.HeaderNames() {
+			if err := ValidateHeader(header, ctx.ExtraHeaders[header]); err != nil {
+				report(fmt.Sprintf("context %q: %v", name, err), "contexts", name, "extra_headers", header)
+			}
+		}
+		if ctx.RateLimit != "" && opts.RateLimit != nil {
+			if err := opts.RateLimit(ctx.RateLimit); err != nil {
+				report(fmt.Sprintf("context %q: %v", name, err), "contexts", name, "rate_limit")
+			}
+		}
+	}
+
+	for i, rule := range cfg.ContextRules {
+		index := strconv.Itoa(i)
+		switch {
+		case NormalizeRulePrefix(rule.Prefix) == "":
+			report("context rule has no prefix", "context_rules", index, "prefix")
+		case rule.Context == "":
+			report(fmt.Sprintf("context rule %q has no context", rule.Prefix), "context_rules", index, "context")
+		default:
+			if _, ok := cfg.Contexts[rule.Context]; !ok {
+				report(fmt.Sprintf("context rule %q: context %q is not defined under contexts", rule.Prefix, rule.Context), "context_rules", index, "context")
+			}
+		}
+	}
+
+	if cfg.Preferences.MaxConcurrency < 0 {
+		report("preferences: max_concurrency must not be negative", "preferences", "max_concurrency")
+	}
+	for _, pattern := range cfg.Preferences.SecretPatterns {
+		if _, err := filter.CompileSecretPattern(pattern); err != nil {
+			report("preferences: "+err.Error(), "preferences", "secret_patterns")
+		}
+	}
+	return findings
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// keyLine is the line of the key at path, or of its closest ancestor that
+// exists; 0 without a document.
+func keyLine(root *yaml.Node, path ...string) int {
+	line := 0
+	node := root
+	for _, key := range path {
+		if node != nil && node.Kind == yaml.SequenceNode {
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node.Content) {
+				break
+			}
+			node = node.Content[i]
+			line = node.Line
+			continue
+		}
+		if node == nil || node.Kind != yaml.MappingNode {
+			break
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				line, next = node.Content[i].Line, node.Content[i+1]
+				break
+			}
+		}
+		node = next
+	}
+	return line
+}
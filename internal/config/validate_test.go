@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidateReportsFindingsWithLines(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, []byte("pem"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte(`version: 1
+active: staging
+contexts:
+  prod:
+    urll: https://ci.example.com
+    ca_file: ` + caFile + `
+  dev:
+    url: ci.example.com
+    insecure: "sometimes"
+    ca_file: /nonexistent/ca.pem
+    rate_limit: fast
+    extra_headers:
+      Authorization: Bearer x
+      X-Org-Token: "secret:"
+preferences:
+  max_concurrency: -1
+  secret_patterns: ["creds", "("]
+colour: auto
+context_rules:
+  - prefix: team-a/
+    context: team-a
+  - prefix: /
+    context: prod
+`)
+
+	findings := Validate(data, ValidateOptions{RateLimit: func(spec string) error {
+		return os.ErrInvalid
+	}})
+
+	got := make([]string, len(findings))
+	for i, finding := range findings {
+		got[i] = fmt.Sprintf("%s@%d: %s", finding.Key, finding.Line, finding.Message)
+	}
+	want := []string{
+		"active@2: active context \"staging\" is not defined under contexts",
+		"contexts.prod@4: context \"prod\" has no url",
+		"@5: unknown key \"urll\" in a context",
+		"contexts.dev.url@8: context \"dev\": url \"ci.example.com\" is not an absolute http(s) URL",
+		"@9: type mismatch: cannot unmarshal !!str `sometimes` into bool",
+		"contexts.dev.ca_file@10: context \"dev\": ca_file \"/nonexistent/ca.pem\" does not exist",
+		"contexts.dev.rate_limit@11: context \"dev\": invalid argument",
+		"contexts.dev.extra_headers.Authorization@13: context \"dev\": the Authorization header is set from the context's username and token",
+		"contexts.dev.extra_headers.X-Org-Token@14: context \"dev\": header X-Org-Token: secret: needs a key",
+		"preferences.max_concurrency@16: preferences: max_concurrency must not be negative",
+		"preferences.secret_patterns@17: preferences: invalid secret pattern \"(\": error parsing regexp: missing closing ): `(`",
+		"@18: unknown key \"colour\" at the top level",
+		"context_rules.0.context@21: context rule \"team-a/\": context \"team-a\" is not defined under contexts",
+		"context_rules.1.prefix@22: context rule has no prefix",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("findings:\n%v\nwant:\n%v", got, want)
+	}
+}
+
+func TestValidateCleanAndBrokenFiles(t *testing.T) {
+	clean := []byte("version: 1\nactive: prod\ncontexts:\n  prod:\n    url: https://ci.example.com\n")
+	if findings := Validate(clean, ValidateOptions{}); len(findings) != 0 {
+		t.Fatalf("expected no findings, got %+v", findings)
+	}
+	if findings := Validate(nil, ValidateOptions{}); len(findings) != 0 {
+		t.Fatalf("expected an empty file to be valid, got %+v", findings)
+	}
+
+	findings := Validate([]byte("contexts:\n  prod:\n    url: [\n"), ValidateOptions{})
+	if len(findings) != 1 || findings[0].Line == 0 {
+		t.Fatalf("expected one syntax finding with a line, got %+v", findings)
+	}
+}
@@ -0,0 +1,78 @@
+// Package configsnap keeps a snapshot of job config.xml files per context, so
+// configuration changes can be found on controllers without a config history
+// plugin.
+//
+// Snapshots live in the "config-snapshots" directory of the jk cache (see
+// cachefile), one file per context, and are replaced atomically. They hold config.xml
+// verbatim, so the files are readable only by the owner.
+package configsnap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+)
+
+// cacheKind names the cache directory of the snapshots.
+const cacheKind = "config-snapshots"
+
+// Snapshot is the state of the job configs in one context.
+type Snapshot struct {
+	// Folder is the folder the snapshot was scoped to; empty for the whole
+	// controller.
+	Folder  string    `json:"folder,omitempty"`
+	TakenAt time.Time `json:"takenAt"`
+	// Truncated reports that the job cap stopped the snapshot before every
+	// job under Folder was recorded.
+	Truncated bool           `json:"truncated,omitempty"`
+	Jobs      map[string]Job `json:"jobs"`
+}
+
+// Job is one job's config.xml and its checksum.
+type Job struct {
+	Checksum string `json:"checksum"`
+	Config   string `json:"config"`
+}
+
+// NewJob records config with its checksum.
+func NewJob(config []byte) Job {
+	return Job{Checksum: Checksum(config), Config: string(config)}
+}
+
+// Checksum is the hex SHA-256 of config.
+func Checksum(config []byte) string {
+	sum := sha256.Sum256(config)
+	return hex.EncodeToString(sum[:])
+}
+
+// Load returns the snapshot for contextName, or nil when none was taken.
+func Load(contextName string) (*Snapshot, error) {
+	data, err := cachefile.Read(cacheKind, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("read config snapshot: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("decode config snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// Save replaces the snapshot for contextName atomically.
+func Save(contextName string, snapshot Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("encode config snapshot: %w", err)
+	}
+	if err := cachefile.Write(cacheKind, contextName, data); err != nil {
+		return fmt.Errorf("write config snapshot: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Package deprecation remembers the endpoints each context's controller
+// marked deprecated, so users are warned before an endpoint disappears
+// without being warned on every command.
+//
+// Sightings live in the "deprecations" directory of the jk cache (see
+// cachefile), one file per context, and are replaced atomically.
+package deprecation
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+)
+
+// cacheKind names the cache directory of the sightings.
+const cacheKind = "deprecations"
+
+// WarnInterval is how long a warning about one endpoint is suppressed after
+// it was shown.
+const WarnInterval = 24 * time.Hour
+
+// DefaultHeaders are the response headers that mark an endpoint deprecated
+// when preferences.deprecation_headers is unset: the IETF Deprecation header
+// and the one the jk companion plugin sets on its facade endpoints.
+var DefaultHeaders = []string{"Deprecation", "X-JK-Deprecated"}
+
+// Endpoint is one endpoint seen deprecated.
+type Endpoint struct {
+	Path string `json:"path"`
+	// Header is the header that marked the endpoint, and Value what it said
+	// the last time it was seen (a date, "true", or a note).
+	Header     string    `json:"header"`
+	Value      string    `json:"value,omitempty"`
+	FirstSeen  time.Time `json:"firstSeen"`
+	LastSeen   time.Time `json:"lastSeen"`
+	LastWarned time.Time `json:"lastWarned"`
+}
+
+// State is the deprecated endpoints of one context, keyed by path.
+type State struct {
+	Endpoints map[string]*Endpoint `json:"endpoints"`
+}
+
+// Observe records a sighting of path marked by header at now and reports
+// whether to warn about it: on the first sighting, and again once
+// WarnInterval has passed since the last warning.
+func (s *State) Observe(path, header, value string, now time.Time) bool {
+	now = now.UTC()
+	endpoint := s.Record(path, header, value, now)
+	if !endpoint.LastWarned.IsZero() && now.Sub(endpoint.LastWarned) < WarnInterval {
+		return false
+	}
+	endpoint.LastWarned = now
+	return true
+}
+
+// Record records a sighting of path marked by header at now without counting
+// it as warned about, for runs that keep warnings to themselves.
+func (s *State) Record(path, header, value string, now time.Time) *Endpoint {
+	if s.Endpoints == nil {
+		s.Endpoints = make(map[string]*Endpoint)
+	}
+	now = now.UTC()
+	endpoint, ok := s.Endpoints[path]
+	if !ok {
+		endpoint = &Endpoint{Path: path, FirstSeen: now}
+		s.Endpoints[path] = endpoint
+	}
+	endpoint.Header, endpoint.Value, endpoint.LastSeen = header, value, now
+	return endpoint
+}
+
+// List returns the recorded endpoints sorted by path.
+func (s *State) List() []Endpoint {
+	if s == nil {
+		return []Endpoint{}
+	}
+	list := make([]Endpoint, 0, len(s.Endpoints))
+	for _, endpoint := range s.Endpoints {
+		list = append(list, *endpoint)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Path < list[j].Path })
+	return list
+}
+
+// Load returns the state for contextName, empty when nothing was recorded.
+func Load(contextName string) (*State, error) {
+	state := &State{Endpoints: make(map[string]*Endpoint)}
+	data, err := cachefile.Read(cacheKind, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("read deprecations: %w", err)
+	}
+	if data == nil {
+		return state, nil
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("decode deprecations: %w", err)
+	}
+	if state.Endpoints == nil {
+		state.Endpoints = make(map[string]*Endpoint)
+	}
+	return state, nil
+}
+
+// Save replaces the state for contextName atomically.
+func Save(contextName string, state *State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode deprecations: %w", err)
+	}
+	if err := cachefile.Write(cacheKind, contextName, data); err != nil {
+		return fmt.Errorf("write deprecations: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package deprecation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+)
+
+func TestObserveWarnsOncePerInterval(t *testing.T) {
+	start := time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)
+	state := &State{}
+
+	steps := []struct {
+		at   time.Duration
+		path string
+		want bool
+	}{
+		{at: 0, path: "/jk/api/credentials", want: true},
+		{at: time.Hour, path: "/jk/api/credentials", want: false},
+		{at: time.Hour, path: "/jk/api/runs", want: true},
+		{at: 23 * time.Hour, path: "/jk/api/credentials", want: false},
+		{at: 24 * time.Hour, path: "/jk/api/credentials", want: true},
+		{at: 30 * time.Hour, path: "/jk/api/credentials", want: false},
+	}
+	for _, step := range steps {
+		if got := state.Observe(step.path, "X-Jk-Deprecated", "true", start.Add(step.at)); got != step.want {
+			t.Fatalf("%s at +%v: warn = %v, want %v", step.path, step.at, got, step.want)
+		}
+	}
+
+	credentials := state.Endpoints["/jk/api/credentials"]
+	if !credentials.FirstSeen.Equal(start) || !credentials.LastSeen.Equal(start.Add(30*time.Hour)) {
+		t.Fatalf("seen = %v..%v, want %v..%v", credentials.FirstSeen, credentials.LastSeen, start, start.Add(30*time.Hour))
+	}
+	if !credentials.LastWarned.Equal(start.Add(24 * time.Hour)) {
+		t.Fatalf("last warned = %v, want +24h", credentials.LastWarned)
+	}
+}
+
+func TestSaveLoadKeepsSuppression(t *testing.T) {
+	t.Setenv(cachefile.DirEnv, t.TempDir())
+	now := time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)
+
+	state, err := Load("prod")
+	if err != nil {
+		t.Fatalf("load empty state: %v", err)
+	}
+	if len(state.List()) != 0 {
+		t.Fatalf("empty state lists %v", state.List())
+	}
+	if !state.Observe("/jk/api/credentials", "Deprecation", "@1767225600", now) {
+		t.Fatal("first sighting did not warn")
+	}
+	if err := Save("prod", state); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// A later process loads the state and stays quiet within the interval.
+	reloaded, err := Load("prod")
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if reloaded.Observe("/jk/api/credentials", "Deprecation", "@1767225600", now.Add(2*time.Hour)) {
+		t.Fatal("reloaded state warned again within the interval")
+	}
+	list := reloaded.List()
+	if len(list) != 1 || list[0].Path != "/jk/api/credentials" || list[0].Value != "@1767225600" {
+		t.Fatalf("list = %+v", list)
+	}
+
+	other, err := Load("staging")
+	if err != nil {
+		t.Fatalf("load other context: %v", err)
+	}
+	if len(other.Endpoints) != 0 {
+		t.Fatalf("state leaked across contexts: %v", other.Endpoints)
+	}
+}
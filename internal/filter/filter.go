@@ -64,12 +64,36 @@ var supportedKeys = []string{
 	"queue.id",
 	"started",
 	"duration",
+	"abortedBy",
 }
 
-var secretKeywords = []string{"password", "secret", "token", "apikey", "api_key", "key", "pwd"}
-
 // Parse converts raw flag values into Filter structures.
 func Parse(raw []string) ([]Filter, error) {
+	return parse(raw, validateKey)
+}
+
+// ParseKeys is Parse for commands with their own key set, such as job
+// listing. Keys match case-insensitively and are returned lower-cased, so
+// evaluation contexts must use lower-case keys.
+func ParseKeys(raw []string, keys []string) ([]Filter, error) {
+	filters, err := parse(raw, func(key string) error {
+		for _, candidate := range keys {
+			if strings.EqualFold(candidate, key) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrUnsupportedKey, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range filters {
+		filters[i].Key = strings.ToLower(filters[i].Key)
+	}
+	return filters, nil
+}
+
+func parse(raw []string, validate func(string) error) ([]Filter, error) {
 	filters := make([]Filter, 0, len(raw))
 	for _, entry := range raw {
 		entry = strings.TrimSpace(entry)
@@ -96,7 +120,7 @@ func Parse(raw []string) ([]Filter, error) {
 			return nil, fmt.Errorf("%w: %q", ErrInvalidFilter, entry)
 		}
 
-		if err := validateKey(key); err != nil {
+		if err := validate(key); err != nil {
 			return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
 		}
 
@@ -292,13 +316,17 @@ func compareTime(actual, expected time.Time, op Operator) bool {
 	}
 }
 
+// parseTimeOrDuration resolves an absolute RFC3339 timestamp first, then falls
+// back to a relative duration meaning "that long ago". Checking timestamps
+// first keeps values like "2025-01-01T00:00:00Z" from being probed as
+// durations.
 func parseTimeOrDuration(value string) (time.Time, error) {
-	if d, err := ParseDuration(value); err == nil {
-		return time.Now().Add(-d), nil
-	}
 	if ts, err := time.Parse(time.RFC3339, value); err == nil {
 		return ts, nil
 	}
+	if d, err := ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
 	return time.Time{}, fmt.Errorf("invalid time value %q", value)
 }
 
@@ -372,6 +400,38 @@ func Operators() []string {
 	return result
 }
 
+// OperatorInfo documents an operator for self-describing CLI output.
+type OperatorInfo struct {
+	Operator    string `json:"operator"`
+	Description string `json:"description"`
+	Example     string `json:"example"`
+}
+
+var operatorHelp = map[Operator]OperatorInfo{
+	OpEQ:  {Description: "equals (case-insensitive)", Example: "result=FAILURE"},
+	OpNEQ: {Description: "does not equal", Example: "result!=SUCCESS"},
+	OpSUB: {Description: "contains (case-insensitive)", Example: "param.CHART_NAME~nova"},
+	OpREG: {Description: "matches a regular expression with --regex, contains otherwise", Example: "branch~=^release/"},
+	OpPFX: {Description: "starts with", Example: "branch^feature/"},
+	OpSFX: {Description: "ends with", Example: "artifact.name$.tgz"},
+	OpGTE: {Description: "at least (numbers, durations, times)", Example: "duration>=10m"},
+	OpLTE: {Description: "at most (numbers, durations, times)", Example: "queue.id<=1200"},
+	OpGT:  {Description: "greater than", Example: "started>24h"},
+	OpLT:  {Description: "less than", Example: "duration<30s"},
+}
+
+// OperatorDetails returns every operator with a description and an example
+// expression, in parse precedence order.
+func OperatorDetails() []OperatorInfo {
+	out := make([]OperatorInfo, 0, len(orderedOperators))
+	for _, op := range orderedOperators {
+		info := operatorHelp[op]
+		info.Operator = string(op)
+		out = append(out, info)
+	}
+	return out
+}
+
 // RequiresArtifacts reports if any filter references artifact fields.
 func RequiresArtifacts(filters []Filter) bool {
 	for _, f := range filters {
@@ -402,17 +462,22 @@ func RequiresCauses(filters []Filter) bool {
 	return false
 }
 
-// IsLikelySecret indicates whether a parameter name probably holds a secret.
-func IsLikelySecret(name string) bool {
-	lower := strings.ToLower(name)
-	for _, keyword := range secretKeywords {
-		if strings.Contains(lower, keyword) {
+// RequiresInterruption reports if any filter references who aborted a build.
+func RequiresInterruption(filters []Filter) bool {
+	for _, f := range filters {
+		if f.Key == "abortedBy" {
 			return true
 		}
 	}
 	return false
 }
 
+// IsLikelySecret indicates whether a parameter name probably holds a secret
+// by the built-in keywords alone. Commands use the configured Redactor.
+func IsLikelySecret(name string) bool {
+	return (*Redactor)(nil).IsSecret(name)
+}
+
 // Option configures evaluation behavior.
 type Option func(*settings)
 
@@ -30,6 +30,19 @@ func TestParseInvalidKey(t *testing.T) {
 	}
 }
 
+func TestParseKeys(t *testing.T) {
+	filters, err := ParseKeys([]string{"lastBuildResult=FAILURE", "name^api"}, []string{"name", "lastBuildResult"})
+	if err != nil {
+		t.Fatalf("ParseKeys returned error: %v", err)
+	}
+	if filters[0].Key != "lastbuildresult" || filters[1].Key != "name" {
+		t.Fatalf("expected lower-cased keys, got %+v", filters)
+	}
+	if _, err := ParseKeys([]string{"result=SUCCESS"}, []string{"name"}); err == nil {
+		t.Fatal("expected run keys to be rejected outside the supplied set")
+	}
+}
+
 func TestEvaluateStringAndNumeric(t *testing.T) {
 	ctx := Context{
 		"result":   "SUCCESS",
@@ -73,6 +86,34 @@ func TestEvaluateTime(t *testing.T) {
 	}
 }
 
+func TestEvaluateTimeBounds(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name    string
+		expr    string
+		started time.Time
+		want    bool
+	}{
+		{"before absolute upper bound", "started<2025-01-01T00:00:00Z", time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC), true},
+		{"after absolute upper bound", "started<2025-01-01T00:00:00Z", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"after absolute lower bound", "started>=2025-01-01T00:00:00Z", time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), true},
+		{"offset timestamp", "started<2025-01-01T02:00:00+02:00", time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC), true},
+		{"older than relative bound", "started<72h", now.Add(-96 * time.Hour), true},
+		{"newer than relative bound", "started<72h", now.Add(-time.Hour), false},
+		{"within relative lower bound", "started>72h", now.Add(-time.Hour), true},
+	}
+
+	for _, tc := range cases {
+		filters, err := Parse([]string{tc.expr})
+		if err != nil {
+			t.Fatalf("%s: Parse error: %v", tc.name, err)
+		}
+		if got := Evaluate(Context{"started": tc.started}, filters); got != tc.want {
+			t.Fatalf("%s: expected %v for %s", tc.name, tc.want, tc.expr)
+		}
+	}
+}
+
 func TestParseDuration(t *testing.T) {
 	cases := map[string]time.Duration{
 		"15m":  15 * time.Minute,
@@ -100,3 +141,22 @@ func TestIsLikelySecret(t *testing.T) {
 		t.Fatal("expected ENV not to be secret")
 	}
 }
+
+func TestOperatorDetailsExamplesParse(t *testing.T) {
+	details := OperatorDetails()
+	if len(details) != len(orderedOperators) {
+		t.Fatalf("expected %d operators, got %d", len(orderedOperators), len(details))
+	}
+	for _, info := range details {
+		if info.Description == "" || info.Example == "" {
+			t.Fatalf("operator %q missing help", info.Operator)
+		}
+		filters, err := Parse([]string{info.Example})
+		if err != nil {
+			t.Fatalf("example %q: %v", info.Example, err)
+		}
+		if string(filters[0].Operator) != info.Operator {
+			t.Fatalf("example %q parsed as %q, want %q", info.Example, filters[0].Operator, info.Operator)
+		}
+	}
+}
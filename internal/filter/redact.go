@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue replaces the value of a likely secret wherever it would be
+// shown, in human and structured output alike.
+const RedactedValue = "[REDACTED]"
+
+var secretKeywords = []string{"password", "secret", "token", "apikey", "api_key", "key", "pwd"}
+
+// Redactor decides which parameter, variable, and setting names hold
+// secrets. A name is a secret when it contains one of the built-in keywords
+// or matches one of the configured patterns, unless it is allowlisted. A nil
+// Redactor applies the keywords alone.
+type Redactor struct {
+	patterns  []*regexp.Regexp
+	nonSecret map[string]struct{}
+}
+
+// NewRedactor builds a Redactor from the preferences secret_patterns
+// (regular expressions matched case-insensitively anywhere in a name) and
+// non_secret_names (exact names, compared case-insensitively).
+func NewRedactor(patterns, nonSecretNames []string) (*Redactor, error) {
+	r := &Redactor{nonSecret: make(map[string]struct{}, len(nonSecretNames))}
+	for _, pattern := range patterns {
+		re, err := CompileSecretPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	for _, name := range nonSecretNames {
+		if name = strings.TrimSpace(name); name != "" {
+			r.nonSecret[strings.ToLower(name)] = struct{}{}
+		}
+	}
+	return r, nil
+}
+
+// CompileSecretPattern compiles one secret_patterns entry the way
+// NewRedactor matches it.
+func CompileSecretPattern(pattern string) (*regexp.Regexp, error) {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return nil, fmt.Errorf("invalid secret pattern %q: %w", pattern, err)
+	}
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// IsSecret reports whether name probably holds a secret.
+func (r *Redactor) IsSecret(name string) bool {
+	lower := strings.ToLower(name)
+	if r != nil {
+		if _, ok := r.nonSecret[lower]; ok {
+			return false
+		}
+		for _, re := range r.patterns {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+	}
+	for _, keyword := range secretKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns value, or RedactedValue when name is a secret.
+func (r *Redactor) Redact(name, value string) string {
+	if r.IsSecret(name) {
+		return RedactedValue
+	}
+	return value
+}
@@ -0,0 +1,60 @@
+package filter
+
+import "testing"
+
+func TestRedactorAllowlistOverridesKeyword(t *testing.T) {
+	r, err := NewRedactor(nil, []string{"public_key_fingerprint"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.IsSecret("PUBLIC_KEY_FINGERPRINT") {
+		t.Fatal("expected the allowlisted name not to be secret")
+	}
+	if !r.IsSecret("DEPLOY_KEY") {
+		t.Fatal("expected other keyword hits to stay secret")
+	}
+	if got := r.Redact("PUBLIC_KEY_FINGERPRINT", "SHA256:abc"); got != "SHA256:abc" {
+		t.Fatalf("Redact = %q, want the value", got)
+	}
+}
+
+func TestRedactorCustomPatternsAddHits(t *testing.T) {
+	r, err := NewRedactor([]string{"creds", `^vault_`}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"DB_CREDS", "VAULT_ROLE", "API_TOKEN"} {
+		if !r.IsSecret(name) {
+			t.Errorf("expected %s to be secret", name)
+		}
+	}
+	if r.IsSecret("MY_VAULT_ROLE") {
+		t.Error("expected an anchored pattern to miss MY_VAULT_ROLE")
+	}
+	if got := r.Redact("DB_CREDS", "hunter2"); got != RedactedValue {
+		t.Fatalf("Redact = %q, want %q", got, RedactedValue)
+	}
+}
+
+func TestRedactorAllowlistBeatsPattern(t *testing.T) {
+	r, err := NewRedactor([]string{"creds"}, []string{"CREDS_HELPER_URL"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.IsSecret("CREDS_HELPER_URL") {
+		t.Fatal("expected the allowlist to win over a custom pattern")
+	}
+}
+
+func TestRedactorNilUsesKeywords(t *testing.T) {
+	var r *Redactor
+	if !r.IsSecret("API_TOKEN") || r.IsSecret("ENV") {
+		t.Fatal("expected a nil Redactor to apply the built-in keywords")
+	}
+}
+
+func TestNewRedactorRejectsInvalidPattern(t *testing.T) {
+	if _, err := NewRedactor([]string{"("}, nil); err == nil {
+		t.Fatal("expected an invalid pattern to fail")
+	}
+}
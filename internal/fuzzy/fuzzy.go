@@ -36,8 +36,11 @@ func Search(query string, items []string, maxResults int) []Match {
 		if matches[i].Score != matches[j].Score {
 			return matches[i].Score > matches[j].Score
 		}
-		// Tie-breaker: prefer shorter strings
-		return len(matches[i].Value) < len(matches[j].Value)
+		// Tie-breaker: prefer shorter strings, then alphabetical order
+		if len(matches[i].Value) != len(matches[j].Value) {
+			return len(matches[i].Value) < len(matches[j].Value)
+		}
+		return matches[i].Value < matches[j].Value
 	})
 
 	if maxResults > 0 && len(matches) > maxResults {
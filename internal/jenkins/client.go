@@ -44,6 +44,12 @@ type Client struct {
 	crumb            *crumbValue
 	crumbMu          sync.Mutex
 	crumbUnsupported bool
+	conditional      *conditionalCache
+	reauth           reauthState
+	reauthMu         sync.Mutex
+	clock            *serverClock
+	whoAmI           whoAmICache
+	deprecations     *deprecationWatch
 }
 
 // Capabilities captures Jenkins feature detection results.
@@ -100,7 +106,9 @@ func NewClient(ctx context.Context, cfg *config.Config, contextName string) (*Cl
 		storeOpts = append(storeOpts, secret.WithAllowFileFallback(true))
 	}
 
+	endSecretOpen := StartSpan(SpanSecretOpen)
 	store, err := secret.Open(storeOpts...)
+	endSecretOpen()
 	var token string
 	switch {
 	case err == nil:
@@ -122,56 +130,148 @@ func NewClient(ctx context.Context, cfg *config.Config, contextName string) (*Cl
 		case legacyErr != nil:
 			return nil, fmt.Errorf("load token for context %s: %w", contextName, legacyErr)
 		}
+		store = legacyStore
 	default:
 		return nil, err
 	}
 
-	parsedURL, err := url.Parse(ctxDef.URL)
+	headers, err := resolveExtraHeaders(store, contextName, ctxDef.ExtraHeaders)
 	if err != nil {
-		return nil, fmt.Errorf("invalid Jenkins URL for context %s: %w", contextName, err)
+		return nil, err
 	}
+	return newClient(ctx, contextName, ctxDef, token, headers, cfg.Preferences.DeprecationHeaders)
+}
 
-	restyClient := resty.New()
-	restyClient.SetBaseURL(strings.TrimSuffix(parsedURL.String(), "/"))
+// NewUnsavedClient constructs a client for a context that is not in the
+// configuration yet, taking its token and resolved header values directly,
+// so credentials can be checked before anything is stored.
+func NewUnsavedClient(ctx context.Context, contextName string, ctxDef *config.Context, token string, headers map[string]string) (*Client, error) {
+	if ctxDef == nil {
+		return nil, errors.New("context is required")
+	}
+	return newClient(ctx, contextName, ctxDef, token, headers, nil)
+}
+
+func newClient(ctx context.Context, contextName string, ctxDef *config.Context, token string, headers map[string]string, deprecationHeaders []string) (*Client, error) {
+	limit, err := effectiveRateLimit(ctxDef.RateLimit)
+	if err != nil {
+		return nil, fmt.Errorf("context %s: %w", contextName, err)
+	}
+
+	restyClient, err := newRestyClient(contextName, ctxDef)
+	if err != nil {
+		return nil, err
+	}
 	restyClient.SetHeader(headerJKClient, build.Version)
 	restyClient.SetHeader(headerJKFeatures, defaultFeatures)
-	restyClient.SetHeader("User-Agent", fmt.Sprintf("%s/%s", defaultUserAgent, build.Version))
 	restyClient.SetRetryCount(2)
 	restyClient.SetRetryWaitTime(500 * time.Millisecond)
 	restyClient.SetRetryMaxWaitTime(3 * time.Second)
 	restyClient.SetBasicAuth(ctxDef.Username, token)
 	restyClient.SetTimeout(30 * time.Second)
 	restyClient.SetHeader("Accept", "application/json")
+	restyClient.SetHeaders(headers)
+	installRequestLog(restyClient, ctxDef.HeaderNames())
+	instrumentTimings(restyClient)
+	installRateLimit(restyClient, newRateLimiter(limit))
+	clock := newServerClock()
+	installServerClock(restyClient, clock)
+	deprecations := newDeprecationWatch(contextName, restyClient.BaseURL, deprecationHeaders)
+	installDeprecationWatch(restyClient, deprecations)
+
+	restyStream := restyClient.Clone()
+	restyStream.SetTimeout(0)
+
+	client := &Client{
+		resty:        restyClient,
+		restyStream:  restyStream,
+		contextName:  contextName,
+		ctxConfig:    ctxDef,
+		conditional:  newConditionalCache(conditionalCacheSize),
+		clock:        clock,
+		deprecations: deprecations,
+	}
+
+	if err := client.refreshCapabilities(ctx); err != nil {
+		log.L().Warn().Err(err).Msg("capability detection failed")
+	}
+
+	return client, nil
+}
+
+// newRestyClient returns an unauthenticated resty client for ctxDef's URL
+// with its proxy and TLS settings, including the --insecure-skip-tls-verify
+// override.
+func newRestyClient(contextName string, ctxDef *config.Context) (*resty.Client, error) {
+	parsedURL, err := url.Parse(ctxDef.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jenkins URL for context %s: %w", contextName, err)
+	}
+
+	restyClient := resty.New()
+	restyClient.SetBaseURL(strings.TrimSuffix(parsedURL.String(), "/"))
+	restyClient.SetHeader("User-Agent", fmt.Sprintf("%s/%s", defaultUserAgent, build.Version))
+
+	if ctxDef.AllowHTTP {
+		// The user acknowledged plain HTTP at login; resty's per-request
+		// basic-auth warning would only repeat that.
+		restyClient.SetDisableWarn(true)
+	}
 
 	if ctxDef.Proxy != "" {
 		restyClient.SetProxy(ctxDef.Proxy)
 	}
 
-	if ctxDef.Insecure {
+	skipVerify := InsecureOverridden()
+	if ctxDef.Insecure || skipVerify {
 		restyClient.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true}) //nolint:gosec // intentional per user configuration
 	}
 
-	if ctxDef.CAFile != "" {
+	if ctxDef.CAFile != "" && !skipVerify {
 		if err := applyCustomCA(restyClient, ctxDef.CAFile); err != nil {
 			return nil, err
 		}
 	}
+	return restyClient, nil
+}
 
-	restyStream := restyClient.Clone()
-	restyStream.SetTimeout(0)
-
-	client := &Client{
-		resty:       restyClient,
-		restyStream: restyStream,
-		contextName: contextName,
-		ctxConfig:   ctxDef,
+// Probe makes one anonymous request to the login page of ctxDef's URL with
+// the proxy and TLS settings a client for it would use, sending headers,
+// and returns the X-Jenkins version header. Any HTTP answer counts as
+// reachable.
+func Probe(ctx context.Context, ctxDef *config.Context, headers map[string]string) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
-
-	if err := client.refreshCapabilities(ctx); err != nil {
-		log.L().Warn().Err(err).Msg("capability detection failed")
+	restyClient, err := newRestyClient("", ctxDef)
+	if err != nil {
+		return "", err
 	}
+	resp, err := restyClient.R().SetContext(ctx).SetHeaders(headers).Get("/login")
+	if err != nil {
+		return "", err
+	}
+	return resp.Header().Get("X-Jenkins"), nil
+}
 
-	return client, nil
+// resolveExtraHeaders reads the values of extra headers written as
+// "secret:<key>" from the secret store.
+func resolveExtraHeaders(store *secret.Store, contextName string, headers map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if key, ok := config.HeaderSecretRef(value); ok {
+			stored, err := store.Get(secret.HeaderKey(contextName, key))
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil, fmt.Errorf("header %s for context %s: secret %q not found; run 'jk auth login' with --header again", name, contextName, key)
+				}
+				return nil, fmt.Errorf("load header %s for context %s: %w", name, contextName, err)
+			}
+			value = stored
+		}
+		resolved[name] = value
+	}
+	return resolved, nil
 }
 
 func applyCustomCA(client *resty.Client, path string) error {
@@ -217,8 +317,13 @@ func (c *Client) ContextName() string {
 	return c.contextName
 }
 
-// Do executes the request with crumb handling.
+// Do executes the request with crumb handling. Absolute URLs are resolved
+// against the configured base URL (see RelativeToBase).
 func (c *Client) Do(req *resty.Request, method, path string, result interface{}) (*resty.Response, error) {
+	path = RelativeToBase(c.resty.BaseURL, path)
+	if result != nil && c.conditional != nil && method == http.MethodGet && isConditional(req) {
+		return c.doConditional(req, path, result)
+	}
 	if result != nil {
 		req.SetResult(result)
 	}
@@ -230,7 +335,22 @@ func (c *Client) Do(req *resty.Request, method, path string, result interface{})
 	return resp, nil
 }
 
+// execute sends the request, then retries once with renewed credentials when
+// it fails with 401 and a reauth hook is installed (see SetReauth).
 func (c *Client) execute(req *resty.Request, method, path string, allowRetry bool) (*resty.Response, error) {
+	generation, reauth := c.authGeneration()
+	resp, err := c.send(req, method, path, allowRetry)
+	if err != nil {
+		return nil, err
+	}
+	if reauth && isUnauthorized(resp) && canReplay(req) && c.renewCredentials(req.Context(), generation) {
+		discardBody(resp)
+		return c.send(req, method, path, false)
+	}
+	return resp, nil
+}
+
+func (c *Client) send(req *resty.Request, method, path string, allowRetry bool) (*resty.Response, error) {
 	if needsCrumb(method) {
 		crumb, err := c.ensureCrumb(req.Context())
 		if err != nil {
@@ -243,13 +363,13 @@ func (c *Client) execute(req *resty.Request, method, path string, allowRetry boo
 
 	resp, err := req.Execute(method, path)
 	if err != nil {
-		return nil, err
+		return nil, &RequestError{Method: method, URL: RedactURL(c.requestURL(req, path)), Err: err}
 	}
 
 	if allowRetry && needsCrumb(method) &&
 		(resp.StatusCode() == http.StatusForbidden || resp.StatusCode() == http.StatusUnauthorized) {
 		c.clearCrumb()
-		return c.execute(req, method, path, false)
+		return c.send(req, method, path, false)
 	}
 
 	return resp, nil
@@ -279,6 +399,8 @@ func (c *Client) ensureCrumb(ctx context.Context) (*crumbValue, error) {
 		ctx = context.Background()
 	}
 
+	defer StartSpan(SpanCrumbFetch)()
+
 	var result crumbResponse
 	resp, err := c.resty.R().SetContext(ctx).SetResult(&result).Get(crumbEndpoint)
 	if err != nil {
@@ -334,6 +456,8 @@ func (c *Client) refreshCapabilities(ctx context.Context) error {
 		ctx = context.Background()
 	}
 
+	defer StartSpan(SpanCapabilityProbe)()
+
 	var status statusResponse
 	resp, err := c.resty.R().SetContext(ctx).SetResult(&status).Get("/jk/api/status")
 	if err != nil {
@@ -397,21 +521,27 @@ func (c *Client) applyFeaturesHeader(caps Capabilities) {
 }
 
 func composeFeaturesHeader(caps Capabilities) string {
-	features := []string{defaultFeatures}
-	if caps.RunsFacade {
+	features := append([]string{defaultFeatures}, caps.Features()...)
+	return strings.Join(features, ",")
+}
+
+// Features lists the detected companion-plugin features by wire name.
+func (c Capabilities) Features() []string {
+	features := []string{}
+	if c.RunsFacade {
 		features = append(features, "runs")
 	}
-	if caps.CredentialFacade {
+	if c.CredentialFacade {
 		features = append(features, "credentials")
 	}
-	if caps.Events {
+	if c.Events {
 		features = append(features, "events")
 	}
-	if caps.SSEGateway {
+	if c.SSEGateway {
 		features = append(features, "sse")
 	}
-	if caps.Prometheus {
+	if c.Prometheus {
 		features = append(features, "prometheus")
 	}
-	return strings.Join(features, ",")
+	return features
 }
@@ -0,0 +1,110 @@
+package jenkins
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	// ClockSkewWarnEnv overrides the skew at which commands warn about the
+	// local clock (a Go duration such as "5m"; "0" disables the warning).
+	ClockSkewWarnEnv = "JK_CLOCK_SKEW_WARN"
+	// DefaultClockSkewWarn is the warning threshold when ClockSkewWarnEnv is
+	// unset or invalid.
+	DefaultClockSkewWarn = 2 * time.Minute
+	// minClockSkew is ignored: Date headers have one-second resolution, so
+	// smaller differences are noise.
+	minClockSkew = time.Second
+)
+
+// timeNow is the local clock; tests replace it.
+var timeNow = time.Now
+
+// serverClock tracks how far the controller's clock is from the local one,
+// taken from the Date header of the first response that carries one.
+type serverClock struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	skew  time.Duration
+	known bool
+}
+
+func newServerClock() *serverClock {
+	return &serverClock{now: timeNow}
+}
+
+// observe records the skew from a Date header value. Only the first valid
+// value counts, so a command works against one consistent offset.
+func (s *serverClock) observe(date string) {
+	if strings.TrimSpace(date) == "" {
+		return
+	}
+	server, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.known {
+		return
+	}
+	skew := server.Sub(s.now())
+	if skew > -minClockSkew && skew < minClockSkew {
+		skew = 0
+	}
+	s.skew, s.known = skew, true
+}
+
+func (s *serverClock) get() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.skew, s.known
+}
+
+// installServerClock feeds every response's Date header to clock.
+func installServerClock(client *resty.Client, clock *serverClock) {
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		clock.observe(resp.Header().Get("Date"))
+		return nil
+	})
+}
+
+// ClockSkew reports how far the controller's clock runs ahead of the local
+// one (negative when it is behind). ok is false until a response with a Date
+// header has arrived; the capability probe in NewClient usually provides it.
+func (c *Client) ClockSkew() (skew time.Duration, ok bool) {
+	if c == nil || c.clock == nil {
+		return 0, false
+	}
+	return c.clock.get()
+}
+
+// ServerNow is the controller's current time: the local time corrected by
+// ClockSkew. Relative --since/--until values are counted back from it so runs
+// stamped by a clock that is ahead of ours are not missed.
+func (c *Client) ServerNow() time.Time {
+	skew, _ := c.ClockSkew()
+	return timeNow().Add(skew)
+}
+
+// ClockSkewWarnThreshold is the |skew| above which commands warn, from
+// ClockSkewWarnEnv. Zero disables the warning.
+func ClockSkewWarnThreshold() time.Duration {
+	value := strings.TrimSpace(os.Getenv(ClockSkewWarnEnv))
+	if value == "" {
+		return DefaultClockSkewWarn
+	}
+	if value == "0" {
+		return 0
+	}
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold < 0 {
+		return DefaultClockSkewWarn
+	}
+	return threshold
+}
@@ -0,0 +1,98 @@
+package jenkins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+)
+
+func TestServerClockObserve(t *testing.T) {
+	local := time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)
+	header := func(d time.Duration) string { return local.Add(d).Format(http.TimeFormat) }
+
+	cases := []struct {
+		name   string
+		dates  []string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "server ahead", dates: []string{header(5 * time.Minute)}, want: 5 * time.Minute, wantOK: true},
+		{name: "server behind", dates: []string{header(-90 * time.Second)}, want: -90 * time.Second, wantOK: true},
+		{name: "header resolution is noise", dates: []string{header(500 * time.Millisecond)}, want: 0, wantOK: true},
+		{name: "first value wins", dates: []string{header(3 * time.Minute), header(time.Hour)}, want: 3 * time.Minute, wantOK: true},
+		{name: "invalid values skipped", dates: []string{"", "yesterday", header(-time.Hour)}, want: -time.Hour, wantOK: true},
+		{name: "nothing observed", dates: nil, wantOK: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &serverClock{now: func() time.Time { return local }}
+			for _, date := range tc.dates {
+				clock.observe(date)
+			}
+			got, ok := clock.get()
+			if got != tc.want || ok != tc.wantOK {
+				t.Fatalf("skew = %v (known %v), want %v (known %v)", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestClientServerNowFollowsDateHeader(t *testing.T) {
+	local := time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)
+	original := timeNow
+	timeNow = func() time.Time { return local }
+	t.Cleanup(func() { timeNow = original })
+
+	serverTime := local.Add(-4 * time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverTime.Format(http.TimeFormat))
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "clocktest")
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	if err != nil {
+		t.Fatalf("open secret store: %v", err)
+	}
+	if err := store.Set(secret.TokenKey("skewed"), "token"); err != nil {
+		t.Fatalf("store token: %v", err)
+	}
+	cfg := &config.Config{Contexts: map[string]*config.Context{
+		"skewed": {URL: server.URL, Username: "tester", AllowInsecureStore: true},
+	}}
+
+	client, err := NewClient(context.Background(), cfg, "skewed")
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	skew, ok := client.ClockSkew()
+	if !ok || skew != -4*time.Minute {
+		t.Fatalf("skew = %v (known %v), want -4m from the capability probe", skew, ok)
+	}
+	if got := client.ServerNow(); !got.Equal(serverTime) {
+		t.Fatalf("ServerNow = %v, want %v", got, serverTime)
+	}
+}
+
+func TestClockSkewWarnThreshold(t *testing.T) {
+	for value, want := range map[string]time.Duration{
+		"":      DefaultClockSkewWarn,
+		"0":     0,
+		"5m":    5 * time.Minute,
+		"bogus": DefaultClockSkewWarn,
+		"-1m":   DefaultClockSkewWarn,
+	} {
+		t.Setenv(ClockSkewWarnEnv, value)
+		if got := ClockSkewWarnThreshold(); got != want {
+			t.Fatalf("%q: threshold = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package jenkins
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// conditionalCacheSize bounds the validators kept per client. Polling loops
+// touch a handful of endpoints, so a small LRU is plenty.
+const conditionalCacheSize = 128
+
+type conditionalKey struct{}
+
+// WithConditionalRequests marks ctx so GETs issued with it send
+// If-None-Match/If-Modified-Since when the same request was seen before.
+// Only polling loops should opt in; one-shot commands always fetch fresh data.
+func WithConditionalRequests(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, conditionalKey{}, true)
+}
+
+// Conditional opts a single request into conditional GETs. Apply it after any
+// SetContext call, which would otherwise drop the marker.
+func Conditional(req *resty.Request) *resty.Request {
+	return req.SetContext(WithConditionalRequests(req.Context()))
+}
+
+func isConditional(req *resty.Request) bool {
+	on, _ := req.Context().Value(conditionalKey{}).(bool)
+	return on
+}
+
+type conditionalEntry struct {
+	key          string
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// conditionalCache is an LRU of validators and response bodies keyed by
+// context, path, and query. Bodies are stored raw and decoded on every hit so
+// callers never share a decoded value.
+type conditionalCache struct {
+	mu      sync.Mutex
+	limit   int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newConditionalCache(limit int) *conditionalCache {
+	return &conditionalCache{limit: limit, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *conditionalCache) get(key string) *conditionalEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*conditionalEntry)
+}
+
+func (c *conditionalCache) put(entry *conditionalEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*conditionalEntry).key)
+	}
+}
+
+func (c *conditionalCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+func (c *Client) conditionalKey(req *resty.Request, path string) string {
+	return c.contextName + " " + path + "?" + req.QueryParam.Encode()
+}
+
+// doConditional runs a GET that opted into conditional requests. A 304 is
+// rewritten into a 200 carrying the cached body, which is decoded into result.
+func (c *Client) doConditional(req *resty.Request, path string, result interface{}) (*resty.Response, error) {
+	key := c.conditionalKey(req, path)
+	cached := c.conditional.get(key)
+	if cached != nil {
+		if cached.etag != "" {
+			req.SetHeader("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.SetHeader("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	req.SetResult(result)
+	resp, err := c.execute(req, http.MethodGet, path, true)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode() == http.StatusNotModified && cached != nil:
+		if err := json.Unmarshal(cached.body, result); err != nil {
+			c.conditional.remove(key)
+			return nil, fmt.Errorf("decode cached response: %w", err)
+		}
+		if resp.RawResponse != nil {
+			resp.RawResponse.StatusCode = http.StatusOK
+			resp.RawResponse.Status = "200 OK"
+		}
+		resp.SetBody(cached.body)
+	case resp.StatusCode() == http.StatusOK:
+		etag := resp.Header().Get("ETag")
+		lastModified := resp.Header().Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			c.conditional.remove(key)
+			break
+		}
+		c.conditional.put(&conditionalEntry{
+			key:          key,
+			etag:         etag,
+			lastModified: lastModified,
+			body:         append([]byte(nil), resp.Body()...),
+		})
+	default:
+		c.conditional.remove(key)
+	}
+	return resp, nil
+}
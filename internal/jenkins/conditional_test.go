@@ -0,0 +1,134 @@
+package jenkins
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+type conditionalPayload struct {
+	Number int `json:"number"`
+}
+
+type conditionalStub struct {
+	mu      sync.Mutex
+	headers []http.Header
+	server  *httptest.Server
+}
+
+// newConditionalStub serves {"number":1} with an ETag and Last-Modified, and
+// answers 304 whenever the client presents the matching validator.
+func newConditionalStub(t *testing.T) *conditionalStub {
+	t.Helper()
+	stub := &conditionalStub{}
+	stub.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stub.mu.Lock()
+		stub.headers = append(stub.headers, r.Header.Clone())
+		stub.mu.Unlock()
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte(`{"number":1}`))
+	}))
+	t.Cleanup(stub.server.Close)
+	return stub
+}
+
+func (s *conditionalStub) client() *Client {
+	return &Client{
+		resty:       resty.New().SetBaseURL(s.server.URL),
+		contextName: "test",
+		conditional: newConditionalCache(conditionalCacheSize),
+	}
+}
+
+func (s *conditionalStub) header(i int) http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.headers[i]
+}
+
+func TestConditionalGetReusesCachedBodyOn304(t *testing.T) {
+	stub := newConditionalStub(t)
+	client := stub.client()
+
+	for i := 0; i < 2; i++ {
+		var payload conditionalPayload
+		resp, err := client.Do(Conditional(client.NewRequest().SetQueryParam("tree", "number")), http.MethodGet, "/job/app/1/api/json", &payload)
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if resp.StatusCode() != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i, resp.StatusCode())
+		}
+		if payload.Number != 1 {
+			t.Fatalf("request %d: payload %+v, want number 1", i, payload)
+		}
+	}
+
+	if got := stub.header(0).Get("If-None-Match"); got != "" {
+		t.Fatalf("first request sent If-None-Match %q", got)
+	}
+	if got := stub.header(1).Get("If-None-Match"); got != `"v1"` {
+		t.Fatalf("second request If-None-Match = %q, want \"v1\"", got)
+	}
+	if got := stub.header(1).Get("If-Modified-Since"); got != "Mon, 02 Jan 2006 15:04:05 GMT" {
+		t.Fatalf("second request If-Modified-Since = %q", got)
+	}
+}
+
+func TestConditionalGetKeysOnQuery(t *testing.T) {
+	stub := newConditionalStub(t)
+	client := stub.client()
+
+	var payload conditionalPayload
+	if _, err := client.Do(Conditional(client.NewRequest().SetQueryParam("tree", "number")), http.MethodGet, "/job/app/1/api/json", &payload); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(Conditional(client.NewRequest().SetQueryParam("tree", "result")), http.MethodGet, "/job/app/1/api/json", &payload); err != nil {
+		t.Fatal(err)
+	}
+	if got := stub.header(1).Get("If-None-Match"); got != "" {
+		t.Fatalf("different query reused validator %q", got)
+	}
+}
+
+func TestUnmarkedRequestsStayUnconditional(t *testing.T) {
+	stub := newConditionalStub(t)
+	client := stub.client()
+
+	for i := 0; i < 2; i++ {
+		var payload conditionalPayload
+		if _, err := client.Do(client.NewRequest(), http.MethodGet, "/job/app/1/api/json", &payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := stub.header(1).Get("If-None-Match"); got != "" {
+		t.Fatalf("one-shot request sent If-None-Match %q", got)
+	}
+}
+
+func TestConditionalCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newConditionalCache(2)
+	cache.put(&conditionalEntry{key: "a"})
+	cache.put(&conditionalEntry{key: "b"})
+	if cache.get("a") == nil {
+		t.Fatal("expected a to be cached")
+	}
+	cache.put(&conditionalEntry{key: "c"})
+
+	if cache.get("b") != nil {
+		t.Fatal("expected b to be evicted")
+	}
+	if cache.get("a") == nil || cache.get("c") == nil {
+		t.Fatal("expected a and c to remain cached")
+	}
+}
@@ -0,0 +1,114 @@
+package jenkins
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/avivsinai/jenkins-cli/internal/deprecation"
+)
+
+// DeprecationFunc is told about an endpoint the controller marked deprecated:
+// path is relative to the context URL, header the header that said so, and
+// value its content.
+type DeprecationFunc func(contextName, path, header, value string)
+
+// deprecationWatch looks for deprecation headers on responses and reports
+// each endpoint to its hook once per client. Sightings made before a hook is
+// installed, such as by the capability probe, are held until it is.
+type deprecationWatch struct {
+	mu      sync.Mutex
+	headers []string
+	baseURL string
+	context string
+	seen    map[string]bool
+	pending []deprecationSighting
+	fn      DeprecationFunc
+}
+
+type deprecationSighting struct {
+	path, header, value string
+}
+
+func newDeprecationWatch(contextName, baseURL string, headers []string) *deprecationWatch {
+	if len(headers) == 0 {
+		headers = deprecation.DefaultHeaders
+	}
+	return &deprecationWatch{headers: headers, baseURL: baseURL, seen: make(map[string]bool), context: contextName}
+}
+
+// observe reports the response's endpoint when one of the watched headers is
+// set and the endpoint was not reported before.
+func (w *deprecationWatch) observe(resp *resty.Response) {
+	if resp == nil || resp.RawResponse == nil || resp.RawResponse.Request == nil {
+		return
+	}
+	header, value := w.match(resp.Header())
+	if header == "" {
+		return
+	}
+	endpoint := *resp.RawResponse.Request.URL
+	endpoint.RawQuery = ""
+	path := RelativeToBase(w.baseURL, endpoint.String())
+
+	w.mu.Lock()
+	if w.seen[path] {
+		w.mu.Unlock()
+		return
+	}
+	w.seen[path] = true
+	fn := w.fn
+	if fn == nil {
+		w.pending = append(w.pending, deprecationSighting{path: path, header: header, value: value})
+	}
+	w.mu.Unlock()
+	if fn != nil {
+		fn(w.context, path, header, value)
+	}
+}
+
+func (w *deprecationWatch) match(headers http.Header) (string, string) {
+	for _, name := range w.headers {
+		if values := headers.Values(name); len(values) > 0 {
+			value := strings.TrimSpace(values[0])
+			if strings.EqualFold(value, "false") {
+				continue
+			}
+			return name, value
+		}
+	}
+	return "", ""
+}
+
+// installDeprecationWatch feeds every response to watch.
+func installDeprecationWatch(client *resty.Client, watch *deprecationWatch) {
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		watch.observe(resp)
+		return nil
+	})
+}
+
+// SetDeprecationHook installs the hook told about endpoints the controller
+// marks deprecated (see preferences.deprecation_headers), and reports the
+// endpoints seen so far. Each endpoint is reported at most once per client.
+func (c *Client) SetDeprecationHook(fn DeprecationFunc) {
+	w := c.deprecations
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	w.fn = fn
+	pending := w.pending
+	if fn != nil {
+		w.pending = nil
+	}
+	w.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	for _, sighting := range pending {
+		fn(w.context, sighting.path, sighting.header, sighting.value)
+	}
+}
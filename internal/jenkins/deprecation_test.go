@@ -0,0 +1,57 @@
+package jenkins
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestDeprecationWatchReportsEachEndpointOnce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/jk/api/credentials"):
+			w.Header().Set("X-JK-Deprecated", "use /jk/api/v2/credentials")
+		case strings.HasSuffix(r.URL.Path, "/computer/api/json"):
+			w.Header().Set("Deprecation", "false")
+		case strings.HasSuffix(r.URL.Path, "/job/a/api/json"):
+			w.Header().Set("Sunset-Notice", "soon")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	client := resty.New().SetBaseURL(server.URL + "/jenkins")
+	watch := newDeprecationWatch("prod", client.BaseURL, nil)
+	installDeprecationWatch(client, watch)
+
+	type report struct{ context, path, header, value string }
+	var reports []report
+	get := func(path string) {
+		t.Helper()
+		if _, err := client.R().SetQueryParam("depth", "1").Get(path); err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+	}
+
+	// Seen before a hook is installed: held, then reported once.
+	get("/jk/api/credentials")
+	(&Client{deprecations: watch}).SetDeprecationHook(func(contextName, path, header, value string) {
+		reports = append(reports, report{contextName, path, header, value})
+	})
+	get("/jk/api/credentials")
+	get("/computer/api/json")
+	get("/job/a/api/json")
+
+	want := []report{{"prod", "/jk/api/credentials", "X-JK-Deprecated", "use /jk/api/v2/credentials"}}
+	if len(reports) != len(want) || reports[0] != want[0] {
+		t.Fatalf("reports = %+v, want %+v", reports, want)
+	}
+
+	custom := newDeprecationWatch("prod", client.BaseURL, []string{"Sunset-Notice"})
+	if header, value := custom.match(http.Header{"Sunset-Notice": {"soon"}}); header != "Sunset-Notice" || value != "soon" {
+		t.Fatalf("custom header matched %q=%q", header, value)
+	}
+}
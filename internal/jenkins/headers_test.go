@@ -0,0 +1,117 @@
+package jenkins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+)
+
+func newHeaderClient(t *testing.T, headers map[string]string, secrets map[string]string) (*Client, *sync.Map, error) {
+	t.Helper()
+	seen := &sync.Map{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen.Store(r.Method+" "+r.URL.Path, r.Header.Clone())
+		switch r.URL.Path {
+		case "/crumbIssuer/api/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"crumb":"c","crumbRequestField":"Jenkins-Crumb"}`))
+		case "/job/app/1/logText/progressiveText":
+			w.Header().Set("X-Text-Size", "5")
+			_, _ = w.Write([]byte("hello"))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "headertest")
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	if err != nil {
+		t.Fatalf("open secret store: %v", err)
+	}
+	if err := store.Set(secret.TokenKey("gw"), "token"); err != nil {
+		t.Fatalf("store token: %v", err)
+	}
+	for key, value := range secrets {
+		if err := store.Set(secret.HeaderKey("gw", key), value); err != nil {
+			t.Fatalf("store header secret: %v", err)
+		}
+	}
+
+	cfg := &config.Config{Contexts: map[string]*config.Context{
+		"gw": {URL: server.URL, Username: "tester", AllowInsecureStore: true, AllowHTTP: true, ExtraHeaders: headers},
+	}}
+	client, err := NewClient(context.Background(), cfg, "gw")
+	return client, seen, err
+}
+
+func TestExtraHeadersReachEveryRequest(t *testing.T) {
+	client, seen, err := newHeaderClient(t,
+		map[string]string{"X-Org-Token": "secret:org", "Cookie": "gateway=1"},
+		map[string]string{"org": "from-keyring"})
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := client.Do(client.NewRequest(), http.MethodGet, "/job/app/api/json", nil); err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if _, err := client.Do(client.NewRequest(), http.MethodPost, "/job/app/build", nil); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	stream := client.NewStreamingRequest().SetDoNotParseResponse(true)
+	resp, err := client.Do(stream, http.MethodGet, "/job/app/1/logText/progressiveText", nil)
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	_ = resp.RawBody().Close()
+
+	for _, request := range []string{
+		"GET /job/app/api/json",
+		"GET /crumbIssuer/api/json",
+		"POST /job/app/build",
+		"GET /job/app/1/logText/progressiveText",
+	} {
+		value, ok := seen.Load(request)
+		if !ok {
+			t.Fatalf("%s was not sent", request)
+		}
+		header := value.(http.Header)
+		if got := header.Get("X-Org-Token"); got != "from-keyring" {
+			t.Fatalf("%s: X-Org-Token = %q, want the stored secret", request, got)
+		}
+		if got := header.Get("Cookie"); got != "gateway=1" {
+			t.Fatalf("%s: Cookie = %q", request, got)
+		}
+	}
+}
+
+func TestExtraHeaderMissingSecret(t *testing.T) {
+	_, _, err := newHeaderClient(t, map[string]string{"X-Org-Token": "secret:org"}, nil)
+	if err == nil || !strings.Contains(err.Error(), `secret "org" not found`) {
+		t.Fatalf("expected a missing secret error, got %v", err)
+	}
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{
+		"Authorization": {"Basic dGVzdGVyOnRva2Vu"},
+		"X-Org-Token":   {"from-keyring"},
+		"Accept":        {"application/json"},
+	}
+	sensitive := map[string]bool{"Authorization": true, "X-Org-Token": true}
+	got := strings.Join(redactHeaders(header, sensitive), "\n")
+	want := "Accept: application/json\nAuthorization: [REDACTED]\nX-Org-Token: [REDACTED]"
+	if got != want {
+		t.Fatalf("redactHeaders = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,164 @@
+package jenkins
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-resty/resty/v2"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxHTMLErrorBytes caps how much of an error page is parsed; the useful
+	// part of Jenkins error pages is near the top, stack traces can be huge.
+	maxHTMLErrorBytes = 256 * 1024
+	// maxHTMLErrorMessage caps the extracted message, in runes.
+	maxHTMLErrorMessage = 300
+)
+
+// exceptionLine matches the first line of a Java stack trace, e.g.
+// "java.lang.IllegalArgumentException: Illegal choice for parameter ENV".
+var exceptionLine = regexp.MustCompile(`^(?:Caused by: )?(?:[A-Za-z_$][\w$]*\.)+[A-Za-z_$][\w$]*(?:Exception|Error)\b(?::.*)?$`)
+
+// genericHeadings are error page headings that say nothing about the failure.
+var genericHeadings = map[string]bool{
+	"oops!": true,
+	"error": true,
+}
+
+// HTMLErrorMessage extracts a one-line explanation from the HTML error page
+// of a failed response: the exception from a stack trace, the message of a
+// Jenkins "Error" page, or the "A problem occurred" summary. It returns ""
+// for successful responses, non-HTML bodies, and pages without one.
+func HTMLErrorMessage(resp *resty.Response) string {
+	if resp == nil || resp.StatusCode() < 400 {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header().Get("Content-Type"))
+	if err != nil || mediaType != "text/html" {
+		return ""
+	}
+	body := resp.Body()
+	if len(body) > maxHTMLErrorBytes {
+		body = body[:maxHTMLErrorBytes]
+	}
+	return extractHTMLError(bytes.NewReader(body))
+}
+
+type htmlBlock struct {
+	tag  string
+	text string
+}
+
+// extractHTMLError picks the most specific message on an error page.
+func extractHTMLError(r io.Reader) string {
+	blocks := htmlBlocks(r)
+
+	for _, block := range blocks {
+		if block.tag != "pre" {
+			continue
+		}
+		for _, line := range strings.Split(block.text, "\n") {
+			line = strings.TrimSpace(line)
+			if exceptionLine.MatchString(line) {
+				return clipMessage(strings.TrimPrefix(line, "Caused by: "))
+			}
+		}
+	}
+
+	// hudson.model.Failure pages: <h1>Error</h1><p>message</p>.
+	for i, block := range blocks {
+		if block.tag == "h1" && strings.EqualFold(block.text, "error") {
+			for _, next := range blocks[i+1:] {
+				if next.tag == "p" && next.text != "" {
+					return clipMessage(next.text)
+				}
+			}
+		}
+	}
+
+	for i, block := range blocks {
+		if (block.tag == "h1" || block.tag == "h2") && block.text != "" && !genericHeadings[strings.ToLower(block.text)] {
+			message := strings.TrimSuffix(block.text, ".")
+			// The Oops page logs the stack trace under an ID instead of
+			// showing it; the ID is what an administrator needs.
+			for _, next := range blocks[i+1:] {
+				if next.tag == "p" && strings.HasPrefix(next.text, "Logging ID=") {
+					message += " (" + next.text + ")"
+					break
+				}
+			}
+			return clipMessage(message)
+		}
+	}
+	return ""
+}
+
+// htmlBlocks returns the text of the heading, paragraph, and pre
+// elements in document order. Whitespace is collapsed except in pre. The
+// tokenizer tolerates malformed markup; parsing stops at the first error.
+func htmlBlocks(r io.Reader) []htmlBlock {
+	var (
+		blocks  []htmlBlock
+		current *htmlBlock
+		text    strings.Builder
+		skip    int
+	)
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return blocks
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			switch tag {
+			case "script", "style":
+				skip++
+			case "h1", "h2", "p", "pre":
+				if current == nil {
+					current = &htmlBlock{tag: tag}
+					text.Reset()
+				}
+			case "br":
+				if current != nil {
+					text.WriteByte('\n')
+				}
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+			switch {
+			case tag == "script" || tag == "style":
+				if skip > 0 {
+					skip--
+				}
+			case current != nil && tag == current.tag:
+				current.text = text.String()
+				if current.tag != "pre" {
+					current.text = strings.Join(strings.Fields(current.text), " ")
+				}
+				blocks = append(blocks, *current)
+				current = nil
+			}
+		case html.TextToken:
+			if current != nil && skip == 0 {
+				text.Write(z.Text())
+			}
+		}
+	}
+}
+
+// clipMessage collapses s to one line of at most maxHTMLErrorMessage runes.
+func clipMessage(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if utf8.RuneCountInString(s) <= maxHTMLErrorMessage {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:maxHTMLErrorMessage-1])) + "…"
+}
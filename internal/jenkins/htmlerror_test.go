@@ -0,0 +1,91 @@
+package jenkins
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func htmlErrorFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "htmlerror", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+func TestExtractHTMLErrorFixtures(t *testing.T) {
+	tests := []struct {
+		fixture string
+		expect  string
+	}{
+		{"createitem-conflict.html", "A job already exists with the name ‘app’"},
+		{"script-security-rejected.html", "org.jenkinsci.plugins.scriptsecurity.sandbox.RejectedAccessException: Scripts not permitted to use staticMethod jenkins.model.Jenkins getInstance"},
+		{"missing-parameter.html", "java.lang.IllegalArgumentException: Illegal choice for parameter ENV: qa"},
+		{"oops-logging-id.html", "A problem occurred while processing the request (Logging ID=8d2c1f7e-54ab-4c0e-9f3e-2b7d5a61c0aa)"},
+	}
+
+	for _, tt := range tests {
+		got := extractHTMLError(strings.NewReader(string(htmlErrorFixture(t, tt.fixture))))
+		if got != tt.expect {
+			t.Fatalf("%s: expected %q got %q", tt.fixture, tt.expect, got)
+		}
+	}
+}
+
+func TestExtractHTMLErrorTolerant(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		expect string
+	}{
+		{"empty", "", ""},
+		{"no message", "<html><body><div>nothing here</div></body></html>", ""},
+		{"unclosed tags", "<h1>Error<p>Nothing is submitted", ""},
+		{"caused by", "<pre>\njavax.servlet.ServletException: wrapper\nCaused by: java.io.IOException: disk full\n</pre>", "javax.servlet.ServletException: wrapper"},
+		{"script ignored", "<h2><script>var x = 1;</script>Bad &amp; broken</h2>", "Bad & broken"},
+		{"generic heading only", "<h1>Oops!</h1>", ""},
+	}
+
+	for _, tt := range tests {
+		if got := extractHTMLError(strings.NewReader(tt.body)); got != tt.expect {
+			t.Fatalf("%s: expected %q got %q", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestClipMessage(t *testing.T) {
+	got := clipMessage(strings.Repeat("word ", 200))
+	if n := len([]rune(got)); n != maxHTMLErrorMessage {
+		t.Fatalf("expected %d runes, got %d", maxHTMLErrorMessage, n)
+	}
+	if !strings.HasSuffix(got, "…") || strings.Contains(got, "\n") {
+		t.Fatalf("unexpected clipped message %q", got)
+	}
+}
+
+func TestHTMLErrorMessageRequiresHTMLFailure(t *testing.T) {
+	page := htmlErrorFixture(t, "createitem-conflict.html")
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		expect      string
+	}{
+		{"html failure", http.StatusBadRequest, "text/html;charset=utf-8", "A job already exists with the name ‘app’"},
+		{"success", http.StatusOK, "text/html", ""},
+		{"json failure", http.StatusBadRequest, "application/json", ""},
+	}
+	for _, tt := range tests {
+		resp := &resty.Response{RawResponse: &http.Response{StatusCode: tt.status, Header: http.Header{"Content-Type": {tt.contentType}}}}
+		resp.SetBody(page)
+		if got := HTMLErrorMessage(resp); got != tt.expect {
+			t.Fatalf("%s: expected %q got %q", tt.name, tt.expect, got)
+		}
+	}
+}
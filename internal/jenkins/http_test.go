@@ -0,0 +1,45 @@
+package jenkins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+)
+
+func TestNewClientAllowHTTPDisablesWarning(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "httptest")
+
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	if err != nil {
+		t.Fatalf("open secret store: %v", err)
+	}
+	for _, name := range []string{"plain", "acknowledged"} {
+		if err := store.Set(secret.TokenKey(name), "token"); err != nil {
+			t.Fatalf("store token: %v", err)
+		}
+	}
+
+	cfg := &config.Config{Contexts: map[string]*config.Context{
+		"plain":        {URL: server.URL, Username: "tester", AllowInsecureStore: true},
+		"acknowledged": {URL: server.URL, Username: "tester", AllowInsecureStore: true, AllowHTTP: true},
+	}}
+
+	for name, want := range map[string]bool{"plain": false, "acknowledged": true} {
+		client, err := NewClient(context.Background(), cfg, name)
+		if err != nil {
+			t.Fatalf("create client %s: %v", name, err)
+		}
+		if client.resty.DisableWarn != want || client.restyStream.DisableWarn != want {
+			t.Fatalf("%s: DisableWarn = %v/%v, want %v", name, client.resty.DisableWarn, client.restyStream.DisableWarn, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package jenkinstest provides helpers for exercising commands against an
+// httptest-backed Jenkins controller.
+package jenkinstest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+)
+
+const contextName = "test"
+
+// NewClient starts an httptest server with handler and returns a client bound
+// to it. Credentials live in a throwaway encrypted file store.
+func NewClient(t testing.TB, handler http.Handler) *jenkins.Client {
+	t.Helper()
+	return NewClientAt(t, handler, "")
+}
+
+// NewClientAt is NewClient for a controller served under contextPath (for
+// example "/jenkins"). The handler sees the full request path.
+func NewClientAt(t testing.TB, handler http.Handler, contextPath string) *jenkins.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "jenkinstest")
+	// Commands that record the last triggered run or a config snapshot must
+	// not touch the real user cache.
+	t.Setenv(cachefile.DirEnv, t.TempDir())
+
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	if err != nil {
+		t.Fatalf("open secret store: %v", err)
+	}
+	if err := store.Set(secret.TokenKey(contextName), "token"); err != nil {
+		t.Fatalf("store token: %v", err)
+	}
+
+	cfg := &config.Config{
+		Active: contextName,
+		Contexts: map[string]*config.Context{
+			contextName: {URL: server.URL + contextPath, Username: "tester", AllowInsecureStore: true},
+		},
+	}
+
+	client, err := jenkins.NewClient(context.Background(), cfg, contextName)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	return client
+}
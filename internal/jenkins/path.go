@@ -5,30 +5,30 @@ import (
 	"strings"
 )
 
-const jobSegment = "job"
-
-// EncodeJobPath converts a human path like "team/app/main" into the Jenkins URL
-// form "job/team/job/app/job/main".
-func EncodeJobPath(human string) string {
-	trimmed := strings.Trim(human, "/")
-	if trimmed == "" {
-		return ""
+// RelativeToBase rewrites an absolute URL handed out by Jenkins, such as a
+// queue Location header, into a path under baseURL. Jenkins builds those URLs
+// from its own root URL setting, which behind a proxy often names an internal
+// host, so only the path and query are kept. A leading context path matching
+// baseURL's is stripped so it is not doubled when the base is re-applied.
+// Relative paths are returned unchanged.
+func RelativeToBase(baseURL, location string) string {
+	loc, err := url.Parse(location)
+	if err != nil || !loc.IsAbs() {
+		return location
 	}
 
-	segments := strings.Split(trimmed, "/")
-	var builder strings.Builder
-
-	for _, segment := range segments {
-		if segment == "" {
-			continue
-		}
-		if builder.Len() > 0 {
-			builder.WriteRune('/')
+	path := loc.EscapedPath()
+	if base, err := url.Parse(baseURL); err == nil {
+		contextPath := strings.TrimSuffix(base.EscapedPath(), "/")
+		if contextPath != "" && (path == contextPath || strings.HasPrefix(path, contextPath+"/")) {
+			path = strings.TrimPrefix(path, contextPath)
 		}
-		builder.WriteString(jobSegment)
-		builder.WriteRune('/')
-		builder.WriteString(url.PathEscape(segment))
 	}
-
-	return builder.String()
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if loc.RawQuery != "" {
+		path += "?" + loc.RawQuery
+	}
+	return path
 }
@@ -2,20 +2,25 @@ package jenkins
 
 import "testing"
 
-func TestEncodeJobPath(t *testing.T) {
+func TestRelativeToBase(t *testing.T) {
 	tests := []struct {
-		name   string
-		input  string
-		expect string
+		name     string
+		base     string
+		location string
+		expect   string
 	}{
-		{"empty", "", ""},
-		{"single", "example", "job/example"},
-		{"nested", "team/app/build", "job/team/job/app/job/build"},
-		{"spaces", "folder name/job", "job/folder%20name/job/job"},
+		{"relative unchanged", "https://ci.example.com/jenkins", "/queue/item/5/", "/queue/item/5/"},
+		{"root base", "https://ci.example.com", "http://jenkins-0:8080/queue/item/5/", "/queue/item/5/"},
+		{"context path stripped", "https://ci.example.com/jenkins", "http://jenkins-0:8080/jenkins/queue/item/5/", "/queue/item/5/"},
+		{"context path trailing slash", "https://ci.example.com/jenkins/", "https://ci.example.com/jenkins/queue/item/5/", "/queue/item/5/"},
+		{"proxy stripped prefix", "https://ci.example.com/jenkins", "http://jenkins-0:8080/queue/item/5/", "/queue/item/5/"},
+		{"prefix must end at segment", "https://ci.example.com/jenkins", "http://jenkins-0:8080/jenkins2/queue/item/5/", "/jenkins2/queue/item/5/"},
+		{"query kept", "https://ci.example.com/jenkins", "https://ci.example.com/jenkins/job/app/1/api/json?tree=number", "/job/app/1/api/json?tree=number"},
+		{"escaping kept", "https://ci.example.com/jenkins", "https://ci.example.com/jenkins/job/my%20app/", "/job/my%20app/"},
 	}
 
 	for _, tt := range tests {
-		got := EncodeJobPath(tt.input)
+		got := RelativeToBase(tt.base, tt.location)
 		if got != tt.expect {
 			t.Fatalf("%s: expected %s got %s", tt.name, tt.expect, got)
 		}
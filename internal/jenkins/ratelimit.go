@@ -0,0 +1,180 @@
+package jenkins
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RateLimitEnv overrides the per-context rate_limit setting.
+const RateLimitEnv = "JK_RATE_LIMIT"
+
+// RateLimit describes a token bucket: Rate requests per second on average,
+// with up to Burst requests allowed back to back. The zero value is unlimited.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled reports whether the limit throttles anything.
+func (l RateLimit) Enabled() bool {
+	return l.Rate > 0
+}
+
+var (
+	rateLimitMu       sync.Mutex
+	rateLimitOverride *RateLimit
+)
+
+// SetRateLimitOverride replaces the configured rate limit for every client
+// created afterwards. It backs the --rate-limit flag and JK_RATE_LIMIT.
+func SetRateLimitOverride(limit RateLimit) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	rateLimitOverride = &limit
+}
+
+func effectiveRateLimit(spec string) (RateLimit, error) {
+	rateLimitMu.Lock()
+	override := rateLimitOverride
+	rateLimitMu.Unlock()
+	if override != nil {
+		return *override, nil
+	}
+	return ParseRateLimit(spec)
+}
+
+// ParseRateLimit parses "<n>/s" or "<n>/m", optionally followed by
+// ",burst=<n>". The burst defaults to one second's worth of requests. An empty
+// string, "0", or "off" disables limiting.
+func ParseRateLimit(spec string) (RateLimit, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	switch spec {
+	case "", "0", "off", "none":
+		return RateLimit{}, nil
+	}
+
+	ratePart, burstPart, hasBurst := strings.Cut(spec, ",")
+	countPart, unit, ok := strings.Cut(strings.TrimSpace(ratePart), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q (want e.g. 10/s or 300/m)", spec)
+	}
+	count, err := strconv.ParseFloat(strings.TrimSpace(countPart), 64)
+	if err != nil || count <= 0 || math.IsInf(count, 0) {
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: request count must be a positive number", spec)
+	}
+
+	var limit RateLimit
+	switch strings.TrimSpace(unit) {
+	case "s", "sec", "second":
+		limit.Rate = count
+	case "m", "min", "minute":
+		limit.Rate = count / 60
+	default:
+		return RateLimit{}, fmt.Errorf("invalid rate limit %q: unit must be s or m", spec)
+	}
+
+	limit.Burst = int(math.Ceil(limit.Rate))
+	if hasBurst {
+		key, value, _ := strings.Cut(strings.TrimSpace(burstPart), "=")
+		burst, err := strconv.Atoi(strings.TrimSpace(value))
+		if strings.TrimSpace(key) != "burst" || err != nil || burst <= 0 {
+			return RateLimit{}, fmt.Errorf("invalid rate limit %q: burst must be burst=<positive integer>", spec)
+		}
+		limit.Burst = burst
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return limit, nil
+}
+
+// rateLimiter is a token bucket shared by every goroutine using a client.
+// Callers reserve a token up front, so concurrent waiters are spaced out
+// instead of all waking at once.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if !limit.Enabled() {
+		return nil
+	}
+	return &rateLimiter{
+		rate:   limit.Rate,
+		burst:  float64(limit.Burst),
+		tokens: float64(limit.Burst),
+	}
+}
+
+// Wait blocks until a request may be sent. It returns ctx.Err() as soon as
+// the context is done, handing the reserved token back.
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	start := time.Now()
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		recordSpan(TimingSpan{Name: SpanRateLimitWait}, time.Since(start), 0)
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// installRateLimit gates every request attempt, retries included, on the
+// limiter. Only request starts are throttled; a streaming body that is
+// already being read is never paused.
+func installRateLimit(client *resty.Client, limiter *rateLimiter) {
+	if limiter == nil {
+		return
+	}
+	client.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		return limiter.Wait(req.Context())
+	})
+}
@@ -0,0 +1,155 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	cases := []struct {
+		spec  string
+		want  RateLimit
+		valid bool
+	}{
+		{spec: "", want: RateLimit{}, valid: true},
+		{spec: "off", want: RateLimit{}, valid: true},
+		{spec: "10/s", want: RateLimit{Rate: 10, Burst: 10}, valid: true},
+		{spec: " 2.5/S ", want: RateLimit{Rate: 2.5, Burst: 3}, valid: true},
+		{spec: "30/m", want: RateLimit{Rate: 0.5, Burst: 1}, valid: true},
+		{spec: "10/s,burst=2", want: RateLimit{Rate: 10, Burst: 2}, valid: true},
+		{spec: "10", valid: false},
+		{spec: "-1/s", valid: false},
+		{spec: "10/h", valid: false},
+		{spec: "10/s,burst=0", valid: false},
+		{spec: "10/s,max=3", valid: false},
+	}
+	for _, tc := range cases {
+		got, err := ParseRateLimit(tc.spec)
+		if tc.valid != (err == nil) {
+			t.Fatalf("ParseRateLimit(%q) error = %v, want valid=%v", tc.spec, err, tc.valid)
+		}
+		if tc.valid && got != tc.want {
+			t.Fatalf("ParseRateLimit(%q) = %+v, want %+v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+func newLimitedClient(t *testing.T, limit RateLimit, handler http.HandlerFunc) *resty.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := resty.New().SetBaseURL(server.URL)
+	installRateLimit(client, newRateLimiter(limit))
+	return client
+}
+
+func TestRateLimitSpacesRequests(t *testing.T) {
+	client := newLimitedClient(t, RateLimit{Rate: 20, Burst: 1}, func(w http.ResponseWriter, r *http.Request) {})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := client.R().Get("/api/json"); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+	// The first request spends the burst; the other five wait 50ms each.
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Fatalf("6 requests at 20/s finished in %s, want at least 250ms", elapsed)
+	}
+}
+
+func TestRateLimitSharedAcrossGoroutines(t *testing.T) {
+	client := newLimitedClient(t, RateLimit{Rate: 40, Burst: 2}, func(w http.ResponseWriter, r *http.Request) {})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.R().Get("/api/json"); err != nil {
+				t.Errorf("request: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	// Two requests ride the burst; the remaining eight are spaced 25ms apart.
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("10 concurrent requests at 40/s finished in %s, want at least 200ms", elapsed)
+	}
+}
+
+func TestRateLimitAppliesToRetries(t *testing.T) {
+	var attempts atomic.Int32
+	client := newLimitedClient(t, RateLimit{Rate: 10, Burst: 1}, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	client.SetRetryCount(2).SetRetryWaitTime(time.Millisecond).SetRetryMaxWaitTime(time.Millisecond)
+	client.AddRetryCondition(func(resp *resty.Response, err error) bool {
+		return resp != nil && resp.StatusCode() == http.StatusServiceUnavailable
+	})
+
+	start := time.Now()
+	resp, err := client.R().Get("/api/json")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK || attempts.Load() != 3 {
+		t.Fatalf("expected success on the third attempt, got %d after %d attempts", resp.StatusCode(), attempts.Load())
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Fatalf("3 attempts at 10/s finished in %s, want at least 200ms", elapsed)
+	}
+}
+
+func TestRateLimitReturnsOnCancel(t *testing.T) {
+	client := newLimitedClient(t, RateLimit{Rate: 1.0 / 60, Burst: 1}, func(w http.ResponseWriter, r *http.Request) {})
+
+	if _, err := client.R().Get("/api/json"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.R().SetContext(ctx).Get("/api/json")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("cancelled request waited %s for the limiter", elapsed)
+	}
+}
+
+func TestRateLimitRecordsWaitSpan(t *testing.T) {
+	resetTimings(t)
+	EnableTimings()
+
+	client := newLimitedClient(t, RateLimit{Rate: 50, Burst: 1}, func(w http.ResponseWriter, r *http.Request) {})
+	for i := 0; i < 3; i++ {
+		if _, err := client.R().Get("/api/json"); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+
+	for _, span := range TimingsSnapshot() {
+		if span.Name == SpanRateLimitWait {
+			if span.Count != 2 {
+				t.Fatalf("expected 2 throttled requests, got %+v", span)
+			}
+			return
+		}
+	}
+	t.Fatalf("no %s span recorded: %+v", SpanRateLimitWait, TimingsSnapshot())
+}
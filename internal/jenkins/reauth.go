@@ -0,0 +1,94 @@
+package jenkins
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/avivsinai/jenkins-cli/internal/log"
+)
+
+// ReauthFunc obtains fresh credentials after Jenkins rejects the current ones.
+// username is the configured user; implementations may return a different
+// one. An error leaves the original 401 response in place.
+type ReauthFunc func(ctx context.Context, contextName, username string) (newUsername, token string, err error)
+
+type reauthState struct {
+	fn        ReauthFunc
+	attempted bool
+	// generation counts successful renewals so a request that failed with
+	// credentials replaced since it was sent retries without prompting.
+	generation int
+}
+
+// SetReauth installs the hook consulted when a request fails with 401. The
+// hook runs at most once per client; capability probes and crumb fetches
+// never trigger it.
+func (c *Client) SetReauth(fn ReauthFunc) {
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+	c.reauth.fn = fn
+}
+
+func (c *Client) authGeneration() (int, bool) {
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+	return c.reauth.generation, c.reauth.fn != nil
+}
+
+// renewCredentials reports whether a request sent under generation should be
+// retried.
+func (c *Client) renewCredentials(ctx context.Context, generation int) bool {
+	c.reauthMu.Lock()
+	defer c.reauthMu.Unlock()
+
+	if c.reauth.generation != generation {
+		return true
+	}
+	if c.reauth.fn == nil || c.reauth.attempted {
+		return false
+	}
+	c.reauth.attempted = true
+
+	username := ""
+	if c.ctxConfig != nil {
+		username = c.ctxConfig.Username
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	newUsername, token, err := c.reauth.fn(ctx, c.contextName, username)
+	if err != nil {
+		log.L().Debug().Err(err).Msg("re-authentication skipped")
+		return false
+	}
+
+	c.resty.SetBasicAuth(newUsername, token)
+	if c.restyStream != nil {
+		c.restyStream.SetBasicAuth(newUsername, token)
+	}
+	if c.ctxConfig != nil {
+		c.ctxConfig.Username = newUsername
+	}
+	c.reauth.generation++
+	c.clearCrumb()
+	return true
+}
+
+// canReplay reports whether the request body survives being sent twice.
+func canReplay(req *resty.Request) bool {
+	_, streamed := req.Body.(io.Reader)
+	return !streamed
+}
+
+func isUnauthorized(resp *resty.Response) bool {
+	return resp != nil && resp.StatusCode() == http.StatusUnauthorized
+}
+
+func discardBody(resp *resty.Response) {
+	if body := resp.RawBody(); body != nil {
+		_ = body.Close()
+	}
+}
@@ -0,0 +1,101 @@
+package jenkins
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+)
+
+// newReauthStub accepts only alice:fresh and answers 401 to everything else,
+// including the capability probes.
+func newReauthStub(t *testing.T) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"number":1}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Client{
+		resty:       resty.New().SetBaseURL(server.URL).SetBasicAuth("alice", "stale"),
+		contextName: "test",
+		ctxConfig:   &config.Context{Username: "alice"},
+	}
+}
+
+func countingReauth(calls *int32, token string) ReauthFunc {
+	return func(_ context.Context, contextName, username string) (string, string, error) {
+		atomic.AddInt32(calls, 1)
+		return username, token, nil
+	}
+}
+
+func TestReauthRetriesOnceWithRenewedToken(t *testing.T) {
+	client := newReauthStub(t)
+	var calls int32
+	var gotContext, gotUser string
+	client.SetReauth(func(ctx context.Context, contextName, username string) (string, string, error) {
+		atomic.AddInt32(&calls, 1)
+		gotContext, gotUser = contextName, username
+		return username, "fresh", nil
+	})
+
+	var payload conditionalPayload
+	resp, err := client.Do(client.NewRequest(), http.MethodGet, "/job/app/api/json", &payload)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if resp.StatusCode() != http.StatusOK || payload.Number != 1 {
+		t.Fatalf("expected renewed request to succeed, got %d %+v", resp.StatusCode(), payload)
+	}
+	if calls != 1 || gotContext != "test" || gotUser != "alice" {
+		t.Fatalf("expected one prompt for test/alice, got %d for %s/%s", calls, gotContext, gotUser)
+	}
+
+	if _, err := client.Do(client.NewRequest(), http.MethodGet, "/job/app/api/json", &payload); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected renewed credentials to be reused, got %d prompts", calls)
+	}
+}
+
+func TestReauthIsAttemptedOnlyOnce(t *testing.T) {
+	client := newReauthStub(t)
+	var calls int32
+	client.SetReauth(countingReauth(&calls, "still-wrong"))
+
+	for i := 0; i < 3; i++ {
+		resp, err := client.Do(client.NewRequest(), http.MethodGet, "/job/app/api/json", nil)
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+		if resp.StatusCode() != http.StatusUnauthorized {
+			t.Fatalf("expected 401 to surface, got %d", resp.StatusCode())
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single reauth attempt, got %d", calls)
+	}
+}
+
+func TestReauthSkipsCapabilityProbes(t *testing.T) {
+	client := newReauthStub(t)
+	var calls int32
+	client.SetReauth(countingReauth(&calls, "fresh"))
+
+	_ = client.refreshCapabilities(context.Background())
+	if calls != 0 {
+		t.Fatalf("capability probes must not trigger reauth, got %d prompts", calls)
+	}
+}
@@ -0,0 +1,54 @@
+package jenkins
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/avivsinai/jenkins-cli/internal/log"
+)
+
+const redactedHeaderValue = "[REDACTED]"
+
+// alwaysRedactedHeaders carry credentials whatever the context configures.
+var alwaysRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// installRequestLog logs each outgoing request with its headers at trace
+// level (JK_LOG=trace). Credentials and the context's extra headers are
+// logged by name only.
+func installRequestLog(client *resty.Client, extraHeaders []string) {
+	sensitive := make(map[string]bool, len(alwaysRedactedHeaders)+len(extraHeaders))
+	for _, name := range append(append([]string{}, alwaysRedactedHeaders...), extraHeaders...) {
+		sensitive[http.CanonicalHeaderKey(name)] = true
+	}
+	client.SetPreRequestHook(func(_ *resty.Client, req *http.Request) error {
+		logger := log.L()
+		if logger.GetLevel() > zerolog.TraceLevel {
+			return nil
+		}
+		logger.Trace().
+			Str("method", req.Method).
+			Str("url", req.URL.Redacted()).
+			Strs("headers", redactHeaders(req.Header, sensitive)).
+			Msg("http request")
+		return nil
+	})
+}
+
+// redactHeaders renders headers as sorted "Name: value" lines, replacing the
+// values of sensitive headers.
+func redactHeaders(header http.Header, sensitive map[string]bool) []string {
+	lines := make([]string, 0, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if sensitive[http.CanonicalHeaderKey(name)] {
+			value = redactedHeaderValue
+		}
+		lines = append(lines, name+": "+value)
+	}
+	sort.Strings(lines)
+	return lines
+}
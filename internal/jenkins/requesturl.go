@@ -0,0 +1,109 @@
+package jenkins
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+)
+
+// sensitiveQueryParams are substrings of query parameter names whose values
+// never appear in messages; remote trigger tokens travel as ?token=.
+var sensitiveQueryParams = []string{"token", "secret", "password", "crumb"}
+
+// RequestError is a transport failure annotated with the request that hit it.
+type RequestError struct {
+	Method string
+	// URL is redacted; see RedactURL.
+	URL string
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	cause := e.Err
+	// url.Error repeats the unredacted URL; keep only what went wrong.
+	var urlErr *url.Error
+	if errors.As(cause, &urlErr) {
+		cause = urlErr.Err
+	}
+	return fmt.Sprintf("%s %s: %v", e.Method, e.URL, cause)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// RedactURL drops user info from raw and masks the values of query
+// parameters whose names look like credentials. Parameter order is kept.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	u.User = nil
+	if u.RawQuery != "" {
+		pairs := strings.Split(u.RawQuery, "&")
+		for i, pair := range pairs {
+			name, _, hasValue := strings.Cut(pair, "=")
+			decoded, err := url.QueryUnescape(name)
+			if err != nil {
+				decoded = name
+			}
+			if hasValue && isSensitiveParam(decoded) {
+				pairs[i] = name + "=" + filter.RedactedValue
+			}
+		}
+		u.RawQuery = strings.Join(pairs, "&")
+	}
+	return u.String()
+}
+
+func isSensitiveParam(name string) bool {
+	lower := strings.ToLower(name)
+	for _, keyword := range sensitiveQueryParams {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResponseRequest reports the method and redacted URL that produced resp.
+func ResponseRequest(resp *resty.Response) (method, requestURL string, ok bool) {
+	if resp == nil {
+		return "", "", false
+	}
+	if raw := resp.RawResponse; raw != nil && raw.Request != nil && raw.Request.URL != nil {
+		return raw.Request.Method, RedactURL(raw.Request.URL.String()), true
+	}
+	if resp.Request != nil && resp.Request.URL != "" {
+		return resp.Request.Method, RedactURL(resp.Request.URL), true
+	}
+	return "", "", false
+}
+
+// requestURL is the URL req was sent to. Middleware that fails before resty
+// resolves the URL (the rate limiter, for one) leaves req.URL relative, so the
+// base URL and query are applied here.
+func (c *Client) requestURL(req *resty.Request, path string) string {
+	target := req.URL
+	if target == "" {
+		target = path
+	}
+	if u, err := url.Parse(target); err == nil && u.IsAbs() {
+		return target
+	}
+	target = strings.TrimSuffix(c.resty.BaseURL, "/") + "/" + strings.TrimPrefix(target, "/")
+	if query := req.QueryParam.Encode(); query != "" {
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + query
+	}
+	return target
+}
@@ -0,0 +1,178 @@
+package jenkins
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// Stable span names emitted by the timing collector. Keep these unchanged so
+// downstream exporters can rely on them.
+const (
+	SpanConfigLoad      = "config.load"
+	SpanSecretOpen      = "secret.open"
+	SpanCapabilityProbe = "capability.probe"
+	SpanCrumbFetch      = "crumb.fetch"
+	SpanHTTPRequest     = "http.request"
+	SpanRateLimitWait   = "ratelimit.wait"
+)
+
+// TimingSpan aggregates every occurrence of a span with the same name, method,
+// and path. Repeated polls (log streaming, follow loops) collapse into a
+// single entry with a count instead of one span per request.
+type TimingSpan struct {
+	Name    string `json:"name"`
+	Method  string `json:"method,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Status  int    `json:"status,omitempty"`
+	Count   int    `json:"count"`
+	TotalMs int64  `json:"totalMs"`
+	MaxMs   int64  `json:"maxMs"`
+	Bytes   int64  `json:"bytes,omitempty"`
+}
+
+type timingCollector struct {
+	mu       sync.Mutex
+	enabled  bool
+	reported bool
+	started  time.Time
+	order    []string
+	spans    map[string]*TimingSpan
+}
+
+var timings = &timingCollector{spans: make(map[string]*TimingSpan)}
+
+// EnableTimings turns on in-process span collection for the current invocation.
+func EnableTimings() {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+	timings.enabled = true
+	timings.started = time.Now()
+}
+
+// TimingsEnabled reports whether --timings collection is active.
+func TimingsEnabled() bool {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+	return timings.enabled
+}
+
+// StartSpan begins a named phase and returns a function that ends it.
+func StartSpan(name string) func() {
+	if !TimingsEnabled() {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		recordSpan(TimingSpan{Name: name}, time.Since(start), 0)
+	}
+}
+
+// TimingsSnapshot returns the collected spans in first-seen order.
+func TimingsSnapshot() []TimingSpan {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+	out := make([]TimingSpan, 0, len(timings.order))
+	for _, key := range timings.order {
+		out = append(out, *timings.spans[key])
+	}
+	return out
+}
+
+// MarkTimingsReported records that the spans were attached to structured
+// output, so the stderr summary can be skipped.
+func MarkTimingsReported() {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+	timings.reported = true
+}
+
+// TimingsReported reports whether MarkTimingsReported was called.
+func TimingsReported() bool {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+	return timings.reported
+}
+
+// WriteTimings prints a compact, human-readable breakdown of collected spans.
+func WriteTimings(w io.Writer) {
+	spans := TimingsSnapshot()
+	timings.mu.Lock()
+	total := time.Since(timings.started)
+	timings.mu.Unlock()
+
+	_, _ = fmt.Fprintln(w, "Timings:")
+	for _, span := range spans {
+		label := span.Name
+		if span.Method != "" {
+			label = fmt.Sprintf("%s %s %s", span.Name, span.Method, span.Path)
+		}
+		line := fmt.Sprintf("  %-60s %4dx %8s", label, span.Count, time.Duration(span.TotalMs)*time.Millisecond)
+		if span.Count > 1 {
+			line += fmt.Sprintf(" (max %s)", time.Duration(span.MaxMs)*time.Millisecond)
+		}
+		if span.Bytes > 0 {
+			line += fmt.Sprintf(" %d bytes", span.Bytes)
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	_, _ = fmt.Fprintf(w, "  total %s\n", total.Round(time.Millisecond))
+}
+
+func recordSpan(span TimingSpan, elapsed time.Duration, bytes int64) {
+	timings.mu.Lock()
+	defer timings.mu.Unlock()
+	if !timings.enabled {
+		return
+	}
+
+	key := strings.Join([]string{span.Name, span.Method, span.Path}, " ")
+	existing, ok := timings.spans[key]
+	if !ok {
+		existing = &TimingSpan{Name: span.Name, Method: span.Method, Path: span.Path}
+		timings.spans[key] = existing
+		timings.order = append(timings.order, key)
+	}
+
+	ms := elapsed.Milliseconds()
+	existing.Count++
+	existing.TotalMs += ms
+	if ms > existing.MaxMs {
+		existing.MaxMs = ms
+	}
+	existing.Bytes += bytes
+	if span.Status != 0 {
+		existing.Status = span.Status
+	}
+}
+
+// instrumentTimings installs resty middleware recording one span per request.
+func instrumentTimings(client *resty.Client) {
+	client.OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+		if !TimingsEnabled() || resp == nil || resp.Request == nil {
+			return nil
+		}
+		recordSpan(TimingSpan{
+			Name:   SpanHTTPRequest,
+			Method: resp.Request.Method,
+			Path:   requestPath(resp.Request),
+			Status: resp.StatusCode(),
+		}, resp.Time(), resp.Size())
+		return nil
+	})
+}
+
+func requestPath(req *resty.Request) string {
+	if req.RawRequest != nil && req.RawRequest.URL != nil {
+		return req.RawRequest.URL.Path
+	}
+	if parsed, err := url.Parse(req.URL); err == nil {
+		return parsed.Path
+	}
+	return req.URL
+}
@@ -0,0 +1,70 @@
+package jenkins
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func resetTimings(t *testing.T) {
+	t.Helper()
+	timings = &timingCollector{spans: make(map[string]*TimingSpan)}
+	t.Cleanup(func() {
+		timings = &timingCollector{spans: make(map[string]*TimingSpan)}
+	})
+}
+
+func TestTimingsDisabledRecordsNothing(t *testing.T) {
+	resetTimings(t)
+
+	StartSpan(SpanConfigLoad)()
+	if spans := TimingsSnapshot(); len(spans) != 0 {
+		t.Fatalf("expected no spans while disabled, got %d", len(spans))
+	}
+}
+
+func TestTimingsAggregatesRepeatedRequests(t *testing.T) {
+	resetTimings(t)
+	EnableTimings()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+	}))
+	defer server.Close()
+
+	client := resty.New().SetBaseURL(server.URL)
+	instrumentTimings(client)
+
+	StartSpan(SpanConfigLoad)()
+	for i := 0; i < 3; i++ {
+		if _, err := client.R().SetQueryParam("start", "0").Get("/job/demo/1/logText/progressiveText"); err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+	}
+
+	spans := TimingsSnapshot()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 aggregated spans, got %+v", spans)
+	}
+	if spans[0].Name != SpanConfigLoad || spans[0].Count != 1 {
+		t.Fatalf("unexpected phase span %+v", spans[0])
+	}
+
+	poll := spans[1]
+	if poll.Name != SpanHTTPRequest || poll.Method != http.MethodGet || poll.Path != "/job/demo/1/logText/progressiveText" {
+		t.Fatalf("unexpected request span %+v", poll)
+	}
+	if poll.Count != 3 || poll.Status != http.StatusOK || poll.Bytes != 15 {
+		t.Fatalf("expected 3 polls totalling 15 bytes, got %+v", poll)
+	}
+
+	var buf bytes.Buffer
+	WriteTimings(&buf)
+	if !strings.Contains(buf.String(), "http.request GET /job/demo/1/logText/progressiveText") {
+		t.Fatalf("summary missing request span:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,24 @@
+package jenkins
+
+import "sync"
+
+var (
+	insecureOverrideMu sync.Mutex
+	insecureOverride   bool
+)
+
+// SetInsecureOverride makes every client created afterwards skip TLS
+// verification, ignoring the context's insecure and ca_file settings. It backs
+// --insecure-skip-tls-verify and is never written to the config.
+func SetInsecureOverride(enabled bool) {
+	insecureOverrideMu.Lock()
+	defer insecureOverrideMu.Unlock()
+	insecureOverride = enabled
+}
+
+// InsecureOverridden reports whether SetInsecureOverride is in effect.
+func InsecureOverridden() bool {
+	insecureOverrideMu.Lock()
+	defer insecureOverrideMu.Unlock()
+	return insecureOverride
+}
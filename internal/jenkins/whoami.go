@@ -0,0 +1,59 @@
+package jenkins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Identity is the user Jenkins authenticates this client as.
+type Identity struct {
+	// ID is the user id, the value build causes record as userId.
+	ID        string `json:"name"`
+	Anonymous bool   `json:"anonymous"`
+}
+
+// ErrUnauthorized is wrapped by WhoAmI when Jenkins rejects the client's
+// username or token.
+var ErrUnauthorized = errors.New("credentials rejected")
+
+// whoAmICache holds the first successful /whoAmI answer; the identity behind
+// a client does not change while the process runs.
+type whoAmICache struct {
+	mu       sync.Mutex
+	identity *Identity
+}
+
+// WhoAmI returns the authenticated user from /whoAmI, fetching it on first
+// use and caching it for the life of the client. Anonymous sessions are
+// returned, not reported as errors; callers decide whether that is fatal.
+func (c *Client) WhoAmI(ctx context.Context) (Identity, error) {
+	c.whoAmI.mu.Lock()
+	defer c.whoAmI.mu.Unlock()
+	if c.whoAmI.identity != nil {
+		return *c.whoAmI.identity, nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	var identity Identity
+	resp, err := c.Do(c.NewRequest().SetContext(ctx), http.MethodGet, "/whoAmI/api/json", &identity)
+	if err != nil {
+		return Identity{}, err
+	}
+	switch resp.StatusCode() {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return Identity{}, fmt.Errorf("resolve current user: %s: %w", resp.Status(), ErrUnauthorized)
+	default:
+		return Identity{}, fmt.Errorf("resolve current user: %s", resp.Status())
+	}
+	if identity.ID == "" || identity.ID == "anonymous" {
+		identity.Anonymous = true
+	}
+	c.whoAmI.identity = &identity
+	return identity, nil
+}
@@ -1,11 +1,14 @@
 package jkcmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/avivsinai/jenkins-cli/internal/build"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
 	jkfactory "github.com/avivsinai/jenkins-cli/pkg/cmd/factory"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/root"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
@@ -30,19 +33,68 @@ func Main() int {
 		return 1
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	err = rootCmd.Execute()
+	if jenkins.TimingsEnabled() && !jenkins.TimingsReported() {
+		jenkins.WriteTimings(ios.ErrOut)
+	}
+
+	if err != nil {
+		code := 1
+		msg := fmt.Sprintf("Error: %v", err)
 		var exitErr *cmdutil.ExitError
 		if errors.As(err, &exitErr) {
-			if exitErr.Msg != "" {
-				_, _ = fmt.Fprintln(ios.ErrOut, exitErr.Msg)
-			}
-			return exitErr.Code
+			code, msg = exitErr.Code, exitErr.Msg
+		} else if errors.Is(err, cmdutil.ErrSilent) {
+			msg = ""
 		}
-		if err != cmdutil.ErrSilent {
-			_, _ = fmt.Fprintf(ios.ErrOut, "Error: %v\n", err)
+		if jsonOut, _ := rootCmd.PersistentFlags().GetBool("json"); jsonOut && msg != "" {
+			writeJSONError(ios.ErrOut, code, err)
+		} else if msg != "" {
+			_, _ = fmt.Fprintln(ios.ErrOut, msg)
 		}
-		return 1
+		return code
 	}
 
 	return 0
 }
+
+type errorOutput struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    int                  `json:"code"`
+	Message string               `json:"message"`
+	Request *cmdutil.RequestInfo `json:"request,omitempty"`
+	Details any                  `json:"details,omitempty"`
+}
+
+// writeJSONError reports err as a JSON object on stderr so --json callers can
+// parse failures as well as results.
+func writeJSONError(w io.Writer, code int, err error) {
+	detail := errorDetail{Code: code, Message: err.Error(), Request: failedRequest(err)}
+	var exitErr *cmdutil.ExitError
+	if errors.As(err, &exitErr) {
+		detail.Details = exitErr.Details
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(errorOutput{Error: detail})
+}
+
+// failedRequest finds the HTTP request behind err, if any.
+func failedRequest(err error) *cmdutil.RequestInfo {
+	var exitErr *cmdutil.ExitError
+	if errors.As(err, &exitErr) && exitErr.Request != nil {
+		return exitErr.Request
+	}
+	var reqErr *cmdutil.RequestError
+	if errors.As(err, &reqErr) && reqErr.Request != nil {
+		return reqErr.Request
+	}
+	var transportErr *jenkins.RequestError
+	if errors.As(err, &transportErr) {
+		return &cmdutil.RequestInfo{Method: transportErr.Method, URL: transportErr.URL}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package jobindex caches the job paths of each context, so shell widgets and
+// completion can list jobs without walking the controller on every keystroke.
+//
+// Indexes live in the "job-index" directory of the jk cache (see cachefile),
+// one file per context, and are replaced atomically.
+package jobindex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+)
+
+// cacheKind names the cache directory of the indexes.
+const cacheKind = "job-index"
+
+// TTL is how long an index is used before it is rebuilt.
+const TTL = 15 * time.Minute
+
+// Index is the job paths of one context.
+type Index struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	Jobs      []string  `json:"jobs"`
+}
+
+// New builds an index of jobs, sorted.
+func New(jobs []string) Index {
+	sorted := append([]string(nil), jobs...)
+	sort.Strings(sorted)
+	return Index{UpdatedAt: time.Now().UTC(), Jobs: sorted}
+}
+
+// Stale reports whether the index is older than TTL at now.
+func (i Index) Stale(now time.Time) bool {
+	return now.Sub(i.UpdatedAt) > TTL
+}
+
+// Match returns the jobs starting with prefix, at most limit of them when limit
+// is positive. The index is sorted, so the result is too.
+func (i Index) Match(prefix string, limit int) []string {
+	prefix = strings.TrimLeft(prefix, "/")
+	matched := make([]string, 0)
+	for _, job := range i.Jobs {
+		if !strings.HasPrefix(job, prefix) {
+			continue
+		}
+		if limit > 0 && len(matched) == limit {
+			break
+		}
+		matched = append(matched, job)
+	}
+	return matched
+}
+
+// Load returns the index for contextName, or nil when none was built.
+func Load(contextName string) (*Index, error) {
+	data, err := cachefile.Read(cacheKind, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("read job index: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var index Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("decode job index: %w", err)
+	}
+	return &index, nil
+}
+
+// Save replaces the index for contextName atomically.
+func Save(contextName string, index Index) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("encode job index: %w", err)
+	}
+	if err := cachefile.Write(cacheKind, contextName, data); err != nil {
+		return fmt.Errorf("write job index: %w", err)
+	}
+	return nil
+}
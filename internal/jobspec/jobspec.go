@@ -0,0 +1,285 @@
+// Package jobspec converts a small YAML job definition into Jenkins
+// config.xml. It covers the common cases (pipeline from SCM, inline pipeline
+// script, parameters, cron and SCM polling triggers, folders); anything else
+// still needs a hand-written config.xml.
+//
+// A pipeline job:
+//
+//	description: Builds the API
+//	parameters:
+//	  - name: TARGET
+//	    type: choice
+//	    choices: [staging, production]
+//	  - name: DRY_RUN
+//	    type: boolean
+//	    default: true
+//	triggers:
+//	  cron: "H 2 * * *"
+//	  pollSCM: "H/15 * * * *"
+//	pipeline:
+//	  scm:
+//	    url: https://github.com/acme/api.git
+//	    credentialsId: github
+//	    branch: main
+//	  scriptPath: ci/Jenkinsfile
+//
+// An inline script replaces scm and scriptPath with script (and optionally
+// sandbox: false). A folder is `type: folder` with an optional description.
+package jobspec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Job types.
+const (
+	TypePipeline = "pipeline"
+	TypeFolder   = "folder"
+)
+
+// Parameter types.
+const (
+	ParamString  = "string"
+	ParamBoolean = "boolean"
+	ParamChoice  = "choice"
+)
+
+const (
+	defaultBranch     = "main"
+	defaultScriptPath = "Jenkinsfile"
+)
+
+// Spec is a parsed job definition.
+type Spec struct {
+	Type        string      `yaml:"type"`
+	Description string      `yaml:"description"`
+	Parameters  []Parameter `yaml:"parameters"`
+	Triggers    *Triggers   `yaml:"triggers"`
+	Pipeline    *Pipeline   `yaml:"pipeline"`
+}
+
+// Parameter is a build parameter. Default is a string, a boolean, or one of
+// Choices depending on Type.
+type Parameter struct {
+	Name        string    `yaml:"name"`
+	Type        string    `yaml:"type"`
+	Description string    `yaml:"description"`
+	Default     yaml.Node `yaml:"default"`
+	Choices     []string  `yaml:"choices"`
+	Trim        bool      `yaml:"trim"`
+}
+
+// Triggers are the supported build triggers; each value is a cron spec.
+type Triggers struct {
+	Cron    string `yaml:"cron"`
+	PollSCM string `yaml:"pollSCM"`
+}
+
+// Pipeline is either an inline Script or a Jenkinsfile loaded from SCM.
+type Pipeline struct {
+	Script     string `yaml:"script"`
+	Sandbox    *bool  `yaml:"sandbox"`
+	SCM        *SCM   `yaml:"scm"`
+	ScriptPath string `yaml:"scriptPath"`
+}
+
+// SCM is a Git repository.
+type SCM struct {
+	URL           string `yaml:"url"`
+	CredentialsID string `yaml:"credentialsId"`
+	Branch        string `yaml:"branch"`
+}
+
+// Parse decodes and validates a job definition. Unknown keys are errors.
+func Parse(r io.Reader) (*Spec, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var spec Spec
+	if err := decoder.Decode(&spec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("job definition is empty")
+		}
+		return nil, fmt.Errorf("parse job definition: %w", err)
+	}
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// ParseBytes is Parse for an in-memory definition.
+func ParseBytes(data []byte) (*Spec, error) {
+	return Parse(bytes.NewReader(data))
+}
+
+func (s *Spec) validate() error {
+	s.Type = strings.ToLower(strings.TrimSpace(s.Type))
+	switch s.Type {
+	case "":
+		s.Type = TypePipeline
+	case TypePipeline, TypeFolder:
+	default:
+		return fmt.Errorf("type must be %q or %q, got %q", TypePipeline, TypeFolder, s.Type)
+	}
+
+	if s.Type == TypeFolder {
+		if len(s.Parameters) > 0 || s.Triggers != nil || s.Pipeline != nil {
+			return errors.New("a folder takes only a description")
+		}
+		return nil
+	}
+
+	if s.Pipeline == nil {
+		return errors.New("pipeline is required: set pipeline.script or pipeline.scm")
+	}
+	if err := s.Pipeline.validate(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(s.Parameters))
+	for i := range s.Parameters {
+		param := &s.Parameters[i]
+		if err := param.validate(); err != nil {
+			return fmt.Errorf("parameters[%d]: %w", i, err)
+		}
+		if _, dup := seen[param.Name]; dup {
+			return fmt.Errorf("parameters[%d]: duplicate name %q", i, param.Name)
+		}
+		seen[param.Name] = struct{}{}
+	}
+
+	if s.Triggers != nil {
+		s.Triggers.Cron = strings.TrimSpace(s.Triggers.Cron)
+		s.Triggers.PollSCM = strings.TrimSpace(s.Triggers.PollSCM)
+	}
+	return nil
+}
+
+func (p *Pipeline) validate() error {
+	hasScript := strings.TrimSpace(p.Script) != ""
+	switch {
+	case hasScript && p.SCM != nil:
+		return errors.New("pipeline.script and pipeline.scm cannot be combined")
+	case !hasScript && p.SCM == nil:
+		return errors.New("pipeline needs script or scm")
+	case hasScript && p.ScriptPath != "":
+		return errors.New("pipeline.scriptPath applies only with pipeline.scm")
+	case !hasScript && p.Sandbox != nil:
+		return errors.New("pipeline.sandbox applies only with pipeline.script")
+	}
+	if hasScript {
+		return nil
+	}
+
+	p.SCM.URL = strings.TrimSpace(p.SCM.URL)
+	if p.SCM.URL == "" {
+		return errors.New("pipeline.scm.url is required")
+	}
+	p.SCM.Branch = strings.TrimSpace(p.SCM.Branch)
+	if p.SCM.Branch == "" {
+		p.SCM.Branch = defaultBranch
+	}
+	p.ScriptPath = strings.TrimSpace(p.ScriptPath)
+	if p.ScriptPath == "" {
+		p.ScriptPath = defaultScriptPath
+	}
+	return nil
+}
+
+func (p *Parameter) validate() error {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+	if p.Type == "" {
+		p.Type = ParamString
+	}
+	if len(p.Choices) > 0 && p.Type != ParamChoice {
+		return fmt.Errorf("%s: choices apply only to type %q", p.Name, ParamChoice)
+	}
+	if p.Trim && p.Type != ParamString {
+		return fmt.Errorf("%s: trim applies only to type %q", p.Name, ParamString)
+	}
+
+	switch p.Type {
+	case ParamString:
+		if p.hasDefault() && p.Default.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s: default must be a string", p.Name)
+		}
+	case ParamBoolean:
+		if p.hasDefault() {
+			var value bool
+			if err := p.Default.Decode(&value); err != nil {
+				return fmt.Errorf("%s: default must be true or false", p.Name)
+			}
+		}
+	case ParamChoice:
+		if len(p.Choices) == 0 {
+			return fmt.Errorf("%s: choices are required", p.Name)
+		}
+		if p.hasDefault() {
+			if p.Default.Kind != yaml.ScalarNode || indexOf(p.Choices, p.Default.Value) < 0 {
+				return fmt.Errorf("%s: default must be one of the choices", p.Name)
+			}
+		}
+	default:
+		return fmt.Errorf("%s: type must be %s, %s, or %s", p.Name, ParamString, ParamBoolean, ParamChoice)
+	}
+	return nil
+}
+
+// hasDefault reports whether the definition sets a default; a YAML null,
+// written as "default: null", "default: ~", or a bare "default:", does not.
+func (p Parameter) hasDefault() bool {
+	if p.Default.Kind == 0 {
+		return false
+	}
+	return p.Default.Kind != yaml.ScalarNode || p.Default.ShortTag() != "!!null"
+}
+
+// DefaultString is the string parameter default ("" when unset).
+func (p Parameter) DefaultString() string {
+	if !p.hasDefault() || p.Default.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return p.Default.Value
+}
+
+// DefaultBool is the boolean parameter default (false when unset).
+func (p Parameter) DefaultBool() bool {
+	var value bool
+	if p.hasDefault() {
+		_ = p.Default.Decode(&value)
+	}
+	return value
+}
+
+// OrderedChoices lists the choices with the default first, since Jenkins
+// offers the first choice as the default.
+func (p Parameter) OrderedChoices() []string {
+	i := indexOf(p.Choices, p.DefaultString())
+	if i <= 0 {
+		return p.Choices
+	}
+	out := make([]string, 0, len(p.Choices))
+	out = append(out, p.Choices[i])
+	out = append(out, p.Choices[:i]...)
+	return append(out, p.Choices[i+1:]...)
+}
+
+func indexOf(values []string, want string) int {
+	for i, value := range values {
+		if value == want {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,75 @@
+package jobspec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestXMLGolden(t *testing.T) {
+	for _, name := range []string{"scm_minimal", "scm_full", "inline_script", "inline_unsandboxed", "folder"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", name+".yaml"))
+			if err != nil {
+				t.Fatalf("read spec: %v", err)
+			}
+			spec, err := ParseBytes(data)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got, err := spec.XML()
+			if err != nil {
+				t.Fatalf("XML: %v", err)
+			}
+			fakejenkins.AssertFixture(t, name+".xml", got)
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unknown top-level key", "pipeline:\n  script: x\nschedule: daily\n", "field schedule not found"},
+		{"unknown nested key", "pipeline:\n  scm:\n    url: u\n    ref: main\n", "field ref not found"},
+		{"empty", "", "empty"},
+		{"no pipeline", "description: x\n", "pipeline is required"},
+		{"script and scm", "pipeline:\n  script: x\n  scm:\n    url: u\n", "cannot be combined"},
+		{"missing url", "pipeline:\n  scm:\n    branch: main\n", "url is required"},
+		{"folder with pipeline", "type: folder\npipeline:\n  script: x\n", "only a description"},
+		{"unknown type", "type: freestyle\n", "type must be"},
+		{"bad parameter type", "pipeline:\n  script: x\nparameters:\n  - name: A\n    type: password\n", "type must be"},
+		{"choice default", "pipeline:\n  script: x\nparameters:\n  - name: A\n    type: choice\n    choices: [a]\n    default: b\n", "one of the choices"},
+		{"boolean default", "pipeline:\n  script: x\nparameters:\n  - name: A\n    type: boolean\n    default: maybe\n", "true or false"},
+		{"duplicate parameter", "pipeline:\n  script: x\nparameters:\n  - name: A\n  - name: A\n", "duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBytes([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNullDefaultIsUnset(t *testing.T) {
+	spec, err := ParseBytes([]byte("pipeline:\n  script: x\nparameters:\n  - name: A\n    default: null\n  - name: B\n    type: choice\n    choices: [a, b]\n    default:\n  - name: C\n    default: \"null\"\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := spec.Parameters[0].DefaultString(); got != "" {
+		t.Fatalf("null default = %q, want empty", got)
+	}
+	if got := spec.Parameters[1].OrderedChoices(); strings.Join(got, ",") != "a,b" {
+		t.Fatalf("choices = %v", got)
+	}
+	if got := spec.Parameters[2].DefaultString(); got != "null" {
+		t.Fatalf("quoted default = %q, want null", got)
+	}
+}
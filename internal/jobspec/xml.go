@@ -0,0 +1,148 @@
+package jobspec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+var templates = template.Must(template.New("jobspec").Funcs(template.FuncMap{
+	"x":           xmlEscaper.Replace,
+	"branch":      branchSpec,
+	"hasTriggers": func(t *Triggers) bool { return t != nil && (t.Cron != "" || t.PollSCM != "") },
+	"sandbox":     func(v *bool) bool { return v == nil || *v },
+}).Parse(`
+{{- define "folder" -}}
+<?xml version='1.1' encoding='UTF-8'?>
+<com.cloudbees.hudson.plugins.folder.Folder plugin="cloudbees-folder">
+  <description>{{x .Description}}</description>
+  <properties/>
+</com.cloudbees.hudson.plugins.folder.Folder>
+{{end}}
+
+{{- define "pipeline" -}}
+<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job">
+  <description>{{x .Description}}</description>
+  <keepDependencies>false</keepDependencies>
+{{- if or .Parameters (hasTriggers .Triggers)}}
+  <properties>
+{{- if .Parameters}}
+    <hudson.model.ParametersDefinitionProperty>
+      <parameterDefinitions>
+{{- range .Parameters}}{{template "parameter" .}}{{end}}
+      </parameterDefinitions>
+    </hudson.model.ParametersDefinitionProperty>
+{{- end}}
+{{- if hasTriggers .Triggers}}
+    <org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+      <triggers>
+{{- with .Triggers}}
+{{- if .Cron}}
+        <hudson.triggers.TimerTrigger>
+          <spec>{{x .Cron}}</spec>
+        </hudson.triggers.TimerTrigger>
+{{- end}}
+{{- if .PollSCM}}
+        <hudson.triggers.SCMTrigger>
+          <spec>{{x .PollSCM}}</spec>
+          <ignorePostCommitHooks>false</ignorePostCommitHooks>
+        </hudson.triggers.SCMTrigger>
+{{- end}}
+{{- end}}
+      </triggers>
+    </org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+{{- end}}
+  </properties>
+{{- else}}
+  <properties/>
+{{- end}}
+{{- with .Pipeline}}
+{{- if .SCM}}
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition" plugin="workflow-cps">
+    <scm class="hudson.plugins.git.GitSCM" plugin="git">
+      <configVersion>2</configVersion>
+      <userRemoteConfigs>
+        <hudson.plugins.git.UserRemoteConfig>
+          <url>{{x .SCM.URL}}</url>
+{{- if .SCM.CredentialsID}}
+          <credentialsId>{{x .SCM.CredentialsID}}</credentialsId>
+{{- end}}
+        </hudson.plugins.git.UserRemoteConfig>
+      </userRemoteConfigs>
+      <branches>
+        <hudson.plugins.git.BranchSpec>
+          <name>{{x (branch .SCM.Branch)}}</name>
+        </hudson.plugins.git.BranchSpec>
+      </branches>
+      <doGenerateSubmoduleConfigurations>false</doGenerateSubmoduleConfigurations>
+      <submoduleCfg class="empty-list"/>
+      <extensions/>
+    </scm>
+    <scriptPath>{{x .ScriptPath}}</scriptPath>
+    <lightweight>true</lightweight>
+  </definition>
+{{- else}}
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition" plugin="workflow-cps">
+    <script>{{x .Script}}</script>
+    <sandbox>{{sandbox .Sandbox}}</sandbox>
+  </definition>
+{{- end}}
+{{- end}}
+  <triggers/>
+  <disabled>false</disabled>
+</flow-definition>
+{{end}}
+
+{{- define "parameter"}}
+{{- if eq .Type "boolean"}}
+        <hudson.model.BooleanParameterDefinition>
+          <name>{{x .Name}}</name>
+          <description>{{x .Description}}</description>
+          <defaultValue>{{.DefaultBool}}</defaultValue>
+        </hudson.model.BooleanParameterDefinition>
+{{- else if eq .Type "choice"}}
+        <hudson.model.ChoiceParameterDefinition>
+          <name>{{x .Name}}</name>
+          <description>{{x .Description}}</description>
+          <choices class="java.util.Arrays$ArrayList">
+            <a class="string-array">
+{{- range .OrderedChoices}}
+              <string>{{x .}}</string>
+{{- end}}
+            </a>
+          </choices>
+        </hudson.model.ChoiceParameterDefinition>
+{{- else}}
+        <hudson.model.StringParameterDefinition>
+          <name>{{x .Name}}</name>
+          <description>{{x .Description}}</description>
+          <defaultValue>{{x .DefaultString}}</defaultValue>
+          <trim>{{.Trim}}</trim>
+        </hudson.model.StringParameterDefinition>
+{{- end}}
+{{- end}}
+`))
+
+// XML renders the spec as config.xml.
+func (s *Spec) XML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, s.Type, s); err != nil {
+		return nil, fmt.Errorf("render %s config.xml: %w", s.Type, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// xmlEscaper escapes element text. Values are never placed in attributes, so
+// quotes and newlines stay literal and inline scripts remain readable in
+// --print-xml output.
+var xmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// branchSpec qualifies a bare branch name the way the Jenkins UI does.
+func branchSpec(branch string) string {
+	if strings.HasPrefix(branch, "*/") || strings.HasPrefix(branch, "refs/") || strings.Contains(branch, "*") {
+		return branch
+	}
+	return "*/" + branch
+}
@@ -0,0 +1,83 @@
+// Package lastrun remembers the last run each context triggered, so it can be
+// shown, repeated, or cancelled without retyping the command.
+//
+// Records live in the "last" directory of the jk cache (see cachefile), one
+// file per context; shells sharing a context simply overwrite each other, and
+// the last write wins.
+package lastrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+)
+
+// cacheKind names the cache directory of the records.
+const cacheKind = "last"
+
+// Record is the last run triggered in a context.
+type Record struct {
+	// Command is the invocation that triggered the run, e.g. "run start".
+	Command string `json:"command"`
+	JobPath string `json:"jobPath"`
+	// Build is the build number, once known. Runs triggered without
+	// --follow only have a QueueLocation.
+	Build         int64  `json:"build,omitempty"`
+	QueueLocation string `json:"queueLocation,omitempty"`
+	// Parameters holds the values of non-secret parameters. Parameters that
+	// look like secrets are listed by name only in SecretParameters; their
+	// values are never written to disk.
+	Parameters       map[string]string `json:"parameters,omitempty"`
+	SecretParameters []string          `json:"secretParameters,omitempty"`
+	RecordedAt       time.Time         `json:"recordedAt"`
+}
+
+// New builds a record for a run triggered with params, splitting off the
+// parameters redactor considers secrets.
+func New(command, jobPath string, params map[string]string, queueLocation string, redactor *filter.Redactor) Record {
+	record := Record{Command: command, JobPath: jobPath, QueueLocation: queueLocation, RecordedAt: time.Now().UTC()}
+	for name, value := range params {
+		if redactor.IsSecret(name) {
+			record.SecretParameters = append(record.SecretParameters, name)
+			continue
+		}
+		if record.Parameters == nil {
+			record.Parameters = make(map[string]string)
+		}
+		record.Parameters[name] = value
+	}
+	sort.Strings(record.SecretParameters)
+	return record
+}
+
+// Load returns the record for contextName, or nil when nothing was recorded.
+func Load(contextName string) (*Record, error) {
+	data, err := cachefile.Read(cacheKind, contextName)
+	if err != nil {
+		return nil, fmt.Errorf("read last run: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("decode last run: %w", err)
+	}
+	return &record, nil
+}
+
+// Save replaces the record for contextName atomically.
+func Save(contextName string, record Record) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode last run: %w", err)
+	}
+	if err := cachefile.Write(cacheKind, contextName, data); err != nil {
+		return fmt.Errorf("write last run: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package prometheus
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	input := `# HELP jenkins_executor_count_value Executors
+# TYPE jenkins_executor_count_value gauge
+jenkins_executor_count_value 10.0
+
+default_jenkins_executors_busy{label="linux && docker",node="a\"b\\c"} 4 1700000000000
+up NaN
+`
+	samples, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(samples) != 3 {
+		t.Fatalf("expected 3 samples, got %d: %+v", len(samples), samples)
+	}
+
+	if samples[0].Name != "jenkins_executor_count_value" || samples[0].Value != 10 || len(samples[0].Labels) != 0 {
+		t.Fatalf("unexpected first sample %+v", samples[0])
+	}
+	busy := samples[1]
+	if busy.Name != "default_jenkins_executors_busy" || busy.Value != 4 {
+		t.Fatalf("unexpected labelled sample %+v", busy)
+	}
+	if busy.Labels["label"] != "linux && docker" || busy.Labels["node"] != `a"b\c` {
+		t.Fatalf("unexpected labels %+v", busy.Labels)
+	}
+	if !math.IsNaN(samples[2].Value) {
+		t.Fatalf("expected NaN, got %v", samples[2].Value)
+	}
+}
+
+func TestParseRejectsMalformedLines(t *testing.T) {
+	for _, input := range []string{
+		"metric",
+		`metric{label="open} 1`,
+		`metric{label=unquoted} 1`,
+		"metric one",
+	} {
+		if _, err := Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("expected error for %q", input)
+		}
+	}
+}
+
+func TestMatchesName(t *testing.T) {
+	if !MatchesName("default_jenkins_executors_busy", "jenkins_executors_busy") {
+		t.Fatal("expected namespaced metric to match")
+	}
+	if !MatchesName("jenkins_executors_busy", "jenkins_executors_busy") {
+		t.Fatal("expected bare metric to match")
+	}
+	if MatchesName("default_jenkins_executors_busy_total", "jenkins_executors_busy") {
+		t.Fatal("unexpected match on a longer metric")
+	}
+}
@@ -20,6 +20,16 @@ const (
 	envFileDir       = "KEYRING_FILE_DIR"
 )
 
+// promptsDisabled is set by --no-input so the file backend never falls back to
+// an interactive passphrase prompt.
+var promptsDisabled bool
+
+// DisablePrompts makes the encrypted file backend fail instead of prompting
+// for a passphrase when none is configured.
+func DisablePrompts() {
+	promptsDisabled = true
+}
+
 // Store wraps the OS keyring integration.
 type Store struct {
 	kr keyring.Keyring
@@ -112,6 +122,21 @@ func Open(opts ...Option) (*Store, error) {
 	return &Store{kr: kr}, nil
 }
 
+// NativeAvailable reports whether an OS keyring backend can be opened
+// without the encrypted file fallback, honoring KEYRING_BACKEND.
+func NativeAvailable() bool {
+	backends := resolveAllowedBackends(openOptions{})
+	if len(backends) == 0 {
+		return false
+	}
+	_, err := keyring.Open(keyring.Config{ServiceName: serviceName, AllowedBackends: backends})
+	return err == nil
+}
+
+// PassphraseEnv names the environment variable that unlocks the encrypted
+// file backend without a prompt.
+const PassphraseEnv = envPassphrase
+
 // Set writes a secret value.
 func (s *Store) Set(key, value string) error {
 	if s == nil || s.kr == nil {
@@ -160,6 +185,12 @@ func TokenKey(contextName string) string {
 	return fmt.Sprintf("context/%s/token", contextName)
 }
 
+// HeaderKey returns the keyring identifier for an extra header value written
+// as "secret:<key>" in a context.
+func HeaderKey(contextName, key string) string {
+	return fmt.Sprintf("context/%s/header/%s", contextName, key)
+}
+
 // IsNoKeyringError reports whether the provided error indicates that no native
 // keyring backend is available on the host. Callers can use this to decide when
 // to fall back to the encrypted file backend for backwards compatibility.
@@ -244,9 +275,14 @@ func configureFileBackend(cfg *keyring.Config, opts openOptions) error {
 		}
 	}
 
-	if passphrase != "" {
+	switch {
+	case passphrase != "":
 		cfg.FilePasswordFunc = keyring.FixedStringPrompt(passphrase)
-	} else {
+	case promptsDisabled:
+		cfg.FilePasswordFunc = func(string) (string, error) {
+			return "", fmt.Errorf("keyring passphrase required; set %s or remove --no-input", envPassphrase)
+		}
+	default:
 		cfg.FilePasswordFunc = keyring.TerminalPrompt
 	}
 
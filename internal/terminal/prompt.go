@@ -1,42 +1,80 @@
 package terminal
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
 	"golang.org/x/term"
 )
 
-// Prompt requests a value from stdin.
-func Prompt(label string, defaultValue string) (string, error) {
-	reader := bufio.NewReader(os.Stdin)
+// Prompt requests a value from in, writing the label to out.
+func Prompt(in io.Reader, out io.Writer, label string, defaultValue string) (string, error) {
 	if defaultValue != "" {
-		_, _ = fmt.Fprintf(os.Stdout, "%s [%s]: ", label, defaultValue)
+		_, _ = fmt.Fprintf(out, "%s [%s]: ", label, defaultValue)
 	} else {
-		_, _ = fmt.Fprintf(os.Stdout, "%s: ", label)
+		_, _ = fmt.Fprintf(out, "%s: ", label)
 	}
 
-	input, err := reader.ReadString('\n')
+	input, err := readLine(in)
 	if err != nil {
 		return "", err
 	}
 
-	input = strings.TrimSpace(input)
 	if input == "" {
 		return defaultValue, nil
 	}
 	return input, nil
 }
 
-// PromptSecret reads a sensitive value without echoing input.
-func PromptSecret(label string) (string, error) {
-	_, _ = fmt.Fprintf(os.Stdout, "%s: ", label)
-	data, err := term.ReadPassword(int(os.Stdin.Fd()))
-	_, _ = fmt.Fprintln(os.Stdout)
+// PromptSecret reads a sensitive value without echoing input when in is a
+// terminal, falling back to a plain line read otherwise.
+func PromptSecret(in io.Reader, out io.Writer, label string) (string, error) {
+	_, _ = fmt.Fprintf(out, "%s: ", label)
+	if file, ok := in.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		data, err := term.ReadPassword(int(file.Fd()))
+		_, _ = fmt.Fprintln(out)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return readLine(in)
+}
+
+// Confirm asks a yes/no question; anything other than y/yes is a no.
+func Confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, err := readLine(in)
 	if err != nil {
-		return "", err
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
+
+// readLine reads up to and including the next newline one byte at a time,
+// so a buffered read cannot swallow the answers to later prompts when stdin
+// is a pipe.
+func readLine(in io.Reader) (string, error) {
+	var line strings.Builder
+	buf := make([]byte, 1)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line.WriteByte(buf[0])
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) && line.Len() > 0 {
+				break
+			}
+			return "", err
+		}
 	}
-	return strings.TrimSpace(string(data)), nil
+	return strings.TrimSpace(line.String()), nil
 }
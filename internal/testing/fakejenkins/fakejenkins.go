@@ -0,0 +1,290 @@
+// Package fakejenkins is an httptest-backed Jenkins controller for command
+// tests. It serves canned responses per route and records every request so
+// tests can assert on the exact method, path, query, headers, and body a
+// command sends.
+package fakejenkins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+// UpdateFixturesEnv rewrites golden fixtures instead of comparing against them.
+const UpdateFixturesEnv = "JK_UPDATE_FIXTURES"
+
+// Request is a recorded inbound request.
+type Request struct {
+	Method string
+	// Path is the escaped request path, so encoding mistakes stay visible.
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Form parses the recorded body as application/x-www-form-urlencoded.
+func (r Request) Form() url.Values {
+	values, _ := url.ParseQuery(string(r.Body))
+	return values
+}
+
+// JSON decodes the recorded body into v.
+func (r Request) JSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Multipart parses the recorded body as multipart/form-data, keeping file
+// parts in memory.
+func (r Request) Multipart() (*multipart.Form, error) {
+	_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	return multipart.NewReader(bytes.NewReader(r.Body), params["boundary"]).ReadForm(int64(len(r.Body)) + 1)
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// Server routes requests by method and escaped path. Unknown routes, the
+// crumb issuer, and capability probes answer 404 unless configured.
+type Server struct {
+	t        *testing.T
+	mu       sync.Mutex
+	routes   map[string]response
+	requests []Request
+	// contextPath, when set, is required on every request and stripped
+	// before routing and recording.
+	contextPath string
+}
+
+// New returns an empty fake controller.
+func New(t *testing.T) *Server {
+	t.Helper()
+	return &Server{t: t, routes: make(map[string]response)}
+}
+
+// NewClient returns a fake controller together with a real client bound to it.
+func NewClient(t *testing.T) (*Server, *jenkins.Client) {
+	t.Helper()
+	s := New(t)
+	return s, jenkinstest.NewClient(t, s)
+}
+
+// NewClientAt is NewClient for a controller served under contextPath (for
+// example "/jenkins"). Routes and recorded paths omit the context path;
+// requests that lack it always answer 404.
+func NewClientAt(t *testing.T, contextPath string) (*Server, *jenkins.Client) {
+	t.Helper()
+	s := New(t)
+	s.contextPath = strings.TrimSuffix(contextPath, "/")
+	return s, jenkinstest.NewClientAt(t, s, s.contextPath)
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// Handle serves body with status for method and path. The path is matched
+// against the escaped request path, e.g. "/job/a%20b/api/json".
+func (s *Server) Handle(method, path string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	header := http.Header{}
+	if body != "" {
+		header.Set("Content-Type", "application/json")
+	}
+	s.routes[routeKey(method, path)] = response{status: status, header: header, body: []byte(body)}
+}
+
+// HandleJSON serves v encoded as JSON with a 200 status.
+func (s *Server) HandleJSON(method, path string, v any) {
+	s.t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.t.Fatalf("encode %s %s: %v", method, path, err)
+	}
+	s.Handle(method, path, http.StatusOK, string(data))
+}
+
+// HandleResponse serves body with status and exactly the given headers.
+func (s *Server) HandleResponse(method, path string, status int, header http.Header, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[routeKey(method, path)] = response{status: status, header: header.Clone(), body: []byte(body)}
+}
+
+// HandleHTML serves body as text/html with status, the way Jenkins renders
+// error pages.
+func (s *Server) HandleHTML(method, path string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	header := http.Header{"Content-Type": {"text/html;charset=utf-8"}}
+	s.routes[routeKey(method, path)] = response{status: status, header: header, body: []byte(body)}
+}
+
+// HandleFixture serves testdata/<name> with a 200 status.
+func (s *Server) HandleFixture(method, path, name string) {
+	s.t.Helper()
+	s.Handle(method, path, http.StatusOK, string(Fixture(s.t, name)))
+}
+
+// HandleHeaders serves an empty response with the given status and headers,
+// e.g. the Location header Jenkins returns when queueing a build.
+func (s *Server) HandleHeaders(method, path string, status int, header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[routeKey(method, path)] = response{status: status, header: header.Clone()}
+}
+
+// EnableCrumb makes the crumb issuer hand out field=value.
+func (s *Server) EnableCrumb(field, value string) {
+	s.HandleJSON(http.MethodGet, "/crumbIssuer/api/json", map[string]string{
+		"crumbRequestField": field,
+		"crumb":             value,
+	})
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := r.URL.EscapedPath()
+	underContext := true
+	if s.contextPath != "" {
+		stripped := strings.TrimPrefix(path, s.contextPath)
+		underContext = stripped != path && (stripped == "" || strings.HasPrefix(stripped, "/"))
+		if underContext {
+			path = stripped
+		}
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	resp, ok := s.routes[routeKey(r.Method, path)]
+	s.mu.Unlock()
+	ok = ok && underContext
+
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	for key, values := range resp.header {
+		for _, v := range values {
+			w.Header().Add(key, v)
+		}
+	}
+	w.WriteHeader(resp.status)
+	_, _ = w.Write(resp.body)
+}
+
+// Requests returns every recorded request in arrival order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the recorded requests for method and escaped path.
+func (s *Server) RequestsTo(method, path string) []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Request
+	for _, req := range s.requests {
+		if req.Method == strings.ToUpper(method) && req.Path == path {
+			out = append(out, req)
+		}
+	}
+	return out
+}
+
+// LastRequest returns the most recent request for method and path, failing
+// the test when there is none.
+func (s *Server) LastRequest(method, path string) Request {
+	s.t.Helper()
+	reqs := s.RequestsTo(method, path)
+	if len(reqs) == 0 {
+		s.t.Fatalf("no %s %s request recorded; saw %v", method, path, s.paths())
+	}
+	return reqs[len(reqs)-1]
+}
+
+func (s *Server) paths() []string {
+	reqs := s.Requests()
+	out := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		out = append(out, req.Method+" "+req.Path)
+	}
+	return out
+}
+
+// Factory returns a command factory whose Jenkins client is client, with
+// stdout and stderr captured in the returned buffers.
+func Factory(client *jenkins.Client) (*cmdutil.Factory, *bytes.Buffer, *bytes.Buffer) {
+	ios, _, stdout, stderr := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: ios,
+		Config: func() (*config.Config, error) {
+			return &config.Config{Contexts: map[string]*config.Context{}}, nil
+		},
+		JenkinsClient: func(context.Context, string) (*jenkins.Client, error) {
+			return client, nil
+		},
+	}
+	return f, stdout, stderr
+}
+
+// Fixture reads testdata/<name> relative to the calling package.
+func Fixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return data
+}
+
+// AssertFixture compares got with testdata/<name>. With JK_UPDATE_FIXTURES=1
+// the fixture is (re)recorded from got instead.
+func AssertFixture(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if os.Getenv(UpdateFixturesEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("create fixture dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("record fixture: %v", err)
+		}
+		return
+	}
+	want := Fixture(t, name)
+	if !bytes.Equal(want, got) {
+		t.Fatalf("%s mismatch (rerun with %s=1 to record)\nwant:\n%s\ngot:\n%s", path, UpdateFixturesEnv, want, got)
+	}
+}
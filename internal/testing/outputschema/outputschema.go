@@ -0,0 +1,158 @@
+// Package outputschema checks JSON output structs against the time
+// convention documented in pkg/cmd/shared: instants are RFC3339 strings and
+// durations are integer milliseconds in fields ending in "Ms".
+package outputschema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// instantSuffixes mark JSON names that hold a point in time.
+var instantSuffixes = []string{"time", "timestamp", "since", "date", "epoch"}
+
+// durationWords mark JSON names that hold a span of time.
+var durationWords = []string{"duration", "elapsed", "latency", "wait"}
+
+// TimeFieldViolations walks the JSON fields reachable from v (a value or a
+// reflect.Type) and describes each that breaks the convention: time.Duration
+// or time.Time fields, numeric instants such as epoch milliseconds, numeric
+// durations without an Ms suffix, and non-integer Ms fields. JSON names in
+// allowed, such as deprecated fields kept for compatibility, are skipped.
+func TimeFieldViolations(v any, allowed ...string) []string {
+	t, ok := v.(reflect.Type)
+	if !ok {
+		t = reflect.TypeOf(v)
+	}
+	skip := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		skip[name] = true
+	}
+	w := walker{skip: skip, seen: map[reflect.Type]bool{}}
+	w.walk(t, t.String())
+	return w.violations
+}
+
+type walker struct {
+	skip       map[string]bool
+	seen       map[reflect.Type]bool
+	violations []string
+}
+
+func (w *walker) walk(t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		if t.Kind() != reflect.Pointer {
+			path += "[]"
+		}
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType || w.seen[t] {
+		return
+	}
+	w.seen[t] = true
+	defer delete(w.seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// encoding/json flattens embedded structs even when their type is
+		// unexported.
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, inline := jsonName(field)
+		if name == "-" {
+			continue
+		}
+		if inline {
+			w.walk(field.Type, path)
+			continue
+		}
+		fieldPath := path + "." + name
+		if !w.skip[name] {
+			if problem := checkField(name, field.Type); problem != "" {
+				w.violations = append(w.violations, fmt.Sprintf("%s: %s", fieldPath, problem))
+			}
+		}
+		w.walk(field.Type, fieldPath)
+	}
+}
+
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		if field.Anonymous {
+			return "", true
+		}
+		return field.Name, false
+	}
+	return name, false
+}
+
+func checkField(name string, t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case durationType:
+		return "time.Duration; use integer milliseconds in a field ending in Ms"
+	case timeType:
+		return "time.Time; use an RFC3339 UTC string"
+	}
+
+	lower := strings.ToLower(name)
+	hasMs := strings.HasSuffix(name, "Ms")
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if hasMs {
+			return ""
+		}
+		if isInstant(name, lower) {
+			return "numeric instant; use an RFC3339 UTC string"
+		}
+		if isDuration(lower) {
+			return "numeric duration without an Ms suffix"
+		}
+	case reflect.Float32, reflect.Float64:
+		if hasMs || isDuration(lower) || isInstant(name, lower) {
+			return "floating-point time value; use integer milliseconds or an RFC3339 string"
+		}
+	case reflect.String:
+		if hasMs {
+			return "Ms field is a string; use integer milliseconds"
+		}
+		if isDuration(lower) {
+			return "duration as a string; use integer milliseconds in a field ending in Ms"
+		}
+	}
+	return ""
+}
+
+func isInstant(name, lower string) bool {
+	if strings.HasSuffix(name, "At") {
+		return true
+	}
+	for _, suffix := range instantSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func isDuration(lower string) bool {
+	for _, word := range durationWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package outputschema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type conforming struct {
+	StartTime  string  `json:"startTime"`
+	QueuedAt   string  `json:"queuedAt,omitempty"`
+	DurationMs int64   `json:"durationMs"`
+	Count      int     `json:"count"`
+	Nested     *nested `json:"nested,omitempty"`
+	Skipped    int64   `json:"-"`
+	internal   time.Duration
+}
+
+type nested struct {
+	Children []nested `json:"children"`
+	WaitMs   int64    `json:"waitMs"`
+}
+
+type embedded struct {
+	Elapsed time.Duration `json:"elapsed"`
+}
+
+type violating struct {
+	embedded
+	Timestamp    int64     `json:"timestamp"`
+	InQueueSince int64     `json:"inQueueSince"`
+	Created      time.Time `json:"createdAt"`
+	Duration     string    `json:"duration"`
+	Seconds      []struct {
+		Duration float64 `json:"duration"`
+	} `json:"cases"`
+	LatencyMs string `json:"latencyMs"`
+}
+
+func TestTimeFieldViolations(t *testing.T) {
+	if got := TimeFieldViolations(conforming{internal: time.Second}); len(got) != 0 {
+		t.Fatalf("expected no violations, got %v", got)
+	}
+
+	got := TimeFieldViolations(reflect.TypeOf(&violating{}))
+	want := []string{
+		"*outputschema.violating.elapsed: time.Duration; use integer milliseconds in a field ending in Ms",
+		"*outputschema.violating.timestamp: numeric instant; use an RFC3339 UTC string",
+		"*outputschema.violating.inQueueSince: numeric instant; use an RFC3339 UTC string",
+		"*outputschema.violating.createdAt: time.Time; use an RFC3339 UTC string",
+		"*outputschema.violating.duration: duration as a string; use integer milliseconds in a field ending in Ms",
+		"*outputschema.violating.cases[].duration: floating-point time value; use integer milliseconds or an RFC3339 string",
+		"*outputschema.violating.latencyMs: Ms field is a string; use integer milliseconds",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected violations:\n%v", got)
+	}
+
+	if got := TimeFieldViolations(violating{}, "elapsed", "timestamp", "inQueueSince", "createdAt", "duration", "latencyMs"); len(got) != 0 {
+		t.Fatalf("expected allowed fields to be skipped, got %v", got)
+	}
+}
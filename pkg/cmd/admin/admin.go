@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// defaultMaxConfigJobs caps how many config.xml files one snapshot or
+// comparison fetches.
+const defaultMaxConfigJobs = 500
+
+func NewCmdAdmin(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Controller administration helpers",
+	}
+
+	cmd.AddCommand(
+		newAdminAuditConfigCmd(f),
+		newAdminSnapshotConfigCmd(f),
+		newAdminPutFileCmd(f),
+		newAdminListFilesCmd(f),
+	)
+	return cmd
+}
+
+// configScope selects the jobs whose config.xml is fetched.
+type configScope struct {
+	Folder  string
+	MaxJobs int
+}
+
+func addConfigScopeFlags(cmd *cobra.Command, scope *configScope) {
+	cmd.Flags().StringVar(&scope.Folder, "folder", "", "Only jobs under this folder")
+	cmdutil.SetJobPathFlag(cmd, "folder")
+	cmd.Flags().IntVar(&scope.MaxJobs, "max-jobs", defaultMaxConfigJobs, "Fetch at most this many job configs")
+}
+
+func (s configScope) validate() error {
+	if s.MaxJobs < 1 {
+		return shared.NewExitError(2, "--max-jobs must be at least 1")
+	}
+	return nil
+}
+
+// listScopedJobs returns the jobs under scope.Folder, sorted, and keeps the
+// first scope.MaxJobs of them; truncated reports that some were dropped.
+func listScopedJobs(ctx context.Context, client shared.Doer, scope configScope) (jobs []string, truncated bool, err error) {
+	jobs, err = runcmd.DiscoverJobs(ctx, client, scope.Folder)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(jobs) > scope.MaxJobs {
+		return jobs[:scope.MaxJobs], true, nil
+	}
+	return jobs, false, nil
+}
+
+// fetchJobConfigs fetches config.xml of every job with bounded concurrency.
+// Failures are recorded in result and the job is left out of the map.
+func fetchJobConfigs(ctx context.Context, client shared.Doer, jobs []string, concurrency int, result *shared.Result) map[string][]byte {
+	type fetched struct {
+		config []byte
+		err    error
+	}
+	results := make([]fetched, len(jobs))
+	shared.ForEach(len(jobs), concurrency, func(i int) {
+		config, err := shared.FetchJobConfig(ctx, client, jobs[i])
+		results[i] = fetched{config: config, err: err}
+	})
+
+	configs := make(map[string][]byte, len(jobs))
+	for i, job := range jobs {
+		if results[i].err != nil {
+			result.Fail(job, results[i].err)
+			continue
+		}
+		configs[job] = results[i].config
+		result.Succeed()
+	}
+	return configs
+}
+
+// inFolder reports whether jobPath is below folder; every job is below the
+// empty folder.
+func inFolder(folder, jobPath string) bool {
+	return folder == "" || strings.HasPrefix(jobPath, folder+"/")
+}
@@ -0,0 +1,140 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/outputschema"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const (
+	folderClass = "com.cloudbees.hudson.plugins.folder.Folder"
+	jobClass    = "hudson.model.FreeStyleProject"
+)
+
+func runAdmin(t *testing.T, client *jenkins.Client, args ...string) (*bytes.Buffer, *bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.AddCommand(NewCmdAdmin(f))
+	root.SetArgs(append([]string{"admin"}, args...))
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	err := root.Execute()
+	return stdout, stderr, err
+}
+
+func requireExitCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, code, exitErr.Code)
+}
+
+func handleJobs(server *fakejenkins.Server, path string, jobs ...map[string]string) {
+	server.HandleJSON(http.MethodGet, path, map[string]any{"jobs": jobs})
+}
+
+func TestAuditConfigComparesWithSnapshot(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	handleJobs(server, "/api/json",
+		map[string]string{"name": "team", "_class": folderClass},
+		map[string]string{"name": "lint", "_class": jobClass})
+	handleJobs(server, "/job/team/api/json",
+		map[string]string{"name": "app", "_class": jobClass},
+		map[string]string{"name": "api", "_class": jobClass})
+	appConfig := "<project>\n  <description>app</description>\n  <disabled>false</disabled>\n</project>\n"
+	server.Handle(http.MethodGet, "/job/team/job/app/config.xml", http.StatusOK, appConfig)
+	server.Handle(http.MethodGet, "/job/team/job/api/config.xml", http.StatusOK, "<project/>\n")
+	server.Handle(http.MethodGet, "/job/lint/config.xml", http.StatusOK, "<project/>\n")
+
+	_, _, err := runAdmin(t, client, "audit-config")
+	requireExitCode(t, err, 3)
+
+	stdout, _, err := runAdmin(t, client, "snapshot-config")
+	require.NoError(t, err)
+	require.Equal(t, "Saved config snapshot of 3 job(s) for context test\n", stdout.String())
+
+	handleJobs(server, "/api/json", map[string]string{"name": "team", "_class": folderClass})
+	handleJobs(server, "/job/team/api/json",
+		map[string]string{"name": "app", "_class": jobClass},
+		map[string]string{"name": "api", "_class": jobClass},
+		map[string]string{"name": "web", "_class": jobClass})
+	server.Handle(http.MethodGet, "/job/team/job/app/config.xml", http.StatusOK, strings.Replace(appConfig, "false", "true", 1))
+	server.Handle(http.MethodGet, "/job/team/job/web/config.xml", http.StatusOK, "<project/>\n")
+
+	stdout, _, err = runAdmin(t, client, "audit-config", "--json")
+	require.NoError(t, err)
+	var output configAuditOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+	require.Equal(t, changeSourceSnapshot, output.Source)
+	require.NotNil(t, output.SnapshotTakenAt)
+	require.Equal(t, []configChange{
+		{Source: changeSourceSnapshot, Kind: "job", Name: "lint", Operation: "deleted"},
+		{Source: changeSourceSnapshot, Kind: "job", Name: "team/app", Operation: "changed"},
+		{Source: changeSourceSnapshot, Kind: "job", Name: "team/web", Operation: "created"},
+	}, output.Changes)
+	require.Equal(t, 3, output.Summary.Succeeded)
+
+	stdout, _, err = runAdmin(t, client, "audit-config", "--diff", "team/app")
+	require.NoError(t, err)
+	require.Equal(t, `--- a/team/app/config.xml
++++ b/team/app/config.xml
+@@ -1,4 +1,4 @@
+ <project>
+   <description>app</description>
+-  <disabled>false</disabled>
++  <disabled>true</disabled>
+ </project>
+`, stdout.String())
+
+	_, _, err = runAdmin(t, client, "audit-config", "--diff", "team/web")
+	requireExitCode(t, err, 3)
+}
+
+func TestAuditConfigUsesJobConfigHistory(t *testing.T) {
+	history := map[string]string{
+		"jobs": `{"jobConfigHistory":[
+			{"job":"team/app","date":"2099-01-02_03-04-05","operation":"Changed","user":"Jane Doe","userID":"jane"},
+			{"job":"other/app","date":"2099-01-02_03-04-05","operation":"Changed","userID":"joe"},
+			{"job":"team/old","date":"2000-01-01_00-00-00","operation":"Created","userID":"joe"}]}`,
+		"system": `{"jobConfigHistory":[{"job":"config","date":"2099-01-03_00-00-00","operation":"Changed","userID":"admin"}]}`,
+	}
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := history[r.URL.Query().Get("filter")]
+		if r.URL.Path != configHistoryPath || !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+
+	stdout, stderr, err := runAdmin(t, client, "audit-config", "--folder", "team")
+	require.NoError(t, err)
+	require.Equal(t, "2099-01-03T00:00:00Z\tsystem\tconfig\tchanged\tadmin\n"+
+		"2099-01-02T03:04:05Z\tjob\tteam/app\tchanged\tJane Doe\n", stdout.String())
+	require.Contains(t, stderr.String(), "warning: node config history unavailable: 404 Not Found")
+}
+
+func TestOutputTimeFieldConventions(t *testing.T) {
+	for _, output := range []any{configAuditOutput{}, configDiffOutput{}, configSnapshotOutput{}, putFileOutput{}, listFilesOutput{}} {
+		require.Empty(t, outputschema.TimeFieldViolations(output), "%T", output)
+	}
+}
@@ -0,0 +1,374 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/configsnap"
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// Where a reported config change was found.
+const (
+	changeSourcePlugin   = "plugin"
+	changeSourceSnapshot = "snapshot-diff"
+)
+
+const (
+	configHistoryPath = "/jobConfigHistory/api/json"
+	// configHistoryDateLayout is how Job Config History stamps its entries,
+	// in the controller's time zone.
+	configHistoryDateLayout = "2006-01-02_15-04-05"
+)
+
+// configHistoryFilters are the Job Config History listings queried, with the
+// kind of configuration each covers. The first one doubles as the probe.
+var configHistoryFilters = []struct{ Filter, Kind string }{
+	{Filter: "jobs", Kind: "job"},
+	{Filter: "system", Kind: "system"},
+	{Filter: "nodes", Kind: "node"},
+}
+
+type configAuditOutput struct {
+	SchemaVersion string `json:"schemaVersion"`
+	// Source is where the changes came from: plugin or snapshot-diff.
+	Source string `json:"source"`
+	// Since is the start of the window, for the plugin source.
+	Since string `json:"since,omitempty"`
+	// SnapshotTakenAt is when the compared snapshot was taken, for the
+	// snapshot-diff source.
+	SnapshotTakenAt string         `json:"snapshotTakenAt,omitempty"`
+	Changes         []configChange `json:"changes"`
+	shared.Result
+}
+
+type configChange struct {
+	Source string `json:"source"`
+	// Kind is job, system, or node.
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Operation is the plugin's operation in lower case (changed, created,
+	// deleted, renamed, ...), or changed, created, or deleted for snapshot
+	// comparisons.
+	Operation string `json:"operation"`
+	// Author and Time are only known to the plugin.
+	Author string `json:"author,omitempty"`
+	Time   string `json:"time,omitempty"`
+}
+
+type configDiffOutput struct {
+	SchemaVersion   string `json:"schemaVersion"`
+	JobPath         string `json:"jobPath"`
+	Source          string `json:"source"`
+	SnapshotTakenAt string `json:"snapshotTakenAt"`
+	Changed         bool   `json:"changed"`
+	Diff            string `json:"diff,omitempty"`
+}
+
+type configHistoryPayload struct {
+	Entries []configHistoryEntry `json:"jobConfigHistory"`
+}
+
+type configHistoryEntry struct {
+	Job       string `json:"job"`
+	Date      string `json:"date"`
+	Operation string `json:"operation"`
+	User      string `json:"user"`
+	UserID    string `json:"userID"`
+}
+
+func newAdminAuditConfigCmd(f *cmdutil.Factory) *cobra.Command {
+	var since string
+	var diffJob string
+	var scope configScope
+	var okOnPartial bool
+
+	cmd := &cobra.Command{
+		Use:   "audit-config",
+		Short: "Report recent configuration changes on the controller",
+		Long: `List what was reconfigured on the controller recently.
+
+When the Job Config History plugin answers, its job, system, and node
+history within --since is listed with author, time, and operation. Otherwise
+each job's current config.xml is compared with the snapshot taken by
+jk admin snapshot-config and the jobs whose config differs are listed;
+--since does not apply there. Comparisons fetch at most --max-jobs configs,
+a few at a time.
+
+--diff prints a unified diff of one job's config.xml against the snapshot.`,
+		Example: `  jk admin audit-config --since 7d
+  jk admin audit-config --folder releases --json
+  jk admin audit-config --diff releases/helm/Deploy`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := scope.validate(); err != nil {
+				return err
+			}
+			scope.Folder = jobpath.Normalize(scope.Folder)
+			window, err := filter.ParseDuration(since)
+			if err != nil || window <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid --since value %q (want a duration such as 7d or 12h)", since))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			ctx := shared.CommandContext(cmd)
+
+			if strings.TrimSpace(diffJob) != "" {
+				return runConfigDiff(cmd, client, diffJob)
+			}
+
+			cutoff := client.ServerNow().Add(-window).UTC().Truncate(time.Second)
+			result := shared.NewResult()
+			changes, found, err := fetchConfigHistory(ctx, client, cutoff, scope.Folder, &result)
+			if err != nil {
+				return err
+			}
+
+			output := configAuditOutput{SchemaVersion: "1.0", Source: changeSourcePlugin, Since: shared.FormatTime(cutoff), Changes: changes}
+			if !found {
+				snapshot, err := loadSnapshot(client.ContextName())
+				if err != nil {
+					return err
+				}
+				takenAt, snapshotChanges, err := compareWithSnapshot(ctx, client, snapshot, scope, shared.Concurrency(f), &result)
+				if err != nil {
+					return err
+				}
+				if cmd.Flags().Changed("since") {
+					result.Warn("", 0, "--since needs the Job Config History plugin; compared with the config snapshot instead")
+				}
+				output = configAuditOutput{SchemaVersion: "1.0", Source: changeSourceSnapshot, SnapshotTakenAt: shared.FormatTime(takenAt), Changes: snapshotChanges}
+			}
+			output.Result = result
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				renderConfigChanges(cmd, output)
+				return nil
+			}); err != nil {
+				return err
+			}
+			output.WriteIssues(cmd)
+			return output.ExitError("jobs", okOnPartial)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "7d", "With Job Config History, only changes within this duration")
+	cmd.Flags().StringVar(&diffJob, "diff", "", "Print a unified diff of this job's config.xml against the snapshot")
+	addConfigScopeFlags(cmd, &scope)
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		3: "No config snapshot to compare with, or --diff names a job outside it",
+	})
+	return cmd
+}
+
+// fetchConfigHistory lists Job Config History entries since cutoff, job
+// entries only under folder. found is false when the plugin is not installed
+// or not readable; listings after the first that fail are reported as
+// warnings, since older plugin versions lack some of them.
+func fetchConfigHistory(ctx context.Context, client shared.Doer, cutoff time.Time, folder string, result *shared.Result) ([]configChange, bool, error) {
+	changes := []configChange{}
+	for i, source := range configHistoryFilters {
+		var payload configHistoryPayload
+		resp, err := client.Do(client.NewRequest().SetContext(ctx).SetQueryParam("filter", source.Filter), http.MethodGet, configHistoryPath, &payload)
+		if err != nil {
+			return nil, false, err
+		}
+		if status := resp.StatusCode(); status != http.StatusOK {
+			if i > 0 {
+				result.Warn(source.Kind, 0, fmt.Sprintf("%s config history unavailable: %s", source.Kind, shared.ResponseStatus(resp)))
+				continue
+			}
+			switch status {
+			case http.StatusNotFound:
+				return nil, false, nil
+			case http.StatusForbidden:
+				result.Warn("", 5, "no permission to read Job Config History; compared with the config snapshot instead")
+				return nil, false, nil
+			}
+			return nil, false, shared.CheckResponse(resp, "config history")
+		}
+
+		for _, entry := range payload.Entries {
+			when, dated := parseConfigHistoryDate(entry.Date)
+			if dated && when.Before(cutoff) {
+				continue
+			}
+			if source.Kind == "job" && !inFolder(folder, entry.Job) {
+				continue
+			}
+			change := configChange{
+				Source:    changeSourcePlugin,
+				Kind:      source.Kind,
+				Name:      entry.Job,
+				Operation: strings.ToLower(strings.TrimSpace(entry.Operation)),
+				Author:    firstNonEmpty(entry.User, entry.UserID),
+			}
+			if dated {
+				change.Time = shared.FormatTime(when)
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		// RFC3339 UTC times order like the instants they name.
+		a, b := changes[i].Time, changes[j].Time
+		if a == "" || b == "" {
+			return a != ""
+		}
+		return a > b
+	})
+	return changes, true, nil
+}
+
+// parseConfigHistoryDate reads an entry's timestamp. The plugin does not say
+// which time zone it uses, so it is taken as UTC.
+func parseConfigHistoryDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{configHistoryDateLayout, time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// compareWithSnapshot fetches the jobs in scope (the snapshot's folder when
+// scope has none) and reports those whose config.xml checksum differs from
+// the snapshot, were created since, or no longer exist.
+func compareWithSnapshot(ctx context.Context, client shared.Doer, snapshot *configsnap.Snapshot, scope configScope, concurrency int, result *shared.Result) (time.Time, []configChange, error) {
+	if scope.Folder == "" {
+		scope.Folder = snapshot.Folder
+	}
+
+	jobs, truncated, err := listScopedJobs(ctx, client, scope)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	configs := fetchJobConfigs(ctx, client, jobs, concurrency, result)
+
+	changes := []configChange{}
+	untracked := 0
+	for _, job := range jobs {
+		config, ok := configs[job]
+		if !ok {
+			continue
+		}
+		before, known := snapshot.Jobs[job]
+		switch {
+		case !known && (snapshot.Truncated || !inFolder(snapshot.Folder, job)):
+			untracked++
+		case !known:
+			changes = append(changes, configChange{Source: changeSourceSnapshot, Kind: "job", Name: job, Operation: "created"})
+		case before.Checksum != configsnap.Checksum(config):
+			changes = append(changes, configChange{Source: changeSourceSnapshot, Kind: "job", Name: job, Operation: "changed"})
+		}
+	}
+	if !truncated {
+		listed := make(map[string]bool, len(jobs))
+		for _, job := range jobs {
+			listed[job] = true
+		}
+		for job := range snapshot.Jobs {
+			if inFolder(scope.Folder, job) && !listed[job] {
+				changes = append(changes, configChange{Source: changeSourceSnapshot, Kind: "job", Name: job, Operation: "deleted"})
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Name < changes[j].Name })
+
+	if truncated {
+		result.Warn("", 0, fmt.Sprintf("compared only the first %d jobs; use --max-jobs or --folder", scope.MaxJobs))
+	}
+	if untracked > 0 {
+		result.Warn("", 0, fmt.Sprintf("%d job(s) are not in the config snapshot; run `jk admin snapshot-config` to track them", untracked))
+	}
+	return snapshot.TakenAt, changes, nil
+}
+
+func runConfigDiff(cmd *cobra.Command, client *jenkins.Client, arg string) error {
+	snapshot, err := loadSnapshot(client.ContextName())
+	if err != nil {
+		return err
+	}
+	jobPath := jobpath.Normalize(arg)
+	before, ok := snapshot.Jobs[jobPath]
+	if !ok {
+		return shared.NewExitError(3, fmt.Sprintf("job %s is not in the config snapshot of context %s", jobPath, client.ContextName()))
+	}
+	config, err := shared.FetchJobConfig(shared.CommandContext(cmd), client, jobPath)
+	if err != nil {
+		return err
+	}
+
+	diff := unifiedDiff([]byte(before.Config), config, jobPath+"/config.xml")
+	output := configDiffOutput{
+		SchemaVersion:   "1.0",
+		JobPath:         jobPath,
+		Source:          changeSourceSnapshot,
+		SnapshotTakenAt: shared.FormatTime(snapshot.TakenAt),
+		Changed:         diff != "",
+		Diff:            diff,
+	}
+	return shared.PrintOutput(cmd, output, func() error {
+		w := cmd.OutOrStdout()
+		if diff == "" {
+			_, _ = fmt.Fprintf(w, "%s: config.xml unchanged since the snapshot of %s\n", jobPath, shared.FormatTime(snapshot.TakenAt))
+			return nil
+		}
+		_, _ = fmt.Fprint(w, diff)
+		return nil
+	})
+}
+
+func loadSnapshot(contextName string) (*configsnap.Snapshot, error) {
+	snapshot, err := configsnap.Load(contextName)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, shared.NewExitError(3, fmt.Sprintf("no config snapshot for context %s; run `jk admin snapshot-config` first", contextName))
+	}
+	return snapshot, nil
+}
+
+func renderConfigChanges(cmd *cobra.Command, output configAuditOutput) {
+	w := cmd.OutOrStdout()
+	if output.Source == changeSourceSnapshot {
+		_, _ = fmt.Fprintf(w, "Compared with the config snapshot of %s\n", output.SnapshotTakenAt)
+	}
+	if len(output.Changes) == 0 {
+		_, _ = fmt.Fprintln(w, "No config changes found")
+		return
+	}
+	for _, change := range output.Changes {
+		if output.Source == changeSourceSnapshot {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", change.Operation, change.Name)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", firstNonEmpty(change.Time, "-"), change.Kind, change.Name, change.Operation, firstNonEmpty(change.Author, "-"))
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
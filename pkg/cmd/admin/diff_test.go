@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func numberedLines(n int, edit map[int]string) []byte {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		line, ok := edit[i]
+		if !ok {
+			line = fmt.Sprintf("line %d", i)
+		}
+		if line != "" {
+			b.WriteString(line + "\n")
+		}
+	}
+	return []byte(b.String())
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []byte
+		after  []byte
+		want   string
+	}{
+		{
+			name:   "equal",
+			before: numberedLines(5, nil),
+			after:  numberedLines(5, nil),
+		},
+		{
+			name:   "separate hunks",
+			before: numberedLines(20, nil),
+			after:  numberedLines(20, map[int]string{2: "line two", 18: ""}),
+			want: `--- a/job/config.xml
++++ b/job/config.xml
+@@ -1,5 +1,5 @@
+ line 1
+-line 2
++line two
+ line 3
+ line 4
+ line 5
+@@ -15,6 +15,5 @@
+ line 15
+ line 16
+ line 17
+-line 18
+ line 19
+ line 20
+`,
+		},
+		{
+			name:   "insertion into empty file",
+			before: nil,
+			after:  []byte("<project/>\n"),
+			want: `--- a/job/config.xml
++++ b/job/config.xml
+@@ -0,0 +1,1 @@
++<project/>
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, unifiedDiff(tt.before, tt.after, "job/config.xml"))
+		})
+	}
+}
@@ -0,0 +1,372 @@
+package admin
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const (
+	// maxUserContentBytes caps put-file uploads. The file travels base64
+	// encoded and then URL encoded inside a script console form, so typical
+	// files of this size stay below maxFormContentBytes; the encoded form is
+	// still checked, since content heavy in "+" and "/" grows further.
+	maxUserContentBytes = 128 << 10
+	// maxFormContentBytes is Jetty's default limit on a form body, which the
+	// controller rejects beyond.
+	maxFormContentBytes = 200000
+
+	scriptConsolePath = "/scriptText"
+	userContentPath   = "/userContent"
+
+	// Markers the put-file script prints so its outcome can be told apart
+	// from a Groovy error, which the script console also answers with 200.
+	putFileOKMarker     = "JK-PUT-OK "
+	putFileExistsMarker = "JK-PUT-EXISTS"
+
+	// maxScriptErrorLength bounds the script output quoted in an error.
+	maxScriptErrorLength = 200
+)
+
+// userContentNamePattern keeps remote names to plain relative paths, so they
+// can be spliced into the Groovy script and URLs without escaping.
+var userContentNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+
+// putFileScript writes the decoded content below JENKINS_HOME/userContent,
+// refusing names that resolve outside it. The %s verbs are the remote name,
+// whether to overwrite, and the base64 content, in that order.
+const putFileScript = `def root = new File(jenkins.model.Jenkins.get().rootDir, 'userContent').canonicalFile
+def target = new File(root, '%s').canonicalFile
+if (!target.path.startsWith(root.path + File.separator)) { throw new IllegalArgumentException('name resolves outside userContent') }
+def existed = target.exists()
+if (existed && !%s) { println('` + putFileExistsMarker + `'); return }
+target.parentFile.mkdirs()
+target.bytes = '%s'.decodeBase64()
+println('` + putFileOKMarker + `' + target.length() + ' ' + existed)
+`
+
+type putFileOutput struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Name          string `json:"name"`
+	Bytes         int64  `json:"bytes"`
+	SHA256        string `json:"sha256"`
+	URL           string `json:"url"`
+	// Replaced reports that an existing file was overwritten.
+	Replaced bool `json:"replaced,omitempty"`
+}
+
+type userContentEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type listFilesOutput struct {
+	SchemaVersion string             `json:"schemaVersion"`
+	Dir           string             `json:"dir"`
+	Entries       []userContentEntry `json:"entries"`
+}
+
+func newAdminPutFileCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		overwrite bool
+		assumeYes bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "put-file <localPath> [remoteName]",
+		Short: "Publish a small file under userContent (requires admin)",
+		Long: `Upload a local file into the controller's userContent directory, where anyone
+who can read Jenkins fetches it from <url>/userContent/<remoteName>.
+
+There is no REST endpoint for writing userContent, so the file is sent through
+the script console (/scriptText) as a short Groovy script. That needs the
+Overall/Administer permission, and the command asks for confirmation unless
+--yes. Files over 128 KiB, or whose encoded form would exceed the 200000
+bytes Jetty accepts, are refused before anything is sent, an existing file
+is kept unless --overwrite, and the content is never printed or logged.
+
+remoteName defaults to the local file name and may contain subdirectories,
+using letters, digits, ".", "_", and "-".`,
+		Example: `  # Share a rollout plan
+  jk admin put-file ./rollout-plan.md
+
+  # Replace a report in a subdirectory, without prompting
+  jk admin put-file ./report.html reports/nightly.html --overwrite --yes`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := filepath.Base(args[0])
+			if len(args) == 2 {
+				name = args[1]
+			}
+			name, err := validateUserContentName(name)
+			if err != nil {
+				return err
+			}
+			data, err := readUserContentFile(args[0])
+			if err != nil {
+				return err
+			}
+			if size := len(putFileForm(name, data, overwrite).Encode()); size > maxFormContentBytes {
+				return shared.NewExitError(2, fmt.Sprintf("%s is too large for the script console once encoded (%d of %d form bytes)", args[0], size, maxFormContentBytes))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			if !assumeYes {
+				ios, err := f.Streams()
+				if err != nil {
+					return err
+				}
+				if !ios.GetNeverPrompt() && !ios.IsStdinTTY() {
+					return shared.NewExitError(2, "confirmation required when stdin is not a TTY (use --yes)")
+				}
+				question := fmt.Sprintf("Write %s (%d bytes) to userContent on %s through the script console?", name, len(data), client.Context().URL)
+				ok, err := cmdutil.ConfirmOrFail(ios, question, "--yes")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					_ = shared.PrintAction(cmd, shared.ActionResult{Action: "put-file", Target: name, Status: shared.ActionDeclined}, "Cancelled")
+					return cmdutil.ErrSilent
+				}
+			}
+
+			written, replaced, err := putUserContentFile(client, name, data, overwrite)
+			if err != nil {
+				return err
+			}
+			sum := sha256.Sum256(data)
+			output := putFileOutput{
+				SchemaVersion: "1.0",
+				Name:          name,
+				Bytes:         written,
+				SHA256:        hex.EncodeToString(sum[:]),
+				URL:           userContentURL(client.Context().URL, name),
+				Replaced:      replaced,
+			}
+			return shared.PrintOutput(cmd, output, func() error {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Uploaded %s (%d bytes)\n%s\n", name, output.Bytes, output.URL)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&overwrite, "overwrite", false, "Replace an existing file with the same name")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Do not prompt for confirmation")
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		2: "Invalid name, file too large, file exists without --overwrite, or confirmation required",
+		5: "The token lacks Overall/Administer (script console access)",
+	})
+	return cmd
+}
+
+func newAdminListFilesCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ls-files [dir]",
+		Short: "List files under userContent",
+		Long: `List the files and directories under the controller's userContent directory,
+or one of its subdirectories, from the plain-text directory listing. Reading
+userContent needs only Overall/Read.`,
+		Example: `  jk admin ls-files
+  jk admin ls-files reports --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := ""
+			if len(args) == 1 && strings.Trim(args[0], "/ ") != "" {
+				var err error
+				if dir, err = validateUserContentName(args[0]); err != nil {
+					return err
+				}
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			entries, err := listUserContent(client, client.Context().URL, dir)
+			if err != nil {
+				return err
+			}
+
+			output := listFilesOutput{SchemaVersion: "1.0", Dir: dir, Entries: entries}
+			return shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				if len(entries) == 0 {
+					_, _ = fmt.Fprintln(w, "No files")
+					return nil
+				}
+				for _, entry := range entries {
+					name := entry.Name
+					if entry.Type == "dir" {
+						name += "/"
+					}
+					_, _ = fmt.Fprintln(w, name)
+				}
+				return nil
+			})
+		},
+	}
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		3: "The directory does not exist under userContent",
+	})
+	return cmd
+}
+
+// validateUserContentName trims surrounding slashes and rejects names that
+// are not plain relative paths.
+func validateUserContentName(name string) (string, error) {
+	trimmed := strings.Trim(strings.TrimSpace(name), "/")
+	if !userContentNamePattern.MatchString(trimmed) {
+		return "", shared.NewExitError(2, fmt.Sprintf("invalid userContent name %q: use letters, digits, '.', '_', '-', and '/'", name))
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment == "." || segment == ".." {
+			return "", shared.NewExitError(2, fmt.Sprintf("invalid userContent name %q: '.' and '..' are not allowed", name))
+		}
+	}
+	return trimmed, nil
+}
+
+func readUserContentFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, shared.NewExitError(2, fmt.Sprintf("%s does not exist", path))
+		}
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxUserContentBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(data) > maxUserContentBytes {
+		return nil, shared.NewExitError(2, fmt.Sprintf("%s is larger than the %d KiB limit for userContent uploads", path, maxUserContentBytes>>10))
+	}
+	return data, nil
+}
+
+// putFileForm is the script console form that writes data to name.
+func putFileForm(name string, data []byte, overwrite bool) url.Values {
+	script := fmt.Sprintf(putFileScript, name, strconv.FormatBool(overwrite), base64.StdEncoding.EncodeToString(data))
+	return url.Values{"script": {script}}
+}
+
+// putUserContentFile runs the put-file script and returns the size the
+// controller reports for the written file and whether it replaced one.
+func putUserContentFile(client shared.Doer, name string, data []byte, overwrite bool) (int64, bool, error) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	resp, err := client.Do(client.NewRequest().SetFormDataFromValues(putFileForm(name, data, overwrite)), http.MethodPost, scriptConsolePath, nil)
+	if err != nil {
+		return 0, false, err
+	}
+	if err := shared.CheckResponse(resp, "script console (requires Overall/Administer)"); err != nil {
+		return 0, false, err
+	}
+
+	output := resp.String()
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == putFileExistsMarker:
+			return 0, false, shared.NewExitError(2, fmt.Sprintf("userContent/%s already exists; pass --overwrite to replace it", name))
+		case strings.HasPrefix(line, putFileOKMarker):
+			sizeText, replacedText, _ := strings.Cut(strings.TrimPrefix(line, putFileOKMarker), " ")
+			size, err := strconv.ParseInt(sizeText, 10, 64)
+			if err != nil {
+				return 0, false, fmt.Errorf("unexpected script console output %q", line)
+			}
+			return size, replacedText == "true", nil
+		}
+	}
+	return 0, false, fmt.Errorf("script console did not write %s: %s", name, scriptFailure(output, encoded))
+}
+
+// scriptFailure quotes the first line of a failed script's output, skipping
+// lines that echo the encoded content back (Groovy compile errors quote the
+// offending source line).
+func scriptFailure(output, encoded string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || (encoded != "" && strings.Contains(line, encoded[:min(len(encoded), 32)])) {
+			continue
+		}
+		if len(line) > maxScriptErrorLength {
+			line = line[:maxScriptErrorLength] + "…"
+		}
+		return line
+	}
+	return "no output"
+}
+
+// listUserContent reads the plain directory listing of dir, in which
+// directories carry a trailing slash.
+func listUserContent(client shared.Doer, baseURL, dir string) ([]userContentEntry, error) {
+	path := userContentPath + "/"
+	if dir != "" {
+		path += escapeUserContentName(dir) + "/"
+	}
+	resp, err := client.Do(client.NewRequest().SetHeader("Accept", "text/plain"), http.MethodGet, path+"*plain*", nil)
+	if err != nil {
+		return nil, err
+	}
+	subject := "userContent"
+	if dir != "" {
+		subject = "userContent/" + dir
+	}
+	if err := shared.CheckResponse(resp, subject); err != nil {
+		return nil, err
+	}
+
+	entries := []userContentEntry{}
+	for _, line := range strings.Split(resp.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		entry := userContentEntry{Name: strings.TrimSuffix(line, "/"), Type: "file"}
+		if strings.HasSuffix(line, "/") {
+			entry.Type = "dir"
+		}
+		full := entry.Name
+		if dir != "" {
+			full = dir + "/" + entry.Name
+		}
+		entry.URL = userContentURL(baseURL, full)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func userContentURL(baseURL, name string) string {
+	return strings.TrimSuffix(baseURL, "/") + userContentPath + "/" + escapeUserContentName(name)
+}
+
+func escapeUserContentName(name string) string {
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
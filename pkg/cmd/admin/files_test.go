@@ -0,0 +1,139 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func writeLocalFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestValidateUserContentName(t *testing.T) {
+	for in, want := range map[string]string{"plan.md": "plan.md", "/reports/nightly.html/": "reports/nightly.html", "a_b-c.1": "a_b-c.1"} {
+		got, err := validateUserContentName(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got)
+	}
+	for _, in := range []string{"", "../secrets", "a/../b", "with space.txt", "it's.txt", `a\b`, "a//b"} {
+		_, err := validateUserContentName(in)
+		requireExitCode(t, err, 2)
+	}
+}
+
+func TestPutFileUploadsThroughScriptConsole(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/scriptText", http.StatusOK, "JK-PUT-OK 11 false\n")
+	local := writeLocalFile(t, "rollout-plan.md", []byte("hello world"))
+
+	stdout, _, err := runAdmin(t, client, "put-file", local, "plans/q4.md", "--yes", "--json")
+	require.NoError(t, err)
+	var output putFileOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+	require.Equal(t, "plans/q4.md", output.Name)
+	require.Equal(t, int64(11), output.Bytes)
+	require.Equal(t, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9", output.SHA256)
+	require.True(t, strings.HasSuffix(output.URL, "/userContent/plans/q4.md"), output.URL)
+	require.False(t, output.Replaced)
+
+	script := server.LastRequest(http.MethodPost, "/scriptText").Form().Get("script")
+	require.Contains(t, script, "new File(root, 'plans/q4.md')")
+	require.Contains(t, script, "!false")
+	require.Contains(t, script, "'"+base64.StdEncoding.EncodeToString([]byte("hello world"))+"'.decodeBase64()")
+}
+
+func TestPutFileRefusesExistingAndLargeFiles(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/scriptText", http.StatusOK, "JK-PUT-EXISTS\n")
+	local := writeLocalFile(t, "plan.md", []byte("v2"))
+
+	_, _, err := runAdmin(t, client, "put-file", local, "--yes")
+	requireExitCode(t, err, 2)
+	require.Contains(t, err.Error(), "pass --overwrite")
+
+	large := writeLocalFile(t, "big.bin", make([]byte, maxUserContentBytes+1))
+	_, _, err = runAdmin(t, client, "put-file", large, "--yes")
+	requireExitCode(t, err, 2)
+	require.Len(t, server.RequestsTo(http.MethodPost, "/scriptText"), 1, "an oversized file must not be sent")
+
+	// Under the size cap, but every base64 character is "/", which URL
+	// encoding triples past the form limit.
+	slashes := writeLocalFile(t, "slashes.bin", bytes.Repeat([]byte{0xff}, 60<<10))
+	_, _, err = runAdmin(t, client, "put-file", slashes, "--yes")
+	requireExitCode(t, err, 2)
+	require.Contains(t, err.Error(), "too large for the script console")
+	require.Len(t, server.RequestsTo(http.MethodPost, "/scriptText"), 1, "an oversized form must not be sent")
+}
+
+func TestPutFileSendsFilesAtTheCapWithinTheFormLimit(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/scriptText", http.StatusOK, fmt.Sprintf("JK-PUT-OK %d false\n", maxUserContentBytes))
+	data := make([]byte, maxUserContentBytes)
+	_, _ = rand.New(rand.NewSource(1)).Read(data)
+	local := writeLocalFile(t, "random.bin", data)
+
+	_, _, err := runAdmin(t, client, "put-file", local, "--yes")
+	require.NoError(t, err)
+	body := server.LastRequest(http.MethodPost, "/scriptText").Body
+	require.LessOrEqual(t, len(body), maxFormContentBytes)
+}
+
+func TestPutFileRequiresAdmin(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/scriptText", http.StatusForbidden, "")
+	local := writeLocalFile(t, "plan.md", []byte("v1"))
+
+	_, _, err := runAdmin(t, client, "put-file", local, "--yes")
+	requireExitCode(t, err, 5)
+}
+
+func TestPutFileScriptErrorHidesContent(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	secret := "top secret rollout plan"
+	encoded := base64.StdEncoding.EncodeToString([]byte(secret))
+	server.Handle(http.MethodPost, "/scriptText", http.StatusOK,
+		"startup failed:\nScript1.groovy: 6: unexpected token @ line 6\ntarget.bytes = '"+encoded+"'.decodeBase64()\n")
+	local := writeLocalFile(t, "plan.md", []byte(secret))
+
+	_, _, err := runAdmin(t, client, "put-file", local, "--yes")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "startup failed:")
+	require.NotContains(t, err.Error(), encoded)
+}
+
+func TestListFiles(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/userContent/*plain*", http.StatusOK, "readme.txt\nreports/\n")
+	server.Handle(http.MethodGet, "/userContent/reports/*plain*", http.StatusOK, "nightly.html\n")
+
+	stdout, _, err := runAdmin(t, client, "ls-files")
+	require.NoError(t, err)
+	require.Equal(t, "readme.txt\nreports/\n", stdout.String())
+
+	stdout, _, err = runAdmin(t, client, "ls-files", "reports", "--json")
+	require.NoError(t, err)
+	var output listFilesOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+	require.Equal(t, "reports", output.Dir)
+	require.Len(t, output.Entries, 1)
+	require.Equal(t, "file", output.Entries[0].Type)
+	require.True(t, strings.HasSuffix(output.Entries[0].URL, "/userContent/reports/nightly.html"))
+
+	_, _, err = runAdmin(t, client, "ls-files", "missing")
+	requireExitCode(t, err, 3)
+}
@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/configsnap"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+type configSnapshotOutput struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Context       string `json:"context"`
+	Folder        string `json:"folder,omitempty"`
+	TakenAt       string `json:"takenAt"`
+	Jobs          int    `json:"jobs"`
+	Truncated     bool   `json:"truncated,omitempty"`
+	shared.Result
+}
+
+func newAdminSnapshotConfigCmd(f *cmdutil.Factory) *cobra.Command {
+	var scope configScope
+	var okOnPartial bool
+
+	cmd := &cobra.Command{
+		Use:   "snapshot-config",
+		Short: "Record every job's config.xml for later comparison",
+		Long: `Fetch config.xml of every job (or of the jobs under --folder) and keep it
+in the cache directory, replacing the previous snapshot of this context.
+jk admin audit-config compares against it on controllers without the Job
+Config History plugin.
+
+At most --max-jobs configs are fetched, in job path order. The snapshot
+holds the files verbatim and is readable only by the current user.`,
+		Example: `  jk admin snapshot-config
+  jk admin snapshot-config --folder releases --max-jobs 2000`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := scope.validate(); err != nil {
+				return err
+			}
+			scope.Folder = jobpath.Normalize(scope.Folder)
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			ctx := shared.CommandContext(cmd)
+
+			jobs, truncated, err := listScopedJobs(ctx, client, scope)
+			if err != nil {
+				return err
+			}
+			result := shared.NewResult()
+			configs := fetchJobConfigs(ctx, client, jobs, shared.Concurrency(f), &result)
+			if truncated {
+				result.Warn("", 0, fmt.Sprintf("snapshot limited to the first %d jobs; use --max-jobs or --folder", scope.MaxJobs))
+			}
+
+			snapshot := configsnap.Snapshot{
+				Folder:    scope.Folder,
+				TakenAt:   client.ServerNow().UTC().Truncate(time.Second),
+				Truncated: truncated,
+				Jobs:      make(map[string]configsnap.Job, len(configs)),
+			}
+			for job, config := range configs {
+				snapshot.Jobs[job] = configsnap.NewJob(config)
+			}
+			// A run where every fetch failed keeps the previous snapshot.
+			saved := len(configs) > 0 || len(jobs) == 0
+			if saved {
+				if err := configsnap.Save(client.ContextName(), snapshot); err != nil {
+					return err
+				}
+			}
+
+			output := configSnapshotOutput{
+				SchemaVersion: "1.0",
+				Context:       client.ContextName(),
+				Folder:        snapshot.Folder,
+				TakenAt:       shared.FormatTime(snapshot.TakenAt),
+				Jobs:          len(snapshot.Jobs),
+				Truncated:     truncated,
+				Result:        result,
+			}
+			if err := shared.PrintOutput(cmd, output, func() error {
+				if saved {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Saved config snapshot of %d job(s) for context %s\n", output.Jobs, output.Context)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			output.WriteIssues(cmd)
+			return output.ExitError("jobs", okOnPartial)
+		},
+	}
+
+	addConfigScopeFlags(cmd, &scope)
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+	return cmd
+}
@@ -12,12 +12,11 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/bmatcuk/doublestar/v4"
 	"github.com/spf13/cobra"
 
-	"github.com/avivsinai/jenkins-cli/internal/jenkins"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 type artifactListResponse struct {
@@ -28,6 +27,7 @@ type artifactItem struct {
 	FileName     string `json:"fileName"`
 	RelativePath string `json:"relativePath"`
 	Size         int64  `json:"size"`
+	MD5          string `json:"md5,omitempty"`
 }
 
 type artifactResponse interface {
@@ -66,7 +66,16 @@ func ensureArtifactResponse(rel string, resp artifactResponse) (io.ReadCloser, e
 			_, _ = io.Copy(io.Discard, rb)
 			_ = rb.Close()
 		}
-		return nil, fmt.Errorf("download %q failed: %s", rel, resp.Status())
+		code := 1
+		switch resp.StatusCode() {
+		case http.StatusNotFound:
+			code = 3
+		case http.StatusUnauthorized:
+			code = 4
+		case http.StatusForbidden:
+			code = 5
+		}
+		return nil, shared.NewExitError(code, fmt.Sprintf("download %q failed: %s", rel, resp.Status()))
 	}
 	body := resp.RawBody()
 	if body == nil {
@@ -84,35 +93,69 @@ func NewCmdArtifact(f *cmdutil.Factory) *cobra.Command {
 	cmd.AddCommand(
 		newArtifactListCmd(f),
 		newArtifactDownloadCmd(f),
+		NewCmdArtifactCat(f),
+		newArtifactVerifyCmd(f),
 	)
 
 	return cmd
 }
 
 func newArtifactListCmd(f *cmdutil.Factory) *cobra.Command {
+	var checksums bool
+
 	cmd := &cobra.Command{
 		Use:   "ls <jobPath> <buildNumber>",
 		Short: "List artifacts for a run",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			items, err := fetchArtifacts(cmd, f, args[0], args[1])
+			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			items, err := fetchArtifacts(client, jobPath, args[1])
+			if err != nil {
+				return shared.ExplainNotFound(cmd, client, jobPath, err)
+			}
+
+			if checksums {
+				num, err := strconv.Atoi(args[1])
+				if err != nil {
+					return err
+				}
+				if err := applyChecksums(client, jobPath, num, items); err != nil {
+					return err
+				}
+			}
+
 			return shared.PrintOutput(cmd, items, func() error {
 				if len(items) == 0 {
 					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No artifacts found")
 					return nil
 				}
 				for _, item := range items {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%d bytes\n", item.RelativePath, item.FileName, item.Size)
+					if !checksums {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%d bytes\n", item.RelativePath, item.FileName, item.Size)
+						continue
+					}
+					md5 := item.MD5
+					if md5 == "" {
+						md5 = "(no fingerprint)"
+					}
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%d bytes\t%s\n", item.RelativePath, item.FileName, item.Size, md5)
 				}
 				return nil
 			})
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
+	cmd.Flags().BoolVar(&checksums, "checksums", false, "Include the MD5 from each artifact's Jenkins fingerprint")
 	return cmd
 }
 
@@ -120,126 +163,203 @@ func newArtifactDownloadCmd(f *cmdutil.Factory) *cobra.Command {
 	var pattern string
 	var outputDir string
 	var allowEmpty bool
+	var verify bool
+	var flat string
+	var okOnPartial bool
 
 	cmd := &cobra.Command{
 		Use:   "download <jobPath> <buildNumber>",
 		Short: "Download artifacts",
-		Args:  cobra.ExactArgs(2),
+		Long: `Download the artifacts of a run that match --pattern.
+
+Files keep their archived directory structure under --output and take their
+modification time from Jenkins' Last-Modified header. --flat writes every file
+directly into --output by base name; it fails when two artifacts share a name,
+and --flat=rename suffixes the later ones instead (report-1.xml).
+
+A file that fails to download is reported and the rest are still fetched.
+The command exits 1 when some files failed (0 with --ok-on-partial) and with
+the failures' exit code when all of them did.`,
+		Example: `  jk artifact download team/app 42 -o out
+  jk artifact download team/app 42 -p 'target/reports/**/*.xml' --flat -o reports`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			items, err := fetchArtifacts(cmd, f, args[0], args[1])
+			if flat != "" && flat != flatError && flat != flatRename {
+				return shared.NewExitError(2, fmt.Sprintf("invalid --flat %q: use %s or %s", flat, flatError, flatRename))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			items, err := fetchArtifacts(client, jobPath, args[1])
+			if err != nil {
+				return shared.ExplainNotFound(cmd, client, jobPath, err)
+			}
+
 			if pattern == "" {
 				pattern = "**/*"
 			}
 
-			matched := make([]artifactItem, 0, len(items))
-			for _, item := range items {
-				match, err := doublestar.Match(pattern, item.RelativePath)
-				if err != nil {
-					return err
-				}
-				if match {
-					matched = append(matched, item)
-				}
+			matched, err := matchArtifacts(items, pattern)
+			if err != nil {
+				return err
 			}
 
 			if len(matched) == 0 {
 				if allowEmpty {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No artifacts matched pattern")
-					return nil
+					output := artifactDownloadOutput{SchemaVersion: "1.0", Items: []artifactDownloadItem{}, Result: shared.NewResult()}
+					return shared.PrintOutput(cmd, output, func() error {
+						_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No artifacts matched pattern")
+						return nil
+					})
 				}
 				return shared.NewExitError(3, "no artifacts matched pattern")
 			}
 
-			client, err := shared.JenkinsClient(cmd, f)
+			num, err := strconv.Atoi(args[1])
 			if err != nil {
 				return err
 			}
 
-			num, err := strconv.Atoi(args[1])
-			if err != nil {
-				return err
+			if verify {
+				if err := applyChecksums(client, jobPath, num, matched); err != nil {
+					return err
+				}
 			}
 
-			encoded := jenkins.EncodeJobPath(args[0])
-			base := fmt.Sprintf("/%s/%d/artifact", encoded, num)
 			outputDirAbs, err := filepath.Abs(outputDir)
 			if err != nil {
 				return fmt.Errorf("resolve output dir: %w", err)
 			}
 
-			for _, art := range matched {
-				destPath, displayPath, cleanRel, err := sanitizeArtifactPath(outputDirAbs, outputDir, art.RelativePath)
-				if err != nil {
-					return err
-				}
+			targets, err := planDownloads(outputDirAbs, outputDir, matched, flat)
+			if err != nil {
+				return err
+			}
 
-				if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
-					return err
-				}
+			progress := f.Progress()
+			defer progress.Done()
 
-				req := client.NewStreamingRequest().SetDoNotParseResponse(true)
-				segs := strings.Split(cleanRel, "/")
-				for i, s := range segs {
-					segs[i] = url.PathEscape(s)
-				}
-				artifactPath := base + "/" + strings.Join(segs, "/")
-				resp, err := client.Do(req, http.MethodGet, artifactPath, nil)
+			human := !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd)
+			output := artifactDownloadOutput{SchemaVersion: "1.0", Items: []artifactDownloadItem{}, Result: shared.NewResult()}
+			var verified []artifactVerifyResult
+			for _, target := range targets {
+				item, err := downloadArtifact(client, jobPath, num, target, progress, verify)
 				if err != nil {
-					return err
+					output.Fail(target.CleanRel, err)
+					continue
 				}
-
-				body, err := ensureArtifactResponse(art.RelativePath, resp)
-				if err != nil {
-					return err
+				output.Succeed()
+				output.Items = append(output.Items, item)
+				if item.Verify != nil {
+					verified = append(verified, *item.Verify)
 				}
-				if err := saveArtifact(destPath, body); err != nil {
-					return err
+				if !human {
+					continue
+				}
+				line := "Downloaded " + target.DisplayPath
+				if result := item.Verify; result != nil {
+					note := "md5 " + result.Status
+					if result.Status == verifyMismatch {
+						note += fmt.Sprintf(", expected %s, got %s", result.Expected, result.Actual)
+					}
+					line += " (" + note + ")"
 				}
-				if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Downloaded %s\n", displayPath); err != nil {
+				if _, err := fmt.Fprintln(cmd.OutOrStdout(), line); err != nil {
 					return err
 				}
 			}
 
-			return nil
+			output.WriteIssues(cmd)
+			if !human {
+				if err := shared.PrintOutput(cmd, output, nil); err != nil {
+					return err
+				}
+			}
+			if err := output.ExitError("artifacts", okOnPartial); err != nil {
+				return err
+			}
+			return verifyExitError(verified)
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
 	cmd.Flags().StringVarP(&pattern, "pattern", "p", "**/*", "Glob to match artifacts")
 	cmd.Flags().StringVarP(&outputDir, "output", "o", ".", "Output directory")
 	cmd.Flags().BoolVar(&allowEmpty, "allow-empty", false, "Do not error when no artifacts match")
+	cmd.Flags().BoolVar(&verify, "verify", false, "Check each file against its Jenkins fingerprint after it lands; exit 2 on mismatch")
+	cmd.Flags().StringVar(&flat, "flat", "", "Write files into the output directory by base name; on name collisions fail (error) or suffix them (rename)")
+	cmd.Flags().Lookup("flat").NoOptDefVal = flatError
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+	cmdutil.SetFlagEnum(cmd, "flat", flatError, flatRename)
 	return cmd
 }
 
-func fetchArtifacts(cmd *cobra.Command, f *cmdutil.Factory, jobPath, buildNumber string) ([]artifactItem, error) {
-	client, err := shared.JenkinsClient(cmd, f)
-	if err != nil {
-		return nil, err
+// artifactURLPath builds the download path for a cleaned relative path,
+// escaping each segment.
+func artifactURLPath(jobPath string, num int, cleanRel string) string {
+	segs := strings.Split(cleanRel, "/")
+	for i, s := range segs {
+		segs[i] = url.PathEscape(s)
 	}
+	return fmt.Sprintf("/%s/%d/artifact/%s", jobpath.Encode(jobPath), num, strings.Join(segs, "/"))
+}
 
+func fetchArtifacts(client shared.Doer, jobPath, buildNumber string) ([]artifactItem, error) {
 	num, err := strconv.Atoi(buildNumber)
 	if err != nil {
 		return nil, err
 	}
 
-	encoded := jenkins.EncodeJobPath(jobPath)
+	encoded := jobpath.Encode(jobPath)
 	if encoded == "" {
 		return nil, errors.New("job path is required")
 	}
 	path := fmt.Sprintf("/%s/%d/api/json", encoded, num)
 
 	var resp artifactListResponse
-	_, err = client.Do(client.NewRequest().SetQueryParam("tree", "artifacts[fileName,relativePath,size]"), http.MethodGet, path, &resp)
+	httpResp, err := client.Do(client.NewRequest().SetQueryParam("tree", "artifacts[fileName,relativePath,size]"), http.MethodGet, path, &resp)
 	if err != nil {
 		return nil, err
 	}
+	if err := shared.CheckResponse(httpResp, fmt.Sprintf("run %s #%d", jobPath, num)); err != nil {
+		return nil, err
+	}
 
 	return resp.Artifacts, nil
 }
 
+// progressReportInterval throttles artifact.download events to one per this
+// many bytes, plus a final event at EOF.
+const progressReportInterval = 256 << 10
+
+// progressReader reports artifact.download events as the body is consumed.
+type progressReader struct {
+	io.ReadCloser
+	progress cmdutil.ProgressReporter
+	file     string
+	total    int64
+	read     int64
+	reported int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.read += int64(n)
+	if r.read-r.reported >= progressReportInterval || (err == io.EOF && r.read != r.reported) {
+		r.reported = r.read
+		r.progress.Report(cmdutil.ProgressEvent{Event: cmdutil.EventArtifactDownload, File: r.file, Bytes: r.read, Total: r.total})
+	}
+	return n, err
+}
+
 func saveArtifact(destPath string, body io.ReadCloser) (err error) {
 	defer func() {
 		if cerr := body.Close(); cerr != nil {
@@ -0,0 +1,165 @@
+package artifact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const (
+	// defaultCatMaxBytes keeps `cat` to manifests and summaries; anything
+	// larger belongs in `artifact download`.
+	defaultCatMaxBytes = 1 << 20
+	// binarySniffBytes is how much of the artifact is checked for NUL bytes
+	// before anything is written to stdout.
+	binarySniffBytes = 8 << 10
+)
+
+// NewCmdArtifactCat returns the `cat` command. It is shared by `jk artifact`
+// and `jk run artifact`.
+func NewCmdArtifactCat(f *cmdutil.Factory) *cobra.Command {
+	var maxBytes int64
+	var binary bool
+
+	cmd := &cobra.Command{
+		Use:   "cat <jobPath> <buildNumber> <relativePath>",
+		Short: "Print a small artifact to stdout",
+		Long: `Print a single artifact to stdout without downloading it.
+
+The path may be the full relative path or any unambiguous suffix of it
+(for example "version.txt" for "build/out/version.txt"). Artifacts larger than
+--max-bytes and artifacts that look binary are refused; use
+"jk artifact download" for those. Only the artifact bytes are written to
+stdout, so the output is safe to pipe.`,
+		Example: `  # Peek at a version manifest
+  jk artifact cat team/app 42 version.json | jq .version
+
+  # Same, from the run command tree
+  jk run artifact cat team/app 42 reports/summary.txt`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxBytes <= 0 {
+				return shared.NewExitError(2, "--max-bytes must be positive")
+			}
+			num, err := strconv.Atoi(args[1])
+			if err != nil || num <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid build number %q", args[1]))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			items, err := fetchArtifacts(client, jobPath, args[1])
+			if err != nil {
+				return shared.ExplainNotFound(cmd, client, jobPath, err)
+			}
+
+			item, err := matchArtifact(items, args[2])
+			if err != nil {
+				return err
+			}
+			if item.Size > maxBytes {
+				return shared.NewExitError(2, fmt.Sprintf("artifact %s is %d bytes, over the %d byte limit; raise --max-bytes or use jk artifact download", item.RelativePath, item.Size, maxBytes))
+			}
+
+			req := client.NewStreamingRequest().SetDoNotParseResponse(true)
+			resp, err := client.Do(req, http.MethodGet, artifactURLPath(jobPath, num, path.Clean(item.RelativePath)), nil)
+			if err != nil {
+				return err
+			}
+			body, err := ensureArtifactResponse(item.RelativePath, resp)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = body.Close() }()
+
+			return catArtifact(cmd.OutOrStdout(), body, item.RelativePath, maxBytes, binary)
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().Int64Var(&maxBytes, "max-bytes", defaultCatMaxBytes, "Refuse artifacts larger than this many bytes")
+	cmd.Flags().BoolVar(&binary, "binary", false, "Write the artifact even if it looks binary")
+	return cmd
+}
+
+// matchArtifact resolves want to a single artifact, preferring an exact
+// relative path and otherwise accepting a suffix that starts at a path
+// segment boundary.
+func matchArtifact(items []artifactItem, want string) (artifactItem, error) {
+	want = strings.TrimPrefix(strings.ReplaceAll(strings.TrimSpace(want), "\\", "/"), "/")
+	if want == "" {
+		return artifactItem{}, shared.NewExitError(2, "artifact path required")
+	}
+
+	var matches []artifactItem
+	for _, item := range items {
+		rel := strings.ReplaceAll(item.RelativePath, "\\", "/")
+		if rel == want {
+			return item, nil
+		}
+		if strings.HasSuffix(rel, "/"+want) {
+			matches = append(matches, item)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return artifactItem{}, shared.NewExitError(3, fmt.Sprintf("no artifact matches %q", want))
+	case 1:
+		return matches[0], nil
+	default:
+		paths := make([]string, 0, len(matches))
+		for _, item := range matches {
+			paths = append(paths, item.RelativePath)
+		}
+		return artifactItem{}, shared.NewExitError(2, fmt.Sprintf("%q matches several artifacts: %s", want, strings.Join(paths, ", ")))
+	}
+}
+
+// catArtifact copies body to w. The first chunk is inspected for NUL bytes
+// before anything is written, and the copy stops with an error once maxBytes
+// is exceeded, in case the listed size was stale.
+func catArtifact(w io.Writer, body io.Reader, rel string, maxBytes int64, binary bool) error {
+	limited := io.LimitReader(body, maxBytes+1)
+
+	head := make([]byte, binarySniffBytes)
+	n, err := io.ReadFull(limited, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("read artifact %q: %w", rel, err)
+	}
+	head = head[:n]
+	if !binary && bytes.IndexByte(head, 0) >= 0 {
+		return shared.NewExitError(2, fmt.Sprintf("artifact %s looks binary; pass --binary or use jk artifact download", rel))
+	}
+	if int64(n) > maxBytes {
+		return shared.NewExitError(2, fmt.Sprintf("artifact %s exceeds the %d byte limit; raise --max-bytes or use jk artifact download", rel, maxBytes))
+	}
+
+	if _, err := w.Write(head); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(w, limited, maxBytes-int64(n)); err != nil && err != io.EOF {
+		return fmt.Errorf("read artifact %q: %w", rel, err)
+	}
+	if extra, _ := limited.Read(make([]byte, 1)); extra > 0 {
+		return shared.NewExitError(2, fmt.Sprintf("artifact %s exceeds the %d byte limit; output truncated", rel, maxBytes))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package artifact
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func newCatServer(t *testing.T) (*fakejenkins.Server, *cmdutil.Factory, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/7/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "version.json", "relativePath": "build/version.json", "size": 17},
+			{"fileName": "summary.txt", "relativePath": "reports/unit/summary.txt", "size": 5},
+			{"fileName": "summary.txt", "relativePath": "reports/e2e/summary.txt", "size": 5},
+			{"fileName": "app.bin", "relativePath": "build/app.bin", "size": 4},
+			{"fileName": "big.log", "relativePath": "big.log", "size": 4096},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/app/7/artifact/build/version.json", http.StatusOK, `{"version":"1.2"}`)
+	server.Handle(http.MethodGet, "/job/app/7/artifact/build/app.bin", http.StatusOK, "\x7fELF\x00")
+	f, stdout, stderr := fakejenkins.Factory(client)
+	return server, f, stdout, stderr
+}
+
+func runCat(f *cmdutil.Factory, stdout, stderr *bytes.Buffer, args ...string) error {
+	cmd := NewCmdArtifactCat(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func requireExitCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, code, exitErr.Code, exitErr.Msg)
+}
+
+func TestArtifactCatStreamsUniqueSuffix(t *testing.T) {
+	_, f, stdout, stderr := newCatServer(t)
+
+	require.NoError(t, runCat(f, stdout, stderr, "app", "7", "version.json"))
+	require.Equal(t, `{"version":"1.2"}`, stdout.String())
+	require.Empty(t, stderr.String())
+}
+
+func TestArtifactCatRejectsAmbiguousAndMissing(t *testing.T) {
+	_, f, stdout, stderr := newCatServer(t)
+
+	err := runCat(f, stdout, stderr, "app", "7", "summary.txt")
+	requireExitCode(t, err, 2)
+	require.Contains(t, err.Error(), "reports/unit/summary.txt")
+
+	requireExitCode(t, runCat(f, stdout, stderr, "app", "7", "ersion.json"), 3)
+	require.Empty(t, stdout.String())
+}
+
+func TestArtifactCatEnforcesSizeCap(t *testing.T) {
+	server, f, stdout, stderr := newCatServer(t)
+
+	err := runCat(f, stdout, stderr, "app", "7", "big.log", "--max-bytes", "1024")
+	requireExitCode(t, err, 2)
+	require.Contains(t, err.Error(), "jk artifact download")
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/7/artifact/big.log"))
+}
+
+func TestArtifactCatRefusesBinaryUnlessForced(t *testing.T) {
+	_, f, stdout, stderr := newCatServer(t)
+
+	requireExitCode(t, runCat(f, stdout, stderr, "app", "7", "build/app.bin"), 2)
+	require.Empty(t, stdout.String())
+
+	require.NoError(t, runCat(f, stdout, stderr, "app", "7", "build/app.bin", "--binary"))
+	require.Equal(t, "\x7fELF\x00", stdout.String())
+}
+
+func TestCatArtifactStopsWhenListedSizeIsStale(t *testing.T) {
+	var out bytes.Buffer
+	err := catArtifact(&out, strings.NewReader(strings.Repeat("a", 20000)), "grown.log", 10000, false)
+	requireExitCode(t, err, 2)
+	require.Equal(t, 10000, out.Len())
+}
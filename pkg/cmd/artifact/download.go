@@ -0,0 +1,238 @@
+package artifact
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// --flat modes: flatError refuses basename collisions, flatRename suffixes
+// later files ("report-1.xml").
+const (
+	flatError  = "error"
+	flatRename = "rename"
+)
+
+type artifactDownloadOutput struct {
+	SchemaVersion string                 `json:"schemaVersion"`
+	Items         []artifactDownloadItem `json:"items"`
+	shared.Result
+}
+
+type artifactDownloadItem struct {
+	Path string `json:"path"`
+	// File is where the artifact was written, as given by --output.
+	File string `json:"file"`
+	Size int64  `json:"size"`
+	// Verify is the fingerprint check, with --verify.
+	Verify *artifactVerifyResult `json:"verify,omitempty"`
+}
+
+// DownloadOptions selects the artifacts DownloadArtifacts fetches and where
+// they go.
+type DownloadOptions struct {
+	// Pattern is a glob over artifact paths; empty matches every artifact.
+	Pattern string
+	// OutputDir receives the files, keeping their archived structure.
+	OutputDir string
+	// Progress receives artifact.download events; nil disables them.
+	Progress cmdutil.ProgressReporter
+}
+
+// DownloadArtifacts downloads the artifacts of build num that match
+// opts.Pattern, with the same matching and path sanitization as jk artifact
+// download, and returns the written files as given by opts.OutputDir. It
+// exits 3 when nothing matches and stops at the first file that fails.
+func DownloadArtifacts(client shared.Doer, jobPath string, num int64, opts DownloadOptions) ([]string, error) {
+	items, err := fetchArtifacts(client, jobPath, strconv.FormatInt(num, 10))
+	if err != nil {
+		return nil, err
+	}
+	pattern := opts.Pattern
+	if pattern == "" {
+		pattern = "**/*"
+	}
+	matched, err := matchArtifacts(items, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(matched) == 0 {
+		return nil, shared.NewExitError(3, fmt.Sprintf("no artifacts matched %q", pattern))
+	}
+
+	outputDir := opts.OutputDir
+	if outputDir == "" {
+		outputDir = "."
+	}
+	outputDirAbs, err := filepath.Abs(outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolve output dir: %w", err)
+	}
+	targets, err := planDownloads(outputDirAbs, outputDir, matched, "")
+	if err != nil {
+		return nil, err
+	}
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = cmdutil.NoopProgress()
+	}
+	defer progress.Done()
+	files := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if _, err := downloadArtifact(client, jobPath, int(num), target, progress, false); err != nil {
+			return files, fmt.Errorf("download %s: %w", target.CleanRel, err)
+		}
+		files = append(files, target.DisplayPath)
+	}
+	return files, nil
+}
+
+// matchArtifacts returns the artifacts whose relative path matches pattern.
+func matchArtifacts(items []artifactItem, pattern string) ([]artifactItem, error) {
+	matched := make([]artifactItem, 0, len(items))
+	for _, item := range items {
+		match, err := doublestar.Match(pattern, item.RelativePath)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			matched = append(matched, item)
+		}
+	}
+	return matched, nil
+}
+
+// downloadTarget is where one matched artifact is written.
+type downloadTarget struct {
+	Artifact    artifactItem
+	CleanRel    string
+	DestPath    string
+	DisplayPath string
+}
+
+// planDownloads maps matched artifacts to local paths: the artifact's
+// relative path under the output directory, or with flat set only its base
+// name.
+func planDownloads(outputDirAbs, outputDir string, matched []artifactItem, flat string) ([]downloadTarget, error) {
+	targets := make([]downloadTarget, 0, len(matched))
+	for _, art := range matched {
+		destPath, displayPath, cleanRel, err := sanitizeArtifactPath(outputDirAbs, outputDir, art.RelativePath)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, downloadTarget{Artifact: art, CleanRel: cleanRel, DestPath: destPath, DisplayPath: displayPath})
+	}
+	if flat == "" {
+		return targets, nil
+	}
+
+	byName := make(map[string][]string)
+	for _, target := range targets {
+		base := path.Base(target.CleanRel)
+		byName[base] = append(byName[base], target.CleanRel)
+	}
+	if flat == flatError {
+		var conflicts []string
+		for base, paths := range byName {
+			if len(paths) > 1 {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s", base, strings.Join(paths, ", ")))
+			}
+		}
+		if len(conflicts) > 0 {
+			sort.Strings(conflicts)
+			return nil, shared.NewExitError(2, fmt.Sprintf("--flat: artifacts share a file name (use --flat=rename to suffix them):\n  %s", strings.Join(conflicts, "\n  ")))
+		}
+	}
+
+	used := make(map[string]bool, len(targets))
+	for base := range byName {
+		used[base] = true
+	}
+	taken := make(map[string]bool, len(targets))
+	for i, target := range targets {
+		base := path.Base(target.CleanRel)
+		name := base
+		if taken[name] {
+			ext := path.Ext(base)
+			stem := strings.TrimSuffix(base, ext)
+			for n := 1; used[name]; n++ {
+				name = stem + "-" + strconv.Itoa(n) + ext
+			}
+		}
+		used[name] = true
+		taken[name] = true
+		targets[i].DestPath = filepath.Join(outputDirAbs, name)
+		targets[i].DisplayPath = filepath.Join(outputDir, name)
+	}
+	return targets, nil
+}
+
+// downloadArtifact fetches one artifact to target.DestPath and, with verify,
+// checks it against its fingerprint.
+func downloadArtifact(client shared.Doer, jobPath string, num int, target downloadTarget, progress cmdutil.ProgressReporter, verify bool) (artifactDownloadItem, error) {
+	item := artifactDownloadItem{Path: target.CleanRel, File: target.DisplayPath, Size: target.Artifact.Size}
+	if err := os.MkdirAll(filepath.Dir(target.DestPath), 0o755); err != nil {
+		return item, err
+	}
+
+	req := client.NewStreamingRequest().SetDoNotParseResponse(true)
+	resp, err := client.Do(req, http.MethodGet, artifactURLPath(jobPath, num, target.CleanRel), nil)
+	if err != nil {
+		return item, err
+	}
+
+	body, err := ensureArtifactResponse(target.Artifact.RelativePath, resp)
+	if err != nil {
+		return item, err
+	}
+	progress.Report(cmdutil.ProgressEvent{Event: cmdutil.EventArtifactDownload, File: target.CleanRel, Total: target.Artifact.Size})
+	body = &progressReader{ReadCloser: body, progress: progress, file: target.CleanRel, total: target.Artifact.Size}
+	if err := saveArtifact(target.DestPath, body); err != nil {
+		return item, err
+	}
+	if err := applyLastModified(target.DestPath, resp.Header()); err != nil {
+		return item, err
+	}
+	progress.Done()
+	if !verify {
+		return item, nil
+	}
+
+	result, err := verifyArtifactFile(target.DestPath, target.Artifact.MD5)
+	if err != nil {
+		return item, err
+	}
+	result.Path = target.CleanRel
+	item.Verify = &result
+	return item, nil
+}
+
+// applyLastModified sets destPath's modification time from a Last-Modified
+// header so make-style tools see when the artifact was archived. A missing
+// or unparsable header leaves the download time.
+func applyLastModified(destPath string, header http.Header) error {
+	value := strings.TrimSpace(header.Get("Last-Modified"))
+	if value == "" {
+		return nil
+	}
+	modified, err := http.ParseTime(value)
+	if err != nil {
+		return nil
+	}
+	if err := os.Chtimes(destPath, time.Time{}, modified); err != nil {
+		return fmt.Errorf("set modification time of %q: %w", destPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package artifact
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestArtifactDownloadEncodesPaths(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/team/job/my%20app/7/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "test #1.txt", "relativePath": "reports/test #1.txt", "size": 5},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/team/job/my%20app/7/artifact/reports/test%20%231.txt", http.StatusOK, "hello")
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	outDir := t.TempDir()
+
+	cmd := NewCmdArtifact(f)
+	cmd.SetArgs([]string{"download", "team/my app", "7", "--output", outDir})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	require.NoError(t, cmd.Execute())
+
+	list := server.LastRequest(http.MethodGet, "/job/team/job/my%20app/7/api/json")
+	require.Equal(t, "artifacts[fileName,relativePath,size]", list.Query.Get("tree"))
+	server.LastRequest(http.MethodGet, "/job/team/job/my%20app/7/artifact/reports/test%20%231.txt")
+
+	data, err := os.ReadFile(filepath.Join(outDir, "reports", "test #1.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+}
+
+func TestArtifactDownloadReportsJSONProgress(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/3/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "out.txt", "relativePath": "out.txt", "size": 5},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/app/3/artifact/out.txt", http.StatusOK, "hello")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	f.ProgressMode = cmdutil.ProgressJSON
+
+	cmd := NewCmdArtifact(f)
+	cmd.SetArgs([]string{"download", "app", "3", "--output", t.TempDir()})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	require.NoError(t, cmd.Execute())
+
+	require.NotContains(t, stdout.String(), "artifact.download")
+	lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var last cmdutil.ProgressEvent
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &last))
+	require.Equal(t, cmdutil.ProgressEvent{SchemaVersion: "1.0", Event: "artifact.download", File: "out.txt", Bytes: 5, Total: 5}, last)
+}
+
+func TestArtifactDownloadUnderContextPath(t *testing.T) {
+	server, client := fakejenkins.NewClientAt(t, "/jenkins")
+	server.HandleJSON(http.MethodGet, "/job/app/5/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "out.txt", "relativePath": "dist/out.txt", "size": 5},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/app/5/artifact/dist/out.txt", http.StatusOK, "hello")
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	outDir := t.TempDir()
+
+	cmd := NewCmdArtifact(f)
+	cmd.SetArgs([]string{"download", "app", "5", "--output", outDir})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(filepath.Join(outDir, "dist", "out.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+}
+
+func executeDownload(t *testing.T, server *fakejenkins.Server, client *jenkins.Client, artifacts []string, args ...string) (string, error) {
+	t.Helper()
+	items := make([]map[string]any, len(artifacts))
+	for i, rel := range artifacts {
+		items[i] = map[string]any{"fileName": filepath.Base(rel), "relativePath": rel, "size": len(rel)}
+		server.Handle(http.MethodGet, "/job/app/9/artifact/"+rel, http.StatusOK, rel)
+	}
+	server.HandleJSON(http.MethodGet, "/job/app/9/api/json", map[string]any{"artifacts": items})
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdArtifact(f)
+	cmd.SetArgs(append([]string{"download", "app", "9"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestArtifactDownloadPreservesLastModified(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/9/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "app.jar", "relativePath": "target/app.jar", "size": 3},
+			{"fileName": "notes.txt", "relativePath": "notes.txt", "size": 3},
+		},
+	})
+	server.HandleResponse(http.MethodGet, "/job/app/9/artifact/target/app.jar", http.StatusOK,
+		http.Header{"Last-Modified": {"Tue, 14 May 2024 08:30:00 GMT"}}, "jar")
+	server.Handle(http.MethodGet, "/job/app/9/artifact/notes.txt", http.StatusOK, "txt")
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	outDir := t.TempDir()
+	cmd := NewCmdArtifact(f)
+	cmd.SetArgs([]string{"download", "app", "9", "--output", outDir})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	started := time.Now().Add(-time.Minute)
+	require.NoError(t, cmd.Execute())
+
+	info, err := os.Stat(filepath.Join(outDir, "target", "app.jar"))
+	require.NoError(t, err)
+	require.True(t, info.ModTime().Equal(time.Date(2024, 5, 14, 8, 30, 0, 0, time.UTC)), "got %s", info.ModTime())
+
+	info, err = os.Stat(filepath.Join(outDir, "notes.txt"))
+	require.NoError(t, err)
+	require.True(t, info.ModTime().After(started), "files without Last-Modified keep the download time")
+}
+
+func TestArtifactDownloadFlat(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	outDir := t.TempDir()
+
+	stdout, err := executeDownload(t, server, client, []string{"target/reports/2024/05/foo.xml", "target/bar.xml"}, "--flat", "--output", outDir)
+	require.NoError(t, err)
+	require.Contains(t, stdout, "Downloaded "+filepath.Join(outDir, "foo.xml"))
+
+	entries, err := os.ReadDir(outDir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	require.Equal(t, []string{"bar.xml", "foo.xml"}, names)
+	data, err := os.ReadFile(filepath.Join(outDir, "foo.xml"))
+	require.NoError(t, err)
+	require.Equal(t, "target/reports/2024/05/foo.xml", string(data))
+}
+
+func TestArtifactDownloadFlatCollisions(t *testing.T) {
+	artifacts := []string{"a/report.xml", "b/report.xml", "report-1.xml", "c/report.xml"}
+
+	server, client := fakejenkins.NewClient(t)
+	outDir := t.TempDir()
+	_, err := executeDownload(t, server, client, artifacts, "--flat", "--output", outDir)
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 2, exitErr.Code)
+	require.Contains(t, err.Error(), "report.xml: a/report.xml, b/report.xml, c/report.xml")
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/9/artifact/a/report.xml"), "nothing is downloaded on conflict")
+
+	server, client = fakejenkins.NewClient(t)
+	outDir = t.TempDir()
+	_, err = executeDownload(t, server, client, artifacts, "--flat=rename", "--output", outDir)
+	require.NoError(t, err)
+	for name, source := range map[string]string{
+		"report.xml":   "a/report.xml",
+		"report-2.xml": "b/report.xml",
+		"report-1.xml": "report-1.xml",
+		"report-3.xml": "c/report.xml",
+	} {
+		data, err := os.ReadFile(filepath.Join(outDir, name))
+		require.NoError(t, err, name)
+		require.Equal(t, source, string(data), name)
+	}
+
+	_, err = executeDownload(t, server, client, artifacts, "--flat=squash", "--output", outDir)
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 2, exitErr.Code)
+}
+
+func TestArtifactDownloadContinuesPastFailedFiles(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/9/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "a.txt", "relativePath": "a.txt", "size": 1},
+			{"fileName": "b.txt", "relativePath": "b.txt", "size": 1},
+			{"fileName": "c.txt", "relativePath": "c.txt", "size": 1},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/app/9/artifact/a.txt", http.StatusOK, "a")
+	server.Handle(http.MethodGet, "/job/app/9/artifact/b.txt", http.StatusInternalServerError, "boom")
+	server.Handle(http.MethodGet, "/job/app/9/artifact/c.txt", http.StatusOK, "c")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	outDir := t.TempDir()
+	err := runArtifact(f, stdout, stderr, "download", "app", "9", "--output", outDir, "--json")
+	requireExitCode(t, err, 1)
+	require.Contains(t, err.Error(), "1 of 3 artifacts failed")
+
+	var output artifactDownloadOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Len(t, output.Items, 2)
+	require.Equal(t, "c.txt", output.Items[1].Path)
+	require.Equal(t, shared.ResultSummary{Succeeded: 2, Failed: 1}, output.Summary)
+	require.Len(t, output.Errors, 1)
+	require.Equal(t, "b.txt", output.Errors[0].Target)
+	_, err = os.Stat(filepath.Join(outDir, "c.txt"))
+	require.NoError(t, err, "files after a failure are still downloaded")
+
+	stdout.Reset()
+	stderr.Reset()
+	require.NoError(t, runArtifact(f, stdout, stderr, "download", "app", "9", "--output", outDir, "--ok-on-partial"))
+	require.Contains(t, stderr.String(), `error: download "b.txt" failed: 500`)
+}
+
+func TestArtifactDownloadAllFailedKeepsCause(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/9/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "a.txt", "relativePath": "a.txt", "size": 1},
+			{"fileName": "b.txt", "relativePath": "b.txt", "size": 1},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/app/9/artifact/a.txt", http.StatusForbidden, "denied")
+	server.Handle(http.MethodGet, "/job/app/9/artifact/b.txt", http.StatusForbidden, "denied")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	err := runArtifact(f, stdout, stderr, "download", "app", "9", "--output", t.TempDir(), "--ok-on-partial")
+	requireExitCode(t, err, 5)
+}
@@ -0,0 +1,246 @@
+package artifact
+
+import (
+	"crypto/md5" //nolint:gosec // Jenkins fingerprints are MD5; used for integrity checks only
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// Per-file outcomes reported by `artifact verify` and `download --verify`.
+const (
+	verifyMatch        = "match"
+	verifyMismatch     = "mismatch"
+	verifyMissing      = "missing"
+	verifyUnverifiable = "unverifiable"
+)
+
+type fingerprintResponse struct {
+	Fingerprint []struct {
+		FileName string `json:"fileName"`
+		Hash     string `json:"hash"`
+	} `json:"fingerprint"`
+}
+
+type artifactVerifyOutput struct {
+	SchemaVersion string                 `json:"schemaVersion"`
+	JobPath       string                 `json:"jobPath"`
+	Build         int                    `json:"build"`
+	Dir           string                 `json:"dir"`
+	Files         []artifactVerifyResult `json:"files"`
+}
+
+type artifactVerifyResult struct {
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+func newArtifactVerifyCmd(f *cmdutil.Factory) *cobra.Command {
+	var dir string
+	var pattern string
+
+	cmd := &cobra.Command{
+		Use:   "verify <jobPath> <buildNumber>",
+		Short: "Check local files against the run's artifact fingerprints",
+		Long: `Recompute the MD5 of local files laid out like the run's artifacts (as
+written by "jk artifact download --output DIR") and compare them with the
+Jenkins fingerprints.
+
+Each artifact is reported as match, mismatch, missing (no local file), or
+unverifiable (Jenkins recorded no fingerprint for it; enable fingerprinting in
+the archiveArtifacts step). Unverifiable files never count as passing silently,
+but only mismatches and missing files fail the command.`,
+		Example: `  # Verify a release download before signing
+  jk artifact download team/app 42 --output ./downloads
+  jk artifact verify team/app 42 --dir ./downloads`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			num, err := strconv.Atoi(args[1])
+			if err != nil || num <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid build number %q", args[1]))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			items, err := fetchArtifacts(client, jobPath, args[1])
+			if err != nil {
+				return shared.ExplainNotFound(cmd, client, jobPath, err)
+			}
+			if err := applyChecksums(client, jobPath, num, items); err != nil {
+				return err
+			}
+
+			dirAbs, err := filepath.Abs(dir)
+			if err != nil {
+				return fmt.Errorf("resolve dir: %w", err)
+			}
+
+			output := artifactVerifyOutput{SchemaVersion: "1.0", JobPath: jobPath, Build: num, Dir: dir}
+			for _, item := range items {
+				match, err := doublestar.Match(pattern, item.RelativePath)
+				if err != nil {
+					return err
+				}
+				if !match {
+					continue
+				}
+				destPath, _, cleanRel, err := sanitizeArtifactPath(dirAbs, dir, item.RelativePath)
+				if err != nil {
+					return err
+				}
+				result, err := verifyArtifactFile(destPath, item.MD5)
+				if err != nil {
+					return err
+				}
+				result.Path = cleanRel
+				output.Files = append(output.Files, result)
+			}
+			if len(output.Files) == 0 {
+				return shared.NewExitError(3, "no artifacts matched pattern")
+			}
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				for _, file := range output.Files {
+					line := fmt.Sprintf("%-12s %s", file.Status, file.Path)
+					if file.Status == verifyMismatch {
+						line += fmt.Sprintf(" (expected %s, got %s)", file.Expected, file.Actual)
+					}
+					_, _ = fmt.Fprintln(w, line)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			return verifyExitError(output.Files)
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().StringVar(&dir, "dir", ".", "Directory holding the downloaded artifacts")
+	cmd.Flags().StringVarP(&pattern, "pattern", "p", "**/*", "Glob to select artifacts")
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		2: "At least one file does not match its fingerprint",
+		3: "At least one artifact is missing locally",
+	})
+	return cmd
+}
+
+// verifyExitError returns exit 2 when any file mismatched, exit 3 when files
+// are only missing, and nil otherwise. The per-file report has already been
+// printed, so the errors carry no message.
+func verifyExitError(files []artifactVerifyResult) error {
+	var mismatched, missing int
+	for _, file := range files {
+		switch file.Status {
+		case verifyMismatch:
+			mismatched++
+		case verifyMissing:
+			missing++
+		}
+	}
+	switch {
+	case mismatched > 0:
+		return shared.NewExitError(2, "")
+	case missing > 0:
+		return shared.NewExitError(3, "")
+	default:
+		return nil
+	}
+}
+
+func verifyArtifactFile(localPath, expected string) (artifactVerifyResult, error) {
+	result := artifactVerifyResult{Expected: expected}
+	actual, err := fileMD5(localPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		result.Status = verifyMissing
+		return result, nil
+	case err != nil:
+		return result, err
+	}
+	result.Actual = actual
+	switch {
+	case expected == "":
+		result.Status = verifyUnverifiable
+	case strings.EqualFold(expected, actual):
+		result.Status = verifyMatch
+	default:
+		result.Status = verifyMismatch
+	}
+	return result, nil
+}
+
+func fileMD5(localPath string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	h := md5.New() //nolint:gosec // see import
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("hash %q: %w", localPath, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// applyChecksums fills in MD5 from the run's fingerprints. Fingerprints are
+// keyed by file name, which is usually the relative path; a bare base name is
+// accepted too when it identifies a single artifact.
+func applyChecksums(client shared.Doer, jobPath string, num int, items []artifactItem) error {
+	var resp fingerprintResponse
+	req := client.NewRequest().SetQueryParam("tree", "fingerprint[fileName,hash]")
+	httpResp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/%d/api/json", jobpath.Encode(jobPath), num), &resp)
+	if err != nil {
+		return err
+	}
+	if err := shared.CheckResponse(httpResp, fmt.Sprintf("run %s #%d", jobPath, num)); err != nil {
+		return err
+	}
+
+	byName := make(map[string]string, len(resp.Fingerprint))
+	for _, fp := range resp.Fingerprint {
+		byName[path.Clean(strings.ReplaceAll(fp.FileName, "\\", "/"))] = fp.Hash
+	}
+	baseCount := make(map[string]int, len(items))
+	for _, item := range items {
+		baseCount[path.Base(item.RelativePath)]++
+	}
+
+	for i := range items {
+		rel := path.Clean(strings.ReplaceAll(items[i].RelativePath, "\\", "/"))
+		if hash, ok := byName[rel]; ok {
+			items[i].MD5 = hash
+			continue
+		}
+		if base := path.Base(rel); baseCount[base] == 1 {
+			items[i].MD5 = byName[base]
+		}
+	}
+	return nil
+}
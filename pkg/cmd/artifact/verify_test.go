@@ -0,0 +1,127 @@
+package artifact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const (
+	md5Hello = "5d41402abc4b2a76b9719d911017c592"
+	md5World = "7d793037a0760186574b0282f2f435e7"
+)
+
+// newChecksumServer serves one payload for both the artifact and fingerprint
+// tree queries, since the fake routes on path only.
+func newChecksumServer(t *testing.T) (*fakejenkins.Server, *cmdutil.Factory, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/9/api/json", map[string]any{
+		"artifacts": []map[string]any{
+			{"fileName": "a.txt", "relativePath": "dist/a.txt", "size": 5},
+			{"fileName": "b.txt", "relativePath": "dist/b.txt", "size": 5},
+			{"fileName": "c.txt", "relativePath": "c.txt", "size": 5},
+			{"fileName": "d.txt", "relativePath": "d.txt", "size": 5},
+		},
+		"fingerprint": []map[string]any{
+			{"fileName": "a.txt", "hash": md5Hello},
+			{"fileName": "dist/b.txt", "hash": md5World},
+			{"fileName": "d.txt", "hash": md5Hello},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/app/9/artifact/dist/a.txt", http.StatusOK, "hello")
+	server.Handle(http.MethodGet, "/job/app/9/artifact/dist/b.txt", http.StatusOK, "hello")
+	f, stdout, stderr := fakejenkins.Factory(client)
+	return server, f, stdout, stderr
+}
+
+func runArtifact(f *cmdutil.Factory, stdout, stderr *bytes.Buffer, args ...string) error {
+	cmd := NewCmdArtifact(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func writeFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	dest := filepath.Join(dir, filepath.FromSlash(rel))
+	require.NoError(t, os.MkdirAll(filepath.Dir(dest), 0o755))
+	require.NoError(t, os.WriteFile(dest, []byte(content), 0o644))
+}
+
+func TestArtifactListChecksums(t *testing.T) {
+	server, f, stdout, stderr := newChecksumServer(t)
+
+	require.NoError(t, runArtifact(f, stdout, stderr, "ls", "app", "9", "--checksums", "--json"))
+	require.Equal(t, "fingerprint[fileName,hash]", server.LastRequest(http.MethodGet, "/job/app/9/api/json").Query.Get("tree"))
+
+	var items []artifactItem
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &items))
+	got := map[string]string{}
+	for _, item := range items {
+		got[item.RelativePath] = item.MD5
+	}
+	require.Equal(t, map[string]string{"dist/a.txt": md5Hello, "dist/b.txt": md5World, "c.txt": "", "d.txt": md5Hello}, got)
+}
+
+func TestArtifactVerifyReportsEachFile(t *testing.T) {
+	_, f, stdout, stderr := newChecksumServer(t)
+	dir := t.TempDir()
+	writeFile(t, dir, "dist/a.txt", "hello")
+	writeFile(t, dir, "dist/b.txt", "hello")
+	writeFile(t, dir, "c.txt", "hello")
+
+	err := runArtifact(f, stdout, stderr, "verify", "app", "9", "--dir", dir, "--json")
+	requireExitCode(t, err, 2)
+
+	var output artifactVerifyOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	got := map[string]string{}
+	for _, file := range output.Files {
+		got[file.Path] = file.Status
+	}
+	require.Equal(t, map[string]string{
+		"dist/a.txt": verifyMatch,
+		"dist/b.txt": verifyMismatch,
+		"c.txt":      verifyUnverifiable,
+		"d.txt":      verifyMissing,
+	}, got)
+}
+
+func TestArtifactVerifyMissingOnly(t *testing.T) {
+	_, f, stdout, stderr := newChecksumServer(t)
+	dir := t.TempDir()
+	writeFile(t, dir, "dist/a.txt", "hello")
+
+	err := runArtifact(f, stdout, stderr, "verify", "app", "9", "--dir", dir, "--pattern", "{dist/a.txt,d.txt}")
+	requireExitCode(t, err, 3)
+	require.Contains(t, stdout.String(), "match        dist/a.txt")
+	require.Contains(t, stdout.String(), "missing      d.txt")
+}
+
+func TestArtifactDownloadVerify(t *testing.T) {
+	_, f, stdout, stderr := newChecksumServer(t)
+	outDir := t.TempDir()
+
+	err := runArtifact(f, stdout, stderr, "download", "app", "9", "--pattern", "dist/*", "--output", outDir, "--verify")
+	requireExitCode(t, err, 2)
+	require.Contains(t, stdout.String(), "a.txt (md5 match)")
+	require.Contains(t, stdout.String(), "b.txt (md5 mismatch, expected "+md5World+", got "+md5Hello+")")
+
+	data, err := os.ReadFile(filepath.Join(outDir, "dist", "b.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data), "mismatched files are kept for inspection")
+}
@@ -3,15 +3,20 @@ package auth
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/avivsinai/jenkins-cli/internal/config"
 	"github.com/avivsinai/jenkins-cli/internal/secret"
-	"github.com/avivsinai/jenkins-cli/internal/terminal"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 func NewCmdAuth(f *cmdutil.Factory) *cobra.Command {
@@ -38,6 +43,12 @@ type authLoginOptions struct {
 	caFile             string
 	setActive          bool
 	allowInsecureStore bool
+	allowHTTP          bool
+	defaultFolder      string
+	headers            []string
+	// verify, when set, checks the credentials of the new context before
+	// anything is saved; headers carry resolved secret values.
+	verify func(contextName string, ctxDef *config.Context, token string, headers map[string]string) error
 }
 
 func newAuthLoginCmd(f *cmdutil.Factory) *cobra.Command {
@@ -52,7 +63,19 @@ func newAuthLoginCmd(f *cmdutil.Factory) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			return runAuthLogin(cmd, cfg, opts, args[0])
+			ios, err := f.Streams()
+			if err != nil {
+				return err
+			}
+			contextName, err := runAuthLogin(cmd, ios, cfg, opts, args[0])
+			if err != nil {
+				return err
+			}
+			ctxDef, err := cfg.Context(contextName)
+			if err != nil {
+				return err
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "login", Target: contextName, Status: shared.ActionDone}, "Logged in to %s (%s)", ctxDef.URL, contextName)
 		},
 	}
 
@@ -64,33 +87,55 @@ func newAuthLoginCmd(f *cmdutil.Factory) *cobra.Command {
 	cmd.Flags().StringVar(&opts.caFile, "ca-file", "", "Custom CA bundle for TLS verification")
 	cmd.Flags().BoolVar(&opts.setActive, "set-active", true, "Set the context as active after login")
 	cmd.Flags().BoolVar(&opts.allowInsecureStore, "allow-insecure-store", false, "Allow encrypted file-based secret storage")
+	cmd.Flags().BoolVar(&opts.allowHTTP, "allow-http", false, "Accept sending credentials to a plain-HTTP Jenkins URL")
+	cmd.Flags().StringVar(&opts.defaultFolder, "default-folder", "", "Folder that relative job paths resolve against")
+	addHeaderFlag(cmd, &opts.headers)
 
 	return cmd
 }
 
-func runAuthLogin(cmd *cobra.Command, cfg *config.Config, opts *authLoginOptions, rawURL string) error {
+// addHeaderFlag registers the repeatable --header flag of login and init.
+func addHeaderFlag(cmd *cobra.Command, headers *[]string) {
+	cmd.Flags().StringArrayVar(headers, "header", nil, `Extra header sent with every request, "Name: value" (repeatable); a value of "secret:<key>" is kept in the secret store`)
+}
+
+// runAuthLogin stores the context and its token and returns the context
+// name; callers report the result.
+func runAuthLogin(cmd *cobra.Command, ios *iostreams.IOStreams, cfg *config.Config, opts *authLoginOptions, rawURL string) (string, error) {
 	parsed, err := url.Parse(rawURL)
 	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
-		return fmt.Errorf("invalid Jenkins URL %q", rawURL)
+		return "", fmt.Errorf("invalid Jenkins URL %q", rawURL)
 	}
 	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
 
-	contextName := opts.name
-	if contextName == "" {
-		contextName = deriveContextName(parsed)
+	headers, err := parseHeaderFlags(opts.headers)
+	if err != nil {
+		return "", err
+	}
+
+	allowHTTP, err := confirmPlainHTTP(ios, parsed, opts.allowHTTP)
+	if err != nil {
+		return "", err
+	}
+
+	contextName, err := loginContextName(cfg, opts.name, parsed)
+	if err != nil {
+		return "", err
 	}
 
 	username := opts.username
 	if username == "" {
-		if username, err = terminal.Prompt("Username", ""); err != nil {
-			return fmt.Errorf("read username: %w", err)
+		username, err = cmdutil.PromptOrFail(ios, cmdutil.Prompt{Name: "username", Label: "Username", Flag: "--username"})
+		if err != nil {
+			return "", promptError("username", err)
 		}
 	}
 
 	token := opts.token
 	if token == "" {
-		if token, err = terminal.PromptSecret("API token"); err != nil {
-			return fmt.Errorf("read token: %w", err)
+		token, err = cmdutil.PromptOrFail(ios, cmdutil.Prompt{Name: "token", Label: "API token", Flag: "--token", Secret: true})
+		if err != nil {
+			return "", promptError("token", err)
 		}
 	}
 
@@ -101,34 +146,211 @@ func runAuthLogin(cmd *cobra.Command, cfg *config.Config, opts *authLoginOptions
 
 	store, err := secret.Open(storeOpts...)
 	if err != nil {
-		return fmt.Errorf("open secret store: %w", err)
+		return "", fmt.Errorf("open secret store: %w", err)
 	}
 
-	cfg.SetContext(contextName, &config.Context{
+	headerSecrets, err := headerSecretValues(ios, store, contextName, headers)
+	if err != nil {
+		return "", err
+	}
+	var previous *config.Context
+	if existing, err := cfg.Context(contextName); err == nil {
+		previous = existing
+	}
+
+	ctxDef := &config.Context{
 		URL:                parsed.String(),
 		Username:           username,
 		Insecure:           opts.insecure,
 		Proxy:              opts.proxy,
 		CAFile:             opts.caFile,
 		AllowInsecureStore: opts.allowInsecureStore,
-	})
+		AllowHTTP:          allowHTTP,
+		DefaultFolder:      jobpath.Normalize(opts.defaultFolder),
+		ExtraHeaders:       headers,
+	}
+	if opts.verify != nil {
+		resolved, err := resolveHeaderValues(store, contextName, headers, headerSecrets)
+		if err != nil {
+			return "", err
+		}
+		if err := opts.verify(contextName, ctxDef, token, resolved); err != nil {
+			return "", err
+		}
+	}
+
+	cfg.SetContext(contextName, ctxDef)
 
 	if opts.setActive {
 		if err := cfg.SetActive(contextName); err != nil {
-			return fmt.Errorf("set active context: %w", err)
+			return "", fmt.Errorf("set active context: %w", err)
 		}
 	}
 
 	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("save config: %w", err)
+		return "", fmt.Errorf("save config: %w", err)
 	}
 
 	if err := store.Set(secret.TokenKey(contextName), token); err != nil {
-		return fmt.Errorf("store token: %w", err)
+		return "", fmt.Errorf("store token: %w", err)
+	}
+	for key, value := range headerSecrets {
+		if err := store.Set(secret.HeaderKey(contextName, key), value); err != nil {
+			return "", fmt.Errorf("store header secret %s: %w", key, err)
+		}
+	}
+	if previous != nil {
+		refs := headerSecretRefs(headers)
+		for _, key := range previous.HeaderSecretKeys() {
+			if _, ok := refs[key]; !ok {
+				_ = store.Delete(secret.HeaderKey(contextName, key))
+			}
+		}
 	}
 
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s (%s)\n", parsed.String(), contextName)
-	return nil
+	return contextName, nil
+}
+
+// parseHeaderFlags turns "Name: value" flags into the extra_headers map.
+func parseHeaderFlags(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	headers := make(map[string]string, len(values))
+	for _, raw := range values {
+		name, value, ok := strings.Cut(raw, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok {
+			return nil, &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf(`invalid --header %q; expected "Name: value"`, raw)}
+		}
+		if err := config.ValidateHeader(name, value); err != nil {
+			return nil, &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf("invalid --header: %v", err)}
+		}
+		headers[http.CanonicalHeaderKey(name)] = value
+	}
+	return headers, nil
+}
+
+// headerSecretRefs maps the secret keys the headers reference to their
+// header names.
+func headerSecretRefs(headers map[string]string) map[string]string {
+	refs := map[string]string{}
+	for name, value := range headers {
+		if key, ok := config.HeaderSecretRef(value); ok {
+			refs[key] = name
+		}
+	}
+	return refs
+}
+
+// headerSecretValues asks for the value of each "secret:<key>" header the
+// store does not hold yet. Stored values are kept, so logging in again does
+// not ask twice.
+func headerSecretValues(ios *iostreams.IOStreams, store *secret.Store, contextName string, headers map[string]string) (map[string]string, error) {
+	refs := headerSecretRefs(headers)
+	keys := make([]string, 0, len(refs))
+	for key := range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := map[string]string{}
+	for _, key := range keys {
+		if _, err := store.Get(secret.HeaderKey(contextName, key)); err == nil {
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("read header secret %s: %w", key, err)
+		}
+		if ios.GetNeverPrompt() {
+			return nil, &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf("secret %s for header %s is not stored yet; remove --no-input to enter it", key, refs[key])}
+		}
+		value, err := cmdutil.PromptOrFail(ios, cmdutil.Prompt{Name: "header " + refs[key], Label: refs[key] + " value", Flag: "--header", Secret: true})
+		if err != nil {
+			return nil, promptError("header "+refs[key], err)
+		}
+		if err := config.ValidateHeader(refs[key], value); err != nil || strings.HasPrefix(value, config.HeaderSecretPrefix) {
+			return nil, &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf("invalid value for header %s", refs[key])}
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// resolveHeaderValues returns headers with "secret:<key>" values replaced by
+// the value just entered or, failing that, the one already stored.
+func resolveHeaderValues(store *secret.Store, contextName string, headers, entered map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if key, ok := config.HeaderSecretRef(value); ok {
+			if value, ok = entered[key]; !ok {
+				stored, err := store.Get(secret.HeaderKey(contextName, key))
+				if err != nil {
+					return nil, fmt.Errorf("read header secret %s: %w", key, err)
+				}
+				value = stored
+			}
+		}
+		resolved[name] = value
+	}
+	return resolved, nil
+}
+
+// confirmPlainHTTP requires an explicit acknowledgment before credentials for
+// an http:// URL are stored, either --allow-http or an interactive yes. It
+// reports whether the context should record allow_http.
+func confirmPlainHTTP(ios *iostreams.IOStreams, u *url.URL, allowed bool) (bool, error) {
+	if !strings.EqualFold(u.Scheme, "http") {
+		return false, nil
+	}
+	if allowed {
+		return true, nil
+	}
+	refusal := &cmdutil.ExitError{
+		Code: 2,
+		Msg:  fmt.Sprintf("refusing to store credentials for %s: the URL uses plain HTTP; pass --allow-http to accept", u.String()),
+	}
+	if !ios.CanPrompt() {
+		return false, refusal
+	}
+	ok, err := cmdutil.ConfirmOrFail(ios, fmt.Sprintf("%s uses plain HTTP; your API token will be sent unencrypted. Continue?", u.String()), "--allow-http")
+	if err != nil {
+		return false, promptError("confirmation", err)
+	}
+	if !ok {
+		return false, refusal
+	}
+	return true, nil
+}
+
+// promptError keeps --no-input failures as-is and wraps read errors.
+func promptError(name string, err error) error {
+	var exitErr *cmdutil.ExitError
+	if errors.As(err, &exitErr) {
+		return err
+	}
+	return fmt.Errorf("read %s: %w", name, err)
+}
+
+// loginContextName picks the name of the context a login writes: --name or
+// the Jenkins hostname, normalized to lowercase. Logging in again under an
+// existing context's exact name updates it; a new name that differs from an
+// existing one only by case is refused.
+func loginContextName(cfg *config.Config, name string, u *url.URL) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = deriveContextName(u)
+	}
+	if _, err := cfg.Context(name); err == nil {
+		return name, nil
+	}
+	name = config.NormalizeContextName(name)
+	if existing := cfg.CaseCollision(name); existing != "" {
+		return "", &cmdutil.ExitError{
+			Code: 2,
+			Msg:  fmt.Sprintf("context %q collides with existing context %q: context names are case-insensitive; pass --name %s to update it or choose another name", name, existing, existing),
+		}
+	}
+	return name, nil
 }
 
 func deriveContextName(u *url.URL) string {
@@ -191,9 +413,13 @@ func newAuthLogoutCmd(f *cmdutil.Factory) *cobra.Command {
 			if err := store.Delete(secret.TokenKey(contextName)); err != nil {
 				return fmt.Errorf("delete token: %w", err)
 			}
+			for _, key := range ctxDef.HeaderSecretKeys() {
+				if err := store.Delete(secret.HeaderKey(contextName, key)); err != nil {
+					return fmt.Errorf("delete header secret: %w", err)
+				}
+			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Logged out of context %s\n", contextName)
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "logout", Target: contextName, Status: shared.ActionDone}, "Logged out of context %s", contextName)
 		},
 	}
 
@@ -216,15 +442,44 @@ func newAuthStatusCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 
-			if ctx == nil {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No active context")
-				return nil
+			output := authStatusOutput{}
+			if ctx != nil {
+				output = authStatusOutput{
+					Context:   name,
+					URL:       ctx.URL,
+					Username:  ctx.Username,
+					PlainHTTP: strings.HasPrefix(strings.ToLower(ctx.URL), "http://"),
+					Headers:   ctx.HeaderNames(),
+				}
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Active context: %s\n", name)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "URL: %s\n", ctx.URL)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Username: %s\n", ctx.Username)
-			return nil
+			return shared.PrintOutput(cmd, output, func() error {
+				if ctx == nil {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No active context")
+					return nil
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Active context: %s\n", name)
+				if output.PlainHTTP {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "URL: %s (plain HTTP; credentials are sent unencrypted)\n", ctx.URL)
+				} else {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "URL: %s\n", ctx.URL)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Username: %s\n", ctx.Username)
+				if len(output.Headers) > 0 {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Extra headers: %s\n", strings.Join(output.Headers, ", "))
+				}
+				return nil
+			})
 		},
 	}
 }
+
+// authStatusOutput is empty when no context is active.
+type authStatusOutput struct {
+	Context   string `json:"context,omitempty"`
+	URL       string `json:"url,omitempty"`
+	Username  string `json:"username,omitempty"`
+	PlainHTTP bool   `json:"plainHttp,omitempty"`
+	// Headers names the context's extra headers; values are never shown.
+	Headers []string `json:"headers,omitempty"`
+}
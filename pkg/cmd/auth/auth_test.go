@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestAuthLoginNoInputFailsFast(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		msg  string
+	}{
+		{"missing username", []string{"https://jenkins.example", "--token", "abc"}, "username required; pass --username or remove --no-input"},
+		{"missing token", []string{"https://jenkins.example", "--username", "jane"}, "token required; pass --token or remove --no-input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, stdin, _, _ := iostreams.Test()
+			ios.SetStdinTTY(true)
+			ios.SetNeverPrompt(true)
+			stdin.WriteString("should-not-be-read\n")
+
+			f := &cmdutil.Factory{
+				IOStreams: ios,
+				Config: func() (*config.Config, error) {
+					return &config.Config{Contexts: map[string]*config.Context{}}, nil
+				},
+			}
+
+			cmd := newAuthLoginCmd(f)
+			cmd.SetArgs(tt.args)
+			cmd.SetOut(ios.Out)
+			cmd.SetErr(ios.ErrOut)
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			err := cmd.Execute()
+			var exitErr *cmdutil.ExitError
+			require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+			require.Equal(t, 2, exitErr.Code)
+			require.Equal(t, tt.msg, exitErr.Msg)
+			require.Equal(t, "should-not-be-read\n", stdin.String())
+		})
+	}
+}
+
+func TestAuthLoginKeepsContextPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "authtest")
+
+	for _, rawURL := range []string{"https://ci.example.com/jenkins", "https://ci.example.com/jenkins/"} {
+		ios, _, _, _ := iostreams.Test()
+		cfg := &config.Config{Contexts: map[string]*config.Context{}}
+		f := &cmdutil.Factory{
+			IOStreams: ios,
+			Config:    func() (*config.Config, error) { return cfg, nil },
+		}
+
+		cmd := newAuthLoginCmd(f)
+		cmd.SetArgs([]string{rawURL, "--name", "ci", "--username", "jane", "--token", "abc", "--allow-insecure-store"})
+		cmd.SetOut(ios.Out)
+		cmd.SetErr(ios.ErrOut)
+		require.NoError(t, cmd.Execute())
+
+		ctx, err := cfg.Context("ci")
+		require.NoError(t, err)
+		require.Equal(t, "https://ci.example.com/jenkins", ctx.URL, rawURL)
+	}
+}
+
+func TestAuthLoginPlainHTTP(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "authtest")
+
+	run := func(t *testing.T, stdinTTY bool, input string, args ...string) (*config.Config, error) {
+		ios, stdin, _, _ := iostreams.Test()
+		ios.SetStdinTTY(stdinTTY)
+		ios.SetStdoutTTY(stdinTTY)
+		stdin.WriteString(input)
+		cfg := &config.Config{Contexts: map[string]*config.Context{}}
+		f := &cmdutil.Factory{
+			IOStreams: ios,
+			Config:    func() (*config.Config, error) { return cfg, nil },
+		}
+
+		cmd := newAuthLoginCmd(f)
+		cmd.SetArgs(append([]string{"http://ci.example.com", "--name", "ci", "--username", "jane", "--token", "abc", "--allow-insecure-store"}, args...))
+		cmd.SetOut(ios.Out)
+		cmd.SetErr(ios.ErrOut)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return cfg, cmd.Execute()
+	}
+
+	t.Run("refused without flag", func(t *testing.T) {
+		cfg, err := run(t, false, "")
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+		require.Equal(t, 2, exitErr.Code)
+		require.Contains(t, exitErr.Msg, "--allow-http")
+		require.Empty(t, cfg.Contexts)
+	})
+
+	t.Run("declined interactively", func(t *testing.T) {
+		cfg, err := run(t, true, "n\n")
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+		require.Equal(t, 2, exitErr.Code)
+		require.Empty(t, cfg.Contexts)
+	})
+
+	for name, tc := range map[string]struct {
+		tty   bool
+		input string
+		args  []string
+	}{
+		"accepted with flag":      {args: []string{"--allow-http"}},
+		"confirmed interactively": {tty: true, input: "y\n"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := run(t, tc.tty, tc.input, tc.args...)
+			require.NoError(t, err)
+			ctx, err := cfg.Context("ci")
+			require.NoError(t, err)
+			require.True(t, ctx.AllowHTTP)
+		})
+	}
+}
+
+func TestAuthLoginContextNameCase(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "authtest")
+
+	login := func(cfg *config.Config, name string) error {
+		ios, _, _, _ := iostreams.Test()
+		f := &cmdutil.Factory{
+			IOStreams: ios,
+			Config:    func() (*config.Config, error) { return cfg, nil },
+		}
+		cmd := newAuthLoginCmd(f)
+		cmd.SetArgs([]string{"https://ci.example.com", "--name", name, "--username", "jane", "--token", "abc", "--allow-insecure-store"})
+		cmd.SetOut(ios.Out)
+		cmd.SetErr(ios.ErrOut)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return cmd.Execute()
+	}
+
+	t.Run("new names are normalized", func(t *testing.T) {
+		cfg := &config.Config{Contexts: map[string]*config.Context{}}
+		require.NoError(t, login(cfg, "  Prod "))
+		_, err := cfg.Context("prod")
+		require.NoError(t, err)
+		require.Equal(t, "prod", cfg.Active)
+	})
+
+	t.Run("collision is refused", func(t *testing.T) {
+		cfg := &config.Config{Contexts: map[string]*config.Context{"Prod": {URL: "https://old.example.com"}}}
+		err := login(cfg, "PROD")
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+		require.Equal(t, 2, exitErr.Code)
+		require.Contains(t, exitErr.Msg, `"prod"`)
+		require.Contains(t, exitErr.Msg, `"Prod"`)
+		require.Len(t, cfg.Contexts, 1)
+		require.Equal(t, "https://old.example.com", cfg.Contexts["Prod"].URL)
+	})
+
+	t.Run("exact name updates the existing context", func(t *testing.T) {
+		cfg := &config.Config{Contexts: map[string]*config.Context{"Prod": {URL: "https://old.example.com"}}}
+		require.NoError(t, login(cfg, "Prod"))
+		require.Len(t, cfg.Contexts, 1)
+		require.Equal(t, "https://ci.example.com", cfg.Contexts["Prod"].URL)
+	})
+}
+
+func TestAuthLoginExtraHeaders(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "authtest")
+
+	ios, stdin, stdout, _ := iostreams.Test()
+	ios.SetStdinTTY(true)
+	stdin.WriteString("gateway-secret\n")
+	cfg := &config.Config{Contexts: map[string]*config.Context{}}
+	f := &cmdutil.Factory{
+		IOStreams: ios,
+		Config:    func() (*config.Config, error) { return cfg, nil },
+	}
+
+	login := newAuthLoginCmd(f)
+	login.SetArgs([]string{"https://ci.example.com", "--name", "ci", "--username", "jane", "--token", "abc", "--allow-insecure-store",
+		"--header", "X-Org-Token: secret:org", "--header", "x-gateway-env: prod"})
+	login.SetOut(ios.Out)
+	login.SetErr(ios.ErrOut)
+	require.NoError(t, login.Execute())
+
+	ctx, err := cfg.Context("ci")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"X-Org-Token": "secret:org", "X-Gateway-Env": "prod"}, ctx.ExtraHeaders)
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	require.NoError(t, err)
+	value, err := store.Get(secret.HeaderKey("ci", "org"))
+	require.NoError(t, err)
+	require.Equal(t, "gateway-secret", value)
+
+	path, err := config.DefaultPath()
+	require.NoError(t, err)
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(saved), "X-Org-Token: secret:org")
+	require.NotContains(t, string(saved), "gateway-secret")
+
+	stdout.Reset()
+	status := newAuthStatusCmd(f)
+	status.SetOut(ios.Out)
+	require.NoError(t, status.Execute())
+	require.Contains(t, stdout.String(), "Extra headers: X-Gateway-Env, X-Org-Token")
+	require.NotContains(t, stdout.String(), "gateway-secret")
+
+	logout := newAuthLogoutCmd(f)
+	logout.SetArgs([]string{"--context", "ci"})
+	logout.SetOut(ios.Out)
+	require.NoError(t, logout.Execute())
+	_, err = store.Get(secret.HeaderKey("ci", "org"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestAuthLoginRejectsInvalidHeaders(t *testing.T) {
+	for _, header := range []string{"Authorization: Bearer x", "no colon", "Bad Name: x", "X-Org-Token: secret:"} {
+		_, err := parseHeaderFlags([]string{header})
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "%q: expected exit error, got %v", header, err)
+		require.Equal(t, 2, exitErr.Code)
+	}
+}
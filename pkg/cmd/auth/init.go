@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+// initProbeTimeout bounds the reachability check of the entered URL.
+var initProbeTimeout = 10 * time.Second
+
+// NewCmdInit returns `jk init`, the first-run wizard. It gathers what
+// `jk auth login` takes as flags, one prompt at a time, and then logs in
+// through the same code path.
+func NewCmdInit(f *cmdutil.Factory) *cobra.Command {
+	opts := &authLoginOptions{setActive: true}
+	var rawURL string
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Set up jk interactively",
+		Long: `Walk through first-time setup: the Jenkins URL (checked for reachability and
+a trusted TLS certificate), your username and API token, where the token is
+stored, and the context name. The new context becomes active and is verified
+with an authenticated request.
+
+Every prompt can be answered with a flag instead; with --no-input a missing
+answer fails and names the flag to pass.`,
+		Example: `  jk init
+  jk init --url https://jenkins.example.com --username jane --token "$JENKINS_TOKEN" --no-input`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := f.ResolveConfig()
+			if err != nil {
+				return err
+			}
+			ios, err := f.Streams()
+			if err != nil {
+				return err
+			}
+			return runInit(cmd, f, ios, cfg, opts, rawURL)
+		},
+	}
+
+	cmd.Flags().StringVar(&rawURL, "url", "", "Jenkins URL")
+	cmd.Flags().StringVar(&opts.name, "name", "", "Context name (defaults to Jenkins hostname)")
+	cmd.Flags().StringVar(&opts.username, "username", "", "Jenkins username")
+	cmd.Flags().StringVar(&opts.token, "token", "", "Jenkins API token")
+	cmd.Flags().BoolVar(&opts.insecure, "insecure", false, "Skip TLS certificate verification")
+	cmd.Flags().StringVar(&opts.proxy, "proxy", "", "Proxy URL for this context")
+	cmd.Flags().StringVar(&opts.caFile, "ca-file", "", "Custom CA bundle for TLS verification")
+	cmd.Flags().BoolVar(&opts.allowInsecureStore, "allow-insecure-store", false, "Store the token in an encrypted file when no OS keyring is available")
+	cmd.Flags().BoolVar(&opts.allowHTTP, "allow-http", false, "Accept sending credentials to a plain-HTTP Jenkins URL")
+	cmd.Flags().StringVar(&opts.defaultFolder, "default-folder", "", "Folder that relative job paths resolve against")
+	addHeaderFlag(cmd, &opts.headers)
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		2: "A required answer is missing with --no-input, or no secret store was accepted",
+		4: "Jenkins rejected the username or token",
+	})
+	return cmd
+}
+
+func runInit(cmd *cobra.Command, f *cmdutil.Factory, ios *iostreams.IOStreams, cfg *config.Config, opts *authLoginOptions, rawURL string) error {
+	errOut := cmd.ErrOrStderr()
+
+	if strings.TrimSpace(rawURL) == "" {
+		var err error
+		rawURL, err = cmdutil.PromptOrFail(ios, cmdutil.Prompt{Name: "url", Label: "Jenkins URL", Flag: "--url"})
+		if err != nil {
+			return promptError("url", err)
+		}
+	}
+	u, err := parseInitURL(rawURL)
+	if err != nil {
+		return err
+	}
+
+	if opts.allowHTTP, err = confirmPlainHTTP(ios, u, opts.allowHTTP); err != nil {
+		return err
+	}
+	headers, err := parseHeaderFlags(opts.headers)
+	if err != nil {
+		return err
+	}
+	version, err := probeJenkins(cmd.Context(), u, opts, headers)
+	if err != nil {
+		return err
+	}
+	if version == "" {
+		cmdutil.Warnf(cmd, "%s answered without an X-Jenkins header; check that it is the Jenkins root URL", u)
+	} else {
+		_, _ = fmt.Fprintf(errOut, "Found Jenkins %s at %s\n", version, u)
+	}
+
+	if strings.TrimSpace(opts.name) == "" {
+		name, err := cmdutil.PromptOrFail(ios, cmdutil.Prompt{Name: "context name", Label: "Context name", Flag: "--name", Default: deriveContextName(u)})
+		if err != nil {
+			return promptError("context name", err)
+		}
+		opts.name = name
+	}
+	contextName, err := loginContextName(cfg, opts.name, u)
+	if err != nil {
+		return err
+	}
+	opts.name = contextName
+
+	if strings.TrimSpace(opts.username) == "" {
+		opts.username, err = cmdutil.PromptOrFail(ios, cmdutil.Prompt{Name: "username", Label: "Username", Flag: "--username"})
+		if err != nil {
+			return promptError("username", err)
+		}
+	}
+	if opts.token == "" {
+		_, _ = fmt.Fprintf(errOut, "Create an API token at %s (API Token > Add new Token)\n", tokenPageURL(u, opts.username))
+		opts.token, err = cmdutil.PromptOrFail(ios, cmdutil.Prompt{Name: "token", Label: "API token", Flag: "--token", Secret: true})
+		if err != nil {
+			return promptError("token", err)
+		}
+	}
+
+	if err := chooseSecretStore(ios, errOut, opts); err != nil {
+		return err
+	}
+
+	opts.verify = func(name string, ctxDef *config.Context, token string, headers map[string]string) error {
+		return verifyInitLogin(cmd, name, ctxDef, token, headers, u)
+	}
+	if _, err := runAuthLogin(cmd, ios, cfg, opts, u.String()); err != nil {
+		return err
+	}
+
+	return shared.PrintOutput(cmd, shared.ActionResult{Action: "init", Target: contextName, Status: shared.ActionDone}, func() error {
+		out := cmd.OutOrStdout()
+		_, _ = fmt.Fprintf(out, "Logged in to %s (%s)\n", u, contextName)
+		_, _ = fmt.Fprintf(out, "Context %s is active. Next, try:\n  jk job ls\n  jk run ls <jobPath>\n", contextName)
+		return nil
+	})
+}
+
+// parseInitURL accepts a bare host name as https and drops a trailing slash.
+func parseInitURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf("invalid Jenkins URL %q", strings.TrimSpace(raw))}
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawQuery, u.Fragment = "", ""
+	return u, nil
+}
+
+func tokenPageURL(u *url.URL, username string) string {
+	return fmt.Sprintf("%s/user/%s/configure", u.String(), url.PathEscape(username))
+}
+
+// probeJenkins makes one anonymous request to the login page with the TLS and
+// proxy settings the context will use, and returns the X-Jenkins version. Any
+// HTTP answer counts as reachable; certificate failures explain --ca-file
+// and --insecure. Extra headers with literal values are sent so a gateway
+// lets the probe through; secret ones are not known yet.
+func probeJenkins(ctx context.Context, u *url.URL, opts *authLoginOptions, headers map[string]string) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.proxy != "" {
+		if _, err := url.Parse(opts.proxy); err != nil {
+			return "", fmt.Errorf("invalid --proxy: %w", err)
+		}
+	}
+	literal := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if _, ok := config.HeaderSecretRef(value); !ok {
+			literal[name] = value
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, initProbeTimeout)
+	defer cancel()
+	version, err := jenkins.Probe(ctx, &config.Context{URL: u.String(), Insecure: opts.insecure, Proxy: opts.proxy, CAFile: opts.caFile, AllowHTTP: true}, literal)
+	if err != nil {
+		if isCertificateError(err) {
+			return "", fmt.Errorf("cannot verify the TLS certificate of %s: %w\npass --ca-file <bundle.pem> with the CA that signed it, or --insecure to skip verification (not recommended)", u.Host, err)
+		}
+		return "", fmt.Errorf("cannot reach %s: %w", u, err)
+	}
+	return version, nil
+}
+
+func isCertificateError(err error) bool {
+	var (
+		unknownAuthority x509.UnknownAuthorityError
+		hostname         x509.HostnameError
+		invalid          x509.CertificateInvalidError
+		verification     *tls.CertificateVerificationError
+	)
+	return errors.As(err, &unknownAuthority) || errors.As(err, &hostname) ||
+		errors.As(err, &invalid) || errors.As(err, &verification)
+}
+
+// chooseSecretStore explains the encrypted file store before the token is
+// saved when no OS keyring is available, since that store needs a
+// passphrase on every later command.
+func chooseSecretStore(ios *iostreams.IOStreams, errOut io.Writer, opts *authLoginOptions) error {
+	if opts.allowInsecureStore || secret.NativeAvailable() {
+		return nil
+	}
+	unlock := fmt.Sprintf("set %s for non-interactive use, or jk asks for it whenever a command needs the token", secret.PassphraseEnv)
+	if os.Getenv(secret.PassphraseEnv) != "" {
+		unlock = fmt.Sprintf("%s is set, so jk will use it", secret.PassphraseEnv)
+	}
+	_, _ = fmt.Fprintf(errOut, "No OS keyring is available. jk can keep the token in an encrypted file instead, locked with a passphrase: %s.\n", unlock)
+	ok, err := cmdutil.ConfirmOrFail(ios, "Store the token in an encrypted file?", "--allow-insecure-store")
+	if err != nil {
+		return promptError("confirmation", err)
+	}
+	if !ok {
+		return &cmdutil.ExitError{Code: 2, Msg: "no secret store for the token: unlock or install an OS keyring, or rerun with --allow-insecure-store"}
+	}
+	opts.allowInsecureStore = true
+	return nil
+}
+
+// verifyInitLogin makes the first authenticated request with the new
+// context before it is saved, so a mistyped token is reported without
+// leaving a broken context behind.
+func verifyInitLogin(cmd *cobra.Command, contextName string, ctxDef *config.Context, token string, headers map[string]string, u *url.URL) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client, err := jenkins.NewUnsavedClient(ctx, contextName, ctxDef, token, headers)
+	if err != nil {
+		return err
+	}
+
+	identity, err := client.WhoAmI(ctx)
+	if err == nil && identity.Anonymous {
+		err = fmt.Errorf("authenticated as anonymous: %w", jenkins.ErrUnauthorized)
+	}
+	if errors.Is(err, jenkins.ErrUnauthorized) {
+		return &cmdutil.ExitError{
+			Code: 4,
+			Msg:  fmt.Sprintf("Jenkins rejected the token for %s (%v); nothing was saved. Create a new one at %s and run `jk init` again", ctxDef.Username, err, tokenPageURL(u, ctxDef.Username)),
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("verify login: %w", err)
+	}
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Authenticated as %s\n", identity.ID)
+	return nil
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func newInitServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Jenkins", "2.462.1")
+		switch r.URL.Path {
+		case "/login":
+			w.WriteHeader(http.StatusOK)
+		case "/whoAmI/api/json":
+			if user, token, ok := r.BasicAuth(); !ok || user != "jane" || token != "s3cret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"name":"jane","anonymous":false}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caFile, cert, 0o600))
+	return server, caFile
+}
+
+func runInitCmd(t *testing.T, ios *iostreams.IOStreams, cfg *config.Config, args ...string) error {
+	t.Helper()
+	f := &cmdutil.Factory{
+		IOStreams: ios,
+		Config:    func() (*config.Config, error) { return cfg, nil },
+	}
+	cmd := NewCmdInit(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(ios.Out)
+	cmd.SetErr(ios.ErrOut)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func TestInitWizardHappyPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "inittest")
+	server, caFile := newInitServer(t)
+
+	ios, stdin, stdout, stderr := iostreams.Test()
+	ios.SetStdinTTY(true)
+	// URL, context name (accept the default), username, token, file store.
+	stdin.WriteString(server.URL + "/\n\njane\ns3cret\ny\n")
+	cfg := &config.Config{Contexts: map[string]*config.Context{}}
+
+	err := runInitCmd(t, ios, cfg, "--ca-file", caFile)
+	require.NoError(t, err, stderr.String())
+
+	ctx, err := cfg.Context("127-0-0-1")
+	require.NoError(t, err)
+	require.Equal(t, server.URL, ctx.URL)
+	require.Equal(t, "jane", ctx.Username)
+	require.Equal(t, caFile, ctx.CAFile)
+	require.True(t, ctx.AllowInsecureStore)
+	require.Equal(t, "127-0-0-1", cfg.Active)
+
+	require.Contains(t, stderr.String(), "Found Jenkins 2.462.1")
+	require.Contains(t, stderr.String(), server.URL+"/user/jane/configure")
+	require.Contains(t, stderr.String(), "JK_KEYRING_PASSPHRASE is set")
+	require.Contains(t, stderr.String(), "Authenticated as jane")
+	require.Contains(t, stdout.String(), "Context 127-0-0-1 is active")
+	require.Empty(t, stdin.String(), "every scripted answer should be consumed")
+}
+
+func TestInitWizardRejectedToken(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "inittest")
+	server, caFile := newInitServer(t)
+
+	ios, _, _, _ := iostreams.Test()
+	cfg := &config.Config{
+		Active:   "prod",
+		Contexts: map[string]*config.Context{"prod": {URL: "https://prod.example.com", Username: "jane"}},
+	}
+	err := runInitCmd(t, ios, cfg, "--url", server.URL, "--name", "ci", "--username", "jane", "--token", "wrong",
+		"--ca-file", caFile, "--allow-insecure-store")
+
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, 4, exitErr.Code)
+	require.Contains(t, exitErr.Msg, server.URL+"/user/jane/configure")
+
+	// Nothing is persisted for rejected credentials.
+	_, err = cfg.Context("ci")
+	require.Error(t, err)
+	require.Equal(t, "prod", cfg.Active)
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	require.NoError(t, err)
+	_, err = store.Get(secret.TokenKey("ci"))
+	require.Error(t, err)
+}
+
+func TestInitWizardExplainsCertificateErrors(t *testing.T) {
+	server, _ := newInitServer(t)
+	ios, _, _, _ := iostreams.Test()
+	cfg := &config.Config{Contexts: map[string]*config.Context{}}
+
+	err := runInitCmd(t, ios, cfg, "--url", server.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "--ca-file")
+	require.Contains(t, err.Error(), "--insecure")
+}
+
+func TestInitWizardNoInputNamesFlags(t *testing.T) {
+	server, caFile := newInitServer(t)
+	tests := []struct {
+		args []string
+		msg  string
+	}{
+		{nil, "url required; pass --url or remove --no-input"},
+		{[]string{"--url", server.URL, "--ca-file", caFile}, "context name required; pass --name or remove --no-input"},
+		{[]string{"--url", server.URL, "--ca-file", caFile, "--name", "ci"}, "username required; pass --username or remove --no-input"},
+		{[]string{"--url", server.URL, "--ca-file", caFile, "--name", "ci", "--username", "jane"}, "token required; pass --token or remove --no-input"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Fields(tt.msg)[0], func(t *testing.T) {
+			ios, stdin, _, _ := iostreams.Test()
+			ios.SetStdinTTY(true)
+			ios.SetNeverPrompt(true)
+			stdin.WriteString("should-not-be-read\n")
+			cfg := &config.Config{Contexts: map[string]*config.Context{}}
+
+			err := runInitCmd(t, ios, cfg, tt.args...)
+			var exitErr *cmdutil.ExitError
+			require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+			require.Equal(t, 2, exitErr.Code)
+			require.Equal(t, tt.msg, exitErr.Msg)
+			require.Equal(t, "should-not-be-read\n", stdin.String())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+type configValidateOutput struct {
+	SchemaVersion string           `json:"schemaVersion"`
+	File          string           `json:"file"`
+	Valid         bool             `json:"valid"`
+	Findings      []config.Finding `json:"findings"`
+}
+
+func NewCmdConfig(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the jk configuration file",
+	}
+
+	cmd.AddCommand(newConfigValidateCmd(f))
+	return cmd
+}
+
+func newConfigValidateCmd(_ *cmdutil.Factory) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config file for unknown keys and invalid values",
+		Long: `Decode the config file strictly and report every problem with its line:
+unknown keys (usually typos, which a normal load ignores), type mismatches,
+and values that cannot work, such as an unparsable context URL, a missing
+ca_file, an invalid rate_limit, or an active context that is not defined.
+
+Without --file the config jk loads is checked. The command exits 2 when it
+finds any problem, so it can guard a checked-in config template in CI.`,
+		Example: `  jk config validate
+  jk config validate --file deploy/jk-config.yaml --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := file
+			if path == "" {
+				found, exists, err := config.FilePath()
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return shared.NewExitError(3, fmt.Sprintf("no config file at %s", found))
+				}
+				path = found
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return shared.NewExitError(3, fmt.Sprintf("config file %s not found", path))
+				}
+				return fmt.Errorf("read config: %w", err)
+			}
+
+			findings := config.Validate(data, config.ValidateOptions{
+				RateLimit: func(spec string) error {
+					_, err := jenkins.ParseRateLimit(spec)
+					return err
+				},
+			})
+			output := configValidateOutput{SchemaVersion: "1.0", File: path, Valid: len(findings) == 0, Findings: findings}
+			if output.Findings == nil {
+				output.Findings = []config.Finding{}
+			}
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				if output.Valid {
+					_, _ = fmt.Fprintf(w, "%s: no problems found\n", path)
+					return nil
+				}
+				for _, finding := range findings {
+					if finding.Line > 0 {
+						_, _ = fmt.Fprintf(w, "%s:%d: %s\n", path, finding.Line, finding.Message)
+						continue
+					}
+					_, _ = fmt.Fprintf(w, "%s: %s\n", path, finding.Message)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			if !output.Valid {
+				return shared.NewExitError(2, fmt.Sprintf("%d problem(s) in %s", len(findings), path))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Config file to check instead of the one jk loads")
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		2: "The config file has at least one problem",
+		3: "The config file does not exist",
+	})
+	return cmd
+}
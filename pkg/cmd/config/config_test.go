@@ -0,0 +1,64 @@
+package configcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func runConfigValidate(t *testing.T, args ...string) (*bytes.Buffer, error) {
+	t.Helper()
+	ios, _, stdout, stderr := iostreams.Test()
+	cmd := NewCmdConfig(&cmdutil.Factory{IOStreams: ios})
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(append([]string{"validate"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return stdout, cmd.Execute()
+}
+
+func requireExitCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, code, exitErr.Code)
+}
+
+func TestConfigValidate(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.yaml")
+	require.NoError(t, os.WriteFile(good, []byte("active: prod\ncontexts:\n  prod:\n    url: https://ci.example.com\n    rate_limit: 10/s\n"), 0o600))
+	bad := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("active: prod\ncontexts:\n  prod:\n    url: https://ci.example.com\n    rate_limit: lots\n    usrname: me\n"), 0o600))
+
+	stdout, err := runConfigValidate(t, "--file", good)
+	require.NoError(t, err)
+	require.Equal(t, good+": no problems found\n", stdout.String())
+
+	stdout, err = runConfigValidate(t, "--file", bad)
+	requireExitCode(t, err, 2)
+	require.Contains(t, stdout.String(), bad+":5: context \"prod\": ")
+	require.Contains(t, stdout.String(), bad+":6: unknown key \"usrname\" in a context")
+
+	stdout, err = runConfigValidate(t, "--file", bad, "--json")
+	requireExitCode(t, err, 2)
+	var output configValidateOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+	require.False(t, output.Valid)
+	require.Len(t, output.Findings, 2)
+	require.Equal(t, "contexts.prod.rate_limit", output.Findings[0].Key)
+
+	_, err = runConfigValidate(t, "--file", filepath.Join(dir, "missing.yaml"))
+	requireExitCode(t, err, 3)
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/avivsinai/jenkins-cli/internal/config"
 	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 func NewCmdContext(f *cmdutil.Factory) *cobra.Command {
@@ -22,6 +24,11 @@ func NewCmdContext(f *cmdutil.Factory) *cobra.Command {
 		newContextListCmd(f),
 		newContextUseCmd(f),
 		newContextRemoveCmd(f),
+		newContextSetFolderCmd(f),
+		newContextSetDefaultCmd(f),
+		newContextUnsetDefaultCmd(f),
+		newContextRulesCmd(f),
+		newContextPingCmd(f),
 	)
 
 	return cmd
@@ -38,30 +45,47 @@ func newContextListCmd(f *cmdutil.Factory) *cobra.Command {
 			}
 
 			cfgContexts := cfg.Contexts
-			if len(cfgContexts) == 0 {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No contexts configured")
-				return nil
-			}
-
 			names := make([]string, 0, len(cfgContexts))
 			for name := range cfgContexts {
 				names = append(names, name)
 			}
 			sort.Strings(names)
 
-			active := cfg.Active
+			items := make([]contextListItem, 0, len(names))
 			for _, name := range names {
-				prefix := " "
-				if name == active {
-					prefix = "*"
-				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\t%s\n", prefix, name, cfgContexts[name].URL)
+				ctxDef := cfgContexts[name]
+				items = append(items, contextListItem{Name: name, URL: ctxDef.URL, DefaultFolder: ctxDef.DefaultFolder, Active: name == cfg.Active})
 			}
-			return nil
+
+			return shared.PrintOutput(cmd, items, func() error {
+				if len(items) == 0 {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No contexts configured")
+					return nil
+				}
+				for _, item := range items {
+					prefix := " "
+					if item.Active {
+						prefix = "*"
+					}
+					if item.DefaultFolder != "" {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\t%s\tfolder=%s\n", prefix, item.Name, item.URL, item.DefaultFolder)
+						continue
+					}
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\t%s\n", prefix, item.Name, item.URL)
+				}
+				return nil
+			})
 		},
 	}
 }
 
+type contextListItem struct {
+	Name          string `json:"name"`
+	URL           string `json:"url"`
+	DefaultFolder string `json:"defaultFolder,omitempty"`
+	Active        bool   `json:"active"`
+}
+
 func newContextUseCmd(f *cmdutil.Factory) *cobra.Command {
 	return &cobra.Command{
 		Use:   "use <name>",
@@ -85,8 +109,7 @@ func newContextUseCmd(f *cmdutil.Factory) *cobra.Command {
 				return fmt.Errorf("save config: %w", err)
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %s\n", name)
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "use", Target: name, Status: shared.ActionDone}, "Switched to context %s", name)
 		},
 	}
 }
@@ -129,9 +152,88 @@ func newContextRemoveCmd(f *cmdutil.Factory) *cobra.Command {
 			if err := store.Delete(secret.TokenKey(name)); err != nil {
 				return fmt.Errorf("delete token: %w", err)
 			}
+			for _, key := range ctxDef.HeaderSecretKeys() {
+				if err := store.Delete(secret.HeaderKey(name, key)); err != nil {
+					return fmt.Errorf("delete header secret: %w", err)
+				}
+			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed context %s\n", name)
-			return nil
+			for _, rule := range cfg.ContextRules {
+				if rule.Context == name {
+					cmdutil.Warnf(cmd, "context rule %s/ still routes to %s; run jk context rules rm %s", rule.Prefix, name, rule.Prefix)
+				}
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "delete", Target: name, Status: shared.ActionDone}, "Removed context %s", name)
 		},
 	}
 }
+
+func newContextSetFolderCmd(f *cmdutil.Factory) *cobra.Command {
+	var unset bool
+
+	cmd := &cobra.Command{
+		Use:   "set-folder [folder]",
+		Short: "Set the folder relative job paths resolve against",
+		Long: `Set the default folder for a context. Job path arguments are tried under
+this folder first and fall back to the literal path; prefix a path with "/"
+to skip the folder, or pass --absolute to try the literal path first.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if unset == (len(args) == 1) {
+				return shared.NewExitError(2, "pass a folder or --unset")
+			}
+
+			cfg, name, ctxDef, err := currentContext(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			folder := ""
+			if len(args) == 1 {
+				folder = jobpath.Normalize(args[0])
+				if folder == "" {
+					return shared.NewExitError(2, "folder must not be empty; use --unset to clear it")
+				}
+			}
+			ctxDef.DefaultFolder = folder
+
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+
+			if folder == "" {
+				return shared.PrintAction(cmd, shared.ActionResult{Action: "unset-folder", Target: name, Status: shared.ActionDone}, "Cleared default folder for context %s", name)
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "set-folder", Target: name, Status: shared.ActionDone}, "Default folder for context %s set to %s", name, folder)
+		},
+	}
+
+	cmd.Flags().BoolVar(&unset, "unset", false, "Clear the default folder")
+	return cmd
+}
+
+// currentContext loads the config and the context selected by --context,
+// JK_CONTEXT, or the active context.
+func currentContext(cmd *cobra.Command, f *cmdutil.Factory) (*config.Config, string, *config.Context, error) {
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	name, err := shared.ResolveContextName(cmd, cfg)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if name == "" {
+		return nil, "", nil, errors.New("no active context; run `jk auth login` first")
+	}
+
+	ctxDef, err := cfg.Context(name)
+	if err != nil {
+		if errors.Is(err, config.ErrContextNotFound) {
+			return nil, "", nil, fmt.Errorf("context %q not found", name)
+		}
+		return nil, "", nil, err
+	}
+	return cfg, name, ctxDef, nil
+}
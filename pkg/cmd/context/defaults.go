@@ -0,0 +1,148 @@
+package contextcmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func newContextSetDefaultCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-default <jobGlob> KEY=value...",
+		Short: "Set default build parameters for matching jobs",
+		Long: `Store build parameters that jk run start and jk run rerun pass to jobs
+matching a glob in this context. Globs use the same ** syntax as job
+matching; when several globs match a job, the longest one wins. Explicit
+--param flags always override defaults, and --no-defaults skips them.
+
+Setting a key again replaces its value; other keys under the glob are kept.
+Values are stored in the config file in plain text.`,
+		Example: `  jk context set-default releases/helm/Deploy ENV=staging REGION=eu-west-1
+  jk --context prod context set-default 'releases/**' ENV=prod`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pattern, err := defaultsPattern(args[0])
+			if err != nil {
+				return err
+			}
+			values := make(map[string]string, len(args)-1)
+			for _, arg := range args[1:] {
+				key, value, ok := strings.Cut(arg, "=")
+				key = strings.TrimSpace(key)
+				if !ok || key == "" {
+					return shared.NewExitError(2, fmt.Sprintf("invalid parameter %q; expected KEY=value", arg))
+				}
+				values[key] = value
+			}
+
+			cfg, name, ctxDef, err := currentContext(cmd, f)
+			if err != nil {
+				return err
+			}
+			if ctxDef.Defaults == nil {
+				ctxDef.Defaults = make(map[string]map[string]string)
+			}
+			if ctxDef.Defaults[pattern] == nil {
+				ctxDef.Defaults[pattern] = make(map[string]string, len(values))
+			}
+			for key, value := range values {
+				ctxDef.Defaults[pattern][key] = value
+			}
+
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "set-default", Target: pattern, Status: shared.ActionDone}, "Defaults for %s in context %s: %s", pattern, name, formatDefaults(ctxDef.Defaults[pattern], redactor))
+		},
+	}
+}
+
+func newContextUnsetDefaultCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset-default <jobGlob> [KEY...]",
+		Short: "Remove default build parameters",
+		Long: `Remove the named keys from the defaults stored under a glob, or every
+default under it when no keys are given.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pattern, err := defaultsPattern(args[0])
+			if err != nil {
+				return err
+			}
+
+			cfg, name, ctxDef, err := currentContext(cmd, f)
+			if err != nil {
+				return err
+			}
+			values, ok := ctxDef.Defaults[pattern]
+			if !ok {
+				return shared.NewExitError(3, fmt.Sprintf("no defaults for %s in context %s", pattern, name))
+			}
+			for _, key := range args[1:] {
+				key = strings.TrimSpace(key)
+				if _, ok := values[key]; !ok {
+					return shared.NewExitError(3, fmt.Sprintf("no default %s for %s in context %s", key, pattern, name))
+				}
+				delete(values, key)
+			}
+			if len(args) == 1 || len(values) == 0 {
+				delete(ctxDef.Defaults, pattern)
+			}
+			if len(ctxDef.Defaults) == 0 {
+				ctxDef.Defaults = nil
+			}
+
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+
+			if remaining, ok := ctxDef.Defaults[pattern]; ok {
+				redactor, err := f.Redactor()
+				if err != nil {
+					return err
+				}
+				return shared.PrintAction(cmd, shared.ActionResult{Action: "unset-default", Target: pattern, Status: shared.ActionDone}, "Defaults for %s in context %s: %s", pattern, name, formatDefaults(remaining, redactor))
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "unset-default", Target: pattern, Status: shared.ActionDone}, "Cleared defaults for %s in context %s", pattern, name)
+		},
+	}
+}
+
+// defaultsPattern trims a job glob argument and checks its syntax.
+func defaultsPattern(arg string) (string, error) {
+	pattern := strings.Trim(strings.TrimSpace(arg), "/")
+	if pattern == "" {
+		return "", shared.NewExitError(2, "job glob must not be empty")
+	}
+	if !doublestar.ValidatePattern(pattern) {
+		return "", shared.NewExitError(2, fmt.Sprintf("invalid job glob %q", arg))
+	}
+	return pattern, nil
+}
+
+// formatDefaults renders values as sorted KEY=value pairs with secrets
+// redacted.
+func formatDefaults(values map[string]string, redactor *filter.Redactor) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + redactor.Redact(key, values[key])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,68 @@
+package contextcmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestContextSetAndUnsetDefault(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	t.Setenv("JK_CONTEXT", "")
+	path := filepath.Join(home, "jk", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("active: staging\ncontexts:\n  staging:\n    url: https://ci.example.com\n"), 0o600))
+
+	run := func(args ...string) (string, error) {
+		ios, _, stdout, stderr := iostreams.Test()
+		cmd := NewCmdContext(&cmdutil.Factory{IOStreams: ios})
+		cmd.SetArgs(args)
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+	defaults := func() map[string]map[string]string {
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		return cfg.Contexts["staging"].Defaults
+	}
+
+	out, err := run("set-default", "/releases/helm/Deploy/", "ENV=staging", "REGION=eu-west-1", "API_TOKEN=s3cr3t")
+	require.NoError(t, err)
+	require.Equal(t, "Defaults for releases/helm/Deploy in context staging: API_TOKEN=[REDACTED] ENV=staging REGION=eu-west-1\n", out)
+
+	_, err = run("set-default", "releases/helm/Deploy", "ENV=qa")
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[string]string{
+		"releases/helm/Deploy": {"API_TOKEN": "s3cr3t", "ENV": "qa", "REGION": "eu-west-1"},
+	}, defaults())
+
+	out, err = run("unset-default", "releases/helm/Deploy", "API_TOKEN")
+	require.NoError(t, err)
+	require.Equal(t, "Defaults for releases/helm/Deploy in context staging: ENV=qa REGION=eu-west-1\n", out)
+
+	_, err = run("unset-default", "releases/helm/Deploy", "API_TOKEN")
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, 3, exitErr.Code)
+
+	out, err = run("unset-default", "releases/helm/Deploy")
+	require.NoError(t, err)
+	require.Equal(t, "Cleared defaults for releases/helm/Deploy in context staging\n", out)
+	require.Nil(t, defaults())
+
+	_, err = run("set-default", "releases/[", "ENV=qa")
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, 2, exitErr.Code)
+}
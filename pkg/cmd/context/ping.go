@@ -0,0 +1,230 @@
+package contextcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// defaultPingTimeout applies to each context separately, so one unreachable
+// controller cannot stall the others.
+const defaultPingTimeout = 5 * time.Second
+
+type contextPingOutput struct {
+	SchemaVersion string              `json:"schemaVersion"`
+	Items         []contextPingResult `json:"items"`
+}
+
+type contextPingResult struct {
+	Context       string `json:"context"`
+	URL           string `json:"url"`
+	Credentials   bool   `json:"credentials"`
+	Reachable     bool   `json:"reachable"`
+	LatencyMillis int64  `json:"latencyMs,omitempty"`
+	Status        int    `json:"status,omitempty"`
+	Authenticated bool   `json:"authenticated"`
+	Version       string `json:"version,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+func (r contextPingResult) ok() bool {
+	return r.Credentials && r.Reachable && r.Authenticated
+}
+
+func newContextPingCmd(f *cmdutil.Factory) *cobra.Command {
+	timeout := defaultPingTimeout
+
+	cmd := &cobra.Command{
+		Use:   "ping [name...]",
+		Short: "Check that contexts are reachable and authenticated",
+		Long: `Check every configured context, or only the named ones, in parallel.
+
+Each context gets one authenticated GET of /api/json with its own timeout.
+Contexts without a stored token are reported as having no credentials and
+are not contacted. The command exits 1 when any checked context fails.`,
+		Example: `  jk context ping
+  jk context ping prod staging --timeout 2s
+  jk context ping --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if timeout <= 0 {
+				return shared.NewExitError(2, "--timeout must be positive")
+			}
+
+			cfg, err := f.ResolveConfig()
+			if err != nil {
+				return err
+			}
+
+			names, err := pingTargets(cfg, args)
+			if err != nil {
+				return err
+			}
+
+			output := contextPingOutput{
+				SchemaVersion: "1.0",
+				Items:         pingContexts(cmd.Context(), f, cfg, names, timeout),
+			}
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				return renderPingHuman(cmd, output)
+			}); err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, item := range output.Items {
+				if !item.ok() {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return shared.NewExitError(1, fmt.Sprintf("%d context(s) failed", failed))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultPingTimeout, "Per-context timeout")
+	return cmd
+}
+
+// pingTargets returns the named contexts, or every context when none are
+// named. Unknown names are a usage error.
+func pingTargets(cfg *config.Config, args []string) ([]string, error) {
+	if len(args) == 0 {
+		names := make([]string, 0, len(cfg.Contexts))
+		for name := range cfg.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			return nil, shared.NewExitError(2, "no contexts configured; run `jk auth login` first")
+		}
+		return names, nil
+	}
+
+	for _, name := range args {
+		if _, err := cfg.Context(name); err != nil {
+			if errors.Is(err, config.ErrContextNotFound) {
+				return nil, shared.NewExitError(2, fmt.Sprintf("context %q not found", name))
+			}
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+func pingContexts(ctx context.Context, f *cmdutil.Factory, cfg *config.Config, names []string, timeout time.Duration) []contextPingResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	results := make([]contextPingResult, len(names))
+	shared.ForEach(len(names), shared.Concurrency(f), func(i int) {
+		results[i] = pingContext(ctx, f, cfg, names[i], timeout)
+	})
+	return results
+}
+
+func pingContext(ctx context.Context, f *cmdutil.Factory, cfg *config.Config, name string, timeout time.Duration) contextPingResult {
+	ctxDef, err := cfg.Context(name)
+	if err != nil {
+		return contextPingResult{Context: name, Error: err.Error()}
+	}
+	result := contextPingResult{Context: name, URL: ctxDef.URL}
+
+	hasToken, err := tokenStored(ctxDef, name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if !hasToken {
+		result.Error = "no credentials"
+		return result
+	}
+	result.Credentials = true
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := f.Client(ctx, name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	// A ping reports a rejected token; it does not offer to replace it.
+	client.SetReauth(nil)
+
+	req := client.NewRequest().SetContext(ctx).SetQueryParam("tree", "mode")
+	started := time.Now()
+	resp, err := client.Do(req, http.MethodGet, "/api/json", nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Reachable = true
+	result.LatencyMillis = time.Since(started).Milliseconds()
+	result.Status = resp.StatusCode()
+	result.Version = resp.Header().Get("X-Jenkins")
+	result.Authenticated = resp.StatusCode() < http.StatusBadRequest
+	if !result.Authenticated {
+		result.Error = resp.Status()
+	}
+	return result
+}
+
+// tokenStored reports whether the context has a token, consulting the legacy
+// file store the same way the client does when no keyring is available.
+func tokenStored(ctxDef *config.Context, name string) (bool, error) {
+	storeOpts := []secret.Option{}
+	if ctxDef.AllowInsecureStore {
+		storeOpts = append(storeOpts, secret.WithAllowFileFallback(true))
+	}
+
+	store, err := secret.Open(storeOpts...)
+	if err != nil && !ctxDef.AllowInsecureStore && secret.IsNoKeyringError(err) {
+		store, err = secret.Open(append(storeOpts, secret.WithAllowFileFallback(true))...)
+	}
+	if err != nil {
+		return false, fmt.Errorf("open secret store: %w", err)
+	}
+
+	if _, err := store.Get(secret.TokenKey(name)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("load token: %w", err)
+	}
+	return true, nil
+}
+
+func renderPingHuman(cmd *cobra.Command, output contextPingOutput) error {
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintln(w, "CONTEXT\tREACHABLE\tAUTH\tVERSION\tDETAIL")
+	for _, item := range output.Items {
+		reachable := "no"
+		if item.Reachable {
+			reachable = fmt.Sprintf("yes (%dms)", item.LatencyMillis)
+		}
+		auth := "-"
+		if item.Status != 0 {
+			auth = fmt.Sprintf("%d", item.Status)
+		}
+		version := item.Version
+		if version == "" {
+			version = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", item.Context, reachable, auth, version, item.Error)
+	}
+	return nil
+}
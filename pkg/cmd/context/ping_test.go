@@ -0,0 +1,103 @@
+package contextcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestContextPing(t *testing.T) {
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "pingtest")
+
+	var contacted int32
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/json" {
+			w.Header().Set("X-Jenkins", "2.440.1")
+			_, _ = w.Write([]byte(`{"mode":"NORMAL"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(healthy.Close)
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(rejecting.Close)
+	untouched := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&contacted, 1)
+	}))
+	t.Cleanup(untouched.Close)
+
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	require.NoError(t, err)
+	require.NoError(t, store.Set(secret.TokenKey("prod"), "token"))
+	require.NoError(t, store.Set(secret.TokenKey("stale"), "token"))
+
+	cfg := &config.Config{Contexts: map[string]*config.Context{
+		"prod":    {URL: healthy.URL, Username: "tester", AllowInsecureStore: true},
+		"stale":   {URL: rejecting.URL, Username: "tester", AllowInsecureStore: true},
+		"missing": {URL: untouched.URL, Username: "tester", AllowInsecureStore: true},
+	}}
+
+	run := func(args ...string) (contextPingOutput, error) {
+		ios, _, stdout, stderr := iostreams.Test()
+		f := &cmdutil.Factory{
+			IOStreams: ios,
+			Config:    func() (*config.Config, error) { return cfg, nil },
+		}
+		cmd := NewCmdContext(f)
+		cmd.PersistentFlags().Bool("json", false, "")
+		cmd.PersistentFlags().Bool("yaml", false, "")
+		cmd.SetArgs(append([]string{"ping", "--json"}, args...))
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		err := cmd.Execute()
+
+		var output contextPingOutput
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+		return output, err
+	}
+
+	output, err := run()
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, 1, exitErr.Code)
+	require.Len(t, output.Items, 3)
+
+	byName := map[string]contextPingResult{}
+	for _, item := range output.Items {
+		byName[item.Context] = item
+	}
+
+	require.Equal(t, "no credentials", byName["missing"].Error)
+	require.False(t, byName["missing"].Credentials)
+	require.Zero(t, atomic.LoadInt32(&contacted))
+
+	stale := byName["stale"]
+	require.True(t, stale.Reachable)
+	require.False(t, stale.Authenticated)
+	require.Equal(t, http.StatusUnauthorized, stale.Status)
+
+	prod := byName["prod"]
+	require.True(t, prod.ok(), "%+v", prod)
+	require.Equal(t, http.StatusOK, prod.Status)
+	require.Equal(t, "2.440.1", prod.Version)
+
+	output, err = run("prod")
+	require.NoError(t, err)
+	require.Len(t, output.Items, 1)
+}
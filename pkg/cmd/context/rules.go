@@ -0,0 +1,121 @@
+package contextcmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+type contextRuleItem struct {
+	Prefix  string `json:"prefix"`
+	Context string `json:"context"`
+}
+
+func newContextRulesCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Route job paths to contexts",
+		Long: `Context rules pick the context for commands that take a job path when
+neither --context nor JK_CONTEXT is given. The rule with the longest prefix
+naming the job or a folder above it wins ("team-a" matches team-a/app but not
+team-ab/app); jobs no rule matches use the active context.
+
+With --json, object payloads of commands whose context came from a rule carry
+a contextRule field naming it.`,
+	}
+	cmd.AddCommand(
+		newContextRulesListCmd(f),
+		newContextRulesSetCmd(f),
+		newContextRulesRemoveCmd(f),
+	)
+	return cmd
+}
+
+func newContextRulesListCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ls",
+		Short: "List context rules",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := f.ResolveConfig()
+			if err != nil {
+				return err
+			}
+
+			items := make([]contextRuleItem, 0, len(cfg.ContextRules))
+			for _, rule := range cfg.ContextRules {
+				items = append(items, contextRuleItem(rule))
+			}
+			return shared.PrintOutput(cmd, items, func() error {
+				if len(items) == 0 {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No context rules configured")
+					return nil
+				}
+				for _, item := range items {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s/\t%s\n", item.Prefix, item.Context)
+				}
+				return nil
+			})
+		},
+	}
+}
+
+func newContextRulesSetCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <prefix> <context>",
+		Short: "Route jobs under a prefix to a context",
+		Long: `Route commands on jobs under prefix to context. Setting a prefix again
+replaces its context.`,
+		Example: `  jk context rules set team-a/ team-a
+  jk context rules set team-a/infra infra-admin`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefix := config.NormalizeRulePrefix(args[0])
+			if prefix == "" {
+				return shared.NewExitError(2, "prefix must not be empty")
+			}
+
+			cfg, err := f.ResolveConfig()
+			if err != nil {
+				return err
+			}
+			name, ok := cfg.LookupContext(args[1])
+			if !ok {
+				return shared.NewExitError(3, fmt.Sprintf("context %q not found", args[1]))
+			}
+
+			cfg.SetContextRule(prefix, name)
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "set-rule", Target: prefix, Status: shared.ActionDone}, "Jobs under %s/ use context %s", prefix, name)
+		},
+	}
+}
+
+func newContextRulesRemoveCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rm <prefix>",
+		Short: "Remove a context rule",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prefix := config.NormalizeRulePrefix(args[0])
+
+			cfg, err := f.ResolveConfig()
+			if err != nil {
+				return err
+			}
+			if !cfg.RemoveContextRule(prefix) {
+				return shared.NewExitError(3, fmt.Sprintf("no context rule for %q", args[0]))
+			}
+			if err := cfg.Save(); err != nil {
+				return fmt.Errorf("save config: %w", err)
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "remove-rule", Target: prefix, Status: shared.ActionDone}, "Removed context rule for %s/", prefix)
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package contextcmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestContextRules(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	path := filepath.Join(home, "jk", "config.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte("active: main\ncontexts:\n  main:\n    url: https://ci.example.com\n  team-a:\n    url: https://ci.example.com\n"), 0o600))
+
+	run := func(args ...string) (string, error) {
+		ios, _, stdout, stderr := iostreams.Test()
+		cmd := NewCmdContext(&cmdutil.Factory{IOStreams: ios})
+		cmd.SetArgs(args)
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+	exitCode := func(err error) int {
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+		return exitErr.Code
+	}
+
+	out, err := run("rules", "set", "/team-a/", "Team-A")
+	require.NoError(t, err)
+	require.Equal(t, "Jobs under team-a/ use context team-a\n", out)
+	_, err = run("rules", "set", "team-a/infra", "main")
+	require.NoError(t, err)
+
+	out, err = run("rules", "ls")
+	require.NoError(t, err)
+	require.Equal(t, "team-a/\tteam-a\nteam-a/infra/\tmain\n", out)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	rule, ok := cfg.ContextRuleFor("team-a/infra/terraform")
+	require.True(t, ok)
+	require.Equal(t, "main", rule.Context)
+
+	_, err = run("rules", "set", "team-b", "missing")
+	require.Equal(t, 3, exitCode(err))
+	_, err = run("rules", "set", "/", "main")
+	require.Equal(t, 2, exitCode(err))
+
+	out, err = run("rules", "rm", "team-a/infra/")
+	require.NoError(t, err)
+	require.Equal(t, "Removed context rule for team-a/infra/\n", out)
+	_, err = run("rules", "rm", "team-a/infra")
+	require.Equal(t, 3, exitCode(err))
+
+	out, err = run("rules", "ls")
+	require.NoError(t, err)
+	require.Equal(t, "team-a/\tteam-a\n", out)
+}
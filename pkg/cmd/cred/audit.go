@@ -0,0 +1,191 @@
+package cred
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/spf13/cobra"
+
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+type credAuditOutput struct {
+	SchemaVersion  string           `json:"schemaVersion"`
+	Items          []credentialItem `json:"items"`
+	FoldersScanned int              `json:"foldersScanned"`
+	shared.Result
+}
+
+// credAuditResult is the outcome of one store: its items, or the reason it
+// was skipped (with the exit code it stands for), or the error it failed
+// with.
+type credAuditResult struct {
+	path     string
+	items    []credentialItem
+	skip     string
+	skipCode int
+	err      error
+}
+
+func newCredAuditCmd(f *cmdutil.Factory) *cobra.Command {
+	var folderGlob string
+	var okOnPartial bool
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Report credential metadata across system scope and all folders",
+		Long: `Walk the system credential store and every folder's store and report
+credential metadata (id, type, scope path, description, last use when the jk
+facade tracks it), sorted by path. Secret values are never requested.
+
+Folders without a credential store, or that the current user cannot read, are
+counted as skipped with a warning instead of failing the audit. Branches of
+multibranch projects are not visited; only folders hold credential stores.
+Other failures are reported per store and the audit continues; the exit code
+follows the result envelope, as with --ok-on-partial.`,
+		Example: `  # Audit everything
+  jk cred audit --json
+
+  # Only folders under team/
+  jk cred audit --folder-glob 'team/**'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if folderGlob != "" && !doublestar.ValidatePattern(folderGlob) {
+				return shared.NewExitError(2, fmt.Sprintf("invalid folder glob %q", folderGlob))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			folders, err := runcmd.DiscoverFolders(ctx, client, "")
+			if err != nil {
+				return err
+			}
+			if folderGlob != "" {
+				folders = filterFolders(folders, folderGlob)
+			}
+
+			output := runCredAudit(client, folders, shared.Concurrency(f))
+			if err := shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				if len(output.Items) == 0 {
+					_, _ = fmt.Fprintln(w, "No credentials found")
+				}
+				for _, item := range output.Items {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", auditPath(item), item.ID, item.Type, item.Description)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			output.WriteIssues(cmd)
+			return output.ExitError("credential stores", okOnPartial)
+		},
+	}
+
+	cmd.Flags().StringVar(&folderGlob, "folder-glob", "", "Only audit folders whose path matches this glob (e.g. 'team/*')")
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+	return cmd
+}
+
+func filterFolders(folders []string, glob string) []string {
+	out := make([]string, 0, len(folders))
+	for _, folder := range folders {
+		if ok, err := doublestar.Match(glob, folder); err == nil && ok {
+			out = append(out, folder)
+		}
+	}
+	return out
+}
+
+// runCredAudit fetches the system store and every folder store with bounded
+// concurrency. Missing and forbidden stores are skipped; other failures are
+// recorded and the remaining stores still audited.
+func runCredAudit(client shared.Doer, folders []string, concurrency int) credAuditOutput {
+	targets := append([]string{""}, folders...)
+	results := make([]credAuditResult, len(targets))
+	shared.ForEach(len(targets), concurrency, func(i int) {
+		results[i] = auditStore(client, targets[i])
+	})
+
+	output := credAuditOutput{SchemaVersion: "1.0", Items: []credentialItem{}, FoldersScanned: len(folders), Result: shared.NewResult()}
+	for _, result := range results {
+		switch {
+		case result.err != nil:
+			output.Fail(result.path, result.err)
+		case result.skip != "":
+			output.Skip(result.path, result.skipCode, fmt.Sprintf("%s: %s", result.path, result.skip))
+		default:
+			output.Items = append(output.Items, result.items...)
+			output.Succeed()
+		}
+	}
+
+	sort.SliceStable(output.Items, func(i, j int) bool {
+		a, b := output.Items[i], output.Items[j]
+		if (a.Scope == "system") != (b.Scope == "system") {
+			return a.Scope == "system"
+		}
+		if auditPath(a) != auditPath(b) {
+			return auditPath(a) < auditPath(b)
+		}
+		return a.ID < b.ID
+	})
+	return output
+}
+
+func auditStore(client shared.Doer, folder string) credAuditResult {
+	scope, path := "system", "system"
+	if folder != "" {
+		scope, path = "folder", folder
+	}
+
+	list, err := fetchCredentials(client, scope, folder)
+	if err != nil {
+		var storeErr *credentialStoreError
+		if errors.As(err, &storeErr) {
+			switch storeErr.status {
+			case http.StatusNotFound:
+				return credAuditResult{path: path, skip: "no credential store", skipCode: 3}
+			case http.StatusUnauthorized:
+				return credAuditResult{path: path, skip: "authentication required", skipCode: 4}
+			case http.StatusForbidden:
+				return credAuditResult{path: path, skip: "permission denied", skipCode: 5}
+			}
+		}
+		return credAuditResult{path: path, err: fmt.Errorf("audit %s: %w", path, err)}
+	}
+
+	items := make([]credentialItem, 0, len(list.Items))
+	for _, item := range list.Items {
+		if item.Scope == "" {
+			item.Scope = scope
+		}
+		if item.Path == "" {
+			item.Path = path
+		}
+		items = append(items, item)
+	}
+	return credAuditResult{path: path, items: items}
+}
+
+func auditPath(item credentialItem) string {
+	if strings.TrimSpace(item.Path) == "" {
+		return item.Scope
+	}
+	return item.Path
+}
@@ -11,9 +11,9 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/avivsinai/jenkins-cli/internal/jenkins"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 type credentialItem struct {
@@ -23,6 +23,8 @@ type credentialItem struct {
 	Path        string `json:"path,omitempty"`
 	Description string `json:"description,omitempty"`
 	UpdatedAt   string `json:"updatedAt,omitempty"`
+	// LastUsed is the fingerprint's last usage, when the jk facade tracks it.
+	LastUsed string `json:"lastUsed,omitempty"`
 }
 
 type credentialsList struct {
@@ -46,6 +48,18 @@ type coreCredentialsResponse struct {
 
 var errJKAPINotFound = errors.New("jk credentials endpoint not found")
 
+// credentialStoreError reports a non-success response from a credentials
+// store so callers can tell missing or forbidden stores from other failures.
+type credentialStoreError struct {
+	endpoint string
+	status   int
+	text     string
+}
+
+func (e *credentialStoreError) Error() string {
+	return fmt.Sprintf("%s: %s", e.endpoint, e.text)
+}
+
 func NewCmdCred(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "cred",
@@ -55,7 +69,10 @@ func NewCmdCred(f *cmdutil.Factory) *cobra.Command {
 	cmd.AddCommand(
 		newCredListCmd(f),
 		newCredCreateSecretCmd(f),
+		newCredCreateFileCmd(f),
+		newCredUpdateFileCmd(f),
 		newCredDeleteCmd(f),
+		newCredAuditCmd(f),
 	)
 	return cmd
 }
@@ -105,11 +122,12 @@ func newCredListCmd(f *cmdutil.Factory) *cobra.Command {
 
 	cmd.Flags().StringVar(&scope, "scope", "system", "Scope to query: system or folder")
 	cmd.Flags().StringVar(&folder, "folder", "", "Folder path when scope=folder (e.g. team/service)")
+	cmdutil.SetJobPathFlag(cmd, "folder")
 
 	return cmd
 }
 
-func fetchCredentials(client *jenkins.Client, scope, folder string) (*credentialsList, error) {
+func fetchCredentials(client shared.Doer, scope, folder string) (*credentialsList, error) {
 	if scope == "folder" && strings.TrimSpace(folder) == "" {
 		return nil, errors.New("folder path required when scope=folder")
 	}
@@ -125,7 +143,7 @@ func fetchCredentials(client *jenkins.Client, scope, folder string) (*credential
 	return fetchFromCoreAPI(client, scope, folder)
 }
 
-func fetchFromJKAPI(client *jenkins.Client, scope, folder string) (*credentialsList, error) {
+func fetchFromJKAPI(client shared.Doer, scope, folder string) (*credentialsList, error) {
 	req := client.NewRequest().SetQueryParam("scope", scope)
 	if scope == "folder" {
 		req.SetQueryParam("folderPath", folder)
@@ -143,15 +161,15 @@ func fetchFromJKAPI(client *jenkins.Client, scope, folder string) (*credentialsL
 	case http.StatusNotFound:
 		return nil, errJKAPINotFound
 	default:
-		return nil, fmt.Errorf("jk credentials endpoint: %s", httpResp.Status())
+		return nil, &credentialStoreError{endpoint: "jk credentials endpoint", status: httpResp.StatusCode(), text: httpResp.Status()}
 	}
 }
 
-func fetchFromCoreAPI(client *jenkins.Client, scope, folder string) (*credentialsList, error) {
+func fetchFromCoreAPI(client shared.Doer, scope, folder string) (*credentialsList, error) {
 	targetPath := "/credentials/store/system/domain/_/api/json"
 	displayPath := "system"
 	if scope == "folder" {
-		encoded := jenkins.EncodeJobPath(folder)
+		encoded := jobpath.Encode(folder)
 		if encoded == "" {
 			return nil, errors.New("invalid folder path")
 		}
@@ -165,7 +183,7 @@ func fetchFromCoreAPI(client *jenkins.Client, scope, folder string) (*credential
 		return nil, err
 	}
 	if resp.StatusCode() >= 300 {
-		return nil, fmt.Errorf("credentials endpoint: %s", resp.Status())
+		return nil, &credentialStoreError{endpoint: "credentials endpoint", status: resp.StatusCode(), text: shared.ResponseStatus(resp)}
 	}
 
 	out := &credentialsList{Items: make([]credentialItem, 0, len(core.Credentials))}
@@ -233,7 +251,7 @@ func newCredCreateSecretCmd(f *cmdutil.Factory) *cobra.Command {
 
 			path := "/credentials/store/system/domain/_/createCredentials"
 			if scopeVal == "folder" {
-				encoded := jenkins.EncodeJobPath(folder)
+				encoded := jobpath.Encode(folder)
 				if encoded == "" {
 					return errors.New("folder path required when scope=folder")
 				}
@@ -256,16 +274,16 @@ func newCredCreateSecretCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 			if resp.StatusCode() >= 300 {
-				return fmt.Errorf("create credential failed: %s", resp.Status())
+				return fmt.Errorf("create credential failed: %s", shared.ResponseStatus(resp))
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created credential %s in %s scope\n", id, scopeVal)
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "create", Target: id, Status: shared.ActionDone}, "Created credential %s in %s scope", id, scopeVal)
 		},
 	}
 
 	cmd.Flags().StringVar(&scope, "scope", "system", "Scope to create the credential (system or folder)")
 	cmd.Flags().StringVar(&folder, "folder", "", "Folder path when scope=folder (e.g. team/service)")
+	cmdutil.SetJobPathFlag(cmd, "folder")
 	cmd.Flags().StringVar(&id, "id", "", "Credential identifier")
 	cmd.Flags().StringVar(&description, "description", "", "Credential description")
 	cmd.Flags().StringVar(&secret, "secret", "", "Secret value (omit to read from stdin with --from-stdin)")
@@ -302,7 +320,7 @@ func newCredDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 
 			base := "/credentials/store/system/domain/_/credential"
 			if scopeVal == "folder" {
-				encoded := jenkins.EncodeJobPath(folder)
+				encoded := jobpath.Encode(folder)
 				if encoded == "" {
 					return errors.New("folder path required when scope=folder")
 				}
@@ -315,11 +333,10 @@ func newCredDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 			if resp.StatusCode() >= 300 {
-				return fmt.Errorf("delete failed: %s", resp.Status())
+				return fmt.Errorf("delete failed: %s", shared.ResponseStatus(resp))
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Deleted credential %s\n", credentialID)
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "delete", Target: credentialID, Status: shared.ActionDone}, "Deleted credential %s", credentialID)
 		},
 	}
 }
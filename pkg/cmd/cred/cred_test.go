@@ -0,0 +1,268 @@
+package cred
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestCredCreateSecretRequestBody(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.EnableCrumb("Jenkins-Crumb", "c0ffee")
+	server.Handle(http.MethodPost, "/job/team/job/svc/credentials/store/folder/domain/_/createCredentials", http.StatusOK, "")
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.SetArgs([]string{"create-secret", "--scope", "folder", "--folder", "team/svc", "--id", "npm-token", "--description", "npm publish", "--secret", "s3cr3t"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, stdout.String(), "Created credential npm-token in folder scope")
+
+	req := server.LastRequest(http.MethodPost, "/job/team/job/svc/credentials/store/folder/domain/_/createCredentials")
+	require.Equal(t, "c0ffee", req.Header.Get("Jenkins-Crumb"))
+
+	var body map[string]any
+	require.NoError(t, req.JSON(&body))
+	require.Equal(t, map[string]any{
+		"": "0",
+		"credentials": map[string]any{
+			"scope":       "GLOBAL",
+			"id":          "npm-token",
+			"description": "npm publish",
+			"$class":      "org.jenkinsci.plugins.plaincredentials.impl.StringCredentialsImpl",
+			"secret":      "s3cr3t",
+		},
+	}, body)
+}
+
+func TestCredAuditAggregatesAndSkips(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	folderClass := "com.cloudbees.hudson.plugins.folder.Folder"
+	server.HandleJSON(http.MethodGet, "/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "team", "_class": folderClass},
+		{"name": "other", "_class": folderClass},
+		{"name": "root-job", "_class": "hudson.model.FreeStyleProject"},
+	}})
+	server.HandleJSON(http.MethodGet, "/job/team/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "svc", "_class": folderClass},
+	}})
+	server.HandleJSON(http.MethodGet, "/job/team/job/svc/api/json", map[string]any{"jobs": []map[string]string{}})
+	server.HandleJSON(http.MethodGet, "/job/other/api/json", map[string]any{"jobs": []map[string]string{}})
+
+	server.HandleJSON(http.MethodGet, "/credentials/store/system/domain/_/api/json", map[string]any{"credentials": []map[string]string{
+		{"id": "global-token", "typeName": "Secret text", "description": "global"},
+	}})
+	server.HandleJSON(http.MethodGet, "/job/team/credentials/store/folder/domain/_/api/json", map[string]any{"credentials": []map[string]string{
+		{"id": "team-deploy", "typeName": "SSH Username with private key"},
+	}})
+	server.Handle(http.MethodGet, "/job/team/job/svc/credentials/store/folder/domain/_/api/json", http.StatusForbidden, "")
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"audit", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(io.Discard)
+	require.NoError(t, cmd.Execute())
+
+	var output credAuditOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Equal(t, 3, output.FoldersScanned)
+	require.Len(t, output.Items, 2)
+	require.Equal(t, "global-token", output.Items[0].ID)
+	require.Equal(t, "system", output.Items[0].Scope)
+	require.Equal(t, "team-deploy", output.Items[1].ID)
+	require.Equal(t, "team", output.Items[1].Path)
+	require.Equal(t, shared.ResultSummary{Succeeded: 2, Skipped: 2}, output.Summary)
+	require.Equal(t, []shared.ResultIssue{
+		{Code: 3, Message: "other: no credential store", Target: "other"},
+		{Code: 5, Message: "team/svc: permission denied", Target: "team/svc"},
+	}, output.Warnings)
+}
+
+func TestCredAuditSkipsUnreadableFoldersAndBranches(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "locked", "_class": "com.cloudbees.hudson.plugins.folder.Folder"},
+		{"name": "app", "_class": "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject"},
+		{"name": "broken", "_class": "com.cloudbees.hudson.plugins.folder.Folder"},
+	}})
+	server.Handle(http.MethodGet, "/job/locked/api/json", http.StatusForbidden, "")
+	server.HandleJSON(http.MethodGet, "/job/broken/api/json", map[string]any{"jobs": []map[string]string{}})
+	server.HandleJSON(http.MethodGet, "/credentials/store/system/domain/_/api/json", map[string]any{"credentials": []map[string]string{}})
+	server.Handle(http.MethodGet, "/job/locked/credentials/store/folder/domain/_/api/json", http.StatusForbidden, "")
+	server.HandleJSON(http.MethodGet, "/job/app/credentials/store/folder/domain/_/api/json", map[string]any{"credentials": []map[string]string{
+		{"id": "app-token", "typeName": "Secret text"},
+	}})
+	server.Handle(http.MethodGet, "/job/broken/credentials/store/folder/domain/_/api/json", http.StatusInternalServerError, "")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"audit", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	require.Error(t, err, "a failed store is a partial failure")
+
+	var output credAuditOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Equal(t, 3, output.FoldersScanned)
+	require.Len(t, output.Items, 1)
+	require.Equal(t, "app", output.Items[0].Path)
+	require.Equal(t, shared.ResultSummary{Succeeded: 2, Failed: 1, Skipped: 1}, output.Summary)
+	require.Len(t, output.Errors, 1)
+	require.Equal(t, "broken", output.Errors[0].Target)
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/api/json"), "branches are not walked")
+}
+
+func TestCredAuditFolderGlob(t *testing.T) {
+	require.Equal(t, []string{"team", "team/svc"}, filterFolders([]string{"other", "team", "team/svc"}, "team{,/**}"))
+	require.Equal(t, []string{"team/svc"}, filterFolders([]string{"other", "team", "team/svc"}, "team/*"))
+}
+
+func TestCredCreateFileUploadsMultipart(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/job/team/job/svc/credentials/store/folder/domain/_/createCredentials", http.StatusOK, "")
+
+	content := []byte{0x00, 0xff, 'P', 'K', 0x03, 0x04, '\r', '\n', 0x80}
+	file := filepath.Join(t.TempDir(), "release.jks")
+	require.NoError(t, os.WriteFile(file, content, 0o600))
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.SetArgs([]string{"create-file", "--scope", "folder", "--folder", "team/svc", "--id", "keystore", "--file", file})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, stdout.String(), "Created credential keystore in folder scope")
+
+	req := server.LastRequest(http.MethodPost, "/job/team/job/svc/credentials/store/folder/domain/_/createCredentials")
+	form, err := req.Multipart()
+	require.NoError(t, err)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(form.Value["json"][0]), &payload))
+	require.Equal(t, map[string]any{
+		"": "0",
+		"credentials": map[string]any{
+			"scope":       "GLOBAL",
+			"id":          "keystore",
+			"description": "",
+			"$class":      "org.jenkinsci.plugins.plaincredentials.impl.FileCredentialsImpl",
+			"file":        "file0",
+			"fileName":    "release.jks",
+		},
+	}, payload)
+
+	require.Len(t, form.File["file0"], 1)
+	part, err := form.File["file0"][0].Open()
+	require.NoError(t, err)
+	defer func() { _ = part.Close() }()
+	uploaded, err := io.ReadAll(part)
+	require.NoError(t, err)
+	require.Equal(t, content, uploaded)
+}
+
+func TestCredCreateFileRejectsLargeFiles(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+
+	file := filepath.Join(t.TempDir(), "huge.bin")
+	require.NoError(t, os.WriteFile(file, make([]byte, maxCredentialFileBytes+1), 0o600))
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.SetArgs([]string{"create-file", "--id", "huge", "--file", file})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 2, exitErr.Code)
+	require.Contains(t, err.Error(), "10 MiB")
+	require.NotContains(t, err.Error(), file)
+	require.Empty(t, server.RequestsTo(http.MethodPost, "/credentials/store/system/domain/_/createCredentials"))
+}
+
+func TestCredCreateFileMapsErrorStatus(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/credentials/store/system/domain/_/createCredentials", http.StatusForbidden, "")
+
+	file := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0o600))
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.SetArgs([]string{"create-file", "--id", "kubeconfig", "--file", file})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, cmd.Execute(), &exitErr)
+	require.Equal(t, 5, exitErr.Code)
+	require.Contains(t, exitErr.Error(), "permission denied for credential kubeconfig")
+}
+
+func TestCredUpdateFileKeepsDescription(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/credentials/store/system/domain/_/credential/kubeconfig/api/json", map[string]any{"description": "prod cluster"})
+	server.Handle(http.MethodPost, "/credentials/store/system/domain/_/credential/kubeconfig/updateSubmit", http.StatusOK, "")
+
+	file := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(file, []byte("apiVersion: v1\n"), 0o600))
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.SetArgs([]string{"update-file", "kubeconfig", "--file", file, "--file-name", "kubeconfig"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	require.NoError(t, cmd.Execute())
+	require.Contains(t, stdout.String(), "Updated credential kubeconfig in system scope")
+
+	form, err := server.LastRequest(http.MethodPost, "/credentials/store/system/domain/_/credential/kubeconfig/updateSubmit").Multipart()
+	require.NoError(t, err)
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(form.Value["json"][0]), &payload))
+	require.Equal(t, "prod cluster", payload["description"])
+	require.Equal(t, "kubeconfig", payload["fileName"])
+	require.Equal(t, "file0", payload["file"])
+	require.Equal(t, "org.jenkinsci.plugins.plaincredentials.impl.FileCredentialsImpl", payload["stapler-class"])
+}
+
+func TestCredUpdateFileMissingCredential(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+
+	file := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(file, []byte("x"), 0o600))
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdCred(f)
+	cmd.SetArgs([]string{"update-file", "missing", "--file", file})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, cmd.Execute(), &exitErr)
+	require.Equal(t, 3, exitErr.Code)
+}
@@ -0,0 +1,263 @@
+package cred
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	fileCredentialsClass = "org.jenkinsci.plugins.plaincredentials.impl.FileCredentialsImpl"
+	// maxCredentialFileBytes caps uploads client-side. Jenkins keeps secret
+	// files in memory and in credentials.xml, so anything larger is almost
+	// certainly the wrong file.
+	maxCredentialFileBytes = 10 << 20
+	// credentialFilePart is the multipart part holding the file; the
+	// credential's "file" field names it.
+	credentialFilePart = "file0"
+)
+
+type credentialFileOptions struct {
+	scope       string
+	folder      string
+	id          string
+	description string
+	file        string
+	fileName    string
+}
+
+func newCredCreateFileCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &credentialFileOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "create-file",
+		Short: "Create a secret file credential",
+		Long: `Upload a file (a kubeconfig, a keystore) as a secret file credential.
+
+The content is sent byte for byte, so binary files round-trip untouched. Files
+larger than 10 MiB are rejected before anything is sent.`,
+		Example: `  jk cred create-file --id kubeconfig-prod --file ./kubeconfig
+  jk cred create-file --scope folder --folder team/svc --id keystore --file ./release.jks --file-name release.jks`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(opts.id) == "" {
+				return shared.NewExitError(2, "--id is required")
+			}
+			return runCredentialFile(cmd, f, opts, false)
+		},
+	}
+	addCredentialFileFlags(cmd, opts)
+	cmd.Flags().StringVar(&opts.id, "id", "", "Credential identifier")
+	return cmd
+}
+
+func newCredUpdateFileCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := &credentialFileOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "update-file <id>",
+		Short: "Replace the file of a secret file credential",
+		Long: `Replace the content of an existing secret file credential. The description
+is kept unless --description is given.`,
+		Example: `  jk cred update-file kubeconfig-prod --file ./kubeconfig`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.id = strings.TrimSpace(args[0])
+			if opts.id == "" {
+				return shared.NewExitError(2, "credential id required")
+			}
+			return runCredentialFile(cmd, f, opts, true)
+		},
+	}
+	addCredentialFileFlags(cmd, opts)
+	return cmd
+}
+
+func addCredentialFileFlags(cmd *cobra.Command, opts *credentialFileOptions) {
+	cmd.Flags().StringVar(&opts.scope, "scope", "system", "Credential store scope (system or folder)")
+	cmd.Flags().StringVar(&opts.folder, "folder", "", "Folder path when scope=folder (e.g. team/service)")
+	cmdutil.SetJobPathFlag(cmd, "folder")
+	cmd.Flags().StringVar(&opts.description, "description", "", "Credential description")
+	cmd.Flags().StringVar(&opts.file, "file", "", "Local file to upload")
+	cmd.Flags().StringVar(&opts.fileName, "file-name", "", "File name stored with the credential (default: the base name of --file)")
+	_ = cmd.MarkFlagRequired("file")
+}
+
+func runCredentialFile(cmd *cobra.Command, f *cmdutil.Factory, opts *credentialFileOptions, update bool) error {
+	scopeVal := strings.ToLower(strings.TrimSpace(opts.scope))
+	if scopeVal == "" {
+		scopeVal = "system"
+	}
+	if scopeVal != "system" && scopeVal != "folder" {
+		return shared.NewExitError(2, fmt.Sprintf("unsupported scope %q", opts.scope))
+	}
+
+	data, err := readCredentialFile(opts.file)
+	if err != nil {
+		return err
+	}
+	fileName := strings.TrimSpace(opts.fileName)
+	if fileName == "" {
+		fileName = filepath.Base(opts.file)
+	}
+
+	client, err := shared.JenkinsClient(cmd, f)
+	if err != nil {
+		return err
+	}
+
+	base, err := credentialStoreBase(scopeVal, opts.folder)
+	if err != nil {
+		return err
+	}
+
+	credential := map[string]any{
+		"scope":       "GLOBAL",
+		"id":          opts.id,
+		"description": opts.description,
+		"$class":      fileCredentialsClass,
+		"file":        credentialFilePart,
+		"fileName":    fileName,
+	}
+
+	path := base + "/createCredentials"
+	form := map[string]any{"": "0", "credentials": credential}
+	if update {
+		credentialPath := fmt.Sprintf("%s/credential/%s", base, url.PathEscape(opts.id))
+		if !cmd.Flags().Changed("description") {
+			description, err := fetchCredentialDescription(client, credentialPath, opts.id)
+			if err != nil {
+				return err
+			}
+			credential["description"] = description
+		}
+		credential["stapler-class"] = fileCredentialsClass
+		path = credentialPath + "/updateSubmit"
+		form = credential
+	}
+
+	body, contentType, err := credentialFileForm(form, fileName, data)
+	if err != nil {
+		return err
+	}
+	req := client.NewRequest().SetHeader("Content-Type", contentType).SetBody(body)
+	resp, err := client.Do(req, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := shared.CheckResponse(resp, fmt.Sprintf("credential %s", opts.id)); err != nil {
+		return err
+	}
+
+	action, verb := "create", "Created"
+	if update {
+		action, verb = "update", "Updated"
+	}
+
+	return shared.PrintAction(cmd, shared.ActionResult{Action: action, Target: opts.id, Status: shared.ActionDone}, "%s credential %s in %s scope", verb, opts.id, scopeVal)
+}
+
+// readCredentialFile reads at most maxCredentialFileBytes. Errors name --file
+// rather than the path so the location of secrets stays out of logs.
+func readCredentialFile(path string) ([]byte, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, shared.NewExitError(2, "--file is required")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, shared.NewExitError(2, "--file does not exist")
+		}
+		return nil, fmt.Errorf("open --file: %w", unwrapPathError(err))
+	}
+	defer func() { _ = file.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxCredentialFileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read --file: %w", unwrapPathError(err))
+	}
+	if len(data) > maxCredentialFileBytes {
+		return nil, shared.NewExitError(2, fmt.Sprintf("--file is larger than the %d MiB limit for secret files", maxCredentialFileBytes>>20))
+	}
+	return data, nil
+}
+
+func unwrapPathError(err error) error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
+}
+
+// credentialFileForm encodes form as the "json" field Jenkins reads
+// submitted forms from, followed by the file part it references. The body is
+// buffered so the request can be replayed after re-authentication.
+func credentialFileForm(form map[string]any, fileName string, data []byte) ([]byte, string, error) {
+	payload, err := json.Marshal(form)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	if err := writer.WriteField("json", string(payload)); err != nil {
+		return nil, "", err
+	}
+	part, err := writer.CreateFormFile(credentialFilePart, fileName)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, "", err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// fetchCredentialDescription reads the current description so an update
+// does not clear it. A missing credential is exit code 3.
+func fetchCredentialDescription(client shared.Doer, credentialPath, id string) (string, error) {
+	var current struct {
+		Description string `json:"description"`
+	}
+	req := client.NewRequest().SetQueryParam("tree", "description")
+	resp, err := client.Do(req, http.MethodGet, credentialPath+"/api/json", &current)
+	if err != nil {
+		return "", err
+	}
+	if err := shared.CheckResponse(resp, fmt.Sprintf("credential %s", id)); err != nil {
+		return "", err
+	}
+	return current.Description, nil
+}
+
+// credentialStoreBase is the global domain of the system or folder store.
+func credentialStoreBase(scope, folder string) (string, error) {
+	if scope != "folder" {
+		return "/credentials/store/system/domain/_", nil
+	}
+	encoded := jobpath.Encode(folder)
+	if encoded == "" {
+		return "", shared.NewExitError(2, "folder path required when scope=folder")
+	}
+	return fmt.Sprintf("/%s/credentials/store/folder/domain/_", encoded), nil
+}
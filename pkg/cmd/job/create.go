@@ -0,0 +1,199 @@
+package job
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jobspec"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+func newJobCreateCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		file     string
+		fromYAML string
+		printXML bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create <jobPath>",
+		Short: "Create a job from config.xml or a YAML definition",
+		Long: `Create a job or folder at jobPath.
+
+--file posts an existing config.xml (- reads stdin). --from-yaml converts a
+short YAML definition covering pipeline-from-SCM, inline pipeline scripts,
+string/boolean/choice parameters, cron and SCM polling triggers, and folders;
+unknown keys are rejected. See docs/job-yaml.md for the schema. --print-xml
+writes the converted config.xml to stdout without contacting Jenkins.`,
+		Example: `  jk job create team/api --from-yaml api.yaml
+  jk job create team/api --from-yaml api.yaml --print-xml
+  jk job create team/api --file config.xml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (file == "") == (fromYAML == "") {
+				return shared.NewExitError(2, "pass exactly one of --file or --from-yaml")
+			}
+			if printXML && fromYAML == "" {
+				return shared.NewExitError(2, "--print-xml requires --from-yaml")
+			}
+
+			var (
+				configXML []byte
+				err       error
+			)
+			if fromYAML != "" {
+				configXML, err = convertJobYAML(cmd, fromYAML)
+			} else {
+				configXML, err = readInput(cmd, file)
+			}
+			if err != nil {
+				return err
+			}
+
+			if printXML {
+				_, err := cmd.OutOrStdout().Write(configXML)
+				return err
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			parent, name, err := resolveCreateTarget(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			jobPath, err := createJob(client, parent, name, configXML)
+			if err != nil {
+				return err
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "create", Target: jobPath, Status: shared.ActionDone}, "Created %s", jobPath)
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().StringVar(&file, "file", "", "config.xml to create the job from (- for stdin)")
+	cmd.Flags().StringVar(&fromYAML, "from-yaml", "", "YAML job definition to convert (- for stdin)")
+	cmd.Flags().BoolVar(&printXML, "print-xml", false, "Print the config.xml generated by --from-yaml and exit")
+	return cmd
+}
+
+func convertJobYAML(cmd *cobra.Command, name string) ([]byte, error) {
+	data, err := readInput(cmd, name)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := jobspec.ParseBytes(data)
+	if err != nil {
+		return nil, shared.NewExitError(2, fmt.Sprintf("%s: %v", name, err))
+	}
+	return spec.XML()
+}
+
+func readInput(cmd *cobra.Command, name string) ([]byte, error) {
+	if name == "-" {
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+// resolveCreateTarget splits arg into the parent folder and the name of the
+// job to create. The parent resolves against the default folder like any
+// other job path; the leaf name is taken as given.
+func resolveCreateTarget(cmd *cobra.Command, client *jenkins.Client, arg string) (parent, name string, err error) {
+	trimmed := strings.TrimSpace(arg)
+	absolute := strings.HasPrefix(trimmed, "/")
+	parentPath, name := jobpath.Split(trimmed)
+	if name == "" {
+		return "", "", shared.NewExitError(2, "job path must name the job to create")
+	}
+
+	switch {
+	case parentPath == "" && absolute:
+		// "/name" creates at the Jenkins root.
+	case parentPath == "":
+		parent = shared.DefaultFolder(client)
+	default:
+		if absolute {
+			parentPath = "/" + parentPath
+		}
+		parent, err = shared.ResolveJobPath(cmd, client, parentPath)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return parent, name, nil
+}
+
+// createJob posts configXML to the parent folder's createItem endpoint, or
+// the root's when parent is empty, and returns the new job's path.
+func createJob(client shared.Doer, parent, name string, configXML []byte) (string, error) {
+	endpoint := "/createItem"
+	jobPath := name
+	if parent != "" {
+		endpoint = fmt.Sprintf("/%s/createItem", jobpath.Encode(parent))
+		jobPath = jobpath.Join(parent, name)
+	}
+
+	req := client.NewRequest().
+		SetQueryParam("name", name).
+		SetHeader("Content-Type", "application/xml").
+		SetBody(configXML)
+	resp, err := client.Do(req, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode() == http.StatusBadRequest {
+		// Jenkins explains rejections (name taken, invalid XML) in X-Error.
+		reason := strings.TrimSpace(resp.Header().Get("X-Error"))
+		if reason == "" {
+			reason = shared.ResponseStatus(resp)
+		}
+		return "", shared.NewExitError(2, fmt.Sprintf("create %s: %s", jobPath, reason))
+	}
+	if resp.StatusCode() == http.StatusNotFound && parent != "" {
+		missing := missingFolder(client, parent)
+		return "", shared.NewExitError(3, fmt.Sprintf("folder %s not found; create the parent folders before %s", missing, jobPath))
+	}
+	subject := "Jenkins root"
+	if parent != "" {
+		subject = fmt.Sprintf("folder %s", parent)
+	}
+	if err := shared.CheckResponse(resp, subject); err != nil {
+		return "", err
+	}
+	return jobPath, nil
+}
+
+// missingFolder is the outermost folder of parent that does not exist, so a
+// failed create names the folder to add first. It falls back to parent when
+// every probe finds its folder or fails for another reason.
+func missingFolder(client shared.Doer, parent string) string {
+	names := jobpath.Segments(parent)
+	for i := range names {
+		folder := strings.Join(names[:i+1], "/")
+		req := client.NewRequest().SetQueryParam("tree", "_class")
+		resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/api/json", jobpath.Encode(folder)), nil)
+		if err == nil && resp.StatusCode() == http.StatusNotFound {
+			return folder
+		}
+	}
+	return parent
+}
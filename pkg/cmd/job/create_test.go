@@ -0,0 +1,112 @@
+package job
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const createSpec = `description: API
+pipeline:
+  scm:
+    url: https://github.com/acme/api.git
+`
+
+func executeCreate(t *testing.T, f *cmdutil.Factory, args ...string) error {
+	t.Helper()
+	cmd := newJobCreateCmd(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(f.IOStreams.Out)
+	cmd.SetErr(f.IOStreams.ErrOut)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func writeSpec(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "job.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestJobCreateFromYAML(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/job/team/createItem", http.StatusOK, "")
+	f, stdout, _ := fakejenkins.Factory(client)
+
+	require.NoError(t, executeCreate(t, f, "team/api", "--from-yaml", writeSpec(t, createSpec)))
+	require.Equal(t, "Created team/api\n", stdout.String())
+
+	req := server.LastRequest(http.MethodPost, "/job/team/createItem")
+	require.Equal(t, "api", req.Query.Get("name"))
+	require.Equal(t, "application/xml", req.Header.Get("Content-Type"))
+	require.Contains(t, string(req.Body), "<url>https://github.com/acme/api.git</url>")
+}
+
+func TestJobCreatePrintXMLSkipsJenkins(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	f, stdout, _ := fakejenkins.Factory(client)
+
+	require.NoError(t, executeCreate(t, f, "team/api", "--from-yaml", writeSpec(t, createSpec), "--print-xml"))
+	require.Contains(t, stdout.String(), "<flow-definition plugin=\"workflow-job\">")
+	require.Empty(t, server.RequestsTo(http.MethodPost, "/job/team/createItem"))
+}
+
+func TestJobCreateFromFile(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/createItem", http.StatusOK, "")
+	f, _, _ := fakejenkins.Factory(client)
+
+	path := filepath.Join(t.TempDir(), "config.xml")
+	require.NoError(t, os.WriteFile(path, []byte("<project/>"), 0o644))
+	require.NoError(t, executeCreate(t, f, "/api", "--file", path))
+	require.Equal(t, "<project/>", string(server.LastRequest(http.MethodPost, "/createItem").Body))
+}
+
+func TestJobCreateValidation(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+	f, _, _ := fakejenkins.Factory(client)
+
+	requireExit(t, executeCreate(t, f, "api", "--from-yaml", writeSpec(t, createSpec+"schedule: daily\n")), 2)
+	requireExit(t, executeCreate(t, f, "api"), 2)
+	requireExit(t, executeCreate(t, f, "api", "--file", "config.xml", "--print-xml"), 2)
+}
+
+func TestJobCreateExistingName(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleHeaders(http.MethodPost, "/createItem", http.StatusBadRequest, http.Header{"X-Error": {"A job already exists with the name ‘api’"}})
+	f, _, _ := fakejenkins.Factory(client)
+
+	err := executeCreate(t, f, "api", "--from-yaml", writeSpec(t, createSpec))
+	requireExit(t, err, 2)
+	require.Contains(t, err.Error(), "already exists")
+}
+
+func TestJobCreateReportsHTMLErrorPage(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleHTML(http.MethodPost, "/createItem", http.StatusBadRequest,
+		`<html><head><title>Error [Jenkins]</title></head><body><div id="main-panel"><h1>Error</h1><p>A job already exists with the name ‘api’</p></div></body></html>`)
+	f, _, _ := fakejenkins.Factory(client)
+
+	err := executeCreate(t, f, "api", "--from-yaml", writeSpec(t, createSpec))
+	requireExit(t, err, 2)
+	require.Equal(t, "create api: 400 Bad Request: A job already exists with the name ‘api’", err.Error())
+}
+
+func TestJobCreateNamesMissingFolder(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/team/api/json", map[string]any{"_class": "com.cloudbees.hudson.plugins.folder.Folder"})
+	f, _, _ := fakejenkins.Factory(client)
+
+	err := executeCreate(t, f, "/team/backend/api", "--from-yaml", writeSpec(t, createSpec))
+	requireExit(t, err, 3)
+	require.Contains(t, err.Error(), "folder team/backend not found")
+	require.Len(t, server.RequestsTo(http.MethodPost, "/job/team/job/backend/createItem"), 1)
+}
@@ -0,0 +1,212 @@
+package job
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+)
+
+// jobListBaseTree is what `jk job ls` always fetches per job.
+var jobListBaseTree = []string{"name", "url", "color"}
+
+// jobFieldSpec describes a --select field or --filter key for `jk job ls`.
+// needs lists the tree attributes it requires, as "attr" or "parent.child".
+type jobFieldSpec struct {
+	name        string
+	needs       []string
+	selectable  bool
+	description string
+}
+
+// jobFieldRegistry is keyed by lower-cased name; --select and --filter both
+// match case-insensitively.
+var jobFieldRegistry = map[string]jobFieldSpec{
+	"name":            {name: "name", description: "Job name"},
+	"color":           {name: "color", description: "Jenkins ball color (blue, red, disabled, aborted_anime, ...)"},
+	"status":          {name: "status", description: "Status derived from color: success, failed, unstable, aborted, disabled, notbuilt, building"},
+	"buildable":       {name: "buildable", needs: []string{"buildable"}, description: "Whether the job can be triggered"},
+	"description":     {name: "description", needs: []string{"description"}, selectable: true, description: "Job description"},
+	"healthscore":     {name: "healthScore", needs: []string{"healthReport.score"}, selectable: true, description: "Lowest health report score (0-100)"},
+	"lastbuildnumber": {name: "lastBuildNumber", needs: []string{"lastBuild.number"}, selectable: true, description: "Number of the latest build"},
+	"lastbuildresult": {name: "lastBuildResult", needs: []string{"lastBuild.result", "lastBuild.building"}, selectable: true, description: "Result of the latest build (empty while running)"},
+	"lastbuildtime":   {name: "lastBuildTime", needs: []string{"lastBuild.timestamp"}, selectable: true, description: "Start time of the latest build (RFC3339)"},
+}
+
+type jobListEntry struct {
+	Name         string `json:"name"`
+	URL          string `json:"url"`
+	Color        string `json:"color"`
+	Buildable    *bool  `json:"buildable"`
+	Description  string `json:"description"`
+	HealthReport []struct {
+		Score int `json:"score"`
+	} `json:"healthReport"`
+	LastBuild *struct {
+		Number    int64  `json:"number"`
+		Result    string `json:"result"`
+		Building  bool   `json:"building"`
+		Timestamp int64  `json:"timestamp"`
+	} `json:"lastBuild"`
+}
+
+// jobFilterKeys returns the --filter keys in their documented spelling.
+func jobFilterKeys() []string {
+	keys := make([]string, 0, len(jobFieldRegistry))
+	for _, spec := range jobFieldRegistry {
+		keys = append(keys, spec.name)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jobSelectFieldNames() []string {
+	fields := make([]string, 0, len(jobFieldRegistry))
+	for key, spec := range jobFieldRegistry {
+		if spec.selectable {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// parseJobSelectFields validates --select, keeping the order given.
+func parseJobSelectFields(value string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		field := strings.ToLower(strings.TrimSpace(part))
+		if field == "" || seen[field] {
+			continue
+		}
+		if spec, ok := jobFieldRegistry[field]; !ok || !spec.selectable {
+			return nil, fmt.Errorf("unsupported select field %q", strings.TrimSpace(part))
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// jobListTree builds the tree query for the selected fields and filters, so
+// a filter on a field that was not selected still fetches it.
+func jobListTree(selectFields []string, filters []filter.Filter) string {
+	attrs := append([]string{}, jobListBaseTree...)
+	nested := make(map[string][]string)
+	var parents []string
+
+	add := func(need string) {
+		parent, child, ok := strings.Cut(need, ".")
+		if !ok {
+			if !slices.Contains(attrs, need) {
+				attrs = append(attrs, need)
+			}
+			return
+		}
+		if _, seen := nested[parent]; !seen {
+			parents = append(parents, parent)
+		}
+		if !slices.Contains(nested[parent], child) {
+			nested[parent] = append(nested[parent], child)
+		}
+	}
+
+	for _, field := range selectFields {
+		for _, need := range jobFieldRegistry[field].needs {
+			add(need)
+		}
+	}
+	for _, f := range filters {
+		for _, need := range jobFieldRegistry[f.Key].needs {
+			add(need)
+		}
+	}
+	for _, parent := range parents {
+		attrs = append(attrs, fmt.Sprintf("%s[%s]", parent, strings.Join(nested[parent], ",")))
+	}
+	return fmt.Sprintf("jobs[%s]", strings.Join(attrs, ","))
+}
+
+// jobStatusFromColor maps a Jenkins ball color to a status. Folders have no
+// color and map to "".
+func jobStatusFromColor(color string) string {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if strings.HasSuffix(color, "_anime") {
+		return "building"
+	}
+	switch color {
+	case "blue", "green":
+		return "success"
+	case "red":
+		return "failed"
+	case "yellow":
+		return "unstable"
+	case "aborted":
+		return "aborted"
+	case "disabled":
+		return "disabled"
+	case "notbuilt", "nobuilt", "grey":
+		return "notbuilt"
+	default:
+		return ""
+	}
+}
+
+// jobFieldValues returns the value of every field the entry has data for,
+// keyed by lower-cased name. Fields that were not fetched, or that the job
+// lacks (such as the latest build of a job that never ran), are absent.
+func jobFieldValues(entry jobListEntry) map[string]any {
+	values := map[string]any{
+		"name":   entry.Name,
+		"color":  entry.Color,
+		"status": jobStatusFromColor(entry.Color),
+	}
+	if entry.Buildable != nil {
+		values["buildable"] = *entry.Buildable
+	}
+	if entry.Description != "" {
+		values["description"] = entry.Description
+	}
+	if len(entry.HealthReport) > 0 {
+		score := entry.HealthReport[0].Score
+		for _, report := range entry.HealthReport[1:] {
+			if report.Score < score {
+				score = report.Score
+			}
+		}
+		values["healthscore"] = score
+	}
+	if build := entry.LastBuild; build != nil {
+		if build.Number > 0 {
+			values["lastbuildnumber"] = build.Number
+		}
+		if !build.Building {
+			values["lastbuildresult"] = strings.ToUpper(build.Result)
+		}
+		if build.Timestamp > 0 {
+			values["lastbuildtime"] = time.UnixMilli(build.Timestamp).UTC()
+		}
+	}
+	return values
+}
+
+// selectedJobFields renders the selected fields for output. Missing values are
+// reported as null so every job carries the same keys.
+func selectedJobFields(values map[string]any, selectFields []string) map[string]any {
+	if len(selectFields) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(selectFields))
+	for _, field := range selectFields {
+		value := values[field]
+		if ts, ok := value.(time.Time); ok {
+			value = ts.Format(time.RFC3339)
+		}
+		fields[jobFieldRegistry[field].name] = value
+	}
+	return fields
+}
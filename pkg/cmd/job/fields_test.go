@@ -0,0 +1,103 @@
+package job
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+const jobListBody = `{"jobs":[
+	{"name":"api","url":"https://ci/job/team/job/api/","color":"red","buildable":true,"healthReport":[{"score":80},{"score":20}],"lastBuild":{"number":12,"result":"FAILURE","building":false,"timestamp":1700000000000}},
+	{"name":"web","url":"https://ci/job/team/job/web/","color":"blue_anime","buildable":true,"lastBuild":{"number":7,"building":true,"timestamp":1700000500000}},
+	{"name":"old","url":"https://ci/job/team/job/old/","color":"disabled","buildable":false},
+	{"name":"libs","url":"https://ci/job/team/job/libs/"}
+]}`
+
+func runJobList(t *testing.T, args ...string) (*fakejenkins.Server, string, error) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/api/json", http.StatusOK, jobListBody)
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := newJobListCmd(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(append([]string{"team"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return server, stdout.String(), err
+}
+
+func TestJobStatusFromColor(t *testing.T) {
+	cases := map[string]string{
+		"blue":          "success",
+		"red":           "failed",
+		"yellow":        "unstable",
+		"aborted":       "aborted",
+		"disabled":      "disabled",
+		"notbuilt":      "notbuilt",
+		"aborted_anime": "building",
+		"red_anime":     "building",
+		"":              "",
+	}
+	for color, want := range cases {
+		require.Equal(t, want, jobStatusFromColor(color), color)
+	}
+}
+
+func TestJobListTreeExpandsOnlyWhenNeeded(t *testing.T) {
+	require.Equal(t, "jobs[name,url,color]", jobListTree(nil, nil))
+
+	filters, err := filter.ParseKeys([]string{"buildable=true", "lastBuildNumber>3"}, jobFilterKeys())
+	require.NoError(t, err)
+	require.Equal(t,
+		"jobs[name,url,color,description,buildable,lastBuild[result,building,number],healthReport[score]]",
+		jobListTree([]string{"lastbuildresult", "description", "healthscore"}, filters))
+}
+
+func TestJobListFilterAndSelect(t *testing.T) {
+	server, out, err := runJobList(t, "--filter", "status=failed", "--select", "lastBuildResult,healthScore,lastBuildTime", "--json")
+	require.NoError(t, err)
+	require.Equal(t, "jobs[name,url,color,lastBuild[result,building,timestamp],healthReport[score]]",
+		server.LastRequest(http.MethodGet, "/job/team/api/json").Query.Get("tree"))
+
+	var jobs []jobSummary
+	require.NoError(t, json.Unmarshal([]byte(out), &jobs))
+	require.Len(t, jobs, 1)
+	require.Equal(t, "api", jobs[0].Name)
+	require.Equal(t, map[string]any{
+		"lastBuildResult": "FAILURE",
+		"healthScore":     float64(20),
+		"lastBuildTime":   "2023-11-14T22:13:20Z",
+	}, jobs[0].Fields)
+}
+
+func TestJobListFilterForcesFetch(t *testing.T) {
+	server, out, err := runJobList(t, "--filter", "buildable=false")
+	require.NoError(t, err)
+	require.Equal(t, "jobs[name,url,color,buildable]", server.LastRequest(http.MethodGet, "/job/team/api/json").Query.Get("tree"))
+	require.Equal(t, "old\thttps://ci/job/team/job/old/\n", out)
+}
+
+func TestJobListSelectHumanShowsMissingValues(t *testing.T) {
+	_, out, err := runJobList(t, "--filter", "name^", "--select", "lastbuildnumber")
+	require.NoError(t, err)
+	require.Contains(t, out, "api\thttps://ci/job/team/job/api/\t12\n")
+	require.Contains(t, out, "libs\thttps://ci/job/team/job/libs/\t-\n")
+}
+
+func TestJobListRejectsUnknownKeys(t *testing.T) {
+	_, _, err := runJobList(t, "--filter", "result=FAILURE")
+	require.ErrorIs(t, err, filter.ErrUnsupportedKey)
+
+	_, _, err = runJobList(t, "--select", "color")
+	require.ErrorContains(t, err, "unsupported select field")
+}
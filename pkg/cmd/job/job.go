@@ -2,23 +2,28 @@ package job
 
 import (
 	"fmt"
+	"net/http"
 	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
-	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 type jobListResponse struct {
-	Jobs []jobSummary `json:"jobs"`
+	Jobs []jobListEntry `json:"jobs"`
 }
 
 type jobSummary struct {
-	Name  string `json:"name"`
-	URL   string `json:"url"`
-	Color string `json:"color"`
+	Name   string         `json:"name"`
+	URL    string         `json:"url"`
+	Color  string         `json:"color"`
+	Fields map[string]any `json:"fields,omitempty"`
 }
 
 func NewCmdJob(f *cmdutil.Factory) *cobra.Command {
@@ -30,13 +35,26 @@ func NewCmdJob(f *cmdutil.Factory) *cobra.Command {
 	cmd.AddCommand(
 		newJobListCmd(f),
 		newJobViewCmd(f),
+		newJobToggleCmd(f, "enable"),
+		newJobToggleCmd(f, "disable"),
+		newJobCreateCmd(f),
+		newJobLintCmd(f),
+		newJobTriggersCmd(f),
+		newJobPathsCmd(f),
+		newJobRetentionCmd(f),
+		runcmd.NewCmdRunLast(f),
 	)
 
 	return cmd
 }
 
 func newJobListCmd(f *cmdutil.Factory) *cobra.Command {
-	var folder string
+	var (
+		folder     string
+		filterArgs []string
+		selectArg  string
+		fullPaths  bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "ls [folder]",
@@ -47,6 +65,15 @@ Related commands:
   jk search --job-glob '<pattern>'      Search for jobs by pattern`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			filters, err := filter.ParseKeys(filterArgs, jobFilterKeys())
+			if err != nil {
+				return err
+			}
+			selectFields, err := parseJobSelectFields(selectArg)
+			if err != nil {
+				return err
+			}
+
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
@@ -60,16 +87,20 @@ Related commands:
 				}
 				targetFolder = args[0]
 			}
+			targetFolder, err = shared.ResolveFolder(cmd, client, targetFolder)
+			if err != nil {
+				return err
+			}
 
 			path := "/api/json"
 			if targetFolder != "" {
-				path = fmt.Sprintf("/%s/api/json", jenkins.EncodeJobPath(targetFolder))
+				path = fmt.Sprintf("/%s/api/json", jobpath.Encode(targetFolder))
 			}
 
 			var resp jobListResponse
-			_, err = client.Do(
+			httpResp, err := client.Do(
 				client.NewRequest().
-					SetQueryParam("tree", "jobs[name,url,color]"),
+					SetQueryParam("tree", jobListTree(selectFields, filters)),
 				"GET",
 				path,
 				&resp,
@@ -77,13 +108,33 @@ Related commands:
 			if err != nil {
 				return err
 			}
+			subject := "Jenkins root"
+			if targetFolder != "" {
+				subject = fmt.Sprintf("folder %s", targetFolder)
+			}
+			if err := shared.CheckResponse(httpResp, subject); err != nil {
+				return err
+			}
 
-			sort.Slice(resp.Jobs, func(i, j int) bool {
-				return resp.Jobs[i].Name < resp.Jobs[j].Name
+			jobs := make([]jobSummary, 0, len(resp.Jobs))
+			for _, entry := range resp.Jobs {
+				values := jobFieldValues(entry)
+				if !filter.Evaluate(filter.Context(values), filters) {
+					continue
+				}
+				jobs = append(jobs, jobSummary{
+					Name:   entry.Name,
+					URL:    entry.URL,
+					Color:  entry.Color,
+					Fields: selectedJobFields(values, selectFields),
+				})
+			}
+			sort.Slice(jobs, func(i, j int) bool {
+				return jobs[i].Name < jobs[j].Name
 			})
 
-			return shared.PrintOutput(cmd, resp.Jobs, func() error {
-				if len(resp.Jobs) == 0 {
+			return shared.PrintOutput(cmd, jobs, func() error {
+				if len(jobs) == 0 {
 					if targetFolder != "" {
 						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No jobs found in %s\n", targetFolder)
 					} else {
@@ -92,36 +143,70 @@ Related commands:
 					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Hint: use `jk search --job-glob '*<pattern>*'` to discover job paths by name")
 					return nil
 				}
-				for _, job := range resp.Jobs {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", job.Name, job.URL)
+				fit := shared.NewPathFitter(f, fullPaths)
+				for _, job := range jobs {
+					fields := make([]string, 0, len(selectFields))
+					for _, field := range selectFields {
+						value := job.Fields[jobFieldRegistry[field].name]
+						if value == nil {
+							value = "-"
+						}
+						fields = append(fields, fmt.Sprint(value))
+					}
+					url := fit.Fit(job.URL, shared.RowWidth(append([]string{job.Name, ""}, fields...)...))
+					line := strings.Join(append([]string{job.Name, url}, fields...), "\t")
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), line)
 				}
 				return nil
 			})
 		},
 	}
 
-	cmd.Flags().StringVar(&folder, "folder", "", "Folder path to list jobs from")
+	cmd.Flags().StringVar(&folder, "folder", "", "Folder path to list jobs from (defaults to the context default folder; pass / for the root)")
+	cmdutil.SetJobPathArg(cmd, 0)
+	cmdutil.SetJobPathFlag(cmd, "folder")
+	cmd.Flags().StringSliceVar(&filterArgs, "filter", nil, "Filter jobs (repeatable): key[op]value, e.g. status=failed")
+	cmd.Flags().StringVar(&selectArg, "select", "", "Select additional fields (comma-separated)")
+	shared.AddFullPathsFlag(cmd, &fullPaths)
+	cmdutil.SetFlagEnum(cmd, "select", jobSelectFieldNames()...)
+	cmdutil.SetFilterSupport(cmd, jobFilterKeys(), filter.Operators())
 	return cmd
 }
 
 func newJobViewCmd(f *cmdutil.Factory) *cobra.Command {
+	var urlOnly bool
+
 	cmd := &cobra.Command{
 		Use:   "view <jobPath>",
 		Short: "View job details",
 		Args:  cobra.ExactArgs(1),
+		// Completion reads the job index `jk job paths` maintains.
+		ValidArgsFunction: completeJobPaths(f),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
-			jobPath := fmt.Sprintf("/%s/api/json", jenkins.EncodeJobPath(args[0]))
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			path := fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath))
 
 			var data map[string]any
-			_, err = client.Do(client.NewRequest(), "GET", jobPath, &data)
+			resp, err := client.Do(client.NewRequest(), "GET", path, &data)
 			if err != nil {
 				return err
 			}
+			if err := shared.CheckResponse(resp, fmt.Sprintf("job %s", jobPath)); err != nil {
+				return err
+			}
+			if urlOnly {
+				url, _ := data["url"].(string)
+				return shared.PrintURLs(cmd, url)
+			}
 
 			return shared.PrintOutput(cmd, data, func() error {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Name: %v\n", data["name"])
@@ -129,12 +214,49 @@ func newJobViewCmd(f *cmdutil.Factory) *cobra.Command {
 					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Description: %s\n", desc)
 				}
 				if url, ok := data["url"].(string); ok {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "URL: %s\n", url)
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "URL: %s\n", shared.Hyperlink(f, url, url))
 				}
 				return nil
 			})
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	shared.AddURLOnlyFlag(cmd, &urlOnly)
+	return cmd
+}
+
+// newJobToggleCmd builds `jk job enable` and `jk job disable`, which map to the
+// Jenkins /enable and /disable job actions.
+func newJobToggleCmd(f *cmdutil.Factory, action string) *cobra.Command {
+	past := action + "d"
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s <jobPath>", action),
+		Short: fmt.Sprintf("%s a job", strings.ToUpper(action[:1])+action[1:]),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
 
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			path := fmt.Sprintf("/%s/%s", jobpath.Encode(jobPath), action)
+			resp, err := client.Do(client.NewRequest(), http.MethodPost, path, nil)
+			if err != nil {
+				return err
+			}
+			if err := shared.CheckResponse(resp, fmt.Sprintf("job %s", jobPath)); err != nil {
+				return err
+			}
+
+			return shared.PrintAction(cmd, shared.ActionResult{Action: action, Target: jobPath, Status: shared.ActionDone}, "Job %s %s", jobPath, past)
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
 	return cmd
 }
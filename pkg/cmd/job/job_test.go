@@ -0,0 +1,96 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func runJobCmd(t *testing.T, status int, body string, args ...string) (string, error) {
+	t.Helper()
+
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jk/api/status" || r.URL.Path == "/crumbIssuer/api/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+
+	ios, _, stdout, _ := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: ios,
+		Config: func() (*config.Config, error) {
+			return &config.Config{Contexts: map[string]*config.Context{}}, nil
+		},
+		JenkinsClient: func(context.Context, string) (*jenkins.Client, error) {
+			return client, nil
+		},
+	}
+
+	cmd := NewCmdJob(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SilenceErrors = true
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func requireExitCode(t *testing.T, err error, code int, msg string) {
+	t.Helper()
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, code, exitErr.Code)
+	require.Contains(t, exitErr.Msg, msg)
+}
+
+func TestJobListMissingFolder(t *testing.T) {
+	_, err := runJobCmd(t, http.StatusNotFound, `{}`, "ls", "--folder", "team/nonexistent")
+	requireExitCode(t, err, 3, "folder team/nonexistent not found")
+}
+
+func TestJobListForbiddenFolder(t *testing.T) {
+	_, err := runJobCmd(t, http.StatusForbidden, `{}`, "ls", "team/secret")
+	requireExitCode(t, err, 5, "permission denied for folder team/secret")
+}
+
+func TestJobListEmptyFolder(t *testing.T) {
+	out, err := runJobCmd(t, http.StatusOK, `{"jobs":[]}`, "ls", "team/empty")
+	require.NoError(t, err)
+	require.Contains(t, out, "No jobs found in team/empty")
+}
+
+func TestJobViewMissingJob(t *testing.T) {
+	_, err := runJobCmd(t, http.StatusNotFound, `{}`, "view", "team/missing")
+	requireExitCode(t, err, 3, "job team/missing not found")
+}
+
+func TestJobViewForbiddenJob(t *testing.T) {
+	_, err := runJobCmd(t, http.StatusForbidden, `{}`, "view", "team/secret")
+	requireExitCode(t, err, 5, "permission denied for job team/secret")
+}
+
+func TestJobEnableDisable(t *testing.T) {
+	out, err := runJobCmd(t, http.StatusOK, `{}`, "enable", "team/app")
+	require.NoError(t, err)
+	require.Equal(t, "Job team/app enabled\n", out)
+
+	out, err = runJobCmd(t, http.StatusOK, `{}`, "disable", "team/app")
+	require.NoError(t, err)
+	require.Equal(t, "Job team/app disabled\n", out)
+
+	_, err = runJobCmd(t, http.StatusNotFound, `{}`, "enable", "team/ghost")
+	requireExitCode(t, err, 3, "job team/ghost not found")
+}
@@ -0,0 +1,222 @@
+package job
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const (
+	lintEndpoint     = "/pipeline-model-converter/validate"
+	lintErrorsHeader = "Errors encountered validating Jenkinsfile:"
+	cpsScmDefinition = "org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition"
+)
+
+type jobLintOutput struct {
+	SchemaVersion string   `json:"schemaVersion"`
+	Source        string   `json:"source"`
+	Valid         bool     `json:"valid"`
+	Errors        []string `json:"errors,omitempty"`
+}
+
+// pipelineConfig is the part of a pipeline job's config.xml that carries an
+// inline Jenkinsfile.
+type pipelineConfig struct {
+	Definition struct {
+		Class  string `xml:"class,attr"`
+		Script string `xml:"script"`
+	} `xml:"definition"`
+}
+
+func newJobLintCmd(f *cmdutil.Factory) *cobra.Command {
+	var file string
+	var jobArg string
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Validate a declarative Jenkinsfile against the server",
+		Long: `Validate a declarative Jenkinsfile with the server's pipeline linter
+(/pipeline-model-converter/validate).
+
+By default ./Jenkinsfile is validated; --file - reads stdin. With --job the
+script configured inline on a pipeline job is validated instead, which catches
+drift between the job and the repository.`,
+		Example: `  # Validate ./Jenkinsfile before pushing
+  jk job lint
+
+  # Validate from stdin
+  git show HEAD:Jenkinsfile | jk job lint --file -
+
+  # Validate the script stored on a job
+  jk job lint --job team/app`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jobArg != "" && cmd.Flags().Changed("file") {
+				return shared.NewExitError(2, "--file and --job cannot be combined")
+			}
+
+			var (
+				script string
+				source string
+			)
+			if jobArg == "" {
+				data, err := readJenkinsfile(cmd, file)
+				if err != nil {
+					return err
+				}
+				script, source = string(data), file
+				if file == "-" {
+					source = "stdin"
+				}
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			if jobArg != "" {
+				jobPath, err := shared.ResolveJobPath(cmd, client, jobArg)
+				if err != nil {
+					return err
+				}
+				script, err = fetchJobScript(shared.CommandContext(cmd), client, jobPath)
+				if err != nil {
+					return err
+				}
+				source = "job " + jobPath
+			}
+
+			if strings.TrimSpace(script) == "" {
+				return shared.NewExitError(2, fmt.Sprintf("%s is empty", source))
+			}
+
+			output, err := lintJenkinsfile(client, script)
+			if err != nil {
+				return err
+			}
+			output.Source = source
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				if output.Valid {
+					_, _ = fmt.Fprintf(w, "%s: Jenkinsfile successfully validated\n", source)
+					return nil
+				}
+				for _, msg := range output.Errors {
+					_, _ = fmt.Fprintln(w, msg)
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			if !output.Valid {
+				return shared.NewExitError(2, "")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "Jenkinsfile", "Path to the Jenkinsfile (use - for stdin)")
+	cmd.Flags().StringVar(&jobArg, "job", "", "Validate the inline script configured on this pipeline job")
+	cmdutil.SetJobPathFlag(cmd, "job")
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		2: "Jenkinsfile has validation errors",
+		8: "Server has no declarative pipeline linter",
+	})
+	return cmd
+}
+
+func readJenkinsfile(cmd *cobra.Command, file string) ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if file == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, shared.NewExitError(2, fmt.Sprintf("%s not found; pass --file or --job", file))
+		}
+		return nil, fmt.Errorf("read Jenkinsfile: %w", err)
+	}
+	return data, nil
+}
+
+func lintJenkinsfile(client shared.Doer, script string) (jobLintOutput, error) {
+	req := client.NewRequest().SetFormData(map[string]string{"jenkinsfile": script})
+	resp, err := client.Do(req, http.MethodPost, lintEndpoint, nil)
+	if err != nil {
+		return jobLintOutput{}, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return jobLintOutput{}, shared.NewExitError(8, "server does not support Jenkinsfile validation (pipeline-model-definition plugin not installed)")
+	}
+	if err := shared.CheckResponse(resp, "Jenkinsfile validation"); err != nil {
+		return jobLintOutput{}, err
+	}
+
+	errs := parseLintErrors(resp.String())
+	return jobLintOutput{SchemaVersion: "1.0", Valid: len(errs) == 0, Errors: errs}, nil
+}
+
+// parseLintErrors extracts one message per error from the linter's text
+// response. Each error starts with "WorkflowScript: <line>:" and is followed
+// by an indented source excerpt, which is dropped.
+func parseLintErrors(body string) []string {
+	idx := strings.Index(body, lintErrorsHeader)
+	if idx < 0 {
+		return nil
+	}
+
+	var errs, fallback []string
+	for _, line := range strings.Split(body[idx+len(lintErrorsHeader):], "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+		case strings.HasPrefix(trimmed, "WorkflowScript:"):
+			errs = append(errs, trimmed)
+		case line == trimmed:
+			fallback = append(fallback, trimmed)
+		}
+	}
+	if len(errs) == 0 {
+		errs = fallback
+	}
+	if len(errs) == 0 {
+		errs = []string{"Jenkinsfile validation failed"}
+	}
+	return errs
+}
+
+func fetchJobScript(ctx context.Context, client shared.Doer, jobPath string) (string, error) {
+	data, err := shared.FetchJobConfig(ctx, client, jobPath)
+	if err != nil {
+		return "", err
+	}
+
+	var cfg pipelineConfig
+	if err := xml.Unmarshal(shared.StripXMLDeclaration(data), &cfg); err != nil {
+		return "", fmt.Errorf("parse config.xml for %s: %w", jobPath, err)
+	}
+	switch {
+	case cfg.Definition.Class == cpsScmDefinition:
+		return "", shared.NewExitError(2, fmt.Sprintf("job %s loads its Jenkinsfile from SCM; lint the file in your checkout with --file", jobPath))
+	case strings.TrimSpace(cfg.Definition.Script) == "":
+		return "", shared.NewExitError(2, fmt.Sprintf("job %s has no inline pipeline script", jobPath))
+	}
+	return cfg.Definition.Script, nil
+}
@@ -0,0 +1,106 @@
+package job
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const lintFailureBody = `Errors encountered validating Jenkinsfile:
+WorkflowScript: 4: Unknown stage section "stepz". @ line 4, column 5.
+       stage('Build') {
+       ^
+
+WorkflowScript: 4: Expected one of "steps", "stages", or "parallel" for stage "Build" @ line 4, column 5.
+       stage('Build') {
+       ^
+`
+
+func executeLint(t *testing.T, f *cmdutil.Factory, args ...string) error {
+	t.Helper()
+	cmd := newJobLintCmd(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(args)
+	cmd.SetOut(f.IOStreams.Out)
+	cmd.SetErr(f.IOStreams.ErrOut)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func writeJenkinsfile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "Jenkinsfile")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func requireExit(t *testing.T, err error, code int) {
+	t.Helper()
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, code, exitErr.Code)
+}
+
+func TestJobLintValid(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/pipeline-model-converter/validate", http.StatusOK, "Jenkinsfile successfully validated.\n")
+	f, stdout, _ := fakejenkins.Factory(client)
+
+	path := writeJenkinsfile(t, "pipeline { agent any }")
+	require.NoError(t, executeLint(t, f, "--file", path))
+	require.Contains(t, stdout.String(), "successfully validated")
+
+	req := server.LastRequest(http.MethodPost, "/pipeline-model-converter/validate")
+	require.Equal(t, "pipeline { agent any }", req.Form().Get("jenkinsfile"))
+}
+
+func TestJobLintReportsErrorsOnePerLine(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/pipeline-model-converter/validate", http.StatusOK, lintFailureBody)
+	f, stdout, _ := fakejenkins.Factory(client)
+
+	err := executeLint(t, f, "--file", writeJenkinsfile(t, "pipeline {}"))
+	requireExit(t, err, 2)
+	require.Equal(t, `WorkflowScript: 4: Unknown stage section "stepz". @ line 4, column 5.
+WorkflowScript: 4: Expected one of "steps", "stages", or "parallel" for stage "Build" @ line 4, column 5.
+`, stdout.String())
+}
+
+func TestJobLintUnsupportedServer(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+	f, _, _ := fakejenkins.Factory(client)
+
+	err := executeLint(t, f, "--file", writeJenkinsfile(t, "pipeline {}"))
+	requireExit(t, err, 8)
+	require.Contains(t, err.Error(), "does not support Jenkinsfile validation")
+}
+
+func TestJobLintValidatesJobScript(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/app/config.xml", http.StatusOK, `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job">
+  <definition class="org.jenkinsci.plugins.workflow.cps.CpsFlowDefinition" plugin="workflow-cps">
+    <script>pipeline { agent none }</script>
+    <sandbox>true</sandbox>
+  </definition>
+</flow-definition>`)
+	server.Handle(http.MethodGet, "/job/team/job/scm/config.xml", http.StatusOK, `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition><definition class="org.jenkinsci.plugins.workflow.cps.CpsScmFlowDefinition"><scriptPath>Jenkinsfile</scriptPath></definition></flow-definition>`)
+	server.Handle(http.MethodPost, "/pipeline-model-converter/validate", http.StatusOK, "Jenkinsfile successfully validated.\n")
+	f, _, _ := fakejenkins.Factory(client)
+
+	require.NoError(t, executeLint(t, f, "--job", "/team/app"))
+	req := server.LastRequest(http.MethodPost, "/pipeline-model-converter/validate")
+	require.Equal(t, "pipeline { agent none }", req.Form().Get("jenkinsfile"))
+
+	requireExit(t, executeLint(t, f, "--job", "/team/scm"), 2)
+}
@@ -0,0 +1,153 @@
+package job
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/jobindex"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func newJobPathsCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		prefix   string
+		maxPaths int
+		refresh  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "paths",
+		Short: "Print every job path, one per line",
+		Long: `Print the path of every job in the context, one per line and sorted, for
+shell pickers, Makefiles, and grep.
+
+Paths come from a job index in the cache directory, rebuilt when it is older
+than 15 minutes or with --refresh. When the controller cannot be reached, the
+cached index is printed with a warning on stderr. The command never prompts,
+so it is safe to call from shell widgets. Output is the paths and nothing
+else; --json prints them as an array of strings.`,
+		Example: `  # Pick a job with fzf and view it
+  jk job view "$(jk job paths | fzf)"
+
+  # Jobs under team/
+  jk job paths --prefix team/`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxPaths < 0 {
+				return shared.NewExitError(2, "--max must not be negative")
+			}
+			if ios, err := f.Streams(); err == nil {
+				ios.SetNeverPrompt(true)
+			}
+			secret.DisablePrompts()
+
+			index, err := loadJobIndex(cmd, f, refresh)
+			if err != nil {
+				return err
+			}
+			paths := index.Match(prefix, maxPaths)
+
+			return shared.PrintOutput(cmd, paths, func() error {
+				w := cmd.OutOrStdout()
+				for _, path := range paths {
+					_, _ = fmt.Fprintln(w, path)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&prefix, "prefix", "", "Only print paths starting with this prefix, e.g. team/")
+	cmd.Flags().IntVar(&maxPaths, "max", 0, "Print at most N paths (0 for all)")
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Rebuild the job index even if it is recent")
+	return cmd
+}
+
+// loadJobIndex returns the context's job index, rebuilding it when it is
+// missing, stale, or refresh is set. A failed rebuild falls back to the
+// cached index with a warning.
+func loadJobIndex(cmd *cobra.Command, f *cmdutil.Factory, refresh bool) (*jobindex.Index, error) {
+	// Creating the client does not contact the controller; it fails only
+	// when the context cannot be resolved, and then the index is looked up
+	// by the context name alone.
+	client, clientErr := shared.JenkinsClient(cmd, f)
+	var contextName string
+	if client != nil {
+		contextName = client.ContextName()
+	} else {
+		name, err := indexContextName(cmd, f)
+		if err != nil {
+			return nil, clientErr
+		}
+		contextName = name
+	}
+
+	cached, err := jobindex.Load(contextName)
+	if err != nil {
+		cmdutil.Warnf(cmd, "%v", err)
+		cached = nil
+	}
+	if cached != nil && !refresh && !cached.Stale(time.Now()) {
+		return cached, nil
+	}
+
+	err = clientErr
+	var jobs []string
+	if err == nil {
+		jobs, err = runcmd.DiscoverJobs(shared.CommandContext(cmd), client, "")
+	}
+	if err != nil {
+		if cached == nil {
+			return nil, err
+		}
+		cmdutil.Warnf(cmd, "could not refresh the job index (%v); using paths cached at %s", err, shared.FormatTime(cached.UpdatedAt))
+		return cached, nil
+	}
+
+	index := jobindex.New(jobs)
+	if err := jobindex.Save(contextName, index); err != nil {
+		cmdutil.Warnf(cmd, "%v", err)
+	}
+	return &index, nil
+}
+
+func indexContextName(cmd *cobra.Command, f *cmdutil.Factory) (string, error) {
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		return "", err
+	}
+	name, err := shared.ResolveContextName(cmd, cfg)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", errors.New("no active context")
+	}
+	return name, nil
+}
+
+// completeJobPaths completes job path arguments from the cached job index
+// only; completion never contacts the controller.
+func completeJobPaths(f *cmdutil.Factory) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		contextName, err := indexContextName(cmd, f)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		index, err := jobindex.Load(contextName)
+		if err != nil || index == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return index.Match(strings.TrimSpace(toComplete), 0), cobra.ShellCompDirectiveNoFileComp
+	}
+}
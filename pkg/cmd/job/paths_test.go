@@ -0,0 +1,75 @@
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func runJobPaths(t *testing.T, client *jenkins.Client, args ...string) (*bytes.Buffer, *bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.AddCommand(NewCmdJob(f))
+	root.SetArgs(append([]string{"job", "paths"}, args...))
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	err := root.Execute()
+	return stdout, stderr, err
+}
+
+func TestJobPathsFromIndex(t *testing.T) {
+	const folderClass = "com.cloudbees.hudson.plugins.folder.Folder"
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "team", "_class": folderClass},
+		{"name": "lint", "_class": "hudson.model.FreeStyleProject"},
+	}})
+	server.HandleJSON(http.MethodGet, "/job/team/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "web", "_class": "hudson.model.FreeStyleProject"},
+		{"name": "api", "_class": "hudson.model.FreeStyleProject"},
+	}})
+
+	stdout, stderr, err := runJobPaths(t, client)
+	require.NoError(t, err)
+	require.Equal(t, "lint\nteam/api\nteam/web\n", stdout.String())
+	require.Empty(t, stderr.String())
+
+	stdout, _, err = runJobPaths(t, client, "--prefix", "team/", "--max", "1", "--json")
+	require.NoError(t, err)
+	var paths []string
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &paths))
+	require.Equal(t, []string{"team/api"}, paths)
+
+	stdout, _, err = runJobPaths(t, client, "--prefix", "nope/", "--json")
+	require.NoError(t, err)
+	require.JSONEq(t, `[]`, stdout.String())
+
+	// A controller that cannot be reached leaves the cached index in use.
+	server.Handle(http.MethodGet, "/api/json", http.StatusBadGateway, "")
+	stdout, stderr, err = runJobPaths(t, client, "--refresh")
+	require.NoError(t, err)
+	require.Equal(t, "lint\nteam/api\nteam/web\n", stdout.String())
+	require.Contains(t, stderr.String(), "warning: could not refresh the job index")
+}
+
+func TestJobPathsWithoutIndexFails(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/api/json", http.StatusBadGateway, "")
+
+	stdout, _, err := runJobPaths(t, client)
+	require.Error(t, err)
+	require.Empty(t, stdout.String())
+}
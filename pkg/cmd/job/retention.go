@@ -0,0 +1,385 @@
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const retentionTree = "firstBuild[number,timestamp],lastBuild[number]"
+
+// Retention issues, in the order they are listed.
+const (
+	retentionNoDiscarder = "no-discarder"
+	retentionUnbounded   = "unbounded"
+	retentionOverBuilds  = "builds-over-limit"
+	retentionOverDays    = "days-over-limit"
+)
+
+const (
+	logRotatorClass       = "hudson.tasks.LogRotator"
+	buildDiscarderElement = "jenkins.model.BuildDiscarderProperty"
+)
+
+type jobRetentionOutput struct {
+	SchemaVersion string             `json:"schemaVersion"`
+	Folder        string             `json:"folder,omitempty"`
+	MaxBuilds     int                `json:"maxBuilds,omitempty"`
+	MaxDays       int                `json:"maxDays,omitempty"`
+	Items         []jobRetentionItem `json:"items"`
+	shared.Result
+}
+
+type jobRetentionItem struct {
+	JobPath string `json:"jobPath"`
+	// Discarder is nil when the job keeps every build.
+	Discarder *buildDiscarder `json:"discarder,omitempty"`
+	// Builds spans the first to the last build number, an upper bound on
+	// the builds kept: counting them exactly would load every build.
+	Builds      int64    `json:"builds"`
+	OldestBuild string   `json:"oldestBuild,omitempty"`
+	OldestDays  int      `json:"oldestDays,omitempty"`
+	Issues      []string `json:"issues"`
+}
+
+// buildDiscarder is a job's build discarder. The limits are nil when unset
+// (Jenkins writes -1 for them).
+type buildDiscarder struct {
+	Class              string `json:"class"`
+	DaysToKeep         *int   `json:"daysToKeep,omitempty"`
+	NumToKeep          *int   `json:"numToKeep,omitempty"`
+	ArtifactDaysToKeep *int   `json:"artifactDaysToKeep,omitempty"`
+	ArtifactNumToKeep  *int   `json:"artifactNumToKeep,omitempty"`
+}
+
+type retentionPolicy struct {
+	MaxBuilds int
+	MaxDays   int
+}
+
+func newJobRetentionCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		folder      string
+		recursive   bool
+		policy      retentionPolicy
+		all         bool
+		okOnPartial bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Report jobs that keep builds without limit",
+		Long: `Read the build discarder of every job in a folder from its config.xml (a
+top-level logRotator or a BuildDiscarderProperty strategy) and report the jobs
+that keep every build. --max-builds and --max-days add a policy: a job whose
+numToKeep or daysToKeep is unset or above the limit is reported too.
+
+Each job's build count (the span from its first to its last build number)
+and the age of its oldest build come from one cheap query, and the worst
+offenders are listed first. Jobs are inspected four at a time; --all lists
+compliant jobs as well.`,
+		Example: `  # Jobs under team/ without a build discarder
+  jk job retention --folder team --recursive
+
+  # Jobs keeping more than 50 builds or 30 days
+  jk job retention --folder team --recursive --max-builds 50 --max-days 30 --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if policy.MaxBuilds < 0 || policy.MaxDays < 0 {
+				return shared.NewExitError(2, "--max-builds and --max-days must not be negative")
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			targetFolder, err := shared.ResolveFolder(cmd, client, folder)
+			if err != nil {
+				return err
+			}
+
+			ctx := shared.CommandContext(cmd)
+			var jobs []string
+			if recursive {
+				jobs, err = runcmd.DiscoverJobs(ctx, client, targetFolder)
+			} else {
+				jobs, err = runcmd.ListJobs(ctx, client, targetFolder)
+			}
+			if err != nil {
+				return err
+			}
+			if len(jobs) == 0 {
+				subject := "Jenkins root"
+				if targetFolder != "" {
+					subject = "folder " + targetFolder
+				}
+				return shared.NewExitError(3, fmt.Sprintf("no jobs found in %s", subject))
+			}
+
+			output := jobRetentionOutput{
+				SchemaVersion: "1.0",
+				Folder:        targetFolder,
+				MaxBuilds:     policy.MaxBuilds,
+				MaxDays:       policy.MaxDays,
+				Result:        shared.NewResult(),
+			}
+			for _, item := range inspectRetention(ctx, client, jobs, policy, client.ServerNow(), shared.Concurrency(f), &output.Result) {
+				if all || len(item.Issues) > 0 {
+					output.Items = append(output.Items, item)
+				}
+			}
+			if output.Items == nil {
+				output.Items = []jobRetentionItem{}
+			}
+			sortRetentionItems(output.Items)
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				renderJobRetention(cmd.OutOrStdout(), output)
+				output.WriteIssues(cmd)
+				return nil
+			}); err != nil {
+				return err
+			}
+			return output.ExitError("jobs", okOnPartial)
+		},
+	}
+
+	cmd.Flags().StringVar(&folder, "folder", "", "Folder to inspect (defaults to the context default folder; pass / for the root)")
+	cmdutil.SetJobPathFlag(cmd, "folder")
+	cmd.Flags().BoolVar(&recursive, "recursive", false, "Include jobs in subfolders")
+	cmd.Flags().IntVar(&policy.MaxBuilds, "max-builds", 0, "Also report jobs keeping more than N builds or with no numToKeep")
+	cmd.Flags().IntVar(&policy.MaxDays, "max-days", 0, "Also report jobs keeping builds longer than N days or with no daysToKeep")
+	cmd.Flags().BoolVar(&all, "all", false, "List jobs that meet the policy too")
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+	return cmd
+}
+
+// inspectRetention reads the discarder and build span of each job, a few at
+// a time. Jobs that cannot be read are recorded as failures in result.
+func inspectRetention(ctx context.Context, client shared.Doer, jobs []string, policy retentionPolicy, now time.Time, concurrency int, result *shared.Result) []jobRetentionItem {
+	items := make([]*jobRetentionItem, len(jobs))
+	errs := make([]error, len(jobs))
+	shared.ForEach(len(jobs), concurrency, func(i int) {
+		if ctx.Err() != nil {
+			errs[i] = ctx.Err()
+			return
+		}
+		items[i], errs[i] = inspectJobRetention(ctx, client, jobs[i], policy, now)
+	})
+
+	inspected := make([]jobRetentionItem, 0, len(jobs))
+	for i, job := range jobs {
+		if errs[i] != nil {
+			result.Fail(job, errs[i])
+			continue
+		}
+		result.Succeed()
+		inspected = append(inspected, *items[i])
+	}
+	return inspected
+}
+
+func inspectJobRetention(ctx context.Context, client shared.Doer, jobPath string, policy retentionPolicy, now time.Time) (*jobRetentionItem, error) {
+	config, err := shared.FetchJobConfig(ctx, client, jobPath)
+	if err != nil {
+		return nil, err
+	}
+	discarder, err := parseBuildDiscarder(config)
+	if err != nil {
+		return nil, fmt.Errorf("parse config.xml for %s: %w", jobPath, err)
+	}
+
+	var builds struct {
+		FirstBuild *struct {
+			Number    int64 `json:"number"`
+			Timestamp int64 `json:"timestamp"`
+		} `json:"firstBuild"`
+		LastBuild *struct {
+			Number int64 `json:"number"`
+		} `json:"lastBuild"`
+	}
+	req := client.NewRequest().SetQueryParam("tree", retentionTree)
+	resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath)), &builds)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.CheckResponse(resp, fmt.Sprintf("job %s", jobPath)); err != nil {
+		return nil, err
+	}
+
+	item := &jobRetentionItem{JobPath: jobPath, Discarder: discarder, Issues: retentionIssues(discarder, policy)}
+	if builds.FirstBuild != nil && builds.LastBuild != nil && builds.LastBuild.Number >= builds.FirstBuild.Number {
+		item.Builds = builds.LastBuild.Number - builds.FirstBuild.Number + 1
+	}
+	if builds.FirstBuild != nil && builds.FirstBuild.Timestamp > 0 {
+		oldest := time.UnixMilli(builds.FirstBuild.Timestamp)
+		item.OldestBuild = shared.FormatTime(oldest)
+		if age := now.Sub(oldest); age > 0 {
+			item.OldestDays = int(age / (24 * time.Hour))
+		}
+	}
+	return item, nil
+}
+
+// retentionIssues lists how a discarder falls short of policy.
+func retentionIssues(discarder *buildDiscarder, policy retentionPolicy) []string {
+	issues := []string{}
+	if discarder == nil {
+		return append(issues, retentionNoDiscarder)
+	}
+	if discarder.Class == logRotatorClass && discarder.NumToKeep == nil && discarder.DaysToKeep == nil {
+		return append(issues, retentionUnbounded)
+	}
+	if policy.MaxBuilds > 0 && discarder.Class == logRotatorClass && (discarder.NumToKeep == nil || *discarder.NumToKeep > policy.MaxBuilds) {
+		issues = append(issues, retentionOverBuilds)
+	}
+	if policy.MaxDays > 0 && discarder.Class == logRotatorClass && (discarder.DaysToKeep == nil || *discarder.DaysToKeep > policy.MaxDays) {
+		issues = append(issues, retentionOverDays)
+	}
+	return issues
+}
+
+// sortRetentionItems puts jobs with issues first, then the most builds, then
+// the oldest build, then by path.
+func sortRetentionItems(items []jobRetentionItem) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if (len(a.Issues) > 0) != (len(b.Issues) > 0) {
+			return len(a.Issues) > 0
+		}
+		if a.Builds != b.Builds {
+			return a.Builds > b.Builds
+		}
+		if a.OldestDays != b.OldestDays {
+			return a.OldestDays > b.OldestDays
+		}
+		return a.JobPath < b.JobPath
+	})
+}
+
+// parseBuildDiscarder finds the build discarder in config.xml: the strategy
+// of a BuildDiscarderProperty, or the top-level logRotator older jobs keep.
+// Discarders other than LogRotator are reported by class only.
+func parseBuildDiscarder(data []byte) (*buildDiscarder, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(shared.StripXMLDeclaration(data)))
+
+	var (
+		stack     []string
+		discarder *buildDiscarder
+		depth     = -1
+		text      strings.Builder
+	)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, tok.Name.Local)
+			text.Reset()
+			if discarder != nil || depth >= 0 {
+				continue
+			}
+			isStrategy := tok.Name.Local == "strategy" && len(stack) >= 2 && stack[len(stack)-2] == buildDiscarderElement
+			isLogRotator := tok.Name.Local == "logRotator" && len(stack) == 2
+			if !isStrategy && !isLogRotator {
+				continue
+			}
+			class := logRotatorClass
+			for _, attr := range tok.Attr {
+				if attr.Name.Local == "class" {
+					class = attr.Value
+				}
+			}
+			discarder = &buildDiscarder{Class: class}
+			depth = len(stack)
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			switch {
+			case depth >= 0 && len(stack) == depth:
+				depth = -2
+			case depth >= 0 && len(stack) == depth+1:
+				discarder.setLimit(tok.Name.Local, strings.TrimSpace(text.String()))
+			}
+			text.Reset()
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return discarder, nil
+}
+
+func (d *buildDiscarder) setLimit(name, value string) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return
+	}
+	switch name {
+	case "daysToKeep":
+		d.DaysToKeep = &n
+	case "numToKeep":
+		d.NumToKeep = &n
+	case "artifactDaysToKeep":
+		d.ArtifactDaysToKeep = &n
+	case "artifactNumToKeep":
+		d.ArtifactNumToKeep = &n
+	}
+}
+
+// keepSummary describes what a discarder keeps, e.g. "50 builds, 30 days".
+func (d *buildDiscarder) keepSummary() string {
+	if d == nil {
+		return "all builds"
+	}
+	if d.Class != logRotatorClass {
+		return d.Class
+	}
+	var parts []string
+	if d.NumToKeep != nil {
+		parts = append(parts, fmt.Sprintf("%d builds", *d.NumToKeep))
+	}
+	if d.DaysToKeep != nil {
+		parts = append(parts, fmt.Sprintf("%d days", *d.DaysToKeep))
+	}
+	if len(parts) == 0 {
+		return "all builds"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func renderJobRetention(w io.Writer, output jobRetentionOutput) {
+	if len(output.Items) == 0 {
+		_, _ = fmt.Fprintln(w, "Every job limits its build history")
+		return
+	}
+	for _, item := range output.Items {
+		oldest := "-"
+		if item.OldestBuild != "" {
+			oldest = fmt.Sprintf("%dd", item.OldestDays)
+		}
+		issues := strings.Join(item.Issues, ",")
+		if issues == "" {
+			issues = "ok"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%d builds\toldest %s\t%s\n", item.JobPath, item.Discarder.keepSummary(), item.Builds, oldest, issues)
+	}
+}
@@ -0,0 +1,147 @@
+package job
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+const pipelineWithDiscarder = `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job">
+  <properties>
+    <jenkins.model.BuildDiscarderProperty>
+      <strategy class="hudson.tasks.LogRotator">
+        <daysToKeep>-1</daysToKeep>
+        <numToKeep>10</numToKeep>
+        <artifactDaysToKeep>-1</artifactDaysToKeep>
+        <artifactNumToKeep>3</artifactNumToKeep>
+      </strategy>
+    </jenkins.model.BuildDiscarderProperty>
+  </properties>
+</flow-definition>
+`
+
+const freestyleUnbounded = `<project>
+  <logRotator class="hudson.tasks.LogRotator">
+    <daysToKeep>-1</daysToKeep>
+    <numToKeep>-1</numToKeep>
+    <artifactDaysToKeep>-1</artifactDaysToKeep>
+    <artifactNumToKeep>-1</artifactNumToKeep>
+  </logRotator>
+</project>
+`
+
+func TestParseBuildDiscarder(t *testing.T) {
+	discarder, err := parseBuildDiscarder([]byte(pipelineWithDiscarder))
+	require.NoError(t, err)
+	require.NotNil(t, discarder)
+	require.Equal(t, logRotatorClass, discarder.Class)
+	require.Nil(t, discarder.DaysToKeep)
+	require.Equal(t, 10, *discarder.NumToKeep)
+	require.Equal(t, 3, *discarder.ArtifactNumToKeep)
+	require.Equal(t, "10 builds", discarder.keepSummary())
+
+	discarder, err = parseBuildDiscarder([]byte(freestyleUnbounded))
+	require.NoError(t, err)
+	require.Equal(t, []string{retentionUnbounded}, retentionIssues(discarder, retentionPolicy{}))
+
+	discarder, err = parseBuildDiscarder([]byte(`<project><properties/></project>`))
+	require.NoError(t, err)
+	require.Nil(t, discarder)
+
+	custom := `<flow-definition><properties><jenkins.model.BuildDiscarderProperty><strategy class="org.example.CustomDiscarder"/></jenkins.model.BuildDiscarderProperty></properties></flow-definition>`
+	discarder, err = parseBuildDiscarder([]byte(custom))
+	require.NoError(t, err)
+	require.Equal(t, "org.example.CustomDiscarder", discarder.Class)
+	require.Empty(t, retentionIssues(discarder, retentionPolicy{MaxBuilds: 5}))
+}
+
+func runJobRetention(t *testing.T, client *jenkins.Client, args ...string) (*bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, _ := fakejenkins.Factory(client)
+
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.AddCommand(NewCmdJob(f))
+	root.SetArgs(append([]string{"job", "retention"}, args...))
+	root.SetOut(stdout)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	return stdout, root.Execute()
+}
+
+func TestJobRetentionReportsWorstFirst(t *testing.T) {
+	const folderClass = "com.cloudbees.hudson.plugins.folder.Folder"
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/team/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "app", "_class": "org.jenkinsci.plugins.workflow.job.WorkflowJob"},
+		{"name": "lint", "_class": "hudson.model.FreeStyleProject"},
+		{"name": "nightly", "_class": "hudson.model.FreeStyleProject"},
+		{"name": "sub", "_class": folderClass},
+	}})
+	server.HandleJSON(http.MethodGet, "/job/team/job/sub/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "deep", "_class": "hudson.model.FreeStyleProject"},
+	}})
+	server.Handle(http.MethodGet, "/job/team/job/app/config.xml", http.StatusOK, pipelineWithDiscarder)
+	server.Handle(http.MethodGet, "/job/team/job/lint/config.xml", http.StatusOK, freestyleUnbounded)
+	server.Handle(http.MethodGet, "/job/team/job/nightly/config.xml", http.StatusOK, `<project/>`)
+	server.Handle(http.MethodGet, "/job/team/job/sub/job/deep/config.xml", http.StatusOK, `<project/>`)
+
+	oldest := time.Now().Add(-72 * time.Hour).UnixMilli()
+	builds := func(first, last int64) map[string]any {
+		return map[string]any{
+			"firstBuild": map[string]any{"number": first, "timestamp": oldest},
+			"lastBuild":  map[string]any{"number": last},
+		}
+	}
+	server.HandleJSON(http.MethodGet, "/job/team/job/app/api/json", builds(91, 100))
+	server.HandleJSON(http.MethodGet, "/job/team/job/lint/api/json", builds(1, 40))
+	server.HandleJSON(http.MethodGet, "/job/team/job/nightly/api/json", builds(1, 900))
+	server.HandleJSON(http.MethodGet, "/job/team/job/sub/job/deep/api/json", builds(1, 5))
+
+	stdout, err := runJobRetention(t, client, "--folder", "team")
+	require.NoError(t, err)
+	require.Equal(t, "team/nightly\tall builds\t900 builds\toldest 3d\tno-discarder\n"+
+		"team/lint\tall builds\t40 builds\toldest 3d\tunbounded\n", stdout.String())
+
+	stdout, err = runJobRetention(t, client, "--folder", "team", "--recursive", "--max-builds", "5", "--json")
+	require.NoError(t, err)
+	var output jobRetentionOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+	paths := make([]string, len(output.Items))
+	for i, item := range output.Items {
+		paths[i] = item.JobPath
+	}
+	require.Equal(t, []string{"team/nightly", "team/lint", "team/app", "team/sub/deep"}, paths)
+	require.Equal(t, []string{retentionOverBuilds}, output.Items[2].Issues)
+	require.Equal(t, int64(10), output.Items[2].Builds)
+	require.Equal(t, 3, output.Items[2].OldestDays)
+	require.Equal(t, 4, output.Summary.Succeeded)
+}
+
+func TestJobRetentionPartialFailure(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/team/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "app", "_class": "hudson.model.FreeStyleProject"},
+		{"name": "secret", "_class": "hudson.model.FreeStyleProject"},
+	}})
+	server.Handle(http.MethodGet, "/job/team/job/app/config.xml", http.StatusOK, `<project/>`)
+	server.HandleJSON(http.MethodGet, "/job/team/job/app/api/json", map[string]any{})
+	server.Handle(http.MethodGet, "/job/team/job/secret/config.xml", http.StatusForbidden, "")
+
+	_, err := runJobRetention(t, client, "--folder", "team")
+	requireExitCode(t, err, 1, "1 of 2 jobs failed")
+
+	stdout, err := runJobRetention(t, client, "--folder", "team", "--ok-on-partial")
+	require.NoError(t, err)
+	require.Equal(t, "team/app\tall builds\t0 builds\toldest -\tno-discarder\n", stdout.String())
+}
@@ -0,0 +1,428 @@
+package job
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	triggerTypeCron       = "cron"
+	triggerTypeSCM        = "scm"
+	triggerTypeUpstream   = "upstream"
+	triggerTypeWebhook    = "generic-webhook"
+	triggerTypeFolderScan = "folder-scan"
+	triggerTypeOther      = "other"
+
+	// cronDisabledMarker prefixes each schedule line that --disable-cron
+	// turns into a cron comment, so --enable-cron restores exactly those.
+	cronDisabledMarker = "# jk:disabled "
+)
+
+// triggerTypes maps trigger classes, which are also their config.xml element
+// names, to the types jk reports.
+var triggerTypes = map[string]string{
+	"hudson.triggers.TimerTrigger":                                       triggerTypeCron,
+	"hudson.triggers.SCMTrigger":                                         triggerTypeSCM,
+	"jenkins.triggers.ReverseBuildTrigger":                               triggerTypeUpstream,
+	"org.jenkinsci.plugins.gwt.GenericTrigger":                           triggerTypeWebhook,
+	"com.cloudbees.hudson.plugins.folder.computed.PeriodicFolderTrigger": triggerTypeFolderScan,
+}
+
+type jobTriggersOutput struct {
+	SchemaVersion string `json:"schemaVersion"`
+	JobPath       string `json:"jobPath"`
+	// Source is the job whose config.xml was read: the multibranch parent
+	// for branch jobs, which have no editable config of their own.
+	Source   string       `json:"source"`
+	ReadOnly bool         `json:"readOnly"`
+	Triggers []jobTrigger `json:"triggers"`
+	// Changed counts the cron triggers --disable-cron or --enable-cron
+	// rewrote (or would rewrite, with DryRun).
+	Changed int    `json:"changed,omitempty"`
+	DryRun  bool   `json:"dryRun,omitempty"`
+	Diff    string `json:"diff,omitempty"`
+}
+
+type jobTrigger struct {
+	Type  string `json:"type"`
+	Class string `json:"class"`
+	// Schedule is the cron spec of cron, scm, and folder-scan triggers.
+	Schedule string `json:"schedule,omitempty"`
+	// Disabled is set on cron triggers whose schedule --disable-cron
+	// commented out.
+	Disabled bool              `json:"disabled,omitempty"`
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// specStart and specEnd delimit the raw <spec> text in config.xml.
+	specStart, specEnd int64
+}
+
+func newJobTriggersCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		disableCron bool
+		enableCron  bool
+		dryRun      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "triggers <jobPath>",
+		Short: "Show a job's triggers and pause or resume its cron schedule",
+		Long: `List the triggers in a job's config.xml: cron schedules (TimerTrigger), SCM
+polling, upstream (reverse build) triggers, generic webhook triggers, and any
+others by class. Secret-looking settings such as webhook tokens are redacted.
+
+--disable-cron comments out every line of the job's cron schedules and
+--enable-cron restores them; nothing else in config.xml changes. --dry-run
+prints the diff instead of saving it. Pipelines that declare triggers in their
+Jenkinsfile rewrite them on the next run.
+
+Multibranch branch jobs have no editable config; their triggers are read from
+the multibranch project and reported as read-only.`,
+		Example: `  jk job triggers team/nightly
+  jk job triggers team/nightly --disable-cron --dry-run
+  jk job triggers team/nightly --enable-cron`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if disableCron && enableCron {
+				return shared.NewExitError(2, "--disable-cron and --enable-cron are mutually exclusive")
+			}
+			mutate := disableCron || enableCron
+			if dryRun && !mutate {
+				return shared.NewExitError(2, "--dry-run requires --disable-cron or --enable-cron")
+			}
+
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			output := jobTriggersOutput{SchemaVersion: "1.0", JobPath: jobPath, Source: jobPath}
+			if parent := multibranchParentPath(client, jobPath); parent != "" {
+				if mutate {
+					return shared.NewExitError(2, fmt.Sprintf("job %s is a multibranch branch with no editable config; its triggers belong to %s or its Jenkinsfile", jobPath, parent))
+				}
+				output.Source, output.ReadOnly = parent, true
+			}
+
+			config, err := shared.FetchJobConfig(shared.CommandContext(cmd), client, output.Source)
+			if err != nil {
+				return err
+			}
+			triggers, err := parseJobTriggers(config, redactor)
+			if err != nil {
+				return fmt.Errorf("parse config.xml for %s: %w", output.Source, err)
+			}
+
+			if mutate {
+				updated, changed := setCronDisabled(config, triggers, disableCron)
+				output.Changed, output.DryRun = changed, dryRun
+				if changed > 0 {
+					output.Diff = configDiff(config, updated, "config.xml")
+					if !dryRun {
+						if err := postJobConfig(client, jobPath, updated); err != nil {
+							return err
+						}
+					}
+					if triggers, err = parseJobTriggers(updated, redactor); err != nil {
+						return fmt.Errorf("parse updated config.xml for %s: %w", jobPath, err)
+					}
+				}
+			}
+			output.Triggers = triggers
+
+			return shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				if mutate {
+					renderCronChange(w, output, disableCron)
+					return nil
+				}
+				renderJobTriggers(w, output)
+				return nil
+			})
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().BoolVar(&disableCron, "disable-cron", false, "Comment out the job's cron schedules")
+	cmd.Flags().BoolVar(&enableCron, "enable-cron", false, "Restore cron schedules disabled with --disable-cron")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the config.xml diff without saving it")
+	return cmd
+}
+
+// multibranchParentPath returns the multibranch project owning jobPath, or ""
+// when jobPath is not a branch job or the parent cannot be read.
+func multibranchParentPath(client shared.Doer, jobPath string) string {
+	parent, _ := jobpath.Split(jobPath)
+	if parent == "" {
+		return ""
+	}
+	var payload struct {
+		Class string `json:"_class"`
+	}
+	req := client.NewRequest().SetQueryParam("tree", "_class")
+	resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/api/json", jobpath.Encode(parent)), &payload)
+	if err != nil || resp.StatusCode() != http.StatusOK || !strings.Contains(strings.ToLower(payload.Class), "multibranch") {
+		return ""
+	}
+	return parent
+}
+
+func postJobConfig(client shared.Doer, jobPath string, config []byte) error {
+	path := fmt.Sprintf("/%s/config.xml", jobpath.Encode(jobPath))
+	req := client.NewRequest().
+		SetHeader("Content-Type", "application/xml").
+		SetBody(config)
+	resp, err := client.Do(req, http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	return shared.CheckResponse(resp, fmt.Sprintf("job %s", jobPath))
+}
+
+// parseJobTriggers walks config.xml with the token decoder and collects the
+// children of every <triggers> element: freestyle jobs keep them at the top
+// level, pipelines under PipelineTriggersJobProperty, and multibranch
+// projects on the project itself. Offsets of each <spec> are kept so cron
+// schedules can be rewritten in place.
+func parseJobTriggers(data []byte, redactor *filter.Redactor) ([]jobTrigger, error) {
+	body := shared.StripXMLDeclaration(data)
+	base := int64(len(data) - len(body))
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+
+	var (
+		stack         []string
+		triggers      []jobTrigger
+		current       *jobTrigger
+		triggersDepth = -1
+		triggerDepth  = -1
+		text          strings.Builder
+	)
+
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, tok.Name.Local)
+			depth := len(stack)
+			text.Reset()
+			switch {
+			case current == nil && triggersDepth < 0 && tok.Name.Local == "triggers":
+				triggersDepth = depth
+			case current == nil && triggersDepth > 0 && depth == triggersDepth+1:
+				class := tok.Name.Local
+				typ, ok := triggerTypes[class]
+				if !ok {
+					typ = triggerTypeOther
+				}
+				current = &jobTrigger{Type: typ, Class: class, specStart: -1}
+				triggerDepth = depth
+			case current != nil && depth == triggerDepth+1 && tok.Name.Local == "spec":
+				current.specStart = base + decoder.InputOffset()
+			}
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			depth := len(stack)
+			value := strings.TrimSpace(text.String())
+			text.Reset()
+			switch {
+			case current != nil && depth == triggerDepth:
+				triggers = append(triggers, *current)
+				current, triggerDepth = nil, -1
+			case current != nil && depth == triggerDepth+1:
+				if tok.Name.Local == "spec" {
+					current.specEnd = base + offset
+					current.Schedule = value
+					current.Disabled = current.Type == triggerTypeCron && cronDisabled(value)
+				} else if value != "" {
+					current.setSetting(tok.Name.Local, value, redactor)
+				}
+			case current != nil && depth == triggerDepth+2 && tok.Name.Local == "name" && stack[depth-2] == "threshold":
+				current.setSetting("threshold", value, redactor)
+			case depth == triggersDepth:
+				triggersDepth = -1
+			}
+			stack = stack[:depth-1]
+		}
+	}
+	if triggers == nil {
+		triggers = []jobTrigger{}
+	}
+	return triggers, nil
+}
+
+func (t *jobTrigger) setSetting(name, value string, redactor *filter.Redactor) {
+	if t.Settings == nil {
+		t.Settings = make(map[string]string)
+	}
+	t.Settings[name] = redactor.Redact(name, value)
+}
+
+// cronDisabled reports whether every schedule line was commented out by
+// --disable-cron.
+func cronDisabled(spec string) bool {
+	marked := false
+	for _, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, strings.TrimSpace(cronDisabledMarker)):
+			marked = true
+		case strings.HasPrefix(line, "#"):
+		default:
+			return false
+		}
+	}
+	return marked
+}
+
+// setCronDisabled comments out (or restores) the schedule lines of every cron
+// trigger, touching only the text inside their <spec> elements. Comment lines
+// the user wrote are left alone. It returns the new config and how many
+// triggers changed.
+func setCronDisabled(config []byte, triggers []jobTrigger, disable bool) ([]byte, int) {
+	var out bytes.Buffer
+	last := int64(0)
+	changed := 0
+	for _, trigger := range triggers {
+		if trigger.Type != triggerTypeCron || trigger.specStart < 0 || trigger.specEnd < trigger.specStart {
+			continue
+		}
+		spec := string(config[trigger.specStart:trigger.specEnd])
+		rewritten := rewriteCronSpec(spec, disable)
+		if rewritten == spec {
+			continue
+		}
+		out.Write(config[last:trigger.specStart])
+		out.WriteString(rewritten)
+		last = trigger.specEnd
+		changed++
+	}
+	if changed == 0 {
+		return config, 0
+	}
+	out.Write(config[last:])
+	return out.Bytes(), changed
+}
+
+func rewriteCronSpec(spec string, disable bool) string {
+	lines := strings.Split(spec, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(trimmed)]
+		switch {
+		case disable && strings.TrimSpace(trimmed) != "" && !strings.HasPrefix(trimmed, "#"):
+			lines[i] = indent + cronDisabledMarker + trimmed
+		case !disable && strings.HasPrefix(trimmed, cronDisabledMarker):
+			lines[i] = indent + strings.TrimPrefix(trimmed, cronDisabledMarker)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// configDiff renders the lines that differ between before and after as
+// unified diff hunks without context. The rewrites above never add or remove
+// lines, so lines are compared by position.
+func configDiff(before, after []byte, name string) string {
+	oldLines := strings.Split(string(before), "\n")
+	newLines := strings.Split(string(after), "\n")
+	if len(oldLines) != len(newLines) {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", name, name)
+	for i := 0; i < len(oldLines); {
+		if oldLines[i] == newLines[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(oldLines) && oldLines[i] != newLines[i] {
+			i++
+		}
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", start+1, i-start, start+1, i-start)
+		for _, line := range oldLines[start:i] {
+			fmt.Fprintf(&b, "-%s\n", line)
+		}
+		for _, line := range newLines[start:i] {
+			fmt.Fprintf(&b, "+%s\n", line)
+		}
+	}
+	return b.String()
+}
+
+func renderJobTriggers(w io.Writer, output jobTriggersOutput) {
+	if output.ReadOnly {
+		_, _ = fmt.Fprintf(w, "Triggers of multibranch project %s (read-only)\n", output.Source)
+	}
+	if len(output.Triggers) == 0 {
+		_, _ = fmt.Fprintln(w, "No triggers configured")
+		return
+	}
+	for _, trigger := range output.Triggers {
+		line := trigger.Type
+		if trigger.Type == triggerTypeOther {
+			line += "\t" + trigger.Class
+		}
+		if trigger.Schedule != "" {
+			line += "\t" + strings.Join(strings.Fields(strings.ReplaceAll(trigger.Schedule, "\n", " ; ")), " ")
+		}
+		if trigger.Disabled {
+			line += "\t(disabled)"
+		}
+		_, _ = fmt.Fprintln(w, line)
+		keys := make([]string, 0, len(trigger.Settings))
+		for key := range trigger.Settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			_, _ = fmt.Fprintf(w, "    %s: %s\n", key, trigger.Settings[key])
+		}
+	}
+}
+
+func renderCronChange(w io.Writer, output jobTriggersOutput, disable bool) {
+	verb := "Enabled"
+	if disable {
+		verb = "Disabled"
+	}
+	if output.Changed == 0 {
+		_, _ = fmt.Fprintf(w, "No cron triggers to change on %s\n", output.JobPath)
+		return
+	}
+	if output.DryRun {
+		_, _ = fmt.Fprint(w, output.Diff)
+		_, _ = fmt.Fprintf(w, "(dry run) would change %d cron trigger(s) on %s\n", output.Changed, output.JobPath)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s %d cron trigger(s) on %s\n", verb, output.Changed, output.JobPath)
+}
@@ -0,0 +1,182 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const freestyleTriggersConfig = `<?xml version='1.1' encoding='UTF-8'?>
+<project>
+  <description>nightly</description>
+  <triggers>
+    <hudson.triggers.TimerTrigger>
+      <spec># nightly build
+H 3 * * *
+  H 15 * * 1-5</spec>
+    </hudson.triggers.TimerTrigger>
+    <hudson.triggers.SCMTrigger>
+      <spec>H/15 * * * *</spec>
+      <ignorePostCommitHooks>false</ignorePostCommitHooks>
+    </hudson.triggers.SCMTrigger>
+    <jenkins.triggers.ReverseBuildTrigger>
+      <spec></spec>
+      <upstreamProjects>team/lib, team/api</upstreamProjects>
+      <threshold>
+        <name>SUCCESS</name>
+        <ordinal>0</ordinal>
+      </threshold>
+    </jenkins.triggers.ReverseBuildTrigger>
+  </triggers>
+  <builders/>
+</project>
+`
+
+const pipelineTriggersConfig = `<?xml version='1.1' encoding='UTF-8'?>
+<flow-definition plugin="workflow-job@1400">
+  <properties>
+    <org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+      <triggers>
+        <org.jenkinsci.plugins.gwt.GenericTrigger plugin="generic-webhook-trigger@2.2">
+          <genericVariables>
+            <org.jenkinsci.plugins.gwt.GenericVariable>
+              <key>ref</key>
+            </org.jenkinsci.plugins.gwt.GenericVariable>
+          </genericVariables>
+          <regexpFilterText>$ref</regexpFilterText>
+          <regexpFilterExpression>refs/heads/main</regexpFilterExpression>
+          <token>hook-s3cr3t</token>
+        </org.jenkinsci.plugins.gwt.GenericTrigger>
+        <com.example.CustomTrigger/>
+      </triggers>
+    </org.jenkinsci.plugins.workflow.job.properties.PipelineTriggersJobProperty>
+  </properties>
+</flow-definition>
+`
+
+func executeTriggers(t *testing.T, f *cmdutil.Factory, jsonOut bool, args ...string) error {
+	t.Helper()
+	cmd := newJobTriggersCmd(f)
+	cmd.PersistentFlags().Bool("json", jsonOut, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(args)
+	cmd.SetOut(f.IOStreams.Out)
+	cmd.SetErr(f.IOStreams.ErrOut)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func TestJobTriggersListsFreestyleTriggers(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/nightly/config.xml", http.StatusOK, freestyleTriggersConfig)
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	require.NoError(t, executeTriggers(t, f, true, "team/nightly"))
+
+	var got jobTriggersOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+	require.False(t, got.ReadOnly)
+	require.Equal(t, "team/nightly", got.Source)
+	require.Len(t, got.Triggers, 3)
+
+	require.Equal(t, triggerTypeCron, got.Triggers[0].Type)
+	require.Equal(t, "# nightly build\nH 3 * * *\n  H 15 * * 1-5", got.Triggers[0].Schedule)
+	require.False(t, got.Triggers[0].Disabled)
+	require.Equal(t, triggerTypeSCM, got.Triggers[1].Type)
+	require.Equal(t, "H/15 * * * *", got.Triggers[1].Schedule)
+	require.Equal(t, map[string]string{"ignorePostCommitHooks": "false"}, got.Triggers[1].Settings)
+	require.Equal(t, triggerTypeUpstream, got.Triggers[2].Type)
+	require.Equal(t, map[string]string{"upstreamProjects": "team/lib, team/api", "threshold": "SUCCESS"}, got.Triggers[2].Settings)
+}
+
+func TestJobTriggersRedactsWebhookToken(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/deploy/config.xml", http.StatusOK, pipelineTriggersConfig)
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	require.NoError(t, executeTriggers(t, f, false, "deploy"))
+
+	out := stdout.String()
+	require.Contains(t, out, "generic-webhook\n")
+	require.Contains(t, out, "    regexpFilterExpression: refs/heads/main\n")
+	require.Contains(t, out, "    token: [REDACTED]\n")
+	require.Contains(t, out, "other\tcom.example.CustomTrigger\n")
+	require.NotContains(t, out, "hook-s3cr3t")
+}
+
+func TestJobTriggersDisableCronRewritesOnlySpec(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/nightly/config.xml", http.StatusOK, freestyleTriggersConfig)
+	server.Handle(http.MethodPost, "/job/team/job/nightly/config.xml", http.StatusOK, "")
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	require.NoError(t, executeTriggers(t, f, false, "team/nightly", "--disable-cron"))
+	require.Contains(t, stdout.String(), "Disabled 1 cron trigger(s) on team/nightly")
+
+	posted := string(server.LastRequest(http.MethodPost, "/job/team/job/nightly/config.xml").Body)
+	want := strings.Replace(freestyleTriggersConfig, "H 3 * * *\n  H 15 * * 1-5</spec>", "# jk:disabled H 3 * * *\n  # jk:disabled H 15 * * 1-5</spec>", 1)
+	require.Equal(t, want, posted)
+
+	triggers, err := parseJobTriggers([]byte(posted), nil)
+	require.NoError(t, err)
+	require.True(t, triggers[0].Disabled)
+	restored, changed := setCronDisabled([]byte(posted), triggers, false)
+	require.Equal(t, 1, changed)
+	require.Equal(t, freestyleTriggersConfig, string(restored))
+}
+
+func TestJobTriggersDryRunPrintsDiff(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/nightly/config.xml", http.StatusOK, freestyleTriggersConfig)
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	require.NoError(t, executeTriggers(t, f, false, "team/nightly", "--disable-cron", "--dry-run"))
+	require.Equal(t, `--- a/config.xml
++++ b/config.xml
+@@ -7,2 +7,2 @@
+-H 3 * * *
+-  H 15 * * 1-5</spec>
++# jk:disabled H 3 * * *
++  # jk:disabled H 15 * * 1-5</spec>
+(dry run) would change 1 cron trigger(s) on team/nightly
+`, stdout.String())
+	require.Empty(t, server.RequestsTo(http.MethodPost, "/job/team/job/nightly/config.xml"))
+}
+
+func TestJobTriggersBranchJobIsReadOnly(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/team/job/app/api/json", map[string]any{
+		"_class": "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject",
+	})
+	server.Handle(http.MethodGet, "/job/team/job/app/config.xml", http.StatusOK, `<org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject>
+  <triggers>
+    <com.cloudbees.hudson.plugins.folder.computed.PeriodicFolderTrigger>
+      <spec>H H/4 * * *</spec>
+      <interval>86400000</interval>
+    </com.cloudbees.hudson.plugins.folder.computed.PeriodicFolderTrigger>
+  </triggers>
+</org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject>`)
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	require.NoError(t, executeTriggers(t, f, true, "team/app/main"))
+	var got jobTriggersOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &got))
+	require.True(t, got.ReadOnly)
+	require.Equal(t, "team/app", got.Source)
+	require.Len(t, got.Triggers, 1)
+	require.Equal(t, triggerTypeFolderScan, got.Triggers[0].Type)
+	require.Equal(t, "86400000", got.Triggers[0].Settings["interval"])
+
+	err := executeTriggers(t, f, false, "team/app/main", "--disable-cron")
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	require.Equal(t, 2, exitErr.Code)
+}
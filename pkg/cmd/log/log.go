@@ -13,9 +13,11 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 type logOptions struct {
@@ -23,19 +25,43 @@ type logOptions struct {
 	buildString string
 	follow      bool
 	interval    time.Duration
+	retry       shared.PollRetry
 	plain       bool
 	maxBytes    int
+	last        int
+	onlyFailed  bool
+	filters     []string
+	raw         bool
+	// rawLimit caps --raw output; 0 copies the whole log.
+	rawLimit int64
+}
+
+// rawLogBufferSize is the copy buffer of --raw.
+const rawLogBufferSize = 64 * 1024
+
+// legacyDuration renders ms for the deprecated duration field, which keeps
+// the time.Duration format it shipped with rather than the human one.
+func legacyDuration(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
 }
 
 type logOutput struct {
-	JobPath   string `json:"jobPath"`
-	Build     int64  `json:"build"`
-	Status    string `json:"status"`
-	Result    string `json:"result,omitempty"`
-	StartTime string `json:"startTime,omitempty"`
+	JobPath    string `json:"jobPath"`
+	Build      int64  `json:"build"`
+	Status     string `json:"status"`
+	Result     string `json:"result,omitempty"`
+	StartTime  string `json:"startTime,omitempty"`
+	DurationMs int64  `json:"durationMs,omitempty"`
+	// Duration is DurationMs in Go's duration format ("1m32s"); its format
+	// is frozen with the field, see legacyDuration.
+	//
+	// Deprecated: kept for one release; consumers should read DurationMs.
 	Duration  string `json:"duration,omitempty"`
 	Log       string `json:"log"`
 	Truncated bool   `json:"truncated,omitempty"`
+	// OmittedBytes is set when a log larger than --max-bytes was sampled:
+	// Log holds its head and tail around a "--- N bytes omitted ---" line.
+	OmittedBytes int64 `json:"omittedBytes,omitempty"`
 }
 
 type runDetail struct {
@@ -53,20 +79,80 @@ func NewCmdLog(f *cmdutil.Factory) *cobra.Command {
 	}
 
 	cmd := &cobra.Command{
-		Use:   "log <jobPath> <buildNumber>",
+		Use:   "log <jobPath> [<buildNumber>]",
 		Short: "Show Jenkins run logs",
-		Long:  "Display the console log for a Jenkins run. Add --follow to stream live output similar to `gh run view --log`.",
-		Args:  cobra.ExactArgs(2),
+		Long: `Display the console log for a Jenkins run. Add --follow to stream live output similar to ` + "`gh run view --log`" + `.
+
+Use --last N instead of a build number to print the logs of the N most recent
+completed runs, newest first. Narrow the set with --only-failed or the same
+--filter expressions accepted by ` + "`jk run ls`" + `.
+
+--raw copies consoleText to stdout in one streamed request, with no heading
+and no sampling: the fastest way to dump a large log. It reads to the end
+unless --max-bytes is given explicitly.`,
+		Example: `  # Logs of the last 5 failed builds
+  jk log team/app --last 5 --only-failed
+
+  # Dump a large log as fast as possible
+  jk log team/app 42 --raw > build.log`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.jobPath = args[0]
+			if err := opts.retry.Validate(); err != nil {
+				return err
+			}
+			if opts.raw {
+				switch {
+				case opts.follow:
+					return shared.NewExitError(2, "--raw cannot be combined with --follow")
+				case opts.last > 0:
+					return shared.NewExitError(2, "--raw cannot be combined with --last")
+				case shared.WantsJSON(cmd) || shared.WantsYAML(cmd):
+					return shared.NewExitError(2, "--raw writes the log as-is; drop --json/--yaml")
+				case len(args) != 2:
+					return shared.NewExitError(2, "build number required")
+				}
+				if cmd.Flags().Changed("max-bytes") {
+					if opts.maxBytes <= 0 {
+						return shared.NewExitError(2, "--max-bytes must be positive")
+					}
+					opts.rawLimit = int64(opts.maxBytes)
+				}
+				opts.buildString = args[1]
+				return runRawLog(cmd, f, opts)
+			}
+			if opts.last > 0 {
+				if len(args) != 1 {
+					return shared.NewExitError(2, "--last selects builds itself; drop the build number")
+				}
+				if opts.follow {
+					return shared.NewExitError(2, "--follow cannot be combined with --last")
+				}
+				return runMultiLog(cmd, f, opts)
+			}
+			if opts.onlyFailed || len(opts.filters) > 0 {
+				return shared.NewExitError(2, "--only-failed and --filter require --last")
+			}
+			if len(args) != 2 {
+				return shared.NewExitError(2, "build number required (or pass --last N)")
+			}
 			opts.buildString = args[1]
 			return runLog(cmd, f, opts)
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
 	cmd.Flags().BoolVar(&opts.follow, "follow", false, "Stream log output until the run finishes")
 	cmd.Flags().DurationVar(&opts.interval, "interval", time.Second, "Polling interval while following live logs")
+	shared.AddPollRetryFlags(cmd, &opts.retry)
 	cmd.Flags().BoolVar(&opts.plain, "plain", false, "Disable headings and additional formatting")
+	cmd.Flags().IntVar(&opts.maxBytes, "max-bytes", opts.maxBytes, "Maximum bytes of each log snapshot")
+	cmd.Flags().IntVar(&opts.last, "last", 0, "Show logs for the N most recent completed runs")
+	cmd.Flags().BoolVar(&opts.onlyFailed, "only-failed", false, "With --last, only consider runs that FAILED")
+	cmd.Flags().StringSliceVar(&opts.filters, "filter", nil, "With --last, filter runs (repeatable): key[op]value")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Stream consoleText to stdout unmodified in one request")
+	cmdutil.SetFilterSupport(cmd, filter.AllowedKeys(), filter.Operators())
+	cmdutil.SetExitCodes(cmd, map[int]string{3: "Run not found"})
 	return cmd
 }
 
@@ -76,15 +162,17 @@ func runLog(cmd *cobra.Command, f *cmdutil.Factory, opts *logOptions) error {
 		return err
 	}
 
-	num, err := strconv.ParseInt(opts.buildString, 10, 64)
+	opts.jobPath, err = shared.ResolveJobPath(cmd, client, opts.jobPath)
 	if err != nil {
-		return fmt.Errorf("invalid build number: %w", err)
+		return err
 	}
-	if num <= 0 {
-		return errors.New("build number must be positive")
+
+	num, err := parseBuildNumber(opts.buildString)
+	if err != nil {
+		return err
 	}
 
-	encoded := jenkins.EncodeJobPath(opts.jobPath)
+	encoded := jobpath.Encode(opts.jobPath)
 	if encoded == "" {
 		return errors.New("job path is required")
 	}
@@ -96,7 +184,7 @@ func runLog(cmd *cobra.Command, f *cmdutil.Factory, opts *logOptions) error {
 		return err
 	}
 	if resp.StatusCode() == http.StatusNotFound {
-		return shared.NewExitError(3, fmt.Sprintf("run %s #%d not found", opts.jobPath, num))
+		return shared.ExplainNotFound(cmd, client, opts.jobPath, shared.NewExitError(3, fmt.Sprintf("run %s #%d not found", opts.jobPath, num)))
 	}
 
 	status := statusFromFlags(detail.Building)
@@ -115,7 +203,90 @@ func runLog(cmd *cobra.Command, f *cmdutil.Factory, opts *logOptions) error {
 	return renderLogSnapshot(cmd, client, opts, int(num), detail, status, result)
 }
 
-func streamLogFollow(cmd *cobra.Command, client *jenkins.Client, opts *logOptions, buildNumber int, detail *runDetail, status, result string) error {
+func parseBuildNumber(s string) (int64, error) {
+	num, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid build number: %w", err)
+	}
+	if num <= 0 {
+		return 0, errors.New("build number must be positive")
+	}
+	return num, nil
+}
+
+// runRawLog implements --raw: one streamed consoleText request piped to
+// stdout, skipping the run lookup and the progressiveText loop.
+func runRawLog(cmd *cobra.Command, f *cmdutil.Factory, opts *logOptions) error {
+	client, err := shared.JenkinsClient(cmd, f)
+	if err != nil {
+		return err
+	}
+
+	opts.jobPath, err = shared.ResolveJobPath(cmd, client, opts.jobPath)
+	if err != nil {
+		return err
+	}
+
+	num, err := parseBuildNumber(opts.buildString)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	truncated, err := copyRawLog(ctx, client, opts.jobPath, num, opts.rawLimit, cmd.OutOrStdout())
+	if err != nil {
+		return shared.ExplainNotFound(cmd, client, opts.jobPath, err)
+	}
+	if truncated {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "log truncated at %d bytes; raise --max-bytes to see more\n", opts.rawLimit)
+	}
+	return nil
+}
+
+// copyRawLog copies the consoleText of a build to out. With a positive
+// limit it stops after limit bytes, closing the response early, and reports
+// whether the log went on.
+func copyRawLog(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64, limit int64, out io.Writer) (bool, error) {
+	encoded := jobpath.Encode(jobPath)
+	if encoded == "" {
+		return false, errors.New("job path is required")
+	}
+
+	req := client.NewStreamingRequest().
+		SetDoNotParseResponse(true).
+		SetContext(ctx)
+	resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/%d/consoleText", encoded, buildNumber), nil)
+	if err != nil {
+		return false, err
+	}
+	body := resp.RawBody()
+	if body == nil {
+		return false, errors.New("log stream returned empty body")
+	}
+	defer func() { _ = body.Close() }()
+	if err := shared.CheckResponse(resp, fmt.Sprintf("run %s #%d", jobPath, buildNumber)); err != nil {
+		return false, err
+	}
+
+	buf := make([]byte, rawLogBufferSize)
+	if limit <= 0 {
+		_, err := io.CopyBuffer(out, body, buf)
+		return false, err
+	}
+	written, err := io.CopyBuffer(out, io.LimitReader(body, limit), buf)
+	if err != nil || written < limit {
+		return false, err
+	}
+	var probe [1]byte
+	n, _ := io.ReadFull(body, probe[:])
+	return n > 0, nil
+}
+
+func streamLogFollow(cmd *cobra.Command, client shared.Doer, opts *logOptions, buildNumber int, detail *runDetail, status, result string) error {
 	if !opts.plain && !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd) {
 		printLogHeading(cmd.OutOrStdout(), opts.jobPath, int64(buildNumber), detail, status, result)
 		_, _ = fmt.Fprintln(cmd.OutOrStdout())
@@ -126,7 +297,8 @@ func streamLogFollow(cmd *cobra.Command, client *jenkins.Client, opts *logOption
 		ctx = context.Background()
 	}
 
-	if err := shared.StreamProgressiveLog(ctx, client, opts.jobPath, buildNumber, opts.interval, cmd.OutOrStdout()); err != nil {
+	poller := opts.retry.WithNotes(cmd, cmd.ErrOrStderr()).Poller()
+	if err := shared.StreamProgressiveLog(ctx, client, opts.jobPath, buildNumber, opts.interval, cmd.OutOrStdout(), poller); err != nil {
 		return err
 	}
 
@@ -138,31 +310,31 @@ func streamLogFollow(cmd *cobra.Command, client *jenkins.Client, opts *logOption
 	return nil
 }
 
-func renderLogSnapshot(cmd *cobra.Command, client *jenkins.Client, opts *logOptions, buildNumber int, detail *runDetail, status, result string) error {
+func renderLogSnapshot(cmd *cobra.Command, client shared.Doer, opts *logOptions, buildNumber int, detail *runDetail, status, result string) error {
 	ctx := cmd.Context()
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
 	var buf bytes.Buffer
-	truncated, err := shared.CollectLogSnapshot(ctx, client, opts.jobPath, buildNumber, opts.maxBytes, &buf)
+	snapshot, err := shared.CollectLogSnapshot(ctx, client, opts.jobPath, buildNumber, opts.maxBytes, &buf)
 	if err != nil {
 		return err
 	}
 
 	output := logOutput{
-		JobPath:   opts.jobPath,
-		Build:     int64(buildNumber),
-		Status:    status,
-		Result:    result,
-		Log:       buf.String(),
-		Truncated: truncated,
-	}
-	if detail.Timestamp > 0 {
-		output.StartTime = time.UnixMilli(detail.Timestamp).UTC().Format(time.RFC3339)
+		JobPath:      opts.jobPath,
+		Build:        int64(buildNumber),
+		Status:       status,
+		Result:       result,
+		Log:          buf.String(),
+		Truncated:    snapshot.Truncated,
+		OmittedBytes: snapshot.OmittedBytes,
 	}
+	output.StartTime = shared.FormatTimestamp(detail.Timestamp)
 	if detail.Duration > 0 {
-		output.Duration = shared.DurationString(detail.Duration)
+		output.DurationMs = detail.Duration
+		output.Duration = legacyDuration(detail.Duration)
 	}
 
 	return shared.PrintOutput(cmd, output, func() error {
@@ -185,9 +357,15 @@ func renderLogSnapshot(cmd *cobra.Command, client *jenkins.Client, opts *logOpti
 			}
 		}
 
-		if truncated && !opts.plain {
-			_, _ = fmt.Fprintln(writer)
-			_, _ = fmt.Fprintln(writer, "(log truncated; use --follow to stream live output)")
+		if !opts.plain {
+			switch {
+			case snapshot.OmittedBytes > 0:
+				_, _ = fmt.Fprintln(writer)
+				_, _ = fmt.Fprintf(writer, "(log sampled: first and last %d bytes shown; raise --max-bytes to see more)\n", opts.maxBytes/2)
+			case snapshot.Truncated:
+				_, _ = fmt.Fprintln(writer)
+				_, _ = fmt.Fprintln(writer, "(log truncated; use --follow to stream live output)")
+			}
 		}
 		return nil
 	})
@@ -211,7 +389,7 @@ func printLogHeading(w io.Writer, jobPath string, buildNumber int64, detail *run
 	}
 	if detail != nil {
 		if detail.Timestamp > 0 {
-			pieces = append(pieces, fmt.Sprintf("started: %s", time.UnixMilli(detail.Timestamp).UTC().Format(time.RFC3339)))
+			pieces = append(pieces, fmt.Sprintf("started: %s", shared.FormatTimestamp(detail.Timestamp)))
 		}
 		if detail.Duration > 0 {
 			pieces = append(pieces, fmt.Sprintf("duration: %s", shared.DurationString(detail.Duration)))
@@ -221,3 +399,101 @@ func printLogHeading(w io.Writer, jobPath string, buildNumber int64, detail *run
 		_, _ = fmt.Fprintf(w, "   %s\n", strings.Join(pieces, "   "))
 	}
 }
+
+// runMultiLog prints snapshots for the runs picked by --last. Logs are fetched
+// concurrently but always emitted newest-first.
+func runMultiLog(cmd *cobra.Command, f *cmdutil.Factory, opts *logOptions) error {
+	filterArgs := append([]string{}, opts.filters...)
+	if opts.onlyFailed {
+		filterArgs = append(filterArgs, "result=FAILURE")
+	}
+	filters, err := filter.Parse(filterArgs)
+	if err != nil {
+		return err
+	}
+
+	client, err := shared.JenkinsClient(cmd, f)
+	if err != nil {
+		return err
+	}
+
+	opts.jobPath, err = shared.ResolveJobPath(cmd, client, opts.jobPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	runs, err := runcmd.SelectRecentRuns(ctx, client, opts.jobPath, opts.last, filters)
+	if err != nil {
+		return err
+	}
+
+	outputs, err := collectLogOutputs(ctx, client, opts.jobPath, runs, opts.maxBytes, shared.Concurrency(f))
+	if err != nil {
+		return err
+	}
+
+	return shared.PrintOutput(cmd, outputs, func() error {
+		writer := cmd.OutOrStdout()
+		if len(outputs) == 0 {
+			_, _ = fmt.Fprintln(writer, "No matching runs found")
+			return nil
+		}
+		for i, output := range outputs {
+			if i > 0 {
+				_, _ = fmt.Fprintln(writer)
+			}
+			_, _ = fmt.Fprintf(writer, "==> #%d (%s)\n", output.Build, output.Result)
+			_, _ = io.WriteString(writer, output.Log)
+			if output.Log != "" && !strings.HasSuffix(output.Log, "\n") {
+				_, _ = fmt.Fprintln(writer)
+			}
+			switch {
+			case output.OmittedBytes > 0:
+				_, _ = fmt.Fprintf(writer, "(log sampled: first and last %d bytes shown; raise --max-bytes to see more)\n", opts.maxBytes/2)
+			case output.Truncated:
+				_, _ = fmt.Fprintf(writer, "(log truncated at %d bytes; raise --max-bytes to see more)\n", opts.maxBytes)
+			}
+		}
+		return nil
+	})
+}
+
+func collectLogOutputs(ctx context.Context, client shared.Doer, jobPath string, runs []runcmd.RecentRun, maxBytes, concurrency int) ([]logOutput, error) {
+	outputs := make([]logOutput, len(runs))
+	errs := make([]error, len(runs))
+	shared.ForEach(len(runs), concurrency, func(i int) {
+		run := runs[i]
+		var buf bytes.Buffer
+		snapshot, err := shared.CollectLogSnapshot(ctx, client, jobPath, int(run.Number), maxBytes, &buf)
+		if err != nil {
+			errs[i] = fmt.Errorf("log for %s #%d: %w", jobPath, run.Number, err)
+			return
+		}
+		outputs[i] = logOutput{
+			JobPath:      jobPath,
+			Build:        run.Number,
+			Status:       "completed",
+			Result:       run.Result,
+			StartTime:    run.StartTime,
+			Log:          buf.String(),
+			Truncated:    snapshot.Truncated,
+			OmittedBytes: snapshot.OmittedBytes,
+		}
+		if run.DurationMs > 0 {
+			outputs[i].DurationMs = run.DurationMs
+			outputs[i].Duration = legacyDuration(run.DurationMs)
+		}
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return outputs, nil
+}
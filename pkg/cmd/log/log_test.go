@@ -0,0 +1,224 @@
+package logcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/outputschema"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func newMultiLogClient(t *testing.T) (*fakejenkins.Server, *jenkins.Client) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/api/json", map[string]any{
+		"builds": []map[string]any{
+			{"number": 5, "building": true},
+			{"number": 4, "result": "FAILURE", "timestamp": 4000, "duration": 1000},
+			{"number": 3, "result": "SUCCESS", "timestamp": 3000, "duration": 1000},
+			{"number": 2, "result": "FAILURE", "timestamp": 2000, "duration": 1000},
+			{"number": 1, "result": "FAILURE", "timestamp": 1000, "duration": 1000},
+		},
+	})
+	for _, num := range []string{"1", "2", "3", "4", "5"} {
+		server.Handle(http.MethodGet, "/job/app/"+num+"/logText/progressiveText", http.StatusOK, "log of "+num+"\n")
+	}
+	return server, client
+}
+
+func runLogCmd(t *testing.T, client *jenkins.Client, args ...string) (string, error) {
+	t.Helper()
+	f, stdout, _ := fakejenkins.Factory(client)
+	cmd := NewCmdLog(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stdout)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestLogLastOnlyFailedNewestFirst(t *testing.T) {
+	server, client := newMultiLogClient(t)
+
+	out, err := runLogCmd(t, client, "app", "--last", "2", "--only-failed")
+	require.NoError(t, err)
+	require.Equal(t, "==> #4 (FAILURE)\nlog of 4\n\n==> #2 (FAILURE)\nlog of 2\n", out)
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/5/logText/progressiveText"))
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/3/logText/progressiveText"))
+}
+
+func TestLogLastJSONEmitsArray(t *testing.T) {
+	_, client := newMultiLogClient(t)
+
+	out, err := runLogCmd(t, client, "app", "--last", "3", "--json")
+	require.NoError(t, err)
+
+	var outputs []logOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &outputs))
+	require.Len(t, outputs, 3)
+	require.Equal(t, []int64{4, 3, 2}, []int64{outputs[0].Build, outputs[1].Build, outputs[2].Build})
+	require.Equal(t, "SUCCESS", outputs[1].Result)
+	require.Equal(t, "log of 3\n", outputs[1].Log)
+}
+
+func TestLogLastRejectsFollowAndBuildNumber(t *testing.T) {
+	_, client := newMultiLogClient(t)
+
+	for _, args := range [][]string{
+		{"app", "--last", "2", "--follow"},
+		{"app", "4", "--last", "2"},
+		{"app", "--only-failed"},
+	} {
+		_, err := runLogCmd(t, client, args...)
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "args %v: %v", args, err)
+		require.Equal(t, 2, exitErr.Code)
+	}
+}
+
+func TestLogOutputTimeFieldConventions(t *testing.T) {
+	// duration is the deprecated human-readable twin of durationMs.
+	require.Empty(t, outputschema.TimeFieldViolations(logOutput{}, "duration"))
+}
+
+func TestLogRawStreamsConsoleText(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/7/consoleText", http.StatusOK, "line one\nline two")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdLog(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"app", "7", "--raw"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	require.NoError(t, cmd.Execute())
+	require.Equal(t, "line one\nline two", stdout.String())
+	require.Empty(t, stderr.String())
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/7/api/json"))
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/7/logText/progressiveText"))
+}
+
+func TestLogRawMaxBytesTruncates(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/7/consoleText", http.StatusOK, "0123456789")
+
+	for _, tt := range []struct {
+		maxBytes string
+		out      string
+		warning  string
+	}{
+		{"4", "0123", "log truncated at 4 bytes; raise --max-bytes to see more\n"},
+		{"10", "0123456789", ""},
+	} {
+		f, stdout, stderr := fakejenkins.Factory(client)
+		cmd := NewCmdLog(f)
+		cmd.PersistentFlags().Bool("json", false, "")
+		cmd.PersistentFlags().Bool("yaml", false, "")
+		cmd.SetArgs([]string{"app", "7", "--raw", "--max-bytes", tt.maxBytes})
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		require.NoError(t, cmd.Execute())
+		require.Equal(t, tt.out, stdout.String())
+		require.Equal(t, tt.warning, stderr.String())
+	}
+}
+
+func TestLogRawRejectsFormattingFlags(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/7/consoleText", http.StatusNotFound, "")
+
+	for _, args := range [][]string{
+		{"app", "7", "--raw", "--follow"},
+		{"app", "7", "--raw", "--json"},
+		{"app", "--raw", "--last", "2"},
+		{"app", "--raw"},
+	} {
+		_, err := runLogCmd(t, client, args...)
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "args %v: %v", args, err)
+		require.Equal(t, 2, exitErr.Code, "args %v", args)
+	}
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/app/7/consoleText"))
+
+	_, err := runLogCmd(t, client, "app", "7", "--raw")
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "%v", err)
+	require.Equal(t, 3, exitErr.Code)
+}
+
+const (
+	// largeLogSize is the console log served to the log dump benchmarks.
+	largeLogSize = 100 << 20
+	// largeLogLatency delays every response like the round trip to a remote
+	// controller would; on loopback the number of requests hardly matters.
+	largeLogLatency = 2 * time.Millisecond
+)
+
+// largeLogHandler serves a 100 MB console log the way Jenkins does:
+// consoleText streams it whole, progressiveText in 256 KiB chunks.
+func largeLogHandler() http.Handler {
+	line := []byte(strings.Repeat("x", 79) + "\n")
+	log := bytes.Repeat(line, largeLogSize/len(line))
+	const chunk = 256 << 10
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(largeLogLatency)
+		switch r.URL.Path {
+		case "/job/app/1/consoleText":
+			_, _ = w.Write(log)
+		case "/job/app/1/logText/progressiveText":
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			end := min(start+chunk, len(log))
+			w.Header().Set("X-Text-Size", strconv.Itoa(end))
+			if end < len(log) {
+				w.Header().Set("X-More-Data", "true")
+			}
+			_, _ = w.Write(log[start:end])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func BenchmarkLogDumpRaw(b *testing.B) {
+	client := jenkinstest.NewClient(b, largeLogHandler())
+	b.SetBytes(largeLogSize)
+	for i := 0; i < b.N; i++ {
+		if _, err := copyRawLog(context.Background(), client, "app", 1, 0, io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLogDumpSnapshot(b *testing.B) {
+	client := jenkinstest.NewClient(b, largeLogHandler())
+	b.SetBytes(largeLogSize)
+	for i := 0; i < b.N; i++ {
+		if _, err := shared.CollectLogSnapshot(context.Background(), client, "app", 1, 2*largeLogSize, io.Discard); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestLegacyDurationKeepsGoFormat(t *testing.T) {
+	require.Equal(t, "1m32.5s", legacyDuration(92500))
+	require.Equal(t, "26h0m0s", legacyDuration(26*3600*1000))
+}
@@ -0,0 +1,248 @@
+package node
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const bulkToggleTree = "computer[_class,displayName,offline,temporarilyOffline,assignedLabels[name]]"
+
+// Per-node outcomes of a bulk cordon or uncordon.
+const (
+	bulkResultChanged   = "changed"
+	bulkResultUnchanged = "unchanged"
+	bulkResultFailed    = "failed"
+)
+
+// bulkSelector picks the nodes a bulk cordon or uncordon applies to.
+type bulkSelector struct {
+	Label          string
+	All            bool
+	IncludeBuiltIn bool
+	Yes            bool
+	OKOnPartial    bool
+}
+
+func addBulkSelectorFlags(cmd *cobra.Command, sel *bulkSelector) {
+	cmd.Flags().StringVar(&sel.Label, "label", "", "Apply to every node with this label")
+	cmd.Flags().BoolVar(&sel.All, "all", false, "Apply to every node (the built-in node only with --include-built-in)")
+	cmd.Flags().BoolVar(&sel.IncludeBuiltIn, "include-built-in", false, "Include the built-in node in --all")
+	cmd.Flags().BoolVarP(&sel.Yes, "yes", "y", false, "Do not prompt for confirmation")
+	shared.AddOKOnPartialFlag(cmd, &sel.OKOnPartial)
+}
+
+func (s bulkSelector) bulk() bool {
+	return strings.TrimSpace(s.Label) != "" || s.All
+}
+
+type bulkToggleOutput struct {
+	SchemaVersion string           `json:"schemaVersion"`
+	Action        string           `json:"action"`
+	Label         string           `json:"label,omitempty"`
+	All           bool             `json:"all,omitempty"`
+	Items         []bulkNodeResult `json:"items"`
+	shared.Result
+}
+
+type bulkNodeResult struct {
+	Name string `json:"name"`
+	// State is the node's state after the operation: cordoned, online, or
+	// offline (disconnected without being cordoned).
+	State  string `json:"state"`
+	Result string `json:"result"`
+
+	err error
+}
+
+type bulkComputers struct {
+	Computers []bulkComputer `json:"computer"`
+}
+
+type bulkComputer struct {
+	Class              string                 `json:"_class"`
+	DisplayName        string                 `json:"displayName"`
+	Offline            bool                   `json:"offline"`
+	TemporarilyOffline bool                   `json:"temporarilyOffline"`
+	AssignedLabels     []shared.AssignedLabel `json:"assignedLabels"`
+}
+
+func (c bulkComputer) builtIn() bool {
+	return c.Class == builtInComputerClass || isBuiltInNode(c.DisplayName)
+}
+
+// pathName is the name toggleOffline is addressed by; the built-in node's
+// display name differs from its URL name.
+func (c bulkComputer) pathName() string {
+	if c.builtIn() {
+		return "(built-in)"
+	}
+	return c.DisplayName
+}
+
+func (c bulkComputer) state() string {
+	switch {
+	case c.TemporarilyOffline:
+		return "cordoned"
+	case c.Offline:
+		return "offline"
+	default:
+		return "online"
+	}
+}
+
+// toggleNodes cordons (offline) or uncordons every node sel matches. Nodes
+// already in the requested state are left alone, since toggleOffline flips
+// the state on older controllers regardless of the offline parameter.
+func toggleNodes(cmd *cobra.Command, f *cmdutil.Factory, args []string, sel bulkSelector, offline bool, message string) error {
+	action := "uncordon"
+	if offline {
+		action = "cordon"
+	}
+	switch {
+	case len(args) > 0:
+		return shared.NewExitError(2, "a node name cannot be combined with --label or --all")
+	case !sel.bulk():
+		return shared.NewExitError(2, fmt.Sprintf("%s requires a node name, --label, or --all", action))
+	case sel.IncludeBuiltIn && !sel.All:
+		return shared.NewExitError(2, "--include-built-in requires --all")
+	}
+
+	client, err := shared.JenkinsClient(cmd, f)
+	if err != nil {
+		return err
+	}
+
+	targets, err := selectBulkTargets(client, sel)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(targets))
+	for i, target := range targets {
+		names[i] = target.DisplayName
+	}
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Nodes to %s (%d): %s\n", action, len(targets), strings.Join(names, ", "))
+	if !sel.Yes {
+		ios, err := f.Streams()
+		if err != nil {
+			return err
+		}
+		if !ios.GetNeverPrompt() && !ios.IsStdinTTY() {
+			return shared.NewExitError(2, "confirmation required when stdin is not a TTY (use --yes)")
+		}
+		ok, err := cmdutil.ConfirmOrFail(ios, fmt.Sprintf("%s %d node(s)?", strings.ToUpper(action[:1])+action[1:], len(targets)), "--yes")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			_ = shared.PrintAction(cmd, shared.ActionResult{Action: action, Target: strings.Join(names, ","), Status: shared.ActionDeclined}, "Cancelled")
+			return cmdutil.ErrSilent
+		}
+	}
+
+	output := bulkToggleOutput{
+		SchemaVersion: "1.0",
+		Action:        action,
+		Label:         strings.TrimSpace(sel.Label),
+		All:           sel.All,
+		Items:         applyBulkToggle(client, targets, offline, message, shared.Concurrency(f)),
+		Result:        shared.NewResult(),
+	}
+	// Nodes already in the requested state count as succeeded.
+	for _, node := range output.Items {
+		if node.err != nil {
+			output.Fail(node.Name, fmt.Errorf("%s %s: %w", action, node.Name, node.err))
+			continue
+		}
+		output.Succeed()
+	}
+
+	if err := shared.PrintOutput(cmd, output, func() error {
+		w := cmd.OutOrStdout()
+		for _, node := range output.Items {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", node.Name, node.State, node.Result)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	output.WriteIssues(cmd)
+	return output.ExitError("nodes", sel.OKOnPartial)
+}
+
+// selectBulkTargets lists the nodes matching sel, sorted by name. An empty
+// match is a not-found error rather than a silent no-op.
+func selectBulkTargets(client shared.Doer, sel bulkSelector) ([]bulkComputer, error) {
+	var list bulkComputers
+	resp, err := client.Do(
+		client.NewRequest().SetQueryParam("tree", bulkToggleTree),
+		http.MethodGet,
+		"/computer/api/json",
+		&list,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.CheckResponse(resp, "node list"); err != nil {
+		return nil, err
+	}
+
+	label := strings.TrimSpace(sel.Label)
+	var targets []bulkComputer
+	for _, computer := range list.Computers {
+		if strings.TrimSpace(computer.DisplayName) == "" {
+			continue
+		}
+		if label != "" && !shared.HasLabel(shared.LabelNames(computer.AssignedLabels), label) {
+			continue
+		}
+		if sel.All && computer.builtIn() && !sel.IncludeBuiltIn {
+			continue
+		}
+		targets = append(targets, computer)
+	}
+	if len(targets) == 0 {
+		if label != "" {
+			return nil, shared.NewExitError(3, fmt.Sprintf("no nodes have label %q", label))
+		}
+		return nil, shared.NewExitError(3, "no nodes found")
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].DisplayName < targets[j].DisplayName })
+	return targets, nil
+}
+
+func applyBulkToggle(client shared.Doer, targets []bulkComputer, offline bool, message string, concurrency int) []bulkNodeResult {
+	results := make([]bulkNodeResult, len(targets))
+	shared.ForEach(len(targets), concurrency, func(i int) {
+		target := targets[i]
+		result := bulkNodeResult{Name: target.DisplayName, State: target.state(), Result: bulkResultUnchanged}
+		if target.TemporarilyOffline == offline {
+			results[i] = result
+			return
+		}
+		if err := setNodeOffline(client, target.pathName(), offline, message); err != nil {
+			result.Result = bulkResultFailed
+			result.err = err
+			results[i] = result
+			return
+		}
+		result.Result = bulkResultChanged
+		target.TemporarilyOffline = offline
+		if !offline {
+			// Jenkins reports cordoned nodes as offline too; whether the
+			// agent is also disconnected is not known until it is listed
+			// again.
+			target.Offline = false
+		}
+		result.State = target.state()
+		results[i] = result
+	})
+	return results
+}
@@ -0,0 +1,282 @@
+package node
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const builtInComputerClass = "hudson.model.Hudson$MasterComputer"
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type nodeConfigListResponse struct {
+	Computers []struct {
+		Class       string `json:"_class"`
+		DisplayName string `json:"displayName"`
+	} `json:"computer"`
+}
+
+type nodeConfigExport struct {
+	Name string `json:"name"`
+	File string `json:"file,omitempty"`
+}
+
+type nodeConfigFailure struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+type nodeConfigExportOutput struct {
+	Directory string              `json:"directory"`
+	Exported  []nodeConfigExport  `json:"exported"`
+	Failed    []nodeConfigFailure `json:"failed,omitempty"`
+}
+
+func newNodeConfigCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Export and import node config.xml",
+	}
+
+	cmd.AddCommand(
+		newNodeConfigGetCmd(f),
+		newNodeConfigSetCmd(f),
+	)
+	return cmd
+}
+
+func newNodeConfigGetCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		all    bool
+		outDir string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get [name]",
+		Short: "Print a node's config.xml or export every node",
+		Example: `  # Print one agent definition
+  jk node config get linux-agent-01 > linux-agent-01.xml
+
+  # Back up every agent into a directory
+  jk node config get --all -o backups/nodes`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all {
+				if len(args) > 0 {
+					return shared.NewExitError(2, "cannot combine a node name with --all")
+				}
+				if strings.TrimSpace(outDir) == "" {
+					return shared.NewExitError(2, "--all requires --output <dir>")
+				}
+			} else if len(args) == 0 {
+				return shared.NewExitError(2, "node name required (or pass --all)")
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			if all {
+				return exportAllNodeConfigs(cmd, client, outDir)
+			}
+
+			name := strings.TrimSpace(args[0])
+			if isBuiltInNode(name) {
+				return builtInConfigError()
+			}
+			data, err := fetchNodeConfig(client, name)
+			if err != nil {
+				return err
+			}
+
+			if outDir != "" {
+				file, err := writeNodeConfig(outDir, name, data, map[string]struct{}{})
+				if err != nil {
+					return err
+				}
+				return shared.PrintAction(cmd, shared.ActionResult{Action: "export", Target: file, Status: shared.ActionDone}, "Wrote %s", file)
+			}
+			_, _ = cmd.OutOrStdout().Write(data)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Export every node except the built-in node")
+	cmd.Flags().StringVarP(&outDir, "output", "o", "", "Directory to write <node>.xml files into")
+	return cmd
+}
+
+func newNodeConfigSetCmd(f *cmdutil.Factory) *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "set <name> --file <node.xml>",
+		Short: "Replace a node's config.xml",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return shared.NewExitError(2, "node name required")
+			}
+			if isBuiltInNode(name) {
+				return builtInConfigError()
+			}
+			if strings.TrimSpace(file) == "" {
+				return shared.NewExitError(2, "--file is required (use - for stdin)")
+			}
+
+			var data []byte
+			var err error
+			if file == "-" {
+				data, err = io.ReadAll(cmd.InOrStdin())
+			} else {
+				data, err = os.ReadFile(file)
+			}
+			if err != nil {
+				return fmt.Errorf("read config: %w", err)
+			}
+			if len(strings.TrimSpace(string(data))) == 0 {
+				return shared.NewExitError(2, "config file is empty")
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			path := fmt.Sprintf("/computer/%s/config.xml", encodeNodeName(name))
+			req := client.NewRequest().
+				SetHeader("Content-Type", "application/xml").
+				SetBody(data)
+			resp, err := client.Do(req, http.MethodPost, path, nil)
+			if err != nil {
+				return err
+			}
+			if err := shared.CheckResponse(resp, fmt.Sprintf("node %s", name)); err != nil {
+				return err
+			}
+
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "update-config", Target: name, Status: shared.ActionDone}, "Updated config for node %s", name)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Path to config.xml (use - for stdin)")
+	return cmd
+}
+
+func builtInConfigError() error {
+	return shared.NewExitError(2, "the built-in node has no config.xml; configure it under Manage Jenkins instead")
+}
+
+func fetchNodeConfig(client shared.Doer, name string) ([]byte, error) {
+	path := fmt.Sprintf("/computer/%s/config.xml", encodeNodeName(name))
+	req := client.NewRequest().SetHeader("Accept", "application/xml")
+	resp, err := client.Do(req, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.CheckResponse(resp, fmt.Sprintf("node %s", name)); err != nil {
+		return nil, err
+	}
+	return resp.Body(), nil
+}
+
+// exportAllNodeConfigs writes each node's config into dir. Failures are
+// collected per node so a single forbidden agent does not abort the backup.
+func exportAllNodeConfigs(cmd *cobra.Command, client shared.Doer, dir string) error {
+	var list nodeConfigListResponse
+	resp, err := client.Do(
+		client.NewRequest().SetQueryParam("tree", "computer[_class,displayName]"),
+		http.MethodGet,
+		"/computer/api/json",
+		&list,
+	)
+	if err != nil {
+		return err
+	}
+	if err := shared.CheckResponse(resp, "node list"); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create output directory: %w", err)
+	}
+
+	output := nodeConfigExportOutput{Directory: dir, Exported: []nodeConfigExport{}}
+	used := make(map[string]struct{})
+	for _, computer := range list.Computers {
+		name := strings.TrimSpace(computer.DisplayName)
+		if name == "" || computer.Class == builtInComputerClass || isBuiltInNode(name) {
+			continue
+		}
+
+		data, err := fetchNodeConfig(client, name)
+		if err == nil {
+			var file string
+			file, err = writeNodeConfig(dir, name, data, used)
+			if err == nil {
+				output.Exported = append(output.Exported, nodeConfigExport{Name: name, File: file})
+				continue
+			}
+		}
+		output.Failed = append(output.Failed, nodeConfigFailure{Name: name, Error: shared.ErrorMessage(err)})
+	}
+
+	if err := shared.PrintOutput(cmd, output, func() error {
+		w := cmd.OutOrStdout()
+		for _, item := range output.Exported {
+			_, _ = fmt.Fprintf(w, "%s\t%s\n", item.Name, item.File)
+		}
+		for _, item := range output.Failed {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s\tfailed: %s\n", item.Name, item.Error)
+		}
+		_, _ = fmt.Fprintf(w, "Exported %d node(s) to %s\n", len(output.Exported), dir)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(output.Failed) > 0 {
+		return shared.NewExitError(1, fmt.Sprintf("%d node(s) failed to export", len(output.Failed)))
+	}
+	return nil
+}
+
+// writeNodeConfig stores data as <sanitized-name>.xml, de-duplicating names
+// that collapse to the same file.
+func writeNodeConfig(dir, name string, data []byte, used map[string]struct{}) (string, error) {
+	base := sanitizeNodeFileName(name)
+	candidate := base
+	for i := 2; ; i++ {
+		if _, taken := used[candidate]; !taken {
+			break
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+	used[candidate] = struct{}{}
+
+	path := filepath.Join(dir, candidate+".xml")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+func sanitizeNodeFileName(name string) string {
+	cleaned := strings.Trim(unsafeFileChars.ReplaceAllString(strings.TrimSpace(name), "_"), "._")
+	if cleaned == "" {
+		return "node"
+	}
+	return cleaned
+}
@@ -0,0 +1,115 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func runNodeCmd(t *testing.T, handler http.Handler, args ...string) (string, error) {
+	t.Helper()
+
+	client := jenkinstest.NewClient(t, handler)
+	ios, _, stdout, _ := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: ios,
+		Config: func() (*config.Config, error) {
+			return &config.Config{Contexts: map[string]*config.Context{}}, nil
+		},
+		JenkinsClient: func(context.Context, string) (*jenkins.Client, error) {
+			return client, nil
+		},
+	}
+
+	cmd := NewCmdNode(f)
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(io.Discard)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestNodeConfigGetPrintsXML(t *testing.T) {
+	out, err := runNodeCmd(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/computer/agent-1/config.xml" {
+			_, _ = w.Write([]byte("<slave><name>agent-1</name></slave>"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}), "config", "get", "agent-1")
+	require.NoError(t, err)
+	require.Equal(t, "<slave><name>agent-1</name></slave>", out)
+}
+
+func TestNodeConfigRejectsBuiltInNode(t *testing.T) {
+	_, err := runNodeCmd(t, http.NotFoundHandler(), "config", "get", "built-in")
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	require.Equal(t, 2, exitErr.Code)
+}
+
+func TestNodeConfigSetPostsXML(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "node.xml")
+	require.NoError(t, os.WriteFile(file, []byte("<slave/>"), 0o600))
+
+	var gotBody, gotType string
+	_, err := runNodeCmd(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/computer/agent-1/config.xml" {
+			body, _ := io.ReadAll(r.Body)
+			gotBody, gotType = string(body), r.Header.Get("Content-Type")
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}), "config", "set", "agent-1", "--file", file)
+	require.NoError(t, err)
+	require.Equal(t, "<slave/>", gotBody)
+	require.Equal(t, "application/xml", gotType)
+}
+
+func TestNodeConfigExportAllCollectsFailures(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nodes")
+	_, err := runNodeCmd(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/computer/api/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"computer":[
+				{"_class":"hudson.model.Hudson$MasterComputer","displayName":"Built-In Node"},
+				{"_class":"hudson.slaves.SlaveComputer","displayName":"linux/agent 1"},
+				{"_class":"hudson.slaves.SlaveComputer","displayName":"locked"}
+			]}`))
+		case "/computer/linux%2Fagent%201/config.xml", "/computer/linux/agent 1/config.xml":
+			_, _ = w.Write([]byte("<slave/>"))
+		case "/computer/locked/config.xml":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}), "config", "get", "--all", "-o", dir)
+
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	require.Contains(t, exitErr.Msg, "1 node(s) failed")
+
+	data, readErr := os.ReadFile(filepath.Join(dir, "linux_agent_1.xml"))
+	require.NoError(t, readErr)
+	require.Equal(t, "<slave/>", string(data))
+}
+
+func TestSanitizeNodeFileName(t *testing.T) {
+	require.Equal(t, "linux_agent_1", sanitizeNodeFileName("linux/agent 1"))
+	require.Equal(t, "node", sanitizeNodeFileName("../"))
+}
@@ -19,6 +19,8 @@ type nodeListResponse struct {
 		Offline            bool   `json:"offline"`
 		TemporarilyOffline bool   `json:"temporarilyOffline"`
 		OfflineCauseReason string `json:"offlineCauseReason"`
+		// ConnectTime is set for agents only, in epoch milliseconds.
+		ConnectTime int64 `json:"connectTime"`
 	} `json:"computer"`
 }
 
@@ -27,6 +29,9 @@ type nodeInfo struct {
 	Offline   bool   `json:"offline"`
 	Temp      bool   `json:"temporarilyOffline"`
 	OfflineBy string `json:"offlineCause,omitempty"`
+	// ConnectedAt is when the agent last connected; the built-in node has
+	// none.
+	ConnectedAt string `json:"connectedAt,omitempty"`
 }
 
 func NewCmdNode(f *cmdutil.Factory) *cobra.Command {
@@ -40,6 +45,8 @@ func NewCmdNode(f *cmdutil.Factory) *cobra.Command {
 		newNodeCordonCmd(f),
 		newNodeUncordonCmd(f),
 		newNodeDeleteCmd(f),
+		newNodeConfigCmd(f),
+		newNodeUtilizationCmd(f),
 	)
 	return cmd
 }
@@ -56,7 +63,7 @@ func newNodeListCmd(f *cmdutil.Factory) *cobra.Command {
 
 			var resp nodeListResponse
 			_, err = client.Do(
-				client.NewRequest().SetQueryParam("tree", "computer[displayName,offline,temporarilyOffline,offlineCauseReason]"),
+				client.NewRequest().SetQueryParam("tree", "computer[displayName,offline,temporarilyOffline,offlineCauseReason,connectTime]"),
 				http.MethodGet,
 				"/computer/api/json",
 				&resp,
@@ -68,10 +75,11 @@ func newNodeListCmd(f *cmdutil.Factory) *cobra.Command {
 			nodes := make([]nodeInfo, 0, len(resp.Computers))
 			for _, n := range resp.Computers {
 				nodes = append(nodes, nodeInfo{
-					Name:      n.DisplayName,
-					Offline:   n.Offline,
-					Temp:      n.TemporarilyOffline,
-					OfflineBy: strings.TrimSpace(n.OfflineCauseReason),
+					Name:        n.DisplayName,
+					Offline:     n.Offline,
+					Temp:        n.TemporarilyOffline,
+					OfflineBy:   strings.TrimSpace(n.OfflineCauseReason),
+					ConnectedAt: shared.FormatTimestamp(n.ConnectTime),
 				})
 			}
 
@@ -102,27 +110,53 @@ func newNodeListCmd(f *cmdutil.Factory) *cobra.Command {
 
 func newNodeCordonCmd(f *cmdutil.Factory) *cobra.Command {
 	var message string
+	var sel bulkSelector
 	cmd := &cobra.Command{
-		Use:   "cordon <name>",
-		Short: "Mark a node temporarily offline",
-		Args:  cobra.ExactArgs(1),
+		Use:   "cordon [<name>]",
+		Short: "Mark nodes temporarily offline",
+		Long: `Mark a node temporarily offline so it takes no new builds.
+
+--label and --all cordon every matching node instead of a named one. The
+matching nodes are listed and must be confirmed (or pass --yes); nodes are then
+updated concurrently and the command exits 1 if any of them failed (0 with
+--ok-on-partial as long as one succeeded).`,
+		Example: `  jk node cordon linux-7 --message "disk replacement"
+  jk node cordon --label rack-12 --message "rack maintenance" --yes
+  jk node cordon --all --include-built-in --json --yes`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if sel.bulk() || len(args) == 0 {
+				return toggleNodes(cmd, f, args, sel, true, message)
+			}
 			return toggleNode(cmd, f, args[0], true, message)
 		},
 	}
 	cmd.Flags().StringVar(&message, "message", "", "Offline message to display")
+	addBulkSelectorFlags(cmd, &sel)
 	return cmd
 }
 
 func newNodeUncordonCmd(f *cmdutil.Factory) *cobra.Command {
-	return &cobra.Command{
-		Use:   "uncordon <name>",
-		Short: "Bring a node back online",
-		Args:  cobra.ExactArgs(1),
+	var sel bulkSelector
+	cmd := &cobra.Command{
+		Use:   "uncordon [<name>]",
+		Short: "Bring nodes back online",
+		Long: `Bring a cordoned node back online.
+
+--label and --all uncordon every matching node instead of a named one, with the
+same confirmation and per-node reporting as cordon.`,
+		Example: `  jk node uncordon linux-7
+  jk node uncordon --label rack-12 --yes`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if sel.bulk() || len(args) == 0 {
+				return toggleNodes(cmd, f, args, sel, false, "")
+			}
 			return toggleNode(cmd, f, args[0], false, "")
 		},
 	}
+	addBulkSelectorFlags(cmd, &sel)
+	return cmd
 }
 
 func newNodeDeleteCmd(f *cmdutil.Factory) *cobra.Command {
@@ -150,11 +184,10 @@ func newNodeDeleteCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 			if resp.StatusCode() >= 300 {
-				return fmt.Errorf("delete failed: %s", resp.Status())
+				return fmt.Errorf("delete failed: %s", shared.ResponseStatus(resp))
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Deleted node %s\n", name)
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "delete", Target: name, Status: shared.ActionDone}, "Deleted node %s", name)
 		},
 	}
 }
@@ -169,6 +202,18 @@ func toggleNode(cmd *cobra.Command, f *cmdutil.Factory, name string, offline boo
 		return err
 	}
 
+	if err := setNodeOffline(client, name, offline, message); err != nil {
+		return err
+	}
+
+	action, state := "uncordon", "online"
+	if offline {
+		action, state = "cordon", "cordoned"
+	}
+	return shared.PrintAction(cmd, shared.ActionResult{Action: action, Target: name, Status: shared.ActionDone}, "Node %s marked %s", name, state)
+}
+
+func setNodeOffline(client shared.Doer, name string, offline bool, message string) error {
 	encodedName := encodeNodeName(name)
 	params := url.Values{}
 	if message != "" {
@@ -187,14 +232,8 @@ func toggleNode(cmd *cobra.Command, f *cmdutil.Factory, name string, offline boo
 		return err
 	}
 	if resp.StatusCode() >= 300 {
-		return fmt.Errorf("toggle failed: %s", resp.Status())
-	}
-
-	state := "online"
-	if offline {
-		state = "cordoned"
+		return fmt.Errorf("toggle failed: %s", shared.ResponseStatus(resp))
 	}
-	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Node %s marked %s\n", name, state)
 	return nil
 }
 
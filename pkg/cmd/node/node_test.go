@@ -0,0 +1,175 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/outputschema"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestNodeListReportsConnectedAt(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/computer/api/json", map[string]any{
+		"computer": []map[string]any{
+			{"displayName": "Built-In Node"},
+			{"displayName": "linux-1", "connectTime": 1735689600000},
+		},
+	})
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdNode(f)
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"ls"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	require.NoError(t, cmd.Execute())
+
+	var nodes []nodeInfo
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &nodes))
+	require.Len(t, nodes, 2)
+	require.Empty(t, nodes[0].ConnectedAt)
+	require.Equal(t, "2025-01-01T00:00:00Z", nodes[1].ConnectedAt)
+	require.Contains(t, server.LastRequest(http.MethodGet, "/computer/api/json").Query.Get("tree"), "connectTime")
+}
+
+func TestOutputTimeFieldConventions(t *testing.T) {
+	for _, output := range []any{nodeInfo{}, utilizationOutput{}, nodeConfigExportOutput{}, bulkToggleOutput{}} {
+		require.Empty(t, outputschema.TimeFieldViolations(output), "%T", output)
+	}
+}
+
+func rackComputers() map[string]any {
+	labels := func(names ...string) []map[string]string {
+		out := make([]map[string]string, len(names))
+		for i, name := range names {
+			out[i] = map[string]string{"name": name}
+		}
+		return out
+	}
+	return map[string]any{
+		"computer": []map[string]any{
+			{"_class": "hudson.model.Hudson$MasterComputer", "displayName": "Built-In Node", "assignedLabels": labels("built-in")},
+			{"displayName": "linux-2", "offline": true, "temporarilyOffline": true, "assignedLabels": labels("linux-2", "rack-12")},
+			{"displayName": "linux-1", "assignedLabels": labels("linux-1", "rack-12")},
+			{"displayName": "linux-3", "assignedLabels": labels("linux-3", "rack-7")},
+		},
+	}
+}
+
+func executeNodeCmd(t *testing.T, server *fakejenkins.Server, client *jenkins.Client, args ...string) (*bytes.Buffer, *bytes.Buffer, error) {
+	t.Helper()
+	server.HandleJSON(http.MethodGet, "/computer/api/json", rackComputers())
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdNode(f)
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return stdout, stderr, cmd.Execute()
+}
+
+func TestNodeCordonByLabel(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/computer/linux-1/toggleOffline", http.StatusOK, "")
+
+	stdout, stderr, err := executeNodeCmd(t, server, client, "cordon", "--label", "rack-12", "--message", "rack maintenance", "--yes")
+	require.NoError(t, err)
+	require.Contains(t, stderr.String(), "Nodes to cordon (2): linux-1, linux-2")
+
+	var output bulkToggleOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Equal(t, "cordon", output.Action)
+	require.Equal(t, "rack-12", output.Label)
+	require.Equal(t, []bulkNodeResult{
+		{Name: "linux-1", State: "cordoned", Result: bulkResultChanged},
+		{Name: "linux-2", State: "cordoned", Result: bulkResultUnchanged},
+	}, output.Items)
+	require.Equal(t, shared.ResultSummary{Succeeded: 2}, output.Summary)
+	require.Empty(t, output.Errors)
+
+	query := server.LastRequest(http.MethodPost, "/computer/linux-1/toggleOffline").Query
+	require.Equal(t, "true", query.Get("offline"))
+	require.Equal(t, "rack maintenance", query.Get("offlineMessage"))
+	require.Empty(t, server.RequestsTo(http.MethodPost, "/computer/linux-2/toggleOffline"))
+	require.Contains(t, server.LastRequest(http.MethodGet, "/computer/api/json").Query.Get("tree"), "assignedLabels[name]")
+}
+
+func TestNodeUncordonAllReportsFailures(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/computer/linux-2/toggleOffline", http.StatusForbidden, "")
+
+	stdout, _, err := executeNodeCmd(t, server, client, "uncordon", "--all", "--yes")
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 1, exitErr.Code)
+	require.Contains(t, exitErr.Msg, "1 of 3 nodes failed")
+
+	var output bulkToggleOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Len(t, output.Items, 3, "built-in node is excluded from --all")
+	require.Equal(t, "linux-2", output.Items[1].Name)
+	require.Equal(t, bulkResultFailed, output.Items[1].Result)
+	require.Equal(t, "cordoned", output.Items[1].State)
+	require.Equal(t, bulkResultUnchanged, output.Items[0].Result)
+	require.Equal(t, shared.ResultSummary{Succeeded: 2, Failed: 1}, output.Summary)
+	require.Len(t, output.Errors, 1)
+	require.Equal(t, "linux-2", output.Errors[0].Target)
+	require.Contains(t, output.Errors[0].Message, "uncordon linux-2")
+
+	_, _, err = executeNodeCmd(t, server, client, "uncordon", "--all", "--yes", "--ok-on-partial")
+	require.NoError(t, err)
+}
+
+func TestNodeCordonAllIncludeBuiltIn(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	for _, path := range []string{"/computer/(master)/toggleOffline", "/computer/linux-1/toggleOffline", "/computer/linux-3/toggleOffline"} {
+		server.Handle(http.MethodPost, path, http.StatusOK, "")
+	}
+
+	stdout, _, err := executeNodeCmd(t, server, client, "cordon", "--all", "--include-built-in", "--yes")
+	require.NoError(t, err)
+
+	var output bulkToggleOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Len(t, output.Items, 4)
+	require.Len(t, server.RequestsTo(http.MethodPost, "/computer/(master)/toggleOffline"), 1)
+}
+
+func TestNodeCordonBulkValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+		msg  string
+	}{
+		{"name with selector", []string{"cordon", "linux-1", "--label", "rack-12"}, 2, "cannot be combined"},
+		{"no target", []string{"uncordon"}, 2, "requires a node name, --label, or --all"},
+		{"built-in without all", []string{"cordon", "--label", "rack-12", "--include-built-in"}, 2, "--include-built-in requires --all"},
+		{"unknown label", []string{"cordon", "--label", "rack-99", "--yes"}, 3, `no nodes have label "rack-99"`},
+		{"no confirmation", []string{"cordon", "--label", "rack-12"}, 2, "use --yes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := fakejenkins.NewClient(t)
+			_, _, err := executeNodeCmd(t, server, client, tt.args...)
+			var exitErr *cmdutil.ExitError
+			require.ErrorAs(t, err, &exitErr)
+			require.Equal(t, tt.code, exitErr.Code)
+			require.Contains(t, err.Error(), tt.msg)
+			require.Empty(t, server.RequestsTo(http.MethodPost, "/computer/linux-1/toggleOffline"))
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
+	"github.com/avivsinai/jenkins-cli/internal/prometheus"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// Utilization sources reported in the output.
+const (
+	utilizationSourcePrometheus = "prometheus"
+	utilizationSourceAPI        = "api"
+)
+
+const prometheusMetricsPath = "/prometheus/"
+
+// Metric names from the Jenkins Prometheus plugin, matched regardless of the
+// configured namespace prefix.
+const (
+	metricExecutorCount = "jenkins_executor_count_value"
+	metricExecutorInUse = "jenkins_executor_in_use_value"
+	metricQueueSize     = "jenkins_queue_size_value"
+	metricLabelBusy     = "jenkins_executors_busy"
+	metricLabelOnline   = "jenkins_executors_online"
+	metricLabelQueue    = "jenkins_executors_queue_length"
+)
+
+const utilizationTree = "computer[displayName,offline,numExecutors,executors[idle],assignedLabels[name]]"
+
+type utilizationOutput struct {
+	SchemaVersion string             `json:"schemaVersion"`
+	Source        string             `json:"source"`
+	Overall       utilizationStats   `json:"overall"`
+	Labels        []labelUtilization `json:"labels"`
+}
+
+type utilizationStats struct {
+	Busy    int     `json:"busy"`
+	Total   int     `json:"total"`
+	Percent float64 `json:"percent"`
+	// QueueLength is nil when the source does not report it.
+	QueueLength *int `json:"queueLength"`
+}
+
+type labelUtilization struct {
+	Label string `json:"label"`
+	utilizationStats
+}
+
+type utilizationComputers struct {
+	Computers []struct {
+		DisplayName  string `json:"displayName"`
+		Offline      bool   `json:"offline"`
+		NumExecutors int    `json:"numExecutors"`
+		Executors    []struct {
+			Idle bool `json:"idle"`
+		} `json:"executors"`
+		AssignedLabels []struct {
+			Name string `json:"name"`
+		} `json:"assignedLabels"`
+	} `json:"computer"`
+}
+
+func newNodeUtilizationCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "utilization",
+		Short: "Show executor utilization overall and per label",
+		Long: `Report how many executors are busy, overall and per label, with queue length.
+
+When the Prometheus plugin is installed the numbers come from one scrape of
+its /prometheus/ endpoint. Otherwise they are computed from /computer/api/json
+and /queue/api/json. Either way they are a point-in-time snapshot; offline
+nodes do not count toward the total.`,
+		Example: `  jk node utilization
+  jk node utilization --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			output, err := fetchUtilization(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+
+			return shared.PrintOutput(cmd, output, func() error {
+				return renderUtilizationHuman(cmd, output)
+			})
+		},
+	}
+	return cmd
+}
+
+// fetchUtilization prefers the Prometheus gauges and falls back to the JSON
+// API when the plugin is missing, its output lacks the executor metrics, or
+// the scrape fails.
+func fetchUtilization(ctx context.Context, client shared.Doer) (utilizationOutput, error) {
+	if client.Capabilities(ctx).Prometheus {
+		samples, err := scrapePrometheus(ctx, client)
+		if err != nil {
+			jklog.L().Debug().Err(err).Msg("scrape prometheus metrics failed; using the JSON API")
+		} else if output, ok := utilizationFromSamples(samples); ok {
+			return output, nil
+		}
+	}
+	return utilizationFromAPI(ctx, client)
+}
+
+func scrapePrometheus(ctx context.Context, client shared.Doer) ([]prometheus.Sample, error) {
+	req := client.NewRequest().SetHeader("Accept", "text/plain")
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+	resp, err := client.Do(req, http.MethodGet, prometheusMetricsPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() >= 400 {
+		return nil, nil
+	}
+	samples, err := prometheus.Parse(bytes.NewReader(resp.Body()))
+	if err != nil {
+		return nil, fmt.Errorf("parse prometheus metrics: %w", err)
+	}
+	return samples, nil
+}
+
+func utilizationFromSamples(samples []prometheus.Sample) (utilizationOutput, bool) {
+	var count, inUse, queue *int
+	labels := make(map[string]*labelUtilization)
+	label := func(name string) *labelUtilization {
+		if labels[name] == nil {
+			labels[name] = &labelUtilization{Label: name}
+		}
+		return labels[name]
+	}
+
+	for _, sample := range samples {
+		value := gaugeInt(sample.Value)
+		name := sample.Labels["label"]
+		switch {
+		case prometheus.MatchesName(sample.Name, metricExecutorCount):
+			count = &value
+		case prometheus.MatchesName(sample.Name, metricExecutorInUse):
+			inUse = &value
+		case prometheus.MatchesName(sample.Name, metricQueueSize):
+			queue = &value
+		case name != "" && prometheus.MatchesName(sample.Name, metricLabelBusy):
+			label(name).Busy = value
+		case name != "" && prometheus.MatchesName(sample.Name, metricLabelOnline):
+			label(name).Total = value
+		case name != "" && prometheus.MatchesName(sample.Name, metricLabelQueue):
+			label(name).QueueLength = &value
+		}
+	}
+	if count == nil || inUse == nil {
+		return utilizationOutput{}, false
+	}
+
+	output := utilizationOutput{
+		SchemaVersion: "1.0",
+		Source:        utilizationSourcePrometheus,
+		Overall:       newUtilizationStats(*inUse, *count),
+		Labels:        sortedLabels(labels),
+	}
+	output.Overall.QueueLength = queue
+	return output, true
+}
+
+func utilizationFromAPI(ctx context.Context, client shared.Doer) (utilizationOutput, error) {
+	req := client.NewRequest().SetQueryParam("tree", utilizationTree)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+	var computers utilizationComputers
+	resp, err := client.Do(req, http.MethodGet, "/computer/api/json", &computers)
+	if err != nil {
+		return utilizationOutput{}, err
+	}
+	if err := shared.CheckResponse(resp, "nodes"); err != nil {
+		return utilizationOutput{}, err
+	}
+
+	var busy, total int
+	labels := make(map[string]*labelUtilization)
+	for _, computer := range computers.Computers {
+		if computer.Offline {
+			continue
+		}
+		nodeBusy := 0
+		for _, executor := range computer.Executors {
+			if !executor.Idle {
+				nodeBusy++
+			}
+		}
+		busy += nodeBusy
+		total += computer.NumExecutors
+		for _, assigned := range computer.AssignedLabels {
+			// Every node carries a label of its own name; only shared
+			// labels say anything about capacity.
+			if assigned.Name == "" || assigned.Name == computer.DisplayName {
+				continue
+			}
+			if labels[assigned.Name] == nil {
+				labels[assigned.Name] = &labelUtilization{Label: assigned.Name}
+			}
+			labels[assigned.Name].Busy += nodeBusy
+			labels[assigned.Name].Total += computer.NumExecutors
+		}
+	}
+
+	output := utilizationOutput{
+		SchemaVersion: "1.0",
+		Source:        utilizationSourceAPI,
+		Overall:       newUtilizationStats(busy, total),
+		Labels:        sortedLabels(labels),
+	}
+
+	queue, err := fetchQueueLength(ctx, client)
+	if err != nil {
+		return utilizationOutput{}, err
+	}
+	output.Overall.QueueLength = queue
+	return output, nil
+}
+
+func fetchQueueLength(ctx context.Context, client shared.Doer) (*int, error) {
+	req := client.NewRequest().SetQueryParam("tree", "items[id]")
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+	var queue struct {
+		Items []struct {
+			ID int64 `json:"id"`
+		} `json:"items"`
+	}
+	resp, err := client.Do(req, http.MethodGet, "/queue/api/json", &queue)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() >= 400 {
+		return nil, nil
+	}
+	length := len(queue.Items)
+	return &length, nil
+}
+
+func newUtilizationStats(busy, total int) utilizationStats {
+	stats := utilizationStats{Busy: busy, Total: total}
+	if total > 0 {
+		stats.Percent = math.Round(float64(busy)/float64(total)*1000) / 10
+	}
+	return stats
+}
+
+func sortedLabels(labels map[string]*labelUtilization) []labelUtilization {
+	out := make([]labelUtilization, 0, len(labels))
+	for _, label := range labels {
+		stats := newUtilizationStats(label.Busy, label.Total)
+		stats.QueueLength = label.QueueLength
+		out = append(out, labelUtilization{Label: label.Label, utilizationStats: stats})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Label < out[j].Label })
+	return out
+}
+
+func gaugeInt(value float64) int {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0
+	}
+	return int(math.Round(value))
+}
+
+func renderUtilizationHuman(cmd *cobra.Command, output utilizationOutput) error {
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "Overall\t%s\n", formatUtilization(output.Overall))
+	for _, label := range output.Labels {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", label.Label, formatUtilization(label.utilizationStats))
+	}
+	return nil
+}
+
+func formatUtilization(stats utilizationStats) string {
+	line := fmt.Sprintf("%d/%d busy\t%.1f%%", stats.Busy, stats.Total, stats.Percent)
+	if stats.QueueLength != nil {
+		line += fmt.Sprintf("\t%d queued", *stats.QueueLength)
+	}
+	return line
+}
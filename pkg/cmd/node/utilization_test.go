@@ -0,0 +1,114 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+const prometheusScrape = `# HELP default_jenkins_executor_count_value Executor count
+# TYPE default_jenkins_executor_count_value gauge
+default_jenkins_executor_count_value 10.0
+default_jenkins_executor_in_use_value 6.0
+default_jenkins_queue_size_value 3.0
+default_jenkins_executors_busy{label="linux",} 4.0
+default_jenkins_executors_online{label="linux",} 6.0
+default_jenkins_executors_queue_length{label="linux",} 2.0
+default_jenkins_executors_busy{label="windows",} 2.0
+default_jenkins_executors_online{label="windows",} 4.0
+`
+
+// executeUtilization registers routes before the client exists, since the
+// Prometheus capability is probed when the client is created.
+func executeUtilization(t *testing.T, setup func(*fakejenkins.Server)) (*fakejenkins.Server, utilizationOutput) {
+	t.Helper()
+	server := fakejenkins.New(t)
+	setup(server)
+	client := jenkinstest.NewClient(t, server)
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdNode(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"utilization", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	require.NoError(t, cmd.Execute())
+
+	var output utilizationOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+	return server, output
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestNodeUtilizationFromPrometheus(t *testing.T) {
+	server, output := executeUtilization(t, func(s *fakejenkins.Server) {
+		s.Handle(http.MethodHead, "/prometheus", http.StatusOK, "")
+		s.Handle(http.MethodGet, "/prometheus/", http.StatusOK, prometheusScrape)
+	})
+
+	require.Equal(t, "1.0", output.SchemaVersion)
+	require.Equal(t, utilizationSourcePrometheus, output.Source)
+	require.Equal(t, utilizationStats{Busy: 6, Total: 10, Percent: 60, QueueLength: intPtr(3)}, output.Overall)
+	require.Equal(t, []labelUtilization{
+		{Label: "linux", utilizationStats: utilizationStats{Busy: 4, Total: 6, Percent: 66.7, QueueLength: intPtr(2)}},
+		{Label: "windows", utilizationStats: utilizationStats{Busy: 2, Total: 4, Percent: 50}},
+	}, output.Labels)
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/computer/api/json"))
+}
+
+func serveComputerAPI(s *fakejenkins.Server) {
+	s.HandleJSON(http.MethodGet, "/computer/api/json", map[string]any{
+		"computer": []map[string]any{
+			{
+				"displayName": "agent-1", "numExecutors": 2,
+				"executors":      []map[string]any{{"idle": false}, {"idle": true}},
+				"assignedLabels": []map[string]any{{"name": "agent-1"}, {"name": "linux"}},
+			},
+			{
+				"displayName": "agent-2", "numExecutors": 2,
+				"executors":      []map[string]any{{"idle": false}, {"idle": false}},
+				"assignedLabels": []map[string]any{{"name": "agent-2"}, {"name": "linux"}, {"name": "docker"}},
+			},
+			{
+				"displayName": "agent-3", "numExecutors": 4, "offline": true,
+				"executors":      []map[string]any{},
+				"assignedLabels": []map[string]any{{"name": "linux"}},
+			},
+		},
+	})
+	s.HandleJSON(http.MethodGet, "/queue/api/json", map[string]any{
+		"items": []map[string]any{{"id": 1}, {"id": 2}},
+	})
+}
+
+func TestNodeUtilizationFallsBackToAPI(t *testing.T) {
+	_, output := executeUtilization(t, serveComputerAPI)
+
+	require.Equal(t, utilizationSourceAPI, output.Source)
+	require.Equal(t, utilizationStats{Busy: 3, Total: 4, Percent: 75, QueueLength: intPtr(2)}, output.Overall)
+	require.Equal(t, []labelUtilization{
+		{Label: "docker", utilizationStats: utilizationStats{Busy: 2, Total: 2, Percent: 100}},
+		{Label: "linux", utilizationStats: utilizationStats{Busy: 3, Total: 4, Percent: 75}},
+	}, output.Labels)
+}
+
+func TestNodeUtilizationFallsBackOnMalformedScrape(t *testing.T) {
+	server, output := executeUtilization(t, func(s *fakejenkins.Server) {
+		s.Handle(http.MethodHead, "/prometheus", http.StatusOK, "")
+		s.Handle(http.MethodGet, "/prometheus/", http.StatusOK, "jenkins_executor_count_value{label=\"linux\" ten\n")
+		serveComputerAPI(s)
+	})
+
+	require.Len(t, server.RequestsTo(http.MethodGet, "/prometheus/"), 1)
+	require.Equal(t, utilizationSourceAPI, output.Source)
+	require.Equal(t, 4, output.Overall.Total)
+}
@@ -0,0 +1,117 @@
+package open
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// NewCmdOpen resolves a shared run reference or build URL and hands it to
+// the matching run command.
+func NewCmdOpen(f *cmdutil.Factory) *cobra.Command {
+	var (
+		showLog       bool
+		showArtifacts bool
+		rerun         bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "open <ref>",
+		Short: "Open a run from a jk:// reference or a Jenkins build URL",
+		Long: `Open a run from a reference printed by 'jk run link' or from a Jenkins build
+URL pasted from the browser or a chat message.
+
+URLs are matched to a configured context by host and path. Classic job URLs
+(including folder views, /console, /consoleFull, and /display/redirect) and
+Blue Ocean run URLs are understood.
+
+By default the run is shown as with 'jk run view'; --log, --artifacts, and
+--rerun chain into 'jk log', 'jk artifact ls', and 'jk run rerun' instead.`,
+		Example: `  jk open jk://prod/team/app/42
+  jk open https://jenkins.example.com/job/team/job/app/42/console --log
+  jk open https://jenkins.example.com/blue/organizations/jenkins/team%2Fapp/detail/main/7/pipeline --artifacts`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := f.ResolveConfig()
+			if err != nil {
+				return err
+			}
+			ref, err := shared.ParseRunRef(args[0], cfg)
+			if err != nil {
+				return err
+			}
+
+			path := []string{"run", "view"}
+			switch {
+			case showLog:
+				path = []string{"log"}
+			case showArtifacts:
+				path = []string{"artifact", "ls"}
+			case rerun:
+				path = []string{"run", "rerun"}
+			}
+			return dispatch(cmd, path, ref)
+		},
+	}
+
+	cmd.Flags().BoolVar(&showLog, "log", false, "Show the run's console log")
+	cmd.Flags().BoolVar(&showArtifacts, "artifacts", false, "List the run's artifacts")
+	cmd.Flags().BoolVar(&rerun, "rerun", false, "Rerun with the same parameters")
+	cmd.MarkFlagsMutuallyExclusive("log", "artifacts", "rerun")
+
+	return cmd
+}
+
+// dispatch runs the command at path for ref with its default flags, pointing
+// the root --context at the reference's context. The job path is passed with
+// a leading "/" so no default folder applies.
+func dispatch(cmd *cobra.Command, path []string, ref shared.RunRef) error {
+	target, _, err := cmd.Root().Find(path)
+	if err != nil || target == nil || target.RunE == nil {
+		return fmt.Errorf("jk open: command %q is unavailable", path)
+	}
+	if err := cmd.Root().PersistentFlags().Set("context", ref.Context); err != nil {
+		return err
+	}
+	if err := target.ParseFlags(nil); err != nil {
+		return err
+	}
+	target.SetContext(cmd.Context())
+
+	args := []string{"/" + ref.JobPath, strconv.FormatInt(ref.Number, 10)}
+	if err := target.ValidateArgs(args); err != nil {
+		return err
+	}
+	if err := preRun(target, args); err != nil {
+		return err
+	}
+	return target.RunE(target, args)
+}
+
+// preRun runs the hooks cobra would run before target: the nearest
+// persistent pre-run, then target's own pre-run.
+func preRun(target *cobra.Command, args []string) error {
+	for c := target; c != nil; c = c.Parent() {
+		if c.PersistentPreRunE != nil {
+			if err := c.PersistentPreRunE(target, args); err != nil {
+				return err
+			}
+			break
+		}
+		if c.PersistentPreRun != nil {
+			c.PersistentPreRun(target, args)
+			break
+		}
+	}
+	if target.PreRunE != nil {
+		return target.PreRunE(target, args)
+	}
+	if target.PreRun != nil {
+		target.PreRun(target, args)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package open
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func newTestRoot(t *testing.T) (*fakejenkins.Server, *jenkins.Client, *cobra.Command) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	f, stdout, stderr := fakejenkins.Factory(client)
+	f.Config = func() (*config.Config, error) {
+		return &config.Config{Contexts: map[string]*config.Context{client.ContextName(): client.Context()}}, nil
+	}
+
+	root := &cobra.Command{Use: "jk", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().StringP("context", "c", "", "")
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.AddCommand(runcmd.NewCmdRun(f), NewCmdOpen(f))
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	return server, client, root
+}
+
+func TestOpenDispatchesBuildURLToRunView(t *testing.T) {
+	server, client, root := newTestRoot(t)
+	server.Handle(http.MethodGet, "/job/team/job/app/42/api/json", http.StatusOK, `{"number":42,"result":"SUCCESS","building":false}`)
+
+	link := strings.TrimSuffix(client.Context().URL, "/") + "/view/All/job/team/job/app/42/console"
+	root.SetArgs([]string{"open", link, "--json"})
+	require.NoError(t, root.Execute())
+
+	require.Len(t, server.RequestsTo(http.MethodGet, "/job/team/job/app/42/api/json"), 1)
+	name, err := root.PersistentFlags().GetString("context")
+	require.NoError(t, err)
+	require.Equal(t, client.ContextName(), name)
+}
+
+func TestOpenRerunFromReference(t *testing.T) {
+	server, client, root := newTestRoot(t)
+	server.Handle(http.MethodGet, "/job/app/7/api/json", http.StatusOK, `{"number":7,"result":"FAILURE","actions":[]}`)
+	server.Handle(http.MethodPost, "/job/app/build", http.StatusCreated, "")
+
+	root.SetArgs([]string{"open", "jk://" + client.ContextName() + "/app/7", "--rerun"})
+	require.NoError(t, root.Execute())
+
+	require.NotNil(t, server.LastRequest(http.MethodPost, "/job/app/build"))
+}
+
+func TestOpenRejectsUnknownHost(t *testing.T) {
+	_, _, root := newTestRoot(t)
+	root.SetArgs([]string{"open", "https://elsewhere.example.com/job/app/1/"})
+
+	err := root.Execute()
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 2, exitErr.Code)
+}
+
+func TestOpenFlagsAreExclusive(t *testing.T) {
+	_, client, root := newTestRoot(t)
+	root.SetArgs([]string{"open", "jk://" + client.ContextName() + "/app/7", "--log", "--rerun"})
+
+	require.Error(t, root.Execute())
+}
+
+func TestOpenRunsPreRunHooks(t *testing.T) {
+	server, client, root := newTestRoot(t)
+	server.Handle(http.MethodGet, "/job/app/7/api/json", http.StatusOK, `{"number":7,"result":"SUCCESS","building":false}`)
+
+	var hooked []string
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		hooked = append(hooked, cmd.Name())
+		return nil
+	}
+	root.SetArgs([]string{"open", "jk://" + client.ContextName() + "/app/7", "--json"})
+	require.NoError(t, root.Execute())
+
+	require.Equal(t, []string{"open", "view"}, hooked)
+}
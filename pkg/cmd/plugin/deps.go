@@ -0,0 +1,278 @@
+package plugin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const pluginGraphTree = "plugins[shortName,version,enabled,pinned,hasUpdate,dependencies[shortName,version,optional]]"
+
+type pluginDependency struct {
+	ShortName string `json:"shortName"`
+	Version   string `json:"version"`
+	Optional  bool   `json:"optional"`
+}
+
+// pluginGraph holds the full plugin list with forward and reverse edges. It is
+// built from a single pluginManager request and reused for every lookup in a
+// command.
+type pluginGraph struct {
+	plugins    map[string]pluginEntry
+	names      []string
+	dependents map[string][]pluginDependency
+}
+
+func fetchPluginGraph(client shared.Doer) (*pluginGraph, error) {
+	var resp pluginListResponse
+	req := client.NewRequest().
+		SetQueryParam("depth", "2").
+		SetQueryParam("tree", pluginGraphTree)
+	httpResp, err := client.Do(req, http.MethodGet, "/pluginManager/api/json", &resp)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.CheckResponse(httpResp, "plugin manager"); err != nil {
+		return nil, err
+	}
+	return newPluginGraph(resp.Plugins), nil
+}
+
+func newPluginGraph(entries []pluginEntry) *pluginGraph {
+	g := &pluginGraph{
+		plugins:    make(map[string]pluginEntry, len(entries)),
+		names:      make([]string, 0, len(entries)),
+		dependents: make(map[string][]pluginDependency),
+	}
+	for _, entry := range entries {
+		g.plugins[entry.ShortName] = entry
+		g.names = append(g.names, entry.ShortName)
+	}
+	sort.Strings(g.names)
+	for _, name := range g.names {
+		entry := g.plugins[name]
+		for _, dep := range entry.Dependencies {
+			g.dependents[dep.ShortName] = append(g.dependents[dep.ShortName], pluginDependency{
+				ShortName: entry.ShortName,
+				Version:   entry.Version,
+				Optional:  dep.Optional,
+			})
+		}
+	}
+	return g
+}
+
+// orphans returns enabled plugins that no installed plugin depends on, not even
+// optionally.
+func (g *pluginGraph) orphans() []pluginEntry {
+	var out []pluginEntry
+	for _, name := range g.names {
+		entry := g.plugins[name]
+		if entry.Enabled && len(g.dependents[name]) == 0 {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// Plugin problem kinds.
+const (
+	// problemDisabledDependency marks a disabled plugin that enabled plugins
+	// require; Jenkins fails to load those dependents after a restart.
+	problemDisabledDependency = "disabled-dependency"
+)
+
+type pluginProblem struct {
+	Kind       string   `json:"kind"`
+	RequiredBy []string `json:"requiredBy,omitempty"`
+}
+
+// Summary is the problem as a human-readable annotation.
+func (p pluginProblem) Summary() string {
+	switch p.Kind {
+	case problemDisabledDependency:
+		return fmt.Sprintf("disabled but required by %s", strings.Join(p.RequiredBy, ", "))
+	default:
+		return p.Kind
+	}
+}
+
+// problems reports what is wrong with the installed plugin name. A disabled
+// plugin is a problem when an enabled plugin depends on it non-optionally.
+func (g *pluginGraph) problems(name string) []pluginProblem {
+	entry, ok := g.plugins[name]
+	if !ok || entry.Enabled {
+		return nil
+	}
+	var requiredBy []string
+	for _, dep := range g.dependents[name] {
+		if dep.Optional {
+			continue
+		}
+		if dependent, ok := g.plugins[dep.ShortName]; ok && dependent.Enabled {
+			requiredBy = append(requiredBy, dep.ShortName)
+		}
+	}
+	if len(requiredBy) == 0 {
+		return nil
+	}
+	return []pluginProblem{{Kind: problemDisabledDependency, RequiredBy: requiredBy}}
+}
+
+type pluginDepsOutput struct {
+	SchemaVersion string           `json:"schemaVersion"`
+	Name          string           `json:"name"`
+	Version       string           `json:"version"`
+	Enabled       bool             `json:"enabled"`
+	Dependencies  []pluginDepsNode `json:"dependencies"`
+	Dependents    []pluginDepsNode `json:"dependents"`
+}
+
+type pluginDepsNode struct {
+	Name     string           `json:"name"`
+	Version  string           `json:"version,omitempty"`
+	Optional bool             `json:"optional,omitempty"`
+	Missing  bool             `json:"missing,omitempty"`
+	Cycle    bool             `json:"cycle,omitempty"`
+	Children []pluginDepsNode `json:"children,omitempty"`
+}
+
+func newPluginDepsCmd(f *cmdutil.Factory) *cobra.Command {
+	var transitive bool
+
+	cmd := &cobra.Command{
+		Use:   "deps <name>",
+		Short: "Show what a plugin depends on and what depends on it",
+		Long: `Show a plugin's declared dependencies and the installed plugins that depend
+on it. With --transitive both directions are expanded recursively.
+
+Dependency versions are the minimum versions the plugin declares; dependent
+versions are the installed versions.`,
+		Example: `  # Why is this plugin installed?
+  jk plugin deps structs --transitive
+
+  # Nested JSON for tooling
+  jk plugin deps workflow-job --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				return shared.NewExitError(2, "plugin name required")
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			graph, err := fetchPluginGraph(client)
+			if err != nil {
+				return err
+			}
+
+			output, err := buildPluginDeps(graph, name, transitive)
+			if err != nil {
+				return err
+			}
+
+			return shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				_, _ = fmt.Fprintf(w, "%s %s\n", output.Name, output.Version)
+				renderPluginDepsSection(w, "Depends on", output.Dependencies)
+				renderPluginDepsSection(w, "Required by", output.Dependents)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&transitive, "transitive", false, "Expand dependencies and dependents recursively")
+	return cmd
+}
+
+func buildPluginDeps(g *pluginGraph, name string, transitive bool) (pluginDepsOutput, error) {
+	entry, ok := g.plugins[name]
+	if !ok {
+		return pluginDepsOutput{}, shared.NewExitError(3, fmt.Sprintf("plugin %s not installed", name))
+	}
+
+	forward := func(name string) []pluginDependency {
+		return g.plugins[name].Dependencies
+	}
+	reverse := func(name string) []pluginDependency {
+		return g.dependents[name]
+	}
+
+	return pluginDepsOutput{
+		SchemaVersion: "1.0",
+		Name:          entry.ShortName,
+		Version:       entry.Version,
+		Enabled:       entry.Enabled,
+		Dependencies:  g.expand(name, forward, transitive, map[string]bool{name: true}),
+		Dependents:    g.expand(name, reverse, transitive, map[string]bool{name: true}),
+	}, nil
+}
+
+// expand walks edges from name. path holds the plugins on the current branch
+// so cycles are reported instead of recursing forever.
+func (g *pluginGraph) expand(name string, edges func(string) []pluginDependency, transitive bool, path map[string]bool) []pluginDepsNode {
+	deps := append([]pluginDependency(nil), edges(name)...)
+	sort.Slice(deps, func(i, j int) bool { return deps[i].ShortName < deps[j].ShortName })
+
+	nodes := make([]pluginDepsNode, 0, len(deps))
+	for _, dep := range deps {
+		node := pluginDepsNode{Name: dep.ShortName, Version: dep.Version, Optional: dep.Optional}
+		if _, ok := g.plugins[dep.ShortName]; !ok {
+			node.Missing = true
+		}
+		switch {
+		case path[dep.ShortName]:
+			node.Cycle = true
+		case transitive && !node.Missing:
+			path[dep.ShortName] = true
+			node.Children = g.expand(dep.ShortName, edges, transitive, path)
+			delete(path, dep.ShortName)
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+func renderPluginDepsSection(w io.Writer, title string, nodes []pluginDepsNode) {
+	if len(nodes) == 0 {
+		_, _ = fmt.Fprintf(w, "%s: (none)\n", title)
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%s:\n", title)
+	renderPluginDepsTree(w, nodes, "  ")
+}
+
+func renderPluginDepsTree(w io.Writer, nodes []pluginDepsNode, indent string) {
+	for _, node := range nodes {
+		line := indent + node.Name
+		if node.Version != "" {
+			line += " " + node.Version
+		}
+		var notes []string
+		if node.Optional {
+			notes = append(notes, "optional")
+		}
+		if node.Missing {
+			notes = append(notes, "not installed")
+		}
+		if node.Cycle {
+			notes = append(notes, "cycle")
+		}
+		if len(notes) > 0 {
+			line += " (" + strings.Join(notes, ", ") + ")"
+		}
+		_, _ = fmt.Fprintln(w, line)
+		renderPluginDepsTree(w, node.Children, indent+"  ")
+	}
+}
@@ -0,0 +1,98 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func newPluginGraphClient(t *testing.T) (*fakejenkins.Server, *cmdutil.Factory, *bytes.Buffer) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/pluginManager/api/json", map[string]any{
+		"plugins": []map[string]any{
+			{"shortName": "structs", "version": "1.0", "enabled": true},
+			{"shortName": "step-api", "version": "2.0", "enabled": true, "dependencies": []map[string]any{
+				{"shortName": "structs", "version": "1.0"},
+			}},
+			{"shortName": "workflow-job", "version": "3.0", "enabled": true, "dependencies": []map[string]any{
+				{"shortName": "step-api", "version": "2.0"},
+				{"shortName": "ghost", "version": "0.1", "optional": true},
+			}},
+			{"shortName": "blueocean", "version": "4.0", "enabled": true, "dependencies": []map[string]any{
+				{"shortName": "structs", "version": "1.0", "optional": true},
+			}},
+			{"shortName": "legacy", "version": "0.9", "enabled": false},
+		},
+	})
+	f, stdout, _ := fakejenkins.Factory(client)
+	return server, f, stdout
+}
+
+func runPluginCmd(cmd *cobra.Command, stdout *bytes.Buffer, args ...string) error {
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(args)
+	cmd.SetOut(stdout)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func TestPluginDepsReverseTransitive(t *testing.T) {
+	server, f, stdout := newPluginGraphClient(t)
+
+	err := runPluginCmd(newPluginDepsCmd(f), stdout, "structs", "--transitive")
+	require.NoError(t, err)
+	require.Equal(t, `structs 1.0
+Depends on: (none)
+Required by:
+  blueocean 4.0 (optional)
+  step-api 2.0
+    workflow-job 3.0
+`, stdout.String())
+
+	reqs := server.RequestsTo(http.MethodGet, "/pluginManager/api/json")
+	require.Len(t, reqs, 1)
+	require.Equal(t, pluginGraphTree, reqs[0].Query.Get("tree"))
+}
+
+func TestPluginDepsJSONMarksMissingAndOptional(t *testing.T) {
+	_, f, stdout := newPluginGraphClient(t)
+
+	err := runPluginCmd(newPluginDepsCmd(f), stdout, "workflow-job", "--transitive", "--json")
+	require.NoError(t, err)
+
+	var out pluginDepsOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	require.Len(t, out.Dependencies, 2)
+	require.Equal(t, pluginDepsNode{Name: "ghost", Version: "0.1", Optional: true, Missing: true}, out.Dependencies[0])
+	require.Equal(t, "step-api", out.Dependencies[1].Name)
+	require.Equal(t, []pluginDepsNode{{Name: "structs", Version: "1.0"}}, out.Dependencies[1].Children)
+	require.Empty(t, out.Dependents)
+}
+
+func TestPluginListOrphans(t *testing.T) {
+	_, f, stdout := newPluginGraphClient(t)
+
+	err := runPluginCmd(newPluginListCmd(f), stdout, "--orphans")
+	require.NoError(t, err)
+	require.Equal(t, "blueocean\t4.0\tenabled\nworkflow-job\t3.0\tenabled\n", stdout.String())
+}
+
+func TestPluginGraphReportsCycles(t *testing.T) {
+	graph := newPluginGraph([]pluginEntry{
+		{ShortName: "a", Dependencies: []pluginDependency{{ShortName: "b"}}},
+		{ShortName: "b", Dependencies: []pluginDependency{{ShortName: "a"}}},
+	})
+	out, err := buildPluginDeps(graph, "a", true)
+	require.NoError(t, err)
+	require.Equal(t, []pluginDepsNode{{Name: "b", Children: []pluginDepsNode{{Name: "a", Cycle: true}}}}, out.Dependencies)
+}
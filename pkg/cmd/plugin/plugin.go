@@ -1,7 +1,6 @@
 package plugin
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/xml"
 	"errors"
@@ -12,17 +11,22 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/avivsinai/jenkins-cli/internal/filter"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
 type pluginListResponse struct {
-	Plugins []struct {
-		ShortName string `json:"shortName"`
-		Version   string `json:"version"`
-		Enabled   bool   `json:"enabled"`
-		Pinned    bool   `json:"pinned"`
-	} `json:"plugins"`
+	Plugins []pluginEntry `json:"plugins"`
+}
+
+type pluginEntry struct {
+	ShortName    string             `json:"shortName"`
+	Version      string             `json:"version"`
+	Enabled      bool               `json:"enabled"`
+	Pinned       bool               `json:"pinned"`
+	HasUpdate    bool               `json:"hasUpdate"`
+	Dependencies []pluginDependency `json:"dependencies,omitempty"`
 }
 
 func NewCmdPlugin(f *cmdutil.Factory) *cobra.Command {
@@ -33,65 +37,207 @@ func NewCmdPlugin(f *cmdutil.Factory) *cobra.Command {
 
 	cmd.AddCommand(
 		newPluginListCmd(f),
+		newPluginDepsCmd(f),
 		newPluginInstallCmd(f),
 		newPluginToggleCmd(f, true),
 		newPluginToggleCmd(f, false),
+		newPluginVerifyCmd(f),
 	)
 	return cmd
 }
 
+// pluginFilterKeys are the keys plugin ls accepts in --filter.
+var pluginFilterKeys = []string{"name", "version", "enabled", "pinned", "hasUpdate"}
+
+type pluginRow struct {
+	Name         string             `json:"name"`
+	Version      string             `json:"version"`
+	Enabled      bool               `json:"enabled"`
+	Pinned       bool               `json:"pinned"`
+	HasUpdate    bool               `json:"hasUpdate,omitempty"`
+	Dependencies []pluginDependency `json:"dependencies,omitempty"`
+	Problems     []pluginProblem    `json:"problems,omitempty"`
+}
+
+type pluginListOptions struct {
+	Filters   []filter.Filter
+	Enabled   bool
+	Disabled  bool
+	HasUpdate bool
+	Orphans   bool
+	Problems  bool
+}
+
+// needsGraph reports whether the listing needs the depth=2 query, which
+// carries dependencies and hasUpdate but is slow on large instances.
+func (o pluginListOptions) needsGraph() bool {
+	if o.HasUpdate || o.Orphans || o.Problems {
+		return true
+	}
+	for _, f := range o.Filters {
+		if f.Key == "hasupdate" {
+			return true
+		}
+	}
+	return false
+}
+
 func newPluginListCmd(f *cmdutil.Factory) *cobra.Command {
-	return &cobra.Command{
+	var (
+		opts       pluginListOptions
+		filterArgs []string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List installed plugins",
+		Example: `  # List installed plugins
+  jk plugin ls
+
+  # Enabled plugins nothing depends on (removal candidates)
+  jk plugin ls --orphans
+
+  # Enabled plugins with an update available
+  jk plugin ls --enabled --has-update
+
+  # Disabled plugins that enabled plugins still require
+  jk plugin ls --problems`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := shared.JenkinsClient(cmd, f)
+			filters, err := filter.ParseKeys(filterArgs, pluginFilterKeys)
 			if err != nil {
 				return err
 			}
+			opts.Filters = filters
 
-			var resp pluginListResponse
-			_, err = client.Do(client.NewRequest().SetQueryParam("depth", "1"), http.MethodGet, "/pluginManager/api/json", &resp)
+			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
-			type pluginRow struct {
-				Name    string `json:"name"`
-				Version string `json:"version"`
-				Enabled bool   `json:"enabled"`
-				Pinned  bool   `json:"pinned"`
-			}
-
-			rows := make([]pluginRow, 0, len(resp.Plugins))
-			for _, p := range resp.Plugins {
-				rows = append(rows, pluginRow{
-					Name:    p.ShortName,
-					Version: p.Version,
-					Enabled: p.Enabled,
-					Pinned:  p.Pinned,
-				})
+			rows, err := listPlugins(client, opts)
+			if err != nil {
+				return err
 			}
 
 			return shared.PrintOutput(cmd, rows, func() error {
+				w := cmd.OutOrStdout()
 				if len(rows) == 0 {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No plugins installed")
+					switch {
+					case opts.Problems:
+						_, _ = fmt.Fprintln(w, "No plugin problems found")
+					case opts.Orphans:
+						_, _ = fmt.Fprintln(w, "No orphaned plugins")
+					case len(opts.Filters) > 0 || opts.Enabled || opts.Disabled || opts.HasUpdate:
+						_, _ = fmt.Fprintln(w, "No matching plugins")
+					default:
+						_, _ = fmt.Fprintln(w, "No plugins installed")
+					}
 					return nil
 				}
 				for _, row := range rows {
-					status := "enabled"
-					if !row.Enabled {
-						status = "disabled"
-					}
-					if row.Pinned {
-						status += " (pinned)"
-					}
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", row.Name, row.Version, status)
+					_, _ = fmt.Fprintln(w, formatPluginRow(row))
 				}
 				return nil
 			})
 		},
 	}
+
+	cmd.Flags().BoolVar(&opts.Orphans, "orphans", false, "Only list enabled plugins that no other plugin depends on")
+	cmd.Flags().StringSliceVar(&filterArgs, "filter", nil, "Filter plugins (repeatable): key[op]value, e.g. name~git")
+	cmd.Flags().BoolVar(&opts.Enabled, "enabled", false, "Only list enabled plugins")
+	cmd.Flags().BoolVar(&opts.Disabled, "disabled", false, "Only list disabled plugins")
+	cmd.Flags().BoolVar(&opts.HasUpdate, "has-update", false, "Only list plugins with an update available")
+	cmd.Flags().BoolVar(&opts.Problems, "problems", false, "Only list disabled plugins that enabled plugins require")
+	cmd.MarkFlagsMutuallyExclusive("enabled", "disabled")
+	cmd.MarkFlagsMutuallyExclusive("orphans", "problems")
+	cmdutil.SetFilterSupport(cmd, pluginFilterKeys, filter.Operators())
+	return cmd
+}
+
+// listPlugins fetches the plugin list, from the dependency graph only when an
+// option needs it, and applies the filters.
+func listPlugins(client shared.Doer, opts pluginListOptions) ([]pluginRow, error) {
+	var (
+		entries []pluginEntry
+		graph   *pluginGraph
+	)
+	if opts.needsGraph() {
+		var err error
+		graph, err = fetchPluginGraph(client)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case opts.Orphans:
+			entries = graph.orphans()
+		default:
+			for _, name := range graph.names {
+				entries = append(entries, graph.plugins[name])
+			}
+		}
+	} else {
+		var resp pluginListResponse
+		_, err := client.Do(client.NewRequest().SetQueryParam("depth", "1"), http.MethodGet, "/pluginManager/api/json", &resp)
+		if err != nil {
+			return nil, err
+		}
+		entries = resp.Plugins
+	}
+
+	rows := make([]pluginRow, 0, len(entries))
+	for _, p := range entries {
+		switch {
+		case opts.Enabled && !p.Enabled,
+			opts.Disabled && p.Enabled,
+			opts.HasUpdate && !p.HasUpdate:
+			continue
+		}
+		values := filter.Context{
+			"name":      p.ShortName,
+			"version":   p.Version,
+			"enabled":   p.Enabled,
+			"pinned":    p.Pinned,
+			"hasupdate": p.HasUpdate,
+		}
+		if !filter.Evaluate(values, opts.Filters) {
+			continue
+		}
+
+		row := pluginRow{
+			Name:      p.ShortName,
+			Version:   p.Version,
+			Enabled:   p.Enabled,
+			Pinned:    p.Pinned,
+			HasUpdate: p.HasUpdate,
+		}
+		if graph != nil {
+			row.Dependencies = p.Dependencies
+			row.Problems = graph.problems(p.ShortName)
+		}
+		if opts.Problems && len(row.Problems) == 0 {
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func formatPluginRow(row pluginRow) string {
+	status := "enabled"
+	if !row.Enabled {
+		status = "disabled"
+	}
+	if row.Pinned {
+		status += " (pinned)"
+	}
+	line := fmt.Sprintf("%s\t%s\t%s", row.Name, row.Version, status)
+	if row.HasUpdate {
+		line += "\tupdate available"
+	}
+	for _, problem := range row.Problems {
+		line += "\t" + problem.Summary()
+	}
+	return line
 }
 
 func newPluginInstallCmd(f *cmdutil.Factory) *cobra.Command {
@@ -101,54 +247,80 @@ func newPluginInstallCmd(f *cmdutil.Factory) *cobra.Command {
 		Short: "Install plugins via the Jenkins update center",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if !assumeYes {
-				ios, err := f.Streams()
-				if err != nil {
-					return err
-				}
-				if !ios.IsStdinTTY() {
-					return errors.New("confirmation required when stdin is not a TTY (use --yes)")
-				}
-				_, _ = fmt.Fprintf(ios.ErrOut, "Install plugins: %s? [y/N]: ", strings.Join(args, ", "))
-				reader := bufio.NewReader(ios.In)
-				answer, err := reader.ReadString('\n')
-				if err != nil && !errors.Is(err, bufio.ErrBufferFull) {
-					return err
-				}
-				answer = strings.ToLower(strings.TrimSpace(answer))
-				if answer != "y" && answer != "yes" {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Cancelled")
-					return cmdutil.ErrSilent
-				}
-			}
-
-			client, err := shared.JenkinsClient(cmd, f)
+			ok, err := confirmInstall(f, args, assumeYes)
 			if err != nil {
 				return err
 			}
-
-			payload, err := buildInstallXML(args)
-			if err != nil {
-				return err
+			target := strings.Join(args, ",")
+			if !ok {
+				_ = shared.PrintAction(cmd, shared.ActionResult{Action: "install", Target: target, Status: shared.ActionDeclined}, "Cancelled")
+				return cmdutil.ErrSilent
 			}
 
-			req := client.NewRequest().SetBody(payload).SetHeader("Content-Type", "text/xml")
-			resp, err := client.Do(req, http.MethodPost, "/pluginManager/installNecessaryPlugins", nil)
+			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
-			if resp.StatusCode() >= 300 {
-				return fmt.Errorf("install failed: %s", resp.Status())
+			if err := triggerInstall(client, args); err != nil {
+				return err
 			}
 
-			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Plugin installation triggered. Monitor Jenkins for progress.")
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "install", Target: target, Status: shared.ActionRequested}, "Plugin installation triggered. Monitor Jenkins for progress.")
 		},
 	}
 	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Do not prompt for confirmation")
 	return cmd
 }
 
+// confirmInstall asks before installing plugins unless assumeYes; without a
+// terminal it requires --yes.
+func confirmInstall(f *cmdutil.Factory, plugins []string, assumeYes bool) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	ios, err := f.Streams()
+	if err != nil {
+		return false, err
+	}
+	if !ios.GetNeverPrompt() && !ios.IsStdinTTY() {
+		return false, errors.New("confirmation required when stdin is not a TTY (use --yes)")
+	}
+	return cmdutil.ConfirmOrFail(ios, fmt.Sprintf("Install plugins: %s?", strings.Join(plugins, ", ")), "--yes")
+}
+
+// triggerInstall asks the update center to install plugins given as
+// name[@version].
+func triggerInstall(client shared.Doer, plugins []string) error {
+	payload, err := buildInstallXML(plugins)
+	if err != nil {
+		return err
+	}
+
+	req := client.NewRequest().SetBody(payload).SetHeader("Content-Type", "text/xml")
+	resp, err := client.Do(req, http.MethodPost, "/pluginManager/installNecessaryPlugins", nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() >= 300 {
+		return fmt.Errorf("install failed: %s", shared.ResponseStatus(resp))
+	}
+	return nil
+}
+
+// installPlugins confirms and triggers the installation of plugins for
+// commands that install as a side effect, such as verify --fix. It returns
+// false when the user declined.
+func installPlugins(f *cmdutil.Factory, client shared.Doer, plugins []string, assumeYes bool) (bool, error) {
+	ok, err := confirmInstall(f, plugins, assumeYes)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := triggerInstall(client, plugins); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 type installRequest struct {
 	XMLName xml.Name       `xml:"jenkins"`
 	Install []installEntry `xml:"install"`
@@ -214,11 +386,10 @@ func newPluginToggleCmd(f *cmdutil.Factory, enable bool) *cobra.Command {
 				return err
 			}
 			if resp.StatusCode() >= 300 {
-				return fmt.Errorf("%s failed: %s", verb, resp.Status())
+				return fmt.Errorf("%s failed: %s", verb, shared.ResponseStatus(resp))
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Plugin %s %sd\n", name, verb)
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: verb, Target: name, Status: shared.ActionDone}, "Plugin %s %sd", name, verb)
 		},
 	}
 	return cmd
@@ -0,0 +1,102 @@
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestPluginInstallNoInputRequiresYes(t *testing.T) {
+	ios, stdin, _, _ := iostreams.Test()
+	ios.SetStdinTTY(true)
+	ios.SetNeverPrompt(true)
+	stdin.WriteString("y\n")
+
+	f := &cmdutil.Factory{IOStreams: ios}
+	cmd := newPluginInstallCmd(f)
+	cmd.SetArgs([]string{"git"})
+	cmd.SetOut(ios.Out)
+	cmd.SetErr(ios.ErrOut)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	err := cmd.Execute()
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, 2, exitErr.Code)
+	require.Equal(t, "confirmation required; pass --yes or remove --no-input", exitErr.Msg)
+	require.Equal(t, "y\n", stdin.String())
+}
+
+func newPluginProblemsClient(t *testing.T) (*fakejenkins.Server, *cmdutil.Factory, *bytes.Buffer) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/pluginManager/api/json", map[string]any{
+		"plugins": []map[string]any{
+			{"shortName": "git-client", "version": "4.0", "enabled": false},
+			{"shortName": "git", "version": "5.0", "enabled": true, "hasUpdate": true, "dependencies": []map[string]any{
+				{"shortName": "git-client", "version": "4.0"},
+			}},
+			{"shortName": "github", "version": "1.3", "enabled": true, "dependencies": []map[string]any{
+				{"shortName": "git-client", "version": "4.0"},
+				{"shortName": "theme", "version": "1.0", "optional": true},
+			}},
+			{"shortName": "theme", "version": "1.0", "enabled": false},
+			{"shortName": "old-ui", "version": "0.1", "enabled": false, "dependencies": []map[string]any{
+				{"shortName": "theme", "version": "1.0"},
+			}},
+		},
+	})
+	f, stdout, _ := fakejenkins.Factory(client)
+	return server, f, stdout
+}
+
+func TestPluginListProblems(t *testing.T) {
+	server, f, stdout := newPluginProblemsClient(t)
+
+	err := runPluginCmd(newPluginListCmd(f), stdout, "--problems")
+	require.NoError(t, err)
+	require.Equal(t, "git-client\t4.0\tdisabled\tdisabled but required by git, github\n", stdout.String())
+	require.Equal(t, "2", server.LastRequest(http.MethodGet, "/pluginManager/api/json").Query.Get("depth"))
+
+	stdout.Reset()
+	err = runPluginCmd(newPluginListCmd(f), stdout, "--problems", "--json")
+	require.NoError(t, err)
+	var rows []pluginRow
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &rows))
+	require.Len(t, rows, 1)
+	require.Equal(t, []pluginProblem{{Kind: problemDisabledDependency, RequiredBy: []string{"git", "github"}}}, rows[0].Problems)
+}
+
+func TestPluginListFilters(t *testing.T) {
+	server, f, stdout := newPluginProblemsClient(t)
+
+	err := runPluginCmd(newPluginListCmd(f), stdout, "--filter", "name~git", "--enabled")
+	require.NoError(t, err)
+	require.Equal(t, "git\t5.0\tenabled\tupdate available\ngithub\t1.3\tenabled\n", stdout.String())
+	req := server.LastRequest(http.MethodGet, "/pluginManager/api/json")
+	require.Equal(t, "1", req.Query.Get("depth"), "plain filters must not need the dependency graph")
+
+	stdout.Reset()
+	err = runPluginCmd(newPluginListCmd(f), stdout, "--has-update", "--json")
+	require.NoError(t, err)
+	var rows []pluginRow
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &rows))
+	require.Len(t, rows, 1)
+	require.Equal(t, "git", rows[0].Name)
+	require.Equal(t, []pluginDependency{{ShortName: "git-client", Version: "4.0"}}, rows[0].Dependencies)
+	require.Equal(t, pluginGraphTree, server.LastRequest(http.MethodGet, "/pluginManager/api/json").Query.Get("tree"))
+
+	stdout.Reset()
+	err = runPluginCmd(newPluginListCmd(f), stdout, "--disabled", "--filter", "name^old")
+	require.NoError(t, err)
+	require.Equal(t, "old-ui\t0.1\tdisabled\n", stdout.String())
+}
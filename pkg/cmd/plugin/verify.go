@@ -0,0 +1,359 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// driftExitCode is returned by `plugin verify` while the installed plugins
+// differ from the allow-list, including after --fix only requested the
+// installation.
+const driftExitCode = 18
+
+// allowedPlugin is one entry of a plugin allow-list file.
+type allowedPlugin struct {
+	Name       string
+	Constraint versionConstraint
+}
+
+// allowListYAML is the YAML allow-list form; version takes an exact version
+// or a constraint such as ">=2.3 <3".
+type allowListYAML struct {
+	Plugins []struct {
+		Name    string `yaml:"name"`
+		Version string `yaml:"version"`
+	} `yaml:"plugins"`
+}
+
+// parseAllowList reads a plugins.txt (one name:version per line, # starts a
+// comment) or, for .yaml/.yml files or content starting with "plugins:", the
+// YAML form. A missing version allows any.
+func parseAllowList(name string, data []byte) ([]allowedPlugin, error) {
+	ext := strings.ToLower(filepath.Ext(name))
+	if ext == ".yaml" || ext == ".yml" || bytes.HasPrefix(bytes.TrimSpace(data), []byte("plugins:")) {
+		return parseAllowListYAML(data)
+	}
+
+	var plugins []allowedPlugin
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		pluginName, version, _ := strings.Cut(text, ":")
+		entry, err := newAllowedPlugin(pluginName, version)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		plugins = append(plugins, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return plugins, checkAllowListNames(plugins)
+}
+
+func parseAllowListYAML(data []byte) ([]allowedPlugin, error) {
+	var doc allowListYAML
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse plugin list: %w", err)
+	}
+	plugins := make([]allowedPlugin, 0, len(doc.Plugins))
+	for i, p := range doc.Plugins {
+		entry, err := newAllowedPlugin(p.Name, p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("plugins[%d]: %w", i, err)
+		}
+		plugins = append(plugins, entry)
+	}
+	return plugins, checkAllowListNames(plugins)
+}
+
+func newAllowedPlugin(name, version string) (allowedPlugin, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return allowedPlugin{}, fmt.Errorf("plugin name is required")
+	}
+	constraint, err := parseVersionConstraint(version)
+	if err != nil {
+		return allowedPlugin{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return allowedPlugin{Name: name, Constraint: constraint}, nil
+}
+
+func checkAllowListNames(plugins []allowedPlugin) error {
+	seen := make(map[string]bool, len(plugins))
+	for _, p := range plugins {
+		if seen[p.Name] {
+			return fmt.Errorf("plugin %s is listed more than once", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return nil
+}
+
+// pluginFinding is one discrepancy between the allow-list and the
+// controller. Direction is "older" or "newer" for a version mismatch.
+type pluginFinding struct {
+	Name      string `json:"name"`
+	Expected  string `json:"expected,omitempty"`
+	Installed string `json:"installed,omitempty"`
+	Direction string `json:"direction,omitempty"`
+}
+
+type pluginVerifyOutput struct {
+	SchemaVersion string          `json:"schemaVersion"`
+	File          string          `json:"file"`
+	Missing       []pluginFinding `json:"missing"`
+	Mismatched    []pluginFinding `json:"mismatched"`
+	Extras        []pluginFinding `json:"extras"`
+	Disabled      []pluginFinding `json:"disabled"`
+	// Ignored names the categories suppressed by --ignore-* flags; they are
+	// left empty.
+	Ignored []string `json:"ignored,omitempty"`
+	// Installing lists the name@version specs --fix sent to the update
+	// center.
+	Installing []string `json:"installing,omitempty"`
+}
+
+func (o pluginVerifyOutput) discrepancies() int {
+	return len(o.Missing) + len(o.Mismatched) + len(o.Extras) + len(o.Disabled)
+}
+
+type pluginVerifyOptions struct {
+	IgnoreExtras     bool
+	IgnoreMismatches bool
+	IgnoreDisabled   bool
+}
+
+func newPluginVerifyCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		file      string
+		opts      pluginVerifyOptions
+		fix       bool
+		assumeYes bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify --file <plugins.txt>",
+		Short: "Compare installed plugins with an allow-list file",
+		Long: `Compare the installed plugins with an allow-list and report plugins that are
+missing, at a version the list does not allow (older or newer), installed but
+not listed, or listed but disabled.
+
+The file is either plugins.txt (one name:version per line, # comments, a bare
+name allows any version) or YAML:
+
+  plugins:
+    - name: git
+      version: ">=5.2 <6"
+    - name: workflow-aggregator
+
+YAML versions accept an exact version or constraints joined by spaces:
+=, >, >=, <, <=, ~1.4 (same minor), and ^2.1 (same major).
+
+--fix installs the missing plugins and the mismatched ones pinned to an exact
+version, or with no upper bound, after confirmation. Jenkins installs them in
+the background, so the command still exits 18 until a later verify passes.`,
+		Example: `  # Fail when the controller drifts from the blessed set
+  jk plugin verify --file plugins.txt
+
+  # Only care about required plugins
+  jk plugin verify --file plugins.yaml --ignore-extras --json
+
+  # Install what is missing or pinned to another version
+  jk plugin verify --file plugins.txt --ignore-extras --fix`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(file) == "" {
+				return shared.NewExitError(2, "--file is required")
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return shared.NewExitError(2, fmt.Sprintf("read plugin list: %v", err))
+			}
+			allowed, err := parseAllowList(file, data)
+			if err != nil {
+				return shared.NewExitError(2, fmt.Sprintf("%s: %v", file, err))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			installed, err := listPlugins(client, pluginListOptions{})
+			if err != nil {
+				return err
+			}
+
+			output := verifyPlugins(allowed, installed, opts)
+			output.File = file
+
+			var specs, skipped []string
+			if fix {
+				specs, skipped = fixSpecs(allowed, output)
+				for _, name := range skipped {
+					cmdutil.Warnf(cmd, "not fixing %s; its version range has an upper bound, install a version inside it by hand", name)
+				}
+				if len(specs) > 0 {
+					triggered, err := installPlugins(f, client, specs, assumeYes)
+					if err != nil {
+						return err
+					}
+					if triggered {
+						output.Installing = specs
+					}
+				}
+			}
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				renderPluginVerify(cmd, output)
+				return nil
+			}); err != nil {
+				return err
+			}
+
+			if output.discrepancies() == 0 {
+				return nil
+			}
+			if len(output.Installing) > 0 {
+				return shared.NewExitError(driftExitCode, "installation requested; run jk plugin verify again once Jenkins has installed the plugins")
+			}
+			return shared.NewExitError(driftExitCode, "")
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Allow-list file (plugins.txt name:version lines, or YAML)")
+	cmd.Flags().BoolVar(&opts.IgnoreExtras, "ignore-extras", false, "Do not report installed plugins missing from the file")
+	cmd.Flags().BoolVar(&opts.IgnoreMismatches, "ignore-mismatches", false, "Do not report version mismatches")
+	cmd.Flags().BoolVar(&opts.IgnoreDisabled, "ignore-disabled", false, "Do not report listed plugins that are disabled")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Install missing and mismatched plugins (asks for confirmation)")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "With --fix, do not prompt for confirmation")
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		2:             "The file is invalid",
+		driftExitCode: "Installed plugins differ from the file, even when --fix requested their installation",
+	})
+	return cmd
+}
+
+// verifyPlugins compares the allow-list with the installed plugins. Each
+// category is sorted by name.
+func verifyPlugins(allowed []allowedPlugin, installed []pluginRow, opts pluginVerifyOptions) pluginVerifyOutput {
+	output := pluginVerifyOutput{
+		SchemaVersion: "1.0",
+		Missing:       []pluginFinding{},
+		Mismatched:    []pluginFinding{},
+		Extras:        []pluginFinding{},
+		Disabled:      []pluginFinding{},
+	}
+	byName := make(map[string]pluginRow, len(installed))
+	for _, row := range installed {
+		byName[row.Name] = row
+	}
+	listed := make(map[string]bool, len(allowed))
+
+	for _, want := range allowed {
+		listed[want.Name] = true
+		row, ok := byName[want.Name]
+		if !ok {
+			output.Missing = append(output.Missing, pluginFinding{Name: want.Name, Expected: want.Constraint.Raw})
+			continue
+		}
+		if ok, direction := want.Constraint.check(row.Version); !ok && !opts.IgnoreMismatches {
+			output.Mismatched = append(output.Mismatched, pluginFinding{Name: want.Name, Expected: want.Constraint.Raw, Installed: row.Version, Direction: direction})
+		}
+		if !row.Enabled && !opts.IgnoreDisabled {
+			output.Disabled = append(output.Disabled, pluginFinding{Name: want.Name, Expected: want.Constraint.Raw, Installed: row.Version})
+		}
+	}
+	if !opts.IgnoreExtras {
+		for _, row := range installed {
+			if !listed[row.Name] {
+				output.Extras = append(output.Extras, pluginFinding{Name: row.Name, Installed: row.Version})
+			}
+		}
+	}
+
+	for _, findings := range [][]pluginFinding{output.Missing, output.Mismatched, output.Extras, output.Disabled} {
+		sort.Slice(findings, func(i, j int) bool { return findings[i].Name < findings[j].Name })
+	}
+	if opts.IgnoreExtras {
+		output.Ignored = append(output.Ignored, "extras")
+	}
+	if opts.IgnoreMismatches {
+		output.Ignored = append(output.Ignored, "mismatched")
+	}
+	if opts.IgnoreDisabled {
+		output.Ignored = append(output.Ignored, "disabled")
+	}
+	return output
+}
+
+// fixSpecs returns the install specs --fix can derive: the pinned version
+// when there is one, otherwise the latest release unless a range caps it.
+// Names whose range has an upper bound are returned as skipped.
+func fixSpecs(allowed []allowedPlugin, output pluginVerifyOutput) (specs, skipped []string) {
+	constraints := make(map[string]versionConstraint, len(allowed))
+	for _, p := range allowed {
+		constraints[p.Name] = p.Constraint
+	}
+	for _, finding := range append(append([]pluginFinding{}, output.Missing...), output.Mismatched...) {
+		c := constraints[finding.Name]
+		if version, ok := c.exact(); ok {
+			specs = append(specs, finding.Name+"@"+version)
+			continue
+		}
+		if c.hasUpperBound() {
+			skipped = append(skipped, finding.Name)
+			continue
+		}
+		specs = append(specs, finding.Name+"@latest")
+	}
+	return specs, skipped
+}
+
+func renderPluginVerify(cmd *cobra.Command, output pluginVerifyOutput) {
+	w := cmd.OutOrStdout()
+	if output.discrepancies() == 0 {
+		_, _ = fmt.Fprintf(w, "Installed plugins match %s\n", output.File)
+		return
+	}
+	for _, f := range output.Missing {
+		line := "missing\t" + f.Name
+		if f.Expected != "" {
+			line += "\t" + f.Expected
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	for _, f := range output.Mismatched {
+		_, _ = fmt.Fprintf(w, "mismatch\t%s\t%s installed (%s than %s)\n", f.Name, f.Installed, f.Direction, f.Expected)
+	}
+	for _, f := range output.Disabled {
+		_, _ = fmt.Fprintf(w, "disabled\t%s\t%s\n", f.Name, f.Installed)
+	}
+	for _, f := range output.Extras {
+		_, _ = fmt.Fprintf(w, "extra\t%s\t%s\n", f.Name, f.Installed)
+	}
+	_, _ = fmt.Fprintf(w, "%d missing, %d mismatched, %d disabled, %d extra\n", len(output.Missing), len(output.Mismatched), len(output.Disabled), len(output.Extras))
+	if len(output.Installing) > 0 {
+		_, _ = fmt.Fprintf(w, "Installing %s. Monitor Jenkins for progress.\n", strings.Join(output.Installing, ", "))
+	}
+}
@@ -0,0 +1,197 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0", "1.0", 0},
+		{"1.10", "1.9", 1},
+		{"2.0", "2.0.1", -1},
+		{"2.0-beta", "2.0", -1},
+		{"2.0-rc1", "2.0-rc2", -1},
+		{"5.2.1", "5.2", 1},
+		{"1055.v7c5a_fe0b_b_90b", "1054.v5a_5e4b_8c7d70", 1},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, compareVersions(tc.a, tc.b), "%s vs %s", tc.a, tc.b)
+		require.Equal(t, -tc.want, compareVersions(tc.b, tc.a), "%s vs %s", tc.b, tc.a)
+	}
+}
+
+func TestVersionConstraint(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		ok         bool
+		direction  string
+	}{
+		{"", "1.0", true, ""},
+		{"latest", "1.0", true, ""},
+		{"5.2", "5.2", true, ""},
+		{"5.2", "5.1", false, "older"},
+		{"=5.2", "5.3", false, "newer"},
+		{">=5.2 <6", "5.9", true, ""},
+		{">=5.2 <6", "6.0", false, "newer"},
+		{">=5.2, <6", "5.1", false, "older"},
+		{">5.2", "5.2", false, "older"},
+		{"<=3", "3.0.1", false, "newer"},
+		{"~1.4", "1.4.7", true, ""},
+		{"~1.4", "1.5", false, "newer"},
+		{"^2.1", "2.9", true, ""},
+		{"^2.1", "3.0", false, "newer"},
+		{"^2.1", "2.0", false, "older"},
+	}
+	for _, tc := range cases {
+		c, err := parseVersionConstraint(tc.constraint)
+		require.NoError(t, err, tc.constraint)
+		ok, direction := c.check(tc.version)
+		require.Equal(t, tc.ok, ok, "%s against %q", tc.version, tc.constraint)
+		require.Equal(t, tc.direction, direction, "%s against %q", tc.version, tc.constraint)
+	}
+
+	_, err := parseVersionConstraint(">=")
+	require.Error(t, err)
+	_, err = parseVersionConstraint("^x.1")
+	require.Error(t, err)
+}
+
+func TestParseAllowList(t *testing.T) {
+	plugins, err := parseAllowList("plugins.txt", []byte(`# blessed set
+git:5.2.1
+workflow-aggregator   # any version
+
+credentials:1311.vcf0a_900b_37c2
+`))
+	require.NoError(t, err)
+	require.Len(t, plugins, 3)
+	require.Equal(t, "git", plugins[0].Name)
+	version, ok := plugins[0].Constraint.exact()
+	require.True(t, ok)
+	require.Equal(t, "5.2.1", version)
+	require.Equal(t, "workflow-aggregator", plugins[1].Name)
+	require.Empty(t, plugins[1].Constraint.Bounds)
+
+	plugins, err = parseAllowList("plugins.yaml", []byte(`plugins:
+  - name: git
+    version: ">=5.2 <6"
+  - name: matrix-auth
+`))
+	require.NoError(t, err)
+	require.Len(t, plugins, 2)
+	require.True(t, plugins[0].Constraint.hasUpperBound())
+	require.Equal(t, ">=5.2 <6", plugins[0].Constraint.Raw)
+
+	_, err = parseAllowList("plugins.txt", []byte("git:1\ngit:2\n"))
+	require.ErrorContains(t, err, "listed more than once")
+
+	_, err = parseAllowList("plugins.yaml", []byte("plugins:\n  - name: git\n    pinned: true\n"))
+	require.Error(t, err)
+}
+
+func TestVerifyPluginsCategories(t *testing.T) {
+	allowed, err := parseAllowList("plugins.txt", []byte("git:5.2\ngit-client:4.0\ngithub\nmatrix-auth:3.1\n"))
+	require.NoError(t, err)
+	installed := []pluginRow{
+		{Name: "git", Version: "5.0", Enabled: true},
+		{Name: "git-client", Version: "4.0", Enabled: false},
+		{Name: "github", Version: "1.3", Enabled: true},
+		{Name: "theme", Version: "1.0", Enabled: true},
+	}
+
+	output := verifyPlugins(allowed, installed, pluginVerifyOptions{})
+	require.Equal(t, []pluginFinding{{Name: "matrix-auth", Expected: "3.1"}}, output.Missing)
+	require.Equal(t, []pluginFinding{{Name: "git", Expected: "5.2", Installed: "5.0", Direction: "older"}}, output.Mismatched)
+	require.Equal(t, []pluginFinding{{Name: "git-client", Expected: "4.0", Installed: "4.0"}}, output.Disabled)
+	require.Equal(t, []pluginFinding{{Name: "theme", Installed: "1.0"}}, output.Extras)
+	require.Equal(t, 4, output.discrepancies())
+
+	output = verifyPlugins(allowed, installed, pluginVerifyOptions{IgnoreExtras: true, IgnoreMismatches: true, IgnoreDisabled: true})
+	require.Empty(t, output.Mismatched)
+	require.Empty(t, output.Extras)
+	require.Empty(t, output.Disabled)
+	require.Len(t, output.Missing, 1)
+	require.Equal(t, []string{"extras", "mismatched", "disabled"}, output.Ignored)
+
+	specs, skipped := fixSpecs(allowed, output)
+	require.Equal(t, []string{"matrix-auth@3.1"}, specs)
+	require.Empty(t, skipped)
+}
+
+func TestFixSpecsSkipsCappedRanges(t *testing.T) {
+	allowed, err := parseAllowList("plugins.yaml", []byte(`plugins:
+  - name: git
+    version: "^5.2"
+  - name: github
+    version: ">=1.3"
+`))
+	require.NoError(t, err)
+	output := verifyPlugins(allowed, nil, pluginVerifyOptions{})
+	specs, skipped := fixSpecs(allowed, output)
+	require.Equal(t, []string{"github@latest"}, specs)
+	require.Equal(t, []string{"git"}, skipped)
+}
+
+func TestPluginVerifyCommand(t *testing.T) {
+	_, f, stdout := newPluginProblemsClient(t)
+	dir := t.TempDir()
+	file := filepath.Join(dir, "plugins.txt")
+	require.NoError(t, os.WriteFile(file, []byte("git:5.0\ngithub:1.3\ngit-client\ntheme:1.0\nold-ui\n"), 0o600))
+
+	err := runPluginCmd(newPluginVerifyCmd(f), stdout, "--file", file, "--ignore-disabled")
+	require.NoError(t, err)
+	require.Equal(t, "Installed plugins match "+file+"\n", stdout.String())
+
+	stdout.Reset()
+	require.NoError(t, os.WriteFile(file, []byte("git:5.2\ngithub\nmatrix-auth\n"), 0o600))
+	err = runPluginCmd(newPluginVerifyCmd(f), stdout, "--file", file, "--json")
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, driftExitCode, exitErr.Code)
+
+	var output pluginVerifyOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Equal(t, []pluginFinding{{Name: "matrix-auth"}}, output.Missing)
+	require.Equal(t, []pluginFinding{{Name: "git", Expected: "5.2", Installed: "5.0", Direction: "older"}}, output.Mismatched)
+	require.Len(t, output.Extras, 3)
+	require.Empty(t, output.Disabled)
+}
+
+func TestPluginVerifyRequiresFile(t *testing.T) {
+	_, f, stdout := newPluginProblemsClient(t)
+	err := runPluginCmd(newPluginVerifyCmd(f), stdout)
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	require.Equal(t, 2, exitErr.Code)
+}
+
+func TestPluginVerifyFixKeepsFailingUntilInstalled(t *testing.T) {
+	server, f, stdout := newPluginProblemsClient(t)
+	server.Handle(http.MethodPost, "/pluginManager/installNecessaryPlugins", http.StatusOK, "")
+	file := filepath.Join(t.TempDir(), "plugins.txt")
+	require.NoError(t, os.WriteFile(file, []byte("git:5.2\nmatrix-auth:3.1\n"), 0o600))
+
+	err := runPluginCmd(newPluginVerifyCmd(f), stdout, "--file", file, "--ignore-extras", "--fix", "--yes", "--json")
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr), "expected exit error, got %v", err)
+	require.Equal(t, driftExitCode, exitErr.Code)
+	require.Contains(t, exitErr.Msg, "installation requested")
+
+	var output pluginVerifyOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Equal(t, []string{"matrix-auth@3.1", "git@5.2"}, output.Installing)
+	require.Len(t, server.RequestsTo(http.MethodPost, "/pluginManager/installNecessaryPlugins"), 1)
+}
@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// compareVersions orders two plugin versions, returning -1, 0, or 1. Versions
+// are split on dots and dashes; numeric parts compare as numbers and the
+// rest as text, with a number ranking above text so 2.0 > 2.0-beta. A
+// version that runs out of parts first is older, except that a trailing
+// pre-release tag such as -rc1 ranks below the bare release.
+func compareVersions(a, b string) int {
+	pa, pb := splitVersion(a), splitVersion(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		switch {
+		case i >= len(pa):
+			return missingPartOrder(pb[i])
+		case i >= len(pb):
+			return -missingPartOrder(pa[i])
+		}
+		if c := comparePart(pa[i], pb[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func splitVersion(v string) []string {
+	return strings.FieldsFunc(strings.TrimSpace(v), func(r rune) bool {
+		return r == '.' || r == '-' || r == '+'
+	})
+}
+
+// missingPartOrder compares a missing part with part: a version without a
+// further number is older, one without a further tag is newer.
+func missingPartOrder(part string) int {
+	if _, err := strconv.ParseUint(part, 10, 64); err == nil {
+		return -1
+	}
+	return 1
+}
+
+func comparePart(a, b string) int {
+	na, errA := strconv.ParseUint(a, 10, 64)
+	nb, errB := strconv.ParseUint(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		switch {
+		case na < nb:
+			return -1
+		case na > nb:
+			return 1
+		}
+		return 0
+	case errA == nil:
+		return 1
+	case errB == nil:
+		return -1
+	}
+	return strings.Compare(a, b)
+}
+
+// versionBound is one comparison of a constraint, such as >=2.3.
+type versionBound struct {
+	Op      string
+	Version string
+}
+
+// versionConstraint is an allow-list version: an exact version or bounds
+// that must all hold, written like ">=2.3 <3", "~1.4" (>=1.4, <1.5), or
+// "^2.1" (>=2.1, <3). An empty constraint accepts any version.
+type versionConstraint struct {
+	Raw    string
+	Bounds []versionBound
+}
+
+func parseVersionConstraint(raw string) (versionConstraint, error) {
+	c := versionConstraint{Raw: strings.TrimSpace(raw)}
+	if c.Raw == "" || c.Raw == "*" || strings.EqualFold(c.Raw, "latest") {
+		c.Raw = ""
+		return c, nil
+	}
+	for _, term := range strings.FieldsFunc(c.Raw, func(r rune) bool { return r == ' ' || r == ',' }) {
+		op := ""
+		for _, candidate := range []string{">=", "<=", "==", ">", "<", "=", "~", "^"} {
+			if strings.HasPrefix(term, candidate) {
+				op = candidate
+				break
+			}
+		}
+		version := strings.TrimSpace(strings.TrimPrefix(term, op))
+		if version == "" {
+			return c, fmt.Errorf("invalid version constraint %q", raw)
+		}
+		switch op {
+		case "", "=", "==":
+			c.Bounds = append(c.Bounds, versionBound{"=", version})
+		case "~", "^":
+			upper, err := constraintCeiling(op, version)
+			if err != nil {
+				return c, fmt.Errorf("invalid version constraint %q: %w", raw, err)
+			}
+			c.Bounds = append(c.Bounds, versionBound{">=", version}, versionBound{"<", upper})
+		default:
+			c.Bounds = append(c.Bounds, versionBound{op, version})
+		}
+	}
+	return c, nil
+}
+
+// constraintCeiling is the exclusive upper bound of ~v (next minor) or ^v
+// (next major).
+func constraintCeiling(op, version string) (string, error) {
+	parts := strings.Split(version, ".")
+	index := 0
+	if op == "~" && len(parts) > 1 {
+		index = 1
+	}
+	n, err := strconv.ParseUint(parts[index], 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("%s needs a numeric version, got %q", op, version)
+	}
+	return strings.Join(append(parts[:index:index], strconv.FormatUint(n+1, 10)), "."), nil
+}
+
+// exact returns the pinned version of an "=v" constraint.
+func (c versionConstraint) exact() (string, bool) {
+	if len(c.Bounds) == 1 && c.Bounds[0].Op == "=" {
+		return c.Bounds[0].Version, true
+	}
+	return "", false
+}
+
+// hasUpperBound reports whether some newer version would violate c.
+func (c versionConstraint) hasUpperBound() bool {
+	for _, b := range c.Bounds {
+		if b.Op == "=" || b.Op == "<" || b.Op == "<=" {
+			return true
+		}
+	}
+	return false
+}
+
+// check reports whether version satisfies c and, when it does not, whether
+// it is "older" or "newer" than allowed.
+func (c versionConstraint) check(version string) (bool, string) {
+	for _, b := range c.Bounds {
+		cmp := compareVersions(version, b.Version)
+		ok := true
+		switch b.Op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if ok {
+			continue
+		}
+		if cmp < 0 || (cmp == 0 && b.Op == ">") {
+			return false, "older"
+		}
+		return false, "newer"
+	}
+	return true, ""
+}
@@ -0,0 +1,59 @@
+package queue
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+)
+
+// queueParamsTree is queueTree plus build parameters. It is only requested
+// with --with-params, since it grows every item of a large queue.
+const queueParamsTree = "items[id,task[name,url],why,inQueueSince,actions[parameters[name,value]]]"
+
+// queueAction is the part of a queue item's actions that carries the
+// ParametersAction.
+type queueAction struct {
+	Parameters []struct {
+		Name  string `json:"name"`
+		Value any    `json:"value"`
+	} `json:"parameters"`
+}
+
+type queueParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// queueParameters flattens the ParametersAction of a queue item in Jenkins'
+// order. Values of the parameters redactor considers secrets are redacted;
+// Jenkins itself omits the value of password parameters.
+func queueParameters(actions []queueAction, redactor *filter.Redactor) []queueParameter {
+	var params []queueParameter
+	for _, action := range actions {
+		for _, param := range action.Parameters {
+			if strings.TrimSpace(param.Name) == "" {
+				continue
+			}
+			value := ""
+			switch {
+			case redactor.IsSecret(param.Name):
+				value = filter.RedactedValue
+			case param.Value != nil:
+				value = fmt.Sprint(param.Value)
+			}
+			params = append(params, queueParameter{Name: param.Name, Value: value})
+		}
+	}
+	return params
+}
+
+// formatQueueParameters renders parameters as space-separated KEY=value
+// pairs.
+func formatQueueParameters(params []queueParameter) string {
+	pairs := make([]string, len(params))
+	for i, param := range params {
+		pairs[i] = param.Name + "=" + param.Value
+	}
+	return strings.Join(pairs, " ")
+}
@@ -1,26 +1,46 @@
 package queue
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-resty/resty/v2"
 	"github.com/spf13/cobra"
 
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
+const queueTree = "items[id,task[name,url],why,inQueueSince,blocked,buildable]"
+
 type queueListResponse struct {
 	Items []queueItem `json:"items"`
 }
 
 type queueItem struct {
-	ID           int64        `json:"id"`
-	Why          string       `json:"why"`
+	ID  int64  `json:"id"`
+	Why string `json:"why"`
+	// InQueueSince is Jenkins' raw epoch milliseconds.
+	//
+	// Deprecated: kept for one release; consumers should read QueuedAt.
 	InQueueSince int64        `json:"inQueueSince"`
+	QueuedAt     string       `json:"queuedAt,omitempty"`
+	WaitMs       int64        `json:"waitMs,omitempty"`
+	Blocked      bool         `json:"blocked"`
+	Buildable    bool         `json:"buildable"`
 	Task         queueTaskRef `json:"task"`
+	// Parameters is only fetched with --with-params.
+	Parameters []queueParameter `json:"parameters,omitempty"`
+	// Actions is Jenkins' raw action list; it is folded into Parameters
+	// and never printed.
+	Actions []queueAction `json:"actions,omitempty"`
 }
 
 type queueTaskRef struct {
@@ -28,28 +48,65 @@ type queueTaskRef struct {
 	URL  string `json:"url"`
 }
 
+const queueItemTree = "id,url,why,inQueueSince,blocked,buildable,stuck,cancelled,task[name,url],executable[number,url],actions[parameters[name,value]]"
+
+type queueItemDetail struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+	Why string `json:"why,omitempty"`
+	// InQueueSince is Jenkins' raw epoch milliseconds.
+	//
+	// Deprecated: kept for one release; consumers should read QueuedAt.
+	InQueueSince int64  `json:"inQueueSince,omitempty"`
+	QueuedAt     string `json:"queuedAt,omitempty"`
+	// WaitMs is how long the item has waited; it is only set while the item
+	// is still queued.
+	WaitMs     int64            `json:"waitMs,omitempty"`
+	Blocked    bool             `json:"blocked"`
+	Buildable  bool             `json:"buildable"`
+	Stuck      bool             `json:"stuck"`
+	Cancelled  bool             `json:"cancelled,omitempty"`
+	Task       queueTaskRef     `json:"task"`
+	Executable *queueRunRef     `json:"executable,omitempty"`
+	Parameters []queueParameter `json:"parameters,omitempty"`
+	// Actions is folded into Parameters and never printed.
+	Actions []queueAction `json:"actions,omitempty"`
+}
+
+type queueRunRef struct {
+	Number int64  `json:"number"`
+	URL    string `json:"url"`
+}
+
 func NewCmdQueue(f *cmdutil.Factory) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "queue",
 		Short: "Inspect the build queue",
 	}
 
-	cmd.AddCommand(newQueueListCmd(f), newQueueCancelCmd(f))
+	cmd.AddCommand(newQueueListCmd(f), newQueueViewCmd(f), newQueueWhyCmd(f), newQueueCancelCmd(f), newQueueWaitCmd(f))
 	return cmd
 }
 
 func newQueueListCmd(f *cmdutil.Factory) *cobra.Command {
-	return &cobra.Command{
+	var withParams bool
+
+	cmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List queued items",
+		Example: `  # Which deployment is waiting, and with what parameters?
+  jk queue ls --with-params`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
-			var resp queueListResponse
-			_, err = client.Do(client.NewRequest().SetQueryParam("tree", "items[id,task[name,url],why,inQueueSince]"), http.MethodGet, "/queue/api/json", &resp)
+			resp, err := fetchQueue(cmd.Context(), client, withParams, redactor)
 			if err != nil {
 				return err
 			}
@@ -59,14 +116,86 @@ func newQueueListCmd(f *cmdutil.Factory) *cobra.Command {
 					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Queue is empty")
 					return nil
 				}
+				stamp := shared.TimeFormatter(cmd, f)
 				for _, item := range resp.Items {
-					wait := time.Since(time.UnixMilli(item.InQueueSince))
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "#%d\t%s\twaiting %s\t%s\n", item.ID, item.Task.Name, wait.Truncate(time.Second), item.Why)
+					line := fmt.Sprintf("#%d\t%s\tqueued %s\t%s", item.ID, item.Task.Name, stamp(item.QueuedAt), item.Why)
+					if len(item.Parameters) > 0 {
+						line += "\t" + formatQueueParameters(item.Parameters)
+					}
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), line)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&withParams, "with-params", false, "Show each item's build parameters (secret-looking values redacted)")
+	return cmd
+}
+
+func newQueueViewCmd(f *cmdutil.Factory) *cobra.Command {
+	var urlOnly bool
+
+	cmd := &cobra.Command{
+		Use:   "view <id>",
+		Short: "View a queued item",
+		Example: `  # Why is this item still waiting?
+  jk queue view 1357
+
+  # Open the queue item in a browser
+  open "$(jk queue view 1357 --url-only)"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil || id <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid queue id %q", args[0]))
+			}
+
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			item, err := fetchQueueItem(cmd.Context(), client, id, redactor)
+			if err != nil {
+				return err
+			}
+			if urlOnly {
+				return shared.PrintURLs(cmd, item.URL)
+			}
+
+			return shared.PrintOutput(cmd, item, func() error {
+				w := cmd.OutOrStdout()
+				_, _ = fmt.Fprintf(w, "Queue item %s\n", shared.Hyperlink(f, item.URL, fmt.Sprintf("#%d", item.ID)))
+				_, _ = fmt.Fprintf(w, "Task: %s\n", shared.Hyperlink(f, item.Task.URL, item.Task.Name))
+				switch {
+				case item.Cancelled:
+					_, _ = fmt.Fprintln(w, "State: cancelled")
+				case item.Executable != nil:
+					_, _ = fmt.Fprintf(w, "State: started as %s\n", shared.Hyperlink(f, item.Executable.URL, fmt.Sprintf("#%d", item.Executable.Number)))
+				default:
+					if item.WaitMs > 0 {
+						_, _ = fmt.Fprintf(w, "Waiting: %s\n", waitString(item.WaitMs))
+					}
+					if item.Why != "" {
+						_, _ = fmt.Fprintf(w, "Why: %s\n", item.Why)
+					}
+				}
+				if len(item.Parameters) > 0 {
+					_, _ = fmt.Fprintf(w, "Parameters: %s\n", formatQueueParameters(item.Parameters))
 				}
+				_, _ = fmt.Fprintf(w, "URL: %s\n", shared.Hyperlink(f, item.URL, item.URL))
 				return nil
 			})
 		},
 	}
+
+	shared.AddURLOnlyFlag(cmd, &urlOnly)
+	return cmd
 }
 
 func newQueueCancelCmd(f *cmdutil.Factory) *cobra.Command {
@@ -90,11 +219,316 @@ func newQueueCancelCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 			if resp.StatusCode() >= 300 {
-				return fmt.Errorf("cancel failed: %s", resp.Status())
+				return fmt.Errorf("cancel failed: %s", shared.ResponseStatus(resp))
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancelled queue item %s\n", args[0])
-			return nil
+			return shared.PrintAction(cmd, shared.ActionResult{Action: "cancel", Target: args[0], Status: shared.ActionDone}, "Cancelled queue item %s", args[0])
 		},
 	}
 }
+
+type queueWaitOutput struct {
+	Condition string `json:"condition"`
+	JobPath   string `json:"jobPath,omitempty"`
+	ID        int64  `json:"id,omitempty"`
+	Satisfied bool   `json:"satisfied"`
+	TimedOut  bool   `json:"timedOut,omitempty"`
+	Remaining int    `json:"remaining"`
+	// Blocked counts the remaining items that cannot start yet: blocked by
+	// another build or resource, or still in their quiet period.
+	Blocked  int   `json:"blocked,omitempty"`
+	WaitedMs int64 `json:"waitedMs"`
+}
+
+func newQueueWaitCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		empty    bool
+		id       int64
+		jobPath  string
+		timeout  time.Duration
+		interval time.Duration
+		retry    shared.PollRetry
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait",
+		Short: "Block until the queue drains or an item leaves it",
+		Long: `Poll the build queue until a condition is met.
+
+--empty waits until no items remain (optionally only those for --job).
+--id waits until the given queue item has left the queue.
+
+Exits 0 when the condition is met and 7 when --timeout elapses first.`,
+		Example: `  # Wait for the queue to drain before maintenance
+  jk queue wait --empty --timeout 30m
+
+  # Wait for a specific queue item to start (or be cancelled)
+  jk queue wait --id 1357`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if empty == (id > 0) {
+				return shared.NewExitError(2, "specify exactly one of --empty or --id")
+			}
+			if jobPath != "" && !empty {
+				return shared.NewExitError(2, "--job can only be combined with --empty")
+			}
+			if interval <= 0 {
+				interval = 5 * time.Second
+			}
+			if err := retry.Validate(); err != nil {
+				return err
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			quiet := cmdutil.Quiet(cmd)
+			output := queueWaitOutput{Condition: "empty", JobPath: jobpath.Normalize(jobPath)}
+			if id > 0 {
+				output = queueWaitOutput{Condition: "id", ID: id}
+			}
+
+			start := time.Now()
+			var deadline time.Time
+			if timeout > 0 {
+				deadline = start.Add(timeout)
+			}
+			polled := false
+			timedOut := func() error {
+				output.TimedOut = true
+				output.WaitedMs = time.Since(start).Milliseconds()
+				msg := fmt.Sprintf("timed out after %s waiting for queue (%d item(s) remaining)", timeout, output.Remaining)
+				if output.Blocked > 0 {
+					msg = fmt.Sprintf("timed out after %s waiting for queue (%d item(s) remaining, %d blocked)", timeout, output.Remaining, output.Blocked)
+				}
+				if !polled {
+					msg = fmt.Sprintf("timed out after %s waiting for queue (no poll succeeded)", timeout)
+				}
+				if shared.WantsJSON(cmd) || shared.WantsYAML(cmd) {
+					if err := shared.PrintOutput(cmd, output, func() error { return nil }); err != nil {
+						return err
+					}
+				}
+				return shared.NewExitError(7, msg)
+			}
+			// sleep waits for d, but no longer than the deadline allows.
+			sleep := func(d time.Duration) error {
+				if !deadline.IsZero() {
+					d = min(d, time.Until(deadline))
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(d):
+					return nil
+				}
+			}
+			pastDeadline := func() bool {
+				return !deadline.IsZero() && !time.Now().Before(deadline)
+			}
+
+			// Polls reuse the last payload when Jenkins answers 304.
+			pollCtx := jenkins.WithConditionalRequests(ctx)
+			poller := retry.WithNotes(cmd, cmd.ErrOrStderr()).Poller()
+			for {
+				resp, httpResp, err := requestQueue(pollCtx, client, false, nil)
+				wait, err := poller.Observe(httpResp, err, "queue")
+				if err != nil {
+					return err
+				}
+				if wait > 0 {
+					if pastDeadline() {
+						return timedOut()
+					}
+					if err := sleep(wait); err != nil {
+						return err
+					}
+					continue
+				}
+
+				polled = true
+				remaining := matchingQueueItems(resp.Items, output.JobPath, id)
+				output.Remaining = len(remaining)
+				output.Blocked = countBlocked(remaining)
+				output.WaitedMs = time.Since(start).Milliseconds()
+
+				if len(remaining) == 0 {
+					output.Satisfied = true
+					return shared.PrintOutput(cmd, output, func() error {
+						if !quiet {
+							_, _ = fmt.Fprintln(cmd.OutOrStdout(), queueWaitDoneMessage(output))
+						}
+						return nil
+					})
+				}
+
+				if pastDeadline() {
+					return timedOut()
+				}
+
+				if !quiet {
+					_, _ = fmt.Fprintln(cmd.ErrOrStderr(), queueWaitStatusLine(remaining, time.Now()))
+				}
+
+				if err := sleep(interval); err != nil {
+					return err
+				}
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&empty, "empty", false, "Wait until no queued items remain")
+	cmd.Flags().Int64Var(&id, "id", 0, "Wait until the queue item with this id leaves the queue")
+	cmd.Flags().StringVar(&jobPath, "job", "", "With --empty, only consider items for this job path")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "Give up after this long (0 waits forever)")
+	cmd.Flags().DurationVar(&interval, "interval", 5*time.Second, "Polling interval")
+	shared.AddPollRetryFlags(cmd, &retry)
+	return cmd
+}
+
+// fetchQueue lists the queue; withParams adds each item's build parameters,
+// with secrets redacted by redactor.
+func fetchQueue(ctx context.Context, client shared.Doer, withParams bool, redactor *filter.Redactor) (*queueListResponse, error) {
+	resp, httpResp, err := requestQueue(ctx, client, withParams, redactor)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.CheckResponse(httpResp, "queue"); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// requestQueue fetches the queue and leaves judging the response to the
+// caller; the items are only filled in on success.
+func requestQueue(ctx context.Context, client shared.Doer, withParams bool, redactor *filter.Redactor) (*queueListResponse, *resty.Response, error) {
+	tree := queueTree
+	if withParams {
+		tree = queueParamsTree
+	}
+	req := client.NewRequest().SetQueryParam("tree", tree)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+
+	var resp queueListResponse
+	httpResp, err := client.Do(req, http.MethodGet, "/queue/api/json", &resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	for i := range resp.Items {
+		item := &resp.Items[i]
+		item.QueuedAt, item.WaitMs = queueTiming(item.InQueueSince, now)
+		item.Parameters, item.Actions = queueParameters(item.Actions, redactor), nil
+	}
+	return &resp, httpResp, nil
+}
+
+// queueTiming derives queuedAt and the wait so far from Jenkins'
+// inQueueSince epoch milliseconds.
+func queueTiming(inQueueSince int64, now time.Time) (string, int64) {
+	if inQueueSince <= 0 {
+		return "", 0
+	}
+	wait := now.Sub(time.UnixMilli(inQueueSince)).Milliseconds()
+	if wait < 0 {
+		wait = 0
+	}
+	return shared.FormatTimestamp(inQueueSince), wait
+}
+
+// fetchQueueItem loads a single queue item. Jenkins reports the item URL
+// relative to the controller root, so it is made absolute here.
+func fetchQueueItem(ctx context.Context, client *jenkins.Client, id int64, redactor *filter.Redactor) (*queueItemDetail, error) {
+	var item queueItemDetail
+	if err := shared.FetchQueueItem(ctx, client, shared.QueueItemLocation(id), queueItemTree, &item); err != nil {
+		return nil, err
+	}
+
+	if item.URL == "" || !strings.Contains(item.URL, "://") {
+		base := ""
+		if ctxDef := client.Context(); ctxDef != nil {
+			base = strings.TrimSuffix(ctxDef.URL, "/")
+		}
+		item.URL = fmt.Sprintf("%s/queue/item/%d/", base, id)
+	}
+	var wait int64
+	item.QueuedAt, wait = queueTiming(item.InQueueSince, time.Now())
+	if item.Executable == nil && !item.Cancelled {
+		item.WaitMs = wait
+	}
+	item.Parameters, item.Actions = queueParameters(item.Actions, redactor), nil
+	return &item, nil
+}
+
+// matchingQueueItems narrows the queue to the items a wait condition cares
+// about: a single id, items whose task URL decodes to jobPath, or everything.
+func matchingQueueItems(items []queueItem, jobPath string, id int64) []queueItem {
+	var out []queueItem
+	for _, item := range items {
+		switch {
+		case id > 0:
+			if item.ID != id {
+				continue
+			}
+		case jobPath != "":
+			if queueTaskPath(item) != jobPath {
+				continue
+			}
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// countBlocked counts the items that cannot start yet, so a wait that never
+// ends can say why.
+func countBlocked(items []queueItem) int {
+	n := 0
+	for _, item := range items {
+		if item.Blocked || !item.Buildable {
+			n++
+		}
+	}
+	return n
+}
+
+func queueWaitStatusLine(items []queueItem, now time.Time) string {
+	oldest := int64(0)
+	for _, item := range items {
+		if item.InQueueSince > 0 && (oldest == 0 || item.InQueueSince < oldest) {
+			oldest = item.InQueueSince
+		}
+	}
+	line := fmt.Sprintf("%d item(s) remaining", len(items))
+	if blocked := countBlocked(items); blocked > 0 {
+		line += fmt.Sprintf(" (%d blocked)", blocked)
+	}
+	if oldest > 0 {
+		line += fmt.Sprintf(", oldest waiting %s", waitString(now.Sub(time.UnixMilli(oldest)).Milliseconds()))
+	}
+	return line
+}
+
+// waitString renders a queue wait to the second.
+func waitString(ms int64) string {
+	return shared.DurationString(ms / 1000 * 1000)
+}
+
+func queueWaitDoneMessage(output queueWaitOutput) string {
+	if output.Condition == "id" {
+		return fmt.Sprintf("Queue item %d left the queue", output.ID)
+	}
+	if output.JobPath != "" {
+		return fmt.Sprintf("No queued items remain for %s", output.JobPath)
+	}
+	return "Queue is empty"
+}
@@ -0,0 +1,262 @@
+package queue
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/outputschema"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+func TestQueueListReportsQueuedAtAndWait(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	queued := time.Now().Add(-90 * time.Second).Truncate(time.Second)
+	server.HandleJSON(http.MethodGet, "/queue/api/json", map[string]any{
+		"items": []map[string]any{
+			{"id": 7, "why": "Waiting for next available executor", "inQueueSince": queued.UnixMilli(), "task": map[string]any{"name": "app"}},
+		},
+	})
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdQueue(f)
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"ls"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	require.NoError(t, cmd.Execute())
+
+	var items []queueItem
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &items))
+	require.Len(t, items, 1)
+	require.Equal(t, queued.UTC().Format(time.RFC3339), items[0].QueuedAt)
+	require.Equal(t, queued.UnixMilli(), items[0].InQueueSince)
+	require.GreaterOrEqual(t, items[0].WaitMs, int64(90_000))
+}
+
+func TestOutputTimeFieldConventions(t *testing.T) {
+	// inQueueSince is the deprecated epoch twin of queuedAt.
+	for _, output := range []any{queueItem{}, queueItemDetail{}, queueWaitOutput{}, queueWhyOutput{}} {
+		require.Empty(t, outputschema.TimeFieldViolations(output, "inQueueSince"), "%T", output)
+	}
+}
+
+func TestNormalizeWhy(t *testing.T) {
+	tests := []struct {
+		why    string
+		reason string
+		label  string
+	}{
+		{"Waiting for next available executor on ‘linux’", "Waiting for next available executor on linux", "linux"},
+		{"Waiting for next available executor on 'docker && amd64'", "Waiting for next available executor on docker && amd64", "docker && amd64"},
+		{"Waiting for next available executor on linux", "Waiting for next available executor on linux", "linux"},
+		{"Waiting for next available executor", "Waiting for next available executor", ""},
+		{"There are no nodes with the label ‘gpu’", "There are no nodes with the label gpu", "gpu"},
+		{"All nodes of label ‘windows’ are offline", "All nodes of label windows are offline", "windows"},
+		{"‘agent-7’ is offline", "agent-7 is offline", ""},
+		{"Build #1234 is already in progress (ETA: 3 min 12 sec)", "Build is already in progress", ""},
+		{"In the quiet period. Expires in 4.2 sec", "In the quiet period", ""},
+		{"Upstream project team/lib is already building.", "Upstream project is already building", ""},
+		{"Blocked by #88 (ETA: 1 min)", "Blocked by #N", ""},
+		{"  Waiting   for next available executor on ‘linux’\nand more  ", "Waiting for next available executor on linux", "linux"},
+		{"", "Unknown", ""},
+	}
+
+	for _, tt := range tests {
+		reason, label := normalizeWhy(tt.why)
+		require.Equal(t, tt.reason, reason, tt.why)
+		require.Equal(t, tt.label, label, tt.why)
+	}
+}
+
+func TestQueueWhyGroupsReasonsAndDetectsStarvedLabels(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	base := client.Context().URL
+	now := time.Now()
+	item := func(id int, job, why string, age time.Duration) map[string]any {
+		return map[string]any{
+			"id":           id,
+			"why":          why,
+			"inQueueSince": now.Add(-age).UnixMilli(),
+			"task":         map[string]any{"name": job, "url": base + "/job/team/job/" + job + "/"},
+		}
+	}
+	server.HandleJSON(http.MethodGet, "/queue/api/json", map[string]any{
+		"items": []map[string]any{
+			item(1, "app", "Waiting for next available executor on ‘linux’", time.Minute),
+			item(2, "web", "Waiting for next available executor on ‘linux’", 10*time.Minute),
+			item(3, "app", "Waiting for next available executor on ‘linux’", 2*time.Minute),
+			item(4, "gpu-train", "Waiting for next available executor on ‘gpu’", 30*time.Minute),
+			item(5, "lib", "Build #17 is already in progress (ETA: 2 min 0 sec)", 5*time.Minute),
+		},
+	})
+	server.HandleJSON(http.MethodGet, "/label/linux/api/json", map[string]any{"busyExecutors": 4, "totalExecutors": 4})
+	server.HandleJSON(http.MethodGet, "/label/gpu/api/json", map[string]any{"busyExecutors": 0, "totalExecutors": 0})
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdQueue(f)
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"why"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	require.NoError(t, cmd.Execute())
+
+	var output queueWhyOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+	require.Equal(t, 5, output.Total)
+	require.Len(t, output.Groups, 3)
+
+	linux := output.Groups[0]
+	require.Equal(t, "Waiting for next available executor on linux", linux.Reason)
+	require.Equal(t, 3, linux.Count)
+	require.Equal(t, []int64{1, 2, 3}, linux.ItemIDs)
+	require.Equal(t, []string{"team/app", "team/web"}, linux.Jobs)
+	require.GreaterOrEqual(t, linux.OldestWaitMs, (10 * time.Minute).Milliseconds())
+	require.Equal(t, &shared.LabelStatus{Name: "linux", TotalExecutors: 4, BusyExecutors: 4}, linux.Label)
+
+	gpu := output.Groups[1]
+	require.Equal(t, "Waiting for next available executor on gpu", gpu.Reason)
+	require.True(t, gpu.Label.NoOnlineExecutors)
+
+	require.Equal(t, "Build is already in progress", output.Groups[2].Reason)
+	require.Nil(t, output.Groups[2].Label)
+	require.Len(t, server.RequestsTo(http.MethodGet, "/label/linux/api/json"), 1)
+}
+
+func TestQueueWhyHuman(t *testing.T) {
+	output := queueWhyOutput{Total: 7, Groups: []queueWhyGroup{{
+		Reason:       "Waiting for next available executor on gpu",
+		Count:        7,
+		OldestWaitMs: (14*time.Minute + 3*time.Second + 400*time.Millisecond).Milliseconds(),
+		Jobs:         []string{"a", "b", "c", "d", "e", "f", "g"},
+		Label:        &shared.LabelStatus{Name: "gpu", NoOnlineExecutors: true},
+	}}}
+
+	var buf strings.Builder
+	renderQueueWhy(&buf, output)
+	require.Equal(t, "7\tWaiting for next available executor on gpu\toldest 14m 3s\n"+
+		"    label gpu has no online executors\n"+
+		"    jobs: a, b, c, d, e (+2 more)\n", buf.String())
+}
+
+func TestQueueListWithParams(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/queue/api/json", map[string]any{
+		"items": []map[string]any{
+			{"id": 7, "why": "Waiting for next available executor", "task": map[string]any{"name": "deploy"}, "actions": []map[string]any{
+				{},
+				{"parameters": []map[string]any{
+					{"name": "CHART", "value": "nova"},
+					{"name": "DRY_RUN", "value": false},
+					{"name": "API_TOKEN", "value": "s3cret"},
+				}},
+			}},
+			{"id": 8, "why": "In the quiet period", "task": map[string]any{"name": "lint"}},
+		},
+	})
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	run := func(args ...string) {
+		stdout.Reset()
+		cmd := NewCmdQueue(f)
+		cmd.PersistentFlags().Bool("json", false, "")
+		cmd.PersistentFlags().Bool("yaml", false, "")
+		cmd.SetArgs(append([]string{"ls"}, args...))
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		require.NoError(t, cmd.Execute())
+	}
+
+	run()
+	require.Equal(t, queueTree, server.LastRequest(http.MethodGet, "/queue/api/json").Query.Get("tree"), "parameters are only requested with --with-params")
+
+	run("--with-params")
+	require.Equal(t, queueParamsTree, server.LastRequest(http.MethodGet, "/queue/api/json").Query.Get("tree"))
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasSuffix(lines[0], "\tCHART=nova DRY_RUN=false API_TOKEN=[REDACTED]"), lines[0])
+	require.True(t, strings.HasSuffix(lines[1], "In the quiet period"), lines[1])
+
+	run("--with-params", "--json")
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &items))
+	require.NotContains(t, items[0], "actions")
+	require.Equal(t, []any{
+		map[string]any{"name": "CHART", "value": "nova"},
+		map[string]any{"name": "DRY_RUN", "value": "false"},
+		map[string]any{"name": "API_TOKEN", "value": "[REDACTED]"},
+	}, items[0]["parameters"])
+	require.NotContains(t, items[1], "parameters")
+}
+
+func TestQueueWaitTimesOutDuringBackoff(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/queue/api/json", http.StatusServiceUnavailable, "")
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdQueue(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"wait", "--empty", "--timeout", "100ms", "--poll-backoff", "1h"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	start := time.Now()
+	err := cmd.Execute()
+	require.Less(t, time.Since(start), 10*time.Second)
+	require.Equal(t, 7, shared.ExitCode(err), "%v", err)
+	require.Contains(t, err.Error(), "timed out after 100ms")
+}
+
+func TestMatchingQueueItemsComparesFullPaths(t *testing.T) {
+	items := []queueItem{
+		{ID: 1, Buildable: true, Task: queueTaskRef{URL: "https://ci.example.com/jenkins/job/a/job/b/"}},
+		{ID: 2, Buildable: true, Task: queueTaskRef{URL: "https://ci.example.com/jenkins/job/x/job/a/job/b/"}},
+		{ID: 3, Blocked: true, Task: queueTaskRef{URL: "https://ci.example.com/jenkins/job/a/job/b/"}},
+		{ID: 4, Buildable: true, Task: queueTaskRef{URL: "https://ci.example.com/jenkins/job/b/"}},
+	}
+
+	matched := matchingQueueItems(items, "a/b", 0)
+	require.Len(t, matched, 2)
+	require.Equal(t, int64(1), matched[0].ID)
+	require.Equal(t, int64(3), matched[1].ID)
+	require.Equal(t, 1, countBlocked(matched))
+	require.Equal(t, "2 item(s) remaining (1 blocked)", queueWaitStatusLine(matched, time.Now()))
+
+	require.Len(t, matchingQueueItems(items, "b", 0), 1)
+}
+
+func TestQueueExitCodes(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/queue/api/json", http.StatusForbidden, "")
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	for _, tt := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"wait"}, 2},
+		{[]string{"wait", "--empty", "--id", "7"}, 2},
+		{[]string{"wait", "--id", "7", "--job", "app"}, 2},
+		{[]string{"ls"}, 5},
+	} {
+		cmd := NewCmdQueue(f)
+		cmd.PersistentFlags().Bool("json", false, "")
+		cmd.PersistentFlags().Bool("yaml", false, "")
+		cmd.SetArgs(tt.args)
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		require.Equal(t, tt.code, shared.ExitCode(cmd.Execute()), "%q", tt.args)
+	}
+}
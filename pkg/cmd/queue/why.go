@@ -0,0 +1,241 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// whyJobsShown caps the jobs listed under each reason in human output.
+const whyJobsShown = 5
+
+type queueWhyOutput struct {
+	SchemaVersion string          `json:"schemaVersion"`
+	Total         int             `json:"total"`
+	Groups        []queueWhyGroup `json:"groups"`
+}
+
+type queueWhyGroup struct {
+	Reason         string              `json:"reason"`
+	Count          int                 `json:"count"`
+	OldestQueuedAt string              `json:"oldestQueuedAt,omitempty"`
+	OldestWaitMs   int64               `json:"oldestWaitMs,omitempty"`
+	Jobs           []string            `json:"jobs"`
+	ItemIDs        []int64             `json:"itemIds"`
+	Label          *shared.LabelStatus `json:"label,omitempty"`
+
+	// labelName is the label the reason waits on, if any.
+	labelName string
+}
+
+// whyNormalizer rewrites one family of Jenkins blocked reasons to a stable
+// form. A "label" capture names the label the reason waits on.
+type whyNormalizer struct {
+	pattern *regexp.Regexp
+	reason  string
+}
+
+// whyNormalizers cover the CauseOfBlockage messages from Jenkins core. Names
+// may be wrapped in typographic or plain quotes depending on the version.
+var whyNormalizers = []whyNormalizer{
+	{regexp.MustCompile(`^Waiting for next available executor on ['‘]?(?P<label>.+?)['’]?$`), "Waiting for next available executor on ${label}"},
+	{regexp.MustCompile(`^There are no nodes with the label ['‘]?(?P<label>.+?)['’]?$`), "There are no nodes with the label ${label}"},
+	{regexp.MustCompile(`^All nodes of label ['‘]?(?P<label>.+?)['’]? are offline$`), "All nodes of label ${label} are offline"},
+	{regexp.MustCompile(`^['‘]?(?P<node>.+?)['’]? is offline$`), "${node} is offline"},
+	{regexp.MustCompile(`^Build #\d+ is already in progress.*$`), "Build is already in progress"},
+	{regexp.MustCompile(`^In the quiet period\..*$`), "In the quiet period"},
+	{regexp.MustCompile(`^Upstream project .+ is already building\.?$`), "Upstream project is already building"},
+	{regexp.MustCompile(`^Waiting for \d+(?:\.\d+)? \S+ for .+$`), "Waiting for a delay"},
+}
+
+var (
+	whyETA         = regexp.MustCompile(`\s*\(ETA:[^)]*\)`)
+	whyBuildNumber = regexp.MustCompile(`#\d+`)
+	whySpace       = regexp.MustCompile(`\s+`)
+)
+
+func newQueueWhyCmd(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "why",
+		Short: "Group queued items by why they are blocked",
+		Long: `Group the current queue by blocked reason, with build numbers, ETAs, and
+other item-specific details stripped so identical causes collapse into one
+line. Each reason reports how many items it holds, the oldest wait, and the
+jobs affected, most common first.
+
+When a reason waits on a label, the label's executors are looked up so a label
+with no online executors (an outage, not a busy farm) stands out.`,
+		Example: `  jk queue why
+  jk queue why --json | jq '.groups[] | select(.label.noOnlineExecutors)'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			resp, err := fetchQueue(ctx, client, false, nil)
+			if err != nil {
+				return err
+			}
+
+			groups := groupQueueReasons(resp.Items)
+			statuses := make(map[string]*shared.LabelStatus)
+			for i := range groups {
+				name := groups[i].labelName
+				if name == "" {
+					continue
+				}
+				status, seen := statuses[name]
+				if !seen {
+					status, err = shared.FetchLabelStatus(ctx, client, name)
+					if err != nil {
+						jklog.L().Debug().Err(err).Str("label", name).Msg("fetch label failed")
+					}
+					statuses[name] = status
+				}
+				groups[i].Label = status
+			}
+
+			output := queueWhyOutput{SchemaVersion: "1.0", Total: len(resp.Items), Groups: groups}
+			return shared.PrintOutput(cmd, output, func() error {
+				renderQueueWhy(cmd.OutOrStdout(), output)
+				return nil
+			})
+		},
+	}
+}
+
+// normalizeWhy reduces a blocked reason to its stable form and returns the
+// label it waits on, if any. Only the first line of multi-line reasons is
+// kept.
+func normalizeWhy(why string) (string, string) {
+	why, _, _ = strings.Cut(strings.TrimSpace(why), "\n")
+	why = whySpace.ReplaceAllString(strings.TrimSpace(why), " ")
+	if why == "" {
+		return "Unknown", ""
+	}
+	for _, n := range whyNormalizers {
+		match := n.pattern.FindStringSubmatchIndex(why)
+		if match == nil {
+			continue
+		}
+		reason := string(n.pattern.ExpandString(nil, n.reason, why, match))
+		label := ""
+		if i := n.pattern.SubexpIndex("label"); i >= 0 && match[2*i] >= 0 {
+			label = why[match[2*i]:match[2*i+1]]
+		}
+		return reason, label
+	}
+	why = whyETA.ReplaceAllString(why, "")
+	return whyBuildNumber.ReplaceAllString(why, "#N"), ""
+}
+
+// groupQueueReasons groups items by normalized reason, most items first.
+func groupQueueReasons(items []queueItem) []queueWhyGroup {
+	index := make(map[string]int)
+	jobs := make(map[string]map[string]struct{})
+	var groups []queueWhyGroup
+
+	for _, item := range items {
+		reason, label := normalizeWhy(item.Why)
+		i, ok := index[reason]
+		if !ok {
+			i = len(groups)
+			index[reason] = i
+			groups = append(groups, queueWhyGroup{Reason: reason, labelName: label})
+			jobs[reason] = make(map[string]struct{})
+		}
+		group := &groups[i]
+		group.Count++
+		group.ItemIDs = append(group.ItemIDs, item.ID)
+		if group.OldestQueuedAt == "" || item.WaitMs > group.OldestWaitMs {
+			group.OldestWaitMs = item.WaitMs
+			group.OldestQueuedAt = item.QueuedAt
+		}
+		jobs[reason][queueItemJob(item)] = struct{}{}
+	}
+
+	for i := range groups {
+		names := make([]string, 0, len(jobs[groups[i].Reason]))
+		for name := range jobs[groups[i].Reason] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		groups[i].Jobs = names
+		sort.Slice(groups[i].ItemIDs, func(a, b int) bool { return groups[i].ItemIDs[a] < groups[i].ItemIDs[b] })
+	}
+	sort.SliceStable(groups, func(a, b int) bool {
+		if groups[a].Count != groups[b].Count {
+			return groups[a].Count > groups[b].Count
+		}
+		if groups[a].OldestWaitMs != groups[b].OldestWaitMs {
+			return groups[a].OldestWaitMs > groups[b].OldestWaitMs
+		}
+		return groups[a].Reason < groups[b].Reason
+	})
+	return groups
+}
+
+// queueItemJob is the job path of a queued task, decoded from its URL, or
+// the task name when the URL is not a job URL.
+func queueItemJob(item queueItem) string {
+	if path := queueTaskPath(item); path != "" {
+		return path
+	}
+	return item.Task.Name
+}
+
+// queueTaskPath is the normalized job path of a queued task, decoded from its
+// URL, or "" when the URL is not a job URL.
+func queueTaskPath(item queueItem) string {
+	u, err := url.Parse(item.Task.URL)
+	if err != nil {
+		return ""
+	}
+	path, _ := jobpath.Decode(u.EscapedPath())
+	return path
+}
+
+func renderQueueWhy(w io.Writer, output queueWhyOutput) {
+	if len(output.Groups) == 0 {
+		_, _ = fmt.Fprintln(w, "Queue is empty")
+		return
+	}
+	for _, group := range output.Groups {
+		line := fmt.Sprintf("%d\t%s", group.Count, group.Reason)
+		if group.OldestWaitMs > 0 {
+			line += fmt.Sprintf("\toldest %s", waitString(group.OldestWaitMs))
+		}
+		_, _ = fmt.Fprintln(w, line)
+		if label := group.Label; label != nil {
+			if label.NoOnlineExecutors {
+				_, _ = fmt.Fprintf(w, "    label %s has no online executors\n", label.Name)
+			} else {
+				_, _ = fmt.Fprintf(w, "    label %s: %d/%d executors busy\n", label.Name, label.BusyExecutors, label.TotalExecutors)
+			}
+		}
+		jobs := group.Jobs
+		more := ""
+		if len(jobs) > whyJobsShown {
+			more = fmt.Sprintf(" (+%d more)", len(jobs)-whyJobsShown)
+			jobs = jobs[:whyJobsShown]
+		}
+		_, _ = fmt.Fprintf(w, "    jobs: %s%s\n", strings.Join(jobs, ", "), more)
+	}
+}
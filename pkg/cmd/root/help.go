@@ -10,33 +10,54 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
 type helpDocument struct {
 	SchemaVersion string            `json:"schemaVersion"`
 	Commands      []helpCommand     `json:"commands"`
 	ExitCodes     map[string]string `json:"exitCodes,omitempty"`
+	Conventions   *helpConventions  `json:"conventions,omitempty"`
+}
+
+// helpConventions documents how JSON output encodes time values.
+type helpConventions struct {
+	Timestamps string            `json:"timestamps"`
+	Durations  string            `json:"durations"`
+	Deprecated map[string]string `json:"deprecated,omitempty"`
 }
 
 type helpCommand struct {
-	Name        string        `json:"name"`
-	Use         string        `json:"use"`
-	Description string        `json:"description,omitempty"`
-	Long        string        `json:"long,omitempty"`
-	Examples    []string      `json:"examples,omitempty"`
-	Flags       []helpFlag    `json:"flags,omitempty"`
-	Subcommands []helpCommand `json:"subcommands,omitempty"`
+	Name        string            `json:"name"`
+	Use         string            `json:"use"`
+	Description string            `json:"description,omitempty"`
+	Long        string            `json:"long,omitempty"`
+	Examples    []string          `json:"examples,omitempty"`
+	Flags       []helpFlag        `json:"flags,omitempty"`
+	Filters     *helpFilters      `json:"filters,omitempty"`
+	ExitCodes   map[string]string `json:"exitCodes,omitempty"`
+	Subcommands []helpCommand     `json:"subcommands,omitempty"`
 }
 
 type helpFlag struct {
-	Name        string `json:"name"`
-	Shorthand   string `json:"shorthand,omitempty"`
-	Type        string `json:"type"`
-	Description string `json:"description,omitempty"`
-	Default     string `json:"default,omitempty"`
-	Persistent  bool   `json:"persistent,omitempty"`
+	Name        string   `json:"name"`
+	Shorthand   string   `json:"shorthand,omitempty"`
+	Type        string   `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Persistent  bool     `json:"persistent,omitempty"`
+}
+
+type helpFilters struct {
+	Keys      []string `json:"keys,omitempty"`
+	Operators []string `json:"operators,omitempty"`
 }
 
+const helpSchemaVersion = "1.2"
+
 func attachJSONHelp(root *cobra.Command) {
 	defaultHelp := root.HelpFunc()
 	root.SetHelpFunc(func(cmd *cobra.Command, args []string) {
@@ -80,11 +101,16 @@ func newHelpCommand(root *cobra.Command) *cobra.Command {
 
 func buildHelpDocument(cmd *cobra.Command, includeExitCodes bool) helpDocument {
 	doc := helpDocument{
-		SchemaVersion: "1.0",
+		SchemaVersion: helpSchemaVersion,
 		Commands:      []helpCommand{buildHelpCommand(cmd)},
 	}
 	if includeExitCodes {
 		doc.ExitCodes = defaultExitCodes()
+		doc.Conventions = &helpConventions{
+			Timestamps: shared.TimestampConvention,
+			Durations:  shared.DurationConvention,
+			Deprecated: shared.DeprecatedTimeFields,
+		}
 	}
 	return doc
 }
@@ -102,6 +128,10 @@ func buildHelpCommand(cmd *cobra.Command) helpCommand {
 		hc.Examples = examples
 	}
 	hc.Flags = collectFlags(cmd)
+	if keys, operators := cmdutil.FilterSupport(cmd); len(keys) > 0 || len(operators) > 0 {
+		hc.Filters = &helpFilters{Keys: keys, Operators: operators}
+	}
+	hc.ExitCodes = cmdutil.ExitCodes(cmd)
 
 	children := cmd.Commands()
 	sort.Slice(children, func(i, j int) bool {
@@ -134,6 +164,7 @@ func collectFlags(cmd *cobra.Command) []helpFlag {
 				Type:        flag.Value.Type(),
 				Description: strings.TrimSpace(flag.Usage),
 				Default:     flag.DefValue,
+				Enum:        cmdutil.FlagEnum(flag),
 				Persistent:  persistent,
 			})
 		})
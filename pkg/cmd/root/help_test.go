@@ -0,0 +1,90 @@
+package root
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestHelpDocumentIncludesRunListFilters(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	root, err := NewCmdRoot(&cmdutil.Factory{ExecutableName: "jk", IOStreams: ios})
+	require.NoError(t, err)
+
+	runLs, _, err := root.Find([]string{"run", "ls"})
+	require.NoError(t, err)
+
+	doc := buildHelpDocument(runLs, false)
+	require.Equal(t, helpSchemaVersion, doc.SchemaVersion)
+	require.Len(t, doc.Commands, 1)
+
+	cmd := doc.Commands[0]
+	require.NotNil(t, cmd.Filters)
+	require.Equal(t, filter.Operators(), cmd.Filters.Operators)
+	require.Equal(t, filter.AllowedKeys(), cmd.Filters.Keys)
+
+	var agg *helpFlag
+	for i := range cmd.Flags {
+		if cmd.Flags[i].Name == "agg" {
+			agg = &cmd.Flags[i]
+		}
+	}
+	require.NotNil(t, agg)
+	require.Equal(t, []string{"count", "first", "last", "sum-artifact-bytes"}, agg.Enum)
+}
+
+func TestHelpDocumentIncludesCommandExitCodes(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	root, err := NewCmdRoot(&cmdutil.Factory{ExecutableName: "jk", IOStreams: ios})
+	require.NoError(t, err)
+
+	logCmd, _, err := root.Find([]string{"log"})
+	require.NoError(t, err)
+
+	doc := buildHelpDocument(logCmd, false)
+	require.Equal(t, "Run not found", doc.Commands[0].ExitCodes["3"])
+}
+
+func TestNoInputFlagAndEnvDisablePrompts(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		env  string
+	}{
+		{"flag", []string{"--no-input", "version", "--client"}, ""},
+		{"env", []string{"version", "--client"}, "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(cmdutil.NoInputEnv, tt.env)
+			ios, _, _, _ := iostreams.Test()
+			root, err := NewCmdRoot(&cmdutil.Factory{ExecutableName: "jk", IOStreams: ios})
+			require.NoError(t, err)
+
+			root.SetArgs(tt.args)
+			require.NoError(t, root.Execute())
+			require.True(t, ios.GetNeverPrompt())
+		})
+	}
+}
+
+func TestRootHelpDocumentIncludesTimeConventions(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	root, err := NewCmdRoot(&cmdutil.Factory{ExecutableName: "jk", IOStreams: ios})
+	require.NoError(t, err)
+
+	doc := buildHelpDocument(root, true)
+	require.NotNil(t, doc.Conventions)
+	require.Contains(t, doc.Conventions.Timestamps, "RFC3339")
+	require.Contains(t, doc.Conventions.Durations, "milliseconds")
+	require.Contains(t, doc.Conventions.Deprecated, "log.duration")
+
+	runLs, _, err := root.Find([]string{"run", "ls"})
+	require.NoError(t, err)
+	require.Nil(t, buildHelpDocument(runLs, false).Conventions)
+}
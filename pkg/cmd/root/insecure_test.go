@@ -0,0 +1,109 @@
+package root
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+const insecureWarning = "warning: TLS certificate verification is disabled for this invocation"
+
+// setupSelfSignedContext points the active context at a TLS server whose
+// certificate no system root trusts, and returns the config file path.
+func setupSelfSignedContext(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "roottest")
+	t.Cleanup(func() { jenkins.SetInsecureOverride(false) })
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jobs":[{"name":"app","url":"/job/app/","color":"blue"}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.SetContext("staging", &config.Context{URL: server.URL, Username: "jane", AllowInsecureStore: true})
+	require.NoError(t, cfg.SetActive("staging"))
+	require.NoError(t, cfg.Save())
+
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	require.NoError(t, err)
+	require.NoError(t, store.Set(secret.TokenKey("staging"), "token"))
+
+	path, err := config.DefaultPath()
+	require.NoError(t, err)
+	return path
+}
+
+func executeRoot(t *testing.T, args ...string) (string, string, error) {
+	t.Helper()
+	ios, _, stdout, stderr := iostreams.Test()
+	root, err := NewCmdRoot(&cmdutil.Factory{ExecutableName: "jk", IOStreams: ios})
+	require.NoError(t, err)
+	root.SetArgs(args)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SilenceErrors = true
+	err = root.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestInsecureSkipTLSVerifyIsScopedToInvocation(t *testing.T) {
+	configPath := setupSelfSignedContext(t)
+	before, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+
+	_, _, err = executeRoot(t, "job", "ls", "--json")
+	require.Error(t, err, "self-signed certificate must be rejected without the flag")
+
+	stdout, stderr, err := executeRoot(t, "--insecure-skip-tls-verify", "job", "ls", "--json")
+	require.NoError(t, err)
+	require.Contains(t, stdout, `"app"`)
+	require.Equal(t, 1, strings.Count(stderr, insecureWarning))
+
+	after, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	require.Equal(t, string(before), string(after))
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	ctx, err := cfg.Context("staging")
+	require.NoError(t, err)
+	require.False(t, ctx.Insecure)
+}
+
+func TestInsecureSkipTLSVerifyQuiet(t *testing.T) {
+	setupSelfSignedContext(t)
+
+	_, stderr, err := executeRoot(t, "--insecure-skip-tls-verify", "--quiet", "job", "ls", "--json")
+	require.NoError(t, err)
+	require.NotContains(t, stderr, insecureWarning)
+}
+
+func TestInsecureSkipTLSVerifyRejectsCAFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() { jenkins.SetInsecureOverride(false) })
+
+	_, _, err := executeRoot(t, "--insecure-skip-tls-verify", "auth", "login", "https://ci.example.com", "--ca-file", "ca.pem", "--username", "jane", "--token", "abc")
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 2, exitErr.Code)
+}
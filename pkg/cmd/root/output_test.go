@@ -0,0 +1,234 @@
+package root
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestJSONAndYAMLTogetherExit2(t *testing.T) {
+	_, _, err := executeRoot(t, "version", "--client", "--json", "--yaml")
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 2, exitErr.Code)
+	require.Contains(t, exitErr.Msg, "--json and --yaml")
+}
+
+// mutatingJSONCases runs in order against one stub controller, so later
+// cases can rely on state left by earlier ones (run start records the run
+// that rerun-last and cancel-last use).
+var mutatingJSONCases = []struct {
+	path string
+	args []string
+}{
+	{"jk admin put-file", []string{"admin", "put-file", "{file:plan.md}", "--yes"}},
+	{"jk auth login", []string{"auth", "login", "https://other.example.com", "--name", "other", "--username", "jane", "--token", "t", "--allow-insecure-store", "--set-active=false"}},
+	{"jk auth logout", []string{"auth", "logout", "--context", "other"}},
+	{"jk context set-default", []string{"context", "set-default", "app", "ENV=dev"}},
+	{"jk context unset-default", []string{"context", "unset-default", "app"}},
+	{"jk context set-folder", []string{"context", "set-folder", "team"}},
+	{"jk init", []string{"init", "--url", "{server}", "--name", "wizard", "--username", "jane", "--token", "t", "--insecure", "--allow-insecure-store"}},
+	{"jk context use", []string{"context", "use", "stub"}},
+	{"jk context rm", []string{"context", "rm", "wizard"}},
+	{"jk context rules set", []string{"context", "rules", "set", "team-a", "stub"}},
+	{"jk context rules rm", []string{"context", "rules", "rm", "team-a"}},
+	{"jk cred create-secret", []string{"cred", "create-secret", "--id", "api", "--secret", "s3cret"}},
+	{"jk cred create-file", []string{"cred", "create-file", "--id", "kubeconfig", "--file", "{file:kubeconfig}"}},
+	{"jk cred update-file", []string{"cred", "update-file", "kubeconfig", "--file", "{file:kubeconfig}", "--description", "kube"}},
+	{"jk cred rm", []string{"cred", "rm", "api"}},
+	{"jk job create", []string{"job", "create", "/new-app", "--file", "{file:config.xml}"}},
+	{"jk job disable", []string{"job", "disable", "/app"}},
+	{"jk job enable", []string{"job", "enable", "/app"}},
+	{"jk node config set", []string{"node", "config", "set", "agent-1", "--file", "{file:node.xml}"}},
+	{"jk node cordon", []string{"node", "cordon", "agent-1"}},
+	{"jk node uncordon", []string{"node", "uncordon", "agent-1"}},
+	{"jk node rm", []string{"node", "rm", "agent-1"}},
+	{"jk plugin install", []string{"plugin", "install", "git", "--yes"}},
+	{"jk plugin disable", []string{"plugin", "disable", "git"}},
+	{"jk plugin enable", []string{"plugin", "enable", "git"}},
+	{"jk queue cancel", []string{"queue", "cancel", "7"}},
+	{"jk run start", []string{"run", "start", "/app"}},
+	{"jk rerun-last", []string{"rerun-last"}},
+	{"jk cancel-last", []string{"cancel-last"}},
+	{"jk run rerun", []string{"run", "rerun", "/app", "1"}},
+	{"jk run cancel", []string{"run", "cancel", "/app", "1"}},
+}
+
+// otherCommands are not mutating commands, or their --json output is
+// covered in their own package tests. A new command must be added here or
+// to mutatingJSONCases.
+var otherCommands = map[string]string{
+	"jk admin audit-config":    "read-only",
+	"jk admin ls-files":        "read-only",
+	"jk admin snapshot-config": "package tests",
+	"jk artifact cat":          "raw artifact content",
+	"jk artifact download":     "package tests",
+	"jk artifact ls":           "read-only",
+	"jk artifact verify":       "read-only",
+	"jk auth status":           "read-only",
+	"jk config validate":       "read-only",
+	"jk context ls":            "read-only",
+	"jk context ping":          "read-only",
+	"jk context rules ls":      "read-only",
+	"jk cred audit":            "read-only",
+	"jk cred ls":               "read-only",
+	"jk job last":              "read-only",
+	"jk job lint":              "read-only",
+	"jk job ls":                "read-only",
+	"jk job paths":             "read-only",
+	"jk job retention":         "read-only",
+	"jk job triggers":          "package tests",
+	"jk job view":              "read-only",
+	"jk last":                  "read-only",
+	"jk log":                   "read-only",
+	"jk node config get":       "read-only",
+	"jk node ls":               "read-only",
+	"jk node utilization":      "read-only",
+	"jk open":                  "opens a browser",
+	"jk plugin deps":           "read-only",
+	"jk plugin ls":             "read-only",
+	"jk plugin verify":         "package tests",
+	"jk queue ls":              "read-only",
+	"jk queue view":            "read-only",
+	"jk queue wait":            "read-only",
+	"jk queue why":             "read-only",
+	"jk run artifact cat":      "raw artifact content",
+	"jk run attach":            "read-only",
+	"jk run causes":            "read-only",
+	"jk run env":               "read-only",
+	"jk run failures":          "read-only",
+	"jk run last":              "read-only",
+	"jk run link":              "read-only",
+	"jk run ls":                "read-only",
+	"jk run params":            "read-only",
+	"jk run report":            "raw JUnit XML",
+	"jk run search":            "read-only",
+	"jk run stats":             "read-only",
+	"jk run status":            "read-only",
+	"jk run top":               "package tests",
+	"jk run view":              "read-only",
+	"jk search":                "read-only",
+	"jk test cases":            "read-only",
+	"jk test report":           "read-only",
+	"jk version":               "read-only",
+}
+
+// newStubController answers every request the mutating commands make with a
+// success response.
+func newStubController(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Jenkins", "2.462.1")
+		writeJSON := func(body string) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(body))
+		}
+		switch {
+		case r.URL.Path == "/crumbIssuer/api/json":
+			writeJSON(`{"crumb":"c","crumbRequestField":"Jenkins-Crumb"}`)
+		case r.URL.Path == "/scriptText":
+			_, _ = w.Write([]byte("JK-PUT-OK 2 false\n"))
+		case r.URL.Path == "/me/api/json" || r.URL.Path == "/whoAmI/api/json":
+			writeJSON(`{"id":"jane","fullName":"Jane","name":"jane"}`)
+		case strings.HasSuffix(r.URL.Path, "/build") || strings.HasSuffix(r.URL.Path, "/buildWithParameters"):
+			w.Header().Set("Location", server.URL+"/queue/item/7/")
+			w.WriteHeader(http.StatusCreated)
+		case r.URL.Path == "/queue/item/7/api/json":
+			writeJSON(`{"id":7,"cancelled":false}`)
+		case strings.HasSuffix(r.URL.Path, "/config.xml"):
+			_, _ = w.Write([]byte("<xml/>"))
+		case strings.HasSuffix(r.URL.Path, "/api/json"):
+			writeJSON(`{"_class":"hudson.model.FreeStyleProject","name":"app","buildable":true,"number":1,"actions":[]}`)
+		case r.Method == http.MethodGet:
+			writeJSON(`{}`)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func leafCommandPaths(cmd *cobra.Command) []string {
+	if !cmd.HasSubCommands() {
+		return []string{cmd.CommandPath()}
+	}
+	var paths []string
+	for _, child := range cmd.Commands() {
+		if child.Hidden || child.Name() == "help" || child.Name() == "completion" {
+			continue
+		}
+		paths = append(paths, leafCommandPaths(child)...)
+	}
+	return paths
+}
+
+func TestMutatingCommandsPrintJSON(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("KEYRING_BACKEND", "file")
+	t.Setenv("KEYRING_FILE_DIR", t.TempDir())
+	t.Setenv("JK_KEYRING_PASSPHRASE", "roottest")
+	server := newStubController(t)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.SetContext("stub", &config.Context{URL: server.URL, Username: "jane", Insecure: true, AllowInsecureStore: true})
+	require.NoError(t, cfg.SetActive("stub"))
+	require.NoError(t, cfg.Save())
+	store, err := secret.Open(secret.WithAllowFileFallback(true))
+	require.NoError(t, err)
+	require.NoError(t, store.Set(secret.TokenKey("stub"), "token"))
+
+	files := t.TempDir()
+	ios, _, _, _ := iostreams.Test()
+	root, err := NewCmdRoot(&cmdutil.Factory{ExecutableName: "jk", IOStreams: ios})
+	require.NoError(t, err)
+
+	covered := map[string]bool{}
+	for _, tc := range mutatingJSONCases {
+		covered[tc.path] = true
+	}
+	var unclassified []string
+	for _, path := range leafCommandPaths(root) {
+		if !covered[path] && otherCommands[path] == "" {
+			unclassified = append(unclassified, path)
+		}
+		delete(covered, path)
+	}
+	sort.Strings(unclassified)
+	require.Empty(t, unclassified, "add new commands to mutatingJSONCases or otherCommands")
+	require.Empty(t, covered, "mutatingJSONCases names commands that no longer exist")
+
+	for _, tc := range mutatingJSONCases {
+		args := make([]string, 0, len(tc.args)+1)
+		for _, arg := range tc.args {
+			switch {
+			case arg == "{server}":
+				arg = server.URL
+			case strings.HasPrefix(arg, "{file:"):
+				name := strings.TrimSuffix(strings.TrimPrefix(arg, "{file:"), "}")
+				arg = filepath.Join(files, name)
+				require.NoError(t, os.WriteFile(arg, []byte("<x/>"), 0o600))
+			}
+			args = append(args, arg)
+		}
+		stdout, stderr, err := executeRoot(t, append(args, "--json")...)
+		require.NoError(t, err, "%s: %s", tc.path, stderr)
+		require.True(t, json.Valid([]byte(stdout)), "%s printed non-JSON output:\n%s", tc.path, stdout)
+	}
+}
@@ -2,21 +2,30 @@ package root
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/avivsinai/jenkins-cli/internal/build"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/admin"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/artifact"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/auth"
+	configcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/config"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/context"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/cred"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/job"
 	logcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/log"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/node"
+	opencmd "github.com/avivsinai/jenkins-cli/pkg/cmd/open"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/plugin"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/queue"
 	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
 	searchcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/search"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	testcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/test"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/version"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
@@ -47,21 +56,76 @@ Quick start:
 	root.PersistentFlags().StringP("context", "c", "", "Active Jenkins context name")
 	root.PersistentFlags().Bool("json", false, "Output in JSON format when supported")
 	root.PersistentFlags().Bool("yaml", false, "Output in YAML format when supported")
+	root.PersistentFlags().Bool("timings", false, "Report request and phase timings on completion")
+	root.PersistentFlags().Bool("no-input", false, "Fail instead of prompting for input (also JK_NO_INPUT=1)")
+	root.PersistentFlags().Bool("absolute", false, "Try job paths as given before the context default folder")
+	root.PersistentFlags().Bool("relative-time", false, "Show timestamps as relative ages (default when stdout is a terminal)")
+	root.PersistentFlags().Bool("absolute-time", false, "Show timestamps as RFC3339 (default when piped)")
+	root.MarkFlagsMutuallyExclusive("relative-time", "absolute-time")
+	root.PersistentFlags().String("progress", cmdutil.ProgressAuto, "Progress reporting on stderr: auto, human, json, none")
+	root.PersistentFlags().String("icons", shared.IconsAuto, "Result glyphs in human output: auto, always, never")
+	_ = root.PersistentFlags().SetAnnotation("icons", cmdutil.AnnotationFlagEnum, []string{shared.IconsAuto, shared.IconsAlways, shared.IconsNever})
+	root.PersistentFlags().Bool("insecure-skip-tls-verify", false, "Skip TLS certificate verification for this invocation only")
+	root.PersistentFlags().Bool("quiet", false, "Suppress warnings on stderr")
+	root.PersistentFlags().String("rate-limit", "", "Throttle requests to the controller, e.g. 10/s or 300/m,burst=5 (also JK_RATE_LIMIT)")
+	_ = root.PersistentFlags().SetAnnotation("progress", cmdutil.AnnotationFlagEnum, []string{cmdutil.ProgressAuto, cmdutil.ProgressHuman, cmdutil.ProgressJSON, cmdutil.ProgressNone})
+
+	root.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		f.Quiet = cmdutil.Quiet(cmd)
+		if enabled, _ := cmd.Flags().GetBool("timings"); enabled {
+			jenkins.EnableTimings()
+		}
+		if mode, _ := cmd.Flags().GetString("progress"); mode != "" {
+			switch mode {
+			case cmdutil.ProgressAuto, cmdutil.ProgressHuman, cmdutil.ProgressJSON, cmdutil.ProgressNone:
+				f.ProgressMode = mode
+			default:
+				return &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf("invalid --progress %q (want auto, human, json, or none)", mode)}
+			}
+		}
+		switch icons, _ := cmd.Flags().GetString("icons"); icons {
+		case shared.IconsAuto, shared.IconsAlways, shared.IconsNever:
+		default:
+			return &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf("invalid --icons %q (want auto, always, or never)", icons)}
+		}
+		if err := shared.ValidateOutputFlags(cmd); err != nil {
+			return err
+		}
+		f.StructuredOutput = shared.OutputFormat(cmd) != shared.FormatHuman
+		if err := applyRateLimit(cmd); err != nil {
+			return err
+		}
+		if err := applyInsecureOverride(cmd); err != nil {
+			return err
+		}
+		if noInput, _ := cmd.Flags().GetBool("no-input"); noInput || cmdutil.NoInputFromEnv() {
+			ios.SetNeverPrompt(true)
+			secret.DisablePrompts()
+		}
+		return nil
+	}
 
 	root.AddCommand(
+		auth.NewCmdInit(f),
 		auth.NewCmdAuth(f),
 		contextcmd.NewCmdContext(f),
+		configcmd.NewCmdConfig(f),
 		job.NewCmdJob(f),
 		cred.NewCmdCred(f),
 		searchcmd.NewCmdSearch(f),
 		runcmd.NewCmdRun(f),
+		runcmd.NewCmdLast(f),
+		runcmd.NewCmdRerunLast(f),
+		runcmd.NewCmdCancelLast(f),
 		logcmd.NewCmdLog(f),
+		opencmd.NewCmdOpen(f),
 		artifact.NewCmdArtifact(f),
 		node.NewCmdNode(f),
+		admin.NewCmdAdmin(f),
 		plugin.NewCmdPlugin(f),
 		queue.NewCmdQueue(f),
 		testcmd.NewCmdTest(f),
-		version.NewCmdVersion(),
+		version.NewCmdVersion(f),
 	)
 
 	root.Version = build.Version
@@ -72,3 +136,43 @@ Quick start:
 
 	return root, nil
 }
+
+// applyInsecureOverride handles --insecure-skip-tls-verify. The override is
+// scoped to this process; `jk auth login --insecure` owns the persisted
+// setting.
+func applyInsecureOverride(cmd *cobra.Command) error {
+	if skip, _ := cmd.Flags().GetBool("insecure-skip-tls-verify"); !skip {
+		return nil
+	}
+	if flag := cmd.Flags().Lookup("ca-file"); flag != nil && flag.Changed {
+		return &cmdutil.ExitError{Code: 2, Msg: "--insecure-skip-tls-verify cannot be combined with --ca-file"}
+	}
+	// jk open runs this hook again for the command it hands off to; warn once.
+	if jenkins.InsecureOverridden() {
+		return nil
+	}
+	jenkins.SetInsecureOverride(true)
+	cmdutil.Warnf(cmd, "TLS certificate verification is disabled for this invocation")
+	return nil
+}
+
+// applyRateLimit lets --rate-limit, then JK_RATE_LIMIT, replace the context's
+// rate_limit setting.
+func applyRateLimit(cmd *cobra.Command) error {
+	spec, source := "", ""
+	if cmd.Flags().Changed("rate-limit") {
+		spec, _ = cmd.Flags().GetString("rate-limit")
+		source = "--rate-limit"
+	} else if value, ok := os.LookupEnv(jenkins.RateLimitEnv); ok && strings.TrimSpace(value) != "" {
+		spec, source = value, jenkins.RateLimitEnv
+	} else {
+		return nil
+	}
+
+	limit, err := jenkins.ParseRateLimit(spec)
+	if err != nil {
+		return &cmdutil.ExitError{Code: 2, Msg: fmt.Sprintf("%s: %v", source, err)}
+	}
+	jenkins.SetRateLimitOverride(limit)
+	return nil
+}
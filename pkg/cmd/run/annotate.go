@@ -0,0 +1,143 @@
+package run
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/build"
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// invocationArgs returns the command line jk was started with; tests replace
+// it.
+var invocationArgs = func() []string { return os.Args }
+
+func addAnnotateBuildFlag(cmd *cobra.Command, annotate *bool) {
+	cmd.Flags().BoolVar(annotate, "annotate-build", false, "With --follow, append the jk command line, version, context, and user@host to the build description")
+}
+
+// buildAnnotation describes the invocation that triggered a run, for the
+// build description.
+func buildAnnotation(contextName string, redactor *filter.Redactor) string {
+	args := invocationArgs()
+	if len(args) > 0 {
+		args = args[1:]
+	}
+	return fmt.Sprintf("Triggered by jk %s: %s\nContext: %s, by %s@%s",
+		build.Version, sanitizeCommandLine(args, redactor), contextName, localUsername(), localHostname())
+}
+
+// sanitizeCommandLine renders args after "jk", with the values of secret
+// parameters (-p TOKEN=...) and secret-looking flags (--api-key ...)
+// replaced by [REDACTED].
+func sanitizeCommandLine(args []string, redactor *filter.Redactor) string {
+	parts := []string{"jk"}
+	redactNext, paramNext := false, false
+	for _, arg := range args {
+		switch {
+		case redactNext:
+			arg = filter.RedactedValue
+		case paramNext:
+			arg = sanitizeParamArg(arg, redactor)
+		case strings.HasPrefix(arg, "--param="):
+			arg = "--param=" + sanitizeParamArg(strings.TrimPrefix(arg, "--param="), redactor)
+		case strings.HasPrefix(arg, "-p") && len(arg) > 2 && !strings.HasPrefix(arg, "--"):
+			arg = "-p" + sanitizeParamArg(strings.TrimPrefix(arg[2:], "="), redactor)
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+			if redactor.IsSecret(name) {
+				if hasValue {
+					arg = "--" + name + "=" + filter.RedactedValue
+				} else {
+					parts = append(parts, quoteArg(arg))
+					redactNext = true
+					continue
+				}
+			}
+		}
+		paramNext = arg == "-p" || arg == "--param"
+		redactNext = false
+		parts = append(parts, quoteArg(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+func sanitizeParamArg(arg string, redactor *filter.Redactor) string {
+	key, _, ok := strings.Cut(arg, "=")
+	if ok && redactor.IsSecret(key) {
+		return key + "=" + filter.RedactedValue
+	}
+	return arg
+}
+
+func quoteArg(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
+
+func localUsername() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+func localHostname() string {
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "unknown"
+}
+
+// annotateBuild appends annotation to a build's description, keeping what
+// is there. It is best effort: failures, including a token without
+// Run/Update permission, only warn.
+func annotateBuild(cmd *cobra.Command, client shared.Doer, jobPath string, buildNumber int64, annotation string) {
+	if err := appendBuildDescription(client, jobPath, buildNumber, annotation); err != nil {
+		cmdutil.Warnf(cmd, "could not annotate %s #%d: %v", jobPath, buildNumber, err)
+	}
+}
+
+func appendBuildDescription(client shared.Doer, jobPath string, buildNumber int64, annotation string) error {
+	base := fmt.Sprintf("/%s/%d", jobpath.Encode(jobPath), buildNumber)
+	subject := fmt.Sprintf("run %s #%d", jobPath, buildNumber)
+
+	var current struct {
+		Description string `json:"description"`
+	}
+	resp, err := client.Do(client.NewRequest().SetQueryParam("tree", "description"), http.MethodGet, base+"/api/json", &current)
+	if err != nil {
+		return err
+	}
+	if err := shared.CheckResponse(resp, subject); err != nil {
+		return err
+	}
+
+	description := annotation
+	if existing := strings.TrimRight(current.Description, "\n"); existing != "" {
+		description = existing + "\n\n" + annotation
+	}
+	req := client.NewRequest().SetFormData(map[string]string{"description": description})
+	resp, err = client.Do(req, http.MethodPost, base+"/submitDescription", nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() == http.StatusForbidden {
+		return fmt.Errorf("permission denied; the token needs Run/Update to edit build descriptions")
+	}
+	return shared.CheckResponse(resp, subject)
+}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/build"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestSanitizeCommandLine(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{
+			args: []string{"run", "start", "team/app", "-p", "ENV=prod", "-p", "DEPLOY_TOKEN=s3cr3t", "--follow"},
+			want: "jk run start team/app -p ENV=prod -p DEPLOY_TOKEN=[REDACTED] --follow",
+		},
+		{
+			args: []string{"run", "start", "team/app", "--param=PASSWORD=hunter2", "-pAPI_KEY=abc", "-p=REGION=eu"},
+			want: "jk run start team/app --param=PASSWORD=[REDACTED] -pAPI_KEY=[REDACTED] -pREGION=eu",
+		},
+		{
+			args: []string{"run", "rerun", "team/app", "7", "--api-key", "abc", "--secret=xyz", "--reason", "hot fix"},
+			want: `jk run rerun team/app 7 --api-key [REDACTED] --secret=[REDACTED] --reason "hot fix"`,
+		},
+	}
+	for _, tt := range tests {
+		if got := sanitizeCommandLine(tt.args, nil); got != tt.want {
+			t.Errorf("sanitizeCommandLine(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestBuildAnnotation(t *testing.T) {
+	saved := invocationArgs
+	t.Cleanup(func() { invocationArgs = saved })
+	invocationArgs = func() []string {
+		return []string{"/usr/local/bin/jk", "run", "start", "app", "-p", "TOKEN=abc", "--follow", "--annotate-build"}
+	}
+
+	got := buildAnnotation("prod", nil)
+	want := "Triggered by jk " + build.Version + ": jk run start app -p TOKEN=[REDACTED] --follow --annotate-build\nContext: prod, by "
+	if !strings.HasPrefix(got, want) || !strings.Contains(got, "@") {
+		t.Fatalf("annotation = %q, want prefix %q", got, want)
+	}
+}
+
+func TestAnnotateBuildMergesDescription(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/7/api/json", map[string]any{"description": "Release 1.2\n"})
+	server.Handle(http.MethodPost, "/job/app/7/submitDescription", http.StatusOK, "")
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+	annotateBuild(cmd, client, "app", 7, "Triggered by jk")
+
+	if stderr.Len() != 0 {
+		t.Fatalf("unexpected warning %q", stderr.String())
+	}
+	got := server.LastRequest(http.MethodPost, "/job/app/7/submitDescription").Form().Get("description")
+	if got != "Release 1.2\n\nTriggered by jk" {
+		t.Fatalf("description = %q", got)
+	}
+}
+
+func TestAnnotateBuildWarnsWithoutPermission(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/7/api/json", map[string]any{"description": nil})
+	server.Handle(http.MethodPost, "/job/app/7/submitDescription", http.StatusForbidden, "")
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+	annotateBuild(cmd, client, "app", 7, "Triggered by jk")
+
+	want := "warning: could not annotate app #7: permission denied; the token needs Run/Update to edit build descriptions\n"
+	if stderr.String() != want {
+		t.Fatalf("stderr = %q, want %q", stderr.String(), want)
+	}
+}
+
+func TestRunStartAnnotateBuildRequiresFollow(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+
+	_, err := executeRunStart(t, client, "--annotate-build")
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit code 2, got %v", err)
+	}
+	if len(server.RequestsTo(http.MethodPost, "/job/releases/job/deploy/build")) != 0 {
+		t.Fatal("expected no build to be triggered")
+	}
+}
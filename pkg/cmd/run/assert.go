@@ -0,0 +1,138 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+// assertionExitCode is returned by `run ls` and `run search` when a count
+// assertion fails; the normal output is still printed first.
+const assertionExitCode = 17
+
+// countAssertion holds the --fail-if-none and --expect-* flags. A negative
+// bound is unset.
+type countAssertion struct {
+	FailIfNone bool
+	Count      int
+	Min        int
+	Max        int
+}
+
+// runAssertion is the outcome of a count assertion, reported in JSON
+// metadata. ExpectedMax is set only for a range (--expect-min with
+// --expect-max).
+type runAssertion struct {
+	Type        string `json:"type"`
+	Expected    int    `json:"expected"`
+	ExpectedMax int    `json:"expectedMax,omitempty"`
+	Actual      int    `json:"actual"`
+	// AtLeast reports that more runs matched than were returned, so Actual
+	// is a lower bound.
+	AtLeast bool   `json:"atLeast,omitempty"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+func addCountAssertionFlags(cmd *cobra.Command, a *countAssertion) {
+	cmd.Flags().BoolVar(&a.FailIfNone, "fail-if-none", false, fmt.Sprintf("Exit %d when nothing matches (groups with --group-by)", assertionExitCode))
+	cmd.Flags().IntVar(&a.Count, "expect-count", -1, fmt.Sprintf("Exit %d unless exactly N match (groups with --group-by)", assertionExitCode))
+	cmd.Flags().IntVar(&a.Min, "expect-min", -1, fmt.Sprintf("Exit %d unless at least N match (groups with --group-by)", assertionExitCode))
+	cmd.Flags().IntVar(&a.Max, "expect-max", -1, fmt.Sprintf("Exit %d if more than N match (groups with --group-by)", assertionExitCode))
+}
+
+// validate rejects negative bounds and combinations that contradict each
+// other; --expect-min and --expect-max together form a range.
+func (a countAssertion) validate(cmd *cobra.Command) error {
+	for _, name := range []string{"expect-count", "expect-min", "expect-max"} {
+		if v, _ := cmd.Flags().GetInt(name); cmd.Flags().Changed(name) && v < 0 {
+			return shared.NewExitError(2, fmt.Sprintf("--%s must not be negative", name))
+		}
+	}
+	set := 0
+	if a.FailIfNone {
+		set++
+	}
+	if a.Count >= 0 {
+		set++
+	}
+	if a.Min >= 0 || a.Max >= 0 {
+		set++
+	}
+	if set > 1 {
+		return shared.NewExitError(2, "use only one of --fail-if-none, --expect-count, or --expect-min/--expect-max")
+	}
+	if a.Min >= 0 && a.Max >= 0 && a.Min > a.Max {
+		return shared.NewExitError(2, fmt.Sprintf("--expect-min (%d) is greater than --expect-max (%d)", a.Min, a.Max))
+	}
+	return nil
+}
+
+// evaluate checks actual against the assertion, or returns nil when no
+// assertion flag was given. more reports that further matches were cut by
+// --limit, which fails any upper bound: the true count is unknown.
+func (a countAssertion) evaluate(actual int, more bool, noun string) *runAssertion {
+	result := &runAssertion{Actual: actual, AtLeast: more}
+	lower, upper := -1, -1
+	switch {
+	case a.FailIfNone:
+		result.Type, result.Expected, lower = "fail-if-none", 1, 1
+	case a.Count >= 0:
+		result.Type, result.Expected, lower, upper = "count", a.Count, a.Count, a.Count
+	case a.Min >= 0 && a.Max >= 0:
+		result.Type, result.Expected, result.ExpectedMax, lower, upper = "range", a.Min, a.Max, a.Min, a.Max
+	case a.Min >= 0:
+		result.Type, result.Expected, lower = "min", a.Min, a.Min
+	case a.Max >= 0:
+		result.Type, result.Expected, upper = "max", a.Max, a.Max
+	default:
+		return nil
+	}
+
+	switch {
+	case lower >= 0 && actual < lower:
+		result.Message = fmt.Sprintf("expected at least %d %s, got %d", lower, noun, actual)
+	case upper >= 0 && more:
+		result.Message = fmt.Sprintf("expected at most %d %s, got more than %d (raise --limit to count them all)", upper, noun, actual)
+	case upper >= 0 && actual > upper:
+		result.Message = fmt.Sprintf("expected at most %d %s, got %d", upper, noun, actual)
+	default:
+		result.Passed = true
+	}
+	if !result.Passed && a.Count >= 0 {
+		result.Message = fmt.Sprintf("expected exactly %d %s, got %d", a.Count, noun, actual)
+		if more {
+			result.Message = fmt.Sprintf("expected exactly %d %s, got more than %d (raise --limit to count them all)", a.Count, noun, actual)
+		}
+	}
+	return result
+}
+
+// assertRunList evaluates the assertion against the listing, counting groups
+// rather than runs when --group-by is active, and records the outcome in
+// the metadata.
+func assertRunList(output *runListOutput, opts runListOptions, a countAssertion) *runAssertion {
+	count, more, noun := len(output.Items), output.NextCursor != "", "runs"
+	if opts.GroupBy != "" {
+		count, more, noun = output.GroupCount, false, "groups"
+	}
+	result := a.evaluate(count, more, noun)
+	if result == nil {
+		return nil
+	}
+	if output.Metadata == nil {
+		output.Metadata = &runListMetadata{}
+	}
+	output.Metadata.Assertion = result
+	return result
+}
+
+// assertionError is the exit error for a failed assertion, or nil.
+func assertionError(result *runAssertion) error {
+	if result == nil || result.Passed {
+		return nil
+	}
+	return shared.NewExitError(assertionExitCode, "assertion failed: "+result.Message)
+}
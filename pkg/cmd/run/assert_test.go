@@ -0,0 +1,140 @@
+package run
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestCountAssertionEvaluate(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion countAssertion
+		actual    int
+		more      bool
+		wantType  string
+		passed    bool
+	}{
+		{"none unset", countAssertion{Count: -1, Min: -1, Max: -1}, 0, false, "", true},
+		{"fail-if-none empty", countAssertion{FailIfNone: true, Count: -1, Min: -1, Max: -1}, 0, false, "fail-if-none", false},
+		{"fail-if-none match", countAssertion{FailIfNone: true, Count: -1, Min: -1, Max: -1}, 2, false, "fail-if-none", true},
+		{"count exact", countAssertion{Count: 3, Min: -1, Max: -1}, 3, false, "count", true},
+		{"count off", countAssertion{Count: 3, Min: -1, Max: -1}, 2, false, "count", false},
+		{"count cut by limit", countAssertion{Count: 3, Min: -1, Max: -1}, 3, true, "count", false},
+		{"min met", countAssertion{Count: -1, Min: 2, Max: -1}, 2, false, "min", true},
+		{"min met past limit", countAssertion{Count: -1, Min: 2, Max: -1}, 2, true, "min", true},
+		{"min missed", countAssertion{Count: -1, Min: 2, Max: -1}, 1, false, "min", false},
+		{"max met", countAssertion{Count: -1, Min: -1, Max: 2}, 2, false, "max", true},
+		{"max exceeded", countAssertion{Count: -1, Min: -1, Max: 2}, 3, false, "max", false},
+		{"max unknown past limit", countAssertion{Count: -1, Min: -1, Max: 5}, 2, true, "max", false},
+		{"range inside", countAssertion{Count: -1, Min: 1, Max: 3}, 2, false, "range", true},
+		{"range above", countAssertion{Count: -1, Min: 1, Max: 3}, 4, false, "range", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.assertion.evaluate(tt.actual, tt.more, "runs")
+			if tt.wantType == "" {
+				if got != nil {
+					t.Fatalf("expected no assertion, got %+v", got)
+				}
+				return
+			}
+			if got == nil || got.Type != tt.wantType || got.Passed != tt.passed || got.Actual != tt.actual {
+				t.Fatalf("unexpected assertion %+v", got)
+			}
+			if !got.Passed && got.Message == "" {
+				t.Fatal("expected a message for a failed assertion")
+			}
+			if err := assertionError(got); (err == nil) != tt.passed {
+				t.Fatalf("expected error only for failures, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCountAssertionValidate(t *testing.T) {
+	tests := []struct {
+		args []string
+		code int
+	}{
+		{[]string{"--expect-min", "1", "--expect-max", "3"}, 0},
+		{[]string{"--fail-if-none", "--expect-count", "2"}, 2},
+		{[]string{"--expect-count", "2", "--expect-max", "3"}, 2},
+		{[]string{"--expect-min", "4", "--expect-max", "3"}, 2},
+		{[]string{"--expect-max", "-2"}, 2},
+	}
+	for _, tt := range tests {
+		var a countAssertion
+		cmd := &cobra.Command{Use: "test"}
+		addCountAssertionFlags(cmd, &a)
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("%v: parse flags: %v", tt.args, err)
+		}
+		if code := exitCode(a.validate(cmd)); code != tt.code {
+			t.Fatalf("%v: expected exit %d, got %d", tt.args, tt.code, code)
+		}
+	}
+}
+
+func TestAssertRunListCountsGroups(t *testing.T) {
+	builds := chartBuilds("api", "web", "api", "db")
+	opts := runListOptions{Limit: 2, GroupBy: "param.CHART", Aggregation: "count"}
+	out, _, err := processRunList("team/app", opts, builds, runListRequirements{Parameters: true})
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+
+	result := assertRunList(&out, opts, countAssertion{Count: 3, Min: -1, Max: -1})
+	if !result.Passed || result.Actual != 3 || result.AtLeast {
+		t.Fatalf("expected the assertion to count 3 groups, got %+v", result)
+	}
+	if out.Metadata == nil || out.Metadata.Assertion != result {
+		t.Fatalf("expected the assertion in metadata, got %+v", out.Metadata)
+	}
+
+	opts.GroupBy = ""
+	out, _, err = processRunList("team/app", opts, builds, runListRequirements{Parameters: true})
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if result := assertRunList(&out, opts, countAssertion{Count: -1, Min: -1, Max: 2}); result.Passed || !result.AtLeast {
+		t.Fatalf("expected runs cut by --limit to fail --expect-max, got %+v", result)
+	}
+}
+
+func TestRunListFailIfNone(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/api/json", map[string]any{
+		"builds": []map[string]any{{"number": 1, "result": "FAILURE", "timestamp": 1000}},
+	})
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"ls", "app", "--filter", "result=SUCCESS", "--fail-if-none", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	err := cmd.Execute()
+	if code := exitCode(err); code != assertionExitCode {
+		t.Fatalf("expected exit %d, got %d (%v)", assertionExitCode, code, err)
+	}
+	if !strings.Contains(err.Error(), "expected at least 1 runs, got 0") {
+		t.Fatalf("unexpected message %q", err.Error())
+	}
+	var out runListOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("expected the listing to be printed: %v\n%s", err, stdout.String())
+	}
+	if out.Metadata == nil || out.Metadata.Assertion == nil || out.Metadata.Assertion.Passed {
+		t.Fatalf("expected a failed assertion in metadata, got %+v", out.Metadata)
+	}
+}
@@ -0,0 +1,150 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const runStatusTree = "number,result,building,timestamp,duration,estimatedDuration"
+
+func newRunAttachCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		interval  time.Duration
+		showStage bool
+		retry     shared.PollRetry
+		fromStart bool
+		tail      int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "attach <jobPath> <buildNumber>",
+		Short: "Follow a run that is already running",
+		Long: `Follow a run as run start --follow would, for example after the jk process
+that was following it died. The log streams from its current end, so earlier
+output is not replayed unless --from-start is given, and the command exits
+with the run's result code (10 UNSTABLE, 11 FAILURE, 12 ABORTED, 13 NOT_BUILT).
+
+A run that has already finished prints the last --tail lines of its log and
+its result, then exits with the same code, so attaching twice is harmless.`,
+		Example: `  # Pick up where a followed run left off
+  jk run attach team/app 42
+
+  # Replay the whole log, then follow
+  jk run attach team/app 42 --from-start`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := retry.Validate(); err != nil {
+				return err
+			}
+			if tail < 0 || tail > maxLogTailLines {
+				return shared.NewExitError(2, fmt.Sprintf("--tail must be between 0 and %d", maxLogTailLines))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			num, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || num <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid build number %q", args[1]))
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			var detail runDetail
+			path := fmt.Sprintf("/%s/%d/api/json", jobpath.Encode(jobPath), num)
+			resp, err := client.Do(client.NewRequest().SetContext(ctx).SetQueryParam("tree", runStatusTree), http.MethodGet, path, &detail)
+			if err != nil {
+				return err
+			}
+			if err := shared.CheckResponse(resp, fmt.Sprintf("run %s #%d", jobPath, num)); err != nil {
+				return err
+			}
+
+			streamLogs := !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd)
+			if !detail.Building {
+				result := strings.ToUpper(detail.Result)
+				if result == "" {
+					result = "SUCCESS"
+				}
+				if streamLogs {
+					if err := writeFinishedLog(ctx, cmd, client, jobPath, num, fromStart, tail); err != nil {
+						return err
+					}
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Run #%d completed with status %s\n", num, result)
+				}
+				return finishFollowedRun(cmd, client, jobPath, num, result, nil)
+			}
+
+			opts := followOptions{
+				Interval:  interval,
+				ShowStage: showStage,
+				Retry:     retry.WithNotes(cmd, cmd.ErrOrStderr()),
+				Progress:  followProgress(cmd, f),
+			}
+			if streamLogs && !fromStart {
+				size, err := shared.ProgressiveLogSize(ctx, client, jobPath, int(num))
+				if err != nil {
+					return err
+				}
+				if size > 0 {
+					opts.LogOffset = size
+					if !cmdutil.Quiet(cmd) {
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Attached to %s #%d; skipping %d bytes of earlier output (--from-start replays them)\n", jobPath, num, size)
+					}
+				}
+			}
+			return followRun(cmd, client, jobPath, num, opts)
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().DurationVar(&interval, "interval", 500*time.Millisecond, "Polling interval for the log")
+	cmd.Flags().BoolVar(&showStage, "show-stage", false, "Include the current pipeline stage in heartbeats (extra requests)")
+	shared.AddPollRetryFlags(cmd, &retry)
+	cmd.Flags().BoolVar(&fromStart, "from-start", false, "Stream the log from its beginning")
+	cmd.Flags().IntVar(&tail, "tail", 50, "Lines of log to print when the run has already finished")
+	cmdutil.SetExitCodes(cmd, followExitCodes())
+	return cmd
+}
+
+// writeFinishedLog prints the log of a finished run: all of it with
+// fromStart, otherwise its last tail lines.
+func writeFinishedLog(ctx context.Context, cmd *cobra.Command, client shared.Doer, jobPath string, num int64, fromStart bool, tail int) error {
+	out := cmd.OutOrStdout()
+	if fromStart {
+		if err := shared.StreamProgressiveLog(ctx, client, jobPath, int(num), time.Second, out, nil); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(out)
+		return nil
+	}
+	if tail == 0 {
+		return nil
+	}
+	lines, err := fetchConsoleTail(ctx, client, jobPath, num, tail)
+	if err != nil {
+		return err
+	}
+	for _, line := range lines {
+		_, _ = fmt.Fprintln(out, line)
+	}
+	return nil
+}
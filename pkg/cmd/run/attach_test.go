@@ -0,0 +1,117 @@
+package run
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+// attachedRun serves run #12 of app as still building until its log has been
+// read past the first line, which Jenkins reports as 4 bytes long.
+type attachedRun struct {
+	*fakejenkins.Server
+	tailed atomic.Bool
+}
+
+func (h *attachedRun) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/job/app/12/api/json":
+		building := !h.tailed.Load()
+		w.Header().Set("Content-Type", "application/json")
+		if building {
+			_, _ = w.Write([]byte(`{"number":12,"building":true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"number":12,"building":false,"result":"UNSTABLE"}`))
+	case "/job/app/12/logText/progressiveText":
+		if r.URL.Query().Get("start") == "0" {
+			w.Header().Set("X-Text-Size", "4")
+			w.Header().Set("X-More-Data", "true")
+			_, _ = w.Write([]byte("old\n"))
+			return
+		}
+		h.tailed.Store(true)
+		w.Header().Set("X-Text-Size", "8")
+		_, _ = w.Write([]byte("new\n"))
+	default:
+		h.Server.ServeHTTP(w, r)
+	}
+}
+
+func executeRunAttach(t *testing.T, handler http.Handler, args ...string) (string, string, error) {
+	t.Helper()
+	prev := runPollInterval
+	runPollInterval = time.Millisecond
+	t.Cleanup(func() { runPollInterval = prev })
+
+	f, stdout, stderr := fakejenkins.Factory(jenkinstest.NewClient(t, handler))
+	cmd := NewCmdRun(f)
+	cmd.SetArgs(append([]string{"attach"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestRunAttachSkipsEarlierOutput(t *testing.T) {
+	handler := &attachedRun{Server: fakejenkins.New(t)}
+	stdout, stderr, err := executeRunAttach(t, handler, "app", "12", "--interval", "1ms")
+	if code := exitCode(err); code != 10 {
+		t.Fatalf("expected exit 10 for UNSTABLE, got %d (%v)", code, err)
+	}
+	if strings.Contains(stdout, "old") || !strings.Contains(stdout, "new\n") {
+		t.Fatalf("expected only the new output, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "Run #12 completed with status UNSTABLE") {
+		t.Fatalf("expected the result line, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "skipping 4 bytes of earlier output") {
+		t.Fatalf("expected the skipped-output note, got %q", stderr)
+	}
+}
+
+func TestRunAttachFromStartReplaysLog(t *testing.T) {
+	handler := &attachedRun{Server: fakejenkins.New(t)}
+	stdout, stderr, err := executeRunAttach(t, handler, "app", "12", "--interval", "1ms", "--from-start")
+	if code := exitCode(err); code != 10 {
+		t.Fatalf("expected exit 10 for UNSTABLE, got %d (%v)", code, err)
+	}
+	if !strings.Contains(stdout, "old\nnew\n") {
+		t.Fatalf("expected the whole log, got %q", stdout)
+	}
+	if strings.Contains(stderr, "skipping") {
+		t.Fatalf("expected no skipped-output note, got %q", stderr)
+	}
+}
+
+func TestRunAttachFinishedRunPrintsTail(t *testing.T) {
+	server := fakejenkins.New(t)
+	server.Handle(http.MethodGet, "/job/app/12/api/json", http.StatusOK, `{"number":12,"building":false,"result":"FAILURE"}`)
+	server.Handle(http.MethodGet, "/job/app/12/consoleText", http.StatusOK, "one\ntwo\nthree\n")
+
+	stdout, _, err := executeRunAttach(t, server, "app", "12", "--tail", "2")
+	if code := exitCode(err); code != 11 {
+		t.Fatalf("expected exit 11 for FAILURE, got %d (%v)", code, err)
+	}
+	if want := "two\nthree\nRun #12 completed with status FAILURE\n"; stdout != want {
+		t.Fatalf("stdout = %q, want %q", stdout, want)
+	}
+	if got := len(server.RequestsTo(http.MethodGet, "/job/app/12/logText/progressiveText")); got != 0 {
+		t.Fatalf("expected no log streaming for a finished run, got %d requests", got)
+	}
+}
+
+func TestRunAttachMissingRun(t *testing.T) {
+	server := fakejenkins.New(t)
+	_, _, err := executeRunAttach(t, server, "app", "12")
+	if code := exitCode(err); code != 3 {
+		t.Fatalf("expected exit 3 for a missing run, got %d (%v)", code, err)
+	}
+}
@@ -0,0 +1,111 @@
+package run
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// jobLabelConfig is the part of a job's config.xml that restricts where it
+// runs. Freestyle and matrix projects write assignedNode; some older jobs
+// write label.
+type jobLabelConfig struct {
+	AssignedNode string `xml:"assignedNode"`
+	Label        string `xml:"label"`
+	CanRoam      bool   `xml:"canRoam"`
+}
+
+// fetchAssignedLabel returns the label expression a job is restricted to,
+// or "" when it may run anywhere. Pipeline jobs choose their agents in the
+// Jenkinsfile and report "". Users without Job/Configure cannot read
+// config.xml; for them the label comes from the job API.
+func fetchAssignedLabel(ctx context.Context, client shared.Doer, jobPath string) (string, error) {
+	data, err := shared.FetchJobConfig(ctx, client, jobPath)
+	if err == nil {
+		return parseAssignedLabel(data)
+	}
+	switch shared.ExitCode(err) {
+	case 3:
+		return "", nil
+	case 5:
+		return fetchLabelExpression(ctx, client, jobPath)
+	}
+	return "", err
+}
+
+// fetchLabelExpression reads the label restriction the job API exports as
+// labelExpression; it is null for jobs that may run anywhere.
+func fetchLabelExpression(ctx context.Context, client shared.Doer, jobPath string) (string, error) {
+	var job struct {
+		LabelExpression string `json:"labelExpression"`
+	}
+	req := client.NewRequest().SetContext(ctx).SetQueryParam("tree", "labelExpression")
+	resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath)), &job)
+	if err != nil {
+		return "", err
+	}
+	if err := shared.CheckResponse(resp, fmt.Sprintf("job %s", jobPath)); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(job.LabelExpression), nil
+}
+
+func parseAssignedLabel(data []byte) (string, error) {
+	var cfg jobLabelConfig
+	if err := xml.Unmarshal(shared.StripXMLDeclaration(data), &cfg); err != nil {
+		return "", fmt.Errorf("parse job config: %w", err)
+	}
+	if cfg.CanRoam {
+		return "", nil
+	}
+	if label := strings.TrimSpace(cfg.AssignedNode); label != "" {
+		return label, nil
+	}
+	return strings.TrimSpace(cfg.Label), nil
+}
+
+// checkLabelCapacity implements --require-capacity: it refuses to trigger a
+// job restricted to a label with no online executors, since the build would
+// wait in the queue until an agent comes online. queueAnyway turns the
+// refusal into a warning. Label expressions are not evaluated; they only
+// warn. Warnings go to stderr, which is nil under --quiet.
+func checkLabelCapacity(ctx context.Context, client shared.Doer, jobPath string, queueAnyway bool, stderr io.Writer) error {
+	label, err := fetchAssignedLabel(ctx, client, jobPath)
+	if err != nil {
+		return err
+	}
+	if label == "" {
+		return nil
+	}
+	if shared.IsLabelExpression(label) {
+		cmdutil.Fwarnf(stderr, "%s is restricted to the label expression %q; --require-capacity only checks single labels", jobPath, label)
+		return nil
+	}
+
+	status, err := shared.FetchLabelStatus(ctx, client, label)
+	if err != nil {
+		return err
+	}
+	if status != nil && !status.NoOnlineExecutors {
+		return nil
+	}
+
+	var msg string
+	if status != nil {
+		msg = fmt.Sprintf("label %s has no online executors (%d online, %d busy)", label, status.TotalExecutors, status.BusyExecutors)
+	} else {
+		msg = fmt.Sprintf("label %s is unknown to Jenkins (0 online executors)", label)
+	}
+	if queueAnyway {
+		cmdutil.Fwarnf(stderr, "%s; triggering %s anyway", msg, jobPath)
+		return nil
+	}
+	return shared.NewExitError(2, fmt.Sprintf("%s; %s would wait in the queue until one comes online (pass --queue-anyway to trigger anyway)", msg, jobPath))
+}
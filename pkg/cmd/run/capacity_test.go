@@ -0,0 +1,147 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestCheckLabelCapacity(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixture     string
+		label       map[string]any
+		queueAnyway bool
+		code        int
+		msg         string
+		warning     string
+		labelLookup bool
+	}{
+		{
+			name:        "labeled with online executors",
+			fixture:     "capacity_labeled_config.xml",
+			label:       map[string]any{"busyExecutors": 2, "totalExecutors": 2},
+			labelLookup: true,
+		},
+		{
+			name:        "labeled without online executors",
+			fixture:     "capacity_labeled_config.xml",
+			label:       map[string]any{"busyExecutors": 0, "totalExecutors": 0},
+			code:        2,
+			msg:         "label gpu has no online executors (0 online, 0 busy); team/app would wait in the queue until one comes online (pass --queue-anyway to trigger anyway)",
+			labelLookup: true,
+		},
+		{
+			name:        "unknown label",
+			fixture:     "capacity_labeled_config.xml",
+			code:        2,
+			msg:         "label gpu is unknown to Jenkins",
+			labelLookup: true,
+		},
+		{
+			name:        "queue anyway",
+			fixture:     "capacity_labeled_config.xml",
+			label:       map[string]any{"busyExecutors": 0, "totalExecutors": 0},
+			queueAnyway: true,
+			warning:     "warning: label gpu has no online executors (0 online, 0 busy); triggering team/app anyway\n",
+			labelLookup: true,
+		},
+		{
+			name:    "unlabeled",
+			fixture: "capacity_unlabeled_config.xml",
+		},
+		{
+			name:    "label expression",
+			fixture: "capacity_expression_config.xml",
+			warning: "warning: team/app is restricted to the label expression \"linux && docker\"; --require-capacity only checks single labels\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := fakejenkins.NewClient(t)
+			server.HandleFixture(http.MethodGet, "/job/team/job/app/config.xml", tt.fixture)
+			if tt.label != nil {
+				server.HandleJSON(http.MethodGet, "/label/gpu/api/json", tt.label)
+			}
+
+			var stderr bytes.Buffer
+			err := checkLabelCapacity(context.Background(), client, "team/app", tt.queueAnyway, &stderr)
+			if tt.code == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			} else {
+				var exitErr *cmdutil.ExitError
+				if !errors.As(err, &exitErr) || exitErr.Code != tt.code {
+					t.Fatalf("expected exit code %d, got %v", tt.code, err)
+				}
+				if !strings.Contains(exitErr.Msg, tt.msg) {
+					t.Fatalf("expected %q in %q", tt.msg, exitErr.Msg)
+				}
+			}
+			if stderr.String() != tt.warning {
+				t.Fatalf("stderr = %q, want %q", stderr.String(), tt.warning)
+			}
+			if got := len(server.RequestsTo(http.MethodGet, "/label/gpu/api/json")) > 0; got != tt.labelLookup {
+				t.Fatalf("label looked up = %v, want %v", got, tt.labelLookup)
+			}
+		})
+	}
+}
+
+func TestRunStartRequireCapacity(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/releases/job/deploy/api/json", map[string]any{"buildable": true})
+	server.HandleFixture(http.MethodGet, "/job/releases/job/deploy/config.xml", "capacity_labeled_config.xml")
+	server.HandleJSON(http.MethodGet, "/label/gpu/api/json", map[string]any{"busyExecutors": 0, "totalExecutors": 0})
+	server.HandleHeaders(http.MethodPost, "/job/releases/job/deploy/build", http.StatusCreated,
+		http.Header{"Location": []string{"/queue/item/5/"}})
+
+	_, err := executeRunStart(t, client, "--require-capacity")
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit code 2, got %v", err)
+	}
+	if len(server.RequestsTo(http.MethodPost, "/job/releases/job/deploy/build")) != 0 {
+		t.Fatal("expected no build to be triggered")
+	}
+
+	if _, err := executeRunStart(t, client, "--require-capacity", "--queue-anyway"); err != nil {
+		t.Fatalf("run start --queue-anyway: %v", err)
+	}
+	if len(server.RequestsTo(http.MethodPost, "/job/releases/job/deploy/build")) != 1 {
+		t.Fatal("expected --queue-anyway to trigger the build")
+	}
+
+	_, err = executeRunStart(t, client, "--queue-anyway")
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected --queue-anyway alone to exit 2, got %v", err)
+	}
+}
+
+func TestCheckLabelCapacityWithoutConfigAccess(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/app/config.xml", http.StatusForbidden, "")
+	server.HandleJSON(http.MethodGet, "/job/team/job/app/api/json", map[string]any{"labelExpression": "gpu"})
+	server.HandleJSON(http.MethodGet, "/label/gpu/api/json", map[string]any{"busyExecutors": 0, "totalExecutors": 0})
+
+	var stderr bytes.Buffer
+	err := checkLabelCapacity(context.Background(), client, "team/app", false, &stderr)
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit code 2, got %v", err)
+	}
+	if !strings.Contains(exitErr.Msg, "label gpu has no online executors") {
+		t.Fatalf("unexpected message %q", exitErr.Msg)
+	}
+	if got := server.LastRequest(http.MethodGet, "/job/team/job/app/api/json").Query.Get("tree"); got != "labelExpression" {
+		t.Fatalf("tree = %q, want labelExpression", got)
+	}
+}
@@ -0,0 +1,277 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	defaultCauseDepth = 5
+
+	causeRunTree = "number,url,result,building," +
+		"actions[causes[_class,shortDescription,userId,userName,upstreamProject,upstreamBuild,upstreamUrl]," +
+		"lastBuiltRevision[SHA1,branch[name]],buildsByBranchName,remoteUrls]," +
+		"changeSet[items[commitId,authorEmail,author[fullName]]]"
+)
+
+type runCausesOutput struct {
+	SchemaVersion string        `json:"schemaVersion"`
+	Run           *causeRunNode `json:"run"`
+}
+
+// causeRunNode is one run in the trigger chain. Causes that point at an
+// upstream run carry that run as a nested node.
+type causeRunNode struct {
+	JobPath string          `json:"jobPath"`
+	Number  int64           `json:"number"`
+	URL     string          `json:"url,omitempty"`
+	Status  string          `json:"status,omitempty"`
+	Result  string          `json:"result,omitempty"`
+	SCM     *runSCMInfo     `json:"scm,omitempty"`
+	Causes  []causeTreeItem `json:"causes"`
+	Error   string          `json:"error,omitempty"`
+}
+
+type causeTreeItem struct {
+	runCause
+	Run *causeRunNode `json:"run,omitempty"`
+	// Cycle marks an upstream reference back to a run already on this path.
+	Cycle bool `json:"cycle,omitempty"`
+	// Truncated marks an upstream reference not followed because of --max-depth.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type causeFetchFunc func(jobPath string, number int64) (*runDetail, error)
+
+func newRunCausesCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		followUpstream bool
+		maxDepth       int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "causes <jobPath> <buildNumber>",
+		Short: "Show what triggered a run",
+		Long: `Show the causes recorded for a run. With --follow-upstream, runs started by
+another build are traced back through each upstream run until the root cause
+(a user, timer, or SCM change) or --max-depth is reached. Trigger loops are
+detected and cut.`,
+		Example: `  # Why did this deploy run?
+  jk run causes team/deploy 42
+
+  # Trace the full trigger chain back to the originating commit
+  jk run causes team/deploy 42 --follow-upstream --json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			num, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || num <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid build number %q", args[1]))
+			}
+			if maxDepth < 1 {
+				return shared.NewExitError(2, "--max-depth must be at least 1")
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			depth := 0
+			if followUpstream {
+				depth = maxDepth
+			}
+
+			root, err := buildCauseTree(causeFetcher(ctx, client), jobpath.Normalize(jobPath), num, depth)
+			if err != nil {
+				return err
+			}
+
+			output := runCausesOutput{SchemaVersion: "1.0", Run: root}
+			return shared.PrintOutput(cmd, output, func() error {
+				renderCauseTree(cmd.OutOrStdout(), root, "")
+				return nil
+			})
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().BoolVar(&followUpstream, "follow-upstream", false, "Recursively follow upstream causes to the root trigger")
+	cmd.Flags().IntVar(&maxDepth, "max-depth", defaultCauseDepth, "Maximum upstream hops to follow")
+	return cmd
+}
+
+func causeFetcher(ctx context.Context, client shared.Doer) causeFetchFunc {
+	return func(jobPath string, number int64) (*runDetail, error) {
+		var detail runDetail
+		path := fmt.Sprintf("/%s/%d/api/json", jobpath.Encode(jobPath), number)
+		req := client.NewRequest().SetContext(ctx).SetQueryParam("tree", causeRunTree)
+		resp, err := client.Do(req, http.MethodGet, path, &detail)
+		if err != nil {
+			return nil, err
+		}
+		if err := shared.CheckResponse(resp, fmt.Sprintf("run %s #%d", jobPath, number)); err != nil {
+			return nil, err
+		}
+		return &detail, nil
+	}
+}
+
+// buildCauseTree fetches the run and, while maxDepth allows, each upstream run
+// it references. Failures fetching the requested run are returned; failures
+// further up the chain are recorded on the node so the known part of the chain
+// is still shown. visited only tracks the current path, so two causes sharing
+// an ancestor are both expanded while trigger loops are cut.
+func buildCauseTree(fetch causeFetchFunc, jobPath string, number int64, maxDepth int) (*causeRunNode, error) {
+	detail, err := fetch(jobPath, number)
+	if err != nil {
+		return nil, err
+	}
+	visited := map[string]struct{}{causeRunKey(jobPath, number): {}}
+	return expandCauseRun(fetch, jobPath, detail, 0, maxDepth, visited), nil
+}
+
+func expandCauseRun(fetch causeFetchFunc, jobPath string, detail *runDetail, depth, maxDepth int, visited map[string]struct{}) *causeRunNode {
+	node := &causeRunNode{
+		JobPath: jobPath,
+		Number:  detail.Number,
+		URL:     detail.URL,
+		Status:  statusFromFlags(detail.Building),
+		Result:  resultForList(detail.Result, detail.Building),
+		SCM:     extractSCMInfo(detail.Actions, detail.ChangeSet),
+		Causes:  []causeTreeItem{},
+	}
+
+	for _, cause := range extractCauses(detail.Actions) {
+		item := causeTreeItem{runCause: cause}
+		upstream := cause.Upstream
+		if upstream == nil || upstream.Build <= 0 || maxDepth == 0 {
+			node.Causes = append(node.Causes, item)
+			continue
+		}
+
+		key := causeRunKey(upstream.Project, upstream.Build)
+		switch {
+		case hasKey(visited, key):
+			item.Cycle = true
+		case depth >= maxDepth:
+			item.Truncated = true
+		default:
+			visited[key] = struct{}{}
+			parent, err := fetch(upstream.Project, upstream.Build)
+			if err != nil {
+				item.Run = &causeRunNode{
+					JobPath: upstream.Project,
+					Number:  upstream.Build,
+					Causes:  []causeTreeItem{},
+					Error:   shared.ErrorMessage(err),
+				}
+			} else {
+				item.Run = expandCauseRun(fetch, upstream.Project, parent, depth+1, maxDepth, visited)
+			}
+			delete(visited, key)
+		}
+		node.Causes = append(node.Causes, item)
+	}
+
+	return node
+}
+
+func causeRunKey(jobPath string, number int64) string {
+	return fmt.Sprintf("%s#%d", jobpath.Normalize(jobPath), number)
+}
+
+func hasKey(set map[string]struct{}, key string) bool {
+	_, ok := set[key]
+	return ok
+}
+
+func renderCauseTree(w io.Writer, node *causeRunNode, indent string) {
+	label := node.Result
+	if label == "" {
+		label = strings.ToUpper(node.Status)
+	}
+	line := fmt.Sprintf("%s%s #%d", indent, node.JobPath, node.Number)
+	if label != "" {
+		line += " " + label
+	}
+	_, _ = fmt.Fprintln(w, line)
+
+	if node.Error != "" {
+		_, _ = fmt.Fprintf(w, "%s  error: %s\n", indent, node.Error)
+		return
+	}
+	if len(node.Causes) == 0 {
+		_, _ = fmt.Fprintf(w, "%s  (no causes recorded)\n", indent)
+		return
+	}
+
+	for _, item := range node.Causes {
+		text := describeCause(item.runCause)
+		if item.Type == "scm" && node.SCM != nil && node.SCM.Commit != "" {
+			text += fmt.Sprintf(" (commit %s", shortCommit(node.SCM.Commit))
+			if node.SCM.Branch != "" {
+				text += " on " + node.SCM.Branch
+			}
+			text += ")"
+		}
+		switch {
+		case item.Cycle:
+			text += " [cycle detected]"
+		case item.Truncated:
+			text += " [max depth reached]"
+		}
+		_, _ = fmt.Fprintf(w, "%s  <- %s\n", indent, text)
+		if item.Run != nil {
+			renderCauseTree(w, item.Run, indent+"     ")
+		}
+	}
+}
+
+// describeCause renders a cause as "type: detail" for human output.
+func describeCause(c runCause) string {
+	kind := c.Type
+	if kind == "" {
+		kind = "other"
+	}
+	switch {
+	case c.Upstream != nil:
+		return fmt.Sprintf("%s: %s #%d", kind, c.Upstream.Project, c.Upstream.Build)
+	case c.UserName != "" || c.UserID != "":
+		user := c.UserName
+		if user == "" {
+			user = c.UserID
+		}
+		return fmt.Sprintf("%s: %s", kind, user)
+	case c.Description != "":
+		return fmt.Sprintf("%s: %s", kind, c.Description)
+	default:
+		return kind
+	}
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
@@ -0,0 +1,118 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+)
+
+func TestExtractCausesUpstream(t *testing.T) {
+	actions := []map[string]any{{
+		"causes": []any{
+			map[string]any{
+				"_class":           "hudson.model.Cause$UpstreamCause",
+				"shortDescription": "Started by upstream project \"team/build\" build number 17",
+				"upstreamProject":  "team/build",
+				"upstreamBuild":    float64(17),
+				"upstreamUrl":      "job/team/job/build/",
+			},
+		},
+	}}
+
+	causes := extractCauses(actions)
+	if len(causes) != 1 {
+		t.Fatalf("expected 1 cause, got %d", len(causes))
+	}
+	up := causes[0].Upstream
+	if causes[0].Type != "upstream" || up == nil {
+		t.Fatalf("expected upstream cause, got %+v", causes[0])
+	}
+	if up.Project != "team/build" || up.Build != 17 || up.URL != "job/team/job/build/" {
+		t.Fatalf("unexpected upstream %+v", up)
+	}
+}
+
+func upstreamRunJSON(number int64, project string, build int64) string {
+	return fmt.Sprintf(`{"number":%d,"result":"SUCCESS","actions":[{"causes":[{"_class":"hudson.model.Cause$UpstreamCause","upstreamProject":%q,"upstreamBuild":%d}]}]}`, number, project, build)
+}
+
+func TestBuildCauseTreeFollowsChainToRoot(t *testing.T) {
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/job/team/job/deploy/42/api/json": upstreamRunJSON(42, "team/build", 17),
+		"/job/team/job/build/17/api/json":  upstreamRunJSON(17, "team/app", 99),
+		"/job/team/job/app/99/api/json": `{"number":99,"result":"SUCCESS","actions":[
+			{"causes":[{"_class":"hudson.triggers.SCMTrigger$SCMTriggerCause","shortDescription":"Started by an SCM change"}]},
+			{"lastBuiltRevision":{"SHA1":"abc1234def5678","branch":[{"name":"origin/main"}]}}]}`,
+	}))
+
+	root, err := buildCauseTree(causeFetcher(context.Background(), client), "team/deploy", 42, defaultCauseDepth)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	build := root.Causes[0].Run
+	if build == nil || build.JobPath != "team/build" || build.Number != 17 {
+		t.Fatalf("expected team/build #17 upstream, got %+v", build)
+	}
+	app := build.Causes[0].Run
+	if app == nil || app.JobPath != "team/app" {
+		t.Fatalf("expected team/app #99 upstream, got %+v", app)
+	}
+	if app.Causes[0].Type != "scm" || app.Causes[0].Run != nil {
+		t.Fatalf("expected scm root cause, got %+v", app.Causes[0])
+	}
+
+	var out bytes.Buffer
+	renderCauseTree(&out, root, "")
+	if !strings.Contains(out.String(), "scm: Started by an SCM change (commit abc1234def56 on origin/main)") {
+		t.Fatalf("expected root commit in output:\n%s", out.String())
+	}
+}
+
+func TestBuildCauseTreeCutsCyclesAndDepth(t *testing.T) {
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/job/a/1/api/json": upstreamRunJSON(1, "b", 2),
+		"/job/b/2/api/json": upstreamRunJSON(2, "a", 1),
+		"/job/c/1/api/json": upstreamRunJSON(1, "c", 2),
+		"/job/c/2/api/json": upstreamRunJSON(2, "c", 3),
+		"/job/c/3/api/json": upstreamRunJSON(3, "c", 4),
+	}))
+	fetch := causeFetcher(context.Background(), client)
+
+	root, err := buildCauseTree(fetch, "a", 1, defaultCauseDepth)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	back := root.Causes[0].Run.Causes[0]
+	if !back.Cycle || back.Run != nil {
+		t.Fatalf("expected cycle back to a #1 to be cut, got %+v", back)
+	}
+
+	root, err = buildCauseTree(fetch, "c", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := root.Causes[0].Run.Causes[0].Run.Causes[0]
+	if !last.Truncated || last.Run != nil {
+		t.Fatalf("expected chain to stop at max depth, got %+v", last)
+	}
+}
+
+func TestBuildCauseTreeWithoutFollowing(t *testing.T) {
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/job/a/1/api/json": upstreamRunJSON(1, "b", 2),
+	}))
+
+	root, err := buildCauseTree(causeFetcher(context.Background(), client), "a", 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	item := root.Causes[0]
+	if item.Upstream == nil || item.Run != nil || item.Truncated {
+		t.Fatalf("expected unfollowed upstream cause, got %+v", item)
+	}
+}
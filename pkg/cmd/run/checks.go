@@ -0,0 +1,130 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+// checksExitCode is returned by `run view --checks` when a required stage is
+// missing or did not pass, whatever the run result.
+const checksExitCode = 16
+
+// Statuses of a required stage in a --checks summary.
+const (
+	checkPass    = "pass"
+	checkFail    = "fail"
+	checkSkipped = "skipped"
+	checkPending = "pending"
+	checkMissing = "missing"
+)
+
+// runCheck is the gate status of one required stage. StageStatus is the raw
+// wfapi status of the matching stage; a missing stage lists the run's actual
+// stage names instead, so the required name can be corrected.
+type runCheck struct {
+	Stage           string   `json:"stage"`
+	Status          string   `json:"status"`
+	StageStatus     string   `json:"stageStatus,omitempty"`
+	AvailableStages []string `json:"availableStages,omitempty"`
+}
+
+// parseRequiredStages trims and de-duplicates --checks names, keeping the
+// first spelling of each; an empty name exits 2.
+func parseRequiredStages(values []string) ([]string, error) {
+	var names []string
+	for _, value := range values {
+		name := strings.TrimSpace(value)
+		if name == "" {
+			return nil, shared.NewExitError(2, "--checks requires stage names")
+		}
+		if !matchesStage(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// evaluateChecks matches each required stage case-insensitively against the
+// run's stages and returns the checks with the overall verdict, "pass" only
+// when every one passed. When a name matches several stages, the first one
+// that did not succeed decides its status.
+func evaluateChecks(required []string, stages []wfapiStage) ([]runCheck, string) {
+	var names []string
+	for _, stage := range stages {
+		names = append(names, stage.Name)
+	}
+	verdict := checkPass
+	checks := make([]runCheck, 0, len(required))
+	for _, name := range required {
+		check := runCheck{Stage: name, Status: checkMissing}
+		for _, stage := range stages {
+			if !strings.EqualFold(strings.TrimSpace(stage.Name), name) {
+				continue
+			}
+			status := strings.ToUpper(strings.TrimSpace(stage.Status))
+			if check.Status == checkMissing || check.Status == checkPass {
+				check.Status = checkStatus(status)
+				check.StageStatus = status
+			}
+		}
+		if check.Status == checkMissing {
+			check.AvailableStages = names
+		}
+		if check.Status != checkPass {
+			verdict = checkFail
+		}
+		checks = append(checks, check)
+	}
+	return checks, verdict
+}
+
+func checkStatus(stageStatus string) string {
+	switch stageStatus {
+	case "SUCCESS":
+		return checkPass
+	case "NOT_EXECUTED", "NOT_BUILT", "SKIPPED":
+		return checkSkipped
+	case "IN_PROGRESS", "PAUSED_PENDING_INPUT", "QUEUED":
+		return checkPending
+	default:
+		return checkFail
+	}
+}
+
+// checksError is the exit error for a failed gate, or nil when every check
+// passed or none were requested. The summary has already been printed, so it
+// carries no message.
+func checksError(verdict string) error {
+	if verdict != checkFail {
+		return nil
+	}
+	return shared.NewExitError(checksExitCode, "")
+}
+
+func renderRunChecks(w io.Writer, checks []runCheck) {
+	_, _ = fmt.Fprintln(w, "Checks:")
+	failed := 0
+	for _, check := range checks {
+		line := fmt.Sprintf("  %-8s %s", check.Status, check.Stage)
+		switch {
+		case check.Status == checkMissing && len(check.AvailableStages) > 0:
+			line += fmt.Sprintf(" (stages: %s)", strings.Join(check.AvailableStages, ", "))
+		case check.Status == checkMissing:
+			line += " (the run has no stages)"
+		case check.Status != checkPass:
+			line += fmt.Sprintf(" (%s)", check.StageStatus)
+		}
+		if check.Status != checkPass {
+			failed++
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	if failed == 0 {
+		_, _ = fmt.Fprintf(w, "Verdict: pass (%d required stages passed)\n", len(checks))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Verdict: fail (%d of %d required stages did not pass)\n", failed, len(checks))
+}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestEvaluateChecks(t *testing.T) {
+	stages := []wfapiStage{
+		{Name: "Checkout", Status: "SUCCESS"},
+		{Name: "Build", Status: "SUCCESS"},
+		{Name: "Test", Status: "SUCCESS"},
+		{Name: "Test", Status: "FAILED"},
+		{Name: "Deploy", Status: "NOT_EXECUTED"},
+	}
+
+	checks, verdict := evaluateChecks([]string{"build", "TEST", "Deploy", "Security-Scan"}, stages)
+	if verdict != checkFail {
+		t.Fatalf("expected verdict fail, got %s", verdict)
+	}
+	want := []string{checkPass, checkFail, checkSkipped, checkMissing}
+	for i, check := range checks {
+		if check.Status != want[i] {
+			t.Fatalf("check %s: expected %s, got %s", check.Stage, want[i], check.Status)
+		}
+	}
+	if strings.Join(checks[3].AvailableStages, ",") != "Checkout,Build,Test,Test,Deploy" {
+		t.Fatalf("expected the run's stage names for a missing stage, got %v", checks[3].AvailableStages)
+	}
+
+	if _, verdict := evaluateChecks([]string{"Build", "checkout"}, stages); verdict != checkPass {
+		t.Fatalf("expected verdict pass, got %s", verdict)
+	}
+}
+
+func TestParseRequiredStages(t *testing.T) {
+	names, err := parseRequiredStages([]string{" Build", "test", "BUILD"})
+	if err != nil || strings.Join(names, ",") != "Build,test" {
+		t.Fatalf("expected de-duplicated names, got %v (%v)", names, err)
+	}
+	if _, err := parseRequiredStages([]string{"Build", " "}); exitCode(err) != 2 {
+		t.Fatalf("expected exit 2 for an empty stage name, got %v", err)
+	}
+}
+
+// executeRunViewChecks views successful run #7 of app, whose wfapi/describe
+// lists stages; nil stages leave wfapi unavailable.
+func executeRunViewChecks(t *testing.T, stages []map[string]any, args ...string) (string, error) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/7/api/json", map[string]any{"number": 7, "result": "SUCCESS"})
+	if stages != nil {
+		server.HandleJSON(http.MethodGet, "/job/app/7/wfapi/describe", map[string]any{"stages": stages})
+	}
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(append([]string{"view", "app", "7"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestRunViewChecksFailSuccessfulRun(t *testing.T) {
+	stages := []map[string]any{{"name": "Build", "status": "SUCCESS"}, {"name": "Test", "status": "SUCCESS"}}
+	stdout, err := executeRunViewChecks(t, stages, "--checks", "build,Security-Scan", "--json")
+	if code := exitCode(err); code != checksExitCode {
+		t.Fatalf("expected exit %d, got %d (%v)", checksExitCode, code, err)
+	}
+	var out runDetailOutput
+	if err := json.Unmarshal([]byte(stdout), &out); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout)
+	}
+	if out.ChecksVerdict != checkFail || len(out.Checks) != 2 || out.Checks[0].Status != checkPass || out.Checks[1].Status != checkMissing {
+		t.Fatalf("unexpected checks %+v (%s)", out.Checks, out.ChecksVerdict)
+	}
+}
+
+func TestRunViewChecksPass(t *testing.T) {
+	stdout, err := executeRunViewChecks(t, []map[string]any{{"name": "Build", "status": "SUCCESS"}}, "--checks", "Build")
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !strings.Contains(stdout, "pass     Build") || !strings.Contains(stdout, "Verdict: pass") {
+		t.Fatalf("expected a passing gate summary, got %q", stdout)
+	}
+}
+
+func TestRunViewChecksWithoutStageData(t *testing.T) {
+	_, err := executeRunViewChecks(t, nil, "--checks", "Build")
+	if code := exitCode(err); code != 8 {
+		t.Fatalf("expected exit 8 without wfapi, got %d (%v)", code, err)
+	}
+}
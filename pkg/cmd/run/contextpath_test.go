@@ -0,0 +1,59 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+// These controllers are served under /jenkins and hand out queue URLs that
+// name an internal host, as Jenkins does behind a reverse proxy. Requests that
+// drop the context path get 404s from the fake.
+
+func TestContextPathTriggerAndQueuePolling(t *testing.T) {
+	useFastQueuePolling(t)
+	server, client := fakejenkins.NewClientAt(t, "/jenkins")
+	server.EnableCrumb("Jenkins-Crumb", "c0ffee")
+	server.HandleHeaders(http.MethodPost, "/job/deploy/build", http.StatusCreated, http.Header{
+		"Location": []string{"http://jenkins-0.internal:8080/jenkins/queue/item/42/"},
+	})
+	server.Handle(http.MethodGet, "/queue/item/42/api/json", http.StatusOK, `{"id":42,"executable":{"number":17}}`)
+
+	resp, err := triggerBuild(client, "deploy", nil, triggerCause{})
+	if err != nil {
+		t.Fatalf("triggerBuild: %v", err)
+	}
+	if got := server.LastRequest(http.MethodPost, "/job/deploy/build").Header.Get("Jenkins-Crumb"); got != "c0ffee" {
+		t.Fatalf("crumb header = %q, want c0ffee", got)
+	}
+
+	var errOut bytes.Buffer
+	number, err := waitForBuildNumber(client, queueLocationFromResponse(resp), 2*time.Second, &errOut, followOptions{})
+	if err != nil {
+		t.Fatalf("waitForBuildNumber: %v", err)
+	}
+	if number != 17 {
+		t.Fatalf("build number = %d, want 17", number)
+	}
+	server.LastRequest(http.MethodGet, "/queue/item/42/api/json")
+}
+
+func TestContextPathStreamsProgressiveLog(t *testing.T) {
+	server, client := fakejenkins.NewClientAt(t, "/jenkins")
+	server.Handle(http.MethodGet, "/job/app/3/logText/progressiveText", http.StatusOK, "hello\n")
+
+	var out bytes.Buffer
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := shared.StreamProgressiveLog(ctx, client, "app", 3, time.Millisecond, &out, nil); err != nil {
+		t.Fatalf("StreamProgressiveLog: %v", err)
+	}
+	if out.String() != "hello\n" {
+		t.Fatalf("log output = %q, want hello", out.String())
+	}
+}
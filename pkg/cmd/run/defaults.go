@@ -0,0 +1,58 @@
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func addNoDefaultsFlag(cmd *cobra.Command, noDefaults *bool) {
+	cmd.Flags().BoolVar(noDefaults, "no-defaults", false, "Ignore the context's default parameters for this job")
+}
+
+// applyDefaultParams merges the context's default parameters for jobPath
+// under params, whose values win. When defaults apply, the effective set is
+// printed on stderr with secrets redacted, unless --quiet.
+func applyDefaultParams(cmd *cobra.Command, client *jenkins.Client, redactor *filter.Redactor, jobPath string, params map[string]string) map[string]string {
+	if client == nil {
+		return params
+	}
+	pattern, defaults := client.Context().DefaultParams(jobPath)
+	if len(defaults) == 0 {
+		return params
+	}
+
+	merged := make(map[string]string, len(defaults)+len(params))
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range params {
+		merged[key] = value
+	}
+
+	if !cmdutil.Quiet(cmd) {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Parameters (defaults from %s): %s\n", pattern, formatParamSet(merged, redactor))
+	}
+	return merged
+}
+
+// formatParamSet renders params as sorted KEY=value pairs with secrets
+// redacted.
+func formatParamSet(params map[string]string, redactor *filter.Redactor) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = key + "=" + redactor.Redact(key, params[key])
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,73 @@
+package run
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func executeRunStart(t *testing.T, client *jenkins.Client, args ...string) (*bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.PersistentFlags().Bool("quiet", false, "")
+	root.AddCommand(NewCmdRun(f))
+	root.SetArgs(append([]string{"run", "start", "releases/deploy"}, args...))
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	return stderr, root.Execute()
+}
+
+func TestRunStartAppliesContextDefaults(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/releases/job/deploy/api/json", map[string]any{"buildable": true})
+	server.HandleHeaders(http.MethodPost, "/job/releases/job/deploy/buildWithParameters", http.StatusCreated,
+		http.Header{"Location": []string{"/queue/item/5/"}})
+	client.Context().Defaults = map[string]map[string]string{
+		"releases/**":     {"ENV": "staging", "CHART": "nova"},
+		"releases/deploy": {"ENV": "qa", "REGION": "eu-west-1", "DEPLOY_TOKEN": "s3cr3t"},
+	}
+
+	stderr, err := executeRunStart(t, client, "-p", "REGION=us-east-1")
+	if err != nil {
+		t.Fatalf("run start: %v", err)
+	}
+	form := server.LastRequest(http.MethodPost, "/job/releases/job/deploy/buildWithParameters").Form()
+	if form.Get("ENV") != "qa" || form.Get("REGION") != "us-east-1" || form.Get("DEPLOY_TOKEN") != "s3cr3t" || form.Has("CHART") {
+		t.Fatalf("expected the longest glob's defaults under --param, got %v", form)
+	}
+	want := "Parameters (defaults from releases/deploy): DEPLOY_TOKEN=[REDACTED] ENV=qa REGION=us-east-1\n"
+	if stderr.String() != want {
+		t.Fatalf("stderr = %q, want %q", stderr.String(), want)
+	}
+
+	stderr, err = executeRunStart(t, client, "-p", "REGION=us-east-1", "--quiet")
+	if err != nil {
+		t.Fatalf("run start --quiet: %v", err)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected no parameter line with --quiet, got %q", stderr.String())
+	}
+
+	stderr, err = executeRunStart(t, client, "-p", "REGION=us-east-1", "--no-defaults")
+	if err != nil {
+		t.Fatalf("run start --no-defaults: %v", err)
+	}
+	form = server.LastRequest(http.MethodPost, "/job/releases/job/deploy/buildWithParameters").Form()
+	if len(form) != 1 || form.Get("REGION") != "us-east-1" {
+		t.Fatalf("expected only explicit parameters with --no-defaults, got %v", form)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("expected no parameter line with --no-defaults, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,118 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func serveFinishedBuild(server *fakejenkins.Server, result string) {
+	server.HandleJSON(http.MethodGet, "/job/app/12/api/json", map[string]any{
+		"number": 12,
+		"result": result,
+		"artifacts": []map[string]any{
+			{"fileName": "app.tar.gz", "relativePath": "dist/app.tar.gz", "size": 4},
+			{"fileName": "build.log", "relativePath": "logs/build.log", "size": 3},
+		},
+	})
+	server.Handle(http.MethodGet, "/job/app/12/artifact/dist/app.tar.gz", http.StatusOK, "tgz!")
+	server.Handle(http.MethodGet, "/job/app/12/artifact/logs/build.log", http.StatusOK, "log")
+}
+
+func finishWithDownload(t *testing.T, client *jenkins.Client, result string, download *followDownload) (runDetailOutput, string, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.PersistentFlags().Bool("json", true, "")
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	err := finishFollowedRun(cmd, client, "app", 12, result, download)
+	var output runDetailOutput
+	if stdout.Len() > 0 {
+		if jsonErr := json.Unmarshal(stdout.Bytes(), &output); jsonErr != nil {
+			t.Fatalf("decode output: %v\n%s", jsonErr, stdout.String())
+		}
+	}
+	return output, stderr.String(), err
+}
+
+func TestFollowDownloadsArtifactsOnSuccess(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	serveFinishedBuild(server, "SUCCESS")
+	dir := filepath.Join(t.TempDir(), "out")
+
+	output, _, err := finishWithDownload(t, client, "SUCCESS", &followDownload{Pattern: "**/*.tar.gz", OutputDir: dir})
+	if err != nil {
+		t.Fatalf("finish: %v", err)
+	}
+	want := filepath.Join(dir, "dist", "app.tar.gz")
+	if len(output.DownloadedArtifacts) != 1 || output.DownloadedArtifacts[0] != want {
+		t.Fatalf("downloadedArtifacts = %q, want [%q]", output.DownloadedArtifacts, want)
+	}
+	data, err := os.ReadFile(want)
+	if err != nil || string(data) != "tgz!" {
+		t.Fatalf("downloaded file = %q, %v", data, err)
+	}
+	if len(server.RequestsTo(http.MethodGet, "/job/app/12/artifact/logs/build.log")) != 0 {
+		t.Fatal("expected unmatched artifacts to be skipped")
+	}
+}
+
+func TestFollowSkipsDownloadOnFailure(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	serveFinishedBuild(server, "FAILURE")
+	dir := t.TempDir()
+
+	output, stderr, err := finishWithDownload(t, client, "FAILURE", &followDownload{Pattern: "**/*", OutputDir: dir})
+	if code := exitCode(err); code != 11 {
+		t.Fatalf("expected exit 11, got %v", err)
+	}
+	if len(output.DownloadedArtifacts) != 0 || len(server.RequestsTo(http.MethodGet, "/job/app/12/artifact/logs/build.log")) != 0 {
+		t.Fatalf("expected no download, got %q", output.DownloadedArtifacts)
+	}
+	if !strings.Contains(stderr, "--download-on-failure") {
+		t.Fatalf("expected a skip note, got %q", stderr)
+	}
+
+	output, _, err = finishWithDownload(t, client, "FAILURE", &followDownload{Pattern: "logs/*", OutputDir: dir, OnFailure: true})
+	if code := exitCode(err); code != 11 {
+		t.Fatalf("expected exit 11 with --download-on-failure, got %v", err)
+	}
+	if len(output.DownloadedArtifacts) != 1 || output.DownloadedArtifacts[0] != filepath.Join(dir, "logs", "build.log") {
+		t.Fatalf("downloadedArtifacts = %q", output.DownloadedArtifacts)
+	}
+}
+
+func TestFollowDownloadWithoutMatchesFailsSuccessfulRun(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	serveFinishedBuild(server, "SUCCESS")
+
+	_, _, err := finishWithDownload(t, client, "SUCCESS", &followDownload{Pattern: "**/*.zip", OutputDir: t.TempDir()})
+	if code := exitCode(err); code != 3 {
+		t.Fatalf("expected exit 3, got %v", err)
+	}
+}
+
+func TestRunStartDownloadRequiresFollow(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+
+	for _, args := range [][]string{{"--download", "**/*"}, {"--follow", "--output", "out"}} {
+		_, err := executeRunStart(t, client, args...)
+		if code := exitCode(err); code != 2 {
+			t.Fatalf("%q: expected exit code 2, got %v", args, err)
+		}
+	}
+	if len(server.RequestsTo(http.MethodPost, "/job/releases/job/deploy/build")) != 0 {
+		t.Fatal("expected no build to be triggered")
+	}
+}
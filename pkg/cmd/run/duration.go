@@ -0,0 +1,61 @@
+package run
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+// durationFlags backs --longer-than and --shorter-than, shorthands for
+// duration filters that avoid quoting > and < in the shell.
+type durationFlags struct {
+	longerThan  string
+	shorterThan string
+}
+
+func addDurationFlags(cmd *cobra.Command, flags *durationFlags) {
+	cmd.Flags().StringVar(&flags.longerThan, "longer-than", "", "Only runs that took longer than this (45m, 2h, or milliseconds); same as --filter duration>VALUE")
+	cmd.Flags().StringVar(&flags.shorterThan, "shorter-than", "", "Only runs that took less than this (5m, 90s, or milliseconds); same as --filter duration<VALUE")
+}
+
+// filterArgs compiles the flags into --filter expressions, so they are parsed,
+// evaluated, and reported exactly like hand-written duration filters.
+func (d durationFlags) filterArgs() ([]string, error) {
+	longer, longerSet, err := parseDurationFlag("--longer-than", d.longerThan)
+	if err != nil {
+		return nil, err
+	}
+	shorter, shorterSet, err := parseDurationFlag("--shorter-than", d.shorterThan)
+	if err != nil {
+		return nil, err
+	}
+	if longerSet && shorterSet && longer >= shorter {
+		return nil, shared.NewExitError(2, fmt.Sprintf("--longer-than %s must be less than --shorter-than %s", strings.TrimSpace(d.longerThan), strings.TrimSpace(d.shorterThan)))
+	}
+
+	var args []string
+	if longerSet {
+		args = append(args, "duration"+string(filter.OpGT)+strings.TrimSpace(d.longerThan))
+	}
+	if shorterSet {
+		args = append(args, "duration"+string(filter.OpLT)+strings.TrimSpace(d.shorterThan))
+	}
+	return args, nil
+}
+
+func parseDurationFlag(name, value string) (time.Duration, bool, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false, nil
+	}
+	d, err := filter.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, false, shared.NewExitError(2, fmt.Sprintf("invalid %s value %q (want a duration such as 45m or milliseconds)", name, value))
+	}
+	return d, true, nil
+}
@@ -0,0 +1,82 @@
+package run
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestDurationFlagsFilterArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   durationFlags
+		want    []string
+		wantErr bool
+	}{
+		{name: "unset", flags: durationFlags{}},
+		{name: "longer", flags: durationFlags{longerThan: "45m"}, want: []string{"duration>45m"}},
+		{name: "shorter", flags: durationFlags{shorterThan: " 5m "}, want: []string{"duration<5m"}},
+		{name: "range", flags: durationFlags{longerThan: "1m", shorterThan: "2h"}, want: []string{"duration>1m", "duration<2h"}},
+		{name: "milliseconds", flags: durationFlags{longerThan: "90000"}, want: []string{"duration>90000"}},
+		{name: "empty range", flags: durationFlags{longerThan: "10m", shorterThan: "10m"}, wantErr: true},
+		{name: "inverted range", flags: durationFlags{longerThan: "1h", shorterThan: "5m"}, wantErr: true},
+		{name: "invalid", flags: durationFlags{longerThan: "soon"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.flags.filterArgs()
+			if tt.wantErr {
+				if code := exitCode(err); code != 2 {
+					t.Fatalf("expected exit 2, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("filterArgs: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("filterArgs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunListLongerThan(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/api/json", http.StatusOK, `{"builds":[
+		{"number":3,"result":"SUCCESS","duration":3600000,"timestamp":1700000300000},
+		{"number":2,"result":"SUCCESS","duration":60000,"timestamp":1700000200000},
+		{"number":1,"result":"FAILURE","duration":2700001,"timestamp":1700000100000}
+	]}`)
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"ls", "app", "--longer-than", "45m", "--filter", "result=SUCCESS", "--with-meta", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run ls: %v", err)
+	}
+
+	var output runListOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout.String())
+	}
+	if len(output.Items) != 1 || output.Items[0].Number != 3 {
+		t.Fatalf("expected only run #3, got %+v", output.Items)
+	}
+	if output.Metadata == nil || output.Metadata.Filters == nil {
+		t.Fatalf("expected filter metadata, got %+v", output.Metadata)
+	}
+	if want := []string{"result=SUCCESS", "duration>45m"}; !reflect.DeepEqual(output.Metadata.Filters.Applied, want) {
+		t.Fatalf("applied filters = %v, want %v", output.Metadata.Filters.Applied, want)
+	}
+}
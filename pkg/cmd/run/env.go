@@ -0,0 +1,227 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	// envSourceInjected means the variables came from the EnvInject plugin.
+	envSourceInjected = "injectedEnvVars"
+	// envSourceSynthesized means the plugin was missing and the well-known
+	// Jenkins variables were rebuilt from run metadata.
+	envSourceSynthesized = "synthesized"
+)
+
+// envFilterKeys are the keys run env accepts in --filter.
+var envFilterKeys = []string{"name", "value"}
+
+type runEnvOutput struct {
+	SchemaVersion string            `json:"schemaVersion"`
+	JobPath       string            `json:"jobPath"`
+	Number        int64             `json:"number"`
+	Source        string            `json:"source"`
+	Redacted      []string          `json:"redacted,omitempty"`
+	Env           map[string]string `json:"env"`
+}
+
+type injectedEnvVars struct {
+	EnvMap map[string]string `json:"envMap"`
+}
+
+func newRunEnvCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		showSecrets bool
+		filterArgs  []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "env <jobPath> <buildNumber>",
+		Short: "Show the environment variables a run saw",
+		Long: `Show the environment a run was built with. When the EnvInject plugin is
+installed its recorded variables are used; otherwise the well-known Jenkins
+variables (BUILD_NUMBER, JOB_NAME, BUILD_URL, GIT_COMMIT, parameters, ...) are
+rebuilt from the run's metadata. The source is reported in both cases.
+
+Values of variables whose names look like secrets are redacted unless
+--show-secrets is set.`,
+		Example: `  jk run env team/app 42
+  jk run env team/app 42 --filter name^GIT_
+  jk run env team/app 42 --json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			num, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || num <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid build number %q", args[1]))
+			}
+			filters, err := filter.ParseKeys(filterArgs, envFilterKeys)
+			if err != nil {
+				return err
+			}
+
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			env, source, err := fetchRunEnv(ctx, client, jobpath.Normalize(jobPath), num)
+			if err != nil {
+				return err
+			}
+
+			output := runEnvOutput{
+				SchemaVersion: "1.0",
+				JobPath:       jobpath.Normalize(jobPath),
+				Number:        num,
+				Source:        source,
+				Env:           make(map[string]string, len(env)),
+			}
+			for name, value := range env {
+				redact := !showSecrets && redactor.IsSecret(name)
+				if redact {
+					value = filter.RedactedValue
+				}
+				if !filter.Evaluate(filter.Context{"name": name, "value": value}, filters) {
+					continue
+				}
+				output.Env[name] = value
+				if redact {
+					output.Redacted = append(output.Redacted, name)
+				}
+			}
+			sort.Strings(output.Redacted)
+
+			return shared.PrintOutput(cmd, output, func() error {
+				renderRunEnv(cmd.OutOrStdout(), output)
+				return nil
+			})
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Print values of variables that look like secrets")
+	cmd.Flags().StringSliceVar(&filterArgs, "filter", nil, "Filter variables (repeatable): key[op]value on name or value, e.g. name^GIT_")
+	cmdutil.SetFilterSupport(cmd, envFilterKeys, filter.Operators())
+	return cmd
+}
+
+// fetchRunEnv reads the EnvInject variables for a run. A 404 there only
+// means the plugin is missing, so the run itself is fetched to tell a
+// missing run apart and to synthesize the standard variables.
+func fetchRunEnv(ctx context.Context, client *jenkins.Client, jobPath string, number int64) (map[string]string, string, error) {
+	base := fmt.Sprintf("/%s/%d", jobpath.Encode(jobPath), number)
+
+	var injected injectedEnvVars
+	resp, err := client.Do(client.NewRequest().SetContext(ctx), http.MethodGet, base+"/injectedEnvVars/api/json", &injected)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode() != http.StatusNotFound {
+		if err := shared.CheckResponse(resp, fmt.Sprintf("environment of run %s #%d", jobPath, number)); err != nil {
+			return nil, "", err
+		}
+		if injected.EnvMap == nil {
+			injected.EnvMap = map[string]string{}
+		}
+		return injected.EnvMap, envSourceInjected, nil
+	}
+
+	var detail runDetail
+	resp, err = client.Do(client.NewRequest().SetContext(ctx), http.MethodGet, base+"/api/json", &detail)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := shared.CheckResponse(resp, fmt.Sprintf("run %s #%d", jobPath, number)); err != nil {
+		return nil, "", err
+	}
+	return synthesizeRunEnv(client.Context().URL, jobPath, detail), envSourceSynthesized, nil
+}
+
+// synthesizeRunEnv rebuilds the variables Jenkins sets for every build from
+// run metadata, plus build parameters, which Jenkins also exports.
+func synthesizeRunEnv(baseURL, jobPath string, detail runDetail) map[string]string {
+	env := make(map[string]string)
+	set := func(name, value string) {
+		if value != "" {
+			env[name] = value
+		}
+	}
+
+	for _, param := range extractParameters(detail) {
+		if param.Value != nil {
+			set(param.Name, fmt.Sprint(param.Value))
+		}
+	}
+
+	jenkinsURL := strings.TrimSuffix(baseURL, "/") + "/"
+	jobURL := jenkinsURL + jobpath.Encode(jobPath) + "/"
+	number := strconv.FormatInt(detail.Number, 10)
+	segments := strings.Split(jobPath, "/")
+
+	set("BUILD_NUMBER", number)
+	set("BUILD_ID", number)
+	set("BUILD_DISPLAY_NAME", "#"+number)
+	set("BUILD_TAG", "jenkins-"+strings.ReplaceAll(jobPath, "/", "-")+"-"+number)
+	set("JOB_NAME", jobPath)
+	set("JOB_BASE_NAME", segments[len(segments)-1])
+	set("JENKINS_URL", jenkinsURL)
+	set("JOB_URL", jobURL)
+	if detail.URL != "" {
+		set("BUILD_URL", detail.URL)
+	} else {
+		set("BUILD_URL", jobURL+number+"/")
+	}
+	set("NODE_NAME", detail.BuiltOn)
+	if detail.Executor != nil {
+		set("EXECUTOR_NUMBER", strconv.Itoa(detail.Executor.Number))
+	}
+	if scm := extractSCMInfo(detail.Actions, detail.ChangeSet); scm != nil {
+		set("GIT_COMMIT", scm.Commit)
+		set("GIT_BRANCH", scm.Branch)
+		set("GIT_URL", scm.Repo)
+	}
+	return env
+}
+
+func renderRunEnv(w io.Writer, output runEnvOutput) {
+	switch output.Source {
+	case envSourceInjected:
+		_, _ = fmt.Fprintln(w, "# Source: EnvInject plugin (injectedEnvVars)")
+	default:
+		_, _ = fmt.Fprintln(w, "# Source: synthesized from run metadata (EnvInject plugin not available)")
+	}
+	names := make([]string, 0, len(output.Env))
+	for name := range output.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		_, _ = fmt.Fprintf(w, "%s=%s\n", name, output.Env[name])
+	}
+}
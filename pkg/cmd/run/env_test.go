@@ -0,0 +1,162 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func executeRunEnv(t *testing.T, client *jenkins.Client, jsonOut bool, args ...string) (*bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", jsonOut, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(append([]string{"env"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return stdout, cmd.Execute()
+}
+
+func TestRunEnvInjectedRedactsSecrets(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/app/42/injectedEnvVars/api/json", http.StatusOK,
+		`{"envMap":{"BUILD_NUMBER":"42","GIT_COMMIT":"abc123","DEPLOY_TOKEN":"s3cr3t","PATH":"/usr/bin"}}`)
+
+	stdout, err := executeRunEnv(t, client, true, "team/app", "42")
+	if err != nil {
+		t.Fatalf("run env: %v", err)
+	}
+	var got runEnvOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if got.Source != envSourceInjected {
+		t.Fatalf("expected source %q got %q", envSourceInjected, got.Source)
+	}
+	if got.Env["DEPLOY_TOKEN"] != filter.RedactedValue || got.Env["GIT_COMMIT"] != "abc123" || len(got.Env) != 4 {
+		t.Fatalf("unexpected env %v", got.Env)
+	}
+	if len(got.Redacted) != 1 || got.Redacted[0] != "DEPLOY_TOKEN" {
+		t.Fatalf("unexpected redacted %v", got.Redacted)
+	}
+}
+
+func TestRunEnvAppliesConfiguredRedaction(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/7/injectedEnvVars/api/json", http.StatusOK,
+		`{"envMap":{"PUBLIC_KEY_FINGERPRINT":"SHA256:abc","DB_CREDS":"admin:hunter2","ENV":"qa"}}`)
+
+	f, stdout, _ := fakejenkins.Factory(client)
+	f.Config = func() (*config.Config, error) {
+		return &config.Config{
+			Contexts: map[string]*config.Context{},
+			Preferences: config.Preferences{
+				SecretPatterns: []string{"creds"},
+				NonSecretNames: []string{"PUBLIC_KEY_FINGERPRINT"},
+			},
+		}, nil
+	}
+	cmd := NewCmdRun(f)
+	cmd.SetArgs([]string{"env", "app", "7"})
+	cmd.SetOut(stdout)
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run env: %v", err)
+	}
+	want := "# Source: EnvInject plugin (injectedEnvVars)\nDB_CREDS=[REDACTED]\nENV=qa\nPUBLIC_KEY_FINGERPRINT=SHA256:abc\n"
+	if stdout.String() != want {
+		t.Fatalf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestRunEnvShowSecretsAndFilter(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/7/injectedEnvVars/api/json", http.StatusOK,
+		`{"envMap":{"GIT_COMMIT":"abc123","GIT_BRANCH":"main","GIT_TOKEN":"s3cr3t","PATH":"/usr/bin"}}`)
+
+	stdout, err := executeRunEnv(t, client, false, "app", "7", "--filter", "name^GIT_", "--show-secrets")
+	if err != nil {
+		t.Fatalf("run env: %v", err)
+	}
+	want := "# Source: EnvInject plugin (injectedEnvVars)\nGIT_BRANCH=main\nGIT_COMMIT=abc123\nGIT_TOKEN=s3cr3t\n"
+	if stdout.String() != want {
+		t.Fatalf("unexpected output:\n%s", stdout.String())
+	}
+}
+
+func TestRunEnvSynthesizesWithoutPlugin(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/app/42/api/json", http.StatusOK, `{
+		"number": 42,
+		"result": "FAILURE",
+		"builtOn": "linux-1",
+		"actions": [
+			{"parameters": [{"name": "TARGET", "value": "prod"}, {"name": "DB_PASSWORD", "value": "hunter2"}]},
+			{"lastBuiltRevision": {"SHA1": "abc123", "branch": [{"name": "origin/main"}]}, "remoteUrls": ["https://git.example.com/app.git"]}
+		]
+	}`)
+
+	stdout, err := executeRunEnv(t, client, true, "team/app", "42")
+	if err != nil {
+		t.Fatalf("run env: %v", err)
+	}
+	var got runEnvOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if got.Source != envSourceSynthesized {
+		t.Fatalf("expected source %q got %q", envSourceSynthesized, got.Source)
+	}
+	jenkinsURL := strings.TrimSuffix(client.Context().URL, "/") + "/"
+	want := map[string]string{
+		"BUILD_NUMBER":       "42",
+		"BUILD_ID":           "42",
+		"BUILD_DISPLAY_NAME": "#42",
+		"BUILD_TAG":          "jenkins-team-app-42",
+		"BUILD_URL":          jenkinsURL + "job/team/job/app/42/",
+		"JOB_NAME":           "team/app",
+		"JOB_BASE_NAME":      "app",
+		"JOB_URL":            jenkinsURL + "job/team/job/app/",
+		"JENKINS_URL":        jenkinsURL,
+		"NODE_NAME":          "linux-1",
+		"GIT_COMMIT":         "abc123",
+		"GIT_BRANCH":         "origin/main",
+		"GIT_URL":            "https://git.example.com/app.git",
+		"TARGET":             "prod",
+		"DB_PASSWORD":        filter.RedactedValue,
+	}
+	if len(got.Env) != len(want) {
+		t.Fatalf("unexpected env %v", got.Env)
+	}
+	for name, value := range want {
+		if got.Env[name] != value {
+			t.Fatalf("%s: expected %q got %q", name, value, got.Env[name])
+		}
+	}
+}
+
+func TestRunEnvMissingRunIsNotFound(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+
+	_, err := executeRunEnv(t, client, false, "app", "9")
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("expected exit 3, got %v", err)
+	}
+	if !strings.HasPrefix(exitErr.Msg, "run app #9 not found") {
+		t.Fatalf("unexpected message %q", exitErr.Msg)
+	}
+}
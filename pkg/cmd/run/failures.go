@@ -0,0 +1,304 @@
+package run
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const defaultFailuresSince = "24h"
+
+// failureResults are the run results counted by `run failures`.
+var failureResults = map[string]bool{
+	"FAILURE":  true,
+	"UNSTABLE": true,
+	"ABORTED":  true,
+}
+
+type runFailuresOutput struct {
+	SchemaVersion string             `json:"schemaVersion"`
+	Jobs          []runFailureGroup  `json:"jobs"`
+	Metadata      *runSearchMetadata `json:"metadata,omitempty"`
+	shared.Result
+}
+
+type runFailureGroup struct {
+	JobPath string         `json:"jobPath"`
+	Count   int            `json:"count"`
+	Results map[string]int `json:"results"`
+	Latest  runFailureRun  `json:"latest"`
+}
+
+type runFailureRun struct {
+	Number    int64  `json:"number"`
+	Result    string `json:"result"`
+	StartTime string `json:"startTime,omitempty"`
+	URL       string `json:"url,omitempty"`
+	// Cause is the failing stage or last log line; only set with --details.
+	Cause string `json:"cause,omitempty"`
+}
+
+func newRunFailuresCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		folder      string
+		jobGlob     string
+		sinceArg    string
+		untilArg    string
+		maxScan     int
+		details     bool
+		okOnPartial bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "failures",
+		Short: "Summarize failed runs per job across a folder",
+		Long: `Search a folder for runs that ended in FAILURE, UNSTABLE, or ABORTED and
+group them by job. Each job lists how many runs failed in the window and its
+most recent failing run.
+
+--details adds a one-line cause for each latest failure: the failing stage
+when the Pipeline Stage View API is available, otherwise the last log line.
+It costs one or two extra requests per job; a cause that cannot be read
+is left out with a warning.`,
+		Example: `  # What failed overnight?
+  jk run failures --folder team --since 24h
+
+  # Include the failing stage or last log line
+  jk run failures --folder team --details`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if trimmed := strings.TrimSpace(jobGlob); trimmed != "" {
+				if _, err := doublestar.Match(trimmed, "test/job"); err != nil {
+					return fmt.Errorf("invalid job glob %q: %w", jobGlob, err)
+				}
+			}
+			if maxScan <= 0 {
+				maxScan = defaultSearchMaxScan
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			since, until, err := parseTimeRange(sinceArg, untilArg, client.ServerNow())
+			if err != nil {
+				return err
+			}
+
+			resolvedFolder, err := shared.ResolveFolder(cmd, client, folder)
+			if err != nil {
+				return err
+			}
+			normalizedFolder := jobpath.Normalize(resolvedFolder)
+			discovery, err := discoverJobs(cmd.Context(), client, normalizedFolder, jobGlob, jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth})
+			if err != nil {
+				return err
+			}
+
+			opts := runSearchOptions{
+				Since:    since,
+				Until:    until,
+				MaxScan:  maxScan,
+				Folder:   normalizedFolder,
+				JobGlob:  jobGlob,
+				Progress: f.Progress(),
+			}
+			search, err := executeRunSearch(cmd.Context(), client, discovery.Jobs, opts)
+			opts.Progress.Done()
+			if err != nil {
+				return err
+			}
+
+			output := runFailuresOutput{
+				SchemaVersion: "1.0",
+				Jobs:          groupRunFailures(search.Items),
+				Metadata:      search.Metadata,
+				Result:        search.Result,
+			}
+			if details {
+				// A cause that cannot be read leaves the digest intact and
+				// becomes a warning.
+				errs := make([]error, len(output.Jobs))
+				shared.ForEach(len(output.Jobs), shared.Concurrency(f), func(i int) {
+					group := &output.Jobs[i]
+					group.Latest.Cause, errs[i] = fetchFailureCause(cmd.Context(), client, group.JobPath, group.Latest.Number)
+				})
+				for i, err := range errs {
+					if err != nil {
+						output.Warn(output.Jobs[i].JobPath, shared.ExitCode(err), err.Error())
+					}
+				}
+			}
+
+			output.WriteIssues(cmd)
+			if err := shared.PrintOutput(cmd, output, func() error {
+				return renderRunFailuresHuman(cmd, output, shared.TimeFormatter(cmd, f), shared.ResultFormatter(cmd, f))
+			}); err != nil {
+				return err
+			}
+			return output.ExitError("jobs", okOnPartial)
+		},
+	}
+
+	cmd.Flags().StringVar(&folder, "folder", "", "Folder path to search in (defaults to the context default folder; pass / for the root)")
+	cmdutil.SetJobPathFlag(cmd, "folder")
+	cmd.Flags().StringVar(&jobGlob, "job-glob", "", "Job glob pattern (e.g., \"*/deploy-*\")")
+	cmd.Flags().StringVar(&sinceArg, "since", defaultFailuresSince, "Only count runs since timestamp or duration (RFC3339, 72h, 7d)")
+	cmd.Flags().StringVar(&untilArg, "until", "", "Only count runs started before timestamp or duration ago (RFC3339, 72h, 7d)")
+	cmd.Flags().IntVar(&maxScan, "max-scan", defaultSearchMaxScan, "Max builds to scan per job")
+	cmd.Flags().BoolVar(&details, "details", false, "Include the failing stage or last log line (extra requests per job)")
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+
+	return cmd
+}
+
+// groupRunFailures keeps failed runs and groups them by job, most failures
+// first. Items arrive sorted newest first, so the first run seen per job is
+// its latest failure.
+func groupRunFailures(items []runSearchItem) []runFailureGroup {
+	index := make(map[string]int)
+	groups := make([]runFailureGroup, 0)
+	for _, item := range items {
+		result := strings.ToUpper(strings.TrimSpace(item.Result))
+		if !failureResults[result] {
+			continue
+		}
+		i, ok := index[item.JobPath]
+		if !ok {
+			i = len(groups)
+			index[item.JobPath] = i
+			groups = append(groups, runFailureGroup{
+				JobPath: item.JobPath,
+				Results: make(map[string]int),
+				Latest: runFailureRun{
+					Number:    item.Number,
+					Result:    result,
+					StartTime: item.StartTime,
+					URL:       item.URL,
+				},
+			})
+		}
+		groups[i].Count++
+		groups[i].Results[result]++
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].JobPath < groups[j].JobPath
+	})
+	return groups
+}
+
+// fetchFailureCause names the first failed pipeline stage, falling back to
+// the last meaningful console line for non-pipeline jobs or failures outside
+// any stage.
+func fetchFailureCause(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64) (string, error) {
+	stage, err := fetchFailedStage(ctx, client, jobPath, buildNumber)
+	if err != nil {
+		return "", err
+	}
+	if stage != "" {
+		return "stage " + stage, nil
+	}
+	return fetchLastLogLine(ctx, client, jobPath, buildNumber)
+}
+
+func fetchFailedStage(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64) (string, error) {
+	stages, err := fetchStages(ctx, client, jobPath, buildNumber)
+	if err != nil {
+		return "", err
+	}
+	return failedStageName(stages), nil
+}
+
+// failedStageName prefers a FAILED stage over UNSTABLE over ABORTED, taking
+// the earliest of each since later stages usually fail as a consequence.
+func failedStageName(stages []wfapiStage) string {
+	for _, status := range []string{"FAILED", "UNSTABLE", "ABORTED"} {
+		for _, stage := range stages {
+			if strings.EqualFold(stage.Status, status) {
+				return stage.Name
+			}
+		}
+	}
+	return ""
+}
+
+func fetchLastLogLine(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64) (string, error) {
+	path := fmt.Sprintf("/%s/%d/consoleText", jobpath.Encode(jobPath), buildNumber)
+	req := client.NewStreamingRequest().
+		SetHeader("Accept", "text/plain").
+		SetDoNotParseResponse(true)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+
+	resp, err := client.Do(req, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+	body := resp.RawBody()
+	if body == nil {
+		return "", nil
+	}
+	defer func() { _ = body.Close() }()
+	if resp.StatusCode() >= 400 {
+		return "", nil
+	}
+
+	last := ""
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := meaningfulLogLine(scanner.Text()); line != "" {
+			last = line
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("read console: %w", err)
+	}
+	return last, nil
+}
+
+// meaningfulLogLine drops blank lines, the trailing "Finished: RESULT"
+// marker, and Pipeline bookkeeping that never explains a failure.
+func meaningfulLogLine(line string) string {
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "":
+		return ""
+	case strings.HasPrefix(line, "Finished: "):
+		return ""
+	case strings.HasPrefix(line, "[Pipeline] "):
+		return ""
+	}
+	return line
+}
+
+func renderRunFailuresHuman(cmd *cobra.Command, output runFailuresOutput, stamp func(string) string, label func(string) string) error {
+	w := cmd.OutOrStdout()
+	if len(output.Jobs) == 0 {
+		_, _ = fmt.Fprintln(w, "No failed runs found")
+		return nil
+	}
+	for _, group := range output.Jobs {
+		line := fmt.Sprintf("%s\t%d failed\t#%d\t%s\t%s\t%s", group.JobPath, group.Count, group.Latest.Number, label(group.Latest.Result), stamp(group.Latest.StartTime), group.Latest.URL)
+		if group.Latest.Cause != "" {
+			line += "\t" + group.Latest.Cause
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+	return nil
+}
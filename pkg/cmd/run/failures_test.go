@@ -0,0 +1,169 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestGroupRunFailures(t *testing.T) {
+	items := []runSearchItem{
+		{JobPath: "team/api", Number: 9, Result: "SUCCESS"},
+		{JobPath: "team/web", Number: 12, Result: "UNSTABLE", URL: "https://ci/job/team/job/web/12/"},
+		{JobPath: "team/api", Number: 8, Result: "FAILURE"},
+		{JobPath: "team/web", Number: 11, Result: "FAILURE"},
+		{JobPath: "team/api", Number: 7, Status: "running"},
+		{JobPath: "team/docs", Number: 3, Result: "ABORTED"},
+	}
+
+	groups := groupRunFailures(items)
+	got := make([]string, 0, len(groups))
+	for _, g := range groups {
+		got = append(got, fmt.Sprintf("%s:%d:#%d:%s", g.JobPath, g.Count, g.Latest.Number, g.Latest.Result))
+	}
+	want := []string{"team/web:2:#12:UNSTABLE", "team/api:1:#8:FAILURE", "team/docs:1:#3:ABORTED"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("groups = %v, want %v", got, want)
+	}
+	if groups[0].Results["FAILURE"] != 1 || groups[0].Results["UNSTABLE"] != 1 {
+		t.Fatalf("unexpected result counts %v", groups[0].Results)
+	}
+	if groups[0].Latest.URL == "" {
+		t.Fatal("latest failure lost its URL")
+	}
+}
+
+func TestFailedStageName(t *testing.T) {
+	stages := []wfapiStage{
+		{Name: "Build", Status: "SUCCESS"},
+		{Name: "Lint", Status: "UNSTABLE"},
+		{Name: "Test", Status: "FAILED"},
+		{Name: "Deploy", Status: "FAILED"},
+	}
+	if got := failedStageName(stages); got != "Test" {
+		t.Fatalf("failedStageName = %q, want Test", got)
+	}
+	if got := failedStageName(stages[:2]); got != "Lint" {
+		t.Fatalf("failedStageName = %q, want Lint", got)
+	}
+	if got := failedStageName(stages[:1]); got != "" {
+		t.Fatalf("failedStageName = %q, want empty", got)
+	}
+}
+
+func TestRunFailuresDetails(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UnixMilli()
+	old := time.Now().Add(-72 * time.Hour).UnixMilli()
+
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/api/json", http.StatusOK,
+		`{"_class":"com.cloudbees.hudson.plugins.folder.Folder","jobs":[{"name":"pipe","_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob"},{"name":"free","_class":"hudson.model.FreeStyleProject"},{"name":"green","_class":"hudson.model.FreeStyleProject"}]}`)
+	server.Handle(http.MethodGet, "/job/team/job/pipe/api/json", http.StatusOK, fmt.Sprintf(
+		`{"builds":[{"number":5,"result":"FAILURE","timestamp":%d},{"number":4,"result":"FAILURE","timestamp":%d},{"number":3,"result":"FAILURE","timestamp":%d}]}`, recent, recent, old))
+	server.Handle(http.MethodGet, "/job/team/job/free/api/json", http.StatusOK, fmt.Sprintf(
+		`{"builds":[{"number":2,"result":"UNSTABLE","timestamp":%d}]}`, recent))
+	server.Handle(http.MethodGet, "/job/team/job/green/api/json", http.StatusOK, fmt.Sprintf(
+		`{"builds":[{"number":1,"result":"SUCCESS","timestamp":%d}]}`, recent))
+	server.Handle(http.MethodGet, "/job/team/job/pipe/5/wfapi/describe", http.StatusOK,
+		`{"stages":[{"name":"Build","status":"SUCCESS"},{"name":"Test","status":"FAILED"}]}`)
+	server.Handle(http.MethodGet, "/job/team/job/free/2/consoleText", http.StatusOK,
+		"Started by timer\n3 tests failed\n\nFinished: UNSTABLE\n")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"failures", "--folder", "team", "--since", "24h", "--details", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run failures: %v", err)
+	}
+
+	var output runFailuresOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout.String())
+	}
+	if len(output.Jobs) != 2 {
+		t.Fatalf("expected 2 failing jobs, got %+v", output.Jobs)
+	}
+	pipe, free := output.Jobs[0], output.Jobs[1]
+	if pipe.JobPath != "team/pipe" || pipe.Count != 2 || pipe.Latest.Number != 5 || pipe.Latest.Cause != "stage Test" {
+		t.Fatalf("unexpected pipeline group %+v", pipe)
+	}
+	if free.JobPath != "team/free" || free.Count != 1 || free.Latest.Cause != "3 tests failed" {
+		t.Fatalf("unexpected freestyle group %+v", free)
+	}
+	if len(server.RequestsTo(http.MethodGet, "/job/team/job/green/1/wfapi/describe")) != 0 {
+		t.Fatal("fetched details for a job without failures")
+	}
+}
+
+func TestRunFailuresDetailsKeepDigestWhenCauseFails(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UnixMilli()
+
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/api/json", http.StatusOK,
+		`{"_class":"com.cloudbees.hudson.plugins.folder.Folder","jobs":[{"name":"pipe","_class":"org.jenkinsci.plugins.workflow.job.WorkflowJob"},{"name":"free","_class":"hudson.model.FreeStyleProject"}]}`)
+	server.Handle(http.MethodGet, "/job/team/job/pipe/api/json", http.StatusOK, fmt.Sprintf(
+		`{"builds":[{"number":5,"result":"FAILURE","timestamp":%d},{"number":4,"result":"FAILURE","timestamp":%d}]}`, recent, recent))
+	server.Handle(http.MethodGet, "/job/team/job/free/api/json", http.StatusOK, fmt.Sprintf(
+		`{"builds":[{"number":2,"result":"UNSTABLE","timestamp":%d}]}`, recent))
+	server.Handle(http.MethodGet, "/job/team/job/pipe/5/wfapi/describe", http.StatusInternalServerError, "")
+	server.Handle(http.MethodGet, "/job/team/job/free/2/consoleText", http.StatusOK, "3 tests failed\nFinished: UNSTABLE\n")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"failures", "--folder", "team", "--since", "24h", "--details", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run failures: %v", err)
+	}
+
+	var output runFailuresOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout.String())
+	}
+	if len(output.Jobs) != 2 {
+		t.Fatalf("expected the digest of 2 failing jobs, got %+v", output.Jobs)
+	}
+	pipe, free := output.Jobs[0], output.Jobs[1]
+	if pipe.JobPath != "team/pipe" || pipe.Count != 2 || pipe.Latest.Cause != "" {
+		t.Fatalf("unexpected pipeline group %+v", pipe)
+	}
+	if free.Latest.Cause != "3 tests failed" {
+		t.Fatalf("unexpected freestyle group %+v", free)
+	}
+	if len(output.Warnings) != 1 || output.Warnings[0].Target != "team/pipe" {
+		t.Fatalf("expected one warning for team/pipe, got %+v", output.Warnings)
+	}
+}
+
+func TestRunFailuresHumanDigest(t *testing.T) {
+	output := runFailuresOutput{Jobs: []runFailureGroup{{
+		JobPath: "team/api",
+		Count:   3,
+		Latest:  runFailureRun{Number: 42, Result: "FAILURE", StartTime: "2026-01-02T03:04:05Z", URL: "https://ci/job/team/job/api/42/", Cause: "stage Test"},
+	}}}
+
+	cmd := newRunFailuresCmd(nil)
+	var out strings.Builder
+	cmd.SetOut(&out)
+	identity := func(s string) string { return s }
+	if err := renderRunFailuresHuman(cmd, output, identity, identity); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "team/api\t3 failed\t#42\tFAILURE\t2026-01-02T03:04:05Z\thttps://ci/job/team/job/api/42/\tstage Test\n"
+	if out.String() != want {
+		t.Fatalf("digest = %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,122 @@
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+// groupByKeys are the keys inspectRun places in the filter context, all of
+// which --group-by can target; groupByPrefixes take a parameter name.
+var (
+	groupByKeys = []string{
+		"result", "status", "branch", "commit", "queue.id", "building", "started", "duration", "estimatedDuration",
+		"cause.user", "cause.type", "artifact.name", "artifact.path", "abortedBy",
+	}
+	groupByPrefixes = []string{"param."}
+)
+
+type runFieldsOutput struct {
+	SchemaVersion string            `json:"schemaVersion"`
+	Select        []selectFieldInfo `json:"select"`
+	Filters       filterCatalog     `json:"filters"`
+	GroupBy       groupByCatalog    `json:"groupBy"`
+}
+
+type selectFieldInfo struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Fetches     []string `json:"fetches,omitempty"`
+}
+
+type filterCatalog struct {
+	Keys      []string              `json:"keys"`
+	Operators []filter.OperatorInfo `json:"operators"`
+}
+
+type groupByCatalog struct {
+	Keys     []string `json:"keys"`
+	Prefixes []string `json:"prefixes"`
+}
+
+// addListFieldsFlag registers --list-fields on run ls and run search.
+func addListFieldsFlag(cmd *cobra.Command, target *bool) {
+	cmd.Flags().BoolVar(target, "list-fields", false, "List --select fields, --filter keys and operators, and --group-by keys, then exit")
+}
+
+func buildRunFieldsOutput() runFieldsOutput {
+	names := availableSelectFields()
+	fields := make([]selectFieldInfo, 0, len(names))
+	for _, name := range names {
+		spec := selectFieldRegistry[name]
+		info := selectFieldInfo{Name: name, Description: spec.description}
+		if spec.requiresParameters {
+			info.Fetches = append(info.Fetches, "parameters")
+		}
+		if spec.requiresArtifacts {
+			info.Fetches = append(info.Fetches, "artifacts")
+		}
+		if spec.requiresCauses {
+			info.Fetches = append(info.Fetches, "causes")
+		}
+		if spec.requiresInterruption {
+			info.Fetches = append(info.Fetches, "interruption")
+		}
+		fields = append(fields, info)
+	}
+
+	keys := append([]string{}, groupByKeys...)
+	sort.Strings(keys)
+
+	return runFieldsOutput{
+		SchemaVersion: "1.0",
+		Select:        fields,
+		Filters: filterCatalog{
+			Keys:      filter.AllowedKeys(),
+			Operators: filter.OperatorDetails(),
+		},
+		GroupBy: groupByCatalog{
+			Keys:     keys,
+			Prefixes: append([]string{}, groupByPrefixes...),
+		},
+	}
+}
+
+func printRunFields(cmd *cobra.Command) error {
+	output := buildRunFieldsOutput()
+	return shared.PrintOutput(cmd, output, func() error {
+		w := cmd.OutOrStdout()
+		_, _ = fmt.Fprintln(w, "SELECT FIELDS (--select)")
+		for _, field := range output.Select {
+			fetches := "-"
+			if len(field.Fetches) > 0 {
+				fetches = "fetches " + strings.Join(field.Fetches, ", ")
+			}
+			_, _ = fmt.Fprintf(w, "  %s\t%s\t%s\n", field.Name, fetches, field.Description)
+		}
+
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "FILTER KEYS (--filter key[op]value)")
+		_, _ = fmt.Fprintf(w, "  %s\n", strings.Join(output.Filters.Keys, ", "))
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "FILTER OPERATORS")
+		for _, op := range output.Filters.Operators {
+			_, _ = fmt.Fprintf(w, "  %s\t%s\te.g. %s\n", op.Operator, op.Description, op.Example)
+		}
+
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "GROUP BY (--group-by)")
+		_, _ = fmt.Fprintf(w, "  %s\n", strings.Join(output.GroupBy.Keys, ", "))
+		prefixes := make([]string, 0, len(output.GroupBy.Prefixes))
+		for _, prefix := range output.GroupBy.Prefixes {
+			prefixes = append(prefixes, prefix+"<name>")
+		}
+		_, _ = fmt.Fprintf(w, "  %s\n", strings.Join(prefixes, ", "))
+		return nil
+	})
+}
@@ -0,0 +1,58 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestListFieldsSkipsAPI(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: ios,
+		Config: func() (*config.Config, error) {
+			return &config.Config{}, nil
+		},
+		JenkinsClient: func(context.Context, string) (*jenkins.Client, error) {
+			t.Fatal("--list-fields must not create a Jenkins client")
+			return nil, nil
+		},
+	}
+
+	for _, args := range [][]string{{"ls", "--list-fields", "--json"}, {"search", "--list-fields", "--json"}} {
+		cmd := NewCmdRun(f)
+		cmd.PersistentFlags().Bool("json", false, "")
+		cmd.PersistentFlags().Bool("yaml", false, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+
+		var output runFieldsOutput
+		if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+			t.Fatalf("%v: decode: %v\n%s", args, err, out.String())
+		}
+		if len(output.Select) != len(selectFieldRegistry) {
+			t.Fatalf("%v: expected %d select fields, got %d", args, len(selectFieldRegistry), len(output.Select))
+		}
+		for _, field := range output.Select {
+			if field.Description == "" {
+				t.Fatalf("select field %q has no description", field.Name)
+			}
+			if field.Name == "parameters" && (len(field.Fetches) != 1 || field.Fetches[0] != "parameters") {
+				t.Fatalf("parameters should report an extra fetch, got %v", field.Fetches)
+			}
+		}
+		if len(output.Filters.Operators) == 0 || len(output.GroupBy.Prefixes) == 0 {
+			t.Fatalf("%v: incomplete catalog %+v", args, output)
+		}
+	}
+}
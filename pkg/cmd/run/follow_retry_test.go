@@ -0,0 +1,140 @@
+package run
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+// flakyRun answers the status of run #12 from a script of status codes, one
+// per request; 0 means the finished run, and requests past the end of the
+// script see it too.
+type flakyRun struct {
+	*fakejenkins.Server
+	script []int
+	calls  atomic.Int32
+}
+
+func (h *flakyRun) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/job/app/12/api/json" {
+		h.Server.ServeHTTP(w, r)
+		return
+	}
+	call := int(h.calls.Add(1)) - 1
+	if call < len(h.script) && h.script[call] != 0 {
+		w.WriteHeader(h.script[call])
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"number":12,"building":false,"result":"SUCCESS"}`))
+}
+
+func followFlakyRun(t *testing.T, quiet bool, max int, script ...int) (string, error) {
+	t.Helper()
+	useFastQueuePolling(t)
+	prev := runPollInterval
+	runPollInterval = time.Millisecond
+	t.Cleanup(func() { runPollInterval = prev })
+
+	server := fakejenkins.New(t)
+	server.Handle(http.MethodGet, "/queue/item/7/api/json", http.StatusOK, `{"id":7,"executable":{"number":12}}`)
+	client := jenkinstest.NewClient(t, &flakyRun{Server: server, script: script})
+
+	cmd := &cobra.Command{}
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmd.Flags().Bool("quiet", quiet, "")
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	resp := &resty.Response{RawResponse: &http.Response{Header: http.Header{"Location": {"/queue/item/7/"}}}}
+	retry := shared.PollRetry{Max: max, Backoff: time.Millisecond}
+	err := followTriggeredRun(cmd, client, "app", resp, followOptions{Retry: retry})
+	return stderr.String(), err
+}
+
+func TestFollowRetriesServerErrors(t *testing.T) {
+	stderr, err := followFlakyRun(t, false, 3, http.StatusServiceUnavailable, http.StatusBadGateway, 0)
+	if err != nil {
+		t.Fatalf("expected the run to be followed to the end, got %v", err)
+	}
+	for _, want := range []string{
+		"server error (503 Service Unavailable), retrying (1/3)…",
+		"server error (502 Bad Gateway), retrying (2/3)…",
+		"connection restored after 2 failed poll(s)",
+	} {
+		if !strings.Contains(stderr, want) {
+			t.Fatalf("expected %q in stderr, got %q", want, stderr)
+		}
+	}
+}
+
+func TestFollowRetryNotesQuiet(t *testing.T) {
+	stderr, err := followFlakyRun(t, true, 3, http.StatusServiceUnavailable, 0)
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if stderr != "" {
+		t.Fatalf("expected --quiet to drop the notes, got %q", stderr)
+	}
+}
+
+func TestFollowGivesUpAfterMaxRetries(t *testing.T) {
+	stderr, err := followFlakyRun(t, false, 2, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, 0)
+	if code := exitCode(err); code != 1 {
+		t.Fatalf("expected exit 1, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "giving up after 3 failed polls of run app #12") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if strings.Contains(stderr, "restored") {
+		t.Fatalf("expected no recovery note, got %q", stderr)
+	}
+}
+
+func TestFollowClientErrorIsNotRetried(t *testing.T) {
+	stderr, err := followFlakyRun(t, false, 3, http.StatusNotFound, 0)
+	if code := exitCode(err); code != 3 {
+		t.Fatalf("expected exit 3, got %v", err)
+	}
+	if strings.Contains(stderr, "retrying") {
+		t.Fatalf("expected no retry for a 404, got %q", stderr)
+	}
+}
+
+func TestWaitForBuildNumberRetriesQueuePolls(t *testing.T) {
+	useFastQueuePolling(t)
+	var calls atomic.Int32
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/queue/item/7/api/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":7,"executable":{"number":12}}`))
+	}))
+
+	var stderr bytes.Buffer
+	retry := shared.PollRetry{Max: 1, Backoff: time.Millisecond, Notes: &stderr}
+	number, err := waitForBuildNumber(client, "/queue/item/7/", time.Minute, &stderr, followOptions{Retry: retry})
+	if err != nil || number != 12 {
+		t.Fatalf("expected build 12, got %d, %v", number, err)
+	}
+	if !strings.Contains(stderr.String(), "server error (500 Internal Server Error), retrying (1/1)…") {
+		t.Fatalf("unexpected stderr %q", stderr.String())
+	}
+}
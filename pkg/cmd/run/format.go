@@ -1,7 +1,9 @@
 package run
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -10,12 +12,17 @@ import (
 	"time"
 
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 type runListOutput struct {
-	SchemaVersion string           `json:"schemaVersion"`
-	Items         []runListItem    `json:"items,omitempty"`
-	Groups        []runListGroup   `json:"groups,omitempty"`
+	SchemaVersion string         `json:"schemaVersion"`
+	Items         []runListItem  `json:"items,omitempty"`
+	Groups        []runListGroup `json:"groups,omitempty"`
+	// GroupCount is the number of distinct groups before --group-limit, and
+	// HasMoreGroups reports that the limit left some out.
+	GroupCount    int              `json:"groupCount,omitempty"`
+	HasMoreGroups bool             `json:"hasMoreGroups,omitempty"`
 	NextCursor    string           `json:"nextCursor,omitempty"`
 	Metadata      *runListMetadata `json:"metadata,omitempty"`
 }
@@ -24,69 +31,100 @@ type runSearchOutput struct {
 	SchemaVersion string             `json:"schemaVersion"`
 	Items         []runSearchItem    `json:"items"`
 	Metadata      *runSearchMetadata `json:"metadata,omitempty"`
+	// Result counts searched jobs; a job that cannot be read is an error
+	// entry and the search goes on.
+	shared.Result
+
+	// more reports that --limit cut further matching runs.
+	more bool
 }
 
 type runListItem struct {
-	ID         string         `json:"id"`
-	Number     int64          `json:"number"`
-	Status     string         `json:"status"`
-	Result     string         `json:"result,omitempty"`
-	DurationMs int64          `json:"durationMs"`
-	StartTime  string         `json:"startTime,omitempty"`
-	Branch     string         `json:"branch,omitempty"`
-	Commit     string         `json:"commit,omitempty"`
-	URL        string         `json:"url,omitempty"`
-	QueueID    int64          `json:"queueId,omitempty"`
-	Fields     map[string]any `json:"fields,omitempty"`
+	ID          string         `json:"id"`
+	Number      int64          `json:"number"`
+	Status      string         `json:"status"`
+	Result      string         `json:"result,omitempty"`
+	DurationMs  int64          `json:"durationMs"`
+	StartTime   string         `json:"startTime,omitempty"`
+	Branch      string         `json:"branch,omitempty"`
+	Commit      string         `json:"commit,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	QueueID     int64          `json:"queueId,omitempty"`
+	AbortedBy   string         `json:"abortedBy,omitempty"`
+	AbortReason string         `json:"abortReason,omitempty"`
+	Fields      map[string]any `json:"fields,omitempty"`
+	LogTail     []string       `json:"logTail,omitempty"`
 }
 
 type runSearchItem struct {
-	JobPath    string         `json:"jobPath"`
-	ID         string         `json:"id"`
-	Number     int64          `json:"number"`
-	Status     string         `json:"status"`
-	Result     string         `json:"result,omitempty"`
-	DurationMs int64          `json:"durationMs"`
-	StartTime  string         `json:"startTime,omitempty"`
-	Branch     string         `json:"branch,omitempty"`
-	Commit     string         `json:"commit,omitempty"`
-	URL        string         `json:"url,omitempty"`
-	QueueID    int64          `json:"queueId,omitempty"`
-	Fields     map[string]any `json:"fields,omitempty"`
+	JobPath     string         `json:"jobPath"`
+	ID          string         `json:"id"`
+	Number      int64          `json:"number"`
+	Status      string         `json:"status"`
+	Result      string         `json:"result,omitempty"`
+	DurationMs  int64          `json:"durationMs"`
+	StartTime   string         `json:"startTime,omitempty"`
+	Branch      string         `json:"branch,omitempty"`
+	Commit      string         `json:"commit,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	QueueID     int64          `json:"queueId,omitempty"`
+	AbortedBy   string         `json:"abortedBy,omitempty"`
+	AbortReason string         `json:"abortReason,omitempty"`
+	Fields      map[string]any `json:"fields,omitempty"`
+	LogTail     []string       `json:"logTail,omitempty"`
 }
 
 type runListGroup struct {
-	Key   string       `json:"key"`
-	Value string       `json:"value"`
-	Count int          `json:"count,omitempty"`
-	First *runListItem `json:"first,omitempty"`
-	Last  *runListItem `json:"last,omitempty"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Count int    `json:"count,omitempty"`
+	// ArtifactBytes is set for --agg sum-artifact-bytes.
+	ArtifactBytes int64        `json:"artifactBytes,omitempty"`
+	First         *runListItem `json:"first,omitempty"`
+	Last          *runListItem `json:"last,omitempty"`
 }
 
 type runListMetadata struct {
 	Filters     *filterMetadata    `json:"filters,omitempty"`
 	Parameters  []runParameterInfo `json:"parameters,omitempty"`
 	Suggestions []string           `json:"suggestions,omitempty"`
+	Notes       []string           `json:"notes,omitempty"`
 	Fields      []string           `json:"fields,omitempty"`
 	Selection   []string           `json:"selection,omitempty"`
 	Since       string             `json:"since,omitempty"`
+	Until       string             `json:"until,omitempty"`
+	SinceBuild  int64              `json:"sinceBuild,omitempty"`
 	GroupBy     string             `json:"groupBy,omitempty"`
 	Aggregation string             `json:"aggregation,omitempty"`
+	Assertion   *runAssertion      `json:"assertion,omitempty"`
 }
 
 type runSearchMetadata struct {
-	Folder      string   `json:"folder,omitempty"`
-	JobGlob     string   `json:"jobGlob,omitempty"`
-	Filters     []string `json:"filters,omitempty"`
-	Since       string   `json:"since,omitempty"`
-	JobsScanned int      `json:"jobsScanned,omitempty"`
-	MaxScan     int      `json:"maxScan,omitempty"`
-	Selection   []string `json:"selection,omitempty"`
+	Folder  string `json:"folder,omitempty"`
+	JobGlob string `json:"jobGlob,omitempty"`
+	// Jobs echoes --job; JobsNotFound lists those that do not exist.
+	Jobs           []string      `json:"jobs,omitempty"`
+	JobsNotFound   []string      `json:"jobsNotFound,omitempty"`
+	IncludeFolders []string      `json:"includeFolders,omitempty"`
+	ExcludeFolders []string      `json:"excludeFolders,omitempty"`
+	FoldersPruned  int           `json:"foldersPruned"`
+	Filters        []string      `json:"filters,omitempty"`
+	Since          string        `json:"since,omitempty"`
+	Until          string        `json:"until,omitempty"`
+	SinceBuild     int64         `json:"sinceBuild,omitempty"`
+	JobsScanned    int           `json:"jobsScanned,omitempty"`
+	JobsWithRuns   int           `json:"jobsWithRuns"`
+	MaxScan        int           `json:"maxScan,omitempty"`
+	Selection      []string      `json:"selection,omitempty"`
+	Assertion      *runAssertion `json:"assertion,omitempty"`
 }
 
 type filterMetadata struct {
 	Available []string `json:"available,omitempty"`
 	Operators []string `json:"operators,omitempty"`
+	// Applied lists the filters in effect, including those compiled from
+	// --longer-than and --shorter-than.
+	Applied []string `json:"applied,omitempty"`
 }
 
 type runParameterInfo struct {
@@ -96,18 +134,21 @@ type runParameterInfo struct {
 	IsSecret     bool     `json:"isSecret"`
 	SampleValues []string `json:"sampleValues,omitempty"`
 	Frequency    float64  `json:"frequency,omitempty"`
+	InferredFrom string   `json:"inferredFrom,omitempty"`
 }
 
 type runParamsOutput struct {
 	JobPath    string             `json:"jobPath"`
 	Source     string             `json:"source"`
 	Parameters []runParameterInfo `json:"parameters"`
+	Notes      []string           `json:"notes,omitempty"`
 }
 
 type runTriggerOutput struct {
 	JobPath       string `json:"jobPath"`
 	Message       string `json:"message"`
 	QueueLocation string `json:"queueLocation,omitempty"`
+	Cause         string `json:"cause,omitempty"`
 }
 
 type runDetailOutput struct {
@@ -130,6 +171,17 @@ type runDetailOutput struct {
 	Node                *runNodeInfo    `json:"node,omitempty"`
 	Description         string          `json:"description,omitempty"`
 	DisplayName         string          `json:"displayName,omitempty"`
+	AbortedBy           string          `json:"abortedBy,omitempty"`
+	AbortReason         string          `json:"abortReason,omitempty"`
+	// ArtifactBytes and LogBytes are set by --sizes; LogBytes stays unset
+	// when Jenkins does not report the console size.
+	ArtifactBytes *int64 `json:"artifactBytes,omitempty"`
+	LogBytes      *int64 `json:"logBytes,omitempty"`
+	// Checks and ChecksVerdict are set by --checks.
+	Checks        []runCheck `json:"checks,omitempty"`
+	ChecksVerdict string     `json:"checksVerdict,omitempty"`
+	// DownloadedArtifacts is set by run start --follow --download.
+	DownloadedArtifacts []string `json:"downloadedArtifacts,omitempty"`
 }
 
 type runParameter struct {
@@ -145,10 +197,18 @@ type runSCMInfo struct {
 }
 
 type runCause struct {
-	Type        string `json:"type,omitempty"`
-	UserID      string `json:"userId,omitempty"`
-	UserName    string `json:"userName,omitempty"`
-	Description string `json:"description,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	UserID      string            `json:"userId,omitempty"`
+	UserName    string            `json:"userName,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Upstream    *runCauseUpstream `json:"upstream,omitempty"`
+}
+
+// runCauseUpstream identifies the run that triggered an UpstreamCause.
+type runCauseUpstream struct {
+	Project string `json:"project"`
+	Build   int64  `json:"build"`
+	URL     string `json:"url,omitempty"`
 }
 
 type runStage struct {
@@ -176,13 +236,19 @@ type runNodeInfo struct {
 	Executor    int    `json:"executor,omitempty"`
 }
 
+// runCursorVersion is the leading byte of every encoded cursor. Cursors issued
+// before versioning are bare JSON and decode as version 0.
+const runCursorVersion byte = 1
+
 type runCursorPayload struct {
+	Version byte   `json:"-"`
 	JobPath string `json:"jobPath,omitempty"`
 	Number  int64  `json:"number"`
+	Scope   string `json:"scope,omitempty"`
 }
 
 func assembleRunListOutput(jobPath string, opts runListOptions, runs []*runInspection, groups map[string]*runGroupAccumulator, collector *metadataCollector, nextCursor string) runListOutput {
-	normalized := normalizeJobPath(jobPath)
+	normalized := jobpath.Normalize(jobPath)
 	items := make([]runListItem, 0, len(runs))
 	for _, run := range runs {
 		if run == nil {
@@ -199,6 +265,9 @@ func assembleRunListOutput(jobPath string, opts runListOptions, runs []*runInspe
 				Value: value,
 				Count: acc.Count,
 			}
+			if opts.Aggregation == aggSumArtifactBytes {
+				group.ArtifactBytes = acc.ArtifactBytes
+			}
 			if acc.First != nil {
 				first := buildRunListItem(normalized, acc.First, opts)
 				group.First = &first
@@ -209,18 +278,20 @@ func assembleRunListOutput(jobPath string, opts runListOptions, runs []*runInspe
 			}
 			groupItems = append(groupItems, group)
 		}
-		sort.Slice(groupItems, func(i, j int) bool {
-			if groupItems[i].Count == groupItems[j].Count {
-				return strings.ToLower(groupItems[i].Value) < strings.ToLower(groupItems[j].Value)
-			}
-			return groupItems[i].Count > groupItems[j].Count
-		})
+		sortRunListGroups(groupItems)
+	}
+	groupCount := len(groupItems)
+	hasMoreGroups := opts.GroupLimit > 0 && groupCount > opts.GroupLimit
+	if hasMoreGroups {
+		groupItems = groupItems[:opts.GroupLimit]
 	}
 
 	output := runListOutput{
 		SchemaVersion: "1.0",
 		Items:         items,
 		Groups:        groupItems,
+		GroupCount:    groupCount,
+		HasMoreGroups: hasMoreGroups,
 		NextCursor:    nextCursor,
 	}
 	if opts.WithMeta && collector != nil {
@@ -229,19 +300,40 @@ func assembleRunListOutput(jobPath string, opts runListOptions, runs []*runInspe
 	return output
 }
 
+// sortRunListGroups orders groups by artifact bytes (when summed), then by
+// count, largest first; ties go by value ignoring case, then by exact value
+// so the order never depends on map iteration.
+func sortRunListGroups(groups []runListGroup) {
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if a.ArtifactBytes != b.ArtifactBytes {
+			return a.ArtifactBytes > b.ArtifactBytes
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if la, lb := strings.ToLower(a.Value), strings.ToLower(b.Value); la != lb {
+			return la < lb
+		}
+		return a.Value < b.Value
+	})
+}
+
 func buildRunSearchItem(jobPath string, item runListItem) runSearchItem {
 	result := runSearchItem{
-		JobPath:    normalizeJobPath(jobPath),
-		ID:         item.ID,
-		Number:     item.Number,
-		Status:     item.Status,
-		Result:     item.Result,
-		DurationMs: item.DurationMs,
-		StartTime:  item.StartTime,
-		Branch:     item.Branch,
-		Commit:     item.Commit,
-		URL:        item.URL,
-		QueueID:    item.QueueID,
+		JobPath:     jobpath.Normalize(jobPath),
+		ID:          item.ID,
+		Number:      item.Number,
+		Status:      item.Status,
+		Result:      item.Result,
+		DurationMs:  item.DurationMs,
+		StartTime:   item.StartTime,
+		Branch:      item.Branch,
+		Commit:      item.Commit,
+		URL:         item.URL,
+		QueueID:     item.QueueID,
+		AbortedBy:   item.AbortedBy,
+		AbortReason: item.AbortReason,
 	}
 	if len(item.Fields) > 0 {
 		fields := make(map[string]any, len(item.Fields))
@@ -265,7 +357,7 @@ func buildRunListItem(jobPath string, inspection *runInspection, opts runListOpt
 		Status:     status,
 		Result:     result,
 		DurationMs: summary.Duration,
-		StartTime:  formatTimestamp(summary.Timestamp),
+		StartTime:  shared.FormatTimestamp(summary.Timestamp),
 	}
 
 	if scm != nil {
@@ -278,6 +370,10 @@ func buildRunListItem(jobPath string, inspection *runInspection, opts runListOpt
 	if summary.QueueID > 0 {
 		item.QueueID = summary.QueueID
 	}
+	if inspection.Interruption != nil {
+		item.AbortedBy = inspection.Interruption.By
+		item.AbortReason = inspection.Interruption.Reason
+	}
 
 	if len(opts.SelectFields) > 0 {
 		fields := make(map[string]any, len(opts.SelectFields))
@@ -315,6 +411,8 @@ func buildRunListItem(jobPath string, inspection *runInspection, opts runListOpt
 				if len(inspection.Artifacts) > 0 {
 					fields["artifacts"] = inspection.Artifacts
 				}
+			case "artifactbytes":
+				fields["artifactBytes"] = sumArtifactBytes(inspection.Artifacts)
 			case "causes":
 				if len(inspection.Causes) > 0 {
 					fields["causes"] = inspection.Causes
@@ -323,6 +421,14 @@ func buildRunListItem(jobPath string, inspection *runInspection, opts runListOpt
 				if summary.EstimatedDuration > 0 {
 					fields["estimatedDurationMs"] = summary.EstimatedDuration
 				}
+			case "abortedby":
+				if item.AbortedBy != "" {
+					fields["abortedBy"] = item.AbortedBy
+				}
+			case "abortreason":
+				if item.AbortReason != "" {
+					fields["abortReason"] = item.AbortReason
+				}
 			}
 		}
 		if len(fields) > 0 {
@@ -334,7 +440,7 @@ func buildRunListItem(jobPath string, inspection *runInspection, opts runListOpt
 }
 
 func buildRunDetailOutput(jobPath string, detail runDetail, testReport *shared.TestReport) runDetailOutput {
-	normalized := normalizeJobPath(jobPath)
+	normalized := jobpath.Normalize(jobPath)
 	status := statusFromFlags(detail.Building)
 	result := resultForList(detail.Result, detail.Building)
 
@@ -364,7 +470,7 @@ func buildRunDetailOutput(jobPath string, detail runDetail, testReport *shared.T
 		URL:                 detail.URL,
 		Status:              status,
 		Result:              result,
-		StartTime:           formatTimestamp(detail.Timestamp),
+		StartTime:           shared.FormatTimestamp(detail.Timestamp),
 		DurationMs:          detail.Duration,
 		EstimatedDurationMs: detail.EstimatedDuration,
 		Parameters:          parameters,
@@ -378,6 +484,10 @@ func buildRunDetailOutput(jobPath string, detail runDetail, testReport *shared.T
 		Description:         strings.TrimSpace(detail.Description),
 		DisplayName:         strings.TrimSpace(detail.FullDisplayName),
 	}
+	if interruption := abortDetails(detail.Result, detail.Actions); interruption != nil {
+		output.AbortedBy = interruption.By
+		output.AbortReason = interruption.Reason
+	}
 
 	return output
 }
@@ -541,6 +651,9 @@ func extractCauses(actions []map[string]any) []runCause {
 	seen := make(map[string]struct{})
 
 	for _, action := range actions {
+		if isInterruptionAction(action) {
+			continue
+		}
 		rawCauses, ok := action["causes"].([]any)
 		if !ok {
 			continue
@@ -558,7 +671,17 @@ func extractCauses(actions []map[string]any) []runCause {
 				UserName:    getString(causeMap["userName"]),
 				Description: description,
 			}
+			if project := jobpath.Normalize(getString(causeMap["upstreamProject"])); project != "" {
+				cause.Upstream = &runCauseUpstream{
+					Project: project,
+					Build:   toInt64(causeMap["upstreamBuild"]),
+					URL:     getString(causeMap["upstreamUrl"]),
+				}
+			}
 			key := fmt.Sprintf("%s|%s|%s|%s", cause.Type, cause.UserID, cause.UserName, cause.Description)
+			if cause.Upstream != nil {
+				key += fmt.Sprintf("|%s#%d", cause.Upstream.Project, cause.Upstream.Build)
+			}
 			if _, exists := seen[key]; exists {
 				continue
 			}
@@ -639,27 +762,20 @@ func statusFromResult(result string) string {
 	}
 }
 
-func formatTimestamp(ts int64) string {
-	if ts <= 0 {
-		return ""
-	}
-	return time.UnixMilli(ts).UTC().Format(time.RFC3339)
-}
-
 func formatTimestampAny(values ...any) string {
 	for _, v := range values {
 		switch typed := v.(type) {
 		case int64:
 			if typed > 0 {
-				return formatTimestamp(typed)
+				return shared.FormatTimestamp(typed)
 			}
 		case int:
 			if typed > 0 {
-				return formatTimestamp(int64(typed))
+				return shared.FormatTimestamp(int64(typed))
 			}
 		case float64:
 			if typed > 0 {
-				return formatTimestamp(int64(typed))
+				return shared.FormatTimestamp(int64(typed))
 			}
 		case string:
 			if strings.TrimSpace(typed) != "" {
@@ -732,20 +848,17 @@ func toInt64(values ...any) int64 {
 	return 0
 }
 
-func normalizeJobPath(jobPath string) string {
-	return strings.Trim(strings.TrimSpace(jobPath), "/")
-}
-
-func encodeRunCursor(jobPath string, number int64) string {
+func encodeRunCursor(jobPath string, number int64, scope string) string {
 	payload := runCursorPayload{
 		JobPath: jobPath,
 		Number:  number,
+		Scope:   scope,
 	}
 	bytes, err := json.Marshal(payload)
 	if err != nil {
 		return ""
 	}
-	return base64.RawURLEncoding.EncodeToString(bytes)
+	return base64.RawURLEncoding.EncodeToString(append([]byte{runCursorVersion}, bytes...))
 }
 
 func decodeRunCursor(cursor string) (runCursorPayload, error) {
@@ -757,8 +870,48 @@ func decodeRunCursor(cursor string) (runCursorPayload, error) {
 	if err != nil {
 		return payload, fmt.Errorf("decode cursor: %w", err)
 	}
+	if len(bytes) > 0 && bytes[0] != '{' {
+		payload.Version = bytes[0]
+		if payload.Version > runCursorVersion {
+			return payload, shared.NewExitError(2, fmt.Sprintf("cursor format v%d is newer than this jk supports; upgrade jk or drop --cursor", payload.Version))
+		}
+		bytes = bytes[1:]
+	}
 	if err := json.Unmarshal(bytes, &payload); err != nil {
 		return payload, fmt.Errorf("decode cursor: %w", err)
 	}
 	return payload, nil
 }
+
+// runCursorScope hashes the options that decide which runs match, so a cursor
+// resumed with different filters or time bounds is detected instead of
+// silently skipping or repeating runs. Relative --since/--until values hash by
+// their literal text so "7d" stays stable across invocations.
+func runCursorScope(opts runListOptions) string {
+	filters := make([]string, 0, len(opts.Filters))
+	for _, f := range opts.Filters {
+		filters = append(filters, f.Key+string(f.Operator)+f.Value)
+	}
+	sort.Strings(filters)
+
+	since := strings.TrimSpace(opts.SinceArg)
+	if since == "" && opts.Since != nil {
+		since = opts.Since.UTC().Format(time.RFC3339)
+	}
+	until := strings.TrimSpace(opts.UntilArg)
+	if until == "" && opts.Until != nil {
+		until = opts.Until.UTC().Format(time.RFC3339)
+	}
+
+	h := sha256.New()
+	for _, f := range filters {
+		_, _ = fmt.Fprintf(h, "filter=%s\n", f)
+	}
+	_, _ = fmt.Fprintf(h, "since=%s\nuntil=%s\nregex=%t\n", since, until, opts.AllowRegex)
+	if opts.SinceBuild > 0 {
+		// Only hashed when set, so cursors issued before --since-build
+		// existed keep their scope.
+		_, _ = fmt.Fprintf(h, "sinceBuild=%d\n", opts.SinceBuild)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
@@ -0,0 +1,72 @@
+package run
+
+import (
+	"strings"
+)
+
+// interruptedBuildActionClass is the action Jenkins attaches when a build is
+// aborted while running. Its causes describe who or what stopped it, and are
+// not build triggers.
+const interruptedBuildActionClass = "jenkins.model.InterruptedBuildAction"
+
+type runInterruption struct {
+	By     string
+	Reason string
+}
+
+func isInterruptionAction(action map[string]any) bool {
+	return getString(action["_class"]) == interruptedBuildActionClass
+}
+
+// extractInterruption reads the first cause of interruption. The user is
+// exported as an id string or a user object depending on the Jenkins version;
+// when neither is present the "Aborted by <user>" description is used.
+func extractInterruption(actions []map[string]any) *runInterruption {
+	for _, action := range actions {
+		if !isInterruptionAction(action) {
+			continue
+		}
+		rawCauses, _ := action["causes"].([]any)
+		for _, raw := range rawCauses {
+			causeMap, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			interruption := &runInterruption{
+				By:     interruptionUser(causeMap["user"]),
+				Reason: strings.TrimSpace(getString(causeMap["shortDescription"])),
+			}
+			if interruption.By == "" {
+				if user, ok := strings.CutPrefix(interruption.Reason, "Aborted by "); ok {
+					interruption.By = strings.TrimSpace(user)
+				}
+			}
+			if interruption.By != "" || interruption.Reason != "" {
+				return interruption
+			}
+		}
+	}
+	return nil
+}
+
+func interruptionUser(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		if id := getString(v["id"]); id != "" {
+			return id
+		}
+		return getString(v["fullName"])
+	}
+	return ""
+}
+
+// abortDetails reports the interruption of an ABORTED run; other results
+// return nil.
+func abortDetails(result string, actions []map[string]any) *runInterruption {
+	if !strings.EqualFold(strings.TrimSpace(result), "ABORTED") {
+		return nil
+	}
+	return extractInterruption(actions)
+}
@@ -0,0 +1,124 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+const abortedActions = `[
+	{"_class":"hudson.model.CauseAction","causes":[{"_class":"hudson.model.Cause$UserIdCause","shortDescription":"Started by user bob","userId":"bob","userName":"Bob"}]},
+	{"_class":"jenkins.model.InterruptedBuildAction","causes":[{"_class":"jenkins.model.CauseOfInterruption$UserInterruption","shortDescription":"Aborted by alice","user":"alice"}]}
+]`
+
+func TestBuildRunDetailOutputAbortedBy(t *testing.T) {
+	var detail runDetail
+	if err := json.Unmarshal([]byte(`{"number":5,"result":"ABORTED","actions":`+abortedActions+`}`), &detail); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	output := buildRunDetailOutput("app", detail, nil)
+	if output.AbortedBy != "alice" || output.AbortReason != "Aborted by alice" {
+		t.Fatalf("unexpected abort details %q / %q", output.AbortedBy, output.AbortReason)
+	}
+	if len(output.Causes) != 1 || output.Causes[0].UserID != "bob" {
+		t.Fatalf("interruption leaked into causes: %+v", output.Causes)
+	}
+
+	detail.Result = "FAILURE"
+	if output := buildRunDetailOutput("app", detail, nil); output.AbortedBy != "" || output.AbortReason != "" {
+		t.Fatalf("expected no abort details for FAILURE, got %+v", output)
+	}
+}
+
+func TestExtractInterruptionFallsBackToDescription(t *testing.T) {
+	interruption := extractInterruption([]map[string]any{{
+		"_class": interruptedBuildActionClass,
+		"causes": []any{map[string]any{"shortDescription": "Aborted by Carol Smith", "user": map[string]any{}}},
+	}})
+	if interruption == nil || interruption.By != "Carol Smith" {
+		t.Fatalf("unexpected interruption %+v", interruption)
+	}
+}
+
+func TestRunListAbortedByFilter(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/api/json", http.StatusOK, `{"builds":[
+		{"number":3,"result":"SUCCESS","timestamp":1700000300000},
+		{"number":2,"result":"ABORTED","timestamp":1700000200000,"actions":`+abortedActions+`},
+		{"number":1,"result":"ABORTED","timestamp":1700000100000,"actions":[]}
+	]}`)
+
+	execute := func(args ...string) runListOutput {
+		t.Helper()
+		f, stdout, stderr := fakejenkins.Factory(client)
+		cmd := NewCmdRun(f)
+		cmd.PersistentFlags().Bool("json", false, "")
+		cmd.PersistentFlags().Bool("yaml", false, "")
+		cmd.SetArgs(append([]string{"ls", "app", "--json"}, args...))
+		cmd.SetOut(stdout)
+		cmd.SetErr(stderr)
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("run ls: %v", err)
+		}
+		var output runListOutput
+		if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+			t.Fatalf("decode: %v\n%s", err, stdout.String())
+		}
+		return output
+	}
+
+	output := execute("--filter", "abortedBy=alice")
+	if len(output.Items) != 1 || output.Items[0].Number != 2 {
+		t.Fatalf("expected only run #2, got %+v", output.Items)
+	}
+	if output.Items[0].AbortedBy != "alice" || output.Items[0].AbortReason != "Aborted by alice" {
+		t.Fatalf("unexpected abort details %+v", output.Items[0])
+	}
+	if tree := server.LastRequest(http.MethodGet, "/job/app/api/json").Query.Get("tree"); !strings.Contains(tree, "causes[shortDescription,user]") {
+		t.Fatalf("expected interruption causes in tree, got %s", tree)
+	}
+
+	output = execute()
+	if len(output.Items) != 3 || output.Items[1].AbortedBy != "" {
+		t.Fatalf("unexpected unfiltered items %+v", output.Items)
+	}
+	if tree := server.LastRequest(http.MethodGet, "/job/app/api/json").Query.Get("tree"); strings.Contains(tree, "causes[") {
+		t.Fatalf("tree should not request causes without abort fields: %s", tree)
+	}
+}
+
+func TestFollowTriggeredRunCancelledInQueue(t *testing.T) {
+	useFastQueuePolling(t)
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/queue/item/7/api/json", http.StatusOK, `{"id":7,"cancelled":true}`)
+
+	cmd := &cobra.Command{}
+	cmd.PersistentFlags().Bool("json", true, "")
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	resp := &resty.Response{RawResponse: &http.Response{Header: http.Header{"Location": {"/queue/item/7/"}}}}
+	err := followTriggeredRun(cmd, client, "app", resp, followOptions{})
+	if code := exitCode(err); code != queueCancelledExitCode {
+		t.Fatalf("expected exit %d, got %v", queueCancelledExitCode, err)
+	}
+
+	var output queueCancelledOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout.String())
+	}
+	if output.Outcome != outcomeCancelledInQueue || output.QueueID != 7 || output.JobPath != "app" {
+		t.Fatalf("unexpected output %+v", output)
+	}
+}
@@ -0,0 +1,188 @@
+package run
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const permalinkFields = "number,result,building,timestamp,duration,url"
+
+// lastBuildsTree fetches the three build permalinks in one request.
+const lastBuildsTree = "lastBuild[" + permalinkFields + "],lastSuccessfulBuild[" + permalinkFields + "],lastFailedBuild[" + permalinkFields + "]"
+
+type permalinkBuild struct {
+	Number    int64  `json:"number"`
+	Result    string `json:"result"`
+	Building  bool   `json:"building"`
+	Timestamp int64  `json:"timestamp"`
+	Duration  int64  `json:"duration"`
+	URL       string `json:"url"`
+}
+
+type lastBuildsResponse struct {
+	LastBuild           *permalinkBuild `json:"lastBuild"`
+	LastSuccessfulBuild *permalinkBuild `json:"lastSuccessfulBuild"`
+	LastFailedBuild     *permalinkBuild `json:"lastFailedBuild"`
+}
+
+type runLastOutput struct {
+	JobPath             string        `json:"jobPath"`
+	LastBuild           *runPermalink `json:"lastBuild"`
+	LastSuccessfulBuild *runPermalink `json:"lastSuccessfulBuild"`
+	LastFailedBuild     *runPermalink `json:"lastFailedBuild"`
+	// BuildsSinceSuccess is nil when the job has never succeeded.
+	BuildsSinceSuccess *int64 `json:"buildsSinceSuccess"`
+}
+
+type runPermalink struct {
+	Number     int64  `json:"number"`
+	Status     string `json:"status"`
+	Result     string `json:"result,omitempty"`
+	StartTime  string `json:"startTime,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	URL        string `json:"url,omitempty"`
+}
+
+// NewCmdRunLast summarizes a job's latest build. It is also exposed as
+// `jk job last`.
+func NewCmdRunLast(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "last <jobPath>",
+		Short: "Show the latest build of a job and its last success and failure",
+		Long: `Summarize a job from its lastBuild, lastSuccessfulBuild, and lastFailedBuild
+permalinks: the latest build's result, start, and duration, how many builds
+ran since the last success, and the last success and failure.
+
+The exit code reflects the latest build's result (0 SUCCESS, 10 UNSTABLE,
+11 FAILURE, 12 ABORTED, 13 NOT_BUILT), so scripts can gate on it. A build that
+is still running exits 0.`,
+		Example: `  # What's the state of the deploy job?
+  jk job last team/deploy
+
+  # Only deploy when the latest build passed
+  jk run last team/app && ./deploy.sh`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			output, err := fetchLastBuilds(client, jobPath)
+			if err != nil {
+				return err
+			}
+			if output.LastBuild == nil {
+				return shared.NewExitError(3, fmt.Sprintf("job %s has never been built", output.JobPath))
+			}
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				return renderRunLastHuman(cmd, output, shared.TimeFormatter(cmd, f), shared.ResultFormatter(cmd, f))
+			}); err != nil {
+				return err
+			}
+
+			if code := exitCodeForResult(output.LastBuild.Result); code != 0 {
+				return shared.NewExitError(code, "")
+			}
+			return nil
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		3:  "Job not found or never built",
+		10: "Latest build is UNSTABLE",
+		11: "Latest build is FAILURE",
+		12: "Latest build is ABORTED",
+		13: "Latest build is NOT_BUILT",
+	})
+	return cmd
+}
+
+func fetchLastBuilds(client shared.Doer, jobPath string) (runLastOutput, error) {
+	var payload lastBuildsResponse
+	resp, err := client.Do(
+		client.NewRequest().SetQueryParam("tree", lastBuildsTree),
+		http.MethodGet,
+		fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath)),
+		&payload,
+	)
+	if err != nil {
+		return runLastOutput{}, err
+	}
+	if err := shared.CheckResponse(resp, fmt.Sprintf("job %s", jobPath)); err != nil {
+		return runLastOutput{}, err
+	}
+
+	output := runLastOutput{
+		JobPath:             jobpath.Normalize(jobPath),
+		LastBuild:           toRunPermalink(payload.LastBuild),
+		LastSuccessfulBuild: toRunPermalink(payload.LastSuccessfulBuild),
+		LastFailedBuild:     toRunPermalink(payload.LastFailedBuild),
+	}
+	if output.LastBuild != nil && output.LastSuccessfulBuild != nil {
+		// Build numbers can have gaps from deleted builds, so this is an
+		// upper bound.
+		since := output.LastBuild.Number - output.LastSuccessfulBuild.Number
+		output.BuildsSinceSuccess = &since
+	}
+	return output, nil
+}
+
+func toRunPermalink(build *permalinkBuild) *runPermalink {
+	if build == nil || build.Number <= 0 {
+		return nil
+	}
+	return &runPermalink{
+		Number:     build.Number,
+		Status:     statusFromFlags(build.Building),
+		Result:     resultForList(build.Result, build.Building),
+		StartTime:  shared.FormatTimestamp(build.Timestamp),
+		DurationMs: build.Duration,
+		URL:        build.URL,
+	}
+}
+
+func renderRunLastHuman(cmd *cobra.Command, output runLastOutput, stamp func(string) string, label func(string) string) error {
+	w := cmd.OutOrStdout()
+	latest := output.LastBuild
+	state := latest.Result
+	duration := shared.DurationString(latest.DurationMs)
+	if state == "" {
+		state = latest.Status
+		duration = "running"
+	}
+	_, _ = fmt.Fprintf(w, "Job: %s\n", output.JobPath)
+	_, _ = fmt.Fprintf(w, "Latest: #%d %s, %s (%s)\n", latest.Number, label(state), stamp(latest.StartTime), duration)
+
+	switch {
+	case output.BuildsSinceSuccess == nil:
+		_, _ = fmt.Fprintln(w, "Last success: never")
+	default:
+		success := output.LastSuccessfulBuild
+		_, _ = fmt.Fprintf(w, "Last success: #%d, %s (%s since)\n", success.Number, stamp(success.StartTime), buildCount(*output.BuildsSinceSuccess))
+	}
+	if failed := output.LastFailedBuild; failed != nil {
+		_, _ = fmt.Fprintf(w, "Last failure: #%d, %s\n", failed.Number, stamp(failed.StartTime))
+	}
+	return nil
+}
+
+func buildCount(n int64) string {
+	if n == 1 {
+		return "1 build"
+	}
+	return fmt.Sprintf("%d builds", n)
+}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func runLast(t *testing.T, body string, args ...string) (string, *fakejenkins.Server, error) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/api/json", http.StatusOK, body)
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRunLast(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(append([]string{"app"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	return stdout.String(), server, err
+}
+
+func exitCode(err error) int {
+	var exitErr *cmdutil.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+func TestRunLastJSON(t *testing.T) {
+	out, server, err := runLast(t, `{
+		"lastBuild":{"number":42,"result":"SUCCESS","timestamp":1700000000000,"duration":65000,"url":"https://ci/job/app/42/"},
+		"lastSuccessfulBuild":{"number":42,"result":"SUCCESS","timestamp":1700000000000,"duration":65000},
+		"lastFailedBuild":{"number":39,"result":"FAILURE","timestamp":1690000000000,"duration":1000}
+	}`, "--json")
+	if err != nil {
+		t.Fatalf("run last: %v", err)
+	}
+	if got := server.LastRequest(http.MethodGet, "/job/app/api/json").Query.Get("tree"); got != lastBuildsTree {
+		t.Fatalf("tree = %q, want one query for all permalinks", got)
+	}
+
+	var output runLastOutput
+	if err := json.Unmarshal([]byte(out), &output); err != nil {
+		t.Fatalf("decode: %v\n%s", err, out)
+	}
+	if output.LastBuild == nil || output.LastBuild.Number != 42 || output.LastBuild.Result != "SUCCESS" || output.LastBuild.URL == "" {
+		t.Fatalf("unexpected lastBuild %+v", output.LastBuild)
+	}
+	if output.LastSuccessfulBuild == nil || output.LastFailedBuild == nil || output.LastFailedBuild.Number != 39 {
+		t.Fatalf("missing permalinks: %+v", output)
+	}
+	if output.BuildsSinceSuccess == nil || *output.BuildsSinceSuccess != 0 {
+		t.Fatalf("buildsSinceSuccess = %v, want 0", output.BuildsSinceSuccess)
+	}
+}
+
+func TestRunLastExitCodeFollowsLatestResult(t *testing.T) {
+	out, _, err := runLast(t, `{
+		"lastBuild":{"number":45,"result":"FAILURE","timestamp":1700000000000,"duration":3000},
+		"lastSuccessfulBuild":{"number":42,"result":"SUCCESS","timestamp":1690000000000},
+		"lastFailedBuild":{"number":45,"result":"FAILURE","timestamp":1700000000000}
+	}`)
+	if code := exitCode(err); code != 11 {
+		t.Fatalf("exit code = %d (%v), want 11", code, err)
+	}
+	for _, want := range []string{"Latest: #45 FAILURE", "Last success: #42", "(3 builds since)", "Last failure: #45"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunLastRunningAndNeverSucceeded(t *testing.T) {
+	out, _, err := runLast(t, `{"lastBuild":{"number":1,"building":true,"timestamp":1700000000000},"lastSuccessfulBuild":null,"lastFailedBuild":null}`)
+	if err != nil {
+		t.Fatalf("running build should exit 0, got %v", err)
+	}
+	if !strings.Contains(out, "#1 running") || !strings.Contains(out, "Last success: never") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+func TestRunLastNeverBuilt(t *testing.T) {
+	_, _, err := runLast(t, `{"lastBuild":null,"lastSuccessfulBuild":null,"lastFailedBuild":null}`)
+	if code := exitCode(err); code != 3 || !strings.Contains(err.Error(), "never been built") {
+		t.Fatalf("expected exit 3 for a job that never built, got %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/lastrun"
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+type lastRunOutput struct {
+	SchemaVersion string        `json:"schemaVersion"`
+	Context       string        `json:"context"`
+	Run           lastRunRecord `json:"run"`
+}
+
+// lastRunRecord is a lastrun.Record with its instant formatted like every
+// other JSON time.
+type lastRunRecord struct {
+	Command          string            `json:"command"`
+	JobPath          string            `json:"jobPath"`
+	Build            int64             `json:"build,omitempty"`
+	QueueLocation    string            `json:"queueLocation,omitempty"`
+	Parameters       map[string]string `json:"parameters,omitempty"`
+	SecretParameters []string          `json:"secretParameters,omitempty"`
+	RecordedAt       string            `json:"recordedAt"`
+}
+
+func newLastRunRecord(record *lastrun.Record) lastRunRecord {
+	return lastRunRecord{
+		Command:          record.Command,
+		JobPath:          record.JobPath,
+		Build:            record.Build,
+		QueueLocation:    record.QueueLocation,
+		Parameters:       record.Parameters,
+		SecretParameters: record.SecretParameters,
+		RecordedAt:       shared.FormatTime(record.RecordedAt),
+	}
+}
+
+// rememberRun records a triggered run as the context's last run. The
+// returned func adds the build number once a followed run starts. Failing to
+// record never fails the command.
+func rememberRun(contextName string, record lastrun.Record) func(int64) {
+	save := func() {
+		if err := lastrun.Save(contextName, record); err != nil {
+			jklog.L().Debug().Err(err).Msg("record last run failed")
+		}
+	}
+	save()
+	return func(number int64) {
+		record.Build = number
+		save()
+	}
+}
+
+// loadLastRun returns the context's last run, or exit code 3 when there is
+// none.
+func loadLastRun(contextName string) (*lastrun.Record, error) {
+	record, err := lastrun.Load(contextName)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, shared.NewExitError(3, fmt.Sprintf("no run recorded for context %s; trigger one with jk run start", contextName))
+	}
+	return record, nil
+}
+
+// NewCmdLast shows the run recorded by the last run start or run rerun.
+func NewCmdLast(f *cmdutil.Factory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "last",
+		Short: "Show the last run triggered from this machine",
+		Long: `Show the last run jk run start or jk run rerun triggered in the current
+context: the job, the build number when known, and the parameters. Secret
+parameters are recorded by name only.
+
+Repeat it with jk rerun-last or stop it with jk cancel-last.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			record, err := loadLastRun(client.ContextName())
+			if err != nil {
+				return err
+			}
+			output := lastRunOutput{SchemaVersion: "1.0", Context: client.ContextName(), Run: newLastRunRecord(record)}
+			return shared.PrintOutput(cmd, output, func() error {
+				renderLastRun(cmd.OutOrStdout(), record, shared.TimeFormatter(cmd, f))
+				return nil
+			})
+		},
+	}
+}
+
+// NewCmdRerunLast triggers the last recorded run again.
+func NewCmdRerunLast(f *cmdutil.Factory) *cobra.Command {
+	var params []string
+	var follow bool
+	var interval time.Duration
+	var showStage bool
+	var waitQuiet bool
+	var gate stageGate
+	var retry shared.PollRetry
+	var cause triggerCause
+
+	cmd := &cobra.Command{
+		Use:   "rerun-last",
+		Short: "Trigger the last run again with the same parameters",
+		Long: `Trigger the job of the last recorded run again with the parameters it used.
+--param overrides a recorded value. Secret parameters were never stored, so
+each one must be passed again with --param.`,
+		Example: `  jk rerun-last --follow
+  jk rerun-last -p ENV=staging -p DEPLOY_TOKEN=$TOKEN`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := gate.validate(follow); err != nil {
+				return err
+			}
+			if err := retry.Validate(); err != nil {
+				return err
+			}
+			if err := cause.validate(follow); err != nil {
+				return err
+			}
+
+			overrides, err := parseParamFlags(params)
+			if err != nil {
+				return err
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			last, err := loadLastRun(client.ContextName())
+			if err != nil {
+				return err
+			}
+
+			paramMap := make(map[string]string, len(last.Parameters)+len(overrides))
+			for name, value := range last.Parameters {
+				paramMap[name] = value
+			}
+			var missing []string
+			for _, name := range last.SecretParameters {
+				if _, ok := overrides[name]; !ok {
+					missing = append(missing, name)
+				}
+			}
+			if len(missing) > 0 {
+				return shared.NewExitError(2, fmt.Sprintf("secret parameters are not recorded; pass them again with --param: %s", strings.Join(missing, ", ")))
+			}
+			for name, value := range overrides {
+				paramMap[name] = value
+			}
+
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
+			cause.Reason = resolveTriggerReason(cmd, client, cause.Reason)
+			resp, err := triggerBuild(client, last.JobPath, paramMap, cause)
+			if err != nil {
+				return err
+			}
+			record := rememberRun(client.ContextName(), lastrun.New(last.Command, last.JobPath, paramMap, queueLocationFromResponse(resp), redactor))
+
+			if !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd) {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered run for %s\n", last.JobPath)
+			}
+			if !follow {
+				if shared.WantsJSON(cmd) || shared.WantsYAML(cmd) {
+					payload := runTriggerOutput{
+						JobPath:       last.JobPath,
+						Message:       "run requested",
+						QueueLocation: queueLocationFromResponse(resp),
+						Cause:         cause.recorded(),
+					}
+					return shared.PrintOutput(cmd, payload, func() error { return nil })
+				}
+				return nil
+			}
+			return followTriggeredRun(cmd, client, last.JobPath, resp, followOptions{Interval: interval, ShowStage: showStage, WaitThroughQuietDown: waitQuiet, StageGate: gate, Retry: retry, Progress: followProgress(cmd, f), OnBuild: record, Annotation: cause.annotation()})
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&params, "param", "p", nil, "Override or supply a build parameter key=value")
+	cmd.Flags().BoolVar(&follow, "follow", false, "Follow the run progress until completion")
+	cmd.Flags().DurationVar(&interval, "interval", 500*time.Millisecond, "Polling interval when following runs")
+	cmd.Flags().BoolVar(&showStage, "show-stage", false, "Include the current pipeline stage in follow heartbeats (extra requests)")
+	cmd.Flags().BoolVar(&waitQuiet, "wait-through-quiet-down", false, "With --follow, keep waiting if Jenkins is quieting down instead of exiting 14")
+	addStageGateFlags(cmd, &gate)
+	shared.AddPollRetryFlags(cmd, &retry)
+	addReasonFlags(cmd, &cause)
+	cmdutil.SetExitCodes(cmd, followExitCodes())
+	return cmd
+}
+
+// NewCmdCancelLast cancels the last recorded run, or its queue item when it
+// has not started yet.
+func NewCmdCancelLast(f *cmdutil.Factory) *cobra.Command {
+	var mode string
+
+	cmd := &cobra.Command{
+		Use:   "cancel-last",
+		Short: "Cancel the last run triggered from this machine",
+		Long: `Cancel the last recorded run. A run triggered without --follow is found
+through its queue item; if it is still queued, the queue item is cancelled
+instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			action, err := resolveCancelAction(mode)
+			if err != nil {
+				return err
+			}
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			last, err := loadLastRun(client.ContextName())
+			if err != nil {
+				return err
+			}
+
+			if last.Build == 0 {
+				if strings.TrimSpace(last.QueueLocation) == "" {
+					return shared.NewExitError(3, "the last run has no build number or queue item to cancel")
+				}
+				var item queueItemStatus
+				if err := shared.FetchQueueItem(cmd.Context(), client, last.QueueLocation, "", &item); err != nil {
+					return err
+				}
+				switch {
+				case item.Executable != nil && item.Executable.Number > 0:
+					last.Build = item.Executable.Number
+					rememberRun(client.ContextName(), *last)
+				case item.Cancelled:
+					return shared.NewExitError(3, fmt.Sprintf("the last run of %s was already cancelled in the queue", last.JobPath))
+				default:
+					req := client.NewRequest().SetQueryParam("id", strconv.FormatInt(item.ID, 10))
+					resp, err := client.Do(req, http.MethodPost, "/queue/cancelItem", nil)
+					if err != nil {
+						return err
+					}
+					if resp.StatusCode() >= 300 {
+						return fmt.Errorf("cancel failed: %s", shared.ResponseStatus(resp))
+					}
+					return shared.PrintAction(cmd, shared.ActionResult{Action: "cancel", Target: fmt.Sprintf("%s (queue item %d)", last.JobPath, item.ID), Status: shared.ActionDone}, "Cancelled queued run of %s (queue item %d)", last.JobPath, item.ID)
+				}
+			}
+
+			if err := cancelBuild(client, last.JobPath, last.Build, action); err != nil {
+				return err
+			}
+			return shared.PrintAction(cmd, shared.ActionResult{Action: action, Target: fmt.Sprintf("%s #%d", last.JobPath, last.Build), Status: shared.ActionRequested}, "Cancellation requested for %s #%d (%s)", last.JobPath, last.Build, action)
+		},
+	}
+
+	cmd.Flags().StringVar(&mode, "mode", "stop", "Termination mode: stop, term, or kill")
+	cmdutil.SetFlagEnum(cmd, "mode", "stop", "term", "kill")
+	return cmd
+}
+
+func renderLastRun(w io.Writer, record *lastrun.Record, formatTime func(string) string) {
+	if record.Build > 0 {
+		_, _ = fmt.Fprintf(w, "%s %s #%d\n", record.Command, record.JobPath, record.Build)
+	} else {
+		_, _ = fmt.Fprintf(w, "%s %s (build number not recorded)\n", record.Command, record.JobPath)
+	}
+	if len(record.Parameters) > 0 {
+		names := make([]string, 0, len(record.Parameters))
+		for name := range record.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		pairs := make([]string, 0, len(names))
+		for _, name := range names {
+			pairs = append(pairs, name+"="+record.Parameters[name])
+		}
+		_, _ = fmt.Fprintf(w, "  parameters: %s\n", strings.Join(pairs, ", "))
+	}
+	if len(record.SecretParameters) > 0 {
+		_, _ = fmt.Fprintf(w, "  secret parameters (values not recorded): %s\n", strings.Join(record.SecretParameters, ", "))
+	}
+	_, _ = fmt.Fprintf(w, "  triggered: %s\n", formatTime(shared.FormatTime(record.RecordedAt)))
+}
@@ -0,0 +1,147 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/lastrun"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func executeLastUsed(t *testing.T, client *jenkins.Client, jsonOut bool, args ...string) (*bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", jsonOut, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.AddCommand(NewCmdRun(f), NewCmdLast(f), NewCmdRerunLast(f), NewCmdCancelLast(f))
+	root.SetArgs(args)
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	return stdout, root.Execute()
+}
+
+func triggerRecordedRun(t *testing.T) (*fakejenkins.Server, *jenkins.Client) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/releases/job/deploy/api/json", map[string]any{"buildable": true})
+	server.HandleHeaders(http.MethodPost, "/job/releases/job/deploy/buildWithParameters", http.StatusCreated,
+		http.Header{"Location": []string{"/queue/item/77/"}})
+
+	if _, err := executeLastUsed(t, client, false, "run", "start", "releases/deploy", "-p", "CHART=nova", "-p", "ENV=prod", "-p", "DEPLOY_TOKEN=s3cr3t"); err != nil {
+		t.Fatalf("run start: %v", err)
+	}
+	return server, client
+}
+
+func TestLastShowsRecordedRunWithoutSecrets(t *testing.T) {
+	_, client := triggerRecordedRun(t)
+
+	stdout, err := executeLastUsed(t, client, true, "last")
+	if err != nil {
+		t.Fatalf("last: %v", err)
+	}
+	var got lastRunOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout.String())
+	}
+	run := got.Run
+	if got.Context != "test" || run.Command != "run start" || run.JobPath != "releases/deploy" || run.Build != 0 {
+		t.Fatalf("unexpected record %+v", got)
+	}
+	if run.QueueLocation != "/queue/item/77/" {
+		t.Fatalf("unexpected queue location %q", run.QueueLocation)
+	}
+	if len(run.Parameters) != 2 || run.Parameters["CHART"] != "nova" || run.Parameters["ENV"] != "prod" {
+		t.Fatalf("unexpected parameters %v", run.Parameters)
+	}
+	if len(run.SecretParameters) != 1 || run.SecretParameters[0] != "DEPLOY_TOKEN" {
+		t.Fatalf("unexpected secret parameters %v", run.SecretParameters)
+	}
+
+	path, err := cachefile.Path("last", "test")
+	if err != nil {
+		t.Fatalf("path: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read record: %v", err)
+	}
+	if strings.Contains(string(data), "s3cr3t") {
+		t.Fatalf("secret value written to disk:\n%s", data)
+	}
+}
+
+func TestLastWithoutRecordExits3(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+	_, err := executeLastUsed(t, client, false, "last")
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("expected exit code 3, got %v", err)
+	}
+}
+
+func TestRerunLastRequiresSecretParameters(t *testing.T) {
+	server, client := triggerRecordedRun(t)
+
+	_, err := executeLastUsed(t, client, false, "rerun-last")
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 || !strings.Contains(err.Error(), "DEPLOY_TOKEN") {
+		t.Fatalf("expected exit code 2 naming DEPLOY_TOKEN, got %v", err)
+	}
+	if reqs := server.RequestsTo(http.MethodPost, "/job/releases/job/deploy/buildWithParameters"); len(reqs) != 1 {
+		t.Fatalf("expected no new trigger, got %d requests", len(reqs))
+	}
+
+	if _, err := executeLastUsed(t, client, false, "rerun-last", "-p", "DEPLOY_TOKEN=t0k3n", "-p", "ENV=staging"); err != nil {
+		t.Fatalf("rerun-last: %v", err)
+	}
+	form := server.LastRequest(http.MethodPost, "/job/releases/job/deploy/buildWithParameters").Form()
+	if form.Get("CHART") != "nova" || form.Get("ENV") != "staging" || form.Get("DEPLOY_TOKEN") != "t0k3n" {
+		t.Fatalf("unexpected parameters %v", form)
+	}
+}
+
+func TestCancelLastResolvesBuildFromQueue(t *testing.T) {
+	server, client := triggerRecordedRun(t)
+	server.HandleJSON(http.MethodGet, "/queue/item/77/api/json", map[string]any{"id": 77, "executable": map[string]any{"number": 12}})
+	server.Handle(http.MethodPost, "/job/releases/job/deploy/12/stop", http.StatusOK, "")
+
+	stdout, err := executeLastUsed(t, client, false, "cancel-last")
+	if err != nil {
+		t.Fatalf("cancel-last: %v", err)
+	}
+	if !strings.Contains(stdout.String(), "Cancellation requested for releases/deploy #12 (stop)") {
+		t.Fatalf("unexpected output %q", stdout.String())
+	}
+	record, err := lastrun.Load("test")
+	if err != nil || record == nil || record.Build != 12 {
+		t.Fatalf("expected the build number to be recorded, got %+v, %v", record, err)
+	}
+}
+
+func TestCancelLastCancelsQueuedItem(t *testing.T) {
+	server, client := triggerRecordedRun(t)
+	server.HandleJSON(http.MethodGet, "/queue/item/77/api/json", map[string]any{"id": 77, "why": "Waiting for next available executor"})
+	server.Handle(http.MethodPost, "/queue/cancelItem", http.StatusOK, "")
+
+	if _, err := executeLastUsed(t, client, false, "cancel-last"); err != nil {
+		t.Fatalf("cancel-last: %v", err)
+	}
+	if got := server.LastRequest(http.MethodPost, "/queue/cancelItem").Query.Get("id"); got != "77" {
+		t.Fatalf("expected queue item 77 cancelled, got %q", got)
+	}
+}
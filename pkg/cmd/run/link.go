@@ -0,0 +1,71 @@
+package run
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+type runLinkOutput struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Context       string `json:"context"`
+	JobPath       string `json:"jobPath"`
+	Number        int64  `json:"number"`
+	Ref           string `json:"ref"`
+	URL           string `json:"url"`
+}
+
+func newRunLinkCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link <jobPath> <buildNumber>",
+		Short: "Print a shareable reference to a run",
+		Long: `Print a reference to a run that teammates can pass to jk open, followed by
+the run's web URL. The reference names the context and the absolute job path:
+
+  jk://<context>/<jobPath>/<buildNumber>`,
+		Example: `  jk run link team/app 42
+  jk open jk://prod/team/app/42 --log`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			if client.ContextName() == "" {
+				return shared.NewExitError(2, "run link needs a configured context; pass --context")
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			num, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || num <= 0 {
+				return shared.NewExitError(2, fmt.Sprintf("invalid build number %q", args[1]))
+			}
+
+			ref := shared.RunRef{Context: client.ContextName(), JobPath: jobPath, Number: num}
+			output := runLinkOutput{
+				SchemaVersion: "1.0",
+				Context:       ref.Context,
+				JobPath:       ref.JobPath,
+				Number:        ref.Number,
+				Ref:           ref.String(),
+				URL:           fmt.Sprintf("%s/%s/%d/", strings.TrimSuffix(client.Context().URL, "/"), jobpath.Encode(jobPath), num),
+			}
+			return shared.PrintOutput(cmd, output, func() error {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), output.Ref)
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), output.URL)
+				return nil
+			})
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+	return cmd
+}
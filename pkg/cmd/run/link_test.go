@@ -0,0 +1,42 @@
+package run
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestRunLinkJSON(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"link", "team/feature%2Flogin", "42"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run link: %v", err)
+	}
+	var got runLinkOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	wantRef := "jk://" + client.ContextName() + "/team/feature%252Flogin/42"
+	if got.Ref != wantRef {
+		t.Fatalf("expected ref %q got %q", wantRef, got.Ref)
+	}
+	wantURL := strings.TrimSuffix(client.Context().URL, "/") + "/job/team/job/feature%252Flogin/42/"
+	if got.URL != wantURL {
+		t.Fatalf("expected url %q got %q", wantURL, got.URL)
+	}
+	if got.JobPath != "team/feature%2Flogin" || got.Number != 42 || got.Context != client.ContextName() {
+		t.Fatalf("unexpected output %+v", got)
+	}
+}
@@ -0,0 +1,210 @@
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestExecuteRunListStatusMapping(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   int
+		msg    string
+	}{
+		{"missing job", http.StatusNotFound, 3, "job team/missing not found"},
+		{"forbidden job", http.StatusForbidden, 5, "permission denied for job team/missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+
+			_, err := executeRunList(context.Background(), client, "team/missing", runListOptions{Limit: 5})
+			var exitErr *cmdutil.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("expected exit error, got %v", err)
+			}
+			if exitErr.Code != tt.code {
+				t.Fatalf("expected exit code %d, got %d", tt.code, exitErr.Code)
+			}
+			if !strings.HasPrefix(exitErr.Msg, tt.msg) {
+				t.Fatalf("unexpected message %q", exitErr.Msg)
+			}
+		})
+	}
+}
+
+func TestExecuteRunListEmptyJob(t *testing.T) {
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"builds":[]}`))
+	}))
+
+	out, err := executeRunList(context.Background(), client, "team/app", runListOptions{Limit: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(out.Items) != 0 {
+		t.Fatalf("expected no runs, got %d", len(out.Items))
+	}
+}
+
+func TestExecuteRunListFolder(t *testing.T) {
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/job/team/job/services/api/json": `{"_class":"com.cloudbees.hudson.plugins.folder.Folder","jobs":[{"name":"deploy-api"},{"name":"deploy-web"}]}`,
+	}))
+
+	_, err := executeRunList(context.Background(), client, "team/services", runListOptions{Limit: 5})
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit code 2, got %v", err)
+	}
+	if want := "team/services is a folder, not a job; did you mean one of: deploy-api, deploy-web"; exitErr.Msg != want {
+		t.Fatalf("message = %q, want %q", exitErr.Msg, want)
+	}
+	details, ok := exitErr.Details.(shared.FolderDetails)
+	if !ok || len(details.Children) != 2 || details.Children[1] != "team/services/deploy-web" {
+		t.Fatalf("unexpected details %#v", exitErr.Details)
+	}
+}
+
+// stubJobs serves /job/<name>/api/json from the supplied payloads and 404s
+// for anything else.
+func stubJobs(payloads map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := payloads[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func TestFetchParamsFromRunsEmptyAndMissingJobs(t *testing.T) {
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/job/fresh/api/json": `{"builds":[]}`,
+	}))
+
+	tests := []struct {
+		name     string
+		jobPath  string
+		wantErr  error
+		wantCode int
+	}{
+		{name: "job without runs", jobPath: "fresh", wantErr: errNoRunsToInfer},
+		{name: "missing job", jobPath: "ghost", wantCode: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fetchParamsFromRuns(context.Background(), client, tt.jobPath, 10, nil)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantCode != 0 {
+				var exitErr *cmdutil.ExitError
+				if !errors.As(err, &exitErr) || exitErr.Code != tt.wantCode {
+					t.Fatalf("expected exit code %d, got %v", tt.wantCode, err)
+				}
+			}
+		})
+	}
+}
+
+func TestExecuteRunSearchCountsJobsWithRuns(t *testing.T) {
+	started := time.Now().Add(-time.Hour).UnixMilli()
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/job/fresh/api/json":  `{"builds":[]}`,
+		"/job/active/api/json": fmt.Sprintf(`{"builds":[{"number":1,"result":"SUCCESS","timestamp":%d}]}`, started),
+	}))
+
+	out, err := executeRunSearch(context.Background(), client, []string{"fresh", "active"}, runSearchOptions{Limit: 10, MaxScan: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Metadata.JobsScanned != 2 || out.Metadata.JobsWithRuns != 1 {
+		t.Fatalf("expected 2 scanned and 1 with runs, got %+v", out.Metadata)
+	}
+	if len(out.Items) != 1 || out.Items[0].JobPath != "active" {
+		t.Fatalf("expected the single run from active, got %+v", out.Items)
+	}
+
+	out, err = executeRunSearch(context.Background(), client, []string{"ghost", "active"}, runSearchOptions{Limit: 10, MaxScan: 10})
+	if err != nil {
+		t.Fatalf("a missing job should not abort the search: %v", err)
+	}
+	if len(out.Items) != 1 || out.Summary != (shared.ResultSummary{Succeeded: 1, Failed: 1}) {
+		t.Fatalf("expected active's run and one failed job, got %+v %+v", out.Items, out.Summary)
+	}
+	if len(out.Errors) != 1 || out.Errors[0].Target != "ghost" || out.Errors[0].Code != 3 {
+		t.Fatalf("expected a not-found error for ghost, got %+v", out.Errors)
+	}
+}
+
+func TestRunListLimitFollowsTerminalHeight(t *testing.T) {
+	t.Setenv("LINES", "")
+	builds := make([]string, 30)
+	for i := range builds {
+		builds[i] = fmt.Sprintf(`{"number":%d,"result":"SUCCESS","timestamp":%d}`, 30-i, 1700000000000-int64(i)*1000)
+	}
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/api/json", http.StatusOK, `{"builds":[`+strings.Join(builds, ",")+`]}`)
+
+	tests := []struct {
+		name string
+		tty  bool
+		args []string
+		rows int
+		note bool
+	}{
+		{name: "terminal", tty: true, rows: 9, note: true},
+		{name: "explicit limit", tty: true, args: []string{"--limit", "4"}, rows: 4},
+		{name: "piped", tty: false, rows: defaultRunListLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, stdout, stderr := fakejenkins.Factory(client)
+			f.IOStreams.SetStdoutTTY(tt.tty)
+			f.IOStreams.SetTerminalHeight(12)
+			cmd := NewCmdRun(f)
+			cmd.PersistentFlags().Bool("json", false, "")
+			cmd.PersistentFlags().Bool("yaml", false, "")
+			cmd.SetArgs(append([]string{"ls", "app"}, tt.args...))
+			cmd.SetOut(stdout)
+			cmd.SetErr(stderr)
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("run ls: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+			rows := 0
+			for _, line := range lines {
+				if strings.HasPrefix(line, "#") {
+					rows++
+				}
+			}
+			if rows != tt.rows {
+				t.Fatalf("expected %d rows, got %d:\n%s", tt.rows, rows, stdout.String())
+			}
+			hasNote := lines[len(lines)-1] == "showing 9 most recent; use --limit to override"
+			if hasNote != tt.note || (!tt.note && strings.Contains(stdout.String(), "use --limit")) {
+				t.Fatalf("limit note = %v, want %v:\n%s", hasNote, tt.note, stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	// maxLogTailLines caps --with-log-tail so a listing stays responsive.
+	maxLogTailLines = 200
+	// logTailBytes is how much of the end of each console log is read.
+	logTailBytes = 64 * 1024
+	// logTailScanBytes bounds the progressiveText fallback when Jenkins
+	// neither honours Range nor reports the log size.
+	logTailScanBytes = 4 * 1024 * 1024
+)
+
+// logTailTarget is a listed run whose console tail may be attached.
+type logTailTarget struct {
+	JobPath string
+	Number  int64
+	Result  string
+	Tail    *[]string
+}
+
+func validateLogTailLines(n int) error {
+	if n < 0 || n > maxLogTailLines {
+		return shared.NewExitError(2, fmt.Sprintf("--with-log-tail must be between 0 and %d", maxLogTailLines))
+	}
+	return nil
+}
+
+// wantsLogTail reports whether a run with result gets its console tail;
+// successful and still-running builds never do.
+func wantsLogTail(result string) bool {
+	switch strings.ToUpper(strings.TrimSpace(result)) {
+	case "FAILURE", "UNSTABLE":
+		return true
+	}
+	return false
+}
+
+// attachLogTails fetches the last lines of console output for every failed
+// or unstable target with bounded concurrency. A log that cannot be read is
+// left empty rather than failing the listing.
+func attachLogTails(ctx context.Context, client shared.Doer, targets []logTailTarget, lines, concurrency int) {
+	if lines <= 0 {
+		return
+	}
+	shared.ForEach(len(targets), concurrency, func(i int) {
+		target := targets[i]
+		if !wantsLogTail(target.Result) {
+			return
+		}
+		tail, err := fetchConsoleTail(ctx, client, target.JobPath, target.Number, lines)
+		if err != nil {
+			jklog.L().Debug().Err(err).Str("job", target.JobPath).Int64("build", target.Number).Msg("fetch log tail failed")
+			return
+		}
+		*target.Tail = tail
+	})
+}
+
+// fetchConsoleTail returns the last lines of a build log, reading at most
+// logTailBytes from its end. It asks consoleText for a suffix range and falls
+// back to progressiveText from the computed offset when Range is ignored.
+func fetchConsoleTail(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64, lines int) ([]string, error) {
+	base := fmt.Sprintf("/%s/%d", jobpath.Encode(jobPath), buildNumber)
+	req := client.NewStreamingRequest().
+		SetHeader("Accept", "text/plain").
+		SetHeader("Range", fmt.Sprintf("bytes=-%d", logTailBytes)).
+		SetDoNotParseResponse(true)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+
+	resp, err := client.Do(req, http.MethodGet, base+"/consoleText", nil)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.RawBody()
+	if body == nil {
+		return nil, nil
+	}
+
+	switch resp.StatusCode() {
+	case http.StatusPartialContent:
+		defer func() { _ = body.Close() }()
+		data, err := io.ReadAll(io.LimitReader(body, logTailBytes))
+		if err != nil {
+			return nil, fmt.Errorf("read console: %w", err)
+		}
+		partial := !strings.HasPrefix(resp.Header().Get("Content-Range"), "bytes 0-")
+		return lastLines(data, partial, lines), nil
+	case http.StatusOK:
+		size := int64(-1)
+		if resp.RawResponse != nil {
+			size = resp.RawResponse.ContentLength
+		}
+		if size >= 0 && size <= logTailBytes {
+			defer func() { _ = body.Close() }()
+			data, err := io.ReadAll(io.LimitReader(body, logTailBytes))
+			if err != nil {
+				return nil, fmt.Errorf("read console: %w", err)
+			}
+			return lastLines(data, false, lines), nil
+		}
+		_ = body.Close()
+		return fetchProgressiveTail(ctx, client, base, size, lines)
+	default:
+		// 416 means an empty log; other failures leave the tail out.
+		_ = body.Close()
+		return nil, nil
+	}
+}
+
+// fetchProgressiveTail reads progressiveText from logTailBytes before the end
+// of a log of size bytes (from the start when size is unknown), keeping only
+// the last logTailBytes of at most logTailScanBytes read.
+func fetchProgressiveTail(ctx context.Context, client shared.Doer, base string, size int64, lines int) ([]string, error) {
+	start := int64(0)
+	if size > logTailBytes {
+		start = size - logTailBytes
+	}
+	req := client.NewStreamingRequest().
+		SetHeader("Accept", "text/plain").
+		SetQueryParam("start", strconv.FormatInt(start, 10)).
+		SetDoNotParseResponse(true)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+
+	resp, err := client.Do(req, http.MethodGet, base+"/logText/progressiveText", nil)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.RawBody()
+	if body == nil {
+		return nil, nil
+	}
+	defer func() { _ = body.Close() }()
+	if resp.StatusCode() >= 400 {
+		return nil, nil
+	}
+
+	var (
+		window  []byte
+		dropped bool
+		chunk   = make([]byte, 32*1024)
+		reader  = io.LimitReader(body, logTailScanBytes)
+	)
+	for {
+		n, err := reader.Read(chunk)
+		window = append(window, chunk[:n]...)
+		if excess := len(window) - logTailBytes; excess > 0 {
+			window = append(window[:0], window[excess:]...)
+			dropped = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read console: %w", err)
+		}
+	}
+	return lastLines(window, start > 0 || dropped, lines), nil
+}
+
+// lastLines splits data into lines and keeps the last n. When data starts
+// mid-log, its first line is usually cut and is dropped.
+func lastLines(data []byte, partial bool, n int) []string {
+	text := string(data)
+	if partial {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			return nil
+		}
+		text = text[i+1:]
+	}
+	text = strings.TrimRight(text, "\r\n")
+	if text == "" {
+		return nil
+	}
+	all := strings.Split(text, "\n")
+	if len(all) > n {
+		all = all[len(all)-n:]
+	}
+	out := make([]string, len(all))
+	for i, line := range all {
+		out[i] = strings.TrimSuffix(line, "\r")
+	}
+	return out
+}
+
+// writeLogTail prints a run's console tail as an indented block under its
+// row in human output.
+func writeLogTail(w io.Writer, tail []string) {
+	for _, line := range tail {
+		_, _ = fmt.Fprintf(w, "    %s\n", line)
+	}
+}
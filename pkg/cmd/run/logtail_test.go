@@ -0,0 +1,98 @@
+package run
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestLastLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		partial bool
+		n       int
+		expect  []string
+	}{
+		{"whole log", "a\nb\nc\n", false, 2, []string{"b", "c"}},
+		{"fewer lines than asked", "a\r\nb\r\n", false, 5, []string{"a", "b"}},
+		{"partial drops cut line", "ut line\nb\nc", true, 5, []string{"b", "c"}},
+		{"partial without newline", "no newline", true, 5, nil},
+		{"empty", "\n", false, 5, nil},
+	}
+
+	for _, tt := range tests {
+		if got := lastLines([]byte(tt.data), tt.partial, tt.n); !reflect.DeepEqual(got, tt.expect) {
+			t.Fatalf("%s: expected %q got %q", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestRunListWithLogTail(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/api/json", http.StatusOK, `{"builds":[
+		{"number":3,"result":"FAILURE","timestamp":1700000300000},
+		{"number":2,"result":"SUCCESS","timestamp":1700000200000},
+		{"number":1,"result":"UNSTABLE","timestamp":1700000100000}
+	]}`)
+	server.Handle(http.MethodGet, "/job/app/3/consoleText", http.StatusPartialContent, "cut line\nmake: *** [all] Error 2\nFinished: FAILURE\n")
+	// Range ignored on a log larger than the tail window: read the end
+	// through progressiveText instead.
+	server.HandleHeaders(http.MethodGet, "/job/app/1/consoleText", http.StatusOK, http.Header{"Content-Length": {strconv.Itoa(logTailBytes + 10)}})
+	server.Handle(http.MethodGet, "/job/app/1/logText/progressiveText", http.StatusOK, "xxxx\n1 test failed\nFinished: UNSTABLE\n")
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"ls", "app", "--with-log-tail", "1", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run ls: %v", err)
+	}
+
+	var output runListOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout.String())
+	}
+	tails := map[int64][]string{}
+	for _, item := range output.Items {
+		tails[item.Number] = item.LogTail
+	}
+	if !reflect.DeepEqual(tails[3], []string{"Finished: FAILURE"}) || !reflect.DeepEqual(tails[1], []string{"Finished: UNSTABLE"}) || tails[2] != nil {
+		t.Fatalf("unexpected tails %q", tails)
+	}
+
+	if got := server.LastRequest(http.MethodGet, "/job/app/3/consoleText").Header.Get("Range"); got != "bytes=-65536" {
+		t.Fatalf("expected suffix range, got %q", got)
+	}
+	if got := server.LastRequest(http.MethodGet, "/job/app/1/logText/progressiveText").Query.Get("start"); got != "10" {
+		t.Fatalf("expected progressiveText from offset 10, got %q", got)
+	}
+	if n := len(server.RequestsTo(http.MethodGet, "/job/app/2/consoleText")); n != 0 {
+		t.Fatalf("successful run fetched %d times", n)
+	}
+}
+
+func TestRunListWithLogTailRejectsLargeValues(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"ls", "app", "--with-log-tail", "201"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if code := exitCode(cmd.Execute()); code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+}
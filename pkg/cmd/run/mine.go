@@ -0,0 +1,28 @@
+package run
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+func addMineFlag(cmd *cobra.Command, mine *bool) {
+	cmd.Flags().BoolVar(mine, "mine", false, "Only runs started by the authenticated user; same as --filter cause.user=<your id>")
+}
+
+// mineFilterArg compiles --mine into a cause.user filter for the current
+// user, so it is evaluated and reported like a hand-written one.
+func mineFilterArg(ctx context.Context, client *jenkins.Client) (string, error) {
+	identity, err := client.WhoAmI(ctx)
+	if err != nil {
+		return "", err
+	}
+	if identity.Anonymous {
+		return "", shared.NewExitError(4, "--mine needs an authenticated user, but Jenkins treats this session as anonymous; log in with jk auth login or pass --filter cause.user=<id>")
+	}
+	return "cause.user" + string(filter.OpEQ) + identity.ID, nil
+}
@@ -1,8 +1,16 @@
 package run
 
 import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
 func TestParseSelectFields(t *testing.T) {
@@ -40,7 +48,8 @@ func TestNormalizeAggregation(t *testing.T) {
 }
 
 func TestParseSince(t *testing.T) {
-	ts, err := parseSince("2025-10-01T00:00:00Z")
+	now := time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)
+	ts, err := parseSince("2025-10-01T00:00:00Z", now)
 	if err != nil {
 		t.Fatalf("expected RFC3339 parse success, got %v", err)
 	}
@@ -48,12 +57,420 @@ func TestParseSince(t *testing.T) {
 		t.Fatalf("unexpected timestamp %s", ts)
 	}
 
-	value, err := parseSince("1h")
+	value, err := parseSince("1h", now)
 	if err != nil {
 		t.Fatalf("parseSince duration error: %v", err)
 	}
-	diff := time.Since(value)
-	if diff < 30*time.Minute || diff > 90*time.Minute {
-		t.Fatalf("expected diff to be near 1h, got %s", diff)
+	if !value.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("expected 1h before now, got %s", value)
+	}
+}
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Now()
+	since, until, err := parseTimeRange("2025-07-01T00:00:00Z", "2025-10-01T00:00:00Z", now)
+	if err != nil {
+		t.Fatalf("parseTimeRange error: %v", err)
+	}
+	if since == nil || until == nil || !until.After(*since) {
+		t.Fatalf("expected ordered bounds, got %v and %v", since, until)
+	}
+
+	if _, _, err := parseTimeRange("1d", "7d", now); err == nil {
+		t.Fatal("expected error when --until precedes --since")
+	}
+	if _, _, err := parseTimeRange("", "bogus", now); err == nil {
+		t.Fatal("expected error for invalid until value")
+	}
+}
+
+// TestParseTimeRangeUsesServerClock checks that relative bounds follow a
+// controller clock running ahead of the local one while absolute values are
+// kept as typed.
+func TestParseTimeRangeUsesServerClock(t *testing.T) {
+	local := time.Date(2025, 10, 5, 12, 0, 0, 0, time.UTC)
+	server := local.Add(7 * time.Minute)
+
+	since, until, err := parseTimeRange("10m", "2025-10-05T12:05:00Z", server)
+	if err != nil {
+		t.Fatalf("parseTimeRange error: %v", err)
+	}
+	if want := local.Add(-3 * time.Minute); !since.Equal(want) {
+		t.Fatalf("expected since %s, got %s", want, since)
+	}
+	if want := time.Date(2025, 10, 5, 12, 5, 0, 0, time.UTC); !until.Equal(want) {
+		t.Fatalf("absolute until changed: %s", until)
+	}
+}
+
+func TestProcessRunListTimeWindow(t *testing.T) {
+	day := func(d int) int64 {
+		return time.Date(2025, 7, d, 12, 0, 0, 0, time.UTC).UnixMilli()
+	}
+	builds := []runSummary{
+		{Number: 4, Result: "SUCCESS", Timestamp: day(20)},
+		{Number: 3, Result: "SUCCESS", Timestamp: day(15)},
+		{Number: 2, Result: "FAILURE", Timestamp: day(10)},
+		{Number: 1, Result: "SUCCESS", Timestamp: day(5)},
+	}
+	since := time.Date(2025, 7, 8, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC)
+
+	out, _, err := processRunList("team/app", runListOptions{Limit: 10, Since: &since, Until: &until, WithMeta: true}, builds, runListRequirements{})
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if len(out.Items) != 2 || out.Items[0].Number != 3 || out.Items[1].Number != 2 {
+		t.Fatalf("expected runs #3 and #2 inside the window, got %+v", out.Items)
+	}
+	if out.Metadata == nil || out.Metadata.Since != "2025-07-08T00:00:00Z" || out.Metadata.Until != "2025-07-18T00:00:00Z" {
+		t.Fatalf("expected metadata to record both bounds, got %+v", out.Metadata)
+	}
+}
+
+func TestProcessRunListSinceBuild(t *testing.T) {
+	builds := cursorTestBuilds() // #6..#1, one second apart
+
+	out, _, err := processRunList("team/app", runListOptions{Limit: 10, SinceBuild: 3, WithMeta: true}, builds, runListRequirements{})
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if len(out.Items) != 3 || out.Items[0].Number != 6 || out.Items[2].Number != 4 {
+		t.Fatalf("expected runs #6..#4 above the bound, got %+v", out.Items)
+	}
+	if out.Metadata == nil || out.Metadata.SinceBuild != 3 {
+		t.Fatalf("expected metadata to record the build bound, got %+v", out.Metadata)
+	}
+
+	// With --since cutting earlier, the time bound wins.
+	since := time.UnixMilli(5000)
+	out, _, err = processRunList("team/app", runListOptions{Limit: 10, SinceBuild: 3, Since: &since}, builds, runListRequirements{})
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if len(out.Items) != 2 || out.Items[1].Number != 5 {
+		t.Fatalf("expected runs #6 and #5, got %+v", out.Items)
+	}
+
+	out, _, err = processRunList("team/app", runListOptions{Limit: 10, SinceBuild: 99}, builds, runListRequirements{})
+	if err != nil || len(out.Items) != 0 {
+		t.Fatalf("expected a bound above the newest build to match nothing, got %+v, %v", out.Items, err)
+	}
+}
+
+func chartBuilds(charts ...string) []runSummary {
+	builds := make([]runSummary, len(charts))
+	for i, chart := range charts {
+		number := int64(len(charts) - i)
+		builds[i] = runSummary{
+			Number:    number,
+			Result:    "SUCCESS",
+			Timestamp: number * 1000,
+			Actions: []map[string]any{{
+				"parameters": []any{map[string]any{"name": "CHART", "value": chart}},
+			}},
+		}
+	}
+	return builds
+}
+
+func groupValues(groups []runListGroup) []string {
+	values := make([]string, len(groups))
+	for i, group := range groups {
+		values[i] = group.Value
+	}
+	return values
+}
+
+func TestProcessRunListGroupOrder(t *testing.T) {
+	// Counts: api 3, Web 2, web 2, db 2, cache 1. Equal counts sort by value
+	// ignoring case, then by exact value.
+	builds := chartBuilds("api", "web", "db", "Web", "api", "cache", "db", "Web", "api", "web")
+	opts := runListOptions{Limit: 10, GroupBy: "param.CHART", Aggregation: "count"}
+	reqs := runListRequirements{Parameters: true}
+
+	out, _, err := processRunList("team/app", opts, builds, reqs)
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	want := []string{"api", "db", "Web", "web", "cache"}
+	if got := groupValues(out.Groups); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected groups %v, got %v", want, got)
+	}
+	if out.GroupCount != 5 || out.HasMoreGroups {
+		t.Fatalf("expected 5 groups without truncation, got count %d, hasMore %v", out.GroupCount, out.HasMoreGroups)
+	}
+}
+
+func TestProcessRunListGroupLimit(t *testing.T) {
+	builds := chartBuilds("api", "web", "db", "Web", "api", "cache", "db", "Web", "api", "web")
+	reqs := runListRequirements{Parameters: true}
+
+	out, _, err := processRunList("team/app", runListOptions{Limit: 10, GroupBy: "param.CHART", GroupLimit: 2, Aggregation: "count"}, builds, reqs)
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if got := groupValues(out.Groups); strings.Join(got, ",") != "api,db" {
+		t.Fatalf("expected the two largest groups, got %v", got)
+	}
+	if out.GroupCount != 5 || !out.HasMoreGroups {
+		t.Fatalf("expected truncation from 5 groups, got count %d, hasMore %v", out.GroupCount, out.HasMoreGroups)
+	}
+	if len(out.Items) != 10 {
+		t.Fatalf("expected --group-limit to leave items alone, got %d", len(out.Items))
+	}
+
+	// --limit still bounds the runs listed; groups cover every matched run.
+	out, _, err = processRunList("team/app", runListOptions{Limit: 3, GroupBy: "param.CHART", GroupLimit: 5, Aggregation: "count"}, builds, reqs)
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if len(out.Items) != 3 || out.GroupCount != 5 || out.HasMoreGroups {
+		t.Fatalf("expected 3 items and all 5 groups, got %d items, count %d, hasMore %v", len(out.Items), out.GroupCount, out.HasMoreGroups)
+	}
+}
+
+func TestRunCursorWithSinceBuild(t *testing.T) {
+	opts := runListOptions{Limit: 2, SinceBuild: 1}
+	first, _, err := processRunList("team/app", opts, cursorTestBuilds(), runListRequirements{})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+
+	opts.Cursor = first.NextCursor
+	second, _, err := processRunList("team/app", opts, cursorTestBuilds(), runListRequirements{})
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second.Items) != 2 || second.Items[0].Number != 4 || second.Items[1].Number != 3 {
+		t.Fatalf("expected runs #4 and #3, got %+v", second.Items)
+	}
+	third, _, err := processRunList("team/app", runListOptions{Limit: 2, SinceBuild: 1, Cursor: second.NextCursor}, cursorTestBuilds(), runListRequirements{})
+	if err != nil || len(third.Items) != 1 || third.Items[0].Number != 2 || third.NextCursor != "" {
+		t.Fatalf("expected final page with run #2, got %+v, %v", third, err)
+	}
+
+	if _, _, err := processRunList("team/app", runListOptions{Limit: 2, SinceBuild: 2, Cursor: first.NextCursor}, cursorTestBuilds(), runListRequirements{}); err == nil {
+		t.Fatalf("expected a changed --since-build to invalidate the cursor")
+	}
+	if runCursorScope(runListOptions{}) == runCursorScope(runListOptions{SinceBuild: 1}) {
+		t.Fatalf("expected --since-build to change the cursor scope")
+	}
+}
+
+func TestBuildRunListTreeArtifactFields(t *testing.T) {
+	base := "number,url,result,building,timestamp,duration,estimatedDuration,queueId,actions[lastBuiltRevision[SHA1,branch[name]],buildsByBranchName[*],remoteUrls],changeSet[items[authorEmail,author[fullName],commitId,msg]]"
+
+	tests := []struct {
+		name string
+		opts runListOptions
+		want string
+	}{
+		{
+			name: "no artifact references",
+			opts: runListOptions{},
+			want: "builds[" + base + "]{,25}",
+		},
+		{
+			name: "artifact name filter",
+			opts: runListOptions{Filters: []filter.Filter{{Key: "artifact.name", Operator: filter.OpSUB, Value: "report"}}},
+			want: "builds[" + base + ",artifacts[fileName]{0,100}]{,25}",
+		},
+		{
+			name: "artifact path grouping",
+			opts: runListOptions{GroupBy: "artifact.path"},
+			want: "builds[" + base + ",artifacts[relativePath]{0,100}]{,25}",
+		},
+		{
+			name: "name filter with path grouping",
+			opts: runListOptions{Filters: []filter.Filter{{Key: "artifact.name", Operator: filter.OpEQ, Value: "a.txt"}}, GroupBy: "artifact.path"},
+			want: "builds[" + base + ",artifacts[fileName,relativePath]{0,100}]{,25}",
+		},
+		{
+			name: "artifacts selected for output",
+			opts: runListOptions{SelectFields: []string{"artifacts"}},
+			want: "builds[" + base + ",artifacts[fileName,relativePath,size]{0,100}]{,25}",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := buildRunListTree(25, resolveRunListRequirements(tt.opts)); got != tt.want {
+			t.Fatalf("%s: unexpected tree\n got: %s\nwant: %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProcessRunListNotesArtifactCap(t *testing.T) {
+	artifacts := make([]artifactItem, runListArtifactCap)
+	for i := range artifacts {
+		artifacts[i] = artifactItem{FileName: "file.txt"}
+	}
+	builds := []runSummary{{Number: 1, Result: "SUCCESS", Timestamp: time.Now().UnixMilli(), Artifacts: artifacts}}
+	opts := runListOptions{Limit: 5, WithMeta: true, Filters: []filter.Filter{{Key: "artifact.name", Operator: filter.OpSUB, Value: "report"}}}
+
+	out, _, err := processRunList("team/app", opts, builds, resolveRunListRequirements(opts))
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if out.Metadata == nil || len(out.Metadata.Notes) != 1 {
+		t.Fatalf("expected artifact cap note, got %+v", out.Metadata)
+	}
+}
+
+func cursorTestBuilds() []runSummary {
+	builds := make([]runSummary, 0, 6)
+	for n := int64(6); n >= 1; n-- {
+		builds = append(builds, runSummary{Number: n, Result: "SUCCESS", Timestamp: n * 1000})
+	}
+	return builds
+}
+
+func TestRunCursorResumesWithSameFilters(t *testing.T) {
+	filters, err := filter.Parse([]string{"result=SUCCESS"})
+	if err != nil {
+		t.Fatalf("parse filters: %v", err)
+	}
+	opts := runListOptions{Limit: 2, Filters: filters, SinceArg: "7d"}
+	first, _, err := processRunList("team/app", opts, cursorTestBuilds(), runListRequirements{})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if first.NextCursor == "" {
+		t.Fatalf("expected next cursor")
+	}
+
+	opts.Cursor = first.NextCursor
+	second, _, err := processRunList("team/app", opts, cursorTestBuilds(), runListRequirements{})
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second.Items) != 2 || second.Items[0].Number != 4 || second.Items[1].Number != 3 {
+		t.Fatalf("expected runs #4 and #3, got %+v", second.Items)
+	}
+}
+
+func TestRunCursorRejectsChangedFilters(t *testing.T) {
+	opts := runListOptions{Limit: 2}
+	first, _, err := processRunList("team/app", opts, cursorTestBuilds(), runListRequirements{})
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+
+	filters, err := filter.Parse([]string{"result=FAILURE"})
+	if err != nil {
+		t.Fatalf("parse filters: %v", err)
+	}
+	changed := runListOptions{Limit: 2, Cursor: first.NextCursor, Filters: filters}
+	_, _, err = processRunList("team/app", changed, cursorTestBuilds(), runListRequirements{})
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit 2 for changed filters, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "different filters") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+
+	changed.IgnoreCursorScope = true
+	if _, _, err := processRunList("team/app", changed, cursorTestBuilds(), runListRequirements{}); err != nil {
+		t.Fatalf("expected --cursor-ignore-filters to resume, got %v", err)
+	}
+
+	regex := runListOptions{Limit: 2, Cursor: first.NextCursor, AllowRegex: true}
+	if _, _, err := processRunList("team/app", regex, cursorTestBuilds(), runListRequirements{}); err == nil {
+		t.Fatalf("expected toggling --regex to invalidate the cursor")
+	}
+}
+
+func TestRunCursorAcceptsLegacyFormat(t *testing.T) {
+	legacy := base64.RawURLEncoding.EncodeToString([]byte(`{"jobPath":"team/app","number":5}`))
+
+	payload, err := decodeRunCursor(legacy)
+	if err != nil {
+		t.Fatalf("decode legacy cursor: %v", err)
+	}
+	if payload.Version != 0 || payload.Number != 5 {
+		t.Fatalf("unexpected legacy payload: %+v", payload)
+	}
+
+	filters, err := filter.Parse([]string{"result=SUCCESS"})
+	if err != nil {
+		t.Fatalf("parse filters: %v", err)
+	}
+	out, _, err := processRunList("team/app", runListOptions{Limit: 10, Cursor: legacy, Filters: filters}, cursorTestBuilds(), runListRequirements{})
+	if err != nil {
+		t.Fatalf("legacy cursor rejected: %v", err)
+	}
+	if len(out.Items) != 4 || out.Items[0].Number != 4 {
+		t.Fatalf("expected runs below #5, got %+v", out.Items)
+	}
+}
+
+func TestRunListWarnsOnLegacyCursor(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/api/json", http.StatusOK, `{"builds":[{"number":2,"result":"SUCCESS"},{"number":1,"result":"SUCCESS"}]}`)
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := newRunListCmd(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"app", "--cursor", base64.RawURLEncoding.EncodeToString([]byte(`{"jobPath":"app","number":2}`))})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run ls: %v", err)
+	}
+	if !strings.Contains(stderr.String(), "cursor predates filter tracking") {
+		t.Fatalf("expected legacy cursor warning, got %q", stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "#1") {
+		t.Fatalf("expected run #1 in output, got %q", stdout.String())
+	}
+}
+
+func TestValidateArtifactBytes(t *testing.T) {
+	if err := validateArtifactBytes([]string{"artifactbytes"}, "count"); exitCode(err) != 2 {
+		t.Fatalf("expected exit 2 without the artifacts selection, got %v", err)
+	}
+	if err := validateArtifactBytes(nil, aggSumArtifactBytes); exitCode(err) != 2 {
+		t.Fatalf("expected exit 2 for --agg sum-artifact-bytes without artifacts, got %v", err)
+	}
+	if err := validateArtifactBytes([]string{"artifactbytes", "artifacts"}, aggSumArtifactBytes); err != nil {
+		t.Fatalf("expected artifact totals with artifacts selected, got %v", err)
+	}
+	if err := validateArtifactBytes([]string{"result"}, "count"); err != nil {
+		t.Fatalf("expected no error without artifact totals, got %v", err)
+	}
+}
+
+func TestProcessRunListSumArtifactBytes(t *testing.T) {
+	now := time.Now().UnixMilli()
+	build := func(n int64, chart string, sizes ...int64) runSummary {
+		var artifacts []artifactItem
+		for _, size := range sizes {
+			artifacts = append(artifacts, artifactItem{FileName: "a.tgz", RelativePath: "a.tgz", Size: size})
+		}
+		return runSummary{
+			Number:    n,
+			Result:    "SUCCESS",
+			Timestamp: now - n,
+			Actions:   []map[string]any{{"parameters": []any{map[string]any{"name": "CHART", "value": chart}}}},
+			Artifacts: artifacts,
+		}
+	}
+	builds := []runSummary{build(1, "api", 10), build(2, "web", 300), build(3, "api", 20, 5), build(4, "db")}
+	opts := runListOptions{Limit: 10, GroupBy: "param.CHART", Aggregation: aggSumArtifactBytes, SelectFields: []string{"artifactbytes", "artifacts"}}
+
+	out, _, err := processRunList("team/app", opts, builds, resolveRunListRequirements(opts))
+	if err != nil {
+		t.Fatalf("processRunList error: %v", err)
+	}
+	if got := groupValues(out.Groups); strings.Join(got, ",") != "web,api,db" {
+		t.Fatalf("expected groups ordered by bytes, got %v", got)
+	}
+	if out.Groups[0].ArtifactBytes != 300 || out.Groups[1].ArtifactBytes != 35 || out.Groups[2].ArtifactBytes != 0 {
+		t.Fatalf("unexpected group totals %+v", out.Groups)
+	}
+	if got := out.Items[1].Fields["artifactBytes"]; got != int64(25) {
+		t.Fatalf("expected run #3 to report 25 artifact bytes, got %v", got)
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,17 +14,31 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/avivsinai/jenkins-cli/internal/filter"
-	"github.com/avivsinai/jenkins-cli/internal/jenkins"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
+// errNoRunsToInfer reports that run-based inference had nothing to scan.
+var errNoRunsToInfer = errors.New("job has no completed runs to infer from")
+
 const (
 	paramsSourceAuto   = "auto"
 	paramsSourceConfig = "config"
 	paramsSourceRuns   = "runs"
+
+	// inferredFromBranch prefixes the inferredFrom value of parameters taken
+	// from a sibling multibranch branch.
+	inferredFromBranch = "branch:"
+	// maxSiblingBranches bounds how many sibling branches are scanned when a
+	// fresh branch has no runs of its own.
+	maxSiblingBranches = 5
 )
 
+// preferredSiblingBranches are tried first when borrowing parameters from a
+// sibling branch, since they usually run most often.
+var preferredSiblingBranches = []string{"main", "master", "develop", "trunk"}
+
 func newRunParamsCmd(f *cmdutil.Factory) *cobra.Command {
 	var (
 		source    string
@@ -67,32 +82,33 @@ func newRunParamsCmd(f *cmdutil.Factory) *cobra.Command {
 				limitRuns = 50
 			}
 
-			jobPath := args[0]
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
+
 			var (
 				params     []runParameterInfo
 				usedSource string
+				notes      []string
 			)
 
 			switch src {
 			case paramsSourceConfig:
-				params, err = fetchParamsFromConfig(ctx, client, jobPath)
+				params, err = fetchParamsFromConfig(ctx, client, jobPath, redactor)
 				usedSource = paramsSourceConfig
 			case paramsSourceRuns:
-				params, err = fetchParamsFromRuns(ctx, client, jobPath, limitRuns)
+				params, err = fetchParamsFromRuns(ctx, client, jobPath, limitRuns, redactor)
 				usedSource = paramsSourceRuns
-			case paramsSourceAuto:
-				params, err = fetchParamsFromConfig(ctx, client, jobPath)
-				usedSource = paramsSourceConfig
-				if err != nil || len(params) == 0 {
-					paramsRuns, runsErr := fetchParamsFromRuns(ctx, client, jobPath, limitRuns)
-					if runsErr == nil {
-						params = paramsRuns
-						usedSource = paramsSourceRuns
-						err = nil
-					} else if err == nil {
-						err = runsErr
-					}
+				if errors.Is(err, errNoRunsToInfer) {
+					err = fmt.Errorf("%s: %w; try --source config", jobpath.Normalize(jobPath), err)
 				}
+			case paramsSourceAuto:
+				params, usedSource, notes, err = resolveAutoParams(ctx, client, jobPath, limitRuns, redactor)
 			}
 
 			if err != nil {
@@ -104,9 +120,10 @@ func newRunParamsCmd(f *cmdutil.Factory) *cobra.Command {
 			})
 
 			output := runParamsOutput{
-				JobPath:    normalizeJobPath(jobPath),
+				JobPath:    jobpath.Normalize(jobPath),
 				Source:     usedSource,
 				Parameters: params,
+				Notes:      notes,
 			}
 
 			return shared.PrintOutput(cmd, output, func() error {
@@ -114,45 +131,172 @@ func newRunParamsCmd(f *cmdutil.Factory) *cobra.Command {
 			})
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
 	cmd.Flags().StringVar(&source, "source", paramsSourceAuto, "Parameter source: auto, config, or runs")
 	cmd.Flags().IntVar(&limitRuns, "limit-runs", 50, "Number of recent runs to scan when inferring parameters")
+	cmdutil.SetFlagEnum(cmd, "source", paramsSourceAuto, paramsSourceConfig, paramsSourceRuns)
 
 	return cmd
 }
 
-func fetchParamsFromConfig(ctx context.Context, client *jenkins.Client, jobPath string) ([]runParameterInfo, error) {
-	path := fmt.Sprintf("/%s/config.xml", jenkins.EncodeJobPath(jobPath))
-	req := client.NewRequest().SetHeader("Accept", "application/xml")
+// resolveAutoParams implements --source auto: config.xml first, then run
+// inference. Multibranch branch jobs skip config.xml because their parameters
+// come from the Jenkinsfile and only appear once the branch has run.
+func resolveAutoParams(ctx context.Context, client shared.Doer, jobPath string, limitRuns int, redactor *filter.Redactor) ([]runParameterInfo, string, []string, error) {
+	if parent := lookupMultibranchParent(ctx, client, jobPath); parent != nil {
+		return resolveBranchParams(ctx, client, jobPath, parent, limitRuns, redactor)
+	}
+
+	var notes []string
+	params, err := fetchParamsFromConfig(ctx, client, jobPath, redactor)
+	usedSource := paramsSourceConfig
+	if err != nil || len(params) == 0 {
+		paramsRuns, runsErr := fetchParamsFromRuns(ctx, client, jobPath, limitRuns, redactor)
+		switch {
+		case runsErr == nil:
+			params = paramsRuns
+			usedSource = paramsSourceRuns
+			err = nil
+		case errors.Is(runsErr, errNoRunsToInfer):
+			// Keep the (possibly empty) config result and say why
+			// run inference was skipped.
+			notes = append(notes, runsErr.Error())
+		case err == nil:
+			err = runsErr
+		}
+	}
+	return params, usedSource, notes, err
+}
+
+// multibranchParent is the multibranch project that owns a branch job.
+type multibranchParent struct {
+	Path     string
+	Branches []string
+}
+
+// lookupMultibranchParent returns the owning multibranch project when jobPath
+// is one of its branches, and nil otherwise. Lookup failures are treated as
+// "not a branch" so regular jobs keep the config-first behaviour.
+func lookupMultibranchParent(ctx context.Context, client shared.Doer, jobPath string) *multibranchParent {
+	parentPath, _ := jobpath.Split(jobPath)
+	if parentPath == "" {
+		return nil
+	}
+
+	var payload struct {
+		Class string `json:"_class"`
+		Jobs  []struct {
+			Name  string `json:"name"`
+			Class string `json:"_class"`
+		} `json:"jobs"`
+	}
+	req := client.NewRequest().SetQueryParam("tree", "_class,jobs[name,_class]")
 	req.SetContext(ctx)
+	resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/api/json", jobpath.Encode(parentPath)), &payload)
+	if err != nil || resp.StatusCode() != http.StatusOK || !isMultibranchClass(payload.Class) {
+		return nil
+	}
 
-	resp, err := client.Do(req, http.MethodGet, path, nil)
-	if err != nil {
-		return nil, err
+	parent := &multibranchParent{Path: parentPath}
+	for _, job := range payload.Jobs {
+		if !shared.IsFolderClass(job.Class) {
+			parent.Branches = append(parent.Branches, job.Name)
+		}
 	}
-	if resp.StatusCode() == http.StatusNotFound {
-		return nil, nil
+	return parent
+}
+
+// resolveBranchParams infers a branch job's parameters from its own runs, or
+// from a sibling branch when the branch has not run yet.
+func resolveBranchParams(ctx context.Context, client shared.Doer, jobPath string, parent *multibranchParent, limitRuns int, redactor *filter.Redactor) ([]runParameterInfo, string, []string, error) {
+	params, err := fetchParamsFromRuns(ctx, client, jobPath, limitRuns, redactor)
+	switch {
+	case err == nil:
+		return params, paramsSourceRuns, nil, nil
+	case !errors.Is(err, errNoRunsToInfer):
+		return nil, "", nil, err
 	}
-	if resp.StatusCode() >= 400 {
-		return nil, fmt.Errorf("fetch job config failed: %s", resp.Status())
+
+	_, branch := jobpath.Split(jobPath)
+	for _, sibling := range siblingBranchOrder(parent.Branches, branch) {
+		siblingPath := jobpath.Join(parent.Path, sibling)
+		params, err := fetchParamsFromRuns(ctx, client, siblingPath, limitRuns, redactor)
+		if errors.Is(err, errNoRunsToInfer) {
+			continue
+		}
+		if err != nil {
+			return nil, "", nil, err
+		}
+		for i := range params {
+			params[i].InferredFrom = inferredFromBranch + siblingPath
+		}
+		note := fmt.Sprintf("branch %s has no runs yet; parameters were inferred from sibling branch %s", branch, sibling)
+		return params, paramsSourceRuns, []string{note}, nil
 	}
 
-	data := resp.Body()
+	note := fmt.Sprintf("branch %s has no runs yet and no sibling branch has runs to infer from; parameters appear after the first run", branch)
+	return nil, paramsSourceRuns, []string{note}, nil
+}
+
+// siblingBranchOrder lists the branches to borrow parameters from: the
+// conventional mainline names first, then the rest alphabetically.
+func siblingBranchOrder(branches []string, self string) []string {
+	rank := func(name string) int {
+		for i, preferred := range preferredSiblingBranches {
+			if name == preferred {
+				return i
+			}
+		}
+		return len(preferredSiblingBranches)
+	}
+
+	out := make([]string, 0, len(branches))
+	for _, name := range branches {
+		if name != self {
+			out = append(out, name)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ri, rj := rank(out[i]), rank(out[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return out[i] < out[j]
+	})
+	if len(out) > maxSiblingBranches {
+		out = out[:maxSiblingBranches]
+	}
+	return out
+}
+
+func fetchParamsFromConfig(ctx context.Context, client shared.Doer, jobPath string, redactor *filter.Redactor) ([]runParameterInfo, error) {
+	data, err := shared.FetchJobConfig(ctx, client, jobPath)
+	if err != nil {
+		if shared.ExitCode(err) == 3 {
+			return nil, nil
+		}
+		return nil, err
+	}
 	if len(data) == 0 {
 		return nil, nil
 	}
 
-	params, err := parseParametersFromConfig(data)
+	params, err := parseParametersFromConfig(data, redactor)
 	if err != nil {
 		return nil, err
 	}
+	for i := range params {
+		params[i].InferredFrom = paramsSourceConfig
+	}
 	return params, nil
 }
 
-func fetchParamsFromRuns(ctx context.Context, client *jenkins.Client, jobPath string, limit int) ([]runParameterInfo, error) {
+func fetchParamsFromRuns(ctx context.Context, client shared.Doer, jobPath string, limit int, redactor *filter.Redactor) ([]runParameterInfo, error) {
 	opts := runListOptions{
 		Limit:    limit,
 		WithMeta: true,
+		Redactor: redactor,
 	}
 
 	output, err := executeRunList(ctx, client, jobPath, opts)
@@ -160,6 +304,9 @@ func fetchParamsFromRuns(ctx context.Context, client *jenkins.Client, jobPath st
 		return nil, err
 	}
 
+	if len(output.Items) == 0 {
+		return nil, errNoRunsToInfer
+	}
 	if output.Metadata == nil {
 		return nil, nil
 	}
@@ -169,6 +316,7 @@ func fetchParamsFromRuns(ctx context.Context, client *jenkins.Client, jobPath st
 		if params[i].Frequency == 0 {
 			params[i].Frequency = 1
 		}
+		params[i].InferredFrom = paramsSourceRuns
 	}
 	return params, nil
 }
@@ -176,6 +324,9 @@ func fetchParamsFromRuns(ctx context.Context, client *jenkins.Client, jobPath st
 func renderRunParamsHuman(cmd *cobra.Command, output runParamsOutput) error {
 	w := cmd.OutOrStdout()
 
+	for _, note := range output.Notes {
+		_, _ = fmt.Fprintf(w, "Note: %s\n", note)
+	}
 	if len(output.Parameters) == 0 {
 		_, _ = fmt.Fprintf(w, "No parameters found for %s (source: %s)\n", output.JobPath, output.Source)
 		return nil
@@ -192,25 +343,28 @@ func renderRunParamsHuman(cmd *cobra.Command, output runParamsOutput) error {
 			freq = "required"
 		}
 		_, _ = fmt.Fprintf(w, "  %s (%s, %s)\n", param.Name, typeLabel, freq)
-		if param.Default != "" && !param.IsSecret {
+		if param.Default != "" {
 			_, _ = fmt.Fprintf(w, "    Default: %s\n", param.Default)
 		}
 		if param.IsSecret {
-			_, _ = fmt.Fprintln(w, "    Marked as secret (values not displayed)")
+			_, _ = fmt.Fprintln(w, "    Marked as secret (values redacted)")
 		}
-		if len(param.SampleValues) > 0 && !param.IsSecret {
+		if len(param.SampleValues) > 0 {
 			_, _ = fmt.Fprintf(w, "    Sample values: %s\n", strings.Join(param.SampleValues, ", "))
 		}
 		if param.Frequency > 0 && param.Frequency < 0.999 {
 			_, _ = fmt.Fprintf(w, "    Seen in %.0f%% of recent runs\n", param.Frequency*100)
 		}
+		if branch, ok := strings.CutPrefix(param.InferredFrom, inferredFromBranch); ok {
+			_, _ = fmt.Fprintf(w, "    Inferred from %s\n", branch)
+		}
 	}
 
 	return nil
 }
 
-func parseParametersFromConfig(data []byte) ([]runParameterInfo, error) {
-	decoder := xml.NewDecoder(bytes.NewReader(data))
+func parseParametersFromConfig(data []byte, redactor *filter.Redactor) ([]runParameterInfo, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(shared.StripXMLDeclaration(data)))
 
 	var (
 		stack          []xml.StartElement
@@ -263,17 +417,15 @@ func parseParametersFromConfig(data []byte) ([]runParameterInfo, error) {
 			switch element {
 			case "name":
 				current.Name = text
-				if filter.IsLikelySecret(text) {
+				if redactor.IsSecret(text) {
 					current.IsSecret = true
 				}
 			case "defaultValue":
-				if !current.IsSecret {
-					current.Default = text
-				}
+				current.Default = text
 			case "description":
 				// description omitted from output for now
 			case "string":
-				if inChoices && !current.IsSecret {
+				if inChoices {
 					current.SampleValues = appendSampleValue(current.SampleValues, text, 5)
 				}
 			}
@@ -285,8 +437,7 @@ func parseParametersFromConfig(data []byte) ([]runParameterInfo, error) {
 						current.Frequency = 1
 					}
 					if current.IsSecret {
-						current.Default = ""
-						current.SampleValues = nil
+						redactParameterInfo(current)
 					}
 					params = append(params, *current)
 				}
@@ -358,3 +509,14 @@ func appendSampleValue(values []string, value string, limit int) []string {
 	}
 	return append(values, value)
 }
+
+// redactParameterInfo replaces the default and sample values of a secret
+// parameter.
+func redactParameterInfo(param *runParameterInfo) {
+	if param.Default != "" {
+		param.Default = filter.RedactedValue
+	}
+	if len(param.SampleValues) > 0 {
+		param.SampleValues = []string{filter.RedactedValue}
+	}
+}
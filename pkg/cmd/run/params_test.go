@@ -1,6 +1,14 @@
 package run
 
-import "testing"
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
 
 func TestParseParametersFromConfig(t *testing.T) {
 	xml := `
@@ -28,7 +36,7 @@ func TestParseParametersFromConfig(t *testing.T) {
   </properties>
 </project>`
 
-	params, err := parseParametersFromConfig([]byte(xml))
+	params, err := parseParametersFromConfig([]byte(xml), nil)
 	if err != nil {
 		t.Fatalf("parseParametersFromConfig error: %v", err)
 	}
@@ -56,7 +64,7 @@ func TestParseParametersFromConfig(t *testing.T) {
 	if !secret.IsSecret {
 		t.Fatal("SECRET_KEY should be marked secret")
 	}
-	if secret.Default != "" {
+	if secret.Default != filter.RedactedValue {
 		t.Fatalf("expected secret default to be redacted, got %q", secret.Default)
 	}
 
@@ -69,6 +77,37 @@ func TestParseParametersFromConfig(t *testing.T) {
 	}
 }
 
+func TestParseParametersFromConfigAppliesRedactor(t *testing.T) {
+	xml := `<project><properties><hudson.model.ParametersDefinitionProperty><parameterDefinitions>
+  <hudson.model.StringParameterDefinition>
+    <name>PUBLIC_KEY_FINGERPRINT</name>
+    <defaultValue>SHA256:abc</defaultValue>
+  </hudson.model.StringParameterDefinition>
+  <hudson.model.StringParameterDefinition>
+    <name>DB_CREDS</name>
+    <defaultValue>admin:hunter2</defaultValue>
+  </hudson.model.StringParameterDefinition>
+</parameterDefinitions></hudson.model.ParametersDefinitionProperty></properties></project>`
+
+	redactor, err := filter.NewRedactor([]string{"creds"}, []string{"PUBLIC_KEY_FINGERPRINT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, err := parseParametersFromConfig([]byte(xml), redactor)
+	if err != nil {
+		t.Fatalf("parseParametersFromConfig error: %v", err)
+	}
+	if len(params) != 2 {
+		t.Fatalf("expected 2 parameters, got %d", len(params))
+	}
+	if fingerprint := params[1]; fingerprint.IsSecret || fingerprint.Default != "SHA256:abc" {
+		t.Fatalf("expected the allowlisted parameter in the clear, got %+v", fingerprint)
+	}
+	if creds := params[0]; !creds.IsSecret || creds.Default != filter.RedactedValue {
+		t.Fatalf("expected the pattern hit to be redacted, got %+v", creds)
+	}
+}
+
 func TestParameterTypeFromElement(t *testing.T) {
 	tests := map[string]struct {
 		expectedType   string
@@ -96,3 +135,95 @@ func TestAppendSampleValue(t *testing.T) {
 		t.Fatalf("expected capacity limit, got %d values", len(values))
 	}
 }
+
+func TestResolveAutoParamsBranchWithRuns(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleFixture(http.MethodGet, "/job/team/job/app/api/json", "params_multibranch.json")
+	server.HandleFixture(http.MethodGet, "/job/team/job/app/job/main/api/json", "params_branch_runs.json")
+
+	params, source, notes, err := resolveAutoParams(context.Background(), client, "team/app/main", 50, nil)
+	if err != nil {
+		t.Fatalf("resolveAutoParams: %v", err)
+	}
+	if source != paramsSourceRuns || len(notes) != 0 {
+		t.Fatalf("source = %q notes = %v, want runs without notes", source, notes)
+	}
+	if len(params) != 2 {
+		t.Fatalf("expected 2 parameters, got %+v", params)
+	}
+	for _, p := range params {
+		if p.InferredFrom != paramsSourceRuns {
+			t.Fatalf("%s inferredFrom = %q, want runs", p.Name, p.InferredFrom)
+		}
+	}
+	if reqs := server.RequestsTo(http.MethodGet, "/job/team/job/app/job/main/config.xml"); len(reqs) != 0 {
+		t.Fatalf("branch job config.xml should not be fetched, got %d requests", len(reqs))
+	}
+}
+
+func TestResolveAutoParamsFreshBranchUsesSibling(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleFixture(http.MethodGet, "/job/team/job/app/api/json", "params_multibranch.json")
+	server.HandleFixture(http.MethodGet, "/job/team/job/app/job/release/api/json", "params_branch_empty.json")
+	server.HandleFixture(http.MethodGet, "/job/team/job/app/job/main/api/json", "params_branch_runs.json")
+
+	params, source, notes, err := resolveAutoParams(context.Background(), client, "team/app/release", 50, nil)
+	if err != nil {
+		t.Fatalf("resolveAutoParams: %v", err)
+	}
+	if source != paramsSourceRuns {
+		t.Fatalf("source = %q, want runs", source)
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "sibling branch main") {
+		t.Fatalf("expected a note naming the sibling branch, got %v", notes)
+	}
+	if len(params) != 2 {
+		t.Fatalf("expected 2 parameters, got %+v", params)
+	}
+	for _, p := range params {
+		if p.InferredFrom != "branch:team/app/main" {
+			t.Fatalf("%s inferredFrom = %q, want branch:team/app/main", p.Name, p.InferredFrom)
+		}
+	}
+}
+
+func TestResolveAutoParamsFreshBranchWithoutSiblingRuns(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleFixture(http.MethodGet, "/job/team/job/app/api/json", "params_multibranch.json")
+	for _, branch := range []string{"main", "release", "feature%252Flogin"} {
+		server.HandleFixture(http.MethodGet, "/job/team/job/app/job/"+branch+"/api/json", "params_branch_empty.json")
+	}
+
+	params, _, notes, err := resolveAutoParams(context.Background(), client, "team/app/release", 50, nil)
+	if err != nil {
+		t.Fatalf("resolveAutoParams: %v", err)
+	}
+	if len(params) != 0 || len(notes) != 1 || !strings.Contains(notes[0], "no sibling branch has runs") {
+		t.Fatalf("expected no parameters and an explanatory note, got %+v %v", params, notes)
+	}
+}
+
+func TestResolveAutoParamsRegularJobUsesConfig(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/api/json", http.StatusOK, `{"_class":"com.cloudbees.hudson.plugins.folder.Folder","jobs":[{"name":"deploy","_class":"hudson.model.FreeStyleProject"}]}`)
+	server.HandleFixture(http.MethodGet, "/job/team/job/deploy/config.xml", "params_freestyle_config.xml")
+
+	params, source, notes, err := resolveAutoParams(context.Background(), client, "team/deploy", 50, nil)
+	if err != nil {
+		t.Fatalf("resolveAutoParams: %v", err)
+	}
+	if source != paramsSourceConfig || len(notes) != 0 {
+		t.Fatalf("source = %q notes = %v, want config without notes", source, notes)
+	}
+	if len(params) != 1 || params[0].Name != "TAG" || params[0].Default != "latest" || params[0].InferredFrom != paramsSourceConfig {
+		t.Fatalf("unexpected parameters %+v", params)
+	}
+}
+
+func TestSiblingBranchOrder(t *testing.T) {
+	got := siblingBranchOrder([]string{"zeta", "release", "master", "alpha", "main"}, "release")
+	want := []string{"main", "master", "alpha", "zeta"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("siblingBranchOrder = %v, want %v", got, want)
+	}
+}
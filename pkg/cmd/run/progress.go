@@ -0,0 +1,240 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// followOptions tunes how followTriggeredRun and monitorRun report progress.
+type followOptions struct {
+	Interval  time.Duration
+	ShowStage bool
+	// WaitThroughQuietDown keeps waiting for a queued run while Jenkins is
+	// quieting down instead of failing with exit code 14.
+	WaitThroughQuietDown bool
+	// Progress receives run.heartbeat events; nil disables them.
+	Progress cmdutil.ProgressReporter
+	// StageGate stops following early on named pipeline stages.
+	StageGate stageGate
+	// Retry decides how failed status polls are retried.
+	Retry shared.PollRetry
+	// OnBuild, if set, is called with the build number once the queued run
+	// starts.
+	OnBuild func(number int64)
+	// LogOffset is where the streamed log starts, in bytes.
+	LogOffset int64
+	// Annotation, if set, is appended to the build description once the
+	// build number is known; see annotateBuild.
+	Annotation string
+	// Download, if set, fetches matching artifacts once the run finishes.
+	Download *followDownload
+}
+
+// followProgress picks the heartbeat reporter for a followed run. When logs
+// stream to the terminal a spinner would fight them, so only --progress json
+// reports alongside streamed logs.
+func followProgress(cmd *cobra.Command, f *cmdutil.Factory) cmdutil.ProgressReporter {
+	streamLogs := !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd)
+	if streamLogs && f.ProgressMode != cmdutil.ProgressJSON {
+		return nil
+	}
+	return f.Progress()
+}
+
+type runProgress struct {
+	Elapsed   time.Duration
+	Estimated time.Duration
+	// Percent is -1 when Jenkins has no estimate (e.g. the first build of a job).
+	Percent int
+}
+
+type runStatusOutput struct {
+	JobPath             string `json:"jobPath"`
+	Number              int64  `json:"number"`
+	Status              string `json:"status"`
+	Result              string `json:"result,omitempty"`
+	StartTime           string `json:"startTime,omitempty"`
+	ElapsedMs           int64  `json:"elapsedMs"`
+	EstimatedDurationMs int64  `json:"estimatedDurationMs,omitempty"`
+	Percent             *int   `json:"percent,omitempty"`
+	Stage               string `json:"stage,omitempty"`
+}
+
+type wfapiDescribe struct {
+	Stages []wfapiStage `json:"stages"`
+}
+
+type wfapiStage struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	DurationMillis int64  `json:"durationMillis"`
+}
+
+func newRunStatusCmd(f *cmdutil.Factory) *cobra.Command {
+	var showStage bool
+
+	cmd := &cobra.Command{
+		Use:   "status <jobPath> <buildNumber>",
+		Short: "Show run progress against its estimated duration",
+		Example: `  jk run status team/app 42
+  jk run status team/app 42 --show-stage --json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			num, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid build number: %w", err)
+			}
+
+			detail, err := fetchRunDetail(client, jobPath, num)
+			if err != nil {
+				return err
+			}
+
+			progress := computeRunProgress(*detail, time.Now())
+			output := runStatusOutput{
+				JobPath:             jobpath.Normalize(jobPath),
+				Number:              num,
+				Status:              statusFromFlags(detail.Building),
+				Result:              resultForList(detail.Result, detail.Building),
+				StartTime:           shared.FormatTimestamp(detail.Timestamp),
+				ElapsedMs:           progress.Elapsed.Milliseconds(),
+				EstimatedDurationMs: progress.Estimated.Milliseconds(),
+			}
+			if progress.Percent >= 0 {
+				percent := progress.Percent
+				output.Percent = &percent
+			}
+			if showStage {
+				stage, err := fetchCurrentStage(cmd.Context(), client, jobPath, num)
+				if err != nil {
+					return err
+				}
+				output.Stage = stage
+			}
+
+			return shared.PrintOutput(cmd, output, func() error {
+				w := cmd.OutOrStdout()
+				_, _ = fmt.Fprintf(w, "Run #%d (%s)\n", output.Number, output.Status)
+				if output.Result != "" {
+					_, _ = fmt.Fprintf(w, "Result: %s\n", output.Result)
+				}
+				_, _ = fmt.Fprintf(w, "Elapsed: %s\n", progressDuration(progress.Elapsed))
+				if progress.Estimated > 0 {
+					_, _ = fmt.Fprintf(w, "Estimate: ~%s\n", progressDuration(progress.Estimated))
+				}
+				if output.Percent != nil {
+					_, _ = fmt.Fprintf(w, "Progress: %d%%\n", *output.Percent)
+				}
+				if output.Stage != "" {
+					_, _ = fmt.Fprintf(w, "Stage: %s\n", output.Stage)
+				}
+				return nil
+			})
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().BoolVar(&showStage, "show-stage", false, "Include the current pipeline stage (extra wfapi request)")
+	return cmd
+}
+
+// computeRunProgress derives elapsed time and percent complete from the run's
+// start timestamp and Jenkins' estimatedDuration.
+func computeRunProgress(detail runDetail, now time.Time) runProgress {
+	progress := runProgress{Percent: -1}
+
+	switch {
+	case !detail.Building && detail.Duration > 0:
+		progress.Elapsed = time.Duration(detail.Duration) * time.Millisecond
+	case detail.Timestamp > 0:
+		if elapsed := now.Sub(time.UnixMilli(detail.Timestamp)); elapsed > 0 {
+			progress.Elapsed = elapsed
+		}
+	}
+
+	if detail.EstimatedDuration <= 0 {
+		return progress
+	}
+	progress.Estimated = time.Duration(detail.EstimatedDuration) * time.Millisecond
+
+	if !detail.Building {
+		progress.Percent = 100
+		return progress
+	}
+
+	percent := int(progress.Elapsed * 100 / progress.Estimated)
+	// Overrunning builds stay below 100% until Jenkins reports completion.
+	if percent > 99 {
+		percent = 99
+	}
+	progress.Percent = percent
+	return progress
+}
+
+func formatProgressLine(p runProgress, stage string) string {
+	var builder strings.Builder
+	builder.WriteString("elapsed ")
+	builder.WriteString(progressDuration(p.Elapsed))
+	if p.Estimated > 0 {
+		builder.WriteString(" / ~")
+		builder.WriteString(progressDuration(p.Estimated))
+		builder.WriteString(" estimated")
+	}
+	if p.Percent >= 0 {
+		fmt.Fprintf(&builder, " (%d%%)", p.Percent)
+	}
+	if stage != "" {
+		builder.WriteString(" [stage: ")
+		builder.WriteString(stage)
+		builder.WriteString("]")
+	}
+	return builder.String()
+}
+
+// progressDuration renders d to the second, so heartbeats under a minute do
+// not flicker through tenths.
+func progressDuration(d time.Duration) string {
+	return shared.DurationString(d.Round(time.Second).Milliseconds())
+}
+
+// fetchCurrentStage returns the in-progress pipeline stage reported by the
+// Pipeline Stage View API, or the most recent stage when none is running.
+func fetchCurrentStage(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64) (string, error) {
+	stages, err := fetchStages(ctx, client, jobPath, buildNumber)
+	if err != nil {
+		return "", err
+	}
+	return currentStageName(stages), nil
+}
+
+func currentStageName(stages []wfapiStage) string {
+	for i := len(stages) - 1; i >= 0; i-- {
+		if strings.EqualFold(stages[i].Status, "IN_PROGRESS") {
+			return stages[i].Name
+		}
+	}
+	if len(stages) > 0 {
+		return stages[len(stages)-1].Name
+	}
+	return ""
+}
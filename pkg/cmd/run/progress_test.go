@@ -0,0 +1,95 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestComputeRunProgress(t *testing.T) {
+	now := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+	started := now.Add(-4 * time.Minute).UnixMilli()
+
+	progress := computeRunProgress(runDetail{Building: true, Timestamp: started, EstimatedDuration: (12 * time.Minute).Milliseconds()}, now)
+	if progress.Elapsed != 4*time.Minute {
+		t.Fatalf("expected 4m elapsed, got %s", progress.Elapsed)
+	}
+	if progress.Percent != 33 {
+		t.Fatalf("expected 33%%, got %d", progress.Percent)
+	}
+	if got := formatProgressLine(progress, "Build"); got != "elapsed 4m 0s / ~12m 0s estimated (33%) [stage: Build]" {
+		t.Fatalf("unexpected progress line %q", got)
+	}
+}
+
+func TestComputeRunProgressWithoutEstimate(t *testing.T) {
+	now := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+	progress := computeRunProgress(runDetail{Building: true, Timestamp: now.Add(-90 * time.Second).UnixMilli()}, now)
+	if progress.Percent != -1 {
+		t.Fatalf("expected percent to be omitted, got %d", progress.Percent)
+	}
+	if got := formatProgressLine(progress, ""); got != "elapsed 1m 30s" {
+		t.Fatalf("unexpected progress line %q", got)
+	}
+}
+
+func TestComputeRunProgressOverrunAndCompleted(t *testing.T) {
+	now := time.Date(2025, 10, 20, 12, 0, 0, 0, time.UTC)
+	overrun := computeRunProgress(runDetail{Building: true, Timestamp: now.Add(-20 * time.Minute).UnixMilli(), EstimatedDuration: (10 * time.Minute).Milliseconds()}, now)
+	if overrun.Percent != 99 {
+		t.Fatalf("expected overrunning build to cap at 99%%, got %d", overrun.Percent)
+	}
+
+	done := computeRunProgress(runDetail{Timestamp: now.Add(-time.Hour).UnixMilli(), Duration: 60000, EstimatedDuration: 120000}, now)
+	if done.Elapsed != time.Minute || done.Percent != 100 {
+		t.Fatalf("expected completed run to use duration and 100%%, got %s/%d", done.Elapsed, done.Percent)
+	}
+}
+
+func TestCurrentStageName(t *testing.T) {
+	stages := []wfapiStage{
+		{Name: "Checkout", Status: "SUCCESS"},
+		{Name: "Build", Status: "IN_PROGRESS"},
+		{Name: "Deploy", Status: "NOT_EXECUTED"},
+	}
+	if got := currentStageName(stages); got != "Build" {
+		t.Fatalf("expected in-progress stage, got %q", got)
+	}
+	if got := currentStageName(stages[:1]); got != "Checkout" {
+		t.Fatalf("expected last stage fallback, got %q", got)
+	}
+	if got := currentStageName(nil); got != "" {
+		t.Fatalf("expected empty stage, got %q", got)
+	}
+}
+
+func TestRunStatusShowStage(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/42/api/json", http.StatusOK, fmt.Sprintf(
+		`{"number":42,"building":true,"timestamp":%d,"estimatedDuration":600000}`, time.Now().Add(-time.Minute).UnixMilli()))
+	server.Handle(http.MethodGet, "/job/app/42/wfapi/describe", http.StatusOK,
+		`{"stages":[{"name":"Checkout","status":"SUCCESS"},{"name":"Build","status":"IN_PROGRESS"}]}`)
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"status", "app", "42", "--show-stage", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run status: %v", err)
+	}
+
+	var output runStatusOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode: %v\n%s", err, stdout.String())
+	}
+	if output.Stage != "Build" {
+		t.Fatalf("stage = %q, want Build", output.Stage)
+	}
+}
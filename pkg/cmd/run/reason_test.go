@@ -0,0 +1,136 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/build"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+func TestResolveTriggerReason(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+
+	if got := resolveTriggerReason(cmd, nil, "  "); got != "" {
+		t.Fatalf("blank reason = %q, want empty", got)
+	}
+
+	auto := resolveTriggerReason(cmd, nil, "auto")
+	if !strings.HasPrefix(auto, "jk "+build.Version+" by unknown on ") {
+		t.Fatalf("unexpected auto reason %q", auto)
+	}
+	if stderr.Len() != 0 {
+		t.Fatalf("unexpected warning: %q", stderr.String())
+	}
+
+	long := resolveTriggerReason(cmd, nil, strings.Repeat("é", maxTriggerReasonRunes+10))
+	if utf8.RuneCountInString(long) != maxTriggerReasonRunes || !utf8.ValidString(long) {
+		t.Fatalf("truncated reason has %d runes", utf8.RuneCountInString(long))
+	}
+	if !strings.Contains(stderr.String(), "--reason truncated") {
+		t.Fatalf("expected truncation warning, got %q", stderr.String())
+	}
+}
+
+// causeRecordingJenkins models how Jenkins treats the cause parameter: it
+// becomes the note of a RemoteCause on token triggers, and any other
+// trigger records the calling user and drops it.
+type causeRecordingJenkins struct {
+	mu          sync.Mutex
+	cause       string
+	description string
+}
+
+func (j *causeRecordingJenkins) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	const job = "/job/releases/job/deploy"
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == job+"/build":
+		j.cause = "Started by user tester"
+		if r.URL.Query().Get("token") == "t0k" {
+			j.cause = "Started by remote host 127.0.0.1"
+			if note := r.URL.Query().Get("cause"); note != "" {
+				j.cause += " with note: " + note
+			}
+		}
+		w.Header().Set("Location", "/queue/item/1/")
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPost && r.URL.Path == job+"/1/submitDescription":
+		_ = r.ParseForm()
+		j.description = r.PostForm.Get("description")
+	case r.URL.Path == "/queue/item/1/api/json":
+		_, _ = fmt.Fprint(w, `{"id":1,"executable":{"number":1}}`)
+	case r.URL.Path == job+"/1/api/json":
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"number":      1,
+			"result":      "SUCCESS",
+			"description": j.description,
+			"actions":     []map[string]any{{"causes": []map[string]any{{"shortDescription": j.cause}}}},
+		})
+	case r.URL.Path == job+"/api/json":
+		_, _ = fmt.Fprint(w, `{"buildable":true}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func recordedCauses(t *testing.T, client shared.Doer) []string {
+	t.Helper()
+	detail, err := fetchRunDetail(client, "releases/deploy", 1)
+	if err != nil {
+		t.Fatalf("fetch run: %v", err)
+	}
+	var causes []string
+	for _, cause := range extractCauses(detail.Actions) {
+		causes = append(causes, cause.Description)
+	}
+	return causes
+}
+
+func TestTriggerReasonIsStoredOnlyWithToken(t *testing.T) {
+	client := jenkinstest.NewClient(t, &causeRecordingJenkins{})
+
+	if _, err := triggerBuild(client, "releases/deploy", nil, triggerCause{Reason: "nightly audit", Token: "t0k"}); err != nil {
+		t.Fatalf("triggerBuild: %v", err)
+	}
+	if got := recordedCauses(t, client); len(got) != 1 || got[0] != "Started by remote host 127.0.0.1 with note: nightly audit" {
+		t.Fatalf("causes = %q, want the reason as the remote cause note", got)
+	}
+}
+
+func TestRunStartRecordsReasonInDescriptionWithoutToken(t *testing.T) {
+	useFastQueuePolling(t)
+	jenkins := &causeRecordingJenkins{}
+	client := jenkinstest.NewClient(t, jenkins)
+
+	if _, err := executeRunStart(t, client, "--reason", "nightly audit"); exitCode(err) != 2 {
+		t.Fatalf("expected exit 2 for a reason that would be dropped, got %v", err)
+	}
+
+	if _, err := executeRunStart(t, client, "--reason", "nightly audit", "--follow", "--json"); err != nil {
+		t.Fatalf("run start: %v", err)
+	}
+	if got := recordedCauses(t, client); len(got) != 1 || strings.Contains(got[0], "nightly audit") {
+		t.Fatalf("causes = %q, want the user cause only", got)
+	}
+	detail, err := fetchRunDetail(client, "releases/deploy", 1)
+	if err != nil {
+		t.Fatalf("fetch run: %v", err)
+	}
+	if detail.Description != "Reason: nightly audit" {
+		t.Fatalf("description = %q, want the reason", detail.Description)
+	}
+}
@@ -0,0 +1,334 @@
+package run
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// reportLogLines is how much of the build log a failed test case carries.
+const reportLogLines = 50
+
+const reportRunTree = "number,result,building,timestamp,duration,url"
+
+type reportRun struct {
+	Number    int64  `json:"number"`
+	Result    string `json:"result"`
+	Building  bool   `json:"building"`
+	Timestamp int64  `json:"timestamp"`
+	Duration  int64  `json:"duration"`
+	URL       string `json:"url"`
+}
+
+type junitTestSuite struct {
+	XMLName    xml.Name        `xml:"testsuite"`
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",cdata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+func newRunReportCmd(f *cmdutil.Factory) *cobra.Command {
+	var junit bool
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "report <jobPath> <buildNumber>",
+		Short: "Render a run's outcome as a test report",
+		Long: `Render a run's outcome in a format CI systems ingest as test results.
+
+--junit writes a JUnit XML document with one testsuite named after the job and
+one testcase per pipeline stage, taken from the Pipeline Stage View API. Runs
+without stage data become a single testcase. Failed, unstable, and aborted
+stages are reported as failures carrying the last 50 lines of the build log;
+stages that did not execute are skipped.`,
+		Example: `  # Hand a run to a JUnit consumer
+  jk run report team/app 42 --junit -o report.xml`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !junit {
+				return shared.NewExitError(2, "choose a report format: --junit")
+			}
+			num, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid build number: %w", err)
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			suite, err := buildJUnitReport(cmd.Context(), client, jobPath, num)
+			if err != nil {
+				return err
+			}
+
+			if output == "" || output == "-" {
+				return writeJUnit(cmd.OutOrStdout(), suite)
+			}
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("create report: %w", err)
+			}
+			if err := writeJUnit(file, suite); err != nil {
+				_ = file.Close()
+				return err
+			}
+			return file.Close()
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().BoolVar(&junit, "junit", false, "Write a JUnit XML report")
+	cmd.Flags().StringVarP(&output, "output", "o", "-", "File to write the report to (- for stdout)")
+	return cmd
+}
+
+func buildJUnitReport(ctx context.Context, client shared.Doer, jobPath string, num int64) (junitTestSuite, error) {
+	run, err := fetchReportRun(ctx, client, jobPath, num)
+	if err != nil {
+		return junitTestSuite{}, err
+	}
+	stages, err := fetchStages(ctx, client, jobPath, num)
+	if err != nil {
+		return junitTestSuite{}, err
+	}
+
+	jobName := jobpath.Normalize(jobPath)
+	result := resultForList(run.Result, run.Building)
+	suite := junitTestSuite{
+		Name: jobName,
+		Time: junitSeconds(run.Duration),
+		Properties: []junitProperty{
+			{Name: "build", Value: strconv.FormatInt(run.Number, 10)},
+			{Name: "result", Value: result},
+			{Name: "url", Value: run.URL},
+		},
+	}
+	if run.Timestamp > 0 {
+		suite.Timestamp = time.UnixMilli(run.Timestamp).UTC().Format("2006-01-02T15:04:05")
+	}
+
+	if len(stages) == 0 {
+		status := result
+		if run.Building {
+			status = "IN_PROGRESS"
+		}
+		suite.TestCases = []junitTestCase{{
+			Name:      fmt.Sprintf("#%d", run.Number),
+			ClassName: jobName,
+			Time:      junitSeconds(run.Duration),
+		}}
+		applyJUnitStatus(&suite.TestCases[0], status, fmt.Sprintf("run #%d %s", run.Number, status))
+	} else {
+		for _, stage := range stages {
+			tc := junitTestCase{
+				Name:      stage.Name,
+				ClassName: jobName,
+				Time:      junitSeconds(stage.DurationMillis),
+			}
+			status := strings.ToUpper(stage.Status)
+			applyJUnitStatus(&tc, status, fmt.Sprintf("stage %s %s", stage.Name, status))
+			suite.TestCases = append(suite.TestCases, tc)
+		}
+	}
+
+	var excerpt *string
+	for i := range suite.TestCases {
+		tc := &suite.TestCases[i]
+		suite.Tests++
+		switch {
+		case tc.Failure != nil:
+			suite.Failures++
+			if excerpt == nil {
+				lines, err := fetchLogTail(ctx, client, jobPath, num, reportLogLines)
+				if err != nil {
+					return junitTestSuite{}, err
+				}
+				text := xmlSafe(strings.Join(lines, "\n"))
+				excerpt = &text
+			}
+			tc.Failure.Text = *excerpt
+		case tc.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	return suite, nil
+}
+
+// applyJUnitStatus maps a stage status (or run result when there are no
+// stages) onto the test case.
+func applyJUnitStatus(tc *junitTestCase, status, message string) {
+	switch status {
+	case "FAILED", "FAILURE", "UNSTABLE", "ABORTED":
+		tc.Failure = &junitFailure{Message: message, Type: status}
+	case "NOT_EXECUTED", "NOT_BUILT", "SKIPPED":
+		tc.Skipped = &junitSkipped{Message: message}
+	case "IN_PROGRESS", "PAUSED_PENDING_INPUT", "QUEUED":
+		tc.Skipped = &junitSkipped{Message: message + " (still running)"}
+	}
+}
+
+// xmlSafe drops characters XML 1.0 cannot carry, such as the escape bytes of
+// ANSI colour codes; CDATA sections are not escaped by encoding/xml.
+func xmlSafe(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+			return r
+		case r < 0x20, r == 0xFFFE, r == 0xFFFF, r == utf8.RuneError:
+			return -1
+		}
+		return r
+	}, text)
+}
+
+func junitSeconds(ms int64) string {
+	return strconv.FormatFloat(float64(ms)/1000, 'f', 3, 64)
+}
+
+func writeJUnit(w io.Writer, suite junitTestSuite) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func fetchReportRun(ctx context.Context, client shared.Doer, jobPath string, num int64) (reportRun, error) {
+	req := client.NewRequest().SetQueryParam("tree", reportRunTree)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+	var run reportRun
+	resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/%d/api/json", jobpath.Encode(jobPath), num), &run)
+	if err != nil {
+		return reportRun{}, err
+	}
+	if err := shared.CheckResponse(resp, fmt.Sprintf("run %s #%d", jobPath, num)); err != nil {
+		return reportRun{}, err
+	}
+	return run, nil
+}
+
+// fetchStages returns the run's pipeline stages, or nil when the Pipeline
+// Stage View API is unavailable or the job is not a pipeline.
+func fetchStages(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64) ([]wfapiStage, error) {
+	stages, _, err := fetchStageView(ctx, client, jobPath, buildNumber)
+	return stages, err
+}
+
+// fetchStageView is fetchStages that also reports whether the Pipeline Stage
+// View API answered, telling a run without stages yet from a controller (or
+// a freestyle job) without the API.
+func fetchStageView(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64) ([]wfapiStage, bool, error) {
+	path := fmt.Sprintf("/%s/%d/wfapi/describe", jobpath.Encode(jobPath), buildNumber)
+	req := client.NewRequest()
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+
+	var describe wfapiDescribe
+	resp, err := client.Do(req, http.MethodGet, path, &describe)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode() >= 400 {
+		return nil, false, fmt.Errorf("fetch stages: %s", shared.ResponseStatus(resp))
+	}
+	return describe.Stages, true, nil
+}
+
+// fetchLogTail returns the last n lines of the build log.
+func fetchLogTail(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64, n int) ([]string, error) {
+	path := fmt.Sprintf("/%s/%d/consoleText", jobpath.Encode(jobPath), buildNumber)
+	req := client.NewStreamingRequest().
+		SetHeader("Accept", "text/plain").
+		SetDoNotParseResponse(true)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+
+	resp, err := client.Do(req, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.RawBody()
+	if body == nil {
+		return nil, nil
+	}
+	defer func() { _ = body.Close() }()
+	if resp.StatusCode() >= 400 {
+		return nil, nil
+	}
+
+	tail := make([]string, 0, n)
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(tail) == n {
+			tail = append(tail[:0], tail[1:]...)
+		}
+		tail = append(tail, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read console: %w", err)
+	}
+	return tail, nil
+}
@@ -0,0 +1,95 @@
+package run
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+const reportConsole = "Started by user alice\n[Pipeline] stage\n" +
+	"+ go test ./...\n\x1b[31m--- FAIL: TestParse (0.00s)\x1b[0m\n    parse_test.go:12: got <nil> & want \"error\"\n" +
+	"FAIL\nFinished: FAILURE\n"
+
+// runJUnitReport reports on run #7 of app with the given result; stages is
+// the wfapi/describe body, or "" for a job without stage data.
+func runJUnitReport(t *testing.T, result, stages string) (*fakejenkins.Server, []byte) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/7/api/json", http.StatusOK, fmt.Sprintf(
+		`{"number":7,"result":%q,"building":false,"timestamp":1700000000000,"duration":95500,"url":"https://ci/job/app/7/"}`, result))
+	if stages != "" {
+		server.Handle(http.MethodGet, "/job/app/7/wfapi/describe", http.StatusOK, stages)
+	}
+	server.Handle(http.MethodGet, "/job/app/7/consoleText", http.StatusOK, reportConsole)
+
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := newRunReportCmd(f)
+	cmd.SetArgs([]string{"app", "7", "--junit"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run report: %v", err)
+	}
+	return server, stdout.Bytes()
+}
+
+func TestRunReportJUnitPassingRun(t *testing.T) {
+	server, out := runJUnitReport(t, "SUCCESS", `{"stages":[
+		{"id":"6","name":"Build","status":"SUCCESS","durationMillis":30250},
+		{"id":"12","name":"Test","status":"SUCCESS","durationMillis":65000}
+	]}`)
+	fakejenkins.AssertFixture(t, "report_junit_passing.xml", out)
+	if got := len(server.RequestsTo(http.MethodGet, "/job/app/7/consoleText")); got != 0 {
+		t.Fatalf("passing run fetched the log %d times", got)
+	}
+}
+
+func TestRunReportJUnitFailedStage(t *testing.T) {
+	server, out := runJUnitReport(t, "FAILURE", `{"stages":[
+		{"id":"6","name":"Build","status":"SUCCESS","durationMillis":30250},
+		{"id":"12","name":"Test","status":"FAILED","durationMillis":65000},
+		{"id":"20","name":"Deploy","status":"NOT_EXECUTED","durationMillis":0}
+	]}`)
+	fakejenkins.AssertFixture(t, "report_junit_failed_stage.xml", out)
+	if got := len(server.RequestsTo(http.MethodGet, "/job/app/7/consoleText")); got != 1 {
+		t.Fatalf("expected one log fetch, got %d", got)
+	}
+}
+
+func TestRunReportJUnitWithoutStages(t *testing.T) {
+	_, out := runJUnitReport(t, "UNSTABLE", "")
+	fakejenkins.AssertFixture(t, "report_junit_no_stages.xml", out)
+}
+
+func TestRunReportWritesFile(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/7/api/json", http.StatusOK, `{"number":7,"result":"SUCCESS","duration":1000}`)
+
+	dest := filepath.Join(t.TempDir(), "report.xml")
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := newRunReportCmd(f)
+	cmd.SetArgs([]string{"app", "7", "--junit", "-o", dest})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run report: %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("expected nothing on stdout, got %q", stdout.String())
+	}
+	data, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("read report: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("report file is empty")
+	}
+}
@@ -0,0 +1,117 @@
+package run
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestRunListRequestTree(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/my%20app/api/json", http.StatusOK, `{"builds":[]}`)
+
+	filters, err := filter.Parse([]string{"param.ENV=prod"})
+	if err != nil {
+		t.Fatalf("parse filters: %v", err)
+	}
+	if _, err := executeRunList(context.Background(), client, "team/my app", runListOptions{Limit: 5, Filters: filters}); err != nil {
+		t.Fatalf("executeRunList: %v", err)
+	}
+
+	req := server.LastRequest(http.MethodGet, "/job/team/job/my%20app/api/json")
+	fakejenkins.AssertFixture(t, "run_ls_tree.txt", []byte(req.Query.Get("tree")+"\n"))
+}
+
+func TestTriggerBuildSendsFormAndCrumb(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.EnableCrumb("Jenkins-Crumb", "c0ffee")
+	server.HandleHeaders(http.MethodPost, "/job/deploy/buildWithParameters", http.StatusCreated, http.Header{
+		"Location": []string{"/queue/item/42/"},
+	})
+
+	resp, err := triggerBuild(client, "deploy", map[string]string{"ENV": "prod", "TAG": "v1.2 rc"}, triggerCause{})
+	if err != nil {
+		t.Fatalf("triggerBuild: %v", err)
+	}
+	if got := queueLocationFromResponse(resp); got == "" {
+		t.Fatalf("expected queue location from response")
+	}
+
+	req := server.LastRequest(http.MethodPost, "/job/deploy/buildWithParameters")
+	if got := req.Header.Get("Jenkins-Crumb"); got != "c0ffee" {
+		t.Fatalf("crumb header = %q, want c0ffee", got)
+	}
+	form := req.Form()
+	if form.Get("ENV") != "prod" || form.Get("TAG") != "v1.2 rc" {
+		t.Fatalf("unexpected form data: %v", form)
+	}
+	if req.Query.Has("cause") {
+		t.Fatalf("cause sent without --reason: %v", req.Query)
+	}
+}
+
+func TestTriggerBuildSendsCause(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/job/lint/build", http.StatusCreated, "")
+
+	if _, err := triggerBuild(client, "lint", nil, triggerCause{Reason: "nightly & audit #7", Token: "t0k"}); err != nil {
+		t.Fatalf("triggerBuild: %v", err)
+	}
+	req := server.LastRequest(http.MethodPost, "/job/lint/build")
+	if got := req.Query.Get("cause"); got != "nightly & audit #7" {
+		t.Fatalf("cause = %q", got)
+	}
+	if got := req.Query.Get("token"); got != "t0k" {
+		t.Fatalf("token = %q", got)
+	}
+
+	// Jenkins ignores the cause on a trigger without the job token.
+	if _, err := triggerBuild(client, "lint", nil, triggerCause{Reason: "nightly"}); err != nil {
+		t.Fatalf("triggerBuild: %v", err)
+	}
+	if req := server.LastRequest(http.MethodPost, "/job/lint/build"); req.Query.Has("cause") {
+		t.Fatalf("cause sent without a token: %v", req.Query)
+	}
+}
+
+func TestTriggerBuildWithoutParamsUsesBuildEndpoint(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodPost, "/job/lint/build", http.StatusCreated, "")
+
+	if _, err := triggerBuild(client, "lint", nil, triggerCause{}); err != nil {
+		t.Fatalf("triggerBuild: %v", err)
+	}
+	req := server.LastRequest(http.MethodPost, "/job/lint/build")
+	if len(req.Body) != 0 {
+		t.Fatalf("expected empty body, got %q", req.Body)
+	}
+}
+
+type recordingProgress struct {
+	events []cmdutil.ProgressEvent
+}
+
+func (r *recordingProgress) Report(ev cmdutil.ProgressEvent) { r.events = append(r.events, ev) }
+func (r *recordingProgress) Done()                           {}
+
+func TestRunSearchReportsJobProgress(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/a/api/json", http.StatusOK, `{"builds":[]}`)
+	server.Handle(http.MethodGet, "/job/b/api/json", http.StatusOK, `{"builds":[]}`)
+
+	progress := &recordingProgress{}
+	if _, err := executeRunSearch(context.Background(), client, []string{"a", "b"}, runSearchOptions{Limit: 5, MaxScan: 5, Progress: progress}); err != nil {
+		t.Fatalf("executeRunSearch: %v", err)
+	}
+	if len(progress.events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", progress.events)
+	}
+	last := progress.events[1]
+	if last.Event != cmdutil.EventSearchJob || last.JobPath != "b" || last.Index != 2 || last.Total != 2 {
+		t.Fatalf("unexpected event %+v", last)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"sort"
 	"strconv"
@@ -16,9 +17,13 @@ import (
 	"github.com/avivsinai/jenkins-cli/internal/filter"
 	"github.com/avivsinai/jenkins-cli/internal/fuzzy"
 	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/lastrun"
 	jklog "github.com/avivsinai/jenkins-cli/internal/log"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/artifact"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 type runListResponse struct {
@@ -65,6 +70,15 @@ type artifactItem struct {
 	Size         int64  `json:"size"`
 }
 
+// sumArtifactBytes totals the sizes Jenkins reported for artifacts.
+func sumArtifactBytes(artifacts []artifactItem) int64 {
+	var total int64
+	for _, artifact := range artifacts {
+		total += artifact.Size
+	}
+	return total
+}
+
 type changeSet struct {
 	Items []changeSetItem `json:"items"`
 }
@@ -97,15 +111,33 @@ type queueExecutable struct {
 }
 
 type runListOptions struct {
-	Limit        int
-	Cursor       string
-	Filters      []filter.Filter
-	Since        *time.Time
+	Limit      int
+	Cursor     string
+	Filters    []filter.Filter
+	RawFilters []string
+	Since      *time.Time
+	Until      *time.Time
+	SinceArg   string
+	UntilArg   string
+	// SinceBuild stops the scan at the first build numbered at or below it;
+	// zero means no bound.
+	SinceBuild   int64
 	SelectFields []string
 	GroupBy      string
-	Aggregation  string
-	WithMeta     bool
-	AllowRegex   bool
+	// GroupLimit caps the groups emitted, after sorting; zero means all.
+	GroupLimit  int
+	Aggregation string
+	WithMeta    bool
+	AllowRegex  bool
+	// IgnoreCursorScope resumes a cursor even when it was issued for a
+	// different filter set.
+	IgnoreCursorScope bool
+	// Redactor marks secret parameters in the metadata; nil applies the
+	// built-in keywords.
+	Redactor *filter.Redactor
+	// Quiet skips listing a folder's children when jobPath turns out to
+	// name one.
+	Quiet bool
 }
 
 type runInspection struct {
@@ -113,7 +145,9 @@ type runInspection struct {
 	Context    filter.Context
 	Parameters map[string]string
 	Causes     []runCauseInfo
-	Artifacts  []artifactItem
+	// Interruption is set for ABORTED runs when the listing fetched it.
+	Interruption *runInterruption
+	Artifacts    []artifactItem
 }
 
 type runCauseInfo struct {
@@ -129,36 +163,58 @@ type runGroupAccumulator struct {
 	First          *runInspection
 	LastTimestamp  int64
 	FirstTimestamp int64
+	// ArtifactBytes sums the artifact sizes of every run in the group.
+	ArtifactBytes int64
 }
 
 const runListHeadroom = 50
 
+const (
+	// defaultRunListLimit is the `run ls` page size without --limit when
+	// stdout is not a terminal.
+	defaultRunListLimit = 20
+	// runListReservedLines leaves room for the cursor line, the limit note,
+	// and the shell prompt when the page size follows the terminal height.
+	runListReservedLines = 3
+)
+
 type selectionRequirement struct {
 	requiresParameters bool
 	requiresArtifacts  bool
 	requiresCauses     bool
+	// requiresInterruption fetches the InterruptedBuildAction causes.
+	requiresInterruption bool
+	// description is shown by --list-fields.
+	description string
 }
 
 var selectFieldRegistry = map[string]selectionRequirement{
-	"number":              {},
-	"status":              {},
-	"result":              {},
-	"starttime":           {},
-	"durationms":          {},
-	"branch":              {},
-	"commit":              {},
-	"url":                 {},
-	"queueid":             {},
-	"parameters":          {requiresParameters: true},
-	"artifacts":           {requiresArtifacts: true},
-	"causes":              {requiresCauses: true},
-	"estimateddurationms": {},
+	"number":              {description: "Build number"},
+	"status":              {description: "running or completed"},
+	"result":              {description: "Build result (SUCCESS, FAILURE, UNSTABLE, ABORTED)"},
+	"starttime":           {description: "Start time (RFC3339)"},
+	"durationms":          {description: "Duration in milliseconds"},
+	"branch":              {description: "SCM branch that was built"},
+	"commit":              {description: "SCM commit that was built"},
+	"url":                 {description: "Build URL"},
+	"queueid":             {description: "Queue item id that started the build"},
+	"parameters":          {requiresParameters: true, description: "Build parameters as name/value pairs"},
+	"artifacts":           {requiresArtifacts: true, description: "Archived artifacts with path and size"},
+	"artifactbytes":       {description: "Total size of archived artifacts in bytes (requires artifacts)"},
+	"causes":              {requiresCauses: true, description: "What triggered the build (user, timer, SCM, upstream)"},
+	"estimateddurationms": {description: "Jenkins' duration estimate in milliseconds"},
+	"abortedby":           {requiresInterruption: true, description: "User who aborted an ABORTED run"},
+	"abortreason":         {requiresInterruption: true, description: "Why an ABORTED run was interrupted"},
 }
 
 type metadataCollector struct {
 	enabled    bool
+	redactor   *filter.Redactor
 	parameters map[string]*parameterStat
 	totalRuns  int
+	// artifactsCapped records that a build hit runListArtifactCap, so artifact
+	// filters may have missed files beyond the cap.
+	artifactsCapped bool
 }
 
 type parameterStat struct {
@@ -167,9 +223,10 @@ type parameterStat struct {
 	Samples map[string]struct{}
 }
 
-func newMetadataCollector(enabled bool) *metadataCollector {
+func newMetadataCollector(enabled bool, redactor *filter.Redactor) *metadataCollector {
 	return &metadataCollector{
 		enabled:    enabled,
+		redactor:   redactor,
 		parameters: make(map[string]*parameterStat),
 	}
 }
@@ -184,18 +241,18 @@ func (m *metadataCollector) observe(run *runInspection) {
 		stat, ok := m.parameters[name]
 		if !ok {
 			stat = &parameterStat{
-				Secret:  filter.IsLikelySecret(name),
+				Secret:  m.redactor.IsSecret(name),
 				Samples: make(map[string]struct{}),
 			}
 			m.parameters[name] = stat
 		}
 		stat.Count++
-		if stat.Secret {
-			continue
-		}
 		if strings.TrimSpace(value) == "" {
 			continue
 		}
+		if stat.Secret {
+			value = filter.RedactedValue
+		}
 		if len(stat.Samples) < 5 {
 			stat.Samples[value] = struct{}{}
 		}
@@ -220,6 +277,25 @@ func selectionRequiresArtifacts(fields []string) bool {
 	return false
 }
 
+// aggSumArtifactBytes groups runs and totals their artifact sizes.
+const aggSumArtifactBytes = "sum-artifact-bytes"
+
+// validateArtifactBytes rejects artifact size totals unless --select
+// artifacts already fetches the artifact lists they are summed from, so
+// asking for a total never silently adds the per-build artifact fetch.
+func validateArtifactBytes(fields []string, agg string) error {
+	wanted := agg == aggSumArtifactBytes
+	for _, field := range fields {
+		if field == "artifactbytes" {
+			wanted = true
+		}
+	}
+	if !wanted || selectionRequiresArtifacts(fields) {
+		return nil
+	}
+	return shared.NewExitError(2, "artifact byte totals are summed from the artifact lists; add artifacts to --select (e.g. --select artifacts,artifactbytes)")
+}
+
 func selectionRequiresCauses(fields []string) bool {
 	for _, field := range fields {
 		if spec, ok := selectFieldRegistry[field]; ok && spec.requiresCauses {
@@ -229,10 +305,30 @@ func selectionRequiresCauses(fields []string) bool {
 	return false
 }
 
-func parseSince(value string) (time.Time, error) {
+func selectionRequiresInterruption(fields []string) bool {
+	for _, field := range fields {
+		if spec, ok := selectFieldRegistry[field]; ok && spec.requiresInterruption {
+			return true
+		}
+	}
+	return false
+}
+
+func parseSince(value string, now time.Time) (time.Time, error) {
+	return parseTimeBound("since", value, now)
+}
+
+func parseUntil(value string, now time.Time) (time.Time, error) {
+	return parseTimeBound("until", value, now)
+}
+
+// parseTimeBound accepts an RFC3339 timestamp, used as typed, or a relative
+// duration counted back from now. Callers pass the controller's time
+// (jenkins.Client.ServerNow) so local clock drift does not move the cutoff.
+func parseTimeBound(name, value string, now time.Time) (time.Time, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
-		return time.Time{}, errors.New("since value cannot be empty")
+		return time.Time{}, fmt.Errorf("%s value cannot be empty", name)
 	}
 
 	if ts, err := time.Parse(time.RFC3339, value); err == nil {
@@ -241,9 +337,41 @@ func parseSince(value string) (time.Time, error) {
 
 	dur, err := filter.ParseDuration(value)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid since value %q: %w", value, err)
+		return time.Time{}, fmt.Errorf("invalid %s value %q: %w", name, value, err)
+	}
+	return now.Add(-dur), nil
+}
+
+// parseTimeRange resolves the optional --since/--until flags, rejecting empty
+// windows. Relative values count back from now.
+func parseTimeRange(sinceArg, untilArg string, now time.Time) (since, until *time.Time, err error) {
+	if strings.TrimSpace(sinceArg) != "" {
+		value, err := parseSince(sinceArg, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		since = &value
 	}
-	return time.Now().Add(-dur), nil
+	if strings.TrimSpace(untilArg) != "" {
+		value, err := parseUntil(untilArg, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		until = &value
+	}
+	if since != nil && until != nil && !until.After(*since) {
+		return nil, nil, fmt.Errorf("--until (%s) must be later than --since (%s)", until.UTC().Format(time.RFC3339), since.UTC().Format(time.RFC3339))
+	}
+	return since, until, nil
+}
+
+// validateSinceBuild rejects a --since-build that is not a build number. A
+// bound above the newest build is fine and simply matches nothing.
+func validateSinceBuild(cmd *cobra.Command, n int64) error {
+	if cmd.Flags().Changed("since-build") && n <= 0 {
+		return shared.NewExitError(2, "--since-build must be a positive build number")
+	}
+	return nil
 }
 
 func parseSelectFields(value string) ([]string, error) {
@@ -279,7 +407,7 @@ func normalizeAggregation(value string) (string, error) {
 		return "count", nil
 	}
 	switch trimmed {
-	case "count", "first", "last":
+	case "count", "first", "last", aggSumArtifactBytes:
 		return trimmed, nil
 	default:
 		return "", fmt.Errorf("unsupported aggregation %q", value)
@@ -296,61 +424,146 @@ func NewCmdRun(f *cmdutil.Factory) *cobra.Command {
 		newRunStartCmd(f),
 		newRunListCmd(f),
 		NewCmdRunSearch(f),
+		newRunFailuresCmd(f),
+		newRunStatsCmd(f),
+		newRunTopCmd(f),
+		NewCmdRunLast(f),
 		newRunParamsCmd(f),
 		newRunViewCmd(f),
+		newRunLinkCmd(f),
+		newRunEnvCmd(f),
+		newRunCausesCmd(f),
+		newRunReportCmd(f),
+		newRunStatusCmd(f),
 		newRunCancelCmd(f),
 		newRunRerunCmd(f),
+		newRunAttachCmd(f),
+		newRunArtifactCmd(f),
 	)
 
 	return cmd
 }
 
+// newRunArtifactCmd exposes artifact helpers next to run view so a run can be
+// inspected without switching command trees.
+func newRunArtifactCmd(f *cmdutil.Factory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "artifact",
+		Aliases: []string{"artifacts"},
+		Short:   "Inspect run artifacts",
+	}
+	cmd.AddCommand(artifact.NewCmdArtifactCat(f))
+	return cmd
+}
+
 func newRunStartCmd(f *cmdutil.Factory) *cobra.Command {
 	var params []string
 	var follow bool
 	var interval time.Duration
+	var showStage bool
+	var waitQuiet bool
+	var gate stageGate
+	var retry shared.PollRetry
 	var fuzzyMatch bool
+	var fullPaths bool
 	var noInteractive bool
+	var forceTrigger bool
+	var cause triggerCause
+	var noDefaults bool
+	var requireCapacity bool
+	var queueAnyway bool
+	var annotate bool
+	var download followDownload
 
 	cmd := &cobra.Command{
 		Use:   "start <jobPath>",
 		Short: "Trigger a job run",
 		Long: `Trigger a job run. If the job is not found, will automatically search for similar jobs.
 
+With --follow --download, the artifacts matching the glob are fetched into
+--output once the run succeeds, and --json lists them in downloadedArtifacts.
+Failed runs skip the download unless --download-on-failure is set; the exit
+code still reflects the run result.
+
 Related commands:
   jk search --job-glob '<pattern>'      Search for jobs by pattern
   jk job ls --folder '<folder>'         List jobs in a folder`,
-		Args: cobra.ExactArgs(1),
+		Example: `  jk run start team/app -p VERSION=1.2 --follow --download '**/*.tar.gz' --output ./out --json`,
+		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := gate.validate(follow); err != nil {
+				return err
+			}
+			if err := retry.Validate(); err != nil {
+				return err
+			}
+			if queueAnyway && !requireCapacity {
+				return shared.NewExitError(2, "--queue-anyway requires --require-capacity")
+			}
+			if annotate && !follow {
+				return shared.NewExitError(2, "--annotate-build requires --follow")
+			}
+			if err := cause.validate(follow); err != nil {
+				return err
+			}
+			if download.Pattern == "" && (cmd.Flags().Changed("output") || download.OnFailure) {
+				return shared.NewExitError(2, "--output and --download-on-failure require --download")
+			}
+			if download.Pattern != "" && !follow {
+				return shared.NewExitError(2, "--download requires --follow")
+			}
+
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
-			paramMap := make(map[string]string, len(params))
-			for _, p := range params {
-				parts := strings.SplitN(p, "=", 2)
-				if len(parts) != 2 {
-					return fmt.Errorf("invalid parameter %q", p)
-				}
-				paramMap[strings.TrimSpace(parts[0])] = parts[1]
+			paramMap, err := parseParamFlags(params)
+			if err != nil {
+				return err
+			}
+
+			ios, err := f.Streams()
+			if err != nil {
+				return err
 			}
 
 			// Try to resolve the job path (with fuzzy matching if enabled)
-			resolvedPath, err := resolveJobPath(cmd, client, args[0], fuzzyMatch, !noInteractive)
+			resolvedPath, err := resolveJobPath(cmd, ios, client, args[0], fuzzyMatch, !noInteractive && !ios.GetNeverPrompt())
 			if err != nil {
 				return err
 			}
 
 			// Validate job is buildable before attempting to trigger
-			if err := validateJobIsBuildable(client, resolvedPath); err != nil {
+			if !forceTrigger {
+				if err := validateJobIsBuildable(client, resolvedPath); err != nil {
+					return err
+				}
+			}
+			if requireCapacity {
+				ctx := cmd.Context()
+				if ctx == nil {
+					ctx = context.Background()
+				}
+				if err := checkLabelCapacity(ctx, client, resolvedPath, queueAnyway, cmdutil.WarningWriter(cmd)); err != nil {
+					return err
+				}
+			}
+
+			redactor, err := f.Redactor()
+			if err != nil {
 				return err
 			}
+			if !noDefaults {
+				paramMap = applyDefaultParams(cmd, client, redactor, resolvedPath, paramMap)
+			}
 
-			resp, err := triggerBuild(client, resolvedPath, paramMap)
+			cause.Reason = resolveTriggerReason(cmd, client, cause.Reason)
+			resp, err := triggerBuild(client, resolvedPath, paramMap, cause)
 			if err != nil {
 				return err
 			}
+			record := rememberRun(client.ContextName(), lastrun.New("run start", resolvedPath, paramMap, queueLocationFromResponse(resp), redactor))
 
 			if !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd) {
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered run for %s\n", resolvedPath)
@@ -362,6 +575,7 @@ Related commands:
 						JobPath:       resolvedPath,
 						Message:       "run requested",
 						QueueLocation: queueLocationFromResponse(resp),
+						Cause:         cause.recorded(),
 					}
 					return shared.PrintOutput(cmd, payload, func() error {
 						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered run for %s\n", resolvedPath)
@@ -371,15 +585,40 @@ Related commands:
 				return nil
 			}
 
-			return followTriggeredRun(cmd, client, resolvedPath, resp, interval)
+			opts := followOptions{Interval: interval, ShowStage: showStage, WaitThroughQuietDown: waitQuiet, StageGate: gate, Retry: retry, Progress: followProgress(cmd, f), OnBuild: record}
+			opts.Annotation = cause.annotation()
+			if annotate {
+				opts.Annotation = joinAnnotations(opts.Annotation, buildAnnotation(client.ContextName(), redactor))
+			}
+			if download.Pattern != "" {
+				download.Progress = f.Progress()
+				opts.Download = &download
+			}
+			return followTriggeredRun(cmd, client, resolvedPath, resp, opts)
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
 	cmd.Flags().StringSliceVarP(&params, "param", "p", nil, "Build parameter key=value")
 	cmd.Flags().BoolVar(&follow, "follow", false, "Follow the run progress until completion")
 	cmd.Flags().DurationVar(&interval, "interval", 500*time.Millisecond, "Polling interval when following runs")
+	cmd.Flags().BoolVar(&showStage, "show-stage", false, "Include the current pipeline stage in follow heartbeats (extra requests)")
+	cmd.Flags().BoolVar(&waitQuiet, "wait-through-quiet-down", false, "With --follow, keep waiting if Jenkins is quieting down instead of exiting 14")
+	addStageGateFlags(cmd, &gate)
+	shared.AddPollRetryFlags(cmd, &retry)
 	cmd.Flags().BoolVar(&fuzzyMatch, "fuzzy", false, "Enable fuzzy matching for job names")
 	cmd.Flags().BoolVar(&noInteractive, "non-interactive", false, "Disable interactive selection (fail on ambiguous matches)")
+	shared.AddFullPathsFlag(cmd, &fullPaths)
+	cmd.Flags().BoolVar(&forceTrigger, "force-trigger", false, "Skip the disabled/buildable pre-check and trigger anyway")
+	cmd.Flags().BoolVar(&requireCapacity, "require-capacity", false, "Refuse to trigger when the job's label has no online executors")
+	cmd.Flags().BoolVar(&queueAnyway, "queue-anyway", false, "With --require-capacity, only warn when the label has no online executors")
+	addReasonFlags(cmd, &cause)
+	addAnnotateBuildFlag(cmd, &annotate)
+	addNoDefaultsFlag(cmd, &noDefaults)
+	cmd.Flags().StringVar(&download.Pattern, "download", "", "With --follow, download artifacts matching this glob after the run succeeds")
+	cmd.Flags().StringVarP(&download.OutputDir, "output", "o", ".", "Directory for --download")
+	cmd.Flags().BoolVar(&download.OnFailure, "download-on-failure", false, "With --download, also download when the run does not succeed")
+	cmdutil.SetExitCodes(cmd, followExitCodes())
 	return cmd
 }
 
@@ -388,12 +627,23 @@ func newRunListCmd(f *cmdutil.Factory) *cobra.Command {
 		limit       int
 		cursor      string
 		filterArgs  []string
+		durations   durationFlags
 		sinceArg    string
+		untilArg    string
+		sinceBuild  int64
 		selectArg   string
 		groupBy     string
+		groupLimit  int
 		aggregation string
 		withMeta    bool
 		enableRegex bool
+		urlOnly     bool
+		listFields  bool
+		ignoreScope bool
+		logTail     int
+		fullPaths   bool
+		mine        bool
+		assertion   countAssertion
 	)
 
 	cmd := &cobra.Command{
@@ -405,36 +655,79 @@ func newRunListCmd(f *cmdutil.Factory) *cobra.Command {
 	# Filter by parameter values
 	jk run ls Helm.Chart.Deploy --filter param.CHART_NAME~nova --filter result=SUCCESS --since 7d
 
+	# Only runs you started
+	jk run ls Helm.Chart.Deploy --mine --filter result=FAILURE
+
 	# Group by chart name and return the last run per chart
 	jk run ls Helm.Chart.Deploy --group-by param.CHART_NAME --agg last --json
 
+	# The 10 busiest charts among the last 200 runs (--limit bounds the runs
+	# grouped, --group-limit the groups shown)
+	jk run ls Helm.Chart.Deploy --group-by param.CHART_NAME --limit 200 --group-limit 10
+
 	# Select specific fields for agent consumption
-	jk run ls Helm.Chart.Deploy --select parameters --limit 5 --json --with-meta`,
-		Args: cobra.ExactArgs(1),
+	jk run ls Helm.Chart.Deploy --select parameters --limit 5 --json --with-meta
+
+	# Fail a CI step unless a deploy succeeded today
+	jk run ls Helm.Chart.Deploy --filter result=SUCCESS --since 24h --fail-if-none
+
+	# Artifact storage per chart over the last 500 runs
+	jk run ls Helm.Chart.Deploy --select artifacts --group-by param.CHART_NAME --agg sum-artifact-bytes --limit 500`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if listFields {
+				return cobra.MaximumNArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if listFields {
+				return printRunFields(cmd)
+			}
+
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
-			parsedFilters, err := filter.Parse(filterArgs)
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
 			if err != nil {
 				return err
 			}
 
-			var since *time.Time
-			if strings.TrimSpace(sinceArg) != "" {
-				sinceValue, err := parseSince(sinceArg)
+			durationArgs, err := durations.filterArgs()
+			if err != nil {
+				return err
+			}
+			filterArgs = append(filterArgs, durationArgs...)
+			if mine {
+				mineArg, err := mineFilterArg(cmd.Context(), client)
 				if err != nil {
 					return err
 				}
-				since = &sinceValue
+				filterArgs = append(filterArgs, mineArg)
+			}
+			parsedFilters, err := filter.Parse(filterArgs)
+			if err != nil {
+				return err
+			}
+
+			since, until, err := parseTimeRange(sinceArg, untilArg, client.ServerNow())
+			if err != nil {
+				return err
+			}
+			if err := validateSinceBuild(cmd, sinceBuild); err != nil {
+				return err
 			}
 
 			selectFields, err := parseSelectFields(selectArg)
 			if err != nil {
 				return err
 			}
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
+			quiet := cmdutil.Quiet(cmd)
 
 			agg, err := normalizeAggregation(aggregation)
 			if err != nil {
@@ -443,44 +736,139 @@ func newRunListCmd(f *cmdutil.Factory) *cobra.Command {
 			if groupBy == "" && agg != "" && agg != "count" {
 				return errors.New("aggregation flag requires --group-by")
 			}
+			if err := validateArtifactBytes(selectFields, agg); err != nil {
+				return err
+			}
+			if groupLimit < 0 {
+				return shared.NewExitError(2, "--group-limit must not be negative")
+			}
+			if groupLimit > 0 && groupBy == "" {
+				return shared.NewExitError(2, "--group-limit requires --group-by")
+			}
+			if err := validateLogTailLines(logTail); err != nil {
+				return err
+			}
+			if err := assertion.validate(cmd); err != nil {
+				return err
+			}
+
+			// Without --limit, an interactive listing fills the terminal
+			// instead of a fixed page; scripts keep the default.
+			var ios *iostreams.IOStreams
+			fitted := false
+			if !cmd.Flags().Changed("limit") && groupBy == "" && !urlOnly && !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd) {
+				if ios, err = f.Streams(); err == nil {
+					if rows, ok := cmdutil.TerminalListLimit(ios, runListReservedLines); ok {
+						limit, fitted = rows, true
+					}
+				}
+			}
 
 			opts := runListOptions{
-				Limit:        limit,
-				Cursor:       cursor,
-				Filters:      parsedFilters,
-				Since:        since,
-				SelectFields: selectFields,
-				GroupBy:      groupBy,
-				Aggregation:  agg,
-				WithMeta:     withMeta,
-				AllowRegex:   enableRegex,
-			}
-
-			output, err := executeRunList(cmd.Context(), client, args[0], opts)
+				Limit:             limit,
+				Cursor:            cursor,
+				Filters:           parsedFilters,
+				RawFilters:        append([]string{}, filterArgs...),
+				Since:             since,
+				Until:             until,
+				SinceArg:          sinceArg,
+				UntilArg:          untilArg,
+				SinceBuild:        sinceBuild,
+				SelectFields:      selectFields,
+				GroupBy:           groupBy,
+				GroupLimit:        groupLimit,
+				Aggregation:       agg,
+				WithMeta:          withMeta,
+				AllowRegex:        enableRegex,
+				IgnoreCursorScope: ignoreScope,
+				Redactor:          redactor,
+				Quiet:             quiet,
+			}
+			if payload, err := decodeRunCursor(cursor); err == nil && cursor != "" && payload.Version == 0 {
+				cmdutil.Warnf(cmd, "cursor predates filter tracking; results may overlap or skip runs if --filter, --since, or --until changed")
+			}
+
+			output, err := executeRunList(cmd.Context(), client, jobPath, opts)
 			if err != nil {
 				return err
 			}
+			if logTail > 0 {
+				targets := make([]logTailTarget, 0, len(output.Items))
+				for i := range output.Items {
+					item := &output.Items[i]
+					targets = append(targets, logTailTarget{JobPath: jobPath, Number: item.Number, Result: item.Result, Tail: &item.LogTail})
+				}
+				attachLogTails(cmd.Context(), client, targets, logTail, shared.Concurrency(f))
+			}
+			asserted := assertRunList(&output, opts, assertion)
+			if urlOnly {
+				if err := shared.PrintURLs(cmd, runListURLs(output, opts)...); err != nil {
+					return err
+				}
+				return assertionError(asserted)
+			}
 
-			return shared.PrintOutput(cmd, output, func() error {
-				return renderRunListHuman(cmd, output, opts)
-			})
+			if err := shared.PrintOutput(cmd, output, func() error {
+				if err := renderRunListHuman(cmd, output, opts, shared.NewPathFitter(f, fullPaths), func(url, text string) string {
+					return shared.Hyperlink(f, url, text)
+				}, shared.TimeFormatter(cmd, f), shared.ResultFormatter(cmd, f)); err != nil {
+					return err
+				}
+				if fitted && len(output.Items) >= limit {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), ios.ColorScheme().Mutedf("showing %d most recent; use --limit to override", limit))
+				}
+				return nil
+			}); err != nil {
+				return err
+			}
+			return assertionError(asserted)
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
-	cmd.Flags().IntVar(&limit, "limit", 20, "Number of runs to list")
+	cmd.Flags().IntVar(&limit, "limit", defaultRunListLimit, "Number of runs to list (on a terminal, defaults to what fits the window)")
 	cmd.Flags().StringVar(&cursor, "cursor", "", "Cursor for pagination (use value from previous output)")
+	cmd.Flags().BoolVar(&ignoreScope, "cursor-ignore-filters", false, "Resume --cursor even if it was created with different filters")
 	cmd.Flags().StringSliceVar(&filterArgs, "filter", nil, "Filter runs (repeatable): key[op]value")
+	addDurationFlags(cmd, &durations)
+	addMineFlag(cmd, &mine)
 	cmd.Flags().StringVar(&sinceArg, "since", "", "Filter runs since timestamp or duration (RFC3339, 72h, 7d)")
+	cmd.Flags().StringVar(&untilArg, "until", "", "Filter runs started before timestamp or duration ago (RFC3339, 72h, 7d)")
+	cmd.Flags().Int64Var(&sinceBuild, "since-build", 0, "Stop at build numbers at or below N (combines with --since; the first bound reached wins)")
 	cmd.Flags().StringVar(&selectArg, "select", "", "Select additional fields (comma-separated)")
-	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group results by field (e.g., param.CHART_NAME)")
-	cmd.Flags().StringVar(&aggregation, "agg", "count", "Aggregation function for grouped results: count, first, last")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group results by field (e.g., param.CHART_NAME); --limit still bounds the runs grouped")
+	cmd.Flags().IntVar(&groupLimit, "group-limit", 0, "With --group-by, show at most N groups, largest first (0 for all)")
+	cmd.Flags().StringVar(&aggregation, "agg", "count", "Aggregation function for grouped results: count, first, last, sum-artifact-bytes")
 	cmd.Flags().BoolVar(&withMeta, "with-meta", false, "Include metadata in JSON output")
 	cmd.Flags().BoolVar(&enableRegex, "regex", false, "Enable regular expression matching for filters")
+	cmd.Flags().IntVar(&logTail, "with-log-tail", 0, fmt.Sprintf("Include the last N console lines of FAILURE and UNSTABLE runs (max %d)", maxLogTailLines))
+	shared.AddFullPathsFlag(cmd, &fullPaths)
+	shared.AddURLOnlyFlag(cmd, &urlOnly)
+	addListFieldsFlag(cmd, &listFields)
+	addCountAssertionFlags(cmd, &assertion)
+
+	cmdutil.SetFlagEnum(cmd, "agg", "count", "first", "last", aggSumArtifactBytes)
+	cmdutil.SetExitCodes(cmd, map[int]string{assertionExitCode: "A --fail-if-none or --expect-* assertion failed"})
+	cmdutil.SetFlagEnum(cmd, "select", availableSelectFields()...)
+	cmdutil.SetFilterSupport(cmd, filter.AllowedKeys(), filter.Operators())
 
 	return cmd
 }
 
-func executeRunList(ctx context.Context, client *jenkins.Client, jobPath string, opts runListOptions) (runListOutput, error) {
+func executeRunList(ctx context.Context, client shared.Doer, jobPath string, opts runListOptions) (runListOutput, error) {
+	opts, reqs, builds, err := fetchRunSummaries(ctx, client, jobPath, opts)
+	if err != nil {
+		return runListOutput{}, err
+	}
+
+	out, _, err := processRunList(jobPath, opts, builds, reqs)
+	return out, err
+}
+
+// fetchRunSummaries applies list defaults and retrieves the raw build window
+// for jobPath. A job with no builds yields an empty slice, while a missing job
+// surfaces as a not-found exit error.
+func fetchRunSummaries(ctx context.Context, client shared.Doer, jobPath string, opts runListOptions) (runListOptions, runListRequirements, []runSummary, error) {
 	if opts.Limit <= 0 {
 		opts.Limit = 20
 	}
@@ -488,42 +876,111 @@ func executeRunList(ctx context.Context, client *jenkins.Client, jobPath string,
 		opts.Aggregation = "count"
 	}
 
-	requireArtifacts := filter.RequiresArtifacts(opts.Filters) || selectionRequiresArtifacts(opts.SelectFields) || strings.HasPrefix(opts.GroupBy, "artifact.")
-	requireParams := filter.RequiresParameters(opts.Filters) || selectionRequiresParameters(opts.SelectFields) || strings.HasPrefix(opts.GroupBy, "param.") || opts.WithMeta
-	requireCauses := filter.RequiresCauses(opts.Filters) || selectionRequiresCauses(opts.SelectFields) || strings.HasPrefix(opts.GroupBy, "cause.")
+	reqs := resolveRunListRequirements(opts)
 
 	fetchLimit := opts.Limit + runListHeadroom
 	if fetchLimit < opts.Limit {
 		fetchLimit = opts.Limit
 	}
 
-	path := fmt.Sprintf("/%s/api/json", jenkins.EncodeJobPath(jobPath))
-	query := buildRunListTree(fetchLimit, requireArtifacts, requireParams, requireCauses)
+	path := fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath))
+	query := buildRunListTree(fetchLimit, reqs)
 	req := client.NewRequest().SetQueryParam("tree", query)
 	if ctx != nil {
 		req.SetContext(ctx)
 	}
 
 	var resp runListResponse
-	if _, err := client.Do(req, http.MethodGet, path, &resp); err != nil {
-		return runListOutput{}, err
+	httpResp, err := client.Do(req, http.MethodGet, path, &resp)
+	if err != nil {
+		return opts, reqs, nil, err
+	}
+	if err := shared.CheckResponse(httpResp, fmt.Sprintf("job %s", jobPath)); err != nil {
+		return opts, reqs, nil, err
 	}
+	if resp.Builds == nil {
+		// Jobs always report a builds array, if empty; folders have none.
+		if err := shared.CheckFolder(ctx, client, jobPath, !opts.Quiet); err != nil {
+			return opts, reqs, nil, err
+		}
+	}
+	return opts, reqs, resp.Builds, nil
+}
 
-	out, _, err := processRunList(jobPath, opts, resp.Builds, requireArtifacts, requireParams, requireCauses)
-	return out, err
+// runListRequirements describes which optional build fields a run listing
+// needs, so the tree query only pulls what filters, grouping, and selection
+// actually reference.
+type runListRequirements struct {
+	Parameters bool
+	Causes     bool
+	// Interruption requests the causes of InterruptedBuildAction, which
+	// name who aborted a run.
+	Interruption bool
+	// ArtifactNames and ArtifactPaths request fileName and relativePath for
+	// filtering or grouping; ArtifactDetails requests the full record
+	// (including size) for --select artifacts output.
+	ArtifactNames   bool
+	ArtifactPaths   bool
+	ArtifactDetails bool
+}
+
+func (r runListRequirements) needsArtifacts() bool {
+	return r.ArtifactNames || r.ArtifactPaths || r.ArtifactDetails
 }
 
-func buildRunListTree(fetchLimit int, includeArtifacts, includeParameters, includeCauses bool) string {
+// runListArtifactCap bounds the per-build artifact list requested from
+// Jenkins; builds archiving thousands of files otherwise balloon the response.
+const runListArtifactCap = 100
+
+func resolveRunListRequirements(opts runListOptions) runListRequirements {
+	reqs := runListRequirements{
+		Parameters:      filter.RequiresParameters(opts.Filters) || selectionRequiresParameters(opts.SelectFields) || strings.HasPrefix(opts.GroupBy, "param.") || opts.WithMeta,
+		Causes:          filter.RequiresCauses(opts.Filters) || selectionRequiresCauses(opts.SelectFields) || strings.HasPrefix(opts.GroupBy, "cause."),
+		Interruption:    filter.RequiresInterruption(opts.Filters) || selectionRequiresInterruption(opts.SelectFields) || opts.GroupBy == "abortedBy",
+		ArtifactDetails: selectionRequiresArtifacts(opts.SelectFields),
+	}
+
+	keys := make([]string, 0, len(opts.Filters)+1)
+	for _, f := range opts.Filters {
+		keys = append(keys, f.Key)
+	}
+	keys = append(keys, opts.GroupBy)
+	for _, key := range keys {
+		switch {
+		case key == "artifact.name":
+			reqs.ArtifactNames = true
+		case key == "artifact.path":
+			reqs.ArtifactPaths = true
+		case strings.HasPrefix(key, "artifact."):
+			reqs.ArtifactNames = true
+			reqs.ArtifactPaths = true
+		}
+	}
+	return reqs
+}
+
+func buildRunListTree(fetchLimit int, reqs runListRequirements) string {
 	actionsFields := []string{
 		"lastBuiltRevision[SHA1,branch[name]]",
 		"buildsByBranchName[*]",
 		"remoteUrls",
 	}
-	if includeParameters {
+	if reqs.Parameters {
 		actionsFields = append(actionsFields, "parameters[name,value]")
 	}
-	if includeCauses {
-		actionsFields = append(actionsFields, "causes[shortDescription,userId,userName,_class]")
+	var causeFields []string
+	if reqs.Causes {
+		causeFields = append(causeFields, "shortDescription", "userId", "userName", "_class")
+	}
+	if reqs.Interruption {
+		// InterruptedBuildAction causes carry the aborting user.
+		if !reqs.Causes {
+			causeFields = append(causeFields, "shortDescription")
+		}
+		causeFields = append(causeFields, "user")
+	}
+	if len(causeFields) > 0 {
+		actionsFields = append(actionsFields, fmt.Sprintf("causes[%s]", strings.Join(causeFields, ",")))
 	}
 
 	fields := []string{
@@ -538,15 +995,25 @@ func buildRunListTree(fetchLimit int, includeArtifacts, includeParameters, inclu
 		fmt.Sprintf("actions[%s]", strings.Join(actionsFields, ",")),
 		"changeSet[items[authorEmail,author[fullName],commitId,msg]]",
 	}
-	if includeArtifacts {
-		fields = append(fields, "artifacts[fileName,relativePath,size]")
+	if reqs.needsArtifacts() {
+		var artifactFields []string
+		if reqs.ArtifactNames || reqs.ArtifactDetails {
+			artifactFields = append(artifactFields, "fileName")
+		}
+		if reqs.ArtifactPaths || reqs.ArtifactDetails {
+			artifactFields = append(artifactFields, "relativePath")
+		}
+		if reqs.ArtifactDetails {
+			artifactFields = append(artifactFields, "size")
+		}
+		fields = append(fields, fmt.Sprintf("artifacts[%s]{0,%d}", strings.Join(artifactFields, ","), runListArtifactCap))
 	}
 
 	return fmt.Sprintf("builds[%s]{,%d}", strings.Join(fields, ","), fetchLimit)
 }
 
-func processRunList(jobPath string, opts runListOptions, builds []runSummary, needArtifacts, needParams, needCauses bool) (runListOutput, []*runInspection, error) {
-	normalized := normalizeJobPath(jobPath)
+func processRunList(jobPath string, opts runListOptions, builds []runSummary, reqs runListRequirements) (runListOutput, []*runInspection, error) {
+	normalized := jobpath.Normalize(jobPath)
 	sorted := make([]runSummary, len(builds))
 	copy(sorted, builds)
 	sort.Slice(sorted, func(i, j int) bool {
@@ -562,20 +1029,26 @@ func processRunList(jobPath string, opts runListOptions, builds []runSummary, ne
 		if payload.JobPath != "" && payload.JobPath != normalized {
 			return runListOutput{}, nil, fmt.Errorf("cursor job path %q does not match %q", payload.JobPath, normalized)
 		}
+		if payload.Version > 0 && !opts.IgnoreCursorScope && payload.Scope != runCursorScope(opts) {
+			return runListOutput{}, nil, shared.NewExitError(2, "cursor was created with different filters; drop --cursor or reuse the original flags")
+		}
 		cutoff = payload.Number
 	}
 
-	var sinceMs int64
+	var sinceMs, untilMs int64
 	if opts.Since != nil {
 		sinceMs = opts.Since.UnixMilli()
 	}
+	if opts.Until != nil {
+		untilMs = opts.Until.UnixMilli()
+	}
 
 	evalOpts := []filter.Option{}
 	if opts.AllowRegex {
 		evalOpts = append(evalOpts, filter.WithRegexMatching())
 	}
 
-	collector := newMetadataCollector(opts.WithMeta)
+	collector := newMetadataCollector(opts.WithMeta, opts.Redactor)
 	matched := make([]*runInspection, 0, minInt(opts.Limit, len(sorted)))
 	groups := make(map[string]*runGroupAccumulator)
 	moreMatches := false
@@ -587,8 +1060,18 @@ func processRunList(jobPath string, opts runListOptions, builds []runSummary, ne
 		if sinceMs > 0 && summary.Timestamp < sinceMs {
 			break
 		}
+		if opts.SinceBuild > 0 && summary.Number <= opts.SinceBuild {
+			break
+		}
+		if untilMs > 0 && summary.Timestamp >= untilMs {
+			continue
+		}
 
-		inspection := inspectRun(summary, needParams, needCauses, needArtifacts)
+		if reqs.needsArtifacts() && len(summary.Artifacts) >= runListArtifactCap {
+			collector.artifactsCapped = true
+		}
+
+		inspection := inspectRun(summary, reqs)
 		if inspection == nil {
 			continue
 		}
@@ -607,6 +1090,7 @@ func processRunList(jobPath string, opts runListOptions, builds []runSummary, ne
 				groups[groupValue] = acc
 			}
 			acc.Count++
+			acc.ArtifactBytes += sumArtifactBytes(inspection.Artifacts)
 			if acc.Last == nil || summary.Timestamp > acc.LastTimestamp {
 				acc.Last = inspection
 				acc.LastTimestamp = summary.Timestamp
@@ -626,7 +1110,7 @@ func processRunList(jobPath string, opts runListOptions, builds []runSummary, ne
 
 	nextCursor := ""
 	if moreMatches && len(matched) > 0 {
-		nextCursor = encodeRunCursor(normalized, matched[len(matched)-1].Summary.Number)
+		nextCursor = encodeRunCursor(normalized, matched[len(matched)-1].Summary.Number, runCursorScope(opts))
 	}
 
 	return assembleRunListOutput(jobPath, opts, matched, groups, collector, nextCursor), matched, nil
@@ -639,7 +1123,8 @@ func minInt(a, b int) int {
 	return b
 }
 
-func inspectRun(summary runSummary, needParams, needCauses, needArtifacts bool) *runInspection {
+func inspectRun(summary runSummary, reqs runListRequirements) *runInspection {
+	needParams, needCauses, needArtifacts := reqs.Parameters, reqs.Causes, reqs.needsArtifacts()
 	ctx := filter.Context{
 		"result":            strings.ToUpper(strings.TrimSpace(summary.Result)),
 		"status":            statusFromFlags(summary.Building),
@@ -668,9 +1153,12 @@ func inspectRun(summary runSummary, needParams, needCauses, needArtifacts bool)
 		var causeUsers []string
 		var causeTypes []string
 		for _, cause := range causes {
+			// Both name and id match, so --mine (which filters by id) finds
+			// runs whose cause also records a display name.
 			if cause.UserName != "" {
 				causeUsers = append(causeUsers, cause.UserName)
-			} else if cause.UserID != "" {
+			}
+			if cause.UserID != "" && cause.UserID != cause.UserName {
 				causeUsers = append(causeUsers, cause.UserID)
 			}
 			if cause.Type != "" {
@@ -685,6 +1173,14 @@ func inspectRun(summary runSummary, needParams, needCauses, needArtifacts bool)
 		}
 	}
 
+	var interruption *runInterruption
+	if reqs.Interruption {
+		interruption = abortDetails(summary.Result, summary.Actions)
+		if interruption != nil && interruption.By != "" {
+			ctx["abortedBy"] = interruption.By
+		}
+	}
+
 	if needArtifacts {
 		var names []string
 		var paths []string
@@ -714,11 +1210,12 @@ func inspectRun(summary runSummary, needParams, needCauses, needArtifacts bool)
 	}
 
 	return &runInspection{
-		Summary:    summary,
-		Context:    ctx,
-		Parameters: parameters,
-		Causes:     causes,
-		Artifacts:  summary.Artifacts,
+		Summary:      summary,
+		Context:      ctx,
+		Parameters:   parameters,
+		Causes:       causes,
+		Interruption: interruption,
+		Artifacts:    summary.Artifacts,
 	}
 }
 
@@ -746,6 +1243,9 @@ func extractParametersFromSummary(summary runSummary) map[string]string {
 func extractCausesFromSummary(summary runSummary) []runCauseInfo {
 	var causes []runCauseInfo
 	for _, action := range summary.Actions {
+		if isInterruptionAction(action) {
+			continue
+		}
 		raw, ok := action["causes"].([]any)
 		if !ok {
 			continue
@@ -835,17 +1335,25 @@ func (m *metadataCollector) metadata(jobPath string, opts runListOptions) *runLi
 		Filters: &filterMetadata{
 			Available: filter.AllowedKeys(),
 			Operators: filter.Operators(),
+			Applied:   append([]string{}, opts.RawFilters...),
 		},
 		Fields:    availableSelectFields(),
 		Selection: append([]string{}, opts.SelectFields...),
 	}
 	if opts.Since != nil {
-		meta.Since = opts.Since.Format(time.RFC3339)
+		meta.Since = shared.FormatTime(*opts.Since)
+	}
+	if opts.Until != nil {
+		meta.Until = shared.FormatTime(*opts.Until)
 	}
+	meta.SinceBuild = opts.SinceBuild
 	if opts.GroupBy != "" {
 		meta.GroupBy = opts.GroupBy
 		meta.Aggregation = opts.Aggregation
 	}
+	if m.artifactsCapped {
+		meta.Notes = append(meta.Notes, fmt.Sprintf("artifact lists are capped at %d per build; artifact filters may miss files beyond the cap", runListArtifactCap))
+	}
 
 	if !m.enabled || m.totalRuns == 0 {
 		meta.Suggestions = buildMetadataSuggestions(jobPath, opts)
@@ -861,7 +1369,7 @@ func (m *metadataCollector) metadata(jobPath string, opts runListOptions) *runLi
 		if m.totalRuns > 0 {
 			info.Frequency = float64(stat.Count) / float64(m.totalRuns)
 		}
-		if !stat.Secret && len(stat.Samples) > 0 {
+		if len(stat.Samples) > 0 {
 			samples := make([]string, 0, len(stat.Samples))
 			for sample := range stat.Samples {
 				samples = append(samples, sample)
@@ -884,7 +1392,7 @@ func (m *metadataCollector) metadata(jobPath string, opts runListOptions) *runLi
 }
 
 func buildMetadataSuggestions(jobPath string, opts runListOptions) []string {
-	normalized := normalizeJobPath(jobPath)
+	normalized := jobpath.Normalize(jobPath)
 	suggestions := make([]string, 0, 3)
 
 	if len(opts.Filters) == 0 {
@@ -903,7 +1411,31 @@ func buildMetadataSuggestions(jobPath string, opts runListOptions) []string {
 	return suggestions
 }
 
-func renderRunListHuman(cmd *cobra.Command, output runListOutput, opts runListOptions) error {
+// runListURLs returns the run URLs a human listing would show: one per item,
+// or the representative run of each group.
+func runListURLs(output runListOutput, opts runListOptions) []string {
+	var urls []string
+	for _, item := range output.Items {
+		urls = append(urls, item.URL)
+	}
+	for _, group := range output.Groups {
+		run := group.Last
+		if opts.Aggregation == "first" {
+			run = group.First
+		}
+		if run != nil {
+			urls = append(urls, run.URL)
+		}
+	}
+	return urls
+}
+
+// groupRowColumnsWidth approximates the cells taken by the count, run,
+// result, and timestamp columns that follow a group label; labels are often
+// job paths or long parameter values and give way to them.
+const groupRowColumnsWidth = 40
+
+func renderRunListHuman(cmd *cobra.Command, output runListOutput, opts runListOptions, fit shared.PathFitter, link func(url, text string) string, stamp func(string) string, result func(string) string) error {
 	w := cmd.OutOrStdout()
 
 	if len(output.Items) == 0 && len(output.Groups) == 0 {
@@ -918,43 +1450,50 @@ func renderRunListHuman(cmd *cobra.Command, output runListOutput, opts runListOp
 			if strings.TrimSpace(label) == "" {
 				label = "(none)"
 			}
+			label = fit.Fit(label, groupRowColumnsWidth)
 			switch opts.Aggregation {
 			case "count":
 				if group.Last != nil {
-					_, _ = fmt.Fprintf(w, "%s\t%d\t#%d\t%s\t%s\n", label, group.Count, group.Last.Number, strings.ToUpper(group.Last.Result), group.Last.StartTime)
+					_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", label, group.Count, link(group.Last.URL, fmt.Sprintf("#%d", group.Last.Number)), result(strings.ToUpper(group.Last.Result)), stamp(group.Last.StartTime))
 				} else {
 					_, _ = fmt.Fprintf(w, "%s\t%d\n", label, group.Count)
 				}
 			case "last":
 				if group.Last != nil {
-					_, _ = fmt.Fprintf(w, "%s\t#%d\t%s\t%s\n", label, group.Last.Number, strings.ToUpper(group.Last.Result), group.Last.StartTime)
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", label, link(group.Last.URL, fmt.Sprintf("#%d", group.Last.Number)), result(strings.ToUpper(group.Last.Result)), stamp(group.Last.StartTime))
 				} else {
 					_, _ = fmt.Fprintf(w, "%s\t(no data)\n", label)
 				}
+			case aggSumArtifactBytes:
+				_, _ = fmt.Fprintf(w, "%s\t%d\t%d bytes\n", label, group.Count, group.ArtifactBytes)
 			case "first":
 				if group.First != nil {
-					_, _ = fmt.Fprintf(w, "%s\t#%d\t%s\t%s\n", label, group.First.Number, strings.ToUpper(group.First.Result), group.First.StartTime)
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", label, link(group.First.URL, fmt.Sprintf("#%d", group.First.Number)), result(strings.ToUpper(group.First.Result)), stamp(group.First.StartTime))
 				} else {
 					_, _ = fmt.Fprintf(w, "%s\t(no data)\n", label)
 				}
 			default:
 				if group.Last != nil {
-					_, _ = fmt.Fprintf(w, "%s\t#%d\t%s\t%s\n", label, group.Last.Number, strings.ToUpper(group.Last.Result), group.Last.StartTime)
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", label, link(group.Last.URL, fmt.Sprintf("#%d", group.Last.Number)), result(strings.ToUpper(group.Last.Result)), stamp(group.Last.StartTime))
 				} else {
 					_, _ = fmt.Fprintf(w, "%s\t(no data)\n", label)
 				}
 			}
 		}
+		if more := output.GroupCount - len(output.Groups); output.HasMoreGroups && more > 0 {
+			_, _ = fmt.Fprintf(w, "… and %d more groups\n", more)
+		}
 	} else {
 		for _, item := range output.Items {
 			_, _ = fmt.Fprintf(
 				w,
-				"#%d\t%s\t%s\t%s\n",
-				item.Number,
-				strings.ToUpper(item.Result),
-				item.StartTime,
-				shared.DurationString(item.DurationMs),
+				"%s\t%s\t%s\t%s\n",
+				link(item.URL, fmt.Sprintf("#%d", item.Number)),
+				result(strings.ToUpper(item.Result)),
+				stamp(item.StartTime),
+				shared.DurationPadded(item.DurationMs),
 			)
+			writeLogTail(w, item.LogTail)
 		}
 	}
 
@@ -965,65 +1504,156 @@ func renderRunListHuman(cmd *cobra.Command, output runListOutput, opts runListOp
 }
 
 func newRunViewCmd(f *cmdutil.Factory) *cobra.Command {
+	var urlOnly bool
+	var sizes bool
+	var checkArgs []string
+
 	cmd := &cobra.Command{
 		Use:   "view <jobPath> <buildNumber>",
 		Short: "View run details",
-		Args:  cobra.ExactArgs(2),
+		Example: `  # Show a run
+	jk run view Helm.Chart.Deploy 42
+
+	# Exit 16 unless the required stages all passed
+	jk run view Helm.Chart.Deploy 42 --checks Build,Test,Security-Scan`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			required, err := parseRequiredStages(checkArgs)
+			if err != nil {
+				return err
+			}
+
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
 			num, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
 				return fmt.Errorf("invalid build number: %w", err)
 			}
 
-			path := fmt.Sprintf("/%s/%d/api/json", jenkins.EncodeJobPath(args[0]), num)
+			path := fmt.Sprintf("/%s/%d/api/json", jobpath.Encode(jobPath), num)
 			var detail runDetail
-			_, err = client.Do(client.NewRequest(), http.MethodGet, path, &detail)
+			resp, err := client.Do(client.NewRequest(), http.MethodGet, path, &detail)
 			if err != nil {
 				return err
 			}
+			if err := shared.CheckResponse(resp, fmt.Sprintf("run %s #%d", jobPath, num)); err != nil {
+				return shared.ExplainNotFound(cmd, client, jobPath, err)
+			}
 
-			testReport, err := shared.FetchTestReport(client, args[0], num)
+			testReport, err := shared.FetchTestReport(client, jobPath, num)
 			if err != nil {
 				jklog.L().Debug().Err(err).Msg("fetch test report failed")
 			}
 
-			output := buildRunDetailOutput(args[0], detail, testReport)
+			output := buildRunDetailOutput(jobPath, detail, testReport)
+			if urlOnly {
+				return shared.PrintURLs(cmd, output.URL)
+			}
+			if sizes {
+				if err := attachRunSizes(cmd.Context(), client, jobPath, num, &output); err != nil {
+					return err
+				}
+			}
+			if len(required) > 0 {
+				stages, supported, err := fetchStageView(cmd.Context(), client, jobPath, num)
+				if err != nil {
+					return err
+				}
+				if !supported {
+					return shared.NewExitError(8, "--checks needs pipeline stage data, which is unavailable for this run (is the Pipeline Stage View plugin installed?)")
+				}
+				output.Checks, output.ChecksVerdict = evaluateChecks(required, stages)
+			}
 
-			return shared.PrintOutput(cmd, output, func() error {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Run #%d (%s)\n", output.Number, output.Status)
+			if err := shared.PrintOutput(cmd, output, func() error {
+				result := shared.ResultFormatter(cmd, f)
+				status := output.Status
+				if output.Result == "" {
+					status = result(status)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Run %s (%s)\n", shared.Hyperlink(f, output.URL, fmt.Sprintf("#%d", output.Number)), status)
 				if output.Result != "" {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Result: %s\n", output.Result)
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Result: %s\n", result(output.Result))
 				}
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "URL: %s\n", output.URL)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "URL: %s\n", shared.Hyperlink(f, output.URL, output.URL))
 				if output.StartTime != "" {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Started: %s\n", output.StartTime)
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Started: %s\n", shared.TimeFormatter(cmd, f)(output.StartTime))
 				}
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Duration: %s\n", shared.DurationString(output.DurationMs))
 				if output.SCM != nil && (output.SCM.Branch != "" || output.SCM.Commit != "" || output.SCM.Repo != "") {
 					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "SCM: branch=%s commit=%s repo=%s\n", output.SCM.Branch, output.SCM.Commit, output.SCM.Repo)
 				}
+				if len(output.Causes) > 0 {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Causes:")
+					for _, c := range output.Causes {
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", describeCause(c))
+					}
+				}
 				if len(output.Parameters) > 0 {
 					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Parameters:")
 					for _, p := range output.Parameters {
 						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "  %s=%v\n", p.Name, p.Value)
 					}
 				}
+				if output.AbortReason != "" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Aborted: %s\n", output.AbortReason)
+				}
 				if output.Tests != nil {
 					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Tests: total=%d failed=%d skipped=%d\n", output.Tests.Total, output.Tests.Failed, output.Tests.Skipped)
 				}
+				if output.ArtifactBytes != nil {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Artifact bytes: %d (%d files)\n", *output.ArtifactBytes, len(output.Artifacts))
+				}
+				if output.LogBytes != nil {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Log bytes: %d\n", *output.LogBytes)
+				}
+				if len(output.Checks) > 0 {
+					renderRunChecks(cmd.OutOrStdout(), output.Checks)
+				}
 				return nil
-			})
+			}); err != nil {
+				return err
+			}
+			return checksError(output.ChecksVerdict)
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
+	shared.AddURLOnlyFlag(cmd, &urlOnly)
+	cmd.Flags().StringSliceVar(&checkArgs, "checks", nil, "Required pipeline stages (comma-separated, case-insensitive); exit 16 unless all passed")
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		8:              "--checks found no pipeline stage data for the run",
+		checksExitCode: "A --checks stage is missing or did not pass",
+	})
+	cmd.Flags().BoolVar(&sizes, "sizes", false, "Report artifact and console log sizes in bytes (one extra request for the log size)")
 	return cmd
 }
 
+// attachRunSizes fills in the storage a run produced: artifact sizes come
+// from the detail already fetched, the log size from the X-Text-Size header
+// of a single progressiveText request.
+func attachRunSizes(ctx context.Context, client shared.Doer, jobPath string, num int64, output *runDetailOutput) error {
+	artifactBytes := sumArtifactBytes(output.Artifacts)
+	output.ArtifactBytes = &artifactBytes
+
+	logBytes, err := shared.ProgressiveLogSize(ctx, client, jobPath, int(num))
+	if err != nil {
+		return err
+	}
+	if logBytes >= 0 {
+		output.LogBytes = &logBytes
+	}
+	return nil
+}
+
 func newRunCancelCmd(f *cmdutil.Factory) *cobra.Command {
 	var mode string
 
@@ -1037,6 +1667,11 @@ func newRunCancelCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
 			num, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
 				return fmt.Errorf("invalid build number: %w", err)
@@ -1047,109 +1682,170 @@ func newRunCancelCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 
-			path := fmt.Sprintf("/%s/%d/%s", jenkins.EncodeJobPath(args[0]), num, action)
-			resp, err := client.Do(client.NewRequest(), http.MethodPost, path, nil)
-			if err != nil {
+			if err := cancelBuild(client, jobPath, num, action); err != nil {
 				return err
 			}
-			if resp.StatusCode() >= 300 {
-				return fmt.Errorf("cancel failed: %s", resp.Status())
-			}
 
 			if shared.WantsJSON(cmd) || shared.WantsYAML(cmd) {
 				payload := map[string]any{
-					"jobPath": args[0],
+					"jobPath": jobPath,
 					"build":   num,
 					"action":  action,
 					"status":  "requested",
 				}
 				return shared.PrintOutput(cmd, payload, func() error {
-					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancellation requested for %s #%d (%s)\n", args[0], num, action)
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancellation requested for %s #%d (%s)\n", jobPath, num, action)
 					return nil
 				})
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancellation requested for %s #%d (%s)\n", args[0], num, action)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Cancellation requested for %s #%d (%s)\n", jobPath, num, action)
 			return nil
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
 	cmd.Flags().StringVar(&mode, "mode", "stop", "Termination mode: stop, term, or kill")
+	cmdutil.SetFlagEnum(cmd, "mode", "stop", "term", "kill")
 	return cmd
 }
 
 func newRunRerunCmd(f *cmdutil.Factory) *cobra.Command {
 	var follow bool
 	var interval time.Duration
+	var showStage bool
+	var waitQuiet bool
+	var gate stageGate
+	var retry shared.PollRetry
+	var forceTrigger bool
+	var cause triggerCause
+	var noDefaults bool
+	var annotate bool
 
 	cmd := &cobra.Command{
 		Use:   "rerun <jobPath> <buildNumber>",
 		Short: "Rerun a job using the previous parameters",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := gate.validate(follow); err != nil {
+				return err
+			}
+			if err := retry.Validate(); err != nil {
+				return err
+			}
+			if annotate && !follow {
+				return shared.NewExitError(2, "--annotate-build requires --follow")
+			}
+			if err := cause.validate(follow); err != nil {
+				return err
+			}
+
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
 			num, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
 				return fmt.Errorf("invalid build number: %w", err)
 			}
 
-			detail, err := fetchRunDetail(client, args[0], num)
+			detail, err := fetchRunDetail(client, jobPath, num)
 			if err != nil {
 				return err
 			}
 
+			if !forceTrigger {
+				if err := validateJobIsBuildable(client, jobPath); err != nil {
+					return err
+				}
+			}
+
+			redactor, err := f.Redactor()
+			if err != nil {
+				return err
+			}
 			params := collectRerunParameters(*detail)
-			resp, err := triggerBuild(client, args[0], params)
+			if !noDefaults {
+				params = applyDefaultParams(cmd, client, redactor, jobPath, params)
+			}
+			cause.Reason = resolveTriggerReason(cmd, client, cause.Reason)
+			resp, err := triggerBuild(client, jobPath, params, cause)
 			if err != nil {
 				return err
 			}
+			record := rememberRun(client.ContextName(), lastrun.New("run rerun", jobPath, params, queueLocationFromResponse(resp), redactor))
 
 			if !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd) {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered rerun for %s #%d\n", args[0], num)
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered rerun for %s #%d\n", jobPath, num)
 			}
 
 			if !follow {
 				if shared.WantsJSON(cmd) || shared.WantsYAML(cmd) {
 					payload := runTriggerOutput{
-						JobPath:       args[0],
+						JobPath:       jobPath,
 						Message:       "rerun requested",
 						QueueLocation: queueLocationFromResponse(resp),
+						Cause:         cause.recorded(),
 					}
 					return shared.PrintOutput(cmd, payload, func() error {
-						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered rerun for %s #%d\n", args[0], num)
+						_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Triggered rerun for %s #%d\n", jobPath, num)
 						return nil
 					})
 				}
 				return nil
 			}
 
-			return followTriggeredRun(cmd, client, args[0], resp, interval)
+			opts := followOptions{Interval: interval, ShowStage: showStage, WaitThroughQuietDown: waitQuiet, StageGate: gate, Retry: retry, Progress: followProgress(cmd, f), OnBuild: record}
+			opts.Annotation = cause.annotation()
+			if annotate {
+				opts.Annotation = joinAnnotations(opts.Annotation, buildAnnotation(client.ContextName(), redactor))
+			}
+			return followTriggeredRun(cmd, client, jobPath, resp, opts)
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
 	cmd.Flags().BoolVar(&follow, "follow", false, "Follow the rerun progress until completion")
 	cmd.Flags().DurationVar(&interval, "interval", 500*time.Millisecond, "Polling interval when following runs")
+	cmd.Flags().BoolVar(&showStage, "show-stage", false, "Include the current pipeline stage in follow heartbeats (extra requests)")
+	cmd.Flags().BoolVar(&waitQuiet, "wait-through-quiet-down", false, "With --follow, keep waiting if Jenkins is quieting down instead of exiting 14")
+	addStageGateFlags(cmd, &gate)
+	shared.AddPollRetryFlags(cmd, &retry)
+	cmd.Flags().BoolVar(&forceTrigger, "force-trigger", false, "Skip the disabled/buildable pre-check and trigger anyway")
+	addReasonFlags(cmd, &cause)
+	addAnnotateBuildFlag(cmd, &annotate)
+	addNoDefaultsFlag(cmd, &noDefaults)
+	cmdutil.SetExitCodes(cmd, followExitCodes())
 	return cmd
 }
 
 // jobMetadata represents job information including its type
 type jobMetadata struct {
-	Class string         `json:"_class"`
-	Jobs  []jobListEntry `json:"jobs"`
+	Class     string         `json:"_class"`
+	Buildable *bool          `json:"buildable"`
+	Disabled  *bool          `json:"disabled"`
+	Jobs      []jobListEntry `json:"jobs"`
 }
 
+// jobPrecheckTree is the single request made before triggering: the class
+// identifies folders and multibranch parents, buildable/disabled catch jobs
+// Jenkins would reject with an HTML error page.
+const jobPrecheckTree = "_class,buildable,disabled,jobs[name,_class]"
+
 // validateJobIsBuildable checks if a job can be built directly.
-// Returns an error with helpful guidance if the job is a folder or multibranch pipeline.
-func validateJobIsBuildable(client *jenkins.Client, jobPath string) error {
-	// Fetch job metadata to check its type
-	path := fmt.Sprintf("/%s/api/json", jenkins.EncodeJobPath(jobPath))
+// Returns an exit-code-2 error with guidance if the job is disabled, not
+// buildable, a folder, or a multibranch pipeline.
+func validateJobIsBuildable(client shared.Doer, jobPath string) error {
+	path := fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath))
 	var metadata jobMetadata
 	resp, err := client.Do(
-		client.NewRequest().SetQueryParam("tree", "jobs[name,_class],_class"),
+		client.NewRequest().SetQueryParam("tree", jobPrecheckTree),
 		http.MethodGet,
 		path,
 		&metadata,
@@ -1163,34 +1859,31 @@ func validateJobIsBuildable(client *jenkins.Client, jobPath string) error {
 		return nil
 	}
 
-	// Check if it's a multibranch pipeline
-	if isMultibranchClass(metadata.Class) {
-		msg := fmt.Sprintf("'%s' is a Multibranch Pipeline and cannot be built directly", jobPath)
-		if len(metadata.Jobs) > 0 {
-			msg += "\n\nAvailable branches:"
-			for _, job := range metadata.Jobs {
-				msg += fmt.Sprintf("\n  %s/%s", jobPath, job.Name)
-			}
-			msg += fmt.Sprintf("\n\nUsage: jk run start \"%s/<branch>\"", jobPath)
-			msg += fmt.Sprintf("\nExample: jk run start \"%s/%s\"", jobPath, metadata.Jobs[0].Name)
+	// Folders and multibranch projects cannot be built; name their children.
+	if shared.IsFolderClass(metadata.Class) {
+		children := make([]string, 0, len(metadata.Jobs))
+		for _, job := range metadata.Jobs {
+			children = append(children, job.Name)
 		}
-		return errors.New(msg)
+		return shared.FolderError(jobPath, metadata.Class, children)
 	}
 
-	// Check if it's a folder
-	if isFolderClass(metadata.Class) {
-		return fmt.Errorf("'%s' is a folder, not a buildable job", jobPath)
+	if metadata.Disabled != nil && *metadata.Disabled {
+		return shared.NewExitError(2, fmt.Sprintf("job %s is disabled; run `jk job enable %s` first", jobPath, jobPath))
+	}
+	if metadata.Buildable != nil && !*metadata.Buildable {
+		return shared.NewExitError(2, fmt.Sprintf("job %s is not buildable", jobPath))
 	}
 
 	return nil
 }
 
-func triggerBuild(client *jenkins.Client, jobPath string, params map[string]string) (*resty.Response, error) {
-	if client == nil {
-		return nil, errors.New("jenkins client is required")
-	}
-
-	encoded := jenkins.EncodeJobPath(jobPath)
+// triggerBuild queues jobPath. With a token the trigger is a remote one and
+// a non-empty reason is sent as the "cause" query parameter, which Jenkins
+// then records as the build's cause text; without a token it would be
+// ignored, so it is not sent.
+func triggerBuild(client shared.Doer, jobPath string, params map[string]string, cause triggerCause) (*resty.Response, error) {
+	encoded := jobpath.Encode(jobPath)
 	if encoded == "" {
 		return nil, errors.New("job path is required")
 	}
@@ -1201,31 +1894,94 @@ func triggerBuild(client *jenkins.Client, jobPath string, params map[string]stri
 		req.SetFormData(params)
 		methodPath = fmt.Sprintf("/%s/buildWithParameters", encoded)
 	}
+	if cause.Token != "" {
+		req.SetQueryParam("token", cause.Token)
+		if cause.Reason != "" {
+			req.SetQueryParam("cause", cause.Reason)
+		}
+	}
 
 	resp, err := client.Do(req, http.MethodPost, methodPath, nil)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode() >= 300 {
-		return nil, fmt.Errorf("trigger build failed: %s", resp.Status())
+		return nil, fmt.Errorf("trigger build failed: %s", shared.ResponseStatus(resp))
 	}
 	return resp, nil
 }
 
-func followTriggeredRun(cmd *cobra.Command, client *jenkins.Client, jobPath string, resp *resty.Response, interval time.Duration) error {
+func followTriggeredRun(cmd *cobra.Command, client shared.Doer, jobPath string, resp *resty.Response, opts followOptions) error {
+	opts.Retry = opts.Retry.WithNotes(cmd, cmd.ErrOrStderr())
 	queueLocation := queueLocationFromResponse(resp)
-	buildNumber, err := waitForBuildNumber(client, queueLocation, 5*time.Minute)
+	buildNumber, err := waitForBuildNumber(client, queueLocation, 5*time.Minute, cmdutil.WarningWriter(cmd), opts)
+	var cancelled *queueCancelledError
+	if errors.As(err, &cancelled) {
+		return reportQueueCancelled(cmd, jobPath, cancelled)
+	}
 	if err != nil {
 		return err
 	}
+	if opts.OnBuild != nil {
+		opts.OnBuild(buildNumber)
+	}
+	if opts.Annotation != "" {
+		annotateBuild(cmd, client, jobPath, buildNumber, opts.Annotation)
+	}
+	return followRun(cmd, client, jobPath, buildNumber, opts)
+}
 
+// followRun monitors a started build until it completes, streaming its log
+// unless the output is structured, and exits with the result-mapped code.
+func followRun(cmd *cobra.Command, client shared.Doer, jobPath string, buildNumber int64, opts followOptions) error {
 	streamLogs := !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd)
-	result, err := monitorRun(cmd, client, jobPath, buildNumber, interval, streamLogs)
+	result, err := monitorRun(cmd, client, jobPath, buildNumber, opts, streamLogs)
 	if err != nil {
 		return err
 	}
+	return finishFollowedRun(cmd, client, jobPath, buildNumber, result, opts.Download)
+}
+
+// followDownload is the artifact download requested with run start
+// --download.
+type followDownload struct {
+	Pattern   string
+	OutputDir string
+	// OnFailure downloads even when the run did not succeed.
+	OnFailure bool
+	Progress  cmdutil.ProgressReporter
+}
+
+// downloadFollowedArtifacts runs download for a finished build. It returns
+// nothing when the result skips the download.
+func downloadFollowedArtifacts(cmd *cobra.Command, client shared.Doer, jobPath string, buildNumber int64, result string, download *followDownload) ([]string, error) {
+	if download == nil {
+		return nil, nil
+	}
+	if exitCodeForResult(result) != 0 && !download.OnFailure {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Skipping artifact download: run #%d finished with %s (--download-on-failure downloads anyway)\n", buildNumber, result)
+		return nil, nil
+	}
+	return artifact.DownloadArtifacts(client, jobPath, buildNumber, artifact.DownloadOptions{
+		Pattern:   download.Pattern,
+		OutputDir: download.OutputDir,
+		Progress:  download.Progress,
+	})
+}
 
-	if shared.WantsJSON(cmd) || shared.WantsYAML(cmd) {
+// finishFollowedRun downloads the artifacts requested with --download,
+// prints the run detail for --json and --yaml and maps result to the exit
+// code. A failed download fails a successful run; otherwise the run result
+// decides the exit code.
+func finishFollowedRun(cmd *cobra.Command, client shared.Doer, jobPath string, buildNumber int64, result string, download *followDownload) error {
+	downloaded, downloadErr := downloadFollowedArtifacts(cmd, client, jobPath, buildNumber, result, download)
+	structured := shared.WantsJSON(cmd) || shared.WantsYAML(cmd)
+	if !structured {
+		for _, file := range downloaded {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Downloaded %s\n", file)
+		}
+	}
+	if structured {
 		detail, err := fetchRunDetail(client, jobPath, buildNumber)
 		if err != nil {
 			return err
@@ -1235,6 +1991,7 @@ func followTriggeredRun(cmd *cobra.Command, client *jenkins.Client, jobPath stri
 			jklog.L().Debug().Err(err).Msg("fetch test report failed")
 		}
 		output := buildRunDetailOutput(jobPath, *detail, testReport)
+		output.DownloadedArtifacts = downloaded
 		if err := shared.PrintOutput(cmd, output, func() error {
 			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Run #%d completed with status %s\n", output.Number, output.Result)
 			return nil
@@ -1244,6 +2001,12 @@ func followTriggeredRun(cmd *cobra.Command, client *jenkins.Client, jobPath stri
 	}
 
 	code := exitCodeForResult(result)
+	if downloadErr != nil {
+		if code == 0 {
+			return downloadErr
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Artifact download failed: %v\n", downloadErr)
+	}
 	if code == 0 {
 		return nil
 	}
@@ -1261,9 +2024,9 @@ func queueLocationFromResponse(resp *resty.Response) string {
 	return location
 }
 
-func fetchRunDetail(client *jenkins.Client, jobPath string, buildNumber int64) (*runDetail, error) {
+func fetchRunDetail(client shared.Doer, jobPath string, buildNumber int64) (*runDetail, error) {
 	var detail runDetail
-	path := fmt.Sprintf("/%s/%d/api/json", jenkins.EncodeJobPath(jobPath), buildNumber)
+	path := fmt.Sprintf("/%s/%d/api/json", jobpath.Encode(jobPath), buildNumber)
 	_, err := client.Do(client.NewRequest(), http.MethodGet, path, &detail)
 	if err != nil {
 		return nil, err
@@ -1271,6 +2034,32 @@ func fetchRunDetail(client *jenkins.Client, jobPath string, buildNumber int64) (
 	return &detail, nil
 }
 
+// cancelBuild posts the stop, term, or kill action for a build.
+func cancelBuild(client shared.Doer, jobPath string, number int64, action string) error {
+	path := fmt.Sprintf("/%s/%d/%s", jobpath.Encode(jobPath), number, action)
+	resp, err := client.Do(client.NewRequest(), http.MethodPost, path, nil)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode() >= 300 {
+		return fmt.Errorf("cancel failed: %s", shared.ResponseStatus(resp))
+	}
+	return nil
+}
+
+// parseParamFlags turns repeated --param key=value flags into a map.
+func parseParamFlags(params []string) (map[string]string, error) {
+	paramMap := make(map[string]string, len(params))
+	for _, p := range params {
+		parts := strings.SplitN(p, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid parameter %q", p)
+		}
+		paramMap[strings.TrimSpace(parts[0])] = parts[1]
+	}
+	return paramMap, nil
+}
+
 func resolveCancelAction(mode string) (string, error) {
 	if mode == "" {
 		return "stop", nil
@@ -1287,7 +2076,76 @@ func resolveCancelAction(mode string) (string, error) {
 	}
 }
 
-func waitForBuildNumber(client *jenkins.Client, queueLocation string, timeout time.Duration) (int64, error) {
+// queuePollInterval is how often waitForBuildNumber polls the queue item.
+var queuePollInterval = time.Second
+
+const (
+	// quietDownExitCode reports that a followed run is stuck in the queue
+	// because the controller is quieting down.
+	quietDownExitCode = 14
+	// quietDownWhy is the queue blocker Jenkins reports during quiet-down
+	// ("Jenkins is about to shut down").
+	quietDownWhy = "about to shut down"
+	// queueCancelledExitCode reports that a followed run was cancelled while
+	// still queued, so no build ever started.
+	queueCancelledExitCode = 15
+	// outcomeCancelledInQueue is the outcome reported for such runs, as
+	// opposed to a build that started and finished ABORTED.
+	outcomeCancelledInQueue = "cancelled-in-queue"
+)
+
+// queueCancelledError is returned by waitForBuildNumber when the queue item
+// was cancelled before it became a build.
+type queueCancelledError struct {
+	QueueID int64
+	Why     string
+}
+
+func (e *queueCancelledError) Error() string {
+	if e.Why != "" {
+		return fmt.Sprintf("queue item cancelled: %s", e.Why)
+	}
+	return "queue item cancelled"
+}
+
+type queueCancelledOutput struct {
+	SchemaVersion string `json:"schemaVersion"`
+	JobPath       string `json:"jobPath"`
+	Outcome       string `json:"outcome"`
+	QueueID       int64  `json:"queueId,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// reportQueueCancelled prints the cancelled-in-queue outcome and returns its
+// exit code.
+func reportQueueCancelled(cmd *cobra.Command, jobPath string, cancelled *queueCancelledError) error {
+	output := queueCancelledOutput{
+		SchemaVersion: "1.0",
+		JobPath:       jobpath.Normalize(jobPath),
+		Outcome:       outcomeCancelledInQueue,
+		QueueID:       cancelled.QueueID,
+		Reason:        cancelled.Why,
+	}
+	if err := shared.PrintOutput(cmd, output, func() error {
+		if output.Reason != "" {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Run was cancelled in the queue before it started: %s\n", output.Reason)
+			return nil
+		}
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Run was cancelled in the queue before it started")
+		return nil
+	}); err != nil {
+		return err
+	}
+	return shared.NewExitError(queueCancelledExitCode, "")
+}
+
+// waitForBuildNumber polls the queue item until it becomes a build. Each new
+// blocker reason is printed to errOut so users see why the run has not
+// started; a nil errOut (--quiet) prints nothing. When the controller is
+// quieting down it warns and either returns exit code 14 or, with
+// WaitThroughQuietDown, keeps waiting until the timeout, which then also
+// exits 14.
+func waitForBuildNumber(client shared.Doer, queueLocation string, timeout time.Duration, errOut io.Writer, opts followOptions) (int64, error) {
 	if queueLocation == "" {
 		return 0, errors.New("follow requested but queue location unavailable")
 	}
@@ -1298,33 +2156,91 @@ func waitForBuildNumber(client *jenkins.Client, queueLocation string, timeout ti
 	}
 
 	deadline := time.Now().Add(timeout)
+	lastWhy := ""
+	quiet := false
+	poller := opts.Retry.Poller()
 	for {
 		var status queueItemStatus
-		_, err := client.Do(client.NewRequest(), http.MethodGet, queueAPI, &status)
+		resp, err := client.Do(jenkins.Conditional(client.NewRequest()), http.MethodGet, queueAPI, &status)
+		wait, err := poller.Observe(resp, err, "queue item")
 		if err != nil {
 			return 0, err
 		}
+		if wait > 0 {
+			if time.Now().After(deadline) {
+				return 0, queueStartTimeout(lastWhy, quiet)
+			}
+			time.Sleep(min(wait, time.Until(deadline)))
+			continue
+		}
 
 		if status.Cancelled {
-			if status.Why != "" {
-				return 0, fmt.Errorf("queue item cancelled: %s", status.Why)
-			}
-			return 0, errors.New("queue item cancelled")
+			return 0, &queueCancelledError{QueueID: status.ID, Why: strings.TrimSpace(status.Why)}
 		}
 
 		if status.Executable != nil && status.Executable.Number > 0 {
 			return status.Executable.Number, nil
 		}
 
+		if why := strings.TrimSpace(status.Why); why != "" && why != lastWhy {
+			if errOut != nil {
+				_, _ = fmt.Fprintf(errOut, "Waiting in queue: %s\n", why)
+			}
+			lastWhy = why
+		}
+
+		if !quiet && isQuietingDown(client, status.Why) {
+			quiet = true
+			cmdutil.Fwarnf(errOut, "Jenkins is quieting down; queued runs will not start until an administrator cancels quiet-down")
+			if !opts.WaitThroughQuietDown {
+				return 0, shared.NewExitError(quietDownExitCode, "Jenkins is quieting down and the run is still queued; pass --wait-through-quiet-down to keep waiting")
+			}
+		}
+
 		if time.Now().After(deadline) {
-			return 0, errors.New("timed out waiting for run to start")
+			return 0, queueStartTimeout(lastWhy, quiet)
 		}
 
-		time.Sleep(1 * time.Second)
+		time.Sleep(queuePollInterval)
 	}
 }
 
-func monitorRun(cmd *cobra.Command, client *jenkins.Client, jobPath string, buildNumber int64, interval time.Duration, streamLogs bool) (string, error) {
+// queueStartTimeout is the error of a run that did not leave the queue in
+// time. Waiting through quiet-down ends with the quiet-down exit code, since
+// that is what held the run back.
+func queueStartTimeout(lastWhy string, quietingDown bool) error {
+	if quietingDown {
+		return shared.NewExitError(quietDownExitCode, "timed out waiting for run to start: Jenkins is still quieting down")
+	}
+	if lastWhy != "" {
+		return fmt.Errorf("timed out waiting for run to start: %s", lastWhy)
+	}
+	return errors.New("timed out waiting for run to start")
+}
+
+// isQuietingDown reports whether a queue item is held back by quiet-down. The
+// why text has to match, and the controller's quietingDown flag confirms it
+// when readable so a blocker that merely mentions shutting down is not
+// misreported.
+func isQuietingDown(client shared.Doer, why string) bool {
+	if !strings.Contains(strings.ToLower(why), quietDownWhy) {
+		return false
+	}
+	var payload struct {
+		QuietingDown *bool `json:"quietingDown"`
+	}
+	resp, err := client.Do(client.NewRequest().SetQueryParam("tree", "quietingDown"), http.MethodGet, "/api/json", &payload)
+	if err != nil || resp.StatusCode() != http.StatusOK || payload.QuietingDown == nil {
+		return true
+	}
+	return *payload.QuietingDown
+}
+
+// runPollInterval spaces out run status requests while following; tests
+// shorten it.
+var runPollInterval = 2 * time.Second
+
+func monitorRun(cmd *cobra.Command, client shared.Doer, jobPath string, buildNumber int64, opts followOptions, streamLogs bool) (string, error) {
 	ctx := cmd.Context()
 	if ctx == nil {
 		ctx = context.Background()
@@ -1339,17 +2255,36 @@ func monitorRun(cmd *cobra.Command, client *jenkins.Client, jobPath string, buil
 		logCtx, cancel = context.WithCancel(ctx)
 		defer cancel()
 		logErrCh = make(chan error, 1)
+		// The status polls below report connection trouble; the log
+		// stream retries the same way without repeating the notes.
+		logRetry := opts.Retry
+		logRetry.Notes = nil
 		go func() {
-			err := shared.StreamProgressiveLog(logCtx, client, jobPath, int(buildNumber), interval, cmd.OutOrStdout())
+			err := shared.StreamProgressiveLogFrom(logCtx, client, jobPath, int(buildNumber), opts.LogOffset, opts.Interval, cmd.OutOrStdout(), logRetry.Poller())
 			logErrCh <- err
 		}()
 	}
 
-	statusPath := fmt.Sprintf("/%s/%d/api/json", jenkins.EncodeJobPath(jobPath), buildNumber)
+	progress := opts.Progress
+	if progress == nil {
+		progress = cmdutil.NoopProgress()
+	}
+	defer progress.Done()
+
+	statusPath := fmt.Sprintf("/%s/%d/api/json", jobpath.Encode(jobPath), buildNumber)
 	lastStatus := time.Time{}
+	stage := ""
+	gate := newStageGatePoller(opts.StageGate)
+	poller := opts.Retry.Poller()
+	subject := fmt.Sprintf("run %s #%d", jobPath, buildNumber)
 	for {
 		var detail runDetail
-		_, err := client.Do(client.NewRequest(), http.MethodGet, statusPath, &detail)
+		resp, err := client.Do(jenkins.Conditional(client.NewRequest().SetContext(ctx)), http.MethodGet, statusPath, &detail)
+		wait, err := poller.Observe(resp, err, subject)
+		if err == nil && wait > 0 {
+			time.Sleep(wait)
+			continue
+		}
 		if err != nil {
 			if cancel != nil {
 				cancel()
@@ -1361,6 +2296,7 @@ func monitorRun(cmd *cobra.Command, client *jenkins.Client, jobPath string, buil
 		}
 
 		if !detail.Building {
+			progress.Done()
 			if cancel != nil {
 				cancel()
 			}
@@ -1379,11 +2315,59 @@ func monitorRun(cmd *cobra.Command, client *jenkins.Client, jobPath string, buil
 			return result, nil
 		}
 
-		if streamLogs && time.Since(lastStatus) >= 5*time.Second {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Run #%d still running...\n", detail.Number)
+		if stop := gate.poll(ctx, client, jobPath, buildNumber, cmdutil.WarningWriter(cmd)); stop != nil {
+			progress.Done()
+			if cancel != nil {
+				cancel()
+			}
+			if logErrCh != nil {
+				if err := <-logErrCh; err != nil && !errors.Is(err, context.Canceled) {
+					return "", err
+				}
+			}
+			reportStageStop(ctx, client, jobPath, buildNumber, stop, cmd.ErrOrStderr())
+			return stop.Result, nil
+		}
+
+		runProgress := computeRunProgress(detail, time.Now())
+		if time.Since(lastStatus) >= 5*time.Second {
+			if opts.ShowStage && (streamLogs || opts.Progress != nil) {
+				if name, err := fetchCurrentStage(ctx, client, jobPath, buildNumber); err == nil {
+					stage = name
+				} else {
+					jklog.L().Debug().Err(err).Msg("fetch current stage failed")
+				}
+			}
+			if streamLogs {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Run #%d still running... %s\n", detail.Number, formatProgressLine(runProgress, stage))
+			}
 			lastStatus = time.Now()
 		}
-		time.Sleep(2 * time.Second)
+		event := cmdutil.ProgressEvent{
+			Event:     cmdutil.EventRunHeartbeat,
+			JobPath:   jobPath,
+			Build:     buildNumber,
+			ElapsedMs: runProgress.Elapsed.Milliseconds(),
+			Stage:     stage,
+		}
+		if runProgress.Percent >= 0 {
+			percent := runProgress.Percent
+			event.Percent = &percent
+		}
+		progress.Report(event)
+		time.Sleep(runPollInterval)
+	}
+}
+
+// followExitCodes documents the result-based exit codes used with --follow.
+func followExitCodes() map[int]string {
+	return map[int]string{
+		10: "Run finished UNSTABLE (--follow)",
+		11: "Run finished FAILURE (--follow)",
+		12: "Run finished ABORTED (--follow)",
+		13: "Run finished NOT_BUILT (--follow)",
+		14: "Jenkins is quieting down and the run never left the queue (--follow)",
+		15: "Run was cancelled while queued and never started (--follow)",
 	}
 }
 
@@ -1405,7 +2389,13 @@ func exitCodeForResult(result string) int {
 }
 
 // resolveJobPath attempts to resolve a job path, with optional fuzzy matching and auto-search on 404
-func resolveJobPath(cmd *cobra.Command, client *jenkins.Client, jobPath string, fuzzy, interactive bool) (string, error) {
+func resolveJobPath(cmd *cobra.Command, ios *iostreams.IOStreams, client *jenkins.Client, jobPath string, fuzzy, interactive bool) (string, error) {
+	// Apply the context default folder before anything else
+	jobPath, err := shared.ResolveJobPath(cmd, client, jobPath)
+	if err != nil {
+		return "", err
+	}
+
 	// First, try exact match
 	exists, err := jobExists(client, jobPath)
 	if err != nil {
@@ -1421,14 +2411,22 @@ func resolveJobPath(cmd *cobra.Command, client *jenkins.Client, jobPath string,
 		ctx = context.Background()
 	}
 
-	// Discover all jobs
-	allJobs, err := discoverJobs(ctx, client, "", "", maxJobDiscoveryDepth)
-	if err != nil {
-		return "", fmt.Errorf("failed to search for similar jobs: %w", err)
+	// Search the default folder first, then the whole instance
+	var fuzzyMatches []string
+	scopes := []string{""}
+	if folder := shared.DefaultFolder(client); folder != "" {
+		scopes = []string{folder, ""}
+	}
+	for _, scope := range scopes {
+		discovery, err := discoverJobs(ctx, client, scope, "", jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth})
+		if err != nil {
+			return "", fmt.Errorf("failed to search for similar jobs: %w", err)
+		}
+		fuzzyMatches = performFuzzySearch(jobPath, discovery.Jobs, 5)
+		if len(fuzzyMatches) > 0 {
+			break
+		}
 	}
-
-	// Perform fuzzy search
-	fuzzyMatches := performFuzzySearch(jobPath, allJobs, 5)
 
 	if len(fuzzyMatches) == 0 {
 		return "", formatJobNotFoundError(jobPath, nil)
@@ -1442,7 +2440,7 @@ func resolveJobPath(cmd *cobra.Command, client *jenkins.Client, jobPath string,
 
 	// Multiple matches - show suggestions or prompt for selection
 	if interactive && !shared.WantsJSON(cmd) && !shared.WantsYAML(cmd) {
-		selected, err := promptJobSelection(cmd, fuzzyMatches)
+		selected, err := promptJobSelection(cmd, ios, fuzzyMatches)
 		if err != nil {
 			return "", err
 		}
@@ -1456,8 +2454,8 @@ func resolveJobPath(cmd *cobra.Command, client *jenkins.Client, jobPath string,
 }
 
 // jobExists checks if a job exists (returns false on 404, error on other failures)
-func jobExists(client *jenkins.Client, jobPath string) (bool, error) {
-	path := fmt.Sprintf("/%s/api/json", jenkins.EncodeJobPath(jobPath))
+func jobExists(client shared.Doer, jobPath string) (bool, error) {
+	path := fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath))
 	resp, err := client.Do(
 		client.NewRequest().SetQueryParam("tree", "_class"),
 		http.MethodGet,
@@ -1472,7 +2470,7 @@ func jobExists(client *jenkins.Client, jobPath string) (bool, error) {
 		return false, nil
 	}
 	if resp.StatusCode() >= 400 {
-		return false, fmt.Errorf("check job existence: %s", resp.Status())
+		return false, fmt.Errorf("check job existence: %s", shared.ResponseStatus(resp))
 	}
 
 	return true, nil
@@ -1537,21 +2535,31 @@ func performFuzzySearch(query string, allJobs []string, maxResults int) []string
 }
 
 // promptJobSelection prompts the user to select from multiple job matches
-func promptJobSelection(cmd *cobra.Command, matches []string) (string, error) {
+func promptJobSelection(cmd *cobra.Command, ios *iostreams.IOStreams, matches []string) (string, error) {
 	if len(matches) == 0 {
 		return "", errors.New("no matches to select from")
 	}
 
+	fullPaths, _ := cmd.Flags().GetBool("full-paths")
+	fit := shared.StreamsPathFitter(ios, fullPaths)
+
 	w := cmd.OutOrStdout()
 	_, _ = fmt.Fprintln(w, "\nMultiple jobs found. Please select one:")
 	for i, match := range matches {
-		_, _ = fmt.Fprintf(w, "  [%d] %s\n", i+1, match)
+		prefix := fmt.Sprintf("  [%d] ", i+1)
+		_, _ = fmt.Fprintf(w, "%s%s\n", prefix, fit.Fit(match, len(prefix)))
 	}
 	_, _ = fmt.Fprintf(w, "  [0] Cancel\n\n")
-	_, _ = fmt.Fprintf(w, "Enter selection [0-%d]: ", len(matches))
 
-	var selection int
-	_, err := fmt.Fscanln(cmd.InOrStdin(), &selection)
+	answer, err := cmdutil.PromptOrFail(ios, cmdutil.Prompt{
+		Name:  "job selection",
+		Label: fmt.Sprintf("Enter selection [0-%d]", len(matches)),
+		Flag:  "an exact job path",
+	})
+	if err != nil {
+		return "", err
+	}
+	selection, err := strconv.Atoi(answer)
 	if err != nil {
 		return "", fmt.Errorf("read selection: %w", err)
 	}
@@ -0,0 +1,31 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/outputschema"
+)
+
+func TestOutputTimeFieldConventions(t *testing.T) {
+	outputs := []any{
+		runListOutput{},
+		runSearchOutput{},
+		runDetailOutput{},
+		runCausesOutput{},
+		runLastOutput{},
+		lastRunOutput{},
+		runFailuresOutput{},
+		runFieldsOutput{},
+		runParamsOutput{},
+		runTriggerOutput{},
+		runStatusOutput{},
+		runEnvOutput{},
+		runLinkOutput{},
+		queueCancelledOutput{},
+	}
+	for _, output := range outputs {
+		if violations := outputschema.TimeFieldViolations(output); len(violations) > 0 {
+			t.Errorf("%T does not follow the JSON time convention:\n%v", output, violations)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package run
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"path"
@@ -16,24 +17,62 @@ import (
 	"github.com/avivsinai/jenkins-cli/internal/jenkins"
 	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 const (
-	defaultSearchLimit   = 10
-	defaultSearchMaxScan = 500
-	maxJobDiscoveryDepth = 5
+	defaultSearchLimit    = 10
+	defaultSearchMaxScan  = 500
+	defaultDiscoveryDepth = 5
 )
 
+// jobDiscoveryOptions bounds the folder walk behind discoverJobs.
+type jobDiscoveryOptions struct {
+	MaxDepth int
+	// IncludeFolders, when set, limits results to jobs inside matching
+	// folders. ExcludeFolders prunes matching folders and wins over includes.
+	IncludeFolders []string
+	ExcludeFolders []string
+	// MaxJobs, when positive, stops the walk once that many jobs matched.
+	MaxJobs int
+	// FoldersOnly walks for the folders alone: branches of multibranch
+	// projects are not listed and folders the user cannot read are not
+	// entered.
+	FoldersOnly bool
+}
+
+// jobDiscovery is the result of a folder walk.
+type jobDiscovery struct {
+	Jobs []string
+	// Classes maps each job to its Jenkins class, empty when unknown.
+	Classes map[string]string
+	// FoldersPruned counts folders that were not entered because of
+	// IncludeFolders or ExcludeFolders.
+	FoldersPruned int
+	// Truncated is set when MaxJobs stopped the walk before every job was seen.
+	Truncated bool
+}
+
 type runSearchOptions struct {
 	Filters      []filter.Filter
 	RawFilters   []string
 	Since        *time.Time
+	Until        *time.Time
+	SinceBuild   int64
 	Limit        int
 	MaxScan      int
 	SelectFields []string
 	AllowRegex   bool
 	Folder       string
 	JobGlob      string
+	// Jobs is set when --job named the jobs to search. Those jobs may not
+	// exist; a missing one is reported in metadata instead of failing.
+	Jobs []string
+	// Discovery and Pruned are echoed into the metadata.
+	Discovery jobDiscoveryOptions
+	Pruned    int
+	// Progress receives one search.job event per scanned job; nil disables it.
+	Progress cmdutil.ProgressReporter
 }
 
 type jobListEntry struct {
@@ -49,12 +88,25 @@ func NewCmdRunSearch(f *cmdutil.Factory) *cobra.Command {
 	var (
 		folder      string
 		jobGlob     string
+		jobs        []string
 		filterArgs  []string
+		durations   durationFlags
 		sinceArg    string
+		untilArg    string
+		sinceBuild  int64
 		limit       int
 		maxScan     int
 		selectArg   string
 		enableRegex bool
+		listFields  bool
+		maxDepth    int
+		includes    []string
+		excludes    []string
+		logTail     int
+		fullPaths   bool
+		mine        bool
+		okOnPartial bool
+		assertion   countAssertion
 	)
 
 	cmd := &cobra.Command{
@@ -71,31 +123,58 @@ func NewCmdRunSearch(f *cmdutil.Factory) *cobra.Command {
   jk run search --job-glob "*/deploy-*" --filter param.ENVIRONMENT=production --since 7d
 
   # Find builds by user across all jobs
-  jk run search --filter cause.user~john --select parameters --limit 5`,
+  jk run search --filter cause.user~john --select parameters --limit 5
+
+  # Find your own failed builds
+  jk run search --mine --filter result=FAILURE --since 7d
+
+  # Search known jobs only, without crawling folders
+  jk run search --job team/api --job team/web --filter result=FAILURE`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if listFields {
+				return printRunFields(cmd)
+			}
+
 			client, err := shared.JenkinsClient(cmd, f)
 			if err != nil {
 				return err
 			}
 
-			parsedFilters, err := filter.Parse(filterArgs)
+			durationArgs, err := durations.filterArgs()
 			if err != nil {
 				return err
 			}
-
-			var since *time.Time
-			if strings.TrimSpace(sinceArg) != "" {
-				sinceValue, err := parseSince(sinceArg)
+			filterArgs = append(filterArgs, durationArgs...)
+			if mine {
+				mineArg, err := mineFilterArg(cmd.Context(), client)
 				if err != nil {
 					return err
 				}
-				since = &sinceValue
+				filterArgs = append(filterArgs, mineArg)
+			}
+			parsedFilters, err := filter.Parse(filterArgs)
+			if err != nil {
+				return err
+			}
+
+			since, until, err := parseTimeRange(sinceArg, untilArg, client.ServerNow())
+			if err != nil {
+				return err
+			}
+			if err := validateSinceBuild(cmd, sinceBuild); err != nil {
+				return err
+			}
+			if err := assertion.validate(cmd); err != nil {
+				return err
 			}
 
 			selectFields, err := parseSelectFields(selectArg)
 			if err != nil {
 				return err
 			}
+			if err := validateArtifactBytes(selectFields, ""); err != nil {
+				return err
+			}
 
 			if trimmed := strings.TrimSpace(jobGlob); trimmed != "" {
 				if _, err := doublestar.Match(trimmed, "test/job"); err != nil {
@@ -103,78 +182,188 @@ func NewCmdRunSearch(f *cmdutil.Factory) *cobra.Command {
 				}
 			}
 
-			if limit <= 0 {
-				limit = defaultSearchLimit
+			if maxDepth < 0 {
+				return shared.NewExitError(2, "--max-depth must not be negative")
 			}
-			if maxScan <= 0 {
-				maxScan = defaultSearchMaxScan
+			if err := validateLogTailLines(logTail); err != nil {
+				return err
+			}
+			for _, pattern := range append(append([]string{}, includes...), excludes...) {
+				if !doublestar.ValidatePattern(pattern) {
+					return shared.NewExitError(2, fmt.Sprintf("invalid folder glob %q", pattern))
+				}
 			}
 
-			normalizedFolder := normalizeJobPath(folder)
-			jobPaths, err := discoverJobs(cmd.Context(), client, normalizedFolder, jobGlob, maxJobDiscoveryDepth)
-			if err != nil {
-				return err
+			if len(jobs) > 0 {
+				for _, name := range []string{"folder", "job-glob", "include-folder", "exclude-folder"} {
+					if cmd.Flags().Changed(name) {
+						return shared.NewExitError(2, fmt.Sprintf("--job cannot be combined with --%s", name))
+					}
+				}
 			}
 
-			if len(jobPaths) == 0 {
-				empty := runSearchOutput{SchemaVersion: "1.0", Items: []runSearchItem{}, Metadata: &runSearchMetadata{Folder: normalizedFolder, JobGlob: jobGlob, Filters: append([]string{}, filterArgs...), Since: sinceString(since), JobsScanned: 0, MaxScan: maxScan, Selection: append([]string{}, selectFields...)}}
-				return shared.PrintOutput(cmd, empty, func() error {
-					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No matching runs found")
-					return nil
-				})
+			if limit <= 0 {
+				limit = defaultSearchLimit
+			}
+			if maxScan <= 0 {
+				maxScan = defaultSearchMaxScan
 			}
 
 			opts := runSearchOptions{
 				Filters:      parsedFilters,
 				RawFilters:   append([]string{}, filterArgs...),
 				Since:        since,
+				Until:        until,
+				SinceBuild:   sinceBuild,
 				Limit:        limit,
 				MaxScan:      maxScan,
 				SelectFields: selectFields,
 				AllowRegex:   enableRegex,
-				Folder:       normalizedFolder,
-				JobGlob:      jobGlob,
+				Progress:     f.Progress(),
+			}
+
+			var jobPaths []string
+			if len(jobs) > 0 {
+				jobPaths, err = resolveSearchJobs(cmd, client, jobs)
+				if err != nil {
+					return err
+				}
+				opts.Jobs = jobPaths
+			} else {
+				resolvedFolder, err := shared.ResolveFolder(cmd, client, folder)
+				if err != nil {
+					return err
+				}
+				opts.Folder = jobpath.Normalize(resolvedFolder)
+				opts.JobGlob = jobGlob
+				discovery, err := discoverJobs(cmd.Context(), client, opts.Folder, jobGlob, jobDiscoveryOptions{
+					MaxDepth:       maxDepth,
+					IncludeFolders: includes,
+					ExcludeFolders: excludes,
+				})
+				if err != nil {
+					return err
+				}
+				jobPaths = discovery.Jobs
+				opts.Discovery = jobDiscoveryOptions{IncludeFolders: includes, ExcludeFolders: excludes}
+				opts.Pruned = discovery.FoldersPruned
+			}
+
+			if len(jobPaths) == 0 {
+				empty := runSearchOutput{SchemaVersion: "1.0", Items: []runSearchItem{}, Result: shared.NewResult(), Metadata: &runSearchMetadata{Folder: opts.Folder, JobGlob: jobGlob, IncludeFolders: includes, ExcludeFolders: excludes, FoldersPruned: opts.Pruned, Filters: append([]string{}, filterArgs...), Since: sinceString(since), Until: sinceString(until), SinceBuild: sinceBuild, JobsScanned: 0, MaxScan: maxScan, Selection: append([]string{}, selectFields...)}}
+				empty.Metadata.Assertion = assertion.evaluate(0, false, "runs")
+				if err := shared.PrintOutput(cmd, empty, func() error {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No matching runs found")
+					return nil
+				}); err != nil {
+					return err
+				}
+				return assertionError(empty.Metadata.Assertion)
 			}
 
 			output, err := executeRunSearch(cmd.Context(), client, jobPaths, opts)
+			opts.Progress.Done()
 			if err != nil {
 				return err
 			}
+			output.WriteIssues(cmd)
+			output.Metadata.Assertion = assertion.evaluate(len(output.Items), output.more, "runs")
+			if logTail > 0 {
+				targets := make([]logTailTarget, 0, len(output.Items))
+				for i := range output.Items {
+					item := &output.Items[i]
+					targets = append(targets, logTailTarget{JobPath: item.JobPath, Number: item.Number, Result: item.Result, Tail: &item.LogTail})
+				}
+				attachLogTails(cmd.Context(), client, targets, logTail, shared.Concurrency(f))
+			}
 
-			return shared.PrintOutput(cmd, output, func() error {
-				return renderRunSearchHuman(cmd, output)
-			})
+			if err := shared.PrintOutput(cmd, output, func() error {
+				return renderRunSearchHuman(cmd, output, shared.NewPathFitter(f, fullPaths), shared.TimeFormatter(cmd, f), shared.ResultFormatter(cmd, f))
+			}); err != nil {
+				return err
+			}
+			if err := output.ExitError("jobs", okOnPartial); err != nil {
+				return err
+			}
+			return assertionError(output.Metadata.Assertion)
 		},
 	}
 
-	cmd.Flags().StringVar(&folder, "folder", "", "Folder path to search in")
+	cmd.Flags().StringVar(&folder, "folder", "", "Folder path to search in (defaults to the context default folder; pass / for the root)")
 	cmd.Flags().StringVar(&jobGlob, "job-glob", "", "Job glob pattern (e.g., \"*/deploy-*\")")
+	cmd.Flags().StringArrayVar(&jobs, "job", nil, "Search exactly this job, skipping folder discovery (repeatable)")
+	cmdutil.SetJobPathFlag(cmd, "folder")
+	cmdutil.SetJobPathFlag(cmd, "job")
 	cmd.Flags().StringSliceVar(&filterArgs, "filter", nil, "Filter runs (repeatable): key[op]value")
+	addDurationFlags(cmd, &durations)
+	addMineFlag(cmd, &mine)
 	cmd.Flags().StringVar(&sinceArg, "since", "", "Only search runs since timestamp or duration (RFC3339, 72h, 7d)")
+	cmd.Flags().StringVar(&untilArg, "until", "", "Only search runs started before timestamp or duration ago (RFC3339, 72h, 7d)")
+	cmd.Flags().Int64Var(&sinceBuild, "since-build", 0, "Only search builds numbered above N in each job (combines with --since)")
 	cmd.Flags().IntVar(&limit, "limit", defaultSearchLimit, "Max results to return")
 	cmd.Flags().IntVar(&maxScan, "max-scan", defaultSearchMaxScan, "Max builds to scan per job")
 	cmd.Flags().StringVar(&selectArg, "select", "", "Select additional fields (comma-separated)")
 	cmd.Flags().BoolVar(&enableRegex, "regex", false, "Enable regular expression matching for filters")
+	cmd.Flags().IntVar(&logTail, "with-log-tail", 0, fmt.Sprintf("Include the last N console lines of FAILURE and UNSTABLE runs (max %d)", maxLogTailLines))
+	shared.AddFullPathsFlag(cmd, &fullPaths)
+	cmd.Flags().IntVar(&maxDepth, "max-depth", defaultDiscoveryDepth, "How many folder levels below --folder to search")
+	cmd.Flags().StringArrayVar(&includes, "include-folder", nil, "Only search jobs inside folders matching this glob (repeatable)")
+	cmd.Flags().StringArrayVar(&excludes, "exclude-folder", nil, "Skip folders matching this glob (repeatable; wins over --include-folder)")
+	addListFieldsFlag(cmd, &listFields)
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+	addCountAssertionFlags(cmd, &assertion)
+	cmdutil.SetExitCodes(cmd, map[int]string{assertionExitCode: "A --fail-if-none or --expect-* assertion failed"})
+
+	cmdutil.SetFlagEnum(cmd, "select", availableSelectFields()...)
+	cmdutil.SetFilterSupport(cmd, filter.AllowedKeys(), filter.Operators())
 
 	return cmd
 }
 
-func executeRunSearch(ctx context.Context, client *jenkins.Client, jobPaths []string, opts runSearchOptions) (runSearchOutput, error) {
+func executeRunSearch(ctx context.Context, client shared.Doer, jobPaths []string, opts runSearchOptions) (runSearchOutput, error) {
 	items := make([]runSearchItem, 0, opts.Limit)
-	for _, jobPath := range jobPaths {
+	jobsWithRuns := 0
+	var notFound []string
+	result := shared.NewResult()
+	for i, jobPath := range jobPaths {
 		if ctx != nil && ctx.Err() != nil {
 			return runSearchOutput{}, ctx.Err()
 		}
+		if opts.Progress != nil {
+			opts.Progress.Report(cmdutil.ProgressEvent{Event: cmdutil.EventSearchJob, JobPath: jobPath, Index: i + 1, Total: int64(len(jobPaths))})
+		}
 
 		listOpts := runListOptions{
 			Limit:        opts.MaxScan,
 			Filters:      opts.Filters,
 			Since:        opts.Since,
+			Until:        opts.Until,
+			SinceBuild:   opts.SinceBuild,
 			SelectFields: opts.SelectFields,
 			AllowRegex:   opts.AllowRegex,
 		}
 
-		out, err := executeRunList(ctx, client, jobPath, listOpts)
+		listOpts, reqs, builds, err := fetchRunSummaries(ctx, client, jobPath, listOpts)
+		if err != nil {
+			if ctx != nil && ctx.Err() != nil {
+				return runSearchOutput{}, ctx.Err()
+			}
+			var exitErr *cmdutil.ExitError
+			if len(opts.Jobs) > 0 && errors.As(err, &exitErr) && exitErr.Code == 3 {
+				notFound = append(notFound, jobPath)
+				result.Skip(jobPath, 3, fmt.Sprintf("job %s not found", jobPath))
+				continue
+			}
+			result.Fail(jobPath, err)
+			continue
+		}
+		result.Succeed()
+		if len(builds) == 0 {
+			continue
+		}
+		jobsWithRuns++
+
+		out, _, err := processRunList(jobPath, listOpts, builds, reqs)
 		if err != nil {
 			return runSearchOutput{}, err
 		}
@@ -185,40 +374,221 @@ func executeRunSearch(ctx context.Context, client *jenkins.Client, jobPaths []st
 	}
 
 	sortSearchItems(items)
-	if opts.Limit > 0 && len(items) > opts.Limit {
+	more := opts.Limit > 0 && len(items) > opts.Limit
+	if more {
 		items = items[:opts.Limit]
 	}
 
 	metadata := &runSearchMetadata{
-		Folder:      opts.Folder,
-		JobGlob:     opts.JobGlob,
-		Filters:     append([]string{}, opts.RawFilters...),
-		Since:       sinceString(opts.Since),
-		JobsScanned: len(jobPaths),
-		MaxScan:     opts.MaxScan,
-		Selection:   append([]string{}, opts.SelectFields...),
+		Folder:         opts.Folder,
+		JobGlob:        opts.JobGlob,
+		Jobs:           opts.Jobs,
+		JobsNotFound:   notFound,
+		IncludeFolders: opts.Discovery.IncludeFolders,
+		ExcludeFolders: opts.Discovery.ExcludeFolders,
+		FoldersPruned:  opts.Pruned,
+		Filters:        append([]string{}, opts.RawFilters...),
+		Since:          sinceString(opts.Since),
+		Until:          sinceString(opts.Until),
+		SinceBuild:     opts.SinceBuild,
+		JobsScanned:    result.Summary.Succeeded,
+		JobsWithRuns:   jobsWithRuns,
+		MaxScan:        opts.MaxScan,
+		Selection:      append([]string{}, opts.SelectFields...),
+	}
+
+	return runSearchOutput{SchemaVersion: "1.0", Items: items, Metadata: metadata, Result: result, more: more}, nil
+}
+
+// resolveSearchJobs resolves --job values like job path arguments, dropping
+// duplicates.
+func resolveSearchJobs(cmd *cobra.Command, client *jenkins.Client, jobs []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(jobs))
+	resolved := make([]string, 0, len(jobs))
+	for _, raw := range jobs {
+		if jobpath.Normalize(raw) == "" {
+			return nil, shared.NewExitError(2, "--job requires a job path")
+		}
+		jobPath, err := shared.ResolveJobPath(cmd, client, raw)
+		if err != nil {
+			return nil, err
+		}
+		jobPath = jobpath.Normalize(jobPath)
+		if _, dup := seen[jobPath]; dup {
+			continue
+		}
+		seen[jobPath] = struct{}{}
+		resolved = append(resolved, jobPath)
 	}
+	return resolved, nil
+}
+
+func discoverJobs(ctx context.Context, client shared.Doer, folderPath, jobGlob string, opts jobDiscoveryOptions) (jobDiscovery, error) {
+	return walkJobTree(ctx, client, folderPath, jobGlob, opts, nil)
+}
+
+// DiscoverFolders returns every folder (including multibranch projects) below
+// root, sorted, using the same traversal and depth limit as job discovery.
+// Folders that cannot be read are returned but not entered.
+func DiscoverFolders(ctx context.Context, client shared.Doer, root string) ([]string, error) {
+	var folders []string
+	if _, err := walkJobTree(ctx, client, jobpath.Normalize(root), "", jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth, FoldersOnly: true}, func(path string) {
+		folders = append(folders, path)
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(folders)
+	return folders, nil
+}
+
+// DiscoverJobs returns every job below root, sorted, with the traversal and
+// depth limit of run search.
+func DiscoverJobs(ctx context.Context, client shared.Doer, root string) ([]string, error) {
+	discovery, err := discoverJobs(ctx, client, jobpath.Normalize(root), "", jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(discovery.Jobs)
+	return discovery.Jobs, nil
+}
+
+// ListJobs returns the jobs directly in folder, sorted, without entering
+// subfolders. Branches of multibranch projects in folder count as its jobs.
+func ListJobs(ctx context.Context, client shared.Doer, folder string) ([]string, error) {
+	discovery, err := discoverJobs(ctx, client, jobpath.Normalize(folder), "", jobDiscoveryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return discovery.Jobs, nil
+}
 
-	return runSearchOutput{SchemaVersion: "1.0", Items: items, Metadata: metadata}, nil
+// JobQueryOptions selects the jobs FindJobs and FilterJobs return.
+type JobQueryOptions struct {
+	Folder  string
+	JobGlob string
+	// MaxDepth and the folder globs bound the walk of FindJobs; FilterJobs
+	// ignores them.
+	MaxDepth       int
+	IncludeFolders []string
+	ExcludeFolders []string
+	// MaxJobs, when positive, caps the number of jobs returned.
+	MaxJobs int
 }
 
-func discoverJobs(ctx context.Context, client *jenkins.Client, folderPath, jobGlob string, maxDepth int) ([]string, error) {
-	visited := make(map[string]struct{})
+// FoundJob is a job selected by FindJobs or FilterJobs. Class is empty when
+// it is not known.
+type FoundJob struct {
+	Path  string
+	Class string
+}
+
+// FoundJobs is the result of FindJobs and FilterJobs, sorted by path.
+type FoundJobs struct {
+	Jobs          []FoundJob
+	FoldersPruned int
+	// Truncated is set when more jobs matched than MaxJobs allows.
+	Truncated bool
+}
+
+// FindJobs walks the folders below opts.Folder like run search and returns
+// the jobs matching opts.JobGlob. The walk stops once MaxJobs jobs matched,
+// so a bound keeps large controllers cheap.
+func FindJobs(ctx context.Context, client shared.Doer, opts JobQueryOptions) (FoundJobs, error) {
+	discovery, err := discoverJobs(ctx, client, jobpath.Normalize(opts.Folder), opts.JobGlob, jobDiscoveryOptions{
+		MaxDepth:       opts.MaxDepth,
+		IncludeFolders: opts.IncludeFolders,
+		ExcludeFolders: opts.ExcludeFolders,
+		MaxJobs:        opts.MaxJobs,
+	})
+	if err != nil {
+		return FoundJobs{}, err
+	}
+	found := FoundJobs{Jobs: make([]FoundJob, 0, len(discovery.Jobs)), FoldersPruned: discovery.FoldersPruned, Truncated: discovery.Truncated}
+	for _, path := range discovery.Jobs {
+		found.Jobs = append(found.Jobs, FoundJob{Path: path, Class: discovery.Classes[path]})
+	}
+	return found, nil
+}
+
+// FilterJobs applies the folder, glob and MaxJobs selection of FindJobs to
+// known job paths, such as a job index, without contacting the controller.
+func FilterJobs(paths []string, opts JobQueryOptions) FoundJobs {
+	folder := jobpath.Normalize(opts.Folder)
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	found := FoundJobs{Jobs: make([]FoundJob, 0)}
+	for _, path := range sorted {
+		if folder != "" && !strings.HasPrefix(path, folder+"/") {
+			continue
+		}
+		if !matchJobGlob(opts.JobGlob, folder, path) {
+			continue
+		}
+		if opts.MaxJobs > 0 && len(found.Jobs) >= opts.MaxJobs {
+			found.Truncated = true
+			break
+		}
+		found.Jobs = append(found.Jobs, FoundJob{Path: path})
+	}
+	return found
+}
+
+// walkJobTree collects jobs matching jobGlob below folderPath. onFolder, when
+// set, is called for every folder and multibranch project that is entered.
+// Folder include/exclude globs are checked before recursing, so pruned
+// subtrees cost no requests.
+func walkJobTree(ctx context.Context, client shared.Doer, folderPath, jobGlob string, opts jobDiscoveryOptions, onFolder func(string)) (jobDiscovery, error) {
+	visited := make(map[string]string)
 	results := make([]string, 0)
+	pruned := 0
+	truncated := false
+
+	// add records a matching job once, until MaxJobs is reached.
+	add := func(jobPath, class string) {
+		if _, ok := visited[jobPath]; ok {
+			return
+		}
+		if opts.MaxJobs > 0 && len(results) >= opts.MaxJobs {
+			truncated = true
+			return
+		}
+		visited[jobPath] = class
+		results = append(results, jobPath)
+	}
 
-	var walk func(path string, depth int) error
+	var walk func(path string, depth int, included bool) error
 
-	walk = func(current string, depth int) error {
+	// enter decides whether to descend into a child folder and whether jobs
+	// below it count as included.
+	enter := func(childPath string, parentIncluded bool) (bool, bool) {
+		if matchesAnyFolderGlob(opts.ExcludeFolders, folderPath, childPath) {
+			pruned++
+			return false, false
+		}
+		if parentIncluded || matchesAnyFolderGlob(opts.IncludeFolders, folderPath, childPath) {
+			return true, true
+		}
+		for _, pattern := range opts.IncludeFolders {
+			if folderCouldContain(pattern, folderPath, childPath) {
+				return true, false
+			}
+		}
+		pruned++
+		return false, false
+	}
+
+	walk = func(current string, depth int, included bool) error {
 		if ctx != nil && ctx.Err() != nil {
 			return ctx.Err()
 		}
-		if depth > maxDepth {
+		if depth > opts.MaxDepth || truncated {
 			return nil
 		}
 
 		encoded := "/api/json"
 		if current != "" {
-			encoded = fmt.Sprintf("/%s/api/json", jenkins.EncodeJobPath(current))
+			encoded = fmt.Sprintf("/%s/api/json", jobpath.Encode(current))
 		}
 
 		var payload jobListPayload
@@ -229,34 +599,47 @@ func discoverJobs(ctx context.Context, client *jenkins.Client, folderPath, jobGl
 
 		status := resp.StatusCode()
 		if status == http.StatusNotFound && current != "" {
-			if matchJobGlob(jobGlob, folderPath, current) {
-				if _, ok := visited[current]; !ok {
-					visited[current] = struct{}{}
-					results = append(results, current)
-				}
+			if included && matchJobGlob(jobGlob, folderPath, current) {
+				add(current, "")
 			}
 			return nil
 		}
+		if opts.FoldersOnly && current != "" && (status == http.StatusForbidden || status == http.StatusUnauthorized) {
+			return nil
+		}
 		if status >= 400 {
-			return fmt.Errorf("list jobs for %s: %s", current, resp.Status())
+			return fmt.Errorf("list jobs for %s: %s", current, shared.ResponseStatus(resp))
 		}
 
 		for _, job := range payload.Jobs {
-			childPath := joinJobPath(current, job.Name)
+			if truncated {
+				return nil
+			}
+			childPath := jobpath.Join(current, job.Name)
 
 			// Check if this job matches the glob BEFORE deciding how to handle it
 			matches := matchJobGlob(jobGlob, folderPath, childPath)
 
 			// Handle multibranch projects specially
 			if isMultibranchClass(job.Class) {
-				if matches {
+				ok, childIncluded := enter(childPath, included)
+				if !ok {
+					continue
+				}
+				if onFolder != nil {
+					onFolder(childPath)
+				}
+				if opts.FoldersOnly {
+					continue
+				}
+				if matches && childIncluded {
 					// Matched multibranch: add ALL its branches (don't filter children)
-					if err := walkAndAddAllBranches(ctx, client, childPath, &results, visited); err != nil {
+					if err := walkAndAddAllBranches(ctx, client, childPath, add); err != nil {
 						return err
 					}
 				} else {
 					// Multibranch didn't match: recurse normally (children might match)
-					if err := walk(childPath, depth+1); err != nil {
+					if err := walk(childPath, depth+1, childIncluded); err != nil {
 						return err
 					}
 				}
@@ -264,43 +647,91 @@ func discoverJobs(ctx context.Context, client *jenkins.Client, folderPath, jobGl
 			}
 
 			// Handle regular folders: recurse into them
-			if isFolderClass(job.Class) {
-				if err := walk(childPath, depth+1); err != nil {
+			if shared.IsFolderClass(job.Class) {
+				ok, childIncluded := enter(childPath, included)
+				if !ok {
+					continue
+				}
+				if onFolder != nil {
+					onFolder(childPath)
+				}
+				if err := walk(childPath, depth+1, childIncluded); err != nil {
 					return err
 				}
 				continue
 			}
 
 			// Regular job: add if it matches
-			if matches {
-				if _, ok := visited[childPath]; !ok {
-					visited[childPath] = struct{}{}
-					results = append(results, childPath)
-				}
+			if matches && included {
+				add(childPath, job.Class)
 			}
 		}
 
 		return nil
 	}
 
-	if err := walk(folderPath, 0); err != nil {
-		return nil, err
+	rootIncluded := len(opts.IncludeFolders) == 0 || matchesAnyFolderGlob(opts.IncludeFolders, "", folderPath)
+	if err := walk(folderPath, 0, rootIncluded); err != nil {
+		return jobDiscovery{}, err
 	}
 
 	sort.Strings(results)
-	return results, nil
+	return jobDiscovery{Jobs: results, Classes: visited, FoldersPruned: pruned, Truncated: truncated}, nil
+}
+
+// matchesAnyFolderGlob applies matchJobGlob's rules (full path, base name,
+// path component, or path relative to root) to a folder path.
+func matchesAnyFolderGlob(patterns []string, root, folderPath string) bool {
+	if folderPath == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if matchJobGlob(pattern, root, folderPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// folderCouldContain reports whether a folder matching pattern may exist
+// below folderPath. Patterns without a slash match a folder name at any depth;
+// path patterns are compared segment by segment against the full and the
+// root-relative path until a ** segment.
+func folderCouldContain(pattern, root, folderPath string) bool {
+	if !strings.Contains(pattern, "/") {
+		return true
+	}
+	candidates := []string{folderPath}
+	if root != "" && strings.HasPrefix(folderPath, root+"/") {
+		candidates = append(candidates, strings.TrimPrefix(folderPath, root+"/"))
+	}
+	patternSegs := strings.Split(pattern, "/")
+	for _, candidate := range candidates {
+		if segmentPrefixMatch(patternSegs, strings.Split(candidate, "/")) {
+			return true
+		}
+	}
+	return false
 }
 
-func joinJobPath(parent, child string) string {
-	if parent == "" {
-		return child
+func segmentPrefixMatch(patternSegs, pathSegs []string) bool {
+	for i, seg := range pathSegs {
+		if i >= len(patternSegs) {
+			return false
+		}
+		if patternSegs[i] == "**" {
+			return true
+		}
+		if ok, err := doublestar.Match(patternSegs[i], seg); err != nil || !ok {
+			return false
+		}
 	}
-	return fmt.Sprintf("%s/%s", parent, child)
+	return len(patternSegs) > len(pathSegs)
 }
 
-func walkAndAddAllBranches(ctx context.Context, client *jenkins.Client, multibranchPath string, results *[]string, visited map[string]struct{}) error {
+func walkAndAddAllBranches(ctx context.Context, client shared.Doer, multibranchPath string, add func(jobPath, class string)) error {
 	// Fetch branches of matched multibranch project
-	encoded := fmt.Sprintf("/%s/api/json", jenkins.EncodeJobPath(multibranchPath))
+	encoded := fmt.Sprintf("/%s/api/json", jobpath.Encode(multibranchPath))
 	tree := "jobs[name,_class]"
 
 	var payload jobListPayload
@@ -318,18 +749,15 @@ func walkAndAddAllBranches(ctx context.Context, client *jenkins.Client, multibra
 
 	// Propagate HTTP errors (permission denied, server errors, etc.)
 	if resp.StatusCode() >= 400 {
-		return fmt.Errorf("list branches for %s: %s", multibranchPath, resp.Status())
+		return fmt.Errorf("list branches for %s: %s", multibranchPath, shared.ResponseStatus(resp))
 	}
 
 	// Add all branches without glob filtering (user matched parent project)
 	for _, branch := range payload.Jobs {
-		branchPath := joinJobPath(multibranchPath, branch.Name)
+		branchPath := jobpath.Join(multibranchPath, branch.Name)
 		// Only add actual branches (not nested folders)
-		if !isFolderClass(branch.Class) && !isMultibranchClass(branch.Class) {
-			if _, ok := visited[branchPath]; !ok {
-				visited[branchPath] = struct{}{}
-				*results = append(*results, branchPath)
-			}
+		if !shared.IsFolderClass(branch.Class) {
+			add(branchPath, branch.Class)
 		}
 	}
 
@@ -340,12 +768,6 @@ func isMultibranchClass(className string) bool {
 	return strings.Contains(strings.ToLower(className), "multibranch")
 }
 
-func isFolderClass(className string) bool {
-	className = strings.ToLower(className)
-	// Check for folder-like classes, but exclude multibranch (handled separately)
-	return strings.Contains(className, "folder") && !strings.Contains(className, "multibranch")
-}
-
 func matchJobGlob(glob, folder, jobPath string) bool {
 	if glob == "" {
 		return true
@@ -412,10 +834,10 @@ func sinceString(t *time.Time) string {
 	if t == nil {
 		return ""
 	}
-	return t.UTC().Format(time.RFC3339)
+	return shared.FormatTime(*t)
 }
 
-func renderRunSearchHuman(cmd *cobra.Command, output runSearchOutput) error {
+func renderRunSearchHuman(cmd *cobra.Command, output runSearchOutput, fit shared.PathFitter, stamp func(string) string, label func(string) string) error {
 	w := cmd.OutOrStdout()
 	if len(output.Items) == 0 {
 		_, _ = fmt.Fprintln(w, "No matching runs found")
@@ -426,7 +848,10 @@ func renderRunSearchHuman(cmd *cobra.Command, output runSearchOutput) error {
 		if result == "" {
 			result = strings.ToUpper(strings.TrimSpace(item.Status))
 		}
-		_, _ = fmt.Fprintf(w, "%s\t#%d\t%s\t%s\t%s\n", item.JobPath, item.Number, result, item.StartTime, shared.DurationString(item.DurationMs))
+		fields := []string{fmt.Sprintf("#%d", item.Number), label(result), stamp(item.StartTime), shared.DurationPadded(item.DurationMs)}
+		jobPath := fit.Fit(item.JobPath, shared.RowWidth(append([]string{""}, fields...)...))
+		_, _ = fmt.Fprintln(w, strings.Join(append([]string{jobPath}, fields...), "\t"))
+		writeLogTail(w, item.LogTail)
 	}
 	return nil
 }
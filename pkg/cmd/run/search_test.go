@@ -1,8 +1,19 @@
 package run
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
 	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
 func TestMatchJobGlob(t *testing.T) {
@@ -71,30 +82,6 @@ func TestIsMultibranchClass(t *testing.T) {
 	}
 }
 
-func TestIsFolderClass(t *testing.T) {
-	cases := []struct {
-		className string
-		expect    bool
-		desc      string
-	}{
-		{"com.cloudbees.hudson.plugins.folder.Folder", true, "regular folder"},
-		{"com.cloudbees.hudson.plugins.folder.AbstractFolder", true, "abstract folder"},
-		{"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject", false, "multibranch excluded"},
-		{"jenkins.branch.MultiBranchProject", false, "multibranch project excluded"},
-		{"hudson.model.FreeStyleProject", false, "freestyle project"},
-		{"org.jenkinsci.plugins.workflow.job.WorkflowJob", false, "workflow job"},
-		{"FOLDER", true, "case insensitive folder"},
-	}
-
-	for _, tc := range cases {
-		got := isFolderClass(tc.className)
-		if got != tc.expect {
-			t.Errorf("%s: isFolderClass(%q) = %v, want %v",
-				tc.desc, tc.className, got, tc.expect)
-		}
-	}
-}
-
 func TestSortSearchItems(t *testing.T) {
 	items := []runSearchItem{
 		{JobPath: "b/job", Number: 1, StartTime: "2025-10-14T10:00:00Z"},
@@ -149,3 +136,252 @@ func TestPerformFuzzySearchEmptyQuery(t *testing.T) {
 		t.Fatalf("expected nil results for empty query, got %v", got)
 	}
 }
+
+func folderTreeClient(t *testing.T) *jenkins.Client {
+	t.Helper()
+	const folder = "com.cloudbees.hudson.plugins.folder.Folder"
+	const job = "hudson.model.FreeStyleProject"
+	return jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/api/json":                     `{"jobs":[{"name":"app","_class":"` + job + `"},{"name":"team","_class":"` + folder + `"},{"name":"archive","_class":"` + folder + `"}]}`,
+		"/job/team/api/json":            `{"jobs":[{"name":"svc","_class":"` + job + `"},{"name":"legacy","_class":"` + folder + `"}]}`,
+		"/job/team/job/legacy/api/json": `{"jobs":[{"name":"old","_class":"` + job + `"}]}`,
+		"/job/archive/api/json":         `{"jobs":[{"name":"x","_class":"` + job + `"}]}`,
+	}))
+}
+
+func TestDiscoverJobsFolderFilters(t *testing.T) {
+	client := folderTreeClient(t)
+
+	tests := []struct {
+		name       string
+		opts       jobDiscoveryOptions
+		wantJobs   []string
+		wantPruned int
+	}{
+		{
+			name:     "no filters",
+			opts:     jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth},
+			wantJobs: []string{"app", "archive/x", "team/legacy/old", "team/svc"},
+		},
+		{
+			name:       "exclude by name",
+			opts:       jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth, ExcludeFolders: []string{"archive"}},
+			wantJobs:   []string{"app", "team/legacy/old", "team/svc"},
+			wantPruned: 1,
+		},
+		{
+			name:       "exclude nested glob",
+			opts:       jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth, ExcludeFolders: []string{"**/legacy"}},
+			wantJobs:   []string{"app", "archive/x", "team/svc"},
+			wantPruned: 1,
+		},
+		{
+			name:     "include limits results",
+			opts:     jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth, IncludeFolders: []string{"team"}},
+			wantJobs: []string{"team/legacy/old", "team/svc"},
+		},
+		{
+			name:       "exclude wins over include",
+			opts:       jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth, IncludeFolders: []string{"team"}, ExcludeFolders: []string{"team/legacy"}},
+			wantJobs:   []string{"team/svc"},
+			wantPruned: 1,
+		},
+		{
+			name:       "include nested path",
+			opts:       jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth, IncludeFolders: []string{"team/legacy"}},
+			wantJobs:   []string{"team/legacy/old"},
+			wantPruned: 1,
+		},
+		{
+			name:       "include path glob prunes unrelated folders",
+			opts:       jobDiscoveryOptions{MaxDepth: defaultDiscoveryDepth, IncludeFolders: []string{"team/*"}},
+			wantJobs:   []string{"team/legacy/old"},
+			wantPruned: 1,
+		},
+		{
+			name:     "max depth zero",
+			opts:     jobDiscoveryOptions{MaxDepth: 0},
+			wantJobs: []string{"app"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := discoverJobs(context.Background(), client, "", "", tt.opts)
+			if err != nil {
+				t.Fatalf("discoverJobs: %v", err)
+			}
+			if !reflect.DeepEqual(got.Jobs, tt.wantJobs) {
+				t.Fatalf("jobs = %v, want %v", got.Jobs, tt.wantJobs)
+			}
+			if got.FoldersPruned != tt.wantPruned {
+				t.Fatalf("pruned = %d, want %d", got.FoldersPruned, tt.wantPruned)
+			}
+		})
+	}
+}
+
+func runSearchCmd(t *testing.T, client *jenkins.Client, args ...string) (*bytes.Buffer, *bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(append([]string{"search"}, args...))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return stdout, stderr, cmd.Execute()
+}
+
+func TestRunSearchJobsSkipDiscovery(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/api/api/json", http.StatusOK,
+		`{"builds":[{"number":7,"result":"FAILURE","timestamp":2000},{"number":6,"result":"SUCCESS","timestamp":1000}]}`)
+	server.Handle(http.MethodGet, "/job/team/job/web/api/json", http.StatusOK,
+		`{"builds":[{"number":3,"result":"FAILURE","timestamp":3000}]}`)
+
+	stdout, stderr, err := runSearchCmd(t, client,
+		"--job", "team/api", "--job", "team/missing", "--job", "team/web", "--job", "team/api", "--filter", "result=FAILURE")
+	if err != nil {
+		t.Fatalf("run search: %v", err)
+	}
+
+	var got runSearchOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if len(got.Items) != 2 || got.Items[0].JobPath != "team/web" || got.Items[1].JobPath != "team/api" || got.Items[1].Number != 7 {
+		t.Fatalf("unexpected items %+v", got.Items)
+	}
+	meta := got.Metadata
+	if !reflect.DeepEqual(meta.Jobs, []string{"team/api", "team/missing", "team/web"}) {
+		t.Fatalf("unexpected jobs %v", meta.Jobs)
+	}
+	if !reflect.DeepEqual(meta.JobsNotFound, []string{"team/missing"}) || meta.JobsScanned != 2 {
+		t.Fatalf("unexpected metadata %+v", meta)
+	}
+	if !strings.Contains(stderr.String(), "job team/missing not found") {
+		t.Fatalf("expected a warning, got %q", stderr.String())
+	}
+	if reqs := server.RequestsTo(http.MethodGet, "/api/json"); len(reqs) != 0 {
+		t.Fatalf("expected no discovery requests, got %d", len(reqs))
+	}
+}
+
+func TestRunSearchReportsFailingJobsAndContinues(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/api/api/json", http.StatusOK,
+		`{"builds":[{"number":7,"result":"FAILURE","timestamp":2000}]}`)
+	server.Handle(http.MethodGet, "/job/team/job/web/api/json", http.StatusInternalServerError, "boom")
+
+	stdout, _, err := runSearchCmd(t, client, "--job", "team/web", "--job", "team/api")
+	if code := exitCode(err); code != 1 || !strings.Contains(err.Error(), "1 of 2 jobs failed") {
+		t.Fatalf("expected partial failure exit 1, got %v", err)
+	}
+
+	var got runSearchOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if len(got.Items) != 1 || got.Items[0].JobPath != "team/api" {
+		t.Fatalf("expected team/api's run, got %+v", got.Items)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].Target != "team/web" || got.Errors[0].Code != 1 {
+		t.Fatalf("unexpected errors %+v", got.Errors)
+	}
+	if got.Summary.Succeeded != 1 || got.Summary.Failed != 1 {
+		t.Fatalf("unexpected summary %+v", got.Summary)
+	}
+
+	if _, _, err := runSearchCmd(t, client, "--job", "team/web", "--job", "team/api", "--ok-on-partial"); err != nil {
+		t.Fatalf("expected --ok-on-partial to exit 0, got %v", err)
+	}
+}
+
+func TestRunSearchJobsRejectDiscoveryFlags(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+	for _, flag := range []string{"--folder=team", "--job-glob=*api*", "--include-folder=team", "--exclude-folder=old"} {
+		_, _, err := runSearchCmd(t, client, "--job", "team/api", flag)
+		var exitErr *cmdutil.ExitError
+		if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+			t.Fatalf("%s: expected exit code 2, got %v", flag, err)
+		}
+	}
+}
+
+func TestRunSearchSinceBuild(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/api/api/json", http.StatusOK,
+		`{"builds":[{"number":7,"result":"FAILURE","timestamp":3000},{"number":6,"result":"FAILURE","timestamp":2000},{"number":5,"result":"FAILURE","timestamp":1000}]}`)
+
+	stdout, _, err := runSearchCmd(t, client, "--job", "team/api", "--since-build", "5")
+	if err != nil {
+		t.Fatalf("run search: %v", err)
+	}
+	var got runSearchOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if len(got.Items) != 2 || got.Items[1].Number != 6 || got.Metadata.SinceBuild != 5 {
+		t.Fatalf("unexpected output %+v %+v", got.Items, got.Metadata)
+	}
+
+	for _, value := range []string{"0", "-3"} {
+		_, _, err := runSearchCmd(t, client, "--job", "team/api", "--since-build", value)
+		var exitErr *cmdutil.ExitError
+		if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+			t.Fatalf("--since-build %s: expected exit code 2, got %v", value, err)
+		}
+	}
+}
+
+func TestRunSearchMine(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/whoAmI/api/json", map[string]any{"name": "alice", "anonymous": false, "authenticated": true})
+	server.Handle(http.MethodGet, "/job/team/job/api/api/json", http.StatusOK, `{"builds":[
+		{"number":9,"result":"FAILURE","timestamp":3000,"actions":[{"causes":[{"_class":"hudson.model.Cause$UserIdCause","userId":"bob","userName":"Bob"}]}]},
+		{"number":8,"result":"FAILURE","timestamp":2000,"actions":[{"causes":[{"_class":"hudson.model.Cause$UserIdCause","userId":"alice","userName":"Alice Smith"}]}]},
+		{"number":7,"result":"SUCCESS","timestamp":1000,"actions":[{"causes":[{"_class":"hudson.model.Cause$UserIdCause","userId":"alice","userName":"Alice Smith"}]}]}]}`)
+
+	stdout, _, err := runSearchCmd(t, client, "--job", "team/api", "--mine", "--filter", "result=FAILURE")
+	if err != nil {
+		t.Fatalf("run search: %v", err)
+	}
+	var got runSearchOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if len(got.Items) != 1 || got.Items[0].Number != 8 {
+		t.Fatalf("expected only run #8, got %+v", got.Items)
+	}
+	if !reflect.DeepEqual(got.Metadata.Filters, []string{"result=FAILURE", "cause.user=alice"}) {
+		t.Fatalf("expected the expanded filter in metadata, got %v", got.Metadata.Filters)
+	}
+	if req := server.LastRequest(http.MethodGet, "/job/team/job/api/api/json"); !strings.Contains(req.Query.Get("tree"), "causes[") {
+		t.Fatalf("expected causes in the tree, got %s", req.Query.Get("tree"))
+	}
+
+	if _, _, err := runSearchCmd(t, client, "--job", "team/api", "--mine"); err != nil {
+		t.Fatalf("second search: %v", err)
+	}
+	if reqs := server.RequestsTo(http.MethodGet, "/whoAmI/api/json"); len(reqs) != 1 {
+		t.Fatalf("expected the user to be resolved once per client, got %d requests", len(reqs))
+	}
+}
+
+func TestRunSearchMineAnonymous(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/whoAmI/api/json", map[string]any{"name": "anonymous", "anonymous": true, "authenticated": false})
+
+	_, _, err := runSearchCmd(t, client, "--job", "team/api", "--mine")
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 4 {
+		t.Fatalf("expected exit code 4, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "anonymous") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package run
+
+import (
+	"context"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+// RecentRun is a completed run picked by SelectRecentRuns.
+type RecentRun struct {
+	Number     int64
+	Result     string
+	StartTime  string
+	DurationMs int64
+}
+
+// SelectRecentRuns returns up to limit completed runs of jobPath matching
+// filters, newest first. It uses the same build window and filter evaluation
+// as `jk run ls`, so other commands can target "the last N failures" without
+// re-implementing run selection.
+func SelectRecentRuns(ctx context.Context, client shared.Doer, jobPath string, limit int, filters []filter.Filter) ([]RecentRun, error) {
+	completed, err := filter.Parse([]string{"status=completed"})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := runListOptions{
+		Limit:   limit,
+		Filters: append(append([]filter.Filter{}, filters...), completed...),
+	}
+	output, err := executeRunList(ctx, client, jobPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]RecentRun, 0, len(output.Items))
+	for _, item := range output.Items {
+		runs = append(runs, RecentRun{
+			Number:     item.Number,
+			Result:     item.Result,
+			StartTime:  item.StartTime,
+			DurationMs: item.DurationMs,
+		})
+	}
+	return runs, nil
+}
@@ -0,0 +1,150 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// stageFailureLogLines is how much console output is shown when
+// --fail-on-stage stops a followed run.
+const stageFailureLogLines = 30
+
+// stagePollInterval spaces out wfapi/describe requests while following a run
+// with --fail-on-stage or --until-stage; tests shorten it.
+var stagePollInterval = 10 * time.Second
+
+// stageGate ends a followed run early based on named Pipeline stages:
+// FailOn stops with the failure exit code as soon as one of them fails or is
+// aborted, Until stops with exit code 0 once one of them succeeds.
+type stageGate struct {
+	FailOn []string
+	Until  []string
+}
+
+// stageStop is why a stageGate ended a follow.
+type stageStop struct {
+	Stage  string
+	Status string
+	// Result is the run result the exit code is taken from.
+	Result string
+}
+
+func addStageGateFlags(cmd *cobra.Command, gate *stageGate) {
+	cmd.Flags().StringArrayVar(&gate.FailOn, "fail-on-stage", nil, "With --follow, stop and exit with the failure code as soon as this pipeline stage fails (repeatable)")
+	cmd.Flags().StringArrayVar(&gate.Until, "until-stage", nil, "With --follow, stop with exit code 0 once this pipeline stage succeeds (repeatable)")
+}
+
+// validate rejects empty stage names and gates without --follow, before
+// anything is triggered.
+func (g stageGate) validate(follow bool) error {
+	flags := []struct {
+		name   string
+		stages []string
+	}{{"--fail-on-stage", g.FailOn}, {"--until-stage", g.Until}}
+	for _, flag := range flags {
+		for _, stage := range flag.stages {
+			if strings.TrimSpace(stage) == "" {
+				return shared.NewExitError(2, flag.name+" requires a stage name")
+			}
+		}
+		if len(flag.stages) > 0 && !follow {
+			return shared.NewExitError(2, flag.name+" requires --follow")
+		}
+	}
+	return nil
+}
+
+func (g stageGate) active() bool {
+	return len(g.FailOn) > 0 || len(g.Until) > 0
+}
+
+// check returns the first stage, in pipeline order, that ends the follow.
+// Stage names match case-insensitively.
+func (g stageGate) check(stages []wfapiStage) *stageStop {
+	for _, stage := range stages {
+		status := strings.ToUpper(strings.TrimSpace(stage.Status))
+		switch {
+		case matchesStage(g.FailOn, stage.Name) && status == "FAILED":
+			return &stageStop{Stage: stage.Name, Status: status, Result: "FAILURE"}
+		case matchesStage(g.FailOn, stage.Name) && status == "ABORTED":
+			return &stageStop{Stage: stage.Name, Status: status, Result: "ABORTED"}
+		case matchesStage(g.Until, stage.Name) && status == "SUCCESS":
+			return &stageStop{Stage: stage.Name, Status: status, Result: "SUCCESS"}
+		}
+	}
+	return nil
+}
+
+func matchesStage(names []string, stage string) bool {
+	for _, name := range names {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(stage)) {
+			return true
+		}
+	}
+	return false
+}
+
+// stageGatePoller rate-limits stage checks for one followed run and turns
+// itself off when the controller has no Pipeline Stage View API, so the
+// follow degrades to waiting for the run to finish.
+type stageGatePoller struct {
+	gate     stageGate
+	disabled bool
+	lastPoll time.Time
+}
+
+func newStageGatePoller(gate stageGate) *stageGatePoller {
+	return &stageGatePoller{gate: gate, disabled: !gate.active()}
+}
+
+// poll checks the run's stages when a poll is due. Failed requests are
+// retried on the next poll.
+func (p *stageGatePoller) poll(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64, errOut io.Writer) *stageStop {
+	if p.disabled || (!p.lastPoll.IsZero() && time.Since(p.lastPoll) < stagePollInterval) {
+		return nil
+	}
+	p.lastPoll = time.Now()
+
+	stages, supported, err := fetchStageView(ctx, client, jobPath, buildNumber)
+	if err != nil {
+		jklog.L().Debug().Err(err).Msg("fetch stages for stage gate failed")
+		return nil
+	}
+	if !supported {
+		p.disabled = true
+		cmdutil.Fwarnf(errOut, "pipeline stage data is unavailable for this run; --fail-on-stage and --until-stage are ignored")
+		return nil
+	}
+	return p.gate.check(stages)
+}
+
+// reportStageStop tells the user why following ended early; a failed stage
+// comes with the end of the console log.
+func reportStageStop(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64, stop *stageStop, errOut io.Writer) {
+	if stop.Result == "SUCCESS" {
+		_, _ = fmt.Fprintf(errOut, "Stage %q succeeded; stopped following run #%d, which keeps running\n", stop.Stage, buildNumber)
+		return
+	}
+	_, _ = fmt.Fprintf(errOut, "Stage %q %s; stopped following run #%d\n", stop.Stage, stop.Status, buildNumber)
+	tail, err := fetchConsoleTail(ctx, client, jobPath, buildNumber, stageFailureLogLines)
+	if err != nil {
+		jklog.L().Debug().Err(err).Msg("fetch log tail for failed stage failed")
+		return
+	}
+	if len(tail) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(errOut, "Last %d log lines:\n", len(tail))
+	for _, line := range tail {
+		_, _ = fmt.Fprintf(errOut, "  %s\n", line)
+	}
+}
@@ -0,0 +1,156 @@
+package run
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestStageGateCheck(t *testing.T) {
+	gate := stageGate{FailOn: []string{"Deploy"}, Until: []string{"deploy", "Smoke"}}
+	tests := []struct {
+		name   string
+		stages []wfapiStage
+		expect string
+	}{
+		{"running", []wfapiStage{{Name: "Build", Status: "SUCCESS"}, {Name: "Deploy", Status: "IN_PROGRESS"}}, ""},
+		{"failed", []wfapiStage{{Name: "Build", Status: "SUCCESS"}, {Name: "Deploy", Status: "FAILED"}}, "Deploy FAILURE"},
+		{"aborted", []wfapiStage{{Name: "deploy", Status: "ABORTED"}}, "deploy ABORTED"},
+		{"succeeded", []wfapiStage{{Name: "Deploy", Status: "SUCCESS"}, {Name: "Soak", Status: "IN_PROGRESS"}}, "Deploy SUCCESS"},
+		{"unnamed stage failed", []wfapiStage{{Name: "Build", Status: "FAILED"}}, ""},
+		{"unstable is not a failure", []wfapiStage{{Name: "Deploy", Status: "UNSTABLE"}}, ""},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if stop := gate.check(tt.stages); stop != nil {
+			got = stop.Stage + " " + stop.Result
+		}
+		if got != tt.expect {
+			t.Fatalf("%s: expected %q got %q", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func followWithStageGate(t *testing.T, client *jenkins.Client, gate stageGate) (string, error) {
+	t.Helper()
+	useFastQueuePolling(t)
+	cmd := &cobra.Command{}
+	cmd.PersistentFlags().Bool("json", true, "")
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	resp := &resty.Response{RawResponse: &http.Response{Header: http.Header{"Location": {"/queue/item/7/"}}}}
+	err := followTriggeredRun(cmd, client, "app", resp, followOptions{StageGate: gate})
+	return stderr.String(), err
+}
+
+func serveRunningDeploy(server *fakejenkins.Server, deployStatus string) {
+	server.Handle(http.MethodGet, "/queue/item/7/api/json", http.StatusOK, `{"id":7,"executable":{"number":12}}`)
+	server.Handle(http.MethodGet, "/job/app/12/api/json", http.StatusOK, `{"number":12,"building":true}`)
+	server.HandleJSON(http.MethodGet, "/job/app/12/wfapi/describe", map[string]any{"stages": []map[string]string{
+		{"name": "Build", "status": "SUCCESS"},
+		{"name": "Deploy", "status": deployStatus},
+		{"name": "Soak", "status": "IN_PROGRESS"},
+	}})
+}
+
+func TestFollowFailOnStageStopsEarly(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	serveRunningDeploy(server, "FAILED")
+	server.Handle(http.MethodGet, "/job/app/12/consoleText", http.StatusOK, "helm upgrade\nError: timed out waiting for rollout\n")
+
+	stderr, err := followWithStageGate(t, client, stageGate{FailOn: []string{"deploy"}})
+	if code := exitCode(err); code != 11 {
+		t.Fatalf("expected exit 11, got %v", err)
+	}
+	if !strings.Contains(stderr, `Stage "Deploy" FAILED; stopped following run #12`) {
+		t.Fatalf("expected failed stage in stderr, got %q", stderr)
+	}
+	if !strings.Contains(stderr, "Error: timed out waiting for rollout") {
+		t.Fatalf("expected log tail in stderr, got %q", stderr)
+	}
+}
+
+func TestFollowUntilStageExitsZero(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	serveRunningDeploy(server, "SUCCESS")
+
+	stderr, err := followWithStageGate(t, client, stageGate{FailOn: []string{"Deploy"}, Until: []string{"Deploy"}})
+	if err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if !strings.Contains(stderr, `Stage "Deploy" succeeded; stopped following run #12, which keeps running`) {
+		t.Fatalf("unexpected stderr %q", stderr)
+	}
+}
+
+// finishingRun reports the build as running for the first status request and
+// finished afterwards.
+type finishingRun struct {
+	*fakejenkins.Server
+	statusCalls atomic.Int32
+}
+
+func (h *finishingRun) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/job/app/12/api/json" && h.statusCalls.Add(1) > 1 {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"number":12,"building":false,"result":"UNSTABLE"}`))
+		return
+	}
+	h.Server.ServeHTTP(w, r)
+}
+
+func TestFollowStageGateWithoutStageView(t *testing.T) {
+	prev := runPollInterval
+	runPollInterval = time.Millisecond
+	t.Cleanup(func() { runPollInterval = prev })
+
+	server := fakejenkins.New(t)
+	server.Handle(http.MethodGet, "/queue/item/7/api/json", http.StatusOK, `{"id":7,"executable":{"number":12}}`)
+	server.Handle(http.MethodGet, "/job/app/12/api/json", http.StatusOK, `{"number":12,"building":true}`)
+	client := jenkinstest.NewClient(t, &finishingRun{Server: server})
+
+	stderr, err := followWithStageGate(t, client, stageGate{FailOn: []string{"Deploy"}})
+	if code := exitCode(err); code != 10 {
+		t.Fatalf("expected the run result's exit 10, got %v", err)
+	}
+	if !strings.Contains(stderr, "pipeline stage data is unavailable") {
+		t.Fatalf("expected degraded-follow warning, got %q", stderr)
+	}
+	if got := len(server.RequestsTo(http.MethodGet, "/job/app/12/wfapi/describe")); got != 1 {
+		t.Fatalf("expected one stage request, got %d", got)
+	}
+}
+
+func TestStageGateRequiresFollow(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"start", "app", "--until-stage", "Deploy"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	err := cmd.Execute()
+	if code := exitCode(err); code != 2 || !strings.Contains(err.Error(), "--until-stage requires --follow") {
+		t.Fatalf("expected exit 2, got %v", err)
+	}
+	if got := len(server.RequestsTo(http.MethodPost, "/job/app/build")); got != 0 {
+		t.Fatalf("expected no trigger, got %d requests", got)
+	}
+}
@@ -0,0 +1,182 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestValidateJobIsBuildable(t *testing.T) {
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/job/team/job/app/api/json":      `{"_class":"hudson.model.FreeStyleProject","buildable":true,"disabled":false}`,
+		"/job/team/job/off/api/json":      `{"_class":"hudson.model.FreeStyleProject","buildable":false,"disabled":true}`,
+		"/job/team/job/tmpl/api/json":     `{"_class":"hudson.model.FreeStyleProject","buildable":false,"disabled":false}`,
+		"/job/team/api/json":              `{"_class":"com.cloudbees.hudson.plugins.folder.Folder","jobs":[{"name":"app"}]}`,
+		"/job/team/job/mono/api/json":     `{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","jobs":[{"name":"main"}]}`,
+		"/job/team/job/empty-mb/api/json": `{"_class":"org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject","jobs":[]}`,
+	}))
+
+	tests := []struct {
+		name    string
+		jobPath string
+		msg     string
+	}{
+		{name: "buildable job", jobPath: "team/app"},
+		{name: "missing job", jobPath: "team/ghost"},
+		{name: "disabled job", jobPath: "team/off", msg: "job team/off is disabled; run `jk job enable team/off` first"},
+		{name: "not buildable", jobPath: "team/tmpl", msg: "job team/tmpl is not buildable"},
+		{name: "folder", jobPath: "team", msg: "is a folder"},
+		{name: "multibranch", jobPath: "team/mono", msg: "team/mono is a multibranch project, not a job; did you mean one of: main"},
+		{name: "multibranch without branches", jobPath: "team/empty-mb", msg: "list its jobs with `jk job ls team/empty-mb`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateJobIsBuildable(client, tt.jobPath)
+			if tt.msg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var exitErr *cmdutil.ExitError
+			if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+				t.Fatalf("expected exit code 2, got %v", err)
+			}
+			if !strings.Contains(exitErr.Msg, tt.msg) {
+				t.Fatalf("expected %q in %q", tt.msg, exitErr.Msg)
+			}
+		})
+	}
+}
+
+func TestRunStartOnMultibranchProjectIsFolderError(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/releases/job/deploy/api/json", map[string]any{
+		"_class": "org.jenkinsci.plugins.workflow.multibranch.WorkflowMultiBranchProject",
+		"jobs":   []map[string]string{{"name": "main"}, {"name": "develop"}},
+	})
+
+	_, err := executeRunStart(t, client)
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 2 {
+		t.Fatalf("expected exit code 2, got %v", err)
+	}
+	details, ok := exitErr.Details.(shared.FolderDetails)
+	if !ok {
+		t.Fatalf("expected folder details, got %#v", exitErr.Details)
+	}
+	if want := []string{"releases/deploy/main", "releases/deploy/develop"}; !slices.Equal(details.Children, want) {
+		t.Fatalf("children = %v, want %v", details.Children, want)
+	}
+	if len(server.RequestsTo(http.MethodPost, "/job/releases/job/deploy/build")) > 0 {
+		t.Fatal("a multibranch project must not be triggered")
+	}
+}
+
+func useFastQueuePolling(t *testing.T) {
+	t.Helper()
+	prev := queuePollInterval
+	queuePollInterval = time.Millisecond
+	t.Cleanup(func() { queuePollInterval = prev })
+}
+
+const quietDownQueueItem = `{"id":7,"why":"Jenkins is about to shut down","cancelled":false}`
+
+func TestWaitForBuildNumberExitsWhenQuietingDown(t *testing.T) {
+	useFastQueuePolling(t)
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/queue/item/7/api/json": quietDownQueueItem,
+		"/api/json":              `{"quietingDown":true}`,
+	}))
+
+	var errOut bytes.Buffer
+	_, err := waitForBuildNumber(client, "/queue/item/7/", time.Minute, &errOut, followOptions{})
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != quietDownExitCode {
+		t.Fatalf("expected exit code %d, got %v", quietDownExitCode, err)
+	}
+	if !strings.Contains(errOut.String(), "Waiting in queue: Jenkins is about to shut down") {
+		t.Fatalf("queue reason not reported:\n%s", errOut.String())
+	}
+	if !strings.Contains(errOut.String(), "warning: Jenkins is quieting down") {
+		t.Fatalf("quiet-down warning missing:\n%s", errOut.String())
+	}
+}
+
+func TestWaitForBuildNumberWaitsThroughQuietDown(t *testing.T) {
+	useFastQueuePolling(t)
+	var polls atomic.Int32
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/json":
+			_, _ = w.Write([]byte(`{"quietingDown":true}`))
+		case "/queue/item/7/api/json":
+			if polls.Add(1) < 3 {
+				_, _ = w.Write([]byte(quietDownQueueItem))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":7,"executable":{"number":42}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	var errOut bytes.Buffer
+	number, err := waitForBuildNumber(client, "/queue/item/7/", time.Minute, &errOut, followOptions{WaitThroughQuietDown: true})
+	if err != nil {
+		t.Fatalf("waitForBuildNumber: %v", err)
+	}
+	if number != 42 {
+		t.Fatalf("build number = %d, want 42", number)
+	}
+	if got := strings.Count(errOut.String(), "warning: Jenkins is quieting down"); got != 1 {
+		t.Fatalf("expected one quiet-down warning, got %d:\n%s", got, errOut.String())
+	}
+}
+
+func TestWaitForBuildNumberQuietDownKeepsTimeout(t *testing.T) {
+	useFastQueuePolling(t)
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/queue/item/7/api/json": quietDownQueueItem,
+		"/api/json":              `{"quietingDown":true}`,
+	}))
+
+	var errOut bytes.Buffer
+	_, err := waitForBuildNumber(client, "/queue/item/7/", 20*time.Millisecond, &errOut, followOptions{WaitThroughQuietDown: true})
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != quietDownExitCode {
+		t.Fatalf("expected exit code %d, got %v", quietDownExitCode, err)
+	}
+	if !strings.Contains(err.Error(), "quieting down") {
+		t.Fatalf("timeout does not mention quiet-down: %v", err)
+	}
+}
+
+func TestWaitForBuildNumberIgnoresStaleShutdownText(t *testing.T) {
+	useFastQueuePolling(t)
+	client := jenkinstest.NewClient(t, stubJobs(map[string]string{
+		"/queue/item/7/api/json": quietDownQueueItem,
+		"/api/json":              `{"quietingDown":false}`,
+	}))
+
+	var errOut bytes.Buffer
+	_, err := waitForBuildNumber(client, "/queue/item/7/", 20*time.Millisecond, &errOut, followOptions{})
+	if err == nil || !strings.Contains(err.Error(), "timed out waiting for run to start: Jenkins is about to shut down") {
+		t.Fatalf("expected timeout carrying the queue reason, got %v", err)
+	}
+	if strings.Contains(errOut.String(), "quieting down") {
+		t.Fatalf("unexpected quiet-down warning:\n%s", errOut.String())
+	}
+}
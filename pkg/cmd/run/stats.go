@@ -0,0 +1,307 @@
+package run
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/internal/filter"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	defaultStatsSince  = "30d"
+	defaultStatsBucket = "1d"
+	// maxStatsBuckets keeps a tiny --bucket over a long window from
+	// printing (and allocating) an unbounded table.
+	maxStatsBuckets = 1000
+)
+
+// statsWeekEpoch is the Monday that week-sized buckets are aligned to, so
+// --bucket 1w starts each bucket at Monday 00:00 UTC.
+var statsWeekEpoch = time.Date(1970, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+type runStatsOutput struct {
+	SchemaVersion string           `json:"schemaVersion"`
+	JobPath       string           `json:"jobPath"`
+	Bucket        string           `json:"bucket"`
+	Since         string           `json:"since"`
+	Until         string           `json:"until"`
+	Filters       []string         `json:"filters,omitempty"`
+	Buckets       []runStatsBucket `json:"buckets"`
+	// RunsScanned counts the completed runs that matched; Truncated reports
+	// that --max-scan stopped the scan before the start of the window.
+	RunsScanned int  `json:"runsScanned"`
+	MaxScan     int  `json:"maxScan"`
+	Truncated   bool `json:"truncated,omitempty"`
+}
+
+// runStatsBucket summarizes the completed runs that started in one bucket.
+// SuccessRate is the fraction of runs with result SUCCESS; durations are
+// zero for a bucket without runs.
+type runStatsBucket struct {
+	Start            string  `json:"start"`
+	Runs             int     `json:"runs"`
+	Successes        int     `json:"successes"`
+	SuccessRate      float64 `json:"successRate"`
+	MeanDurationMs   int64   `json:"meanDurationMs"`
+	MedianDurationMs int64   `json:"medianDurationMs"`
+	P95DurationMs    int64   `json:"p95DurationMs"`
+	TotalDurationMs  int64   `json:"totalDurationMs"`
+
+	durations []int64
+}
+
+func newRunStatsCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		sinceArg    string
+		untilArg    string
+		bucketArg   string
+		filterArgs  []string
+		enableRegex bool
+		maxScan     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "stats <jobPath>",
+		Short: "Summarize runs per time bucket",
+		Long: `Scan the completed runs of a job in a window and summarize them per time
+bucket: run count, success rate, and mean, median, 95th percentile, and total
+duration. Buckets are aligned to UTC boundaries (weeks start on Monday), and
+buckets without runs are listed with zeros.
+
+At most --max-scan builds are read, newest first; when that stops the scan
+before --since, the oldest buckets are incomplete and a warning is printed.`,
+		Example: `  # Daily trend over the last 30 days
+  jk run stats Helm.Chart.Deploy --since 30d --bucket 1d
+
+  # Weekly failure rate of production deploys
+  jk run stats Helm.Chart.Deploy --since 12w --bucket 1w --filter param.ENV=prod --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, err := parseStatsBucket(bucketArg)
+			if err != nil {
+				return err
+			}
+			if maxScan <= 0 {
+				return shared.NewExitError(2, "--max-scan must be positive")
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			now := client.ServerNow()
+			since, until, err := parseTimeRange(sinceArg, untilArg, now)
+			if err != nil {
+				return err
+			}
+			if until == nil {
+				until = &now
+			}
+			if n := countStatsBuckets(*since, *until, bucket); n > maxStatsBuckets {
+				return shared.NewExitError(2, fmt.Sprintf("--bucket %s over this window makes %d buckets (max %d); use a larger bucket or a shorter --since", bucketArg, n, maxStatsBuckets))
+			}
+
+			parsedFilters, err := filter.Parse(append([]string{"status=completed"}, filterArgs...))
+			if err != nil {
+				return err
+			}
+
+			opts := runListOptions{
+				Limit:      maxScan,
+				Filters:    parsedFilters,
+				RawFilters: append([]string{}, filterArgs...),
+				Since:      since,
+				Until:      until,
+				AllowRegex: enableRegex,
+			}
+			opts, reqs, builds, err := fetchRunSummaries(cmd.Context(), client, jobPath, opts)
+			if err != nil {
+				return err
+			}
+			list, runs, err := processRunList(jobPath, opts, builds, reqs)
+			if err != nil {
+				return err
+			}
+
+			output := runStatsOutput{
+				SchemaVersion: "1.0",
+				JobPath:       jobpath.Normalize(jobPath),
+				Bucket:        strings.TrimSpace(bucketArg),
+				Since:         shared.FormatTime(*since),
+				Until:         shared.FormatTime(*until),
+				Filters:       opts.RawFilters,
+				Buckets:       bucketRunStats(runs, *since, *until, bucket),
+				RunsScanned:   len(runs),
+				MaxScan:       maxScan,
+				Truncated:     list.NextCursor != "" || scanStoppedEarly(builds, maxScan+runListHeadroom, *since),
+			}
+			if output.Truncated {
+				cmdutil.Warnf(cmd, "scanned the newest %d runs without reaching --since; older buckets are incomplete (raise --max-scan)", maxScan)
+			}
+
+			return shared.PrintOutput(cmd, output, func() error {
+				renderRunStatsHuman(cmd, output)
+				return nil
+			})
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().StringVar(&sinceArg, "since", defaultStatsSince, "Start of the window (RFC3339 or duration such as 30d)")
+	cmd.Flags().StringVar(&untilArg, "until", "", "End of the window (RFC3339 or duration ago; defaults to now)")
+	cmd.Flags().StringVar(&bucketArg, "bucket", defaultStatsBucket, "Bucket size in whole hours, days, or weeks (1h, 6h, 1d, 1w)")
+	cmd.Flags().StringSliceVar(&filterArgs, "filter", nil, "Filter runs (repeatable): key[op]value")
+	cmd.Flags().BoolVar(&enableRegex, "regex", false, "Enable regular expression matching for filters")
+	cmd.Flags().IntVar(&maxScan, "max-scan", defaultSearchMaxScan, "Max builds to scan")
+	cmdutil.SetFilterSupport(cmd, filter.AllowedKeys(), filter.Operators())
+
+	return cmd
+}
+
+// parseStatsBucket accepts a positive whole number of hours, such as 1h,
+// 1d, or 2w, so buckets line up with UTC hour boundaries.
+func parseStatsBucket(value string) (time.Duration, error) {
+	bucket, err := filter.ParseDuration(value)
+	if err != nil {
+		return 0, shared.NewExitError(2, fmt.Sprintf("invalid --bucket %q: %v", value, err))
+	}
+	if bucket < time.Hour || bucket%time.Hour != 0 {
+		return 0, shared.NewExitError(2, fmt.Sprintf("--bucket must be a whole number of hours, days, or weeks, got %q", value))
+	}
+	return bucket, nil
+}
+
+// alignStatsBucket returns the start of the bucket holding t. Buckets are
+// multiples of size counted from the Unix epoch in UTC, or from a Monday
+// when size is a whole number of weeks.
+func alignStatsBucket(t time.Time, size time.Duration) time.Time {
+	origin := time.Unix(0, 0).UTC()
+	if size%(7*24*time.Hour) == 0 {
+		origin = statsWeekEpoch
+	}
+	offset := t.UTC().Sub(origin)
+	n := offset / size
+	if offset%size < 0 {
+		n--
+	}
+	return origin.Add(n * size)
+}
+
+func countStatsBuckets(since, until time.Time, size time.Duration) int {
+	first := alignStatsBucket(since, size)
+	last := alignStatsBucket(until, size)
+	return int(last.Sub(first)/size) + 1
+}
+
+// bucketRunStats spreads runs over every bucket from since to until, oldest
+// first, including buckets without runs.
+func bucketRunStats(runs []*runInspection, since, until time.Time, size time.Duration) []runStatsBucket {
+	first := alignStatsBucket(since, size)
+	buckets := make([]runStatsBucket, countStatsBuckets(since, until, size))
+	for i := range buckets {
+		buckets[i].Start = shared.FormatTime(first.Add(time.Duration(i) * size))
+	}
+
+	for _, run := range runs {
+		started := time.UnixMilli(run.Summary.Timestamp)
+		i := int(alignStatsBucket(started, size).Sub(first) / size)
+		if i < 0 || i >= len(buckets) {
+			continue
+		}
+		b := &buckets[i]
+		b.Runs++
+		if strings.EqualFold(strings.TrimSpace(run.Summary.Result), "SUCCESS") {
+			b.Successes++
+		}
+		b.durations = append(b.durations, run.Summary.Duration)
+	}
+
+	for i := range buckets {
+		b := &buckets[i]
+		if b.Runs == 0 {
+			continue
+		}
+		sort.Slice(b.durations, func(x, y int) bool { return b.durations[x] < b.durations[y] })
+		for _, d := range b.durations {
+			b.TotalDurationMs += d
+		}
+		b.SuccessRate = float64(b.Successes) / float64(b.Runs)
+		b.MeanDurationMs = b.TotalDurationMs / int64(b.Runs)
+		b.MedianDurationMs = percentile(b.durations, 50)
+		b.P95DurationMs = percentile(b.durations, 95)
+		b.durations = nil
+	}
+	return buckets
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted values: the
+// smallest value with at least p percent of the values at or below it.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// scanStoppedEarly reports that the build window was full and its oldest
+// build still started inside the window, so older runs were never read.
+func scanStoppedEarly(builds []runSummary, fetchLimit int, since time.Time) bool {
+	if len(builds) < fetchLimit {
+		return false
+	}
+	oldest := builds[0].Timestamp
+	for _, build := range builds {
+		if build.Timestamp < oldest {
+			oldest = build.Timestamp
+		}
+	}
+	return oldest >= since.UnixMilli()
+}
+
+func renderRunStatsHuman(cmd *cobra.Command, output runStatsOutput) {
+	w := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(w, "%-20s %6s %8s %11s %11s %11s %12s\n", "BUCKET", "RUNS", "SUCCESS", "MEAN", "MEDIAN", "P95", "TOTAL")
+	for _, b := range output.Buckets {
+		rate := "-"
+		if b.Runs > 0 {
+			rate = fmt.Sprintf("%.0f%%", b.SuccessRate*100)
+		}
+		_, _ = fmt.Fprintf(w, "%-20s %6d %8s %11s %11s %11s %12s\n",
+			b.Start,
+			b.Runs,
+			rate,
+			statsDuration(b.MeanDurationMs),
+			statsDuration(b.MedianDurationMs),
+			statsDuration(b.P95DurationMs),
+			statsDuration(b.TotalDurationMs),
+		)
+	}
+}
+
+// statsDuration rounds to whole seconds so the table columns stay narrow.
+func statsDuration(ms int64) string {
+	if ms <= 0 {
+		return "-"
+	}
+	return shared.DurationString((ms + 500) / 1000 * 1000)
+}
@@ -0,0 +1,156 @@
+package run
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		values []int64
+		p      float64
+		want   int64
+	}{
+		{nil, 50, 0},
+		{[]int64{7}, 95, 7},
+		{[]int64{1, 2, 3, 4}, 50, 2},
+		{[]int64{1, 2, 3, 4, 5}, 50, 3},
+		{[]int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, 95, 100},
+		{[]int64{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, 90, 90},
+		{[]int64{1, 2, 3}, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.values, tt.p); got != tt.want {
+			t.Fatalf("percentile(%v, %v) = %d, want %d", tt.values, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestAlignStatsBucket(t *testing.T) {
+	ts := time.Date(2026, time.March, 12, 17, 45, 30, 0, time.FixedZone("CET", 3600)) // 16:45:30 UTC, a Thursday
+	tests := []struct {
+		size time.Duration
+		want time.Time
+	}{
+		{time.Hour, time.Date(2026, time.March, 12, 16, 0, 0, 0, time.UTC)},
+		{6 * time.Hour, time.Date(2026, time.March, 12, 12, 0, 0, 0, time.UTC)},
+		{24 * time.Hour, time.Date(2026, time.March, 12, 0, 0, 0, 0, time.UTC)},
+		{7 * 24 * time.Hour, time.Date(2026, time.March, 9, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := alignStatsBucket(ts, tt.size); !got.Equal(tt.want) {
+			t.Fatalf("align to %s: got %s, want %s", tt.size, got, tt.want)
+		}
+	}
+
+	before := time.Date(1969, time.December, 31, 23, 30, 0, 0, time.UTC)
+	if got := alignStatsBucket(before, time.Hour); !got.Equal(time.Date(1969, time.December, 31, 23, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected pre-epoch times to round down, got %s", got)
+	}
+}
+
+func TestParseStatsBucket(t *testing.T) {
+	for value, want := range map[string]time.Duration{"1h": time.Hour, "6h": 6 * time.Hour, "1d": 24 * time.Hour, "2w": 14 * 24 * time.Hour} {
+		got, err := parseStatsBucket(value)
+		if err != nil || got != want {
+			t.Fatalf("parseStatsBucket(%q) = %s, %v; want %s", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"30m", "90m", "0h", "soon"} {
+		if _, err := parseStatsBucket(value); exitCode(err) != 2 {
+			t.Fatalf("expected exit 2 for --bucket %q, got %v", value, err)
+		}
+	}
+}
+
+func TestBucketRunStatsFillsEmptyBuckets(t *testing.T) {
+	day := 24 * time.Hour
+	since := time.Date(2026, time.March, 1, 9, 0, 0, 0, time.UTC)
+	until := time.Date(2026, time.March, 4, 9, 0, 0, 0, time.UTC)
+	run := func(start time.Time, result string, duration time.Duration) *runInspection {
+		return &runInspection{Summary: runSummary{Timestamp: start.UnixMilli(), Result: result, Duration: duration.Milliseconds()}}
+	}
+	runs := []*runInspection{
+		run(time.Date(2026, time.March, 4, 8, 0, 0, 0, time.UTC), "SUCCESS", 4*time.Minute),
+		run(time.Date(2026, time.March, 1, 22, 0, 0, 0, time.UTC), "FAILURE", 3*time.Minute),
+		run(time.Date(2026, time.March, 1, 10, 0, 0, 0, time.UTC), "SUCCESS", time.Minute),
+	}
+
+	buckets := bucketRunStats(runs, since, until, day)
+	if len(buckets) != 4 {
+		t.Fatalf("expected 4 daily buckets, got %d", len(buckets))
+	}
+	if buckets[0].Start != "2026-03-01T00:00:00Z" || buckets[3].Start != "2026-03-04T00:00:00Z" {
+		t.Fatalf("expected oldest-first UTC buckets, got %s .. %s", buckets[0].Start, buckets[3].Start)
+	}
+	first := buckets[0]
+	if first.Runs != 2 || first.Successes != 1 || first.SuccessRate != 0.5 || first.TotalDurationMs != 240000 || first.MeanDurationMs != 120000 || first.MedianDurationMs != 60000 || first.P95DurationMs != 180000 {
+		t.Fatalf("unexpected first bucket %+v", first)
+	}
+	for _, empty := range buckets[1:3] {
+		if empty.Runs != 0 || empty.SuccessRate != 0 || empty.MeanDurationMs != 0 {
+			t.Fatalf("expected an empty bucket, got %+v", empty)
+		}
+	}
+	if buckets[3].Runs != 1 || buckets[3].SuccessRate != 1 {
+		t.Fatalf("unexpected last bucket %+v", buckets[3])
+	}
+}
+
+func TestScanStoppedEarly(t *testing.T) {
+	since := time.UnixMilli(1000)
+	full := []runSummary{{Timestamp: 3000}, {Timestamp: 2000}}
+	if !scanStoppedEarly(full, 2, since) {
+		t.Fatal("expected a full window inside --since to be truncated")
+	}
+	if scanStoppedEarly(append(full, runSummary{Timestamp: 500}), 3, since) {
+		t.Fatal("expected a window reaching past --since to be complete")
+	}
+	if scanStoppedEarly(full, 5, since) {
+		t.Fatal("expected a short window to be complete")
+	}
+}
+
+func TestRunStatsCommand(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	now := time.Now().UTC()
+	server.HandleJSON(http.MethodGet, "/job/app/api/json", map[string]any{
+		"builds": []map[string]any{
+			{"number": 4, "building": true, "timestamp": now.UnixMilli()},
+			{"number": 3, "result": "SUCCESS", "timestamp": now.Add(-time.Hour).UnixMilli(), "duration": 60000},
+			{"number": 2, "result": "FAILURE", "timestamp": now.Add(-49 * time.Hour).UnixMilli(), "duration": 30000},
+			{"number": 1, "result": "SUCCESS", "timestamp": now.Add(-30 * 24 * time.Hour).UnixMilli(), "duration": 10000},
+		},
+	})
+	f, stdout, stderr := fakejenkins.Factory(client)
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"stats", "app", "--since", "3d", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run stats: %v", err)
+	}
+
+	var out runStatsOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if len(out.Buckets) < 4 || out.RunsScanned != 2 || out.Truncated {
+		t.Fatalf("expected the two completed runs in the window over 4+ buckets, got %+v", out)
+	}
+	runs := 0
+	for _, b := range out.Buckets {
+		runs += b.Runs
+	}
+	if runs != 2 {
+		t.Fatalf("expected 2 bucketed runs, got %d", runs)
+	}
+}
@@ -0,0 +1,332 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	defaultTopHighlight = "1.5x"
+	defaultTopInterval  = 5 * time.Second
+	minTopInterval      = time.Second
+)
+
+// runTopRow is one running build in a `run top` snapshot. EstimatedDurationMs
+// and PercentOverEstimate are nil when Jenkins has no estimate for the job;
+// PercentOverEstimate is negative while the build is within its estimate.
+type runTopRow struct {
+	JobPath             string   `json:"jobPath"`
+	Number              int64    `json:"number"`
+	URL                 string   `json:"url"`
+	Node                string   `json:"node"`
+	Labels              []string `json:"labels,omitempty"`
+	Flyweight           bool     `json:"flyweight,omitempty"`
+	StartedAt           string   `json:"startedAt"`
+	ElapsedMs           int64    `json:"elapsedMs"`
+	EstimatedDurationMs *int64   `json:"estimatedDurationMs"`
+	PercentOverEstimate *float64 `json:"percentOverEstimate"`
+	// Overrun marks a build past its estimate by the --highlight factor.
+	Overrun bool `json:"overrun"`
+	// Aborted is set on builds --kill-over stopped.
+	Aborted bool `json:"aborted,omitempty"`
+
+	ratio float64
+}
+
+// runTopOutput is one `run top` snapshot. The Result counts --kill-over
+// aborts and carries the builds that could not be stopped.
+type runTopOutput struct {
+	SchemaVersion string      `json:"schemaVersion"`
+	Items         []runTopRow `json:"items"`
+	shared.Result
+}
+
+type runTopOptions struct {
+	Folder    string
+	Label     string
+	Highlight float64
+}
+
+func newRunTopCmd(f *cmdutil.Factory) *cobra.Command {
+	var (
+		folder       string
+		label        string
+		highlightArg string
+		killOverArg  string
+		assumeYes    bool
+		okOnPartial  bool
+		watch        bool
+		interval     time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Show running builds sorted by overrun",
+		Long: `List every build currently holding an executor, regular or flyweight, with
+the node it runs on, how long it has been running, and how that compares with
+Jenkins' estimated duration. The slowest builds relative to their estimate
+come first; builds without an estimate are listed last. Builds past their
+estimate by the --highlight factor are marked as overruns.
+
+--watch redraws the table every --interval until interrupted. --kill-over
+aborts builds running at least that multiple of their estimate, asking for
+each one unless --yes.`,
+		Example: `  # What is running, and what is stuck
+  jk run top
+
+  # Builds under a folder on linux agents, refreshed every 10 seconds
+  jk run top --folder team/services --label linux --watch --interval 10s
+
+  # Abort builds running three times longer than usual
+  jk run top --kill-over 3x --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			highlight, err := parseOverrunFactor("--highlight", highlightArg)
+			if err != nil {
+				return err
+			}
+			var killOver float64
+			if strings.TrimSpace(killOverArg) != "" {
+				if killOver, err = parseOverrunFactor("--kill-over", killOverArg); err != nil {
+					return err
+				}
+			}
+			structured := shared.WantsJSON(cmd) || shared.WantsYAML(cmd)
+			switch {
+			case watch && structured:
+				return shared.NewExitError(2, "--watch cannot be combined with --json or --yaml")
+			case watch && killOver > 0:
+				return shared.NewExitError(2, "--watch cannot be combined with --kill-over")
+			case watch && interval < minTopInterval:
+				return shared.NewExitError(2, fmt.Sprintf("--interval must be at least %s", minTopInterval))
+			}
+
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+			opts := runTopOptions{Label: strings.TrimSpace(label), Highlight: highlight}
+			if strings.TrimSpace(folder) != "" {
+				if opts.Folder, err = shared.ResolveJobPath(cmd, client, folder); err != nil {
+					return err
+				}
+			}
+			ios, err := f.Streams()
+			if err != nil {
+				return err
+			}
+			snapshot := func(ctx context.Context) ([]runTopRow, error) {
+				builds, err := shared.FetchRunningBuilds(ctx, client)
+				if err != nil {
+					return nil, err
+				}
+				return runTopRows(builds, opts, client.ServerNow()), nil
+			}
+
+			if watch {
+				return watchRunTop(cmd.Context(), interval, func(ctx context.Context) error {
+					rows, err := snapshot(ctx)
+					if err != nil {
+						return err
+					}
+					ios.RefreshScreen()
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s  every %s\n\n", time.Now().Format("15:04:05"), interval)
+					renderRunTopHuman(cmd, rows, ios.ColorScheme().Red)
+					return nil
+				})
+			}
+
+			rows, err := snapshot(cmd.Context())
+			if err != nil {
+				return err
+			}
+			output := runTopOutput{SchemaVersion: "1.0", Items: rows, Result: shared.NewResult()}
+			if killOver > 0 {
+				if err := killOverrunBuilds(cmd, f, client, &output, killOver, assumeYes); err != nil {
+					return err
+				}
+			}
+			if err := shared.PrintOutput(cmd, output, func() error {
+				renderRunTopHuman(cmd, output.Items, ios.ColorScheme().Red)
+				return nil
+			}); err != nil {
+				return err
+			}
+			output.WriteIssues(cmd)
+			return output.ExitError("builds", okOnPartial)
+		},
+	}
+
+	cmd.Flags().StringVar(&folder, "folder", "", "Only builds of jobs under this folder")
+	cmdutil.SetJobPathFlag(cmd, "folder")
+	cmd.Flags().StringVar(&label, "label", "", "Only builds on nodes with this label")
+	cmd.Flags().StringVar(&highlightArg, "highlight", defaultTopHighlight, "Mark builds running this multiple of their estimate (e.g. 1.5x)")
+	cmd.Flags().StringVar(&killOverArg, "kill-over", "", "Abort builds running at least this multiple of their estimate (e.g. 3x)")
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "With --kill-over, abort without asking for each build")
+	shared.AddOKOnPartialFlag(cmd, &okOnPartial)
+	cmd.Flags().BoolVar(&watch, "watch", false, "Redraw the table until interrupted")
+	cmd.Flags().DurationVar(&interval, "interval", defaultTopInterval, "Refresh interval for --watch")
+	cmdutil.SetExitCodes(cmd, map[int]string{
+		1: "A --kill-over abort failed",
+		2: "Invalid flags, or confirmation required with --no-input",
+	})
+	return cmd
+}
+
+// parseOverrunFactor reads a multiple of the estimated duration written as
+// 3x, 3, or 300%; it must be at least 1.
+func parseOverrunFactor(flag, value string) (float64, error) {
+	raw := strings.ToLower(strings.TrimSpace(value))
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(raw, "x"):
+		raw = strings.TrimSuffix(raw, "x")
+	case strings.HasSuffix(raw, "%"):
+		raw, scale = strings.TrimSuffix(raw, "%"), 0.01
+	}
+	factor, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil {
+		return 0, shared.NewExitError(2, fmt.Sprintf("invalid %s %q: use a multiple such as 1.5x", flag, value))
+	}
+	factor *= scale
+	if factor < 1 {
+		return 0, shared.NewExitError(2, fmt.Sprintf("%s must be at least 1x, got %q", flag, value))
+	}
+	return factor, nil
+}
+
+// runTopRows filters builds by folder and node label and sorts them by how
+// far past their estimate they are, then by elapsed time.
+func runTopRows(builds []shared.RunningBuild, opts runTopOptions, now time.Time) []runTopRow {
+	rows := []runTopRow{}
+	for _, build := range builds {
+		if opts.Folder != "" && !strings.HasPrefix(build.JobPath+"/", jobpath.Normalize(opts.Folder)+"/") {
+			continue
+		}
+		if opts.Label != "" && !shared.HasLabel(build.Labels, opts.Label) {
+			continue
+		}
+		row := runTopRow{
+			JobPath:   build.JobPath,
+			Number:    build.Number,
+			URL:       build.URL,
+			Node:      build.Node,
+			Labels:    build.Labels,
+			Flyweight: build.Flyweight,
+			StartedAt: shared.FormatTime(time.UnixMilli(build.Timestamp)),
+			ElapsedMs: max(now.UnixMilli()-build.Timestamp, 0),
+		}
+		if estimate := build.EstimatedDurationMs; estimate > 0 {
+			row.EstimatedDurationMs = &estimate
+			row.ratio = float64(row.ElapsedMs) / float64(estimate)
+			over := (row.ratio - 1) * 100
+			row.PercentOverEstimate = &over
+			row.Overrun = row.ratio >= opts.Highlight
+		}
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if (a.EstimatedDurationMs == nil) != (b.EstimatedDurationMs == nil) {
+			return b.EstimatedDurationMs == nil
+		}
+		if a.ratio != b.ratio {
+			return a.ratio > b.ratio
+		}
+		return a.ElapsedMs > b.ElapsedMs
+	})
+	return rows
+}
+
+// killOverrunBuilds stops the builds running at least factor times their
+// estimate, confirming each one unless assumeYes, and marks them Aborted.
+// Declined builds are left running; each abort counts in output's Result.
+func killOverrunBuilds(cmd *cobra.Command, f *cmdutil.Factory, client shared.Doer, output *runTopOutput, factor float64, assumeYes bool) error {
+	ios, err := f.Streams()
+	if err != nil {
+		return err
+	}
+	for i := range output.Items {
+		row := &output.Items[i]
+		if row.EstimatedDurationMs == nil || row.ratio < factor {
+			continue
+		}
+		if !assumeYes {
+			question := fmt.Sprintf("Abort %s #%d (running %s, %.0f%% over its estimate)?", row.JobPath, row.Number, statsDuration(row.ElapsedMs), *row.PercentOverEstimate)
+			ok, err := cmdutil.ConfirmOrFail(ios, question, "--yes")
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+		}
+		target := fmt.Sprintf("%s #%d", row.JobPath, row.Number)
+		if err := cancelBuild(client, row.JobPath, row.Number, "stop"); err != nil {
+			output.Fail(target, fmt.Errorf("abort %s: %w", target, err))
+			continue
+		}
+		row.Aborted = true
+		output.Succeed()
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Aborted %s\n", target)
+	}
+	return nil
+}
+
+// watchRunTop calls render immediately and then every interval until ctx is
+// done or render fails.
+func watchRunTop(ctx context.Context, interval time.Duration, render func(context.Context) error) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := render(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func renderRunTopHuman(cmd *cobra.Command, rows []runTopRow, highlight func(string) string) {
+	w := cmd.OutOrStdout()
+	if len(rows) == 0 {
+		_, _ = fmt.Fprintln(w, "No running builds")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "%-40s %7s %-20s %11s %11s %8s\n", "JOB", "BUILD", "NODE", "ELAPSED", "ESTIMATE", "OVER")
+	for _, row := range rows {
+		estimate, over := "-", "-"
+		if row.EstimatedDurationMs != nil {
+			estimate = statsDuration(*row.EstimatedDurationMs)
+			over = fmt.Sprintf("%+.0f%%", *row.PercentOverEstimate)
+		}
+		node := row.Node
+		if row.Flyweight {
+			node += " (flyweight)"
+		}
+		line := fmt.Sprintf("%-40s %7s %-20s %11s %11s %8s", row.JobPath, fmt.Sprintf("#%d", row.Number), node, statsDuration(row.ElapsedMs), estimate, over)
+		if row.Aborted {
+			line += "  aborted"
+		}
+		if row.Overrun {
+			line = highlight(line)
+		}
+		_, _ = fmt.Fprintln(w, line)
+	}
+}
@@ -0,0 +1,201 @@
+package run
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestParseOverrunFactor(t *testing.T) {
+	for value, want := range map[string]float64{"3x": 3, "1.5X": 1.5, "2": 2, "250%": 2.5} {
+		got, err := parseOverrunFactor("--kill-over", value)
+		if err != nil || got != want {
+			t.Fatalf("parseOverrunFactor(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"0.5x", "fast", "", "50%"} {
+		if _, err := parseOverrunFactor("--kill-over", value); exitCode(err) != 2 {
+			t.Fatalf("expected exit 2 for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestRunTopRowsSortsByOverrun(t *testing.T) {
+	now := time.Date(2026, time.March, 12, 12, 0, 0, 0, time.UTC)
+	started := func(ago time.Duration) int64 { return now.Add(-ago).UnixMilli() }
+	builds := []shared.RunningBuild{
+		{JobPath: "team/api", Number: 7, Node: "linux-1", Labels: []string{"linux"}, Timestamp: started(10 * time.Minute), EstimatedDurationMs: (20 * time.Minute).Milliseconds()},
+		{JobPath: "team/web", Number: 3, Node: "linux-2", Labels: []string{"linux"}, Timestamp: started(40 * time.Minute), EstimatedDurationMs: (10 * time.Minute).Milliseconds()},
+		{JobPath: "team/new", Number: 1, Node: "mac-1", Labels: []string{"macos"}, Timestamp: started(time.Hour), EstimatedDurationMs: -1},
+		{JobPath: "other/job", Number: 9, Node: "linux-1", Labels: []string{"linux"}, Timestamp: started(15 * time.Minute), EstimatedDurationMs: (10 * time.Minute).Milliseconds()},
+	}
+
+	rows := runTopRows(builds, runTopOptions{Highlight: 1.5}, now)
+	var order []string
+	for _, row := range rows {
+		order = append(order, row.JobPath)
+	}
+	if got := strings.Join(order, ","); got != "team/web,other/job,team/api,team/new" {
+		t.Fatalf("unexpected order %s", got)
+	}
+	if !rows[0].Overrun || *rows[0].PercentOverEstimate != 300 || !rows[1].Overrun || rows[2].Overrun {
+		t.Fatalf("unexpected overrun marks: %+v", rows[:3])
+	}
+	if rows[3].EstimatedDurationMs != nil || rows[3].PercentOverEstimate != nil || rows[3].Overrun {
+		t.Fatalf("expected no estimate for a first build, got %+v", rows[3])
+	}
+
+	rows = runTopRows(builds, runTopOptions{Folder: "team", Label: "LINUX", Highlight: 1.5}, now)
+	if len(rows) != 2 || rows[0].JobPath != "team/web" || rows[1].JobPath != "team/api" {
+		t.Fatalf("expected folder and label filters to keep two builds, got %+v", rows)
+	}
+}
+
+type runTopEnv struct {
+	f      *cmdutil.Factory
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+}
+
+func (e *runTopEnv) execute(args ...string) error {
+	cmd := NewCmdRun(e.f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs(args)
+	cmd.SetOut(e.stdout)
+	cmd.SetErr(e.stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cmd.Execute()
+}
+
+func newRunTopServer(t *testing.T) (*fakejenkins.Server, *runTopEnv) {
+	t.Helper()
+	server, client := fakejenkins.NewClient(t)
+	now := time.Now()
+	executable := func(url string, number int64, ago, estimate time.Duration) map[string]any {
+		return map[string]any{
+			"number":            number,
+			"url":               "https://jenkins.example.com" + url,
+			"timestamp":         now.Add(-ago).UnixMilli(),
+			"estimatedDuration": estimate.Milliseconds(),
+		}
+	}
+	server.HandleJSON(http.MethodGet, "/computer/api/json", map[string]any{
+		"computer": []map[string]any{
+			{
+				"displayName":    "Built-In Node",
+				"assignedLabels": []map[string]any{{"name": "built-in"}},
+				"executors":      []map[string]any{{"currentExecutable": nil}},
+				"oneOffExecutors": []map[string]any{
+					{"currentExecutable": executable("/job/team/job/deploy/12/", 12, 2*time.Hour, 30*time.Minute)},
+					{"currentExecutable": executable("/job/team/job/lint/5/", 5, 5*time.Minute, 10*time.Minute)},
+				},
+			},
+			{
+				"displayName":    "linux-1",
+				"assignedLabels": []map[string]any{{"name": "linux"}, {"name": "linux-1"}},
+				"executors": []map[string]any{
+					{"currentExecutable": executable("/job/team/job/deploy/12/", 12, 2*time.Hour, 30*time.Minute)},
+					{"currentExecutable": map[string]any{"_class": "org.jenkinsci.plugins.workflow.support.steps.ExecutorStepExecution$PlaceholderTask$PlaceholderExecutable"}},
+				},
+			},
+		},
+	})
+	f, stdout, stderr := fakejenkins.Factory(client)
+	return server, &runTopEnv{f: f, stdout: stdout, stderr: stderr}
+}
+
+func TestRunTopCommandJSON(t *testing.T) {
+	_, env := newRunTopServer(t)
+	if err := env.execute("top", "--json"); err != nil {
+		t.Fatalf("run top: %v", err)
+	}
+	var output runTopOutput
+	if err := json.Unmarshal(env.stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, env.stdout.String())
+	}
+	rows := output.Items
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 running builds, got %+v", rows)
+	}
+	if rows[0].JobPath != "team/deploy" || rows[0].Node != "linux-1" || rows[0].Flyweight || !rows[0].Overrun {
+		t.Fatalf("expected the overrunning deploy on linux-1 first, got %+v", rows[0])
+	}
+	if rows[1].JobPath != "team/lint" || !rows[1].Flyweight || rows[1].Overrun {
+		t.Fatalf("expected the flyweight lint build second, got %+v", rows[1])
+	}
+}
+
+func TestRunTopKillOver(t *testing.T) {
+	server, env := newRunTopServer(t)
+	server.Handle(http.MethodPost, "/job/team/job/deploy/12/stop", http.StatusOK, "")
+	if err := env.execute("top", "--kill-over", "3x", "--yes"); err != nil {
+		t.Fatalf("run top --kill-over: %v", err)
+	}
+	if len(server.RequestsTo(http.MethodPost, "/job/team/job/deploy/12/stop")) != 1 {
+		t.Fatal("expected the deploy build to be stopped")
+	}
+	if len(server.RequestsTo(http.MethodPost, "/job/team/job/lint/5/stop")) != 0 {
+		t.Fatal("expected the lint build within its estimate to keep running")
+	}
+	if !strings.Contains(env.stdout.String(), "aborted") || !strings.Contains(env.stderr.String(), "Aborted team/deploy #12") {
+		t.Fatalf("expected the abort to be reported, got stdout %q stderr %q", env.stdout.String(), env.stderr.String())
+	}
+}
+
+func TestRunTopKillOverReportsFailedAborts(t *testing.T) {
+	server, env := newRunTopServer(t)
+	server.Handle(http.MethodPost, "/job/team/job/deploy/12/stop", http.StatusInternalServerError, "")
+
+	err := env.execute("top", "--kill-over", "3x", "--yes", "--json")
+	if exitCode(err) != 1 || !strings.Contains(err.Error(), "abort team/deploy #12") {
+		t.Fatalf("expected the failed abort to exit 1, got %v", err)
+	}
+	var output runTopOutput
+	if err := json.Unmarshal(env.stdout.Bytes(), &output); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, env.stdout.String())
+	}
+	if output.Summary.Failed != 1 || len(output.Errors) != 1 || output.Errors[0].Target != "team/deploy #12" {
+		t.Fatalf("expected the deploy abort error in the envelope, got %+v", output.Result)
+	}
+	if len(output.Items) != 2 || output.Items[0].Aborted {
+		t.Fatalf("expected both builds listed and none aborted, got %+v", output.Items)
+	}
+}
+
+func TestRunTopRejectsWatchWithJSON(t *testing.T) {
+	_, env := newRunTopServer(t)
+	if err := env.execute("top", "--watch", "--json"); exitCode(err) != 2 {
+		t.Fatalf("expected exit 2, got %v", err)
+	}
+}
+
+func TestWatchRunTopStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	renders := 0
+	err := watchRunTop(ctx, time.Millisecond, func(context.Context) error {
+		renders++
+		if renders == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil || renders != 3 {
+		t.Fatalf("expected three renders and no error, got %d, %v", renders, err)
+	}
+
+	boom := errors.New("boom")
+	if err := watchRunTop(context.Background(), time.Millisecond, func(context.Context) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("expected the render error, got %v", err)
+	}
+}
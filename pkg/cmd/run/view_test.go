@@ -0,0 +1,76 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestRunViewNotFoundIncludesRequestURL(t *testing.T) {
+	_, client := fakejenkins.NewClient(t)
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"view", "app", "7"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	err := cmd.Execute()
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("expected exit 3, got %v", err)
+	}
+	want := strings.TrimSuffix(client.Context().URL, "/") + "/job/app/7/api/json"
+	if !strings.HasPrefix(exitErr.Msg, "run app #7 not found") || !strings.HasSuffix(exitErr.Msg, "(GET "+want+")") {
+		t.Fatalf("unexpected message %q", exitErr.Msg)
+	}
+	if exitErr.Request == nil || exitErr.Request.Method != http.MethodGet || exitErr.Request.URL != want {
+		t.Fatalf("unexpected request %+v", exitErr.Request)
+	}
+}
+
+func TestRunViewSizes(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/job/app/7/api/json", map[string]any{
+		"number": 7,
+		"result": "SUCCESS",
+		"artifacts": []map[string]any{
+			{"fileName": "chart.tgz", "relativePath": "dist/chart.tgz", "size": 1500},
+			{"fileName": "sbom.json", "relativePath": "dist/sbom.json", "size": 500},
+		},
+	})
+	server.HandleResponse(http.MethodGet, "/job/app/7/logText/progressiveText", http.StatusOK, http.Header{"X-Text-Size": []string{"4096"}}, "")
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	cmd := NewCmdRun(f)
+	cmd.PersistentFlags().Bool("json", false, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	cmd.SetArgs([]string{"view", "app", "7", "--sizes", "--json"})
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("run view: %v", err)
+	}
+	var out runDetailOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("decode output: %v\n%s", err, stdout.String())
+	}
+	if out.ArtifactBytes == nil || *out.ArtifactBytes != 2000 {
+		t.Fatalf("expected 2000 artifact bytes, got %v", out.ArtifactBytes)
+	}
+	if out.LogBytes == nil || *out.LogBytes != 4096 {
+		t.Fatalf("expected 4096 log bytes, got %v", out.LogBytes)
+	}
+}
@@ -0,0 +1,208 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/avivsinai/jenkins-cli/internal/fuzzy"
+	"github.com/avivsinai/jenkins-cli/internal/jobindex"
+	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	defaultJobLimit   = 10
+	defaultJobMaxScan = 5000
+)
+
+// jobQueryFlags are the search flags that apply to a job query; run filters
+// and selections are rejected.
+var jobQueryFlags = map[string]bool{
+	"folder":         true,
+	"job-glob":       true,
+	"limit":          true,
+	"max-scan":       true,
+	"max-depth":      true,
+	"include-folder": true,
+	"exclude-folder": true,
+	"refresh":        true,
+}
+
+// walkFlags change which jobs a folder walk visits, so a job index built
+// from the whole controller cannot answer for them.
+var walkFlags = []string{"max-depth", "include-folder", "exclude-folder"}
+
+type jobSearchOutput struct {
+	SchemaVersion string            `json:"schemaVersion"`
+	Query         string            `json:"query"`
+	Items         []jobSearchItem   `json:"items"`
+	Metadata      jobSearchMetadata `json:"metadata"`
+}
+
+type jobSearchItem struct {
+	Path  string `json:"path"`
+	Score int    `json:"score"`
+	Class string `json:"class,omitempty"`
+}
+
+type jobSearchMetadata struct {
+	Folder      string `json:"folder,omitempty"`
+	JobGlob     string `json:"jobGlob,omitempty"`
+	JobsScanned int    `json:"jobsScanned"`
+	MaxScan     int    `json:"maxScan"`
+	Truncated   bool   `json:"truncated,omitempty"`
+	// CacheAgeMs is the age of the job index the jobs came from; it is
+	// omitted when the folders were walked.
+	CacheAgeMs *int64 `json:"cacheAgeMs,omitempty"`
+}
+
+// runJobQuery ranks the jobs surviving the folder and glob selection by how
+// well their paths match query.
+func runJobQuery(cmd *cobra.Command, f *cmdutil.Factory, query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return shared.NewExitError(2, "query must not be empty")
+	}
+	var rejected string
+	inherited := cmd.InheritedFlags()
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if rejected == "" && !jobQueryFlags[flag.Name] && inherited.Lookup(flag.Name) == nil {
+			rejected = flag.Name
+		}
+	})
+	if rejected != "" {
+		return shared.NewExitError(2, fmt.Sprintf("--%s cannot be combined with a query", rejected))
+	}
+
+	flags := cmd.Flags()
+	folder, _ := flags.GetString("folder")
+	jobGlob, _ := flags.GetString("job-glob")
+	limit, _ := flags.GetInt("limit")
+	maxScan, _ := flags.GetInt("max-scan")
+	maxDepth, _ := flags.GetInt("max-depth")
+	includes, _ := flags.GetStringArray("include-folder")
+	excludes, _ := flags.GetStringArray("exclude-folder")
+	refresh, _ := flags.GetBool("refresh")
+
+	if limit <= 0 {
+		limit = defaultJobLimit
+	}
+	if !flags.Changed("max-scan") || maxScan <= 0 {
+		maxScan = defaultJobMaxScan
+	}
+	if maxDepth < 0 {
+		return shared.NewExitError(2, "--max-depth must not be negative")
+	}
+	jobGlob = strings.TrimSpace(jobGlob)
+	for _, pattern := range append(append([]string{jobGlob}, includes...), excludes...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return shared.NewExitError(2, fmt.Sprintf("invalid glob %q", pattern))
+		}
+	}
+
+	client, err := shared.JenkinsClient(cmd, f)
+	if err != nil {
+		return err
+	}
+	resolvedFolder, err := shared.ResolveFolder(cmd, client, folder)
+	if err != nil {
+		return err
+	}
+	opts := runcmd.JobQueryOptions{
+		Folder:         jobpath.Normalize(resolvedFolder),
+		JobGlob:        jobGlob,
+		MaxDepth:       maxDepth,
+		IncludeFolders: includes,
+		ExcludeFolders: excludes,
+		MaxJobs:        maxScan,
+	}
+
+	walkShaped := false
+	for _, name := range walkFlags {
+		walkShaped = walkShaped || flags.Changed(name)
+	}
+
+	metadata := jobSearchMetadata{Folder: opts.Folder, JobGlob: jobGlob, MaxScan: maxScan}
+	var found runcmd.FoundJobs
+	index := cachedJobIndex(cmd, client.ContextName(), refresh || walkShaped)
+	if index != nil {
+		found = runcmd.FilterJobs(index.Jobs, opts)
+		age := time.Since(index.UpdatedAt).Milliseconds()
+		metadata.CacheAgeMs = &age
+	} else {
+		found, err = runcmd.FindJobs(cmd.Context(), client, opts)
+		if err != nil {
+			return err
+		}
+		// A walk of the whole controller is exactly what the job index
+		// holds, so completion and later queries can reuse it.
+		if opts.Folder == "" && jobGlob == "" && !walkShaped && !found.Truncated {
+			paths := make([]string, 0, len(found.Jobs))
+			for _, job := range found.Jobs {
+				paths = append(paths, job.Path)
+			}
+			if err := jobindex.Save(client.ContextName(), jobindex.New(paths)); err != nil {
+				cmdutil.Warnf(cmd, "%v", err)
+			}
+		}
+	}
+	metadata.JobsScanned = len(found.Jobs)
+	metadata.Truncated = found.Truncated
+	if found.Truncated {
+		cmdutil.Warnf(cmd, "ranked only the first %d jobs; narrow the search with --folder or --job-glob, or raise --max-scan", maxScan)
+	}
+
+	output := rankJobs(query, found.Jobs, limit)
+	output.Metadata = metadata
+	return shared.PrintOutput(cmd, output, func() error {
+		w := cmd.OutOrStdout()
+		if len(output.Items) == 0 {
+			_, _ = fmt.Fprintln(w, "No matching jobs found")
+			return nil
+		}
+		for _, item := range output.Items {
+			_, _ = fmt.Fprintln(w, item.Path)
+		}
+		return nil
+	})
+}
+
+// rankJobs scores jobs against query, best first, keeping at most limit.
+func rankJobs(query string, jobs []runcmd.FoundJob, limit int) jobSearchOutput {
+	classes := make(map[string]string, len(jobs))
+	paths := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		classes[job.Path] = job.Class
+		paths = append(paths, job.Path)
+	}
+
+	output := jobSearchOutput{SchemaVersion: "1.0", Query: query, Items: []jobSearchItem{}}
+	for _, match := range fuzzy.Search(query, paths, limit) {
+		output.Items = append(output.Items, jobSearchItem{Path: match.Value, Score: match.Score, Class: classes[match.Value]})
+	}
+	return output
+}
+
+// cachedJobIndex returns the context's job index when it is recent and
+// skip is unset. Problems reading it only mean the folders are walked.
+func cachedJobIndex(cmd *cobra.Command, contextName string, skip bool) *jobindex.Index {
+	if skip {
+		return nil
+	}
+	index, err := jobindex.Load(contextName)
+	if err != nil {
+		cmdutil.Warnf(cmd, "%v", err)
+		return nil
+	}
+	if index == nil || index.Stale(time.Now()) {
+		return nil
+	}
+	return index
+}
@@ -4,17 +4,35 @@ import (
 	"github.com/spf13/cobra"
 
 	runcmd "github.com/avivsinai/jenkins-cli/pkg/cmd/run"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
 // NewCmdSearch exposes run search as a top-level command for quick discovery.
+// With a query argument it ranks job paths instead of searching runs.
 func NewCmdSearch(f *cmdutil.Factory) *cobra.Command {
 	cmd := runcmd.NewCmdRunSearch(f)
+	cmd.Use = "search [query]"
 	cmd.Short = "Search Jenkins jobs and runs across folders"
 	cmd.Long = `Search Jenkins jobs and their runs without knowing exact folder paths.
 
-This is equivalent to 'jk run search', exposed at the top level for discoverability.`
-	cmd.Example = `  # Discover job paths that contain "ada"
+Without a query this is equivalent to 'jk run search', exposed at the top level
+for discoverability.
+
+With a query it finds jobs instead: the jobs under --folder that match
+--job-glob are ranked by how closely their paths match the query, best first,
+and the top --limit are printed. A recent job index (see 'jk job paths')
+answers without contacting the controller unless --refresh, --max-depth or a
+folder glob is given. --max-scan bounds how many jobs are ranked (default
+5000); the folder walk stops once that many matched. With --json each item
+carries its path, score and, when known, class.`
+	cmd.Example = `  # Find jobs whose path resembles "api deploy"
+  jk search api-deploy
+
+  # Rank only deploy jobs under a folder
+  jk search api --folder team-a --job-glob "*deploy*"
+
+  # Discover job paths that contain "ada"
   jk search --job-glob "*ada*" --limit 5
 
   # Find recent failed builds across a folder
@@ -22,5 +40,19 @@ This is equivalent to 'jk run search', exposed at the top level for discoverabil
 
   # Search for builds with specific parameter value
   jk search --job-glob "*/deploy-*" --filter param.ENVIRONMENT=production --since 7d`
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	searchRuns := cmd.RunE
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return runJobQuery(cmd, f, args[0])
+		}
+		if cmd.Flags().Changed("refresh") {
+			return shared.NewExitError(2, "--refresh requires a query")
+		}
+		return searchRuns(cmd, args)
+	}
+	cmd.Flags().Bool("refresh", false, "With a query, walk the folders even if the job index is recent")
+	cmd.Flags().Lookup("max-scan").Usage = "Max builds to scan per job (with a query: max jobs to rank)"
 	return cmd
 }
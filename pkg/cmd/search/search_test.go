@@ -0,0 +1,147 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jobindex"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const (
+	folderClass    = "com.cloudbees.hudson.plugins.folder.Folder"
+	freestyleClass = "hudson.model.FreeStyleProject"
+)
+
+func serveJobTree(server *fakejenkins.Server) {
+	server.HandleJSON(http.MethodGet, "/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "team-a", "_class": folderClass},
+		{"name": "team-b", "_class": folderClass},
+	}})
+	server.HandleJSON(http.MethodGet, "/job/team-a/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "api-deploy", "_class": freestyleClass},
+		{"name": "web-deploy", "_class": freestyleClass},
+		{"name": "api-test", "_class": freestyleClass},
+	}})
+	server.HandleJSON(http.MethodGet, "/job/team-b/api/json", map[string]any{"jobs": []map[string]string{
+		{"name": "api-deploy", "_class": freestyleClass},
+	}})
+}
+
+func runSearch(t *testing.T, client *jenkins.Client, args ...string) (jobSearchOutput, *bytes.Buffer, error) {
+	t.Helper()
+	f, stdout, stderr := fakejenkins.Factory(client)
+
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.AddCommand(NewCmdSearch(f))
+	root.SetArgs(append([]string{"search", "--json"}, args...))
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+	err := root.Execute()
+
+	var output jobSearchOutput
+	if err == nil {
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &output), stdout.String())
+	}
+	return output, stderr, err
+}
+
+func paths(output jobSearchOutput) []string {
+	result := make([]string, 0, len(output.Items))
+	for _, item := range output.Items {
+		result = append(result, item.Path)
+	}
+	return result
+}
+
+func TestJobQueryRanksGlobSurvivors(t *testing.T) {
+	t.Setenv(cachefile.DirEnv, t.TempDir())
+	server, client := fakejenkins.NewClient(t)
+	serveJobTree(server)
+
+	output, _, err := runSearch(t, client, "api-deploy", "--job-glob", "*deploy*")
+	require.NoError(t, err)
+	require.Equal(t, "1.0", output.SchemaVersion)
+	require.Equal(t, "api-deploy", output.Query)
+	require.Equal(t, []string{"team-a/api-deploy", "team-b/api-deploy"}, paths(output)[:2])
+	require.NotContains(t, paths(output), "team-a/api-test")
+	require.Positive(t, output.Items[0].Score)
+	require.Equal(t, freestyleClass, output.Items[0].Class)
+	require.Equal(t, 3, output.Metadata.JobsScanned)
+	require.Nil(t, output.Metadata.CacheAgeMs)
+
+	output, _, err = runSearch(t, client, "test", "--folder", "team-a", "--limit", "1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-a/api-test"}, paths(output))
+	require.Equal(t, "team-a", output.Metadata.Folder)
+}
+
+func TestJobQueryMaxScanStopsWalk(t *testing.T) {
+	t.Setenv(cachefile.DirEnv, t.TempDir())
+	server, client := fakejenkins.NewClient(t)
+	serveJobTree(server)
+
+	output, stderr, err := runSearch(t, client, "deploy", "--max-scan", "2")
+	require.NoError(t, err)
+	require.Equal(t, 2, output.Metadata.JobsScanned)
+	require.Equal(t, 2, output.Metadata.MaxScan)
+	require.True(t, output.Metadata.Truncated)
+	require.Contains(t, stderr.String(), "ranked only the first 2 jobs")
+	require.Empty(t, server.RequestsTo(http.MethodGet, "/job/team-b/api/json"))
+
+	// A truncated walk is not a complete index.
+	index, err := jobindex.Load(client.ContextName())
+	require.NoError(t, err)
+	require.Nil(t, index)
+}
+
+func TestJobQueryUsesJobIndex(t *testing.T) {
+	t.Setenv(cachefile.DirEnv, t.TempDir())
+	server, client := fakejenkins.NewClient(t)
+	serveJobTree(server)
+
+	// A walk of the whole controller builds the index.
+	_, _, err := runSearch(t, client, "web")
+	require.NoError(t, err)
+	walks := len(server.RequestsTo(http.MethodGet, "/api/json"))
+
+	output, _, err := runSearch(t, client, "web", "--job-glob", "team-a/*")
+	require.NoError(t, err)
+	require.Equal(t, []string{"team-a/web-deploy"}, paths(output))
+	require.NotNil(t, output.Metadata.CacheAgeMs)
+	require.Len(t, server.RequestsTo(http.MethodGet, "/api/json"), walks)
+
+	output, _, err = runSearch(t, client, "web", "--refresh")
+	require.NoError(t, err)
+	require.Nil(t, output.Metadata.CacheAgeMs)
+	require.Len(t, server.RequestsTo(http.MethodGet, "/api/json"), walks+1)
+}
+
+func TestJobQueryRejectsRunFlags(t *testing.T) {
+	t.Setenv(cachefile.DirEnv, t.TempDir())
+	_, client := fakejenkins.NewClient(t)
+
+	for _, args := range [][]string{
+		{"api", "--filter", "result=FAILURE"},
+		{" "},
+		{"--refresh"},
+	} {
+		_, _, err := runSearch(t, client, args...)
+		var exitErr *cmdutil.ExitError
+		require.True(t, errors.As(err, &exitErr), "%q: %v", args, err)
+		require.Equal(t, 2, exitErr.Code, "%q", args)
+	}
+}
@@ -0,0 +1,139 @@
+package shared
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+const (
+	// ellipsis marks where TruncateMiddle removed text.
+	ellipsis = "…"
+	// minPathShare is the fraction of the terminal a path column always gets,
+	// however wide the other columns are.
+	minPathShare = 0.4
+	// minPathWidth keeps truncated paths recognisable on very narrow
+	// terminals.
+	minPathWidth = 20
+	tabWidth     = 8
+)
+
+// escapeSequence matches the SGR colour codes and OSC 8 hyperlinks that
+// human output may contain; neither takes up terminal cells.
+var escapeSequence = regexp.MustCompile(`\x1b\[[0-9;]*m|\x1b\]8;[^\x1b\x07]*(?:\x1b\\|\x07)`)
+
+// AddFullPathsFlag registers --full-paths, which turns off job path
+// truncation in human output.
+func AddFullPathsFlag(cmd *cobra.Command, target *bool) {
+	cmd.Flags().BoolVar(target, "full-paths", false, "Never truncate job paths to fit the terminal width")
+}
+
+// PathFitter shortens job paths so table rows fit the terminal. The zero
+// value leaves paths untouched.
+type PathFitter struct {
+	width int
+}
+
+// NewPathFitter returns a fitter for the command's terminal. Paths are only
+// truncated when stdout is a terminal and --full-paths is not set, so piped
+// output always carries complete paths.
+func NewPathFitter(f *cmdutil.Factory, fullPaths bool) PathFitter {
+	if f == nil {
+		return PathFitter{}
+	}
+	ios, err := f.Streams()
+	if err != nil {
+		return PathFitter{}
+	}
+	return StreamsPathFitter(ios, fullPaths)
+}
+
+// StreamsPathFitter is NewPathFitter for code that holds the streams.
+func StreamsPathFitter(ios *iostreams.IOStreams, fullPaths bool) PathFitter {
+	if fullPaths || ios == nil || !ios.IsStdoutTTY() {
+		return PathFitter{}
+	}
+	return PathFitter{width: ios.TerminalWidth()}
+}
+
+// Fit truncates path so a row whose other columns take rest cells fits the
+// terminal. The path keeps at least minPathShare of the width.
+func (p PathFitter) Fit(path string, rest int) string {
+	if p.width <= 0 {
+		return path
+	}
+	budget := p.width - rest
+	if floor := int(float64(p.width) * minPathShare); budget < floor {
+		budget = floor
+	}
+	if budget < minPathWidth {
+		budget = minPathWidth
+	}
+	return TruncateMiddle(path, budget)
+}
+
+// DisplayWidth counts the terminal cells text occupies, ignoring colour and
+// hyperlink escapes. Each rune counts as one cell.
+func DisplayWidth(text string) int {
+	return utf8.RuneCountInString(escapeSequence.ReplaceAllString(text, ""))
+}
+
+// RowWidth is the width of fields joined by tabs, with tab stops every eight
+// cells as terminals render them.
+func RowWidth(fields ...string) int {
+	width := 0
+	for i, field := range fields {
+		if i > 0 {
+			width += tabWidth - width%tabWidth
+		}
+		width += DisplayWidth(field)
+	}
+	return width
+}
+
+// TruncateMiddle shortens path to at most limit runes. Job paths keep their
+// first and last segments with an ellipsis in between
+// ("releases/…/Helm.Chart.Deploy"), adding back trailing segments while they
+// fit, so the job name survives. Other text, or a last segment that is too
+// long on its own, loses runes from the middle; URLs always do, keeping the
+// host and the job name. Runes are never split.
+func TruncateMiddle(path string, limit int) string {
+	runes := []rune(path)
+	if len(runes) <= limit {
+		return path
+	}
+	if limit <= 0 {
+		return ""
+	}
+	if limit == 1 {
+		return ellipsis
+	}
+
+	segments := strings.Split(path, "/")
+	if len(segments) >= 3 && !strings.Contains(path, "://") {
+		first, last := segments[0], segments[len(segments)-1]
+		kept := []string{last}
+		if candidate := first + "/" + ellipsis + "/" + last; utf8.RuneCountInString(candidate) <= limit {
+			for i := len(segments) - 2; i > 0; i-- {
+				next := append([]string{segments[i]}, kept...)
+				if utf8.RuneCountInString(first+"/"+ellipsis+"/"+strings.Join(next, "/")) > limit {
+					break
+				}
+				kept = next
+			}
+			return first + "/" + ellipsis + "/" + strings.Join(kept, "/")
+		}
+		if candidate := ellipsis + "/" + last; utf8.RuneCountInString(candidate) <= limit {
+			return candidate
+		}
+	}
+
+	head := (limit - 1) / 2
+	tail := limit - 1 - head
+	return string(runes[:head]) + ellipsis + string(runes[len(runes)-tail:])
+}
@@ -0,0 +1,64 @@
+package shared
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestTruncateMiddle(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		max    int
+		expect string
+	}{
+		{"fits", "team/app", 20, "team/app"},
+		{"keeps first and last segments", "releases/platform/helm/Helm.Chart.Deploy", 30, "releases/…/Helm.Chart.Deploy"},
+		{"adds back trailing segments", "releases/platform/helm/Helm.Chart.Deploy", 35, "releases/…/helm/Helm.Chart.Deploy"},
+		{"drops first segment before the job name", "releases/platform/Helm.Chart.Deploy", 20, "…/Helm.Chart.Deploy"},
+		{"long job name", "a/b/averyveryverylongjobname", 10, "a/b/…bname"},
+		{"plain text", "abcdefghij", 5, "ab…ij"},
+		{"url keeps host and job", "https://ci.example.com/job/team/job/app/", 24, "https://ci.…eam/job/app/"},
+		{"multi-byte segments", "リリース/プラットフォーム/デプロイ", 12, "リリース/…/デプロイ"},
+		{"multi-byte runes", "日本語のジョブ名です", 5, "日本…です"},
+		{"emoji", "🚀🚀🚀🚀🚀🚀", 4, "🚀…🚀🚀"},
+		{"single cell", "abcdef", 1, "…"},
+		{"zero", "abcdef", 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateMiddle(tt.input, tt.max)
+			require.Equal(t, tt.expect, got)
+			require.True(t, utf8.ValidString(got), "split a UTF-8 sequence: %q", got)
+			require.LessOrEqual(t, utf8.RuneCountInString(got), tt.max)
+		})
+	}
+}
+
+func TestDisplayWidthIgnoresEscapes(t *testing.T) {
+	require.Equal(t, 7, DisplayWidth("\x1b[31mFAILURE\x1b[0m"))
+	require.Equal(t, 2, DisplayWidth("\x1b]8;;https://ci.example.com/job/app/7/\x1b\\#7\x1b]8;;\x1b\\"))
+	require.Equal(t, 3, DisplayWidth("日本語"))
+	require.Equal(t, 18, RowWidth("#7", "FAILURE", "2m"))
+}
+
+func TestPathFitter(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: ios}
+	path := "releases/platform/helm/Helm.Chart.Deploy"
+
+	ios.SetTerminalWidth(50)
+	require.Equal(t, path, NewPathFitter(f, false).Fit(path, 30), "non-terminal output must not be truncated")
+
+	ios.SetStdoutTTY(true)
+	require.Equal(t, "releases/…/Helm.Chart.Deploy", NewPathFitter(f, false).Fit(path, 22))
+	require.Equal(t, path, NewPathFitter(f, true).Fit(path, 22), "--full-paths must disable truncation")
+	// However wide the other columns, the path keeps 40% of the width.
+	require.Equal(t, "…/Helm.Chart.Deploy", NewPathFitter(f, false).Fit(path, 45))
+}
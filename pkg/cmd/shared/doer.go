@@ -0,0 +1,22 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+)
+
+// Doer is the slice of *jenkins.Client that request-building helpers need.
+// Helpers accept it instead of the concrete client so tests can point them at
+// an httptest server (see internal/testing/fakejenkins) and assert on the
+// exact requests they send.
+type Doer interface {
+	NewRequest() *resty.Request
+	NewStreamingRequest() *resty.Request
+	Do(req *resty.Request, method, path string, result interface{}) (*resty.Response, error)
+	Capabilities(ctx context.Context) jenkins.Capabilities
+}
+
+var _ Doer = (*jenkins.Client)(nil)
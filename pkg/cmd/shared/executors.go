@@ -0,0 +1,131 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const runningBuildsTree = "computer[displayName,assignedLabels[name]," +
+	"executors[currentExecutable[number,url,timestamp,estimatedDuration]]," +
+	"oneOffExecutors[currentExecutable[number,url,timestamp,estimatedDuration]]]"
+
+// RunningBuild is a build occupying an executor. Node is the agent it runs
+// on; a Pipeline build that only holds a flyweight executor (its top-level
+// script, outside any node block) reports the controller with Flyweight set.
+type RunningBuild struct {
+	JobPath   string   `json:"jobPath"`
+	Number    int64    `json:"number"`
+	URL       string   `json:"url"`
+	Node      string   `json:"node"`
+	Labels    []string `json:"labels,omitempty"`
+	Flyweight bool     `json:"flyweight,omitempty"`
+	// Timestamp is the start time in epoch milliseconds; EstimatedDurationMs
+	// is Jenkins' estimate, or -1 when it has no completed build to go by.
+	Timestamp           int64 `json:"timestamp"`
+	EstimatedDurationMs int64 `json:"estimatedDurationMs"`
+}
+
+type runningExecutor struct {
+	CurrentExecutable *struct {
+		Number            int64  `json:"number"`
+		URL               string `json:"url"`
+		Timestamp         int64  `json:"timestamp"`
+		EstimatedDuration int64  `json:"estimatedDuration"`
+	} `json:"currentExecutable"`
+}
+
+type runningComputers struct {
+	Computers []struct {
+		DisplayName     string            `json:"displayName"`
+		AssignedLabels  []AssignedLabel   `json:"assignedLabels"`
+		Executors       []runningExecutor `json:"executors"`
+		OneOffExecutors []runningExecutor `json:"oneOffExecutors"`
+	} `json:"computer"`
+}
+
+// FetchRunningBuilds lists the builds on every executor, regular and
+// flyweight, from one /computer/api/json request. A build seen on several
+// executors is listed once, at the first regular executor holding it, and
+// executables without a build URL (such as Pipeline node-block placeholders
+// on older controllers) are skipped. Builds are sorted by job path and
+// number.
+func FetchRunningBuilds(ctx context.Context, client Doer) ([]RunningBuild, error) {
+	req := client.NewRequest().SetQueryParam("tree", runningBuildsTree)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+	var computers runningComputers
+	resp, err := client.Do(req, http.MethodGet, "/computer/api/json", &computers)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckResponse(resp, "nodes"); err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]int)
+	var builds []RunningBuild
+	add := func(node string, labels []string, flyweight bool, executor runningExecutor) {
+		exe := executor.CurrentExecutable
+		if exe == nil || exe.URL == "" {
+			return
+		}
+		jobPath := buildJobPath(exe.URL)
+		if jobPath == "" {
+			return
+		}
+		build := RunningBuild{
+			JobPath:             jobPath,
+			Number:              exe.Number,
+			URL:                 exe.URL,
+			Node:                node,
+			Labels:              labels,
+			Flyweight:           flyweight,
+			Timestamp:           exe.Timestamp,
+			EstimatedDurationMs: exe.EstimatedDuration,
+		}
+		key := strings.TrimSuffix(exe.URL, "/")
+		if i, ok := byKey[key]; ok {
+			if builds[i].Flyweight && !flyweight {
+				builds[i] = build
+			}
+			return
+		}
+		byKey[key] = len(builds)
+		builds = append(builds, build)
+	}
+
+	for _, computer := range computers.Computers {
+		labels := LabelNames(computer.AssignedLabels)
+		for _, executor := range computer.Executors {
+			add(computer.DisplayName, labels, false, executor)
+		}
+		for _, executor := range computer.OneOffExecutors {
+			add(computer.DisplayName, labels, true, executor)
+		}
+	}
+
+	sort.Slice(builds, func(i, j int) bool {
+		if builds[i].JobPath != builds[j].JobPath {
+			return builds[i].JobPath < builds[j].JobPath
+		}
+		return builds[i].Number < builds[j].Number
+	})
+	return builds, nil
+}
+
+// buildJobPath extracts the job path from a build URL, or "" when the URL
+// does not point into a job.
+func buildJobPath(buildURL string) string {
+	u, err := url.Parse(buildURL)
+	if err != nil {
+		return ""
+	}
+	path, _ := jobpath.Decode(u.EscapedPath())
+	return path
+}
@@ -0,0 +1,39 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+)
+
+func TestFetchRunningBuildsPrefersRegularExecutors(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.HandleJSON(http.MethodGet, "/computer/api/json", map[string]any{
+		"computer": []map[string]any{
+			{"displayName": "Built-In Node", "oneOffExecutors": []map[string]any{
+				{"currentExecutable": map[string]any{"number": 4, "url": "https://jenkins.example.com/job/a/4/", "estimatedDuration": -1}},
+			}},
+			{"displayName": "agent", "assignedLabels": []map[string]any{{"name": "docker"}}, "executors": []map[string]any{
+				{"currentExecutable": map[string]any{"number": 4, "url": "https://jenkins.example.com/job/a/4/", "estimatedDuration": -1}},
+			}},
+		},
+	})
+
+	builds, err := FetchRunningBuilds(context.Background(), client)
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	require.Equal(t, "a", builds[0].JobPath)
+	require.Equal(t, "agent", builds[0].Node)
+	require.Equal(t, []string{"docker"}, builds[0].Labels)
+	require.False(t, builds[0].Flyweight)
+	require.Contains(t, server.LastRequest(http.MethodGet, "/computer/api/json").Query.Get("tree"), "oneOffExecutors")
+}
+
+func TestBuildJobPath(t *testing.T) {
+	require.Equal(t, "team/app", buildJobPath("https://ci.example.com/jenkins/job/team/job/app/42/"))
+	require.Equal(t, "", buildJobPath("https://ci.example.com/computer/agent/"))
+}
@@ -0,0 +1,106 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// folderChildLimit bounds the children a folder error suggests.
+const folderChildLimit = 10
+
+// FolderDetails is the --json error detail when a job path names a folder.
+type FolderDetails struct {
+	JobPath string `json:"jobPath"`
+	Class   string `json:"class,omitempty"`
+	// Children are the paths of up to ten of the folder's items; empty
+	// with --quiet, which skips listing them.
+	Children []string `json:"children"`
+	// More is set when the folder holds more items than Children lists.
+	More bool `json:"more,omitempty"`
+}
+
+// IsFolderClass reports whether a Jenkins _class holds jobs rather than
+// builds: folders, organization folders, and multibranch projects.
+func IsFolderClass(className string) bool {
+	lower := strings.ToLower(className)
+	return strings.Contains(lower, "folder") || strings.Contains(lower, "multibranch")
+}
+
+// FolderError is the exit 2 error for a folder given where a job was
+// expected, naming up to ten of children (item names) as candidates.
+func FolderError(jobPath, className string, children []string) error {
+	details := FolderDetails{JobPath: jobPath, Class: className, Children: []string{}}
+	if len(children) > folderChildLimit {
+		children, details.More = children[:folderChildLimit], true
+	}
+	for _, child := range children {
+		details.Children = append(details.Children, jobpath.Join(jobPath, child))
+	}
+
+	kind := "a folder"
+	if strings.Contains(strings.ToLower(className), "multibranch") {
+		kind = "a multibranch project"
+	}
+	msg := fmt.Sprintf("%s is %s, not a job", jobPath, kind)
+	if len(children) == 0 {
+		msg += fmt.Sprintf("; list its jobs with `jk job ls %s`", jobPath)
+	} else {
+		msg += "; did you mean one of: " + strings.Join(children, ", ")
+		if details.More {
+			msg += ", …"
+		}
+	}
+	return &cmdutil.ExitError{Code: 2, Msg: msg, Details: details}
+}
+
+// CheckFolder returns a FolderError when jobPath names a folder, listing its
+// children unless listChildren is false. It makes one request and returns
+// nil for jobs and when the lookup fails, leaving the caller's own result
+// to stand.
+func CheckFolder(ctx context.Context, client Doer, jobPath string, listChildren bool) error {
+	tree := "_class"
+	if listChildren {
+		tree = fmt.Sprintf("_class,jobs[name]{0,%d}", folderChildLimit+1)
+	}
+	var payload struct {
+		Class string `json:"_class"`
+		Jobs  []struct {
+			Name string `json:"name"`
+		} `json:"jobs"`
+	}
+	req := client.NewRequest().SetQueryParam("tree", tree)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+	resp, err := client.Do(req, http.MethodGet, fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath)), &payload)
+	if err != nil || resp.StatusCode() != http.StatusOK || !IsFolderClass(payload.Class) {
+		return nil
+	}
+	children := make([]string, 0, len(payload.Jobs))
+	for _, job := range payload.Jobs {
+		children = append(children, job.Name)
+	}
+	return FolderError(jobPath, payload.Class, children)
+}
+
+// ExplainNotFound turns err, a not-found error for a run of jobPath, into a
+// FolderError when jobPath names a folder, which is the usual reason. Other
+// errors are returned unchanged. --quiet skips listing the children.
+func ExplainNotFound(cmd *cobra.Command, client Doer, jobPath string, err error) error {
+	var exitErr *cmdutil.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		return err
+	}
+	if folderErr := CheckFolder(cmd.Context(), client, jobPath, !cmdutil.Quiet(cmd)); folderErr != nil {
+		return folderErr
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+const folderClass = "com.cloudbees.hudson.plugins.folder.Folder"
+
+func folderPayload(children int) string {
+	jobs := make([]string, children)
+	for i := range jobs {
+		jobs[i] = fmt.Sprintf(`{"name":"deploy-%d"}`, i+1)
+	}
+	return fmt.Sprintf(`{"_class":%q,"jobs":[%s]}`, folderClass, strings.Join(jobs, ","))
+}
+
+func TestCheckFolderListsChildren(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/job/services/api/json", http.StatusOK, folderPayload(11))
+
+	err := CheckFolder(context.Background(), client, "team/services", true)
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	require.Equal(t, 2, exitErr.Code)
+	require.Equal(t, "team/services is a folder, not a job; did you mean one of: deploy-1, deploy-2, deploy-3, deploy-4, deploy-5, deploy-6, deploy-7, deploy-8, deploy-9, deploy-10, …", exitErr.Msg)
+
+	details, ok := exitErr.Details.(FolderDetails)
+	require.True(t, ok)
+	require.Len(t, details.Children, 10)
+	require.Equal(t, "team/services/deploy-1", details.Children[0])
+	require.True(t, details.More)
+	require.Equal(t, "_class,jobs[name]{0,11}", server.LastRequest(http.MethodGet, "/job/team/job/services/api/json").Query.Get("tree"))
+}
+
+func TestCheckFolderQuietSkipsChildren(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/api/json", http.StatusOK, fmt.Sprintf(`{"_class":%q}`, folderClass))
+
+	err := CheckFolder(context.Background(), client, "team", false)
+	require.EqualError(t, err, "team is a folder, not a job; list its jobs with `jk job ls team`")
+	require.Equal(t, "_class", server.LastRequest(http.MethodGet, "/job/team/api/json").Query.Get("tree"))
+}
+
+func TestCheckFolderIgnoresJobsAndFailures(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/app/api/json", http.StatusOK, `{"_class":"hudson.model.FreeStyleProject"}`)
+
+	require.NoError(t, CheckFolder(context.Background(), client, "app", true))
+	require.NoError(t, CheckFolder(context.Background(), client, "ghost", true))
+}
+
+func TestExplainNotFound(t *testing.T) {
+	server, client := fakejenkins.NewClient(t)
+	server.Handle(http.MethodGet, "/job/team/api/json", http.StatusOK, folderPayload(2))
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("quiet", false, "")
+
+	notFound := NewExitError(3, "run team #4 not found")
+	err := ExplainNotFound(cmd, client, "team", notFound)
+	require.EqualError(t, err, "team is a folder, not a job; did you mean one of: deploy-1, deploy-2")
+
+	denied := NewExitError(5, "permission denied")
+	require.Same(t, denied, ExplainNotFound(cmd, client, "team", denied))
+	require.Same(t, notFound, ExplainNotFound(cmd, client, "app", notFound))
+}
@@ -0,0 +1,111 @@
+package shared
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// Values accepted by the root --icons flag.
+const (
+	IconsAuto   = "auto"
+	IconsAlways = "always"
+	IconsNever  = "never"
+)
+
+type resultGlyph struct {
+	unicode string
+	ascii   string
+}
+
+// resultGlyphs pairs each result with a shape as well as a color, so results
+// stay distinguishable for color-blind readers and in screenshots.
+var resultGlyphs = map[string]resultGlyph{
+	"SUCCESS":  {unicode: "✓", ascii: "[ok]"},
+	"FAILURE":  {unicode: "✗", ascii: "[x]"},
+	"UNSTABLE": {unicode: "~", ascii: "[~]"},
+	"ABORTED":  {unicode: "⊘", ascii: "[ab]"},
+	"RUNNING":  {unicode: "●", ascii: "[..]"},
+}
+
+// ResultGlyph returns the glyph for a run result or status, or "" when the
+// result has none. An empty result, or a running/building status, maps to the
+// running glyph.
+func ResultGlyph(result string, unicode bool) string {
+	key := strings.ToUpper(strings.TrimSpace(result))
+	switch key {
+	case "", "BUILDING", "IN_PROGRESS":
+		key = "RUNNING"
+	}
+	glyph, ok := resultGlyphs[key]
+	if !ok {
+		return ""
+	}
+	if unicode {
+		return glyph.unicode
+	}
+	return glyph.ascii
+}
+
+// WantsIcons reports whether human output should prefix results with glyphs
+// and whether those glyphs may be Unicode. --icons=auto enables them only when
+// stdout is a terminal; the ASCII fallbacks are used whenever the locale is
+// not UTF-8. Structured output never carries glyphs.
+func WantsIcons(cmd *cobra.Command, f *cmdutil.Factory) (enabled, unicode bool) {
+	if WantsJSON(cmd) || WantsYAML(cmd) {
+		return false, false
+	}
+	mode, _ := cmd.Root().PersistentFlags().GetString("icons")
+	switch mode {
+	case IconsNever:
+		return false, false
+	case IconsAlways:
+		enabled = true
+	default:
+		if f == nil {
+			return false, false
+		}
+		ios, err := f.Streams()
+		if err != nil || ios == nil || !ios.IsStdoutTTY() {
+			return false, false
+		}
+		enabled = true
+	}
+	return enabled, localeIsUTF8(os.Getenv)
+}
+
+// ResultFormatter returns a function that decorates a result label for human
+// output, honoring WantsIcons. Labels without a glyph are returned unchanged.
+func ResultFormatter(cmd *cobra.Command, f *cmdutil.Factory) func(string) string {
+	enabled, unicode := WantsIcons(cmd, f)
+	if !enabled {
+		return func(label string) string { return label }
+	}
+	return func(label string) string {
+		glyph := ResultGlyph(label, unicode)
+		switch {
+		case glyph == "":
+			return label
+		case strings.TrimSpace(label) == "":
+			return glyph
+		default:
+			return glyph + " " + label
+		}
+	}
+}
+
+// localeIsUTF8 applies POSIX locale precedence: LC_ALL, then LC_CTYPE, then
+// LANG. The first non-empty value decides.
+func localeIsUTF8(getenv func(string) string) bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		value := strings.ToLower(getenv(name))
+		if value == "" {
+			continue
+		}
+		return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestResultGlyph(t *testing.T) {
+	cases := []struct {
+		result  string
+		unicode string
+		ascii   string
+	}{
+		{"SUCCESS", "✓", "[ok]"},
+		{"failure", "✗", "[x]"},
+		{"UNSTABLE", "~", "[~]"},
+		{"ABORTED", "⊘", "[ab]"},
+		{"", "●", "[..]"},
+		{"running", "●", "[..]"},
+		{"NOT_BUILT", "", ""},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.unicode, ResultGlyph(tc.result, true), "unicode %q", tc.result)
+		require.Equal(t, tc.ascii, ResultGlyph(tc.result, false), "ascii %q", tc.result)
+	}
+}
+
+func TestLocaleIsUTF8(t *testing.T) {
+	cases := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"unset", nil, false},
+		{"lang utf-8", map[string]string{"LANG": "en_US.UTF-8"}, true},
+		{"lang utf8 lowercase", map[string]string{"LANG": "C.utf8"}, true},
+		{"lang posix", map[string]string{"LANG": "C"}, false},
+		{"lc_all wins", map[string]string{"LC_ALL": "C", "LANG": "en_US.UTF-8"}, false},
+		{"lc_ctype before lang", map[string]string{"LC_CTYPE": "en_US.UTF-8", "LANG": "C"}, true},
+		{"empty lc_all skipped", map[string]string{"LC_ALL": "", "LANG": "de_DE.UTF-8"}, true},
+	}
+	for _, tc := range cases {
+		getenv := func(key string) string { return tc.env[key] }
+		require.Equal(t, tc.want, localeIsUTF8(getenv), tc.name)
+	}
+}
+
+func newIconsTestCmd(t *testing.T, tty bool, flags ...string) (*cobra.Command, *cmdutil.Factory) {
+	t.Helper()
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.PersistentFlags().String("icons", IconsAuto, "")
+	require.NoError(t, root.ParseFlags(flags))
+
+	ios, _, _, _ := iostreams.Test()
+	ios.SetStdoutTTY(tty)
+	return root, &cmdutil.Factory{IOStreams: ios}
+}
+
+func TestResultFormatter(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_CTYPE", "")
+	t.Setenv("LANG", "en_US.UTF-8")
+
+	cmd, f := newIconsTestCmd(t, true)
+	result := ResultFormatter(cmd, f)
+	require.Equal(t, "✓ SUCCESS", result("SUCCESS"))
+	require.Equal(t, "●", result(""))
+	require.Equal(t, "NOT_BUILT", result("NOT_BUILT"))
+
+	cmd, f = newIconsTestCmd(t, false)
+	require.Equal(t, "SUCCESS", ResultFormatter(cmd, f)("SUCCESS"), "auto is off when piped")
+
+	cmd, f = newIconsTestCmd(t, false, "--icons", IconsAlways)
+	require.Equal(t, "✗ FAILURE", ResultFormatter(cmd, f)("FAILURE"))
+
+	cmd, f = newIconsTestCmd(t, true, "--icons", IconsNever)
+	require.Equal(t, "SUCCESS", ResultFormatter(cmd, f)("SUCCESS"))
+
+	cmd, f = newIconsTestCmd(t, true, "--icons", IconsAlways, "--json")
+	require.Equal(t, "SUCCESS", ResultFormatter(cmd, f)("SUCCESS"), "structured output never carries glyphs")
+
+	t.Setenv("LANG", "C")
+	cmd, f = newIconsTestCmd(t, true)
+	require.Equal(t, "[ab] ABORTED", ResultFormatter(cmd, f)("ABORTED"))
+}
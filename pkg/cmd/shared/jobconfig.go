@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// FetchJobConfig returns the config.xml of jobPath verbatim. Error statuses
+// are reported through CheckResponse.
+func FetchJobConfig(ctx context.Context, client Doer, jobPath string) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	path := fmt.Sprintf("/%s/config.xml", jobpath.Encode(jobPath))
+	resp, err := client.Do(client.NewRequest().SetContext(ctx).SetHeader("Accept", "application/xml"), http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := CheckResponse(resp, fmt.Sprintf("job %s", jobPath)); err != nil {
+		return nil, err
+	}
+	return resp.Body(), nil
+}
+
+// StripXMLDeclaration drops the <?xml ...?> prolog. Jenkins writes version 1.1,
+// which encoding/xml refuses even though the documents are 1.0-compatible.
+// The result is always a suffix of data.
+func StripXMLDeclaration(data []byte) []byte {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if bytes.HasPrefix(trimmed, []byte("<?xml")) {
+		if end := bytes.Index(trimmed, []byte("?>")); end >= 0 {
+			return trimmed[end+2:]
+		}
+	}
+	return trimmed
+}
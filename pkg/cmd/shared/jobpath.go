@@ -0,0 +1,155 @@
+package shared
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// DefaultFolder returns the default folder configured on the client's
+// context, without surrounding slashes.
+func DefaultFolder(client *jenkins.Client) string {
+	if client == nil {
+		return ""
+	}
+	ctxDef := client.Context()
+	if ctxDef == nil {
+		return ""
+	}
+	return jobpath.Normalize(ctxDef.DefaultFolder)
+}
+
+// JobPathArg returns the job path or folder cmd was given, as typed: the
+// positional argument marked with cmdutil.SetJobPathArg or, failing that,
+// the first value of a flag marked with cmdutil.SetJobPathFlag.
+func JobPathArg(cmd *cobra.Command) (string, bool) {
+	if cmd == nil {
+		return "", false
+	}
+	if index, ok := cmdutil.JobPathArgIndex(cmd); ok {
+		if arg := strings.TrimSpace(cmd.Flags().Arg(index)); arg != "" {
+			return arg, true
+		}
+	}
+	var arg string
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if arg != "" || !cmdutil.IsJobPathFlag(flag) {
+			return
+		}
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			if slice := values.GetSlice(); len(slice) > 0 {
+				arg = strings.TrimSpace(slice[0])
+			}
+			return
+		}
+		arg = strings.TrimSpace(flag.Value.String())
+	})
+	return arg, arg != ""
+}
+
+// ContextRuleFor returns the context rule for the job path arg names,
+// matched as ResolveJobPath would resolve it without asking the controller:
+// a leading "/" keeps it absolute; otherwise the path under the default
+// folder of the active context is tried before the literal one, or after
+// it with --absolute.
+func ContextRuleFor(cmd *cobra.Command, cfg *config.Config, arg string) (config.ContextRule, bool) {
+	arg = strings.TrimSpace(arg)
+	literal := jobpath.Normalize(arg)
+	candidates := []string{literal}
+	if ctxDef, _, err := cfg.ActiveContext(); err == nil && ctxDef != nil && !strings.HasPrefix(arg, "/") {
+		folder := jobpath.Normalize(ctxDef.DefaultFolder)
+		if folder != "" && literal != "" && literal != folder && !strings.HasPrefix(literal, folder+"/") {
+			prefixed := folder + "/" + literal
+			if wantsAbsolute(cmd) {
+				candidates = append(candidates, prefixed)
+			} else {
+				candidates = []string{prefixed, literal}
+			}
+		}
+	}
+	for _, candidate := range candidates {
+		if rule, ok := cfg.ContextRuleFor(candidate); ok {
+			return rule, true
+		}
+	}
+	return config.ContextRule{}, false
+}
+
+// ResolveJobPath applies the context's default folder to a job path argument.
+// A leading "/" marks the path as absolute and skips the folder entirely.
+// Otherwise <folder>/<arg> is used when it exists and the literal path is the
+// fallback; the root --absolute flag reverses that order.
+func ResolveJobPath(cmd *cobra.Command, client *jenkins.Client, arg string) (string, error) {
+	arg = strings.TrimSpace(arg)
+	literal := jobpath.Normalize(arg)
+	if strings.HasPrefix(arg, "/") {
+		return literal, nil
+	}
+
+	folder := DefaultFolder(client)
+	if folder == "" || literal == "" || literal == folder || strings.HasPrefix(literal, folder+"/") {
+		return literal, nil
+	}
+
+	prefixed := folder + "/" + literal
+	first, fallback := prefixed, literal
+	if wantsAbsolute(cmd) {
+		first, fallback = literal, prefixed
+	}
+
+	exists, err := jobPathExists(client, first)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		jklog.L().Debug().Str("arg", arg).Str("defaultFolder", folder).Msgf("resolved job path to %s", first)
+		return first, nil
+	}
+	jklog.L().Debug().Str("arg", arg).Str("defaultFolder", folder).Msgf("%s not found; using %s", first, fallback)
+	return fallback, nil
+}
+
+// ResolveFolder returns the folder a --folder style flag should target: the
+// flag value resolved like a job path, or the context default when unset.
+func ResolveFolder(cmd *cobra.Command, client *jenkins.Client, flagValue string) (string, error) {
+	if strings.TrimSpace(flagValue) == "" {
+		return DefaultFolder(client), nil
+	}
+	return ResolveJobPath(cmd, client, flagValue)
+}
+
+func wantsAbsolute(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	v, _ := cmd.Root().PersistentFlags().GetBool("absolute")
+	return v
+}
+
+func jobPathExists(client *jenkins.Client, jobPath string) (bool, error) {
+	resp, err := client.Do(
+		client.NewRequest().SetQueryParam("tree", "_class"),
+		http.MethodGet,
+		fmt.Sprintf("/%s/api/json", jobpath.Encode(jobPath)),
+		nil,
+	)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return false, nil
+	}
+	if err := CheckResponse(resp, fmt.Sprintf("job %s", jobPath)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+func newJobPathTestClient(t *testing.T, folder string, existing ...string) (*jenkins.Client, *[]string) {
+	t.Helper()
+	known := make(map[string]struct{}, len(existing))
+	for _, p := range existing {
+		known["/"+jobpath.Encode(p)+"/api/json"] = struct{}{}
+	}
+	var requested []string
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/job/") {
+			requested = append(requested, r.URL.Path)
+		}
+		if _, ok := known[r.URL.Path]; ok {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"_class":"hudson.model.FreeStyleProject"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	client.Context().DefaultFolder = folder
+	return client, &requested
+}
+
+func newJobPathTestCmd(absolute bool) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.PersistentFlags().Bool("absolute", absolute, "")
+	return cmd
+}
+
+func TestResolveJobPathPrefersDefaultFolder(t *testing.T) {
+	client, _ := newJobPathTestClient(t, "team/platform", "team/platform/deploy-prod", "deploy-prod")
+
+	got, err := ResolveJobPath(newJobPathTestCmd(false), client, "deploy-prod")
+	require.NoError(t, err)
+	require.Equal(t, "team/platform/deploy-prod", got)
+}
+
+func TestResolveJobPathFallsBackToLiteral(t *testing.T) {
+	client, _ := newJobPathTestClient(t, "team/platform", "shared/lint")
+
+	got, err := ResolveJobPath(newJobPathTestCmd(false), client, "shared/lint")
+	require.NoError(t, err)
+	require.Equal(t, "shared/lint", got)
+}
+
+func TestResolveJobPathAbsoluteFlagReversesOrder(t *testing.T) {
+	client, _ := newJobPathTestClient(t, "team/platform", "team/platform/deploy-prod", "deploy-prod")
+
+	got, err := ResolveJobPath(newJobPathTestCmd(true), client, "deploy-prod")
+	require.NoError(t, err)
+	require.Equal(t, "deploy-prod", got)
+}
+
+func TestResolveJobPathLeadingSlashSkipsFolder(t *testing.T) {
+	client, requested := newJobPathTestClient(t, "team/platform", "team/platform/deploy-prod")
+
+	got, err := ResolveJobPath(newJobPathTestCmd(false), client, "/deploy-prod")
+	require.NoError(t, err)
+	require.Equal(t, "deploy-prod", got)
+	require.Empty(t, *requested)
+}
+
+func TestResolveJobPathWithoutFolderIsLiteral(t *testing.T) {
+	client, requested := newJobPathTestClient(t, "")
+
+	got, err := ResolveJobPath(newJobPathTestCmd(false), client, "app/main")
+	require.NoError(t, err)
+	require.Equal(t, "app/main", got)
+	require.Empty(t, *requested)
+}
+
+func TestResolveFolderDefaultsToContext(t *testing.T) {
+	client, _ := newJobPathTestClient(t, "/team/platform/")
+
+	got, err := ResolveFolder(newJobPathTestCmd(false), client, "")
+	require.NoError(t, err)
+	require.Equal(t, "team/platform", got)
+
+	got, err = ResolveFolder(newJobPathTestCmd(false), client, "/")
+	require.NoError(t, err)
+	require.Equal(t, "", got)
+}
@@ -0,0 +1,79 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const labelTree = "name,busyExecutors,totalExecutors"
+
+// LabelStatus is the executor capacity behind a node label.
+type LabelStatus struct {
+	Name           string `json:"name"`
+	TotalExecutors int    `json:"totalExecutors"`
+	BusyExecutors  int    `json:"busyExecutors"`
+	// NoOnlineExecutors means work for the label cannot start until an agent
+	// with the label comes online; waiting longer will not help.
+	NoOnlineExecutors bool `json:"noOnlineExecutors"`
+}
+
+// FetchLabelStatus reads a label's executor counts. Jenkins only counts
+// executors on online nodes, so zero total executors means none are online.
+// A label Jenkins does not know returns nil.
+func FetchLabelStatus(ctx context.Context, client Doer, name string) (*LabelStatus, error) {
+	var label struct {
+		BusyExecutors  int `json:"busyExecutors"`
+		TotalExecutors int `json:"totalExecutors"`
+	}
+	req := client.NewRequest().SetContext(ctx).SetQueryParam("tree", labelTree)
+	resp, err := client.Do(req, http.MethodGet, "/label/"+url.PathEscape(name)+"/api/json", &label)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := CheckResponse(resp, fmt.Sprintf("label %s", name)); err != nil {
+		return nil, err
+	}
+	return &LabelStatus{
+		Name:              name,
+		TotalExecutors:    label.TotalExecutors,
+		BusyExecutors:     label.BusyExecutors,
+		NoOnlineExecutors: label.TotalExecutors == 0,
+	}, nil
+}
+
+// IsLabelExpression reports whether label combines labels with operators,
+// e.g. "linux && docker", rather than naming a single label.
+func IsLabelExpression(label string) bool {
+	return strings.ContainsAny(label, "&|!()") || strings.Contains(label, "->") || len(strings.Fields(label)) > 1
+}
+
+// AssignedLabel is an entry of a computer's assignedLabels.
+type AssignedLabel struct {
+	Name string `json:"name"`
+}
+
+// LabelNames lists the names of labels, skipping blank ones.
+func LabelNames(labels []AssignedLabel) []string {
+	var names []string
+	for _, label := range labels {
+		if name := strings.TrimSpace(label.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// HasLabel reports whether labels include want, ignoring case so a --label
+// filter does not depend on how the label was typed.
+func HasLabel(labels []string, want string) bool {
+	return slices.ContainsFunc(labels, func(label string) bool {
+		return strings.EqualFold(label, want)
+	})
+}
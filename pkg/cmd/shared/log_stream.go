@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,16 +11,28 @@ import (
 	"strings"
 	"time"
 
-	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/go-resty/resty/v2"
+
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
-func StreamProgressiveLog(ctx context.Context, client *jenkins.Client, jobPath string, buildNumber int, interval time.Duration, out io.Writer) error {
-	encoded := jenkins.EncodeJobPath(jobPath)
+// StreamProgressiveLog copies a build's console log to out until Jenkins
+// reports no more data. With a poller, failed polls are retried as it
+// decides; without one the first failure ends the stream.
+func StreamProgressiveLog(ctx context.Context, client Doer, jobPath string, buildNumber int, interval time.Duration, out io.Writer, poller *Poller) error {
+	return StreamProgressiveLogFrom(ctx, client, jobPath, buildNumber, 0, interval, out, poller)
+}
+
+// StreamProgressiveLogFrom is StreamProgressiveLog starting at byte offset
+// start, e.g. the log size from ProgressiveLogSize to skip what was already
+// written.
+func StreamProgressiveLogFrom(ctx context.Context, client Doer, jobPath string, buildNumber int, start int64, interval time.Duration, out io.Writer, poller *Poller) error {
+	encoded := jobpath.Encode(jobPath)
 	if encoded == "" {
 		return errors.New("job path is required")
 	}
 
-	offset := 0
+	offset := int(start)
 	path := fmt.Sprintf("/%s/%d/logText/progressiveText", encoded, buildNumber)
 
 	for {
@@ -41,18 +54,29 @@ func StreamProgressiveLog(ctx context.Context, client *jenkins.Client, jobPath s
 		}
 
 		resp, err := client.Do(req, http.MethodGet, path, nil)
-		if err != nil {
-			if ctx != nil && ctx.Err() != nil {
-				return nil
-			}
-			return err
+		if err != nil && ctx != nil && ctx.Err() != nil {
+			return nil
 		}
-
-		if resp.StatusCode() == http.StatusRequestedRangeNotSatisfiable {
+		if err == nil && resp.StatusCode() == http.StatusRequestedRangeNotSatisfiable {
+			discardRawBody(resp)
 			offset = 0
 			time.Sleep(interval)
 			continue
 		}
+		if poller != nil {
+			wait, pollErr := poller.Observe(resp, err, fmt.Sprintf("console log of %s #%d", jobPath, buildNumber))
+			if pollErr != nil {
+				discardRawBody(resp)
+				return pollErr
+			}
+			if wait > 0 {
+				discardRawBody(resp)
+				time.Sleep(wait)
+				continue
+			}
+		} else if err != nil {
+			return err
+		}
 
 		body := resp.RawBody()
 		if body == nil {
@@ -88,10 +112,31 @@ func StreamProgressiveLog(ctx context.Context, client *jenkins.Client, jobPath s
 	}
 }
 
-func CollectLogSnapshot(ctx context.Context, client *jenkins.Client, jobPath string, buildNumber int, maxBytes int, out io.Writer) (bool, error) {
-	encoded := jenkins.EncodeJobPath(jobPath)
+// LogSnapshot describes what CollectLogSnapshot wrote.
+type LogSnapshot struct {
+	// Truncated is set when part of the log was left out.
+	Truncated bool
+	// OmittedBytes is the size of the middle section skipped when a log
+	// larger than maxBytes was sampled, in Jenkins log offsets. It is 0 when
+	// a still-running log was only cut at the end.
+	OmittedBytes int64
+}
+
+// LogOmittedMarker is the line CollectLogSnapshot writes between the head and
+// tail of a sampled log.
+func LogOmittedMarker(omitted int64) string {
+	return fmt.Sprintf("--- %d bytes omitted ---\n", omitted)
+}
+
+// CollectLogSnapshot writes the console log of a build to out. When Jenkins
+// reports (through X-Text-Size) a log larger than maxBytes, it writes the
+// first and last maxBytes/2 instead, trimmed to whole lines and joined by
+// LogOmittedMarker, so both the start-up context and the failure at the end
+// survive.
+func CollectLogSnapshot(ctx context.Context, client Doer, jobPath string, buildNumber int, maxBytes int, out io.Writer) (LogSnapshot, error) {
+	encoded := jobpath.Encode(jobPath)
 	if encoded == "" {
-		return false, errors.New("job path is required")
+		return LogSnapshot{}, errors.New("job path is required")
 	}
 
 	if maxBytes <= 0 {
@@ -101,32 +146,23 @@ func CollectLogSnapshot(ctx context.Context, client *jenkins.Client, jobPath str
 	offset := 0
 	path := fmt.Sprintf("/%s/%d/logText/progressiveText", encoded, buildNumber)
 	total := 0
-	truncated := false
+	truncated := LogSnapshot{Truncated: true}
 
 	for i := 0; i < 1000; i++ {
 		if ctx != nil {
 			select {
 			case <-ctx.Done():
-				return truncated, ctx.Err()
+				return LogSnapshot{}, ctx.Err()
 			default:
 			}
 		}
 
-		req := client.NewStreamingRequest().
-			SetHeader("Accept", "text/plain").
-			SetQueryParam("start", strconv.Itoa(offset)).
-			SetDoNotParseResponse(true)
-
-		if ctx != nil {
-			req.SetContext(ctx)
-		}
-
-		resp, err := client.Do(req, http.MethodGet, path, nil)
+		resp, err := requestProgressiveText(ctx, client, path, int64(offset))
 		if err != nil {
 			if ctx != nil && ctx.Err() != nil {
-				return truncated, ctx.Err()
+				return LogSnapshot{}, ctx.Err()
 			}
-			return truncated, err
+			return LogSnapshot{}, err
 		}
 
 		if resp.StatusCode() == http.StatusRequestedRangeNotSatisfiable {
@@ -137,40 +173,138 @@ func CollectLogSnapshot(ctx context.Context, client *jenkins.Client, jobPath str
 
 		body := resp.RawBody()
 		if body == nil {
-			return truncated, errors.New("log stream returned empty body")
+			return LogSnapshot{}, errors.New("log stream returned empty body")
+		}
+
+		if total == 0 && offset == 0 {
+			if size := textSize(resp); size > int64(maxBytes) {
+				return sampleLog(ctx, client, path, body, size, maxBytes, out)
+			}
 		}
 
 		chunk, err := readAndClose(body)
 		if err != nil {
-			return truncated, fmt.Errorf("read log chunk: %w", err)
+			return LogSnapshot{}, fmt.Errorf("read log chunk: %w", err)
 		}
 
 		if len(chunk) > 0 {
 			if _, err := out.Write(chunk); err != nil {
-				return truncated, err
+				return LogSnapshot{}, err
 			}
 			total += len(chunk)
 		}
 
-		if nextOffset := resp.Header().Get("X-Text-Size"); nextOffset != "" {
-			if val, err := strconv.Atoi(nextOffset); err == nil {
-				offset = val
-			}
+		if size := textSize(resp); size >= 0 {
+			offset = int(size)
 		}
 
 		more := strings.EqualFold(resp.Header().Get("X-More-Data"), "true")
 
 		switch {
 		case !more:
-			return truncated, nil
+			return LogSnapshot{}, nil
 		case len(chunk) == 0:
-			return true, nil
+			return truncated, nil
 		case total >= maxBytes:
-			return true, nil
+			return truncated, nil
+		}
+	}
+
+	return truncated, nil
+}
+
+// sampleLog writes the first half of maxBytes from head, the body of the
+// first progressiveText response, then fetches the last half from the end of
+// a log of size bytes.
+func sampleLog(ctx context.Context, client Doer, path string, head io.ReadCloser, size int64, maxBytes int, out io.Writer) (LogSnapshot, error) {
+	headCap := int64(maxBytes / 2)
+	tailCap := int64(maxBytes) - headCap
+
+	headData, err := io.ReadAll(io.LimitReader(head, headCap))
+	_ = head.Close()
+	if err != nil {
+		return LogSnapshot{}, fmt.Errorf("read log head: %w", err)
+	}
+	if i := bytes.LastIndexByte(headData, '\n'); i >= 0 {
+		headData = headData[:i+1]
+	}
+
+	resp, err := requestProgressiveText(ctx, client, path, size-tailCap)
+	if err != nil {
+		return LogSnapshot{}, err
+	}
+	body := resp.RawBody()
+	if body == nil {
+		return LogSnapshot{}, errors.New("log stream returned empty body")
+	}
+	var tailData []byte
+	if resp.StatusCode() < 400 {
+		tailData, err = io.ReadAll(io.LimitReader(body, tailCap))
+		if err != nil {
+			_ = body.Close()
+			return LogSnapshot{}, fmt.Errorf("read log tail: %w", err)
+		}
+	}
+	_ = body.Close()
+	// The tail starts mid-log; drop the partial first line.
+	if i := bytes.IndexByte(tailData, '\n'); i >= 0 {
+		tailData = tailData[i+1:]
+	}
+
+	omitted := size - int64(len(headData)) - int64(len(tailData))
+	if omitted < 0 {
+		omitted = 0
+	}
+	if len(headData) > 0 && headData[len(headData)-1] != '\n' {
+		headData = append(headData, '\n')
+	}
+	for _, part := range [][]byte{headData, []byte(LogOmittedMarker(omitted)), tailData} {
+		if _, err := out.Write(part); err != nil {
+			return LogSnapshot{}, err
 		}
 	}
+	return LogSnapshot{Truncated: true, OmittedBytes: omitted}, nil
+}
+
+func requestProgressiveText(ctx context.Context, client Doer, path string, start int64) (*resty.Response, error) {
+	req := client.NewStreamingRequest().
+		SetHeader("Accept", "text/plain").
+		SetQueryParam("start", strconv.FormatInt(start, 10)).
+		SetDoNotParseResponse(true)
+	if ctx != nil {
+		req.SetContext(ctx)
+	}
+	return client.Do(req, http.MethodGet, path, nil)
+}
+
+// ProgressiveLogSize returns the current size of a build's console log from
+// the X-Text-Size header of a progressiveText request, closing the response
+// without reading the log. It returns -1 when Jenkins does not report it.
+func ProgressiveLogSize(ctx context.Context, client Doer, jobPath string, buildNumber int) (int64, error) {
+	encoded := jobpath.Encode(jobPath)
+	if encoded == "" {
+		return -1, errors.New("job path is required")
+	}
+	resp, err := requestProgressiveText(ctx, client, fmt.Sprintf("/%s/%d/logText/progressiveText", encoded, buildNumber), 0)
+	if err != nil {
+		return -1, err
+	}
+	if body := resp.RawBody(); body != nil {
+		_ = body.Close()
+	}
+	if err := CheckResponse(resp, fmt.Sprintf("console log of %s #%d", jobPath, buildNumber)); err != nil {
+		return -1, err
+	}
+	return textSize(resp), nil
+}
 
-	return true, nil
+// textSize is the log offset Jenkins reports in X-Text-Size, or -1.
+func textSize(resp *resty.Response) int64 {
+	size, err := strconv.ParseInt(resp.Header().Get("X-Text-Size"), 10, 64)
+	if err != nil || size < 0 {
+		return -1
+	}
+	return size
 }
 
 func readAndClose(rc io.ReadCloser) ([]byte, error) {
@@ -185,3 +319,12 @@ func readAndClose(rc io.ReadCloser) ([]byte, error) {
 	}
 	return data, err
 }
+
+// discardRawBody closes the unparsed body of a response that is not used.
+func discardRawBody(resp *resty.Response) {
+	if resp == nil || resp.RawBody() == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.RawBody())
+	_ = resp.RawBody().Close()
+}
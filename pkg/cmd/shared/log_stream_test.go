@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+)
+
+// progressiveLog serves text as a finished progressiveText log, honouring
+// ?start= and recording each offset requested.
+func progressiveLog(t *testing.T, text string, starts *[]int64) http.Handler {
+	t.Helper()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/app/7/logText/progressiveText" {
+			http.NotFound(w, r)
+			return
+		}
+		start, _ := strconv.ParseInt(r.URL.Query().Get("start"), 10, 64)
+		*starts = append(*starts, start)
+		w.Header().Set("X-Text-Size", strconv.Itoa(len(text)))
+		_, _ = w.Write([]byte(text[start:]))
+	})
+}
+
+func numberedLines(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "line %04d of the build\n", i)
+	}
+	return b.String()
+}
+
+func TestCollectLogSnapshotSamplesHeadAndTail(t *testing.T) {
+	text := numberedLines(10000) // 23 bytes per line, ~230 KB
+	var starts []int64
+	client := jenkinstest.NewClient(t, progressiveLog(t, text, &starts))
+
+	const maxBytes = 1000
+	var out bytes.Buffer
+	snapshot, err := CollectLogSnapshot(context.Background(), client, "app", 7, maxBytes, &out)
+	require.NoError(t, err)
+	require.True(t, snapshot.Truncated)
+	require.Equal(t, []int64{0, int64(len(text) - maxBytes/2)}, starts)
+
+	head, rest, ok := strings.Cut(out.String(), "--- ")
+	require.True(t, ok, "marker missing from %q", out.String())
+	marker, tail, ok := strings.Cut(rest, " bytes omitted ---\n")
+	require.True(t, ok)
+
+	// 500 bytes hold 21 whole lines of 23 bytes on each side.
+	require.Equal(t, numberedLines(21), head)
+	require.True(t, strings.HasPrefix(tail, "line 9979 of the build\n"), tail[:30])
+	require.True(t, strings.HasSuffix(text, tail))
+	require.Len(t, tail, 21*23)
+
+	omitted := int64(len(text) - len(head) - len(tail))
+	require.Equal(t, omitted, snapshot.OmittedBytes)
+	require.Equal(t, strconv.FormatInt(omitted, 10), marker)
+	require.Equal(t, head+LogOmittedMarker(omitted)+tail, out.String())
+}
+
+func TestCollectLogSnapshotKeepsSmallLogWhole(t *testing.T) {
+	text := numberedLines(10)
+	var starts []int64
+	client := jenkinstest.NewClient(t, progressiveLog(t, text, &starts))
+
+	var out bytes.Buffer
+	snapshot, err := CollectLogSnapshot(context.Background(), client, "app", 7, 1000, &out)
+	require.NoError(t, err)
+	require.Equal(t, LogSnapshot{}, snapshot)
+	require.Equal(t, text, out.String())
+	require.Equal(t, []int64{0}, starts)
+}
@@ -0,0 +1,45 @@
+package shared
+
+import (
+	"sync"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// DefaultConcurrency bounds parallel requests when preferences.max_concurrency
+// is unset.
+const DefaultConcurrency = 4
+
+// Concurrency is how many requests a command fanning out over jobs, runs,
+// nodes, or contexts keeps in flight: preferences.max_concurrency when set,
+// DefaultConcurrency otherwise.
+func Concurrency(f *cmdutil.Factory) int {
+	if f != nil {
+		if cfg, err := f.ResolveConfig(); err == nil && cfg != nil && cfg.Preferences.MaxConcurrency > 0 {
+			return cfg.Preferences.MaxConcurrency
+		}
+	}
+	return DefaultConcurrency
+}
+
+// ForEach calls fn for every index below n, running at most limit calls at
+// once, and returns when all have finished. fn usually stores its outcome at
+// index i of a slice sized n.
+func ForEach(n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestConcurrency(t *testing.T) {
+	require.Equal(t, DefaultConcurrency, Concurrency(nil))
+
+	cfg := &config.Config{}
+	f := &cmdutil.Factory{Config: func() (*config.Config, error) { return cfg, nil }}
+	require.Equal(t, DefaultConcurrency, Concurrency(f), "unset")
+
+	cfg.Preferences.MaxConcurrency = 9
+	f = &cmdutil.Factory{Config: func() (*config.Config, error) { return cfg, nil }}
+	require.Equal(t, 9, Concurrency(f))
+}
+
+func TestForEachBoundsParallelCalls(t *testing.T) {
+	var running, peak int32
+	done := make([]bool, 20)
+	ForEach(len(done), 3, func(i int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if n <= old || atomic.CompareAndSwapInt32(&peak, old, n) {
+				break
+			}
+		}
+		done[i] = true
+		atomic.AddInt32(&running, -1)
+	})
+	require.LessOrEqual(t, peak, int32(3))
+	for i, ok := range done {
+		require.True(t, ok, "index %d", i)
+	}
+}
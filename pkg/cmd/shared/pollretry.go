@@ -0,0 +1,166 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// Failure classes of a poll. Timeouts, lost connections, and server errors
+// are transient and retried; client errors are not.
+const (
+	PollTimeout     = "timeout"
+	PollRefused     = "connection refused"
+	PollConnLost    = "connection lost"
+	PollServerError = "server error"
+	PollClientError = "client error"
+)
+
+const (
+	defaultPollRetries = 5
+	defaultPollBackoff = time.Second
+	// maxPollBackoff caps the doubling wait between retried polls.
+	maxPollBackoff = 30 * time.Second
+)
+
+// PollRetry is the policy of follow loops for failed polls: transient
+// failures are retried up to Max times in a row, waiting Backoff after the
+// first and doubling from there.
+type PollRetry struct {
+	Max     int
+	Backoff time.Duration
+	// Notes receives the one-line retry and recovery notes; nil drops them.
+	Notes io.Writer
+}
+
+// AddPollRetryFlags registers --poll-retries and --poll-backoff.
+func AddPollRetryFlags(cmd *cobra.Command, retry *PollRetry) {
+	cmd.Flags().IntVar(&retry.Max, "poll-retries", defaultPollRetries, "Retry this many failed polls in a row (timeouts, lost connections, 5xx) before giving up")
+	cmd.Flags().DurationVar(&retry.Backoff, "poll-backoff", defaultPollBackoff, "Wait after a failed poll; doubles with each further failure, up to 30s")
+}
+
+// Validate rejects negative retry settings.
+func (p PollRetry) Validate() error {
+	if p.Max < 0 {
+		return NewExitError(2, "--poll-retries must not be negative")
+	}
+	if p.Backoff < 0 {
+		return NewExitError(2, "--poll-backoff must not be negative")
+	}
+	return nil
+}
+
+// WithNotes returns p writing its notes to w, or nowhere when cmd has
+// --quiet set.
+func (p PollRetry) WithNotes(cmd *cobra.Command, w io.Writer) PollRetry {
+	if cmdutil.Quiet(cmd) {
+		w = nil
+	}
+	p.Notes = w
+	return p
+}
+
+// Poller applies a PollRetry to the polls of one loop.
+type Poller struct {
+	policy   PollRetry
+	failures int
+}
+
+// Poller starts counting failures for one loop.
+func (p PollRetry) Poller() *Poller {
+	return &Poller{policy: p}
+}
+
+// Observe records the outcome of a poll of subject. It returns how long to
+// wait before polling again when the poll failed transiently, or an error
+// when the loop should stop: a client error (mapped like CheckResponse), a
+// failure that is not a network or server problem, or one transient failure
+// too many. A successful poll after failures prints a recovery note.
+func (p *Poller) Observe(resp *resty.Response, err error, subject string) (time.Duration, error) {
+	class, transient := ClassifyPollFailure(resp, err)
+	if class == "" {
+		if p.failures > 0 {
+			p.note("connection restored after %d failed poll(s)", p.failures)
+			p.failures = 0
+		}
+		return 0, nil
+	}
+
+	cause := err
+	if cause == nil {
+		cause = CheckResponse(resp, subject)
+	}
+	if !transient {
+		return 0, cause
+	}
+
+	p.failures++
+	if p.failures > p.policy.Max {
+		return 0, NewExitError(1, fmt.Sprintf("giving up after %d failed polls of %s: %v", p.failures, subject, cause))
+	}
+	label := class
+	if class == PollServerError {
+		label = fmt.Sprintf("%s (%s)", class, ResponseStatus(resp))
+	}
+	p.note("%s, retrying (%d/%d)…", label, p.failures, p.policy.Max)
+
+	wait := p.policy.Backoff
+	for i := 1; i < p.failures && wait < maxPollBackoff; i++ {
+		wait *= 2
+	}
+	if wait > maxPollBackoff {
+		wait = maxPollBackoff
+	}
+	return wait, nil
+}
+
+func (p *Poller) note(format string, args ...any) {
+	if p.policy.Notes != nil {
+		_, _ = fmt.Fprintf(p.policy.Notes, format+"\n", args...)
+	}
+}
+
+// ClassifyPollFailure names the failure of a poll and whether it is
+// transient. A successful poll has an empty class. Cancellation and errors
+// that are not network failures, such as TLS verification, are not
+// transient.
+func ClassifyPollFailure(resp *resty.Response, err error) (string, bool) {
+	if err != nil {
+		var netErr net.Error
+		switch {
+		case errors.Is(err, context.Canceled):
+			return PollClientError, false
+		case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+			return PollTimeout, true
+		case errors.Is(err, syscall.ECONNREFUSED):
+			return PollRefused, true
+		case errors.Is(err, syscall.ECONNRESET), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, syscall.EPIPE):
+			return PollConnLost, true
+		}
+		var opErr *net.OpError
+		var dnsErr *net.DNSError
+		if errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+			return PollConnLost, true
+		}
+		return PollClientError, false
+	}
+	if resp == nil {
+		return "", false
+	}
+	switch status := resp.StatusCode(); {
+	case status >= 500:
+		return PollServerError, true
+	case status >= 400:
+		return PollClientError, false
+	}
+	return "", false
+}
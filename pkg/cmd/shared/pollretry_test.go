@@ -0,0 +1,98 @@
+package shared
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+)
+
+func statusResponse(code int) *resty.Response {
+	return &resty.Response{RawResponse: &http.Response{StatusCode: code, Status: http.StatusText(code)}}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyPollFailure(t *testing.T) {
+	requestErr := func(err error) error {
+		return &jenkins.RequestError{Method: http.MethodGet, URL: "https://jenkins.example.com/job/app/1/api/json", Err: err}
+	}
+	tests := []struct {
+		name      string
+		resp      *resty.Response
+		err       error
+		class     string
+		transient bool
+	}{
+		{name: "ok", resp: statusResponse(http.StatusOK)},
+		{name: "not modified", resp: statusResponse(http.StatusNotModified)},
+		{name: "timeout", err: requestErr(timeoutError{}), class: PollTimeout, transient: true},
+		{name: "deadline", err: requestErr(context.DeadlineExceeded), class: PollTimeout, transient: true},
+		{name: "refused", err: requestErr(&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}), class: PollRefused, transient: true},
+		{name: "reset", err: requestErr(&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}), class: PollConnLost, transient: true},
+		{name: "eof", err: requestErr(io.ErrUnexpectedEOF), class: PollConnLost, transient: true},
+		{name: "server error", resp: statusResponse(http.StatusBadGateway), class: PollServerError, transient: true},
+		{name: "not found", resp: statusResponse(http.StatusNotFound), class: PollClientError},
+		{name: "cancelled", err: requestErr(context.Canceled), class: PollClientError},
+		{name: "other error", err: requestErr(errors.New("x509: certificate signed by unknown authority")), class: PollClientError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, transient := ClassifyPollFailure(tt.resp, tt.err)
+			require.Equal(t, tt.class, class)
+			require.Equal(t, tt.transient, transient)
+		})
+	}
+}
+
+func TestPollerBacksOffAndGivesUp(t *testing.T) {
+	var notes bytes.Buffer
+	poller := PollRetry{Max: 3, Backoff: 10 * time.Second, Notes: &notes}.Poller()
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	var waits []time.Duration
+	for i := 0; i < 3; i++ {
+		wait, err := poller.Observe(nil, refused, "run app #1")
+		require.NoError(t, err)
+		waits = append(waits, wait)
+	}
+	require.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 30 * time.Second}, waits)
+
+	wait, err := poller.Observe(statusResponse(http.StatusOK), nil, "run app #1")
+	require.NoError(t, err)
+	require.Zero(t, wait)
+	require.Equal(t, "connection refused, retrying (1/3)…\n"+
+		"connection refused, retrying (2/3)…\n"+
+		"connection refused, retrying (3/3)…\n"+
+		"connection restored after 3 failed poll(s)\n", notes.String())
+
+	// The count starts over after a successful poll.
+	for i := 0; i < 3; i++ {
+		_, err := poller.Observe(statusResponse(http.StatusServiceUnavailable), nil, "run app #1")
+		require.NoError(t, err)
+	}
+	_, err = poller.Observe(statusResponse(http.StatusServiceUnavailable), nil, "run app #1")
+	require.Equal(t, 1, ExitCode(err))
+	require.ErrorContains(t, err, "giving up after 4 failed polls of run app #1")
+}
+
+func TestPollerMapsClientErrors(t *testing.T) {
+	poller := PollRetry{Max: 3}.Poller()
+	_, err := poller.Observe(statusResponse(http.StatusForbidden), nil, "run app #1")
+	require.Equal(t, 5, ExitCode(err))
+}
@@ -0,0 +1,35 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// QueueItemLocation is the location of queue item id, relative to the
+// controller root.
+func QueueItemLocation(id int64) string {
+	return fmt.Sprintf("/queue/item/%d/", id)
+}
+
+// FetchQueueItem decodes the queue item at location, either a path from
+// QueueItemLocation or the Location header of a trigger response, into v.
+// tree limits the fields when set. Jenkins forgets items a few minutes after
+// they start, which CheckResponse reports as exit code 3.
+func FetchQueueItem(ctx context.Context, client Doer, location, tree string, v any) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	location = strings.TrimSuffix(strings.TrimSpace(location), "/")
+	req := client.NewRequest().SetContext(ctx)
+	if tree != "" {
+		req.SetQueryParam("tree", tree)
+	}
+	resp, err := client.Do(req, http.MethodGet, location+"/api/json", v)
+	if err != nil {
+		return err
+	}
+	return CheckResponse(resp, "queue item "+path.Base(location))
+}
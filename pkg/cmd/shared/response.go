@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-resty/resty/v2"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// CheckResponse maps Jenkins HTTP failures to the CLI exit code table.
+// subject names the resource in messages, e.g. "folder team/app". Messages
+// end with the request that failed, credentials redacted.
+func CheckResponse(resp *resty.Response, subject string) error {
+	if resp == nil {
+		return nil
+	}
+
+	status := resp.StatusCode()
+	if status < 400 {
+		return nil
+	}
+
+	var (
+		code int
+		msg  string
+	)
+	switch status {
+	case http.StatusNotFound:
+		code, msg = 3, fmt.Sprintf("%s not found", subject)
+	case http.StatusUnauthorized:
+		code, msg = 4, fmt.Sprintf("authentication failed for %s: %s (run `jk auth login` to refresh the token)", subject, ResponseStatus(resp))
+	case http.StatusForbidden:
+		code, msg = 5, fmt.Sprintf("permission denied for %s: %s", subject, ResponseStatus(resp))
+	default:
+		msg = fmt.Sprintf("%s: %s", subject, ResponseStatus(resp))
+	}
+
+	var request *cmdutil.RequestInfo
+	if method, url, ok := jenkins.ResponseRequest(resp); ok {
+		request = &cmdutil.RequestInfo{Method: method, URL: url}
+		msg = fmt.Sprintf("%s (%s %s)", msg, method, url)
+	}
+
+	if code == 0 {
+		return &cmdutil.RequestError{Err: errors.New(msg), Request: request}
+	}
+	return &cmdutil.ExitError{Code: code, Msg: msg, Request: request}
+}
+
+// ResponseStatus is resp's status line followed, for HTML error pages, by the
+// message Jenkins put on the page, e.g. "400 Bad Request: A job already
+// exists with the name ‘app’".
+func ResponseStatus(resp *resty.Response) string {
+	status := resp.Status()
+	if message := jenkins.HTMLErrorMessage(resp); message != "" {
+		return status + ": " + message
+	}
+	return status
+}
@@ -0,0 +1,135 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// ResultIssue is one warning or error in a Result.
+type ResultIssue struct {
+	// Code is the exit code the failure would have had on its own: 3 not
+	// found, 4 auth, 5 permission, 1 otherwise. Warnings use it the same way.
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	// Target is the job, file, or node the issue is about.
+	Target string `json:"target,omitempty"`
+}
+
+// ResultSummary counts targets by outcome.
+type ResultSummary struct {
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped"`
+}
+
+// Result is the envelope of commands that act on many targets and keep
+// going when some of them fail. Commands embed it in their output next to
+// their items, so the JSON reads
+// {schemaVersion, items, warnings, errors, summary}.
+type Result struct {
+	Warnings []ResultIssue `json:"warnings"`
+	Errors   []ResultIssue `json:"errors"`
+	Summary  ResultSummary `json:"summary"`
+}
+
+// NewResult returns an empty Result whose lists encode as [] rather than null.
+func NewResult() Result {
+	return Result{Warnings: []ResultIssue{}, Errors: []ResultIssue{}}
+}
+
+// Succeed counts a target that was processed.
+func (r *Result) Succeed() {
+	r.Summary.Succeeded++
+}
+
+// Skip counts a target that was left alone, with a warning saying why.
+func (r *Result) Skip(target string, code int, message string) {
+	r.Summary.Skipped++
+	r.Warn(target, code, message)
+}
+
+// Warn records a warning that does not affect the outcome.
+func (r *Result) Warn(target string, code int, message string) {
+	r.Warnings = append(r.Warnings, ResultIssue{Code: code, Message: message, Target: target})
+}
+
+// Fail counts a target that failed with err.
+func (r *Result) Fail(target string, err error) {
+	r.Summary.Failed++
+	r.Errors = append(r.Errors, ResultIssue{Code: ExitCode(err), Message: err.Error(), Target: target})
+}
+
+// ExitError derives the exit code from the outcome: nil when nothing failed;
+// when every target failed, the failures' shared exit code (1 if they
+// differ); otherwise exit 1 for a partial failure, or nil with okOnPartial.
+// noun names the targets in the message, e.g. "jobs". The individual errors
+// have been reported already, so the message only summarises.
+func (r Result) ExitError(noun string, okOnPartial bool) error {
+	failed := r.Summary.Failed
+	if failed == 0 {
+		return nil
+	}
+	total := r.Summary.Succeeded + failed + r.Summary.Skipped
+	if r.Summary.Succeeded > 0 {
+		if okOnPartial {
+			return nil
+		}
+		return NewExitError(1, fmt.Sprintf("%d of %d %s failed (use --ok-on-partial to exit 0)", failed, total, noun))
+	}
+
+	code := 0
+	for _, issue := range r.Errors {
+		if code != 0 && issue.Code != code {
+			code = 1
+			break
+		}
+		code = issue.Code
+	}
+	if code == 0 {
+		code = 1
+	}
+	if failed == 1 && len(r.Errors) == 1 {
+		return NewExitError(code, r.Errors[0].Message)
+	}
+	return NewExitError(code, fmt.Sprintf("all %d %s failed", failed, noun))
+}
+
+// WriteIssues prints warnings and errors one per line on cmd's stderr, for
+// human output. --quiet drops the warnings but not the errors.
+func (r Result) WriteIssues(cmd *cobra.Command) {
+	for _, issue := range r.Warnings {
+		cmdutil.Warnf(cmd, "%s", issue.Message)
+	}
+	for _, issue := range r.Errors {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "error: %s\n", issue.Message)
+	}
+}
+
+// ErrorMessage is the message of the ExitError in err's chain, without the
+// context wrapped around it, or err's own message when there is none.
+func ErrorMessage(err error) string {
+	var exitErr *cmdutil.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Msg
+	}
+	return err.Error()
+}
+
+// ExitCode is the exit code err would end the command with.
+func ExitCode(err error) int {
+	var exitErr *cmdutil.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
+// AddOKOnPartialFlag registers --ok-on-partial for commands returning a
+// Result.
+func AddOKOnPartialFlag(cmd *cobra.Command, ok *bool) {
+	cmd.Flags().BoolVar(ok, "ok-on-partial", false, "Exit 0 when some targets fail as long as at least one succeeds")
+}
@@ -0,0 +1,111 @@
+package shared
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func resultExitCode(t *testing.T, err error) int {
+	t.Helper()
+	if err == nil {
+		return 0
+	}
+	var exitErr *cmdutil.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	return exitErr.Code
+}
+
+func TestResultExitError(t *testing.T) {
+	notFound := NewExitError(3, "job team/a not found")
+	denied := NewExitError(5, "permission denied for job team/b")
+
+	tests := []struct {
+		name        string
+		build       func(r *Result)
+		okOnPartial bool
+		code        int
+		message     string
+	}{
+		{
+			name:  "all ok",
+			build: func(r *Result) { r.Succeed(); r.Succeed() },
+		},
+		{
+			name:  "only skipped and warnings",
+			build: func(r *Result) { r.Succeed(); r.Skip("team/c", 3, "job team/c not found") },
+		},
+		{
+			name:    "partial failure",
+			build:   func(r *Result) { r.Succeed(); r.Fail("team/a", notFound) },
+			code:    1,
+			message: "1 of 2 jobs failed (use --ok-on-partial to exit 0)",
+		},
+		{
+			name:        "partial failure tolerated",
+			build:       func(r *Result) { r.Succeed(); r.Fail("team/a", notFound) },
+			okOnPartial: true,
+		},
+		{
+			name:    "single failure keeps its message",
+			build:   func(r *Result) { r.Fail("team/a", notFound) },
+			code:    3,
+			message: "job team/a not found",
+		},
+		{
+			name:        "all failed with one cause",
+			build:       func(r *Result) { r.Fail("team/a", notFound); r.Fail("team/b", NewExitError(3, "job team/b not found")) },
+			okOnPartial: true,
+			code:        3,
+			message:     "all 2 jobs failed",
+		},
+		{
+			name:    "all failed with mixed causes",
+			build:   func(r *Result) { r.Fail("team/a", notFound); r.Fail("team/b", denied) },
+			code:    1,
+			message: "all 2 jobs failed",
+		},
+		{
+			name:    "plain errors exit 1",
+			build:   func(r *Result) { r.Fail("team/a", errors.New("boom")); r.Fail("team/b", errors.New("bang")) },
+			code:    1,
+			message: "all 2 jobs failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewResult()
+			tt.build(&result)
+			err := result.ExitError("jobs", tt.okOnPartial)
+			require.Equal(t, tt.code, resultExitCode(t, err))
+			if tt.message != "" {
+				require.EqualError(t, err, tt.message)
+			}
+		})
+	}
+}
+
+func TestResultEmbedsIntoOutput(t *testing.T) {
+	output := struct {
+		SchemaVersion string   `json:"schemaVersion"`
+		Items         []string `json:"items"`
+		Result
+	}{SchemaVersion: "1.0", Items: []string{"a"}, Result: NewResult()}
+	output.Succeed()
+	output.Fail("b", NewExitError(4, "authentication failed"))
+
+	data, err := json.Marshal(output)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"schemaVersion": "1.0",
+		"items": ["a"],
+		"warnings": [],
+		"errors": [{"code": 4, "message": "authentication failed", "target": "b"}],
+		"summary": {"succeeded": 1, "failed": 1, "skipped": 0}
+	}`, string(data))
+}
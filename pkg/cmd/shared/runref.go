@@ -0,0 +1,173 @@
+package shared
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+// RunRefScheme prefixes the shareable run references printed by
+// `jk run link`.
+const RunRefScheme = "jk://"
+
+// RunRef names a build on a configured context.
+type RunRef struct {
+	Context string
+	// JobPath is absolute; it never goes through the default folder.
+	JobPath string
+	Number  int64
+}
+
+// String formats the reference as jk://<context>/<jobPath>/<number>, each
+// segment path-escaped.
+func (r RunRef) String() string {
+	parts := []string{url.PathEscape(r.Context)}
+	for _, segment := range jobpath.Segments(r.JobPath) {
+		parts = append(parts, url.PathEscape(segment))
+	}
+	parts = append(parts, strconv.FormatInt(r.Number, 10))
+	return RunRefScheme + strings.Join(parts, "/")
+}
+
+// ParseRunRef accepts a jk:// reference or a Jenkins build URL. URLs are
+// matched against the configured contexts by host and context path; the
+// longest matching base wins. Classic job URLs (with or without views,
+// trailing slashes, /console, /display/redirect, and similar suffixes) and
+// Blue Ocean run URLs are understood. Failures are exit code 2.
+func ParseRunRef(raw string, cfg *config.Config) (RunRef, error) {
+	raw = strings.TrimSpace(raw)
+	if strings.HasPrefix(strings.ToLower(raw), RunRefScheme) {
+		return parseJKRef(raw[len(RunRefScheme):], cfg)
+	}
+	return parseRunURL(raw, cfg)
+}
+
+func parseJKRef(rest string, cfg *config.Config) (RunRef, error) {
+	invalid := NewExitError(2, fmt.Sprintf("invalid run reference %q: want %s<context>/<jobPath>/<number>", RunRefScheme+rest, RunRefScheme))
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 3 {
+		return RunRef{}, invalid
+	}
+	number, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil || number <= 0 {
+		return RunRef{}, invalid
+	}
+	decoded := make([]string, 0, len(parts)-1)
+	for _, part := range parts[:len(parts)-1] {
+		segment, err := url.PathUnescape(part)
+		if err != nil || segment == "" {
+			return RunRef{}, invalid
+		}
+		decoded = append(decoded, segment)
+	}
+
+	ref := RunRef{Context: decoded[0], JobPath: strings.Join(decoded[1:], "/"), Number: number}
+	if cfg == nil || cfg.Contexts[ref.Context] == nil {
+		return RunRef{}, NewExitError(2, fmt.Sprintf("context %q from the run reference is not configured", ref.Context))
+	}
+	return ref, nil
+}
+
+func parseRunURL(raw string, cfg *config.Config) (RunRef, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return RunRef{}, NewExitError(2, fmt.Sprintf("%q is neither a %s reference nor a Jenkins URL", raw, RunRefScheme))
+	}
+
+	contextName, rest := matchContextURL(u, cfg)
+	if contextName == "" {
+		return RunRef{}, NewExitError(2, fmt.Sprintf("no configured context matches %s://%s", u.Scheme, u.Host))
+	}
+
+	jobPath, trailing := decodeBlueOceanPath(rest)
+	if jobPath == "" {
+		jobPath, trailing = jobpath.Decode(rest)
+	}
+	if jobPath == "" {
+		return RunRef{}, NewExitError(2, fmt.Sprintf("%s does not point at a job", raw))
+	}
+	if len(trailing) == 0 {
+		return RunRef{}, NewExitError(2, fmt.Sprintf("%s names job %s but no build number", raw, jobPath))
+	}
+	number, err := strconv.ParseInt(trailing[0], 10, 64)
+	if err != nil || number <= 0 {
+		return RunRef{}, NewExitError(2, fmt.Sprintf("%s names job %s but %q is not a build number", raw, jobPath, trailing[0]))
+	}
+	return RunRef{Context: contextName, JobPath: jobPath, Number: number}, nil
+}
+
+// matchContextURL finds the context whose URL has the same host and a path
+// that prefixes u's, preferring the longest path. The scheme is ignored so
+// an http:// link still matches an https:// context. It returns the escaped
+// path below the context's base.
+func matchContextURL(u *url.URL, cfg *config.Config) (string, string) {
+	if cfg == nil {
+		return "", ""
+	}
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	host := normalizedHost(u)
+	path := u.EscapedPath()
+	bestName, bestBase, rest := "", -1, ""
+	for _, name := range names {
+		ctx := cfg.Contexts[name]
+		if ctx == nil {
+			continue
+		}
+		base, err := url.Parse(strings.TrimSpace(ctx.URL))
+		if err != nil || !strings.EqualFold(normalizedHost(base), host) {
+			continue
+		}
+		basePath := strings.TrimSuffix(base.EscapedPath(), "/")
+		if path != basePath && !strings.HasPrefix(path, basePath+"/") {
+			continue
+		}
+		if len(basePath) > bestBase {
+			bestName, bestBase, rest = name, len(basePath), strings.TrimPrefix(path, basePath)
+		}
+	}
+	return bestName, rest
+}
+
+func normalizedHost(u *url.URL) string {
+	host := strings.ToLower(u.Hostname())
+	switch port := u.Port(); {
+	case port == "", port == "443" && u.Scheme == "https", port == "80" && u.Scheme == "http":
+		return host
+	default:
+		return host + ":" + port
+	}
+}
+
+// decodeBlueOceanPath handles /blue/organizations/jenkins/<pipeline>/detail/
+// <name>/<number>/..., where pipeline is the full name with "/" escaped and
+// name is the branch for multibranch projects (otherwise the job's own name).
+func decodeBlueOceanPath(escapedPath string) (string, []string) {
+	segments := strings.Split(strings.Trim(escapedPath, "/"), "/")
+	if len(segments) < 6 || segments[0] != "blue" || segments[1] != "organizations" || segments[4] != "detail" {
+		return "", nil
+	}
+	pipeline, err := url.PathUnescape(segments[3])
+	if err != nil {
+		return "", nil
+	}
+	name, err := url.PathUnescape(segments[5])
+	if err != nil {
+		return "", nil
+	}
+	names := strings.Split(strings.Trim(pipeline, "/"), "/")
+	if name = strings.ReplaceAll(name, "/", "%2F"); name != names[len(names)-1] {
+		names = append(names, name)
+	}
+	return strings.Join(names, "/"), segments[6:]
+}
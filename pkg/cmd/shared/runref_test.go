@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+func TestParseRunRef(t *testing.T) {
+	cfg := &config.Config{Contexts: map[string]*config.Context{
+		"prod":    {URL: "https://ci.example.com"},
+		"legacy":  {URL: "https://ci.example.com/jenkins/"},
+		"staging": {URL: "http://staging.example.com:8080"},
+	}}
+
+	tests := []struct {
+		name   string
+		input  string
+		expect RunRef
+	}{
+		{"jk reference", "jk://prod/team/app/42", RunRef{"prod", "team/app", 42}},
+		{"jk reference escaped", "jk://prod/team/feature%252Flogin/3", RunRef{"prod", "team/feature%2Flogin", 3}},
+		{"jk reference spaces", "  jk://prod/folder%20name/app/1/ ", RunRef{"prod", "folder name/app", 1}},
+		{"build url", "https://ci.example.com/job/team/job/app/42", RunRef{"prod", "team/app", 42}},
+		{"trailing slash", "https://ci.example.com/job/team/job/app/42/", RunRef{"prod", "team/app", 42}},
+		{"console", "https://ci.example.com/job/team/job/app/42/console", RunRef{"prod", "team/app", 42}},
+		{"console full", "https://ci.example.com/job/team/job/app/42/consoleFull", RunRef{"prod", "team/app", 42}},
+		{"display redirect", "https://ci.example.com/job/team/job/app/42/display/redirect", RunRef{"prod", "team/app", 42}},
+		{"test report", "https://ci.example.com/job/app/42/testReport/com.example/FooTest/", RunRef{"prod", "app", 42}},
+		{"query and fragment", "https://ci.example.com/job/app/42/console?foo=bar#footer", RunRef{"prod", "app", 42}},
+		{"view all", "https://ci.example.com/view/All/job/team/job/app/42/", RunRef{"prod", "team/app", 42}},
+		{"nested views", "https://ci.example.com/view/Team/job/team/view/Nightly/job/app/9/", RunRef{"prod", "team/app", 9}},
+		{"context path wins", "https://ci.example.com/jenkins/job/app/5/", RunRef{"legacy", "app", 5}},
+		{"scheme ignored", "http://ci.example.com/job/app/5/", RunRef{"prod", "app", 5}},
+		{"host case ignored", "https://CI.Example.com:443/job/app/5/", RunRef{"prod", "app", 5}},
+		{"port kept", "http://staging.example.com:8080/job/app/6/", RunRef{"staging", "app", 6}},
+		{"multibranch double escaped", "https://ci.example.com/job/repo/job/feature%252Flogin/7/", RunRef{"prod", "repo/feature%2Flogin", 7}},
+		{"multibranch single escaped", "https://ci.example.com/job/repo/job/feature%2Flogin/7/", RunRef{"prod", "repo/feature%2Flogin", 7}},
+		{"blue ocean", "https://ci.example.com/blue/organizations/jenkins/team%2Fapp/detail/app/12/pipeline", RunRef{"prod", "team/app", 12}},
+		{"blue ocean branch", "https://ci.example.com/blue/organizations/jenkins/team%2Frepo/detail/feature%252Flogin/4/pipeline/", RunRef{"prod", "team/repo/feature%2Flogin", 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseRunRef(tt.input, cfg)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, ref)
+		})
+	}
+}
+
+func TestParseRunRefErrors(t *testing.T) {
+	cfg := &config.Config{Contexts: map[string]*config.Context{
+		"prod": {URL: "https://ci.example.com"},
+	}}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown context", "jk://qa/team/app/1"},
+		{"reference without job", "jk://prod/1"},
+		{"reference without number", "jk://prod/team/app"},
+		{"not a url", "team/app#42"},
+		{"unmatched host", "https://other.example.com/job/app/1/"},
+		{"job url without build", "https://ci.example.com/job/team/job/app/"},
+		{"permalink", "https://ci.example.com/job/app/lastBuild/"},
+		{"not a job", "https://ci.example.com/manage/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRunRef(tt.input, cfg)
+			require.Error(t, err)
+			var exitErr *cmdutil.ExitError
+			require.ErrorAs(t, err, &exitErr)
+			require.Equal(t, 2, exitErr.Code)
+		})
+	}
+}
+
+func TestRunRefStringRoundTrip(t *testing.T) {
+	cfg := &config.Config{Contexts: map[string]*config.Context{"prod": {URL: "https://ci.example.com"}}}
+	ref := RunRef{Context: "prod", JobPath: "folder name/repo/feature%2Flogin", Number: 8}
+
+	require.Equal(t, "jk://prod/folder%20name/repo/feature%252Flogin/8", ref.String())
+	parsed, err := ParseRunRef(ref.String(), cfg)
+	require.NoError(t, err)
+	require.Equal(t, ref, parsed)
+}
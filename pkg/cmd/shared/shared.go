@@ -1,21 +1,29 @@
 package shared
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
 	"github.com/avivsinai/jenkins-cli/internal/config"
 	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	jklog "github.com/avivsinai/jenkins-cli/internal/log"
 	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
+// ResolveContextName returns the context selected by --context, JK_CONTEXT,
+// the context rule for the command's job path argument, or the active
+// context, in that order. Names given by flag or environment match a stored
+// context case-insensitively, with a warning when the casing differs.
 func ResolveContextName(cmd *cobra.Command, cfg *config.Config) (string, error) {
 	if cmd == nil {
 		return "", errors.New("command is nil")
@@ -28,14 +36,22 @@ func ResolveContextName(cmd *cobra.Command, cfg *config.Config) (string, error)
 		}
 		name = strings.TrimSpace(name)
 		if name != "" {
-			return name, nil
+			return storedContextName(cmd, cfg, name), nil
 		}
 	}
 
 	if value, ok := os.LookupEnv("JK_CONTEXT"); ok {
 		name := strings.TrimSpace(value)
 		if name != "" {
-			return name, nil
+			return storedContextName(cmd, cfg, name), nil
+		}
+	}
+
+	if jobPath, ok := JobPathArg(cmd); ok && cfg != nil {
+		if rule, ok := ContextRuleFor(cmd, cfg, jobPath); ok {
+			jklog.L().Debug().Str("jobPath", jobPath).Str("prefix", rule.Prefix).Msgf("context rule selected context %s", rule.Context)
+			contextRuleUsed.Store(cmd, rule)
+			return rule.Context, nil
 		}
 	}
 
@@ -46,6 +62,42 @@ func ResolveContextName(cmd *cobra.Command, cfg *config.Config) (string, error)
 	return name, nil
 }
 
+// contextRuleUsed records the context rule that picked each command's
+// context, so JSON output can report it.
+var contextRuleUsed sync.Map
+
+// contextRuleOutput is the contextRule field of JSON object payloads.
+type contextRuleOutput struct {
+	Prefix  string `json:"prefix"`
+	Context string `json:"context"`
+}
+
+// contextCaseWarned records the commands already warned about a context
+// name's casing, so resolving the context twice warns once.
+var contextCaseWarned sync.Map
+
+// storedContextName maps name to the context it matches regardless of case.
+func storedContextName(cmd *cobra.Command, cfg *config.Config, name string) string {
+	if cfg == nil {
+		return name
+	}
+	stored, ok := cfg.LookupContext(name)
+	if !ok || stored == name {
+		return name
+	}
+	if _, warned := contextCaseWarned.LoadOrStore(cmd, true); !warned {
+		cmdutil.Warnf(cmd, "context %q matched %q; context names are case-insensitive", name, stored)
+	}
+	return stored
+}
+
+// Output formats selected by the root --json and --yaml flags.
+const (
+	FormatHuman = "human"
+	FormatJSON  = "json"
+	FormatYAML  = "yaml"
+)
+
 func WantsJSON(cmd *cobra.Command) bool {
 	v, _ := cmd.Root().PersistentFlags().GetBool("json")
 	return v
@@ -56,16 +108,73 @@ func WantsYAML(cmd *cobra.Command) bool {
 	return v
 }
 
+// OutputFormat reports the format PrintOutput writes: FormatJSON, FormatYAML,
+// or FormatHuman.
+func OutputFormat(cmd *cobra.Command) string {
+	switch {
+	case WantsJSON(cmd):
+		return FormatJSON
+	case WantsYAML(cmd):
+		return FormatYAML
+	default:
+		return FormatHuman
+	}
+}
+
+// ValidateOutputFlags rejects --json combined with --yaml instead of letting
+// one silently win.
+func ValidateOutputFlags(cmd *cobra.Command) error {
+	if WantsJSON(cmd) && WantsYAML(cmd) {
+		return NewExitError(2, "--json and --yaml cannot be used together")
+	}
+	return nil
+}
+
+// ActionResult is the structured output of commands whose human output is a
+// single confirmation line, so --json and --yaml always yield a document.
+type ActionResult struct {
+	Action string `json:"action"`
+	Target string `json:"target"`
+	Status string `json:"status"`
+}
+
+// Statuses reported in ActionResult.
+const (
+	ActionDone      = "done"
+	ActionRequested = "requested"
+	ActionDeclined  = "declined"
+)
+
+// PrintAction writes result as JSON or YAML, or the formatted message for
+// humans.
+func PrintAction(cmd *cobra.Command, result ActionResult, format string, args ...any) error {
+	return PrintOutput(cmd, result, func() error {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), format+"\n", args...)
+		return nil
+	})
+}
+
 func PrintOutput(cmd *cobra.Command, data interface{}, human func() error) error {
-	if WantsJSON(cmd) {
-		encoded, err := json.MarshalIndent(data, "", "  ")
+	switch OutputFormat(cmd) {
+	case FormatJSON:
+		encoded, err := json.Marshal(data)
 		if err != nil {
 			return err
 		}
-		_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(encoded))
+		if value, ok := contextRuleUsed.Load(cmd); ok {
+			rule := value.(config.ContextRule)
+			encoded, _ = attachField(encoded, "contextRule", contextRuleOutput{Prefix: rule.Prefix, Context: rule.Context})
+		}
+		if jenkins.TimingsEnabled() {
+			encoded = attachTimings(encoded)
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, encoded, "", "  "); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), indented.String())
 		return nil
-	}
-	if WantsYAML(cmd) {
+	case FormatYAML:
 		encoded, err := yaml.Marshal(data)
 		if err != nil {
 			return err
@@ -76,6 +185,39 @@ func PrintOutput(cmd *cobra.Command, data interface{}, human func() error) error
 	return human()
 }
 
+// attachTimings appends a "timings" array to a JSON object payload. Non-object
+// payloads are returned unchanged and the timings fall back to stderr.
+func attachTimings(encoded []byte) []byte {
+	out, ok := attachField(encoded, "timings", jenkins.TimingsSnapshot())
+	if ok {
+		jenkins.MarkTimingsReported()
+	}
+	return out
+}
+
+// attachField appends name: value to a JSON object payload. Non-object
+// payloads are returned unchanged and ok is false.
+func attachField(encoded []byte, name string, value any) (out []byte, ok bool) {
+	trimmed := bytes.TrimSpace(encoded)
+	if len(trimmed) < 2 || trimmed[0] != '{' || trimmed[len(trimmed)-1] != '}' {
+		return encoded, false
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return encoded, false
+	}
+
+	var buf bytes.Buffer
+	buf.Write(trimmed[:len(trimmed)-1])
+	if len(bytes.TrimSpace(trimmed[1:len(trimmed)-1])) > 0 {
+		buf.WriteByte(',')
+	}
+	buf.WriteString(strconv.Quote(name) + ":")
+	buf.Write(data)
+	buf.WriteByte('}')
+	return buf.Bytes(), true
+}
+
 func JenkinsClient(cmd *cobra.Command, f *cmdutil.Factory) (*jenkins.Client, error) {
 	cfg, err := f.ResolveConfig()
 	if err != nil {
@@ -87,10 +229,14 @@ func JenkinsClient(cmd *cobra.Command, f *cmdutil.Factory) (*jenkins.Client, err
 		return nil, err
 	}
 
-	ctx := cmd.Context()
-	if ctx == nil {
-		ctx = context.Background()
-	}
+	return f.Client(CommandContext(cmd), name)
+}
 
-	return f.Client(ctx, name)
+// CommandContext returns the command's context, or the background context
+// when none was set.
+func CommandContext(cmd *cobra.Command) context.Context {
+	if ctx := cmd.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
 }
@@ -1,12 +1,14 @@
 package shared
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 
 	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
 func TestResolveContextNamePrecedence(t *testing.T) {
@@ -21,19 +23,24 @@ func TestResolveContextNamePrecedence(t *testing.T) {
 					URL: "https://jenkins.other.com",
 				},
 			},
+			ContextRules: []config.ContextRule{{Prefix: "team-a/", Context: "other"}},
 		}
 	}
 
-	newCommand := func() *cobra.Command {
-		cmd := &cobra.Command{}
+	newCommand := func(t *testing.T, jobPath string) *cobra.Command {
+		cmd := &cobra.Command{Use: "view <jobPath>"}
 		cmd.Flags().String("context", "", "")
+		cmdutil.SetJobPathArg(cmd, 0)
+		require.NoError(t, cmd.Flags().Parse([]string{jobPath}))
 		return cmd
 	}
 
 	tests := []struct {
-		name     string
-		setup    func(*testing.T, *cobra.Command)
-		wantName string
+		name          string
+		jobPath       string
+		defaultFolder string
+		setup         func(*testing.T, *cobra.Command)
+		wantName      string
 	}{
 		{
 			name: "flag overrides env and active",
@@ -60,12 +67,70 @@ func TestResolveContextNamePrecedence(t *testing.T) {
 			},
 			wantName: "active",
 		},
+		{
+			name:    "flag overrides env and rule",
+			jobPath: "team-a/app",
+			setup: func(t *testing.T, cmd *cobra.Command) {
+				t.Helper()
+				t.Setenv("JK_CONTEXT", "env-context")
+				require.NoError(t, cmd.Flags().Set("context", "flag-context"))
+			},
+			wantName: "flag-context",
+		},
+		{
+			name:    "env overrides rule",
+			jobPath: "team-a/app",
+			setup: func(t *testing.T, cmd *cobra.Command) {
+				t.Helper()
+				t.Setenv("JK_CONTEXT", "env-context")
+			},
+			wantName: "env-context",
+		},
+		{
+			name:    "rule overrides active",
+			jobPath: "/team-a/app",
+			setup: func(t *testing.T, cmd *cobra.Command) {
+				t.Helper()
+				t.Setenv("JK_CONTEXT", "")
+			},
+			wantName: "other",
+		},
+		{
+			name:    "active when no rule matches",
+			jobPath: "team-ab/app",
+			setup: func(t *testing.T, cmd *cobra.Command) {
+				t.Helper()
+				t.Setenv("JK_CONTEXT", "")
+			},
+			wantName: "active",
+		},
+		{
+			name:          "rule matches the path under the default folder",
+			jobPath:       "app",
+			defaultFolder: "team-a",
+			setup: func(t *testing.T, cmd *cobra.Command) {
+				t.Helper()
+				t.Setenv("JK_CONTEXT", "")
+			},
+			wantName: "other",
+		},
+		{
+			name:          "leading slash skips the default folder",
+			jobPath:       "/app",
+			defaultFolder: "team-a",
+			setup: func(t *testing.T, cmd *cobra.Command) {
+				t.Helper()
+				t.Setenv("JK_CONTEXT", "")
+			},
+			wantName: "active",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := newCommand()
+			cmd := newCommand(t, tt.jobPath)
 			cfg := newConfig()
+			cfg.Contexts["active"].DefaultFolder = tt.defaultFolder
 
 			if tt.setup != nil {
 				tt.setup(t, cmd)
@@ -77,3 +142,98 @@ func TestResolveContextNamePrecedence(t *testing.T) {
 		})
 	}
 }
+
+func TestJobPathArg(t *testing.T) {
+	cmd := &cobra.Command{Use: "cat <buildNumber> <jobPath>"}
+	cmdutil.SetJobPathArg(cmd, 1)
+	require.NoError(t, cmd.Flags().Parse([]string{"42", " team/app "}))
+	jobPath, ok := JobPathArg(cmd)
+	require.True(t, ok)
+	require.Equal(t, "team/app", jobPath)
+
+	cmd = &cobra.Command{Use: "view <jobPath>"}
+	cmdutil.SetJobPathArg(cmd, 0)
+	_, ok = JobPathArg(cmd)
+	require.False(t, ok, "no argument given")
+
+	cmd = &cobra.Command{Use: "view <jobPath>"}
+	require.NoError(t, cmd.Flags().Parse([]string{"team/app"}))
+	_, ok = JobPathArg(cmd)
+	require.False(t, ok, "the usage line alone does not mark a job path")
+
+	cmd = &cobra.Command{Use: "search"}
+	cmd.Flags().String("folder", "", "")
+	cmd.Flags().StringArray("job", nil, "")
+	cmdutil.SetJobPathFlag(cmd, "job")
+	require.NoError(t, cmd.Flags().Parse([]string{"--folder", "ignored", "--job", "team/app", "--job", "team/web"}))
+	jobPath, ok = JobPathArg(cmd)
+	require.True(t, ok)
+	require.Equal(t, "team/app", jobPath)
+}
+
+func TestPrintOutputReportsContextRule(t *testing.T) {
+	t.Setenv("JK_CONTEXT", "")
+	cfg := &config.Config{ContextRules: []config.ContextRule{{Prefix: "team-a", Context: "team-a"}}}
+	cmd := &cobra.Command{Use: "view <jobPath>"}
+	cmd.Flags().String("context", "", "")
+	cmd.PersistentFlags().Bool("json", true, "")
+	cmdutil.SetJobPathArg(cmd, 0)
+	require.NoError(t, cmd.Flags().Parse([]string{"team-a/app"}))
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+
+	name, err := ResolveContextName(cmd, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "team-a", name)
+	require.NoError(t, PrintOutput(cmd, map[string]string{"jobPath": "team-a/app"}, nil))
+	require.JSONEq(t, `{"jobPath":"team-a/app","contextRule":{"prefix":"team-a","context":"team-a"}}`, stdout.String())
+}
+
+func TestResolveContextNameIgnoresCase(t *testing.T) {
+	cfg := &config.Config{
+		Active: "prod",
+		Contexts: map[string]*config.Context{
+			"prod":    {URL: "https://ci.example.com"},
+			"Staging": {URL: "https://staging.example.com"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		wantName string
+		warning  string
+	}{
+		{name: "exact match", flag: "Staging", wantName: "Staging"},
+		{name: "flag differs by case", flag: "PROD", wantName: "prod", warning: `warning: context "PROD" matched "prod"; context names are case-insensitive` + "\n"},
+		{name: "env differs by case", env: "staging", wantName: "Staging", warning: `warning: context "staging" matched "Staging"; context names are case-insensitive` + "\n"},
+		{name: "unknown names pass through", flag: "Other", wantName: "Other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JK_CONTEXT", tt.env)
+			cmd := &cobra.Command{}
+			cmd.Flags().String("context", "", "")
+			var stderr bytes.Buffer
+			cmd.SetErr(&stderr)
+			if tt.flag != "" {
+				require.NoError(t, cmd.Flags().Set("context", tt.flag))
+			}
+
+			for i := 0; i < 2; i++ {
+				got, err := ResolveContextName(cmd, cfg)
+				require.NoError(t, err)
+				require.Equal(t, tt.wantName, got)
+			}
+			require.Equal(t, tt.warning, stderr.String())
+		})
+	}
+}
+
+func TestAttachTimingsAppendsToObjects(t *testing.T) {
+	require.JSONEq(t, `{"a":1,"timings":[]}`, string(attachTimings([]byte(`{"a":1}`))))
+	require.JSONEq(t, `{"timings":[]}`, string(attachTimings([]byte(`{}`))))
+	require.Equal(t, `[1,2]`, string(attachTimings([]byte(`[1,2]`))))
+}
@@ -5,14 +5,18 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
 )
 
 type TestCase struct {
-	ClassName string  `json:"className"`
-	Name      string  `json:"name"`
-	Status    string  `json:"status"`
-	Duration  float64 `json:"duration"`
+	ClassName  string `json:"className"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"durationMs"`
+	// Duration is in seconds, as Jenkins reports it.
+	//
+	// Deprecated: kept for one release; consumers should read DurationMs.
+	Duration float64 `json:"duration"`
 }
 
 type TestSuite struct {
@@ -27,10 +31,7 @@ type TestReport struct {
 	Suites     []TestSuite `json:"suites"`
 }
 
-func FetchTestReport(client *jenkins.Client, jobPath string, buildNumber int64) (*TestReport, error) {
-	if client == nil {
-		return nil, errors.New("jenkins client is required")
-	}
+func FetchTestReport(client Doer, jobPath string, buildNumber int64) (*TestReport, error) {
 	if jobPath == "" {
 		return nil, errors.New("job path is required")
 	}
@@ -38,7 +39,7 @@ func FetchTestReport(client *jenkins.Client, jobPath string, buildNumber int64)
 		return nil, errors.New("build number must be positive")
 	}
 
-	path := fmt.Sprintf("/%s/%d/testReport/api/json", jenkins.EncodeJobPath(jobPath), buildNumber)
+	path := fmt.Sprintf("/%s/%d/testReport/api/json", jobpath.Encode(jobPath), buildNumber)
 	req := client.NewRequest()
 
 	var report TestReport
@@ -51,5 +52,11 @@ func FetchTestReport(client *jenkins.Client, jobPath string, buildNumber int64)
 		return nil, nil
 	}
 
+	for i := range report.Suites {
+		for j := range report.Suites[i].Cases {
+			c := &report.Suites[i].Cases[j]
+			c.DurationMs = SecondsToMs(c.Duration)
+		}
+	}
 	return &report, nil
 }
@@ -1,11 +1,136 @@
 package shared
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
 
-func DurationString(ms int64) string {
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// relativeTimeCutoff is the age after which RelativeTime falls back to a date;
+// "37d ago" is harder to place than the date itself.
+const relativeTimeCutoff = 30 * 24 * time.Hour
+
+// JSON output follows one convention for time values so consumers never have
+// to guess units: instants are RFC3339 strings in UTC and durations are
+// integer milliseconds in fields ending in "Ms". jk help --json publishes it.
+const (
+	TimestampConvention = "RFC3339 string in UTC (e.g. startTime, queuedAt)"
+	DurationConvention  = "integer milliseconds in a field ending in Ms (e.g. durationMs)"
+)
+
+// DeprecatedTimeFields lists JSON fields that predate the convention, keyed
+// by command and field path. Each is still populated next to its replacement
+// for one release.
+var DeprecatedTimeFields = map[string]string{
+	"log.duration":                          "human-readable string; use durationMs",
+	"queue.inQueueSince":                    "epoch milliseconds; use queuedAt and waitMs",
+	"test report.suites[].cases[].duration": "seconds as a float; use durationMs",
+}
+
+// FormatTimestamp renders Jenkins epoch milliseconds as RFC3339 in UTC, or ""
+// when ms is not set.
+func FormatTimestamp(ms int64) string {
 	if ms <= 0 {
-		return "0s"
+		return ""
+	}
+	return FormatTime(time.UnixMilli(ms))
+}
+
+// FormatTime renders t as RFC3339 in UTC, or "" for the zero time.
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// SecondsToMs converts the fractional seconds Jenkins reports for test
+// durations to whole milliseconds.
+func SecondsToMs(seconds float64) int64 {
+	return int64(math.Round(seconds * 1000))
+}
+
+// DurationWidth is the widest DurationString renders below 100 days
+// ("23h 59m 59s", "10d 23h 59m"); DurationPadded pads to it.
+const DurationWidth = 11
+
+// DurationString renders milliseconds for human output ("850ms", "12.3s",
+// "4m 12s", "1d 3h 46m"); see cmdutil.DurationString, which the progress
+// renderer uses too.
+func DurationString(ms int64) string {
+	return cmdutil.DurationString(ms)
+}
+
+// DurationPadded is DurationString right-aligned to DurationWidth, for
+// table columns that should line up.
+func DurationPadded(ms int64) string {
+	return fmt.Sprintf("%*s", DurationWidth, DurationString(ms))
+}
+
+// RelativeTime renders t relative to now ("just now", "12m ago", "3d ago",
+// "2w ago"), falling back to the calendar date once t is older than ~30 days.
+// Timestamps slightly in the future (clock skew) read as "just now".
+func RelativeTime(t, now time.Time) string {
+	age := now.Sub(t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age/time.Minute))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age/time.Hour))
+	case age < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(age/(24*time.Hour)))
+	case age < relativeTimeCutoff:
+		return fmt.Sprintf("%dw ago", int(age/(7*24*time.Hour)))
+	default:
+		return t.UTC().Format("2006-01-02")
+	}
+}
+
+// WantsRelativeTime reports whether human output should show relative
+// timestamps. --relative-time and --absolute-time win when set; otherwise
+// relative is used only when stdout is a terminal. Structured output is always
+// absolute.
+func WantsRelativeTime(cmd *cobra.Command, f *cmdutil.Factory) bool {
+	if WantsJSON(cmd) || WantsYAML(cmd) {
+		return false
+	}
+	flags := cmd.Root().PersistentFlags()
+	if absolute, _ := flags.GetBool("absolute-time"); absolute {
+		return false
+	}
+	if relative, _ := flags.GetBool("relative-time"); relative {
+		return true
+	}
+	if f == nil {
+		return false
+	}
+	ios, err := f.Streams()
+	if err != nil || ios == nil {
+		return false
+	}
+	return ios.IsStdoutTTY()
+}
+
+// TimeFormatter returns a function that renders RFC3339 timestamps for human
+// output, honoring WantsRelativeTime. Ages are measured from the controller's
+// clock (see cmdutil.Factory.Now), so local clock drift does not skew them. Values that do not parse are returned
+// unchanged, so callers can pass through whatever the payload carries.
+func TimeFormatter(cmd *cobra.Command, f *cmdutil.Factory) func(string) string {
+	if !WantsRelativeTime(cmd, f) {
+		return func(value string) string { return value }
+	}
+	now := f.Now()
+	return func(value string) string {
+		ts, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return value
+		}
+		return RelativeTime(ts, now)
 	}
-	d := time.Duration(ms) * time.Millisecond
-	return d.String()
 }
@@ -0,0 +1,166 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/fakejenkins"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestRelativeTimeBoundaries(t *testing.T) {
+	now := time.Date(2025, 7, 31, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		age  time.Duration
+		want string
+	}{
+		{-5 * time.Second, "just now"},
+		{0, "just now"},
+		{59 * time.Second, "just now"},
+		{time.Minute, "1m ago"},
+		{59*time.Minute + 59*time.Second, "59m ago"},
+		{time.Hour, "1h ago"},
+		{23*time.Hour + 59*time.Minute, "23h ago"},
+		{24 * time.Hour, "1d ago"},
+		{6*24*time.Hour + 23*time.Hour, "6d ago"},
+		{7 * 24 * time.Hour, "1w ago"},
+		{29 * 24 * time.Hour, "4w ago"},
+		{30 * 24 * time.Hour, "2025-07-01"},
+		{400 * 24 * time.Hour, "2024-06-26"},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, RelativeTime(now.Add(-tc.age), now), "age %s", tc.age)
+	}
+}
+
+func newTimeTestCmd(t *testing.T, tty bool, flags ...string) (*cobra.Command, *cmdutil.Factory) {
+	t.Helper()
+	root := &cobra.Command{Use: "jk"}
+	root.PersistentFlags().Bool("json", false, "")
+	root.PersistentFlags().Bool("yaml", false, "")
+	root.PersistentFlags().Bool("relative-time", false, "")
+	root.PersistentFlags().Bool("absolute-time", false, "")
+	require.NoError(t, root.ParseFlags(flags))
+
+	ios, _, _, _ := iostreams.Test()
+	ios.SetStdoutTTY(tty)
+	return root, &cmdutil.Factory{IOStreams: ios}
+}
+
+func TestWantsRelativeTime(t *testing.T) {
+	cmd, f := newTimeTestCmd(t, true)
+	require.True(t, WantsRelativeTime(cmd, f), "tty defaults to relative")
+
+	cmd, f = newTimeTestCmd(t, false)
+	require.False(t, WantsRelativeTime(cmd, f), "pipe defaults to absolute")
+
+	cmd, f = newTimeTestCmd(t, false, "--relative-time")
+	require.True(t, WantsRelativeTime(cmd, f))
+
+	cmd, f = newTimeTestCmd(t, true, "--absolute-time")
+	require.False(t, WantsRelativeTime(cmd, f))
+
+	cmd, f = newTimeTestCmd(t, true, "--relative-time", "--json")
+	require.False(t, WantsRelativeTime(cmd, f), "structured output is always absolute")
+}
+
+func TestTimeFormatterKeepsUnparseableValues(t *testing.T) {
+	cmd, f := newTimeTestCmd(t, true)
+	stamp := TimeFormatter(cmd, f)
+	require.Equal(t, "", stamp(""))
+	require.Equal(t, "just now", stamp(time.Now().UTC().Format(time.RFC3339)))
+
+	cmd, f = newTimeTestCmd(t, false)
+	require.Equal(t, "2025-07-01T00:00:00Z", TimeFormatter(cmd, f)("2025-07-01T00:00:00Z"))
+}
+
+func TestTimeFormatterCorrectsClockSkew(t *testing.T) {
+	skew := 10 * time.Minute
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(skew).UTC().Format(http.TimeFormat))
+		http.NotFound(w, r)
+	}))
+	cmd, _ := newTimeTestCmd(t, true)
+	f, _, stderr := fakejenkins.Factory(client)
+	f.IOStreams.SetStdoutTTY(true)
+
+	for i := 0; i < 2; i++ {
+		_, err := f.Client(context.Background(), "")
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, strings.Count(stderr.String(), "behind Jenkins"), stderr.String())
+
+	stamp := TimeFormatter(cmd, f)
+	require.Equal(t, "5m ago", stamp(time.Now().Add(4*time.Minute+30*time.Second).UTC().Format(time.RFC3339)))
+	require.Equal(t, "just now", stamp(time.Now().Add(15*time.Minute).UTC().Format(time.RFC3339)), "future ages clamp")
+}
+
+func TestClockSkewWarningThreshold(t *testing.T) {
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(-3*time.Minute).UTC().Format(http.TimeFormat))
+		http.NotFound(w, r)
+	}))
+
+	t.Setenv(jenkins.ClockSkewWarnEnv, "5m")
+	f, _, stderr := fakejenkins.Factory(client)
+	_, err := f.Client(context.Background(), "")
+	require.NoError(t, err)
+	require.Empty(t, stderr.String())
+
+	t.Setenv(jenkins.ClockSkewWarnEnv, "")
+	f, _, stderr = fakejenkins.Factory(client)
+	_, err = f.Client(context.Background(), "")
+	require.NoError(t, err)
+	require.Contains(t, stderr.String(), "ahead of Jenkins")
+}
+
+func TestDurationStringBoundaries(t *testing.T) {
+	cases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-5 * time.Second, "0s"},
+		{0, "0s"},
+		{time.Millisecond, "1ms"},
+		{999 * time.Millisecond, "999ms"},
+		{time.Second, "1s"},
+		{1050 * time.Millisecond, "1s"},
+		{12345 * time.Millisecond, "12.3s"},
+		{59 * time.Second, "59s"},
+		{59999 * time.Millisecond, "59.9s"},
+		{60 * time.Second, "1m 0s"},
+		{61*time.Second + 500*time.Millisecond, "1m 1s"},
+		{4*time.Minute + 12*time.Second, "4m 12s"},
+		{59*time.Minute + 59*time.Second, "59m 59s"},
+		{time.Hour, "1h 0m 0s"},
+		{61 * time.Minute, "1h 1m 0s"},
+		{5*time.Hour + 37*time.Minute + 12345*time.Millisecond, "5h 37m 12s"},
+		{23*time.Hour + 59*time.Minute + 59*time.Second, "23h 59m 59s"},
+		{24 * time.Hour, "1d 0h 0m"},
+		{25 * time.Hour, "1d 1h 0m"},
+		{27*time.Hour + 46*time.Minute + 40*time.Second, "1d 3h 46m"},
+		{10*24*time.Hour + 23*time.Hour + 59*time.Minute, "10d 23h 59m"},
+		{400 * 24 * time.Hour, "400d 0h 0m"},
+	}
+	for _, tc := range cases {
+		require.Equal(t, tc.want, DurationString(tc.d.Milliseconds()), "duration %s", tc.d)
+	}
+}
+
+func TestDurationPaddedAlignsColumns(t *testing.T) {
+	for _, ms := range []int64{0, 850, 59_999, 61 * 60 * 1000, 86_399_000, 10*86_400_000 + 86_399_000} {
+		got := DurationPadded(ms)
+		require.Len(t, got, DurationWidth, "%q", got)
+		require.Equal(t, DurationString(ms), strings.TrimLeft(got, " "))
+	}
+	require.Equal(t, "400d 0h 0m", strings.TrimSpace(DurationPadded((400 * 24 * time.Hour).Milliseconds())))
+}
@@ -0,0 +1,44 @@
+package shared
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+)
+
+// AddURLOnlyFlag registers the --url-only flag used by commands that know the
+// Jenkins URL of what they display.
+func AddURLOnlyFlag(cmd *cobra.Command, target *bool) {
+	cmd.Flags().BoolVar(target, "url-only", false, "Print only the URL, for piping into other tools")
+}
+
+// PrintURLs writes one URL per line with no decoration so the output can be
+// piped. Structured output flags are rejected because they would be ignored.
+func PrintURLs(cmd *cobra.Command, urls ...string) error {
+	if WantsJSON(cmd) || WantsYAML(cmd) {
+		return NewExitError(2, "--url-only cannot be combined with --json or --yaml")
+	}
+	w := cmd.OutOrStdout()
+	for _, url := range urls {
+		if url = strings.TrimSpace(url); url != "" {
+			_, _ = fmt.Fprintln(w, url)
+		}
+	}
+	return nil
+}
+
+// Hyperlink renders text as a clickable link to url when stdout supports
+// OSC 8 hyperlinks, and as plain text otherwise.
+func Hyperlink(f *cmdutil.Factory, url, text string) string {
+	if f == nil {
+		return text
+	}
+	ios, err := f.Streams()
+	if err != nil || ios == nil {
+		return text
+	}
+	return ios.Hyperlink(url, text)
+}
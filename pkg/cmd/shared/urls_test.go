@@ -0,0 +1,47 @@
+package shared
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func newURLTestCmd(jsonOut bool) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.PersistentFlags().Bool("json", jsonOut, "")
+	cmd.PersistentFlags().Bool("yaml", false, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestPrintURLsWritesOnePerLine(t *testing.T) {
+	cmd, out := newURLTestCmd(false)
+
+	require.NoError(t, PrintURLs(cmd, "https://jenkins.example/job/app/2/", "", "https://jenkins.example/job/app/1/"))
+	require.Equal(t, "https://jenkins.example/job/app/2/\nhttps://jenkins.example/job/app/1/\n", out.String())
+}
+
+func TestPrintURLsRejectsStructuredOutput(t *testing.T) {
+	cmd, _ := newURLTestCmd(true)
+
+	err := PrintURLs(cmd, "https://jenkins.example/")
+	var exitErr *cmdutil.ExitError
+	require.True(t, errors.As(err, &exitErr))
+	require.Equal(t, 2, exitErr.Code)
+}
+
+func TestHyperlinkStaysPlainWhenPiped(t *testing.T) {
+	ios, _, _, _ := iostreams.Test()
+	f := &cmdutil.Factory{IOStreams: ios}
+	require.Equal(t, "#42", Hyperlink(f, "https://jenkins.example/job/app/42/", "#42"))
+
+	ios.SetHyperlinksEnabled(true)
+	require.Equal(t, iostreams.FormatHyperlink("https://jenkins.example/job/app/42/", "#42"), Hyperlink(f, "https://jenkins.example/job/app/42/", "#42"))
+}
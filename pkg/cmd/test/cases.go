@@ -0,0 +1,343 @@
+package testcmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/jobpath"
+)
+
+const (
+	caseSortDuration = "duration"
+	caseSortAge      = "age"
+	caseSortName     = "name"
+
+	// suitesPageSize bounds each testReport request so very large reports are
+	// walked in slices instead of one multi-megabyte response.
+	suitesPageSize = 50
+
+	maxClassWidth = 48
+)
+
+var caseStatuses = []string{"failed", "passed", "skipped", "regression", "fixed"}
+
+type testCaseItem struct {
+	ClassName    string `json:"className"`
+	Name         string `json:"name"`
+	Status       string `json:"status"`
+	DurationMs   int64  `json:"durationMs"`
+	Age          int    `json:"age"`
+	ErrorDetails string `json:"errorDetails,omitempty"`
+	duration     float64
+}
+
+type testCasesOutput struct {
+	SchemaVersion string             `json:"schemaVersion"`
+	Items         []testCaseItem     `json:"items"`
+	Metadata      *testCasesMetadata `json:"metadata"`
+}
+
+type testCasesMetadata struct {
+	JobPath   string `json:"jobPath"`
+	Build     int64  `json:"build"`
+	Status    string `json:"status,omitempty"`
+	Class     string `json:"class,omitempty"`
+	Sort      string `json:"sort"`
+	Total     int    `json:"total"`
+	Passed    int    `json:"passed"`
+	Failed    int    `json:"failed"`
+	Skipped   int    `json:"skipped"`
+	Matched   int    `json:"matched"`
+	Returned  int    `json:"returned"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+type testCasesOptions struct {
+	Status  string
+	Class   string
+	Limit   int
+	Sort    string
+	Details bool
+}
+
+type caseReportPage struct {
+	Suites []struct {
+		Cases []struct {
+			ClassName    string  `json:"className"`
+			Name         string  `json:"name"`
+			Status       string  `json:"status"`
+			Duration     float64 `json:"duration"`
+			Age          int     `json:"age"`
+			ErrorDetails string  `json:"errorDetails"`
+		} `json:"cases"`
+	} `json:"suites"`
+}
+
+func newTestCasesCmd(f *cmdutil.Factory) *cobra.Command {
+	opts := testCasesOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "cases <jobPath> <buildNumber>",
+		Short: "List individual test cases from a run's test report",
+		Example: `  # Slowest failing cases in a build
+  jk test cases team/app 42 --status failed
+
+  # Longest-failing cases in a package
+  jk test cases team/app 42 --status failed --class com.example.billing --sort age --json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := shared.JenkinsClient(cmd, f)
+			if err != nil {
+				return err
+			}
+
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
+			if err != nil {
+				return err
+			}
+
+			num, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || num <= 0 {
+				return fmt.Errorf("invalid build number %q", args[1])
+			}
+
+			opts.Status = strings.ToLower(strings.TrimSpace(opts.Status))
+			if opts.Status != "" && !slices.Contains(caseStatuses, opts.Status) {
+				return fmt.Errorf("unsupported status %q (expected %s)", opts.Status, strings.Join(caseStatuses, ", "))
+			}
+			opts.Sort = strings.ToLower(strings.TrimSpace(opts.Sort))
+			switch opts.Sort {
+			case caseSortDuration, caseSortAge, caseSortName:
+			default:
+				return fmt.Errorf("unsupported sort %q (expected duration, age, name)", opts.Sort)
+			}
+
+			output, found, err := collectTestCases(cmd.Context(), client, jobPath, num, opts)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return shared.PrintOutput(cmd, nil, func() error {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No test report available")
+					return nil
+				})
+			}
+
+			return shared.PrintOutput(cmd, output, func() error {
+				return renderTestCasesHuman(cmd, output)
+			})
+		},
+	}
+	cmdutil.SetJobPathArg(cmd, 0)
+
+	cmd.Flags().StringVar(&opts.Status, "status", "", "Only show cases with status: failed, passed, skipped, regression, fixed")
+	cmd.Flags().StringVar(&opts.Class, "class", "", "Only show cases whose class name contains this substring")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 50, "Maximum cases to return (0 for all)")
+	cmd.Flags().StringVar(&opts.Sort, "sort", caseSortDuration, "Sort by duration, age, or name")
+	cmd.Flags().BoolVar(&opts.Details, "details", false, "Include error details for failing cases")
+	cmdutil.SetFlagEnum(cmd, "status", caseStatuses...)
+	cmdutil.SetFlagEnum(cmd, "sort", caseSortDuration, caseSortAge, caseSortName)
+
+	return cmd
+}
+
+// collectTestCases walks the report suite page by suite page, keeping only
+// the best `limit` matches in memory. found is false when the run has no
+// test report.
+func collectTestCases(ctx context.Context, client shared.Doer, jobPath string, buildNumber int64, opts testCasesOptions) (testCasesOutput, bool, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	meta := &testCasesMetadata{
+		JobPath: jobpath.Normalize(jobPath),
+		Build:   buildNumber,
+		Status:  opts.Status,
+		Class:   opts.Class,
+		Sort:    opts.Sort,
+	}
+	items := make([]testCaseItem, 0)
+	path := fmt.Sprintf("/%s/%d/testReport/api/json", jobpath.Encode(jobPath), buildNumber)
+	classNeedle := strings.ToLower(strings.TrimSpace(opts.Class))
+
+	for start := 0; ; start += suitesPageSize {
+		var page caseReportPage
+		req := client.NewRequest().
+			SetContext(ctx).
+			SetQueryParam("tree", buildCasesTree(opts, start, start+suitesPageSize))
+		resp, err := client.Do(req, http.MethodGet, path, &page)
+		if err != nil {
+			return testCasesOutput{}, false, err
+		}
+		if resp.StatusCode() == http.StatusNotFound {
+			if start == 0 {
+				return testCasesOutput{}, false, nil
+			}
+			break
+		}
+		if err := shared.CheckResponse(resp, fmt.Sprintf("test report for %s #%d", meta.JobPath, buildNumber)); err != nil {
+			return testCasesOutput{}, false, err
+		}
+
+		for _, suite := range page.Suites {
+			for _, c := range suite.Cases {
+				meta.Total++
+				switch strings.ToUpper(c.Status) {
+				case "FAILED", "REGRESSION":
+					meta.Failed++
+				case "SKIPPED":
+					meta.Skipped++
+				default:
+					meta.Passed++
+				}
+
+				if !caseMatchesStatus(c.Status, opts.Status) {
+					continue
+				}
+				if classNeedle != "" && !strings.Contains(strings.ToLower(c.ClassName), classNeedle) {
+					continue
+				}
+				meta.Matched++
+				items = append(items, testCaseItem{
+					ClassName:    c.ClassName,
+					Name:         c.Name,
+					Status:       strings.ToUpper(c.Status),
+					DurationMs:   shared.SecondsToMs(c.Duration),
+					Age:          c.Age,
+					ErrorDetails: c.ErrorDetails,
+					duration:     c.Duration,
+				})
+			}
+		}
+
+		sortTestCases(items, opts.Sort)
+		if opts.Limit > 0 && len(items) > opts.Limit {
+			items = items[:opts.Limit]
+		}
+
+		if len(page.Suites) < suitesPageSize {
+			break
+		}
+	}
+
+	meta.Returned = len(items)
+	meta.Truncated = meta.Matched > meta.Returned
+	return testCasesOutput{SchemaVersion: "1.0", Items: items, Metadata: meta}, true, nil
+}
+
+// buildCasesTree requests only the case fields the command renders, plus
+// errorDetails when --details is set, for suites in [start,end).
+func buildCasesTree(opts testCasesOptions, start, end int) string {
+	fields := []string{"className", "name", "status", "duration", "age"}
+	if opts.Details {
+		fields = append(fields, "errorDetails")
+	}
+	return fmt.Sprintf("suites[cases[%s]]{%d,%d}", strings.Join(fields, ","), start, end)
+}
+
+// caseMatchesStatus treats regressions as failures and fixed cases as passes,
+// mirroring how Jenkins counts them in the report totals.
+func caseMatchesStatus(actual, wanted string) bool {
+	if wanted == "" {
+		return true
+	}
+	actual = strings.ToLower(actual)
+	switch wanted {
+	case "failed":
+		return actual == "failed" || actual == "regression"
+	case "passed":
+		return actual == "passed" || actual == "fixed"
+	default:
+		return actual == wanted
+	}
+}
+
+func sortTestCases(items []testCaseItem, by string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		switch by {
+		case caseSortAge:
+			if a.Age != b.Age {
+				return a.Age > b.Age
+			}
+		case caseSortName:
+			if a.ClassName != b.ClassName {
+				return a.ClassName < b.ClassName
+			}
+			return a.Name < b.Name
+		}
+		if a.duration != b.duration {
+			return a.duration > b.duration
+		}
+		if a.ClassName != b.ClassName {
+			return a.ClassName < b.ClassName
+		}
+		return a.Name < b.Name
+	})
+}
+
+func renderTestCasesHuman(cmd *cobra.Command, output testCasesOutput) error {
+	w := cmd.OutOrStdout()
+	meta := output.Metadata
+
+	if len(output.Items) == 0 {
+		_, _ = fmt.Fprintf(w, "No matching test cases (%d total)\n", meta.Total)
+		return nil
+	}
+
+	for _, item := range output.Items {
+		_, _ = fmt.Fprintf(w, "%s\t%s\tage %d\t%s.%s\n",
+			item.Status,
+			shared.DurationPadded(item.DurationMs),
+			item.Age,
+			abbreviateClassName(item.ClassName, maxClassWidth),
+			item.Name,
+		)
+		if item.ErrorDetails != "" {
+			_, _ = fmt.Fprintf(w, "    %s\n", firstLine(item.ErrorDetails))
+		}
+	}
+
+	summary := fmt.Sprintf("Showing %d of %d matching cases (%d total: %d failed, %d skipped)", meta.Returned, meta.Matched, meta.Total, meta.Failed, meta.Skipped)
+	_, _ = fmt.Fprintln(w, summary)
+	return nil
+}
+
+// abbreviateClassName shortens leading package segments to their initials
+// (com.example.billing.InvoiceTest -> c.e.b.InvoiceTest) until the name fits,
+// so the simple class name stays readable.
+func abbreviateClassName(name string, width int) string {
+	if len(name) <= width {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i := 0; i < len(parts)-1; i++ {
+		if parts[i] != "" {
+			parts[i] = parts[i][:1]
+		}
+		if short := strings.Join(parts, "."); len(short) <= width {
+			return short
+		}
+	}
+	short := strings.Join(parts, ".")
+	if len(short) > width && width > 3 {
+		return "..." + short[len(short)-width+3:]
+	}
+	return short
+}
+
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx >= 0 {
+		return strings.TrimSpace(s[:idx])
+	}
+	return strings.TrimSpace(s)
+}
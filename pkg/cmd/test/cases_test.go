@@ -0,0 +1,89 @@
+package testcmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/outputschema"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+)
+
+func TestBuildCasesTree(t *testing.T) {
+	require.Equal(t, "suites[cases[className,name,status,duration,age]]{0,50}", buildCasesTree(testCasesOptions{}, 0, 50))
+	require.Equal(t, "suites[cases[className,name,status,duration,age,errorDetails]]{50,100}", buildCasesTree(testCasesOptions{Details: true}, 50, 100))
+}
+
+func TestCaseMatchesStatus(t *testing.T) {
+	require.True(t, caseMatchesStatus("REGRESSION", "failed"))
+	require.True(t, caseMatchesStatus("FIXED", "passed"))
+	require.False(t, caseMatchesStatus("FAILED", "regression"))
+	require.True(t, caseMatchesStatus("SKIPPED", ""))
+}
+
+func TestAbbreviateClassName(t *testing.T) {
+	require.Equal(t, "com.example.FooTest", abbreviateClassName("com.example.FooTest", 40))
+	require.Equal(t, "c.e.billing.InvoiceTest", abbreviateClassName("com.example.billing.InvoiceTest", 24))
+	require.Equal(t, "c.e.b.InvoiceTest", abbreviateClassName("com.example.billing.InvoiceTest", 18))
+}
+
+func TestCollectTestCasesPagesSuites(t *testing.T) {
+	// Two pages: the first full page of suites, the second partial.
+	var trees []string
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/job/app/7/testReport/api/json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		tree := r.URL.Query().Get("tree")
+		trees = append(trees, tree)
+
+		suites := 1
+		if strings.HasSuffix(tree, "{0,50}") {
+			suites = suitesPageSize
+		}
+		parts := make([]string, 0, suites)
+		for i := 0; i < suites; i++ {
+			status := "PASSED"
+			if i%10 == 0 {
+				status = "FAILED"
+			}
+			parts = append(parts, fmt.Sprintf(`{"cases":[{"className":"com.example.Suite%d","name":"case","status":%q,"duration":%d.5,"age":%d}]}`, i, status, i, i))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"suites":[%s]}`, strings.Join(parts, ","))
+	}))
+
+	out, found, err := collectTestCases(context.Background(), client, "app", 7, testCasesOptions{Status: "failed", Limit: 2, Sort: caseSortAge})
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, trees, 2)
+
+	meta := out.Metadata
+	require.Equal(t, 51, meta.Total)
+	require.Equal(t, 6, meta.Failed)
+	require.Equal(t, 6, meta.Matched)
+	require.Equal(t, 2, meta.Returned)
+	require.True(t, meta.Truncated)
+	require.Equal(t, "com.example.Suite40", out.Items[0].ClassName)
+	require.Equal(t, int64(40500), out.Items[0].DurationMs)
+}
+
+func TestCollectTestCasesMissingReport(t *testing.T) {
+	client := jenkinstest.NewClient(t, http.NotFoundHandler())
+
+	_, found, err := collectTestCases(context.Background(), client, "app", 7, testCasesOptions{Sort: caseSortDuration})
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestOutputTimeFieldConventions(t *testing.T) {
+	require.Empty(t, outputschema.TimeFieldViolations(testCasesOutput{}))
+	// duration (seconds) is the deprecated twin of durationMs.
+	require.Empty(t, outputschema.TimeFieldViolations(shared.TestReport{}, "duration"))
+}
@@ -16,7 +16,10 @@ func NewCmdTest(f *cmdutil.Factory) *cobra.Command {
 		Short: "Inspect test results",
 	}
 
-	cmd.AddCommand(newTestReportCmd(f))
+	cmd.AddCommand(
+		newTestReportCmd(f),
+		newTestCasesCmd(f),
+	)
 	return cmd
 }
 
@@ -31,22 +34,26 @@ func newTestReportCmd(f *cmdutil.Factory) *cobra.Command {
 				return err
 			}
 
-			num, err := strconv.Atoi(args[1])
+			jobPath, err := shared.ResolveJobPath(cmd, client, args[0])
 			if err != nil {
 				return err
 			}
 
-			report, err := shared.FetchTestReport(client, args[0], int64(num))
+			num, err := strconv.Atoi(args[1])
 			if err != nil {
 				return err
 			}
 
-			if report == nil {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No test report available")
-				return nil
+			report, err := shared.FetchTestReport(client, jobPath, int64(num))
+			if err != nil {
+				return err
 			}
 
 			return shared.PrintOutput(cmd, report, func() error {
+				if report == nil {
+					_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No test report available")
+					return nil
+				}
 				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Total: %d\nFailed: %d\nSkipped: %d\n", report.TotalCount, report.FailCount, report.SkipCount)
 				if len(report.Suites) > 0 {
 					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Suites: %d\n", len(report.Suites))
@@ -55,6 +62,7 @@ func newTestReportCmd(f *cmdutil.Factory) *cobra.Command {
 			})
 		},
 	}
+	cmdutil.SetJobPathArg(cmd, 0)
 
 	return cmd
 }
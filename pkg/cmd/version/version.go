@@ -1,27 +1,264 @@
 package version
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/avivsinai/jenkins-cli/internal/build"
+	"github.com/avivsinai/jenkins-cli/internal/deprecation"
+	"github.com/avivsinai/jenkins-cli/pkg/cmd/shared"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
 )
 
-func NewCmdVersion() *cobra.Command {
-	return &cobra.Command{
+type versionOutput struct {
+	Client *clientVersion `json:"client,omitempty"`
+	Server *serverVersion `json:"server,omitempty"`
+}
+
+type clientVersion struct {
+	Version string `json:"version"`
+	Commit  string `json:"commit,omitempty"`
+	Date    string `json:"date,omitempty"`
+}
+
+type serverVersion struct {
+	Context        string   `json:"context,omitempty"`
+	URL            string   `json:"url,omitempty"`
+	Version        string   `json:"version,omitempty"`
+	QuietingDown   bool     `json:"quietingDown"`
+	PendingUpdates *int     `json:"pendingUpdates,omitempty"`
+	Features       []string `json:"features"`
+	// Deprecations are the endpoints this context's controller has marked
+	// deprecated, as recorded by earlier commands.
+	Deprecations []deprecatedEndpoint `json:"deprecations"`
+	Error        string               `json:"error,omitempty"`
+}
+
+// deprecatedEndpoint is a deprecation.Endpoint with its instants formatted
+// like every other JSON time.
+type deprecatedEndpoint struct {
+	Path       string `json:"path"`
+	Header     string `json:"header"`
+	Value      string `json:"value,omitempty"`
+	FirstSeen  string `json:"firstSeen"`
+	LastSeen   string `json:"lastSeen"`
+	LastWarned string `json:"lastWarned,omitempty"`
+}
+
+type rootStatus struct {
+	QuietingDown bool `json:"quietingDown"`
+}
+
+type updateCenterResponse struct {
+	Sites []struct {
+		Updates []struct {
+			Name string `json:"name"`
+		} `json:"updates"`
+	} `json:"sites"`
+}
+
+func NewCmdVersion(f *cmdutil.Factory) *cobra.Command {
+	var (
+		showClient bool
+		showServer bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "version",
-		Short: "Print jk version information",
+		Short: "Print jk and Jenkins version information",
+		Long: `Print jk version information and, when a context is configured, the Jenkins
+controller version, quiet-down state, pending plugin updates, and detected jk
+companion-plugin features.
+
+Without flags both sections are shown and server errors are reported inline.
+Pass --server to make connection failures fail the command.`,
+		Example: `  # Client and server details to paste into a support request
+  jk version --json
+
+  # Only the CLI build
+  jk version --client`,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "jk version %s", build.Version)
-			if build.Commit != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\ncommit: %s", build.Commit)
+			strict := showServer
+			if !showClient && !showServer {
+				showClient, showServer = true, true
 			}
-			if build.Date != "" {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "\ndate: %s", build.Date)
+
+			output := versionOutput{}
+			if showClient {
+				output.Client = &clientVersion{
+					Version: build.Version,
+					Commit:  build.Commit,
+					Date:    build.Date,
+				}
+			}
+
+			var serverErr error
+			if showServer {
+				output.Server, serverErr = collectServerVersion(cmd, f)
+				if serverErr != nil {
+					output.Server.Error = serverErr.Error()
+				}
+			}
+
+			if err := shared.PrintOutput(cmd, output, func() error {
+				return renderVersionHuman(cmd.OutOrStdout(), output)
+			}); err != nil {
+				return err
+			}
+
+			if strict && serverErr != nil {
+				return fmt.Errorf("server version unavailable: %w", serverErr)
 			}
-			_, _ = fmt.Fprintln(cmd.OutOrStdout())
 			return nil
 		},
 	}
+
+	cmd.Flags().BoolVar(&showClient, "client", false, "Show the jk client version")
+	cmd.Flags().BoolVar(&showServer, "server", false, "Show Jenkins server details; fail when unreachable")
+	return cmd
+}
+
+func collectServerVersion(cmd *cobra.Command, f *cmdutil.Factory) (*serverVersion, error) {
+	out := &serverVersion{Features: []string{}, Deprecations: []deprecatedEndpoint{}}
+
+	client, err := shared.JenkinsClient(cmd, f)
+	if err != nil {
+		return out, err
+	}
+	out.Context = client.ContextName()
+	if ctxDef := client.Context(); ctxDef != nil {
+		out.URL = ctxDef.URL
+	}
+	if state, err := deprecation.Load(out.Context); err == nil {
+		for _, endpoint := range state.List() {
+			out.Deprecations = append(out.Deprecations, deprecatedEndpoint{
+				Path:       endpoint.Path,
+				Header:     endpoint.Header,
+				Value:      endpoint.Value,
+				FirstSeen:  shared.FormatTime(endpoint.FirstSeen),
+				LastSeen:   shared.FormatTime(endpoint.LastSeen),
+				LastWarned: shared.FormatTime(endpoint.LastWarned),
+			})
+		}
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var status rootStatus
+	resp, err := client.Do(
+		client.NewRequest().SetContext(ctx).SetQueryParam("tree", "quietingDown"),
+		http.MethodGet,
+		"/api/json",
+		&status,
+	)
+	if err != nil {
+		return out, err
+	}
+	if err := shared.CheckResponse(resp, "Jenkins root"); err != nil {
+		return out, err
+	}
+	out.Version = resp.Header().Get("X-Jenkins")
+	out.QuietingDown = status.QuietingDown
+
+	// The update center requires Overall/Administer; leave the count unset
+	// rather than failing for regular users.
+	out.PendingUpdates = fetchPendingUpdates(ctx, client)
+	out.Features = client.Capabilities(ctx).Features()
+	return out, nil
+}
+
+func fetchPendingUpdates(ctx context.Context, client shared.Doer) *int {
+	var center updateCenterResponse
+	resp, err := client.Do(
+		client.NewRequest().SetContext(ctx).SetQueryParam("tree", "sites[updates[name]]"),
+		http.MethodGet,
+		"/updateCenter/api/json",
+		&center,
+	)
+	if err != nil || resp.StatusCode() != http.StatusOK {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	for _, site := range center.Sites {
+		for _, update := range site.Updates {
+			seen[update.Name] = struct{}{}
+		}
+	}
+	count := len(seen)
+	return &count
+}
+
+func renderVersionHuman(w io.Writer, output versionOutput) error {
+	if output.Client != nil {
+		_, _ = fmt.Fprintf(w, "jk version %s\n", output.Client.Version)
+		if output.Client.Commit != "" {
+			_, _ = fmt.Fprintf(w, "commit: %s\n", output.Client.Commit)
+		}
+		if output.Client.Date != "" {
+			_, _ = fmt.Fprintf(w, "date: %s\n", output.Client.Date)
+		}
+	}
+
+	server := output.Server
+	if server == nil {
+		return nil
+	}
+	if output.Client != nil {
+		_, _ = fmt.Fprintln(w)
+	}
+
+	header := "Server"
+	if server.Context != "" {
+		header = fmt.Sprintf("Server (context %s)", server.Context)
+	}
+	_, _ = fmt.Fprintf(w, "%s:\n", header)
+	if server.URL != "" {
+		_, _ = fmt.Fprintf(w, "  URL: %s\n", server.URL)
+	}
+	if server.Error != "" {
+		_, _ = fmt.Fprintf(w, "  error: %s\n", server.Error)
+		renderDeprecations(w, server.Deprecations)
+		return nil
+	}
+
+	version := server.Version
+	if version == "" {
+		version = "unknown"
+	}
+	_, _ = fmt.Fprintf(w, "  Jenkins: %s\n", version)
+	_, _ = fmt.Fprintf(w, "  Quieting down: %t\n", server.QuietingDown)
+	if server.PendingUpdates != nil {
+		_, _ = fmt.Fprintf(w, "  Plugin updates: %d\n", *server.PendingUpdates)
+	} else {
+		_, _ = fmt.Fprintln(w, "  Plugin updates: unavailable (requires Overall/Administer)")
+	}
+	features := "none detected"
+	if len(server.Features) > 0 {
+		features = strings.Join(server.Features, ", ")
+	}
+	_, _ = fmt.Fprintf(w, "  jk plugin features: %s\n", features)
+	renderDeprecations(w, server.Deprecations)
+	return nil
+}
+
+func renderDeprecations(w io.Writer, endpoints []deprecatedEndpoint) {
+	if len(endpoints) == 0 {
+		_, _ = fmt.Fprintln(w, "  Deprecated endpoints: none recorded")
+		return
+	}
+	_, _ = fmt.Fprintln(w, "  Deprecated endpoints:")
+	for _, endpoint := range endpoints {
+		_, _ = fmt.Fprintf(w, "    %s (%s) first seen %s, last seen %s\n",
+			endpoint.Path, endpoint.Header, endpoint.FirstSeen, endpoint.LastSeen)
+	}
 }
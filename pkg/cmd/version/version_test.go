@@ -0,0 +1,155 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+
+	"github.com/avivsinai/jenkins-cli/internal/cachefile"
+	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+	"github.com/avivsinai/jenkins-cli/internal/jenkins/jenkinstest"
+	"github.com/avivsinai/jenkins-cli/internal/testing/outputschema"
+	"github.com/avivsinai/jenkins-cli/pkg/cmdutil"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func executeVersion(t *testing.T, clientFn func(context.Context, string) (*jenkins.Client, error), args ...string) (versionOutput, error) {
+	t.Helper()
+
+	ios, _, stdout, _ := iostreams.Test()
+	f := &cmdutil.Factory{
+		IOStreams: ios,
+		Config: func() (*config.Config, error) {
+			return &config.Config{Contexts: map[string]*config.Context{}}, nil
+		},
+		JenkinsClient: clientFn,
+	}
+
+	root := &cobra.Command{Use: "jk", SilenceErrors: true, SilenceUsage: true}
+	root.PersistentFlags().Bool("json", false, "")
+	root.AddCommand(NewCmdVersion(f))
+	root.SetOut(stdout)
+	root.SetArgs(append([]string{"version", "--json"}, args...))
+
+	err := root.Execute()
+	var out versionOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	return out, err
+}
+
+func TestVersionReportsServerDetails(t *testing.T) {
+	client := jenkinstest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/json":
+			w.Header().Set("X-Jenkins", "2.462.3")
+			_, _ = w.Write([]byte(`{"quietingDown":true}`))
+		case "/updateCenter/api/json":
+			_, _ = w.Write([]byte(`{"sites":[{"updates":[{"name":"git"},{"name":"workflow-job"}]}]}`))
+		case "/jk/api/status":
+			_, _ = w.Write([]byte(`{"features":["runs","credentials"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	out, err := executeVersion(t, func(context.Context, string) (*jenkins.Client, error) { return client, nil })
+	require.NoError(t, err)
+	require.NotNil(t, out.Client)
+	require.NotNil(t, out.Server)
+	require.Equal(t, "2.462.3", out.Server.Version)
+	require.True(t, out.Server.QuietingDown)
+	require.NotNil(t, out.Server.PendingUpdates)
+	require.Equal(t, 2, *out.Server.PendingUpdates)
+	require.Equal(t, []string{"runs", "credentials"}, out.Server.Features)
+	require.Empty(t, out.Server.Error)
+}
+
+func TestVersionDegradesWithoutServer(t *testing.T) {
+	unreachable := func(context.Context, string) (*jenkins.Client, error) {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+
+	out, err := executeVersion(t, unreachable)
+	require.NoError(t, err)
+	require.NotNil(t, out.Client)
+	require.Contains(t, out.Server.Error, "connection refused")
+
+	out, err = executeVersion(t, unreachable, "--server")
+	require.Error(t, err)
+	require.Nil(t, out.Client)
+	require.NotNil(t, out.Server)
+}
+
+func TestVersionListsDeprecatedEndpoints(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/json":
+			_, _ = w.Write([]byte(`{"quietingDown":false}`))
+		case "/jk/api/status":
+			w.Header().Set("X-JK-Deprecated", "true")
+			_, _ = w.Write([]byte(`{"features":["runs"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client := jenkinstest.NewClient(t, handler)
+	cacheDir := os.Getenv(cachefile.DirEnv)
+
+	run := func(quiet bool) (versionOutput, string) {
+		ios, _, stdout, stderr := iostreams.Test()
+		f := &cmdutil.Factory{
+			Quiet:     quiet,
+			IOStreams: ios,
+			Config: func() (*config.Config, error) {
+				return &config.Config{Contexts: map[string]*config.Context{}}, nil
+			},
+			JenkinsClient: func(context.Context, string) (*jenkins.Client, error) { return client, nil },
+		}
+		root := &cobra.Command{Use: "jk", SilenceErrors: true, SilenceUsage: true}
+		root.PersistentFlags().Bool("json", false, "")
+		root.AddCommand(NewCmdVersion(f))
+		root.SetOut(stdout)
+		root.SetArgs([]string{"version", "--server", "--json"})
+		require.NoError(t, root.Execute())
+		var out versionOutput
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+		return out, stderr.String()
+	}
+
+	// --quiet records the sighting but keeps the warning for a later run.
+	out, stderr := run(true)
+	require.NotContains(t, stderr, "deprecated")
+	require.Len(t, out.Server.Deprecations, 1)
+	require.Empty(t, out.Server.Deprecations[0].LastWarned)
+
+	client = jenkinstest.NewClient(t, handler)
+	t.Setenv(cachefile.DirEnv, cacheDir)
+	out, stderr = run(false)
+	require.Contains(t, stderr, "warning: endpoint /jk/api/status is deprecated by the server (context test); upgrade the companion plugin")
+	require.Len(t, out.Server.Deprecations, 1)
+	require.Equal(t, "/jk/api/status", out.Server.Deprecations[0].Path)
+	require.Equal(t, "X-JK-Deprecated", out.Server.Deprecations[0].Header)
+	_, err := time.Parse(time.RFC3339, out.Server.Deprecations[0].FirstSeen)
+	require.NoError(t, err)
+
+	// A new process within a day records the sighting without warning again.
+	client = jenkinstest.NewClient(t, handler)
+	t.Setenv(cachefile.DirEnv, cacheDir)
+	out, stderr = run(false)
+	require.NotContains(t, stderr, "deprecated")
+	require.Len(t, out.Server.Deprecations, 1)
+}
+
+func TestOutputTimeFieldConventions(t *testing.T) {
+	require.Empty(t, outputschema.TimeFieldViolations(versionOutput{}))
+}
@@ -0,0 +1,114 @@
+package cmdutil
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Annotation keys used to attach structured, machine-readable hints to
+// commands and flags. `jk help --json` renders them for agents.
+const (
+	AnnotationExitCodes       = "jk.exitCodes"
+	AnnotationFilterKeys      = "jk.filterKeys"
+	AnnotationFilterOperators = "jk.filterOperators"
+	AnnotationFlagEnum        = "jk.enum"
+	AnnotationJobPathArg      = "jk.jobPathArg"
+	AnnotationJobPathFlag     = "jk.jobPath"
+)
+
+// SetFlagEnum records the accepted values for a flag.
+func SetFlagEnum(cmd *cobra.Command, name string, values ...string) {
+	_ = cmd.Flags().SetAnnotation(name, AnnotationFlagEnum, values)
+}
+
+// FlagEnum returns the accepted values registered via SetFlagEnum.
+func FlagEnum(flag *pflag.Flag) []string {
+	if flag == nil || flag.Annotations == nil {
+		return nil
+	}
+	return flag.Annotations[AnnotationFlagEnum]
+}
+
+// SetJobPathArg marks the positional argument at index as the job path or
+// folder cmd works on, so context rules can pick the context for it.
+func SetJobPathArg(cmd *cobra.Command, index int) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[AnnotationJobPathArg] = strconv.Itoa(index)
+}
+
+// JobPathArgIndex returns the index registered via SetJobPathArg.
+func JobPathArgIndex(cmd *cobra.Command) (int, bool) {
+	if cmd == nil || cmd.Annotations == nil {
+		return 0, false
+	}
+	index, err := strconv.Atoi(cmd.Annotations[AnnotationJobPathArg])
+	return index, err == nil && index >= 0
+}
+
+// SetJobPathFlag marks flag name as holding the job path or folder cmd works
+// on, for commands that take it by flag; see SetJobPathArg.
+func SetJobPathFlag(cmd *cobra.Command, name string) {
+	_ = cmd.Flags().SetAnnotation(name, AnnotationJobPathFlag, []string{"true"})
+}
+
+// IsJobPathFlag reports whether flag was marked via SetJobPathFlag.
+func IsJobPathFlag(flag *pflag.Flag) bool {
+	return flag != nil && len(flag.Annotations[AnnotationJobPathFlag]) > 0
+}
+
+// SetFilterSupport documents the --filter keys and operators a command accepts.
+func SetFilterSupport(cmd *cobra.Command, keys, operators []string) {
+	setAnnotationJSON(cmd, AnnotationFilterKeys, keys)
+	setAnnotationJSON(cmd, AnnotationFilterOperators, operators)
+}
+
+// FilterSupport returns the filter keys and operators registered on cmd.
+func FilterSupport(cmd *cobra.Command) (keys, operators []string) {
+	getAnnotationJSON(cmd, AnnotationFilterKeys, &keys)
+	getAnnotationJSON(cmd, AnnotationFilterOperators, &operators)
+	return keys, operators
+}
+
+// SetExitCodes documents the command-specific exit codes a command can return
+// in addition to the global table.
+func SetExitCodes(cmd *cobra.Command, codes map[int]string) {
+	encoded := make(map[string]string, len(codes))
+	for code, meaning := range codes {
+		encoded[strconv.Itoa(code)] = meaning
+	}
+	setAnnotationJSON(cmd, AnnotationExitCodes, encoded)
+}
+
+// ExitCodes returns the exit codes registered via SetExitCodes.
+func ExitCodes(cmd *cobra.Command) map[string]string {
+	var codes map[string]string
+	getAnnotationJSON(cmd, AnnotationExitCodes, &codes)
+	return codes
+}
+
+func setAnnotationJSON(cmd *cobra.Command, key string, value any) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[key] = string(data)
+}
+
+func getAnnotationJSON(cmd *cobra.Command, key string, target any) {
+	if cmd == nil || cmd.Annotations == nil {
+		return
+	}
+	raw, ok := cmd.Annotations[key]
+	if !ok || raw == "" {
+		return
+	}
+	_ = json.Unmarshal([]byte(raw), target)
+}
@@ -0,0 +1,48 @@
+package cmdutil
+
+import (
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/jenkins"
+)
+
+// observeClock remembers the controller's clock skew from client and warns
+// once per process when it exceeds jenkins.ClockSkewWarnThreshold.
+func (f *Factory) observeClock(client *jenkins.Client) {
+	skew, ok := client.ClockSkew()
+	if !ok {
+		return
+	}
+
+	f.clockMu.Lock()
+	f.clockSkew = skew
+	warned := f.clockWarned
+	threshold := jenkins.ClockSkewWarnThreshold()
+	warn := !warned && threshold > 0 && (skew >= threshold || skew <= -threshold)
+	if warn {
+		f.clockWarned = true
+	}
+	f.clockMu.Unlock()
+
+	if !warn {
+		return
+	}
+	direction := "behind"
+	if skew < 0 {
+		direction, skew = "ahead of", -skew
+	}
+	f.warnf("the local clock is %s %s Jenkins (context %s); check your system clock. Relative times are corrected for the difference.",
+		skew.Round(time.Second), direction, client.ContextName())
+}
+
+// Now is the current time on the controller, as far as the clients created
+// so far could tell; the local time otherwise. Relative times in human output
+// are measured from it.
+func (f *Factory) Now() time.Time {
+	if f == nil {
+		return time.Now()
+	}
+	f.clockMu.Lock()
+	defer f.clockMu.Unlock()
+	return time.Now().Add(f.clockSkew)
+}
@@ -0,0 +1,42 @@
+package cmdutil
+
+import (
+	"strings"
+	"time"
+
+	"github.com/avivsinai/jenkins-cli/internal/deprecation"
+	"github.com/avivsinai/jenkins-cli/internal/log"
+)
+
+// observeDeprecation records an endpoint the controller marked deprecated and
+// warns about it at most once per deprecation.WarnInterval, across processes.
+// Under --quiet the sighting is recorded without a warning, so the next run
+// that is not quiet still shows it. A state file that cannot be read or
+// written only costs the suppression.
+func (f *Factory) observeDeprecation(contextName, path, header, value string) {
+	f.deprecationMu.Lock()
+	state, err := deprecation.Load(contextName)
+	if err != nil {
+		log.L().Debug().Err(err).Msg("load deprecations failed")
+		state = &deprecation.State{}
+	}
+	warn := false
+	if f.Quiet {
+		state.Record(path, header, value, time.Now())
+	} else {
+		warn = state.Observe(path, header, value, time.Now())
+	}
+	if err := deprecation.Save(contextName, state); err != nil {
+		log.L().Debug().Err(err).Msg("save deprecations failed")
+	}
+	f.deprecationMu.Unlock()
+
+	if !warn {
+		return
+	}
+	advice := "check for a newer jk release"
+	if strings.HasPrefix(path, "/jk/") {
+		advice = "upgrade the companion plugin"
+	}
+	f.warnf("endpoint %s is deprecated by the server (context %s); %s", path, contextName, advice)
+}
@@ -0,0 +1,35 @@
+package cmdutil
+
+import "fmt"
+
+// DurationString renders milliseconds for human output. Under a minute it
+// keeps tenths of a second ("850ms", "12.3s"); from a minute on it drops
+// sub-second detail and shows whole units down to seconds ("4m 12s",
+// "5h 37m 12s"), or down to minutes from 24h ("1d 3h 46m"). Zero and
+// negative durations read "0s". The output is plain ASCII with a fixed
+// decimal point, so it reads the same in every locale.
+func DurationString(ms int64) string {
+	switch {
+	case ms <= 0:
+		return "0s"
+	case ms < 1000:
+		return fmt.Sprintf("%dms", ms)
+	case ms < 60*1000:
+		tenths := ms / 100
+		if tenths%10 == 0 {
+			return fmt.Sprintf("%ds", tenths/10)
+		}
+		return fmt.Sprintf("%d.%ds", tenths/10, tenths%10)
+	}
+
+	secs := ms / 1000
+	days, hours, minutes, seconds := secs/86400, secs/3600%24, secs/60%60, secs%60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm %ds", hours, minutes, seconds)
+	default:
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	}
+}
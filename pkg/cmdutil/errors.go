@@ -11,8 +11,34 @@ var (
 type ExitError struct {
 	Code int
 	Msg  string
+	// Request is the HTTP request that failed, when there was one.
+	Request *RequestInfo
+	// Details is extra structured context for --json error output, such
+	// as the child jobs of a folder given where a job was expected.
+	Details any
 }
 
 func (e *ExitError) Error() string {
 	return e.Msg
 }
+
+// RequestInfo identifies a failed request in error output. URL is redacted.
+type RequestInfo struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// RequestError attaches the failing request to an error that keeps the
+// default exit code.
+type RequestError struct {
+	Err     error
+	Request *RequestInfo
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
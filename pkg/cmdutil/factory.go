@@ -2,9 +2,12 @@ package cmdutil
 
 import (
 	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/avivsinai/jenkins-cli/internal/config"
+	"github.com/avivsinai/jenkins-cli/internal/filter"
 	"github.com/avivsinai/jenkins-cli/internal/jenkins"
 	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
 )
@@ -20,27 +23,82 @@ type Factory struct {
 	Config        func() (*config.Config, error)
 	JenkinsClient func(context.Context, string) (*jenkins.Client, error)
 
+	// ProgressMode is the --progress value; see Progress.
+	ProgressMode string
+	// StructuredOutput is set for --json and --yaml, which never prompt to
+	// re-authenticate.
+	StructuredOutput bool
+	// Quiet is the --quiet flag: warnings on stderr are suppressed.
+	Quiet bool
+
 	once struct {
-		cfg sync.Once
+		cfg      sync.Once
+		redactor sync.Once
 	}
 	cfg    *config.Config
 	cfgErr error
 	ioOnce sync.Once
 	ios    *iostreams.IOStreams
+
+	redactor    *filter.Redactor
+	redactorErr error
+
+	reauthMu        sync.Mutex
+	reauthAttempted bool
+
+	clockMu     sync.Mutex
+	clockSkew   time.Duration
+	clockWarned bool
+
+	deprecationMu sync.Mutex
 }
 
 // ResolveConfig eagerly loads the CLI configuration, caching the result.
 func (f *Factory) ResolveConfig() (*config.Config, error) {
 	f.once.cfg.Do(func() {
+		defer jenkins.StartSpan(jenkins.SpanConfigLoad)()
 		if f.Config == nil {
 			f.cfg, f.cfgErr = config.Load()
-			return
+		} else {
+			f.cfg, f.cfgErr = f.Config()
+		}
+		if f.cfgErr == nil && f.cfg != nil {
+			f.reportConfigWarnings(f.cfg.Warnings())
 		}
-		f.cfg, f.cfgErr = f.Config()
 	})
 	return f.cfg, f.cfgErr
 }
 
+// Redactor returns the secret redaction rules from the config's
+// preferences, built once per process.
+func (f *Factory) Redactor() (*filter.Redactor, error) {
+	f.once.redactor.Do(func() {
+		cfg, err := f.ResolveConfig()
+		if err != nil {
+			f.redactorErr = err
+			return
+		}
+		if cfg == nil {
+			return
+		}
+		f.redactor, f.redactorErr = filter.NewRedactor(cfg.Preferences.SecretPatterns, cfg.Preferences.NonSecretNames)
+		if f.redactorErr != nil {
+			f.redactorErr = fmt.Errorf("config preferences.secret_patterns: %w", f.redactorErr)
+		}
+	})
+	return f.redactor, f.redactorErr
+}
+
+// reportConfigWarnings prints the problems found while loading the config.
+func (f *Factory) reportConfigWarnings(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	for _, warning := range warnings {
+		f.warnf("%s", warning)
+	}
+}
+
 // Streams returns the IO streams, initialising them lazily.
 func (f *Factory) Streams() (*iostreams.IOStreams, error) {
 	f.ioOnce.Do(func() {
@@ -53,15 +111,25 @@ func (f *Factory) Streams() (*iostreams.IOStreams, error) {
 	return f.ios, nil
 }
 
-// Client returns a Jenkins client for the requested context.
+// Client returns a Jenkins client for the requested context. Interactive
+// sessions are offered a token prompt when Jenkins answers 401.
 func (f *Factory) Client(ctx context.Context, contextName string) (*jenkins.Client, error) {
 	cfg, err := f.ResolveConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	var client *jenkins.Client
 	if f.JenkinsClient != nil {
-		return f.JenkinsClient(ctx, contextName)
+		client, err = f.JenkinsClient(ctx, contextName)
+	} else {
+		client, err = jenkins.NewClient(ctx, cfg, contextName)
+	}
+	if err != nil {
+		return nil, err
 	}
-	return jenkins.NewClient(ctx, cfg, contextName)
+	client.SetReauth(f.reauthenticate)
+	client.SetDeprecationHook(f.observeDeprecation)
+	f.observeClock(client)
+	return client, nil
 }
@@ -0,0 +1,141 @@
+package cmdutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sync"
+
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+// ProgressSchemaVersion is stamped on every --progress json event.
+const ProgressSchemaVersion = "1.0"
+
+// Progress modes accepted by the root --progress flag.
+const (
+	ProgressAuto  = "auto"
+	ProgressJSON  = "json"
+	ProgressHuman = "human"
+	ProgressNone  = "none"
+)
+
+// Stable progress event names.
+const (
+	EventArtifactDownload = "artifact.download"
+	EventRunHeartbeat     = "run.heartbeat"
+	EventSearchJob        = "search.job"
+)
+
+// ProgressEvent is one progress update. Fields that do not apply to an event
+// are omitted from JSON, so consumers should treat a missing number as zero.
+type ProgressEvent struct {
+	SchemaVersion string `json:"schemaVersion"`
+	Event         string `json:"event"`
+	File          string `json:"file,omitempty"`
+	Bytes         int64  `json:"bytes,omitempty"`
+	Total         int64  `json:"total,omitempty"`
+	JobPath       string `json:"jobPath,omitempty"`
+	Build         int64  `json:"build,omitempty"`
+	ElapsedMs     int64  `json:"elapsedMs,omitempty"`
+	Percent       *int   `json:"percent,omitempty"`
+	Stage         string `json:"stage,omitempty"`
+	Index         int    `json:"index,omitempty"`
+}
+
+// ProgressReporter receives progress from long operations. Reporters write to
+// stderr only; stdout stays reserved for the command result. Done clears any
+// indicator and must be called before writing to stdout; reporting again
+// afterwards is allowed.
+type ProgressReporter interface {
+	Report(ProgressEvent)
+	Done()
+}
+
+// Progress returns the reporter selected by --progress. In auto mode a spinner
+// is shown when stderr is a terminal and nothing is reported otherwise.
+func (f *Factory) Progress() ProgressReporter {
+	ios, _ := f.Streams()
+	if ios == nil {
+		return noopProgress{}
+	}
+	switch f.ProgressMode {
+	case ProgressJSON:
+		return &jsonProgress{w: ios.ErrOut}
+	case ProgressHuman:
+		return &humanProgress{ios: ios}
+	case ProgressNone:
+		return noopProgress{}
+	default:
+		if ios.IsStderrTTY() {
+			return &humanProgress{ios: ios}
+		}
+		return noopProgress{}
+	}
+}
+
+// NoopProgress returns a reporter that discards every event.
+func NoopProgress() ProgressReporter {
+	return noopProgress{}
+}
+
+type noopProgress struct{}
+
+func (noopProgress) Report(ProgressEvent) {}
+func (noopProgress) Done()                {}
+
+// jsonProgress writes one NDJSON line per event.
+type jsonProgress struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (p *jsonProgress) Report(ev ProgressEvent) {
+	ev.SchemaVersion = ProgressSchemaVersion
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, _ = p.w.Write(append(data, '\n'))
+}
+
+func (p *jsonProgress) Done() {}
+
+// humanProgress drives the IOStreams spinner with a short label per event.
+type humanProgress struct {
+	ios *iostreams.IOStreams
+}
+
+func (p *humanProgress) Report(ev ProgressEvent) {
+	p.ios.StartProgressIndicatorWithLabel(progressLabel(ev))
+}
+
+func (p *humanProgress) Done() {
+	p.ios.StopProgressIndicator()
+}
+
+func progressLabel(ev ProgressEvent) string {
+	switch ev.Event {
+	case EventArtifactDownload:
+		if ev.Total > 0 {
+			return fmt.Sprintf("Downloading %s %d%%", path.Base(ev.File), ev.Bytes*100/ev.Total)
+		}
+		return fmt.Sprintf("Downloading %s", path.Base(ev.File))
+	case EventRunHeartbeat:
+		label := fmt.Sprintf("Run #%d running %s", ev.Build, DurationString(ev.ElapsedMs/1000*1000))
+		if ev.Percent != nil {
+			label += fmt.Sprintf(" (%d%%)", *ev.Percent)
+		}
+		if ev.Stage != "" {
+			label += " [" + ev.Stage + "]"
+		}
+		return label
+	case EventSearchJob:
+		return fmt.Sprintf("Searching jobs %d/%d", ev.Index, ev.Total)
+	default:
+		return ev.Event
+	}
+}
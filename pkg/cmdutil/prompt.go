@@ -0,0 +1,63 @@
+package cmdutil
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/avivsinai/jenkins-cli/internal/terminal"
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+// NoInputEnv disables every interactive prompt when set to a truthy value.
+const NoInputEnv = "JK_NO_INPUT"
+
+// Prompt describes a single interactive question. Name and Flag build the
+// error returned when prompting is disabled, e.g. "username required; pass
+// --username or remove --no-input".
+type Prompt struct {
+	Name    string
+	Label   string
+	Flag    string
+	Default string
+	Secret  bool
+}
+
+// NoInputFromEnv reports whether JK_NO_INPUT requests non-interactive mode.
+func NoInputFromEnv() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(NoInputEnv))) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// PromptOrFail asks p on the given streams, or fails immediately when
+// --no-input/JK_NO_INPUT is in effect. Every prompt in the CLI goes through
+// here so the flag cannot be bypassed.
+func PromptOrFail(ios *iostreams.IOStreams, p Prompt) (string, error) {
+	if err := noInputError(ios, p.Name, p.Flag); err != nil {
+		return "", err
+	}
+	if p.Secret {
+		return terminal.PromptSecret(ios.In, ios.ErrOut, p.Label)
+	}
+	return terminal.Prompt(ios.In, ios.ErrOut, p.Label, p.Default)
+}
+
+// ConfirmOrFail asks a yes/no question, failing when prompts are disabled.
+// flag names the option that skips the confirmation, typically --yes.
+func ConfirmOrFail(ios *iostreams.IOStreams, question, flag string) (bool, error) {
+	if err := noInputError(ios, "confirmation", flag); err != nil {
+		return false, err
+	}
+	return terminal.Confirm(ios.In, ios.ErrOut, question)
+}
+
+func noInputError(ios *iostreams.IOStreams, name, flag string) error {
+	if ios != nil && !ios.GetNeverPrompt() {
+		return nil
+	}
+	return &ExitError{Code: 2, Msg: fmt.Sprintf("%s required; pass %s or remove --no-input", name, flag)}
+}
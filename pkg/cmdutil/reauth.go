@@ -0,0 +1,86 @@
+package cmdutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/avivsinai/jenkins-cli/internal/secret"
+)
+
+var (
+	errReauthNotInteractive = errors.New("re-authentication needs an interactive terminal")
+	errReauthAttempted      = errors.New("re-authentication already attempted")
+)
+
+// reauthenticate is the jenkins.ReauthFunc installed on every client. It
+// prompts for a new token once per process, only when the session is
+// interactive and the output is not structured, and stores the token for
+// later runs.
+func (f *Factory) reauthenticate(ctx context.Context, contextName, username string) (string, string, error) {
+	ios, _ := f.Streams()
+	if f.StructuredOutput || ios == nil || !ios.CanPrompt() {
+		return "", "", errReauthNotInteractive
+	}
+
+	f.reauthMu.Lock()
+	defer f.reauthMu.Unlock()
+	if f.reauthAttempted {
+		return "", "", errReauthAttempted
+	}
+	f.reauthAttempted = true
+
+	_, _ = fmt.Fprintf(ios.ErrOut, "Jenkins rejected the saved credentials for context %s.\n", contextName)
+	newUsername, err := PromptOrFail(ios, Prompt{Name: "username", Label: "Username", Default: username})
+	if err != nil {
+		return "", "", err
+	}
+	token, err := PromptOrFail(ios, Prompt{Name: "token", Label: "API token", Secret: true})
+	if err != nil {
+		return "", "", err
+	}
+	newUsername = strings.TrimSpace(newUsername)
+	token = strings.TrimSpace(token)
+	if newUsername == "" || token == "" {
+		return "", "", errors.New("username and token are required")
+	}
+
+	if err := f.saveCredentials(contextName, newUsername, token); err != nil {
+		f.warnf("using the new token for this run only: %v", err)
+	}
+	return newUsername, token, nil
+}
+
+// saveCredentials persists a renewed token, and the username when it changed,
+// the same way `jk auth login` does.
+func (f *Factory) saveCredentials(contextName, username, token string) error {
+	cfg, err := f.ResolveConfig()
+	if err != nil {
+		return err
+	}
+	ctxDef, err := cfg.Context(contextName)
+	if err != nil {
+		return err
+	}
+
+	storeOpts := []secret.Option{}
+	if ctxDef.AllowInsecureStore {
+		storeOpts = append(storeOpts, secret.WithAllowFileFallback(true))
+	}
+	store, err := secret.Open(storeOpts...)
+	if err != nil {
+		return fmt.Errorf("open secret store: %w", err)
+	}
+	if err := store.Set(secret.TokenKey(contextName), token); err != nil {
+		return fmt.Errorf("store token: %w", err)
+	}
+
+	if ctxDef.Username != username {
+		ctxDef.Username = username
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package cmdutil
+
+import (
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+// Bounds of a list limit derived from the terminal height.
+const (
+	minTerminalListLimit = 5
+	maxTerminalListLimit = 100
+)
+
+// TerminalListLimit sizes a listing to fill the terminal: its height minus
+// reserved lines for headers, footers, and the next prompt, clamped to
+// minTerminalListLimit..maxTerminalListLimit. ok is false when stdout is not
+// a terminal or its height is unknown; callers then keep their fixed default
+// so piped output does not change.
+func TerminalListLimit(ios *iostreams.IOStreams, reserved int) (limit int, ok bool) {
+	if ios == nil || !ios.IsStdoutTTY() {
+		return 0, false
+	}
+	height := ios.TerminalHeight()
+	if height <= 0 {
+		return 0, false
+	}
+	limit = height - reserved
+	switch {
+	case limit < minTerminalListLimit:
+		limit = minTerminalListLimit
+	case limit > maxTerminalListLimit:
+		limit = maxTerminalListLimit
+	}
+	return limit, true
+}
@@ -0,0 +1,37 @@
+package cmdutil
+
+import (
+	"testing"
+
+	"github.com/avivsinai/jenkins-cli/pkg/iostreams"
+)
+
+func TestTerminalListLimit(t *testing.T) {
+	t.Setenv("LINES", "")
+	tests := []struct {
+		name   string
+		tty    bool
+		height int
+		limit  int
+		ok     bool
+	}{
+		{name: "piped", tty: false, height: 40},
+		{name: "unknown height", tty: true},
+		{name: "fits the pane", tty: true, height: 40, limit: 37, ok: true},
+		{name: "small pane", tty: true, height: 6, limit: minTerminalListLimit, ok: true},
+		{name: "tall pane", tty: true, height: 300, limit: maxTerminalListLimit, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ios, _, _, _ := iostreams.Test()
+			ios.SetStdoutTTY(tt.tty)
+			ios.SetTerminalHeight(tt.height)
+
+			limit, ok := TerminalListLimit(ios, 3)
+			if limit != tt.limit || ok != tt.ok {
+				t.Fatalf("expected (%d, %v), got (%d, %v)", tt.limit, tt.ok, limit, ok)
+			}
+		})
+	}
+}
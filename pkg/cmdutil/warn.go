@@ -0,0 +1,56 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// Quiet reports whether --quiet is set for cmd.
+func Quiet(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	return quiet
+}
+
+// Warnf prints a "warning: " line on cmd's stderr unless --quiet is set.
+// Every warning a command prints goes through it or Fwarnf, so --quiet
+// silences them all.
+func Warnf(cmd *cobra.Command, format string, args ...any) {
+	Fwarnf(WarningWriter(cmd), format, args...)
+}
+
+// WarningWriter is where cmd's warnings go: its stderr, or nil when --quiet
+// is set. Helpers that take a writer instead of the command are handed it and
+// print through Fwarnf.
+func WarningWriter(cmd *cobra.Command) io.Writer {
+	if Quiet(cmd) {
+		return nil
+	}
+	return cmd.ErrOrStderr()
+}
+
+// Fwarnf prints a "warning: " line on w; a nil w drops it.
+func Fwarnf(w io.Writer, format string, args ...any) {
+	if w == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "warning: "+format+"\n", args...)
+}
+
+// warnf is Warnf for warnings the factory raises outside a command, such as
+// while loading the config or creating a client; it honors the --quiet the
+// root command recorded in Quiet.
+func (f *Factory) warnf(format string, args ...any) {
+	if f.Quiet {
+		return
+	}
+	ios, err := f.Streams()
+	if err != nil || ios == nil {
+		return
+	}
+	Fwarnf(ios.ErrOut, format, args...)
+}
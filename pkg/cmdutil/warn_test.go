@@ -0,0 +1,27 @@
+package cmdutil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestWarnfHonorsQuiet(t *testing.T) {
+	for _, quiet := range []bool{false, true} {
+		cmd := &cobra.Command{Use: "jk"}
+		cmd.Flags().Bool("quiet", quiet, "")
+		var stderr bytes.Buffer
+		cmd.SetErr(&stderr)
+
+		Warnf(cmd, "context %q is stale", "prod")
+
+		want := "warning: context \"prod\" is stale\n"
+		if quiet {
+			want = ""
+		}
+		if stderr.String() != want {
+			t.Fatalf("quiet=%v: stderr = %q, want %q", quiet, stderr.String(), want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package iostreams
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FormatHyperlink frames text in an OSC 8 escape sequence pointing at url.
+func FormatHyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// HyperlinksEnabled reports whether stdout is a terminal that renders OSC 8
+// hyperlinks. It is always false when stdout is piped or NO_COLOR is set.
+func (s *IOStreams) HyperlinksEnabled() bool {
+	if s.hyperlinkOverride {
+		return s.hyperlinksEnabled
+	}
+	if !s.IsStdoutTTY() {
+		return false
+	}
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return supportsHyperlinks(os.Getenv)
+}
+
+// SetHyperlinksEnabled overrides terminal detection.
+func (s *IOStreams) SetHyperlinksEnabled(enabled bool) {
+	s.hyperlinkOverride = true
+	s.hyperlinksEnabled = enabled
+}
+
+// Hyperlink returns text wrapped as a clickable link to url when supported and
+// text unchanged otherwise.
+func (s *IOStreams) Hyperlink(url, text string) string {
+	if url == "" || !s.HyperlinksEnabled() {
+		return text
+	}
+	return FormatHyperlink(url, text)
+}
+
+// supportsHyperlinks applies the usual environment heuristics; terminals that
+// do not understand OSC 8 print the escape bytes, so unknown terminals are off.
+func supportsHyperlinks(getenv func(string) string) bool {
+	if force := getenv("FORCE_HYPERLINK"); force != "" {
+		enabled, err := strconv.ParseBool(force)
+		return err == nil && enabled
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	if term == "dumb" {
+		return false
+	}
+
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if getenv("WT_SESSION") != "" || getenv("KONSOLE_VERSION") != "" || getenv("DOMTERM") != "" {
+		return true
+	}
+	if vte, err := strconv.Atoi(getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+	for _, name := range []string{"kitty", "alacritty", "foot", "ghostty", "wezterm"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+	return false
+}
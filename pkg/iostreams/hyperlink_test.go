@@ -0,0 +1,63 @@
+package iostreams
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFormatHyperlink(t *testing.T) {
+	got := FormatHyperlink("https://jenkins.example/job/app/42/", "#42")
+	want := "\x1b]8;;https://jenkins.example/job/app/42/\x1b\\#42\x1b]8;;\x1b\\"
+	if got != want {
+		t.Fatalf("FormatHyperlink() = %q, want %q", got, want)
+	}
+}
+
+func TestHyperlinkPlainWhenPiped(t *testing.T) {
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	t.Setenv("FORCE_HYPERLINK", "")
+	t.Setenv("NO_COLOR", "")
+	_ = os.Unsetenv("NO_COLOR")
+	ios, _, _, _ := Test()
+
+	if got := ios.Hyperlink("https://jenkins.example/", "link"); got != "link" {
+		t.Fatalf("expected plain text for piped stdout, got %q", got)
+	}
+
+	ios.SetStdoutTTY(true)
+	if got := ios.Hyperlink("https://jenkins.example/", "link"); got != FormatHyperlink("https://jenkins.example/", "link") {
+		t.Fatalf("expected hyperlink on a supporting TTY, got %q", got)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if got := ios.Hyperlink("https://jenkins.example/", "link"); got != "link" {
+		t.Fatalf("expected NO_COLOR to disable hyperlinks, got %q", got)
+	}
+}
+
+func TestSupportsHyperlinks(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"unknown terminal", map[string]string{"TERM": "xterm-256color"}, false},
+		{"dumb terminal", map[string]string{"TERM": "dumb", "TERM_PROGRAM": "vscode"}, false},
+		{"iterm", map[string]string{"TERM_PROGRAM": "iTerm.app"}, true},
+		{"windows terminal", map[string]string{"WT_SESSION": "abc"}, true},
+		{"old vte", map[string]string{"VTE_VERSION": "4800"}, false},
+		{"new vte", map[string]string{"VTE_VERSION": "6003"}, true},
+		{"kitty", map[string]string{"TERM": "xterm-kitty"}, true},
+		{"forced off", map[string]string{"FORCE_HYPERLINK": "0", "TERM_PROGRAM": "iTerm.app"}, false},
+		{"forced on", map[string]string{"FORCE_HYPERLINK": "1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string { return tt.env[key] }
+			if got := supportsHyperlinks(getenv); got != tt.want {
+				t.Fatalf("supportsHyperlinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
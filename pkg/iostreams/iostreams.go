@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -53,7 +54,9 @@ type IOStreams struct {
 	Out    fileWriter
 	ErrOut fileWriter
 
-	terminalTheme string
+	terminalTheme  string
+	terminalWidth  int
+	terminalHeight int
 
 	progressIndicatorEnabled bool
 	progressIndicator        *spinner.Spinner
@@ -76,6 +79,9 @@ type IOStreams struct {
 	colorLabels             bool
 	accessibleColorsEnabled bool
 
+	hyperlinkOverride bool
+	hyperlinksEnabled bool
+
 	pagerCommand string
 	pagerProcess *os.Process
 
@@ -408,8 +414,16 @@ func (s *IOStreams) RefreshScreen() {
 	}
 }
 
-// TerminalWidth returns the width of the terminal that controls the process
+// TerminalWidth returns the width of the terminal that controls the process.
+// An explicit SetTerminalWidth wins, then a positive COLUMNS, then the size
+// reported by the terminal itself.
 func (s *IOStreams) TerminalWidth() int {
+	if s.terminalWidth > 0 {
+		return s.terminalWidth
+	}
+	if columns, err := strconv.Atoi(strings.TrimSpace(os.Getenv("COLUMNS"))); err == nil && columns > 0 {
+		return columns
+	}
 	w, _, err := s.term.Size()
 	if err == nil && w > 0 {
 		return w
@@ -417,6 +431,34 @@ func (s *IOStreams) TerminalWidth() int {
 	return DefaultWidth
 }
 
+// SetTerminalWidth overrides the detected terminal width; 0 restores detection.
+func (s *IOStreams) SetTerminalWidth(width int) {
+	s.terminalWidth = width
+}
+
+// TerminalHeight returns the number of rows of the terminal that controls the
+// process, resolved like TerminalWidth with LINES in place of COLUMNS. It
+// returns 0 when the height is unknown.
+func (s *IOStreams) TerminalHeight() int {
+	if s.terminalHeight > 0 {
+		return s.terminalHeight
+	}
+	if lines, err := strconv.Atoi(strings.TrimSpace(os.Getenv("LINES"))); err == nil && lines > 0 {
+		return lines
+	}
+	_, h, err := s.term.Size()
+	if err == nil && h > 0 {
+		return h
+	}
+	return 0
+}
+
+// SetTerminalHeight overrides the detected terminal height; 0 restores
+// detection.
+func (s *IOStreams) SetTerminalHeight(height int) {
+	s.terminalHeight = height
+}
+
 func (s *IOStreams) ColorScheme() *ColorScheme {
 	return &ColorScheme{
 		Enabled:       s.ColorEnabled(),
@@ -60,3 +60,41 @@ func TestHelperProcess(t *testing.T) {
 	}
 	os.Exit(0)
 }
+
+func TestTerminalWidth(t *testing.T) {
+	ios, _, _, _ := Test()
+
+	t.Setenv("COLUMNS", "")
+	if got := ios.TerminalWidth(); got != 80 {
+		t.Fatalf("expected terminal size 80, got %d", got)
+	}
+
+	t.Setenv("COLUMNS", "132")
+	if got := ios.TerminalWidth(); got != 132 {
+		t.Fatalf("expected COLUMNS to win, got %d", got)
+	}
+
+	ios.SetTerminalWidth(60)
+	if got := ios.TerminalWidth(); got != 60 {
+		t.Fatalf("expected override to win, got %d", got)
+	}
+}
+
+func TestTerminalHeight(t *testing.T) {
+	ios, _, _, _ := Test()
+
+	t.Setenv("LINES", "")
+	if got := ios.TerminalHeight(); got != 0 {
+		t.Fatalf("expected unknown height, got %d", got)
+	}
+
+	t.Setenv("LINES", "40")
+	if got := ios.TerminalHeight(); got != 40 {
+		t.Fatalf("expected LINES to win, got %d", got)
+	}
+
+	ios.SetTerminalHeight(24)
+	if got := ios.TerminalHeight(); got != 24 {
+		t.Fatalf("expected override to win, got %d", got)
+	}
+}
@@ -0,0 +1,213 @@
+// Package jobpath is the single definition of Jenkins job paths.
+//
+// A job path is the human form of a job's full name: folder and job names
+// joined by "/", as in "team/app/main". Names never contain a bare "/";
+// multibranch branch jobs whose branch has one (feature/login) keep it
+// escaped as "%2F" (feature%2Flogin), which is also how Jenkins names them.
+//
+// Jenkins URLs spell the same path as "job/team/job/app/job/main", with each
+// name path-escaped, so a branch job's "%2F" becomes "%252F" on the wire.
+// Encode and Decode convert between the two forms; Normalize and Parse accept
+// either, including full URLs pasted from the browser.
+package jobpath
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const jobSegment = "job"
+
+// Normalize returns the canonical form of a job path. Surrounding whitespace
+// and slashes, empty segments, and whitespace around each name are dropped.
+// A path in URL form (a full Jenkins URL, or segments alternating "job/<name>"
+// as copied from a URL) is decoded; anything after the job in a URL, such as
+// a build number, is ignored. Escaped slashes are written "%2F", including
+// branch names pasted still double-escaped ("%252F").
+func Normalize(path string) string {
+	names, _ := parse(path)
+	return strings.Join(names, "/")
+}
+
+// Parse is Normalize for user input that must name a job: it fails on an
+// empty path, on "." and ".." segments, and on URLs that point below a job
+// (a build, a console log) rather than at it.
+func Parse(path string) (string, error) {
+	names, rest := parse(path)
+	if len(names) == 0 {
+		return "", errors.New("job path is empty")
+	}
+	for _, name := range names {
+		if name == "." || name == ".." {
+			return "", fmt.Errorf("invalid job path %q: %q is not a job name", strings.TrimSpace(path), name)
+		}
+	}
+	if len(rest) > 0 {
+		return "", fmt.Errorf("invalid job path %q: URL continues past the job at %q", strings.TrimSpace(path), strings.Join(rest, "/"))
+	}
+	return strings.Join(names, "/"), nil
+}
+
+// Segments returns the names in a job path, outermost first. The empty path
+// has no segments.
+func Segments(path string) []string {
+	names, _ := parse(path)
+	return names
+}
+
+// Join joins job paths, or a folder and a child name, into one normalized
+// path. Empty elements are skipped.
+func Join(elem ...string) string {
+	var names []string
+	for _, e := range elem {
+		names = append(names, Segments(e)...)
+	}
+	return strings.Join(names, "/")
+}
+
+// Split splits a job path into its parent folder and final name. A top-level
+// job has an empty parent.
+func Split(path string) (parent, name string) {
+	names := Segments(path)
+	if len(names) == 0 {
+		return "", ""
+	}
+	return strings.Join(names[:len(names)-1], "/"), names[len(names)-1]
+}
+
+// IsBranchOf reports whether path is a direct child of parent, as the branch
+// jobs of a multibranch project are.
+func IsBranchOf(path, parent string) bool {
+	parentNames := Segments(parent)
+	names := Segments(path)
+	if len(parentNames) == 0 || len(names) != len(parentNames)+1 {
+		return false
+	}
+	for i, name := range parentNames {
+		if names[i] != name {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode converts a job path like "team/app/main" into the Jenkins URL form
+// "job/team/job/app/job/main", without a leading slash. The path is
+// normalized first.
+func Encode(path string) string {
+	var builder strings.Builder
+	for _, name := range Segments(path) {
+		if builder.Len() > 0 {
+			builder.WriteRune('/')
+		}
+		builder.WriteString(jobSegment)
+		builder.WriteRune('/')
+		builder.WriteString(url.PathEscape(name))
+	}
+	return builder.String()
+}
+
+// Decode reverses Encode for the escaped path of a Jenkins URL. Segments
+// before the first "job" (a context path, "view/All", "me/my-views") and
+// "view/<name>" pairs between jobs are skipped. It returns the job path and
+// the segments that follow it, such as a build number and "console". Names
+// keep an escaped "/" as "%2F" even when the URL was only escaped once.
+func Decode(escapedPath string) (string, []string) {
+	names, rest := decode(escapedPath)
+	return strings.Join(names, "/"), rest
+}
+
+// parse splits path into names, decoding URL forms, and returns any URL
+// segments that follow the job.
+func parse(path string) ([]string, []string) {
+	path = strings.TrimSpace(path)
+	if u, err := url.Parse(path); err == nil && u.IsAbs() && u.Host != "" {
+		return decode(u.EscapedPath())
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if isURLForm(segments) {
+		return decode(strings.Join(segments, "/"))
+	}
+
+	names := make([]string, len(segments))
+	for i, segment := range segments {
+		names[i] = canonicalName(segment)
+	}
+	return names, nil
+}
+
+// isURLForm reports whether segments alternate "job/<name>", the shape of a
+// path copied from a Jenkins URL.
+func isURLForm(segments []string) bool {
+	if len(segments) == 0 || len(segments)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(segments); i += 2 {
+		if segments[i] != jobSegment {
+			return false
+		}
+	}
+	return true
+}
+
+func decode(escapedPath string) ([]string, []string) {
+	var segments []string
+	for _, segment := range strings.Split(escapedPath, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	i := 0
+	for i < len(segments) && segments[i] != jobSegment {
+		i++
+	}
+
+	var names []string
+	for i < len(segments) {
+		switch {
+		case segments[i] == jobSegment && i+1 < len(segments):
+			name, err := url.PathUnescape(segments[i+1])
+			if err != nil {
+				name = segments[i+1]
+			}
+			names = append(names, canonicalName(strings.ReplaceAll(strings.TrimSpace(name), "/", "%2F")))
+			i += 2
+		case segments[i] == "view" && i+1 < len(segments) && len(names) > 0:
+			i += 2
+		default:
+			return names, segments[i:]
+		}
+	}
+	return names, nil
+}
+
+// canonicalName spells escaped slashes in a name as "%2F", undoing a second
+// round of escaping and lower-case hex.
+func canonicalName(name string) string {
+	if !strings.Contains(name, "%") {
+		return name
+	}
+	var b strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch rest := name[i:]; {
+		case len(rest) >= 5 && strings.EqualFold(rest[:5], "%252F"):
+			b.WriteString("%2F")
+			i += 4
+		case len(rest) >= 3 && strings.EqualFold(rest[:3], "%2F"):
+			b.WriteString("%2F")
+			i += 2
+		default:
+			b.WriteByte(name[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,201 @@
+package jobpath
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{"empty", "", ""},
+		{"only slashes", " / ", ""},
+		{"single", "app", "app"},
+		{"nested", "team/app/main", "team/app/main"},
+		{"leading and trailing slashes", "/team/app/", "team/app"},
+		{"repeated slashes", "team//app", "team/app"},
+		{"surrounding whitespace", "  team/app\n", "team/app"},
+		{"whitespace around names", "team / app", "team/app"},
+		{"inner spaces kept", "folder name/my job", "folder name/my job"},
+		{"escaped branch", "repo/feature%2Flogin", "repo/feature%2Flogin"},
+		{"lower-case escape", "repo/feature%2flogin", "repo/feature%2Flogin"},
+		{"double escaped branch", "repo/feature%252Flogin", "repo/feature%2Flogin"},
+		{"other escapes kept", "app/100%25", "app/100%25"},
+		{"url path", "job/team/job/app", "team/app"},
+		{"url path with slashes", "/job/team/job/app/", "team/app"},
+		{"url path escaped", "/job/folder%20name/job/feature%252Flogin/", "folder name/feature%2Flogin"},
+		{"job named job", "job", "job"},
+		{"odd segments are names", "job/team/job", "job/team/job"},
+		{"full url", "https://ci.example.com/job/team/job/app/", "team/app"},
+		{"full url with context and view", "https://ci.example.com/jenkins/view/All/job/team/job/app/", "team/app"},
+		{"full url build ignored", "https://ci.example.com/job/team/job/app/42/console", "team/app"},
+		{"full url branch", "https://ci.example.com/job/repo/job/feature%252Flogin/", "repo/feature%2Flogin"},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.input); got != tt.expect {
+			t.Fatalf("%s: expected %q got %q", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		expect  string
+		wantErr string
+	}{
+		{"plain", " team/app/ ", "team/app", ""},
+		{"full url", "https://ci.example.com/job/team/job/app/", "team/app", ""},
+		{"empty", "  ", "", "job path is empty"},
+		{"url without job", "https://ci.example.com/queue/", "", "job path is empty"},
+		{"dot segment", "team/../app", "", `".." is not a job name`},
+		{"build url", "https://ci.example.com/job/app/42/", "", `continues past the job at "42"`},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("%s: expected error containing %q got %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.expect {
+			t.Fatalf("%s: expected %q got %q", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	if got := Segments(""); len(got) != 0 {
+		t.Fatalf("expected no segments, got %v", got)
+	}
+	got := Segments("/team//app/feature%252Fx/")
+	if strings.Join(got, "|") != "team|app|feature%2Fx" {
+		t.Fatalf("unexpected segments %v", got)
+	}
+}
+
+func TestJoinAndSplit(t *testing.T) {
+	joins := []struct {
+		elem   []string
+		expect string
+	}{
+		{nil, ""},
+		{[]string{"", "app"}, "app"},
+		{[]string{"team", "app"}, "team/app"},
+		{[]string{"/team/", "/app/"}, "team/app"},
+		{[]string{"team/app", "", "feature%2Fx"}, "team/app/feature%2Fx"},
+	}
+	for _, tt := range joins {
+		if got := Join(tt.elem...); got != tt.expect {
+			t.Fatalf("Join(%q): expected %q got %q", tt.elem, tt.expect, got)
+		}
+	}
+
+	splits := []struct {
+		input, parent, name string
+	}{
+		{"", "", ""},
+		{"app", "", "app"},
+		{"/team/app/", "team", "app"},
+		{"team/repo/feature%2Fx", "team/repo", "feature%2Fx"},
+	}
+	for _, tt := range splits {
+		parent, name := Split(tt.input)
+		if parent != tt.parent || name != tt.name {
+			t.Fatalf("Split(%q): expected (%q, %q) got (%q, %q)", tt.input, tt.parent, tt.name, parent, name)
+		}
+	}
+}
+
+func TestIsBranchOf(t *testing.T) {
+	tests := []struct {
+		path, parent string
+		expect       bool
+	}{
+		{"team/repo/main", "team/repo", true},
+		{"/team/repo/feature%2Fx/", "team/repo", true},
+		{"team/repo/feature%252Fx", "/team/repo/", true},
+		{"team/repo", "team/repo", false},
+		{"team/repo/a/b", "team/repo", false},
+		{"team/repository/main", "team/repo", false},
+		{"main", "", false},
+	}
+	for _, tt := range tests {
+		if got := IsBranchOf(tt.path, tt.parent); got != tt.expect {
+			t.Fatalf("IsBranchOf(%q, %q): expected %v", tt.path, tt.parent, tt.expect)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{"empty", "", ""},
+		{"single", "example", "job/example"},
+		{"nested", "team/app/build", "job/team/job/app/job/build"},
+		{"spaces", "folder name/job", "job/folder%20name/job/job"},
+		{"slashes trimmed", "/team/app/", "job/team/job/app"},
+		{"branch escaped twice", "repo/feature%2Flogin", "job/repo/job/feature%252Flogin"},
+		{"pasted double escape", "repo/feature%252Flogin", "job/repo/job/feature%252Flogin"},
+		{"url path round-trips", "/job/team/job/app/", "job/team/job/app"},
+	}
+
+	for _, tt := range tests {
+		if got := Encode(tt.input); got != tt.expect {
+			t.Fatalf("%s: expected %s got %s", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expect   string
+		trailing []string
+	}{
+		{"empty", "", "", nil},
+		{"no job", "/queue/item/5/", "", nil},
+		{"single", "/job/app/", "app", nil},
+		{"build", "/job/team/job/app/42/", "team/app", []string{"42"}},
+		{"console", "/job/team/job/app/42/console", "team/app", []string{"42", "console"}},
+		{"context path", "/jenkins/job/app/7", "app", []string{"7"}},
+		{"view prefix", "/view/All/job/app/7/", "app", []string{"7"}},
+		{"nested views", "/view/Team/view/Nightly/job/team/view/Jobs/job/app/3/", "team/app", []string{"3"}},
+		{"escaped name", "/job/folder%20name/job/app/1", "folder name/app", []string{"1"}},
+		{"multibranch double escaped", "/job/repo/job/feature%252Flogin/5/", "repo/feature%2Flogin", []string{"5"}},
+		{"multibranch single escaped", "/job/repo/job/feature%2Flogin/5/", "repo/feature%2Flogin", []string{"5"}},
+	}
+
+	for _, tt := range tests {
+		got, trailing := Decode(tt.path)
+		if got != tt.expect {
+			t.Fatalf("%s: expected path %q got %q", tt.name, tt.expect, got)
+		}
+		if strings.Join(trailing, "/") != strings.Join(tt.trailing, "/") {
+			t.Fatalf("%s: expected trailing %v got %v", tt.name, tt.trailing, trailing)
+		}
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, path := range []string{"app", "team/app/main", "folder name/repo/feature%2Flogin", "a/100%25/b+c"} {
+		got, trailing := Decode("/" + Encode(path) + "/")
+		if got != path || len(trailing) != 0 {
+			t.Fatalf("round trip of %q gave %q %v", path, got, trailing)
+		}
+	}
+}